@@ -0,0 +1,480 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/audit"
+	"github.com/Kdaito/gelete/internal/fsutil"
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// runBatch deletes the branches given as arguments without starting the TUI.
+// It prints one result line per branch (or a JSON document of results with --json) and returns an error
+// if any deletion failed. Unattended runs also list the local branches before and after the deletions
+// (see branchReportEnabled).
+func runBatch(cmd *cobra.Command, branches []string) error {
+	out := cmd.OutOrStdout()
+
+	if err := checkNotCurrentBranch(branches); err != nil {
+		return err
+	}
+
+	if err := askBatchConfirmation(cmd, branches); err != nil {
+		return err
+	}
+
+	before, err := printBranchSnapshot(out, branches, false)
+	if err != nil {
+		return err
+	}
+
+	if err := bundleBeforeBatch(out, cmd.ErrOrStderr(), branches); err != nil {
+		return err
+	}
+	results, failed := deleteBatchBranches(out, cmd.ErrOrStderr(), branches)
+	if err := compactAfterBatch(out, cmd.ErrOrStderr(), len(branches)-failed); err != nil {
+		return err
+	}
+
+	// The branches are deleted by now, so failing to list the survivors only loses the section
+	after, err := printBranchSnapshot(out, nil, true)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+	}
+
+	// JSON is emitted even if deletions failed, so scripts can inspect each result
+	if opts.json {
+		report := output.BatchReport{Before: before, Results: results, After: after}
+		if err := output.WriteJSON(out, report); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return deletionFailed("failed to delete %d of %d branch(es)", failed, len(branches))
+	}
+	return nil
+}
+
+// deleteBatchBranches deletes each branch in turn, printing a result line per branch unless --json is set.
+// Each deletion is recorded in the audit log; failing to write it is a warning on errOut, not an error.
+// Returns the results and the number of failed deletions.
+func deleteBatchBranches(out, errOut io.Writer, branches []string) ([]output.Result, int) {
+	results := make([]output.Result, 0, len(branches))
+	failed := 0
+	for _, branch := range branches {
+		result := deleteBatchBranch(branch, opts.force)
+		reportBatchResult(out, errOut, result)
+		if result.Status == output.StatusFailed {
+			failed++
+		}
+		results = append(results, result)
+	}
+	return results, failed
+}
+
+// reportBatchResult records a deletion in the audit log and prints its result line unless --json is set
+func reportBatchResult(out, errOut io.Writer, result output.Result) {
+	if err := recordBatchAudit(result); err != nil && !auditWarned {
+		auditWarned = true
+		fmt.Fprintf(errOut, "Warning: %v\n", err)
+	}
+	if !opts.json {
+		fmt.Fprintln(out, batchResultLine(result))
+	}
+}
+
+// askBatchConfirmation asks to confirm deleting the branches unless --yes or --dry-run is set.
+// Returns a cancellation error if the user declines.
+func askBatchConfirmation(cmd *cobra.Command, branches []string) error {
+	if opts.yes || opts.dryRun {
+		return nil
+	}
+
+	confirmed, err := confirmBatch(cmd.InOrStdin(), cmd.OutOrStdout(), branches)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Fprintln(cmd.OutOrStdout(), "Aborted.")
+		return cancelled("deletion of %d branch(es) aborted", len(branches))
+	}
+	return nil
+}
+
+// bundleBeforeBatch writes the branches to the --backup-bundle before they are force deleted.
+// Nothing is deleted if the bundle can't be written. The report goes to errOut with --json.
+func bundleBeforeBatch(out, errOut io.Writer, branches []string) error {
+	if opts.backupBundle == "" || !opts.force || opts.dryRun {
+		return nil
+	}
+	if opts.json {
+		out = errOut
+	}
+
+	if err := git.CreateBundle(opts.backupBundle, branches); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Backed up %d branch(es) to %s (restore: git fetch %s %s:%s)\n",
+		len(branches), opts.backupBundle, opts.backupBundle, branches[0], branches[0])
+	return nil
+}
+
+// compactAfterBatch expires the reflog and runs git gc after deleting branches with --expire-reflog, so their
+// commits are removed from disk. The report goes to errOut with --json, to keep stdout valid JSON.
+func compactAfterBatch(out, errOut io.Writer, deleted int) error {
+	if !opts.expireReflog || opts.dryRun || deleted == 0 {
+		return nil
+	}
+	if opts.json {
+		out = errOut
+	}
+
+	fmt.Fprintln(errOut, "Warning: expiring the reflog and running git gc; the deleted commits become unrecoverable")
+	report, err := git.CompactRepository()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, compactionSummary(report))
+	return nil
+}
+
+// compactionSummary describes how long git gc took and, if known, roughly how much space it reclaimed
+func compactionSummary(report git.CompactionReport) string {
+	summary := fmt.Sprintf("Expired the reflog and ran git gc in %s", report.Duration.Round(100*time.Millisecond))
+	if report.SizeKnown {
+		summary += fmt.Sprintf(", reclaimed ~%s", fsutil.FormatSize(report.Reclaimed()))
+	}
+	return summary
+}
+
+// auditWarned is set once a failure to write the audit log was reported, so it is only reported once per run
+var auditWarned bool
+
+// recordBatchAudit appends the outcome of a deletion to the audit log; nothing is recorded in dry-run mode
+func recordBatchAudit(result output.Result) error {
+	if opts.dryRun {
+		return nil
+	}
+
+	entry := audit.Entry{Branch: result.Branch, SHA: result.SHA, Forced: result.Forced}
+	if result.Status == output.StatusFailed {
+		entry.Error = result.Error
+	}
+	return audit.Append(entry)
+}
+
+// runBranchArgs deletes the branches given as arguments. Repeated branches are deleted once, with a note
+// on stderr, and nothing is deleted if any of them is checked out, since git would refuse it anyway.
+func runBranchArgs(cmd *cobra.Command, args []string) error {
+	branches := dedupeBranchArgs(cmd.ErrOrStderr(), args)
+	if err := checkCheckedOutArgs(branches); err != nil {
+		return err
+	}
+	return runBatch(cmd, branches)
+}
+
+// dedupeBranchArgs returns the branches in the order given, without repetitions, noting each repeated one on errOut
+func dedupeBranchArgs(errOut io.Writer, args []string) []string {
+	seen := make(map[string]bool, len(args))
+	branches := make([]string, 0, len(args))
+	for _, branch := range args {
+		if seen[branch] {
+			fmt.Fprintf(errOut, "Note: '%s' was given more than once; deleting it once\n", branch)
+			continue
+		}
+		seen[branch] = true
+		branches = append(branches, branch)
+	}
+	return branches
+}
+
+// checkCheckedOutArgs refuses the branches given as arguments if any of them is checked out: the current branch,
+// or the branch of another worktree. Every such branch is reported at once, each with the command freeing it.
+func checkCheckedOutArgs(branches []string) error {
+	currentBranch, detached, err := git.GetHeadBranch()
+	if err != nil {
+		return err
+	}
+	if detached {
+		currentBranch = ""
+	}
+	checkedOut, err := checkedOutWorktrees()
+	if err != nil {
+		return err
+	}
+
+	defaultBranch := detectDefaultBranch()
+	var problems []string
+	for _, branch := range branches {
+		if problem := checkedOutProblem(branch, currentBranch, checkedOut, defaultBranch); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	return checkedOutError(problems)
+}
+
+// checkedOutWorktrees returns the worktree each branch is checked out in, leaving out prunable worktrees
+func checkedOutWorktrees() (map[string]git.Worktree, error) {
+	worktrees, err := repo.ListWorktrees(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	checkedOut := make(map[string]git.Worktree)
+	for _, wt := range worktrees {
+		if wt.Branch != "" && !wt.Prunable {
+			checkedOut[wt.Branch] = wt
+		}
+	}
+	return checkedOut, nil
+}
+
+// checkedOutProblem returns why the branch can't be deleted, with the command freeing it, or "" if it isn't checked out.
+// currentBranch is empty when HEAD is detached.
+func checkedOutProblem(branch, currentBranch string, checkedOut map[string]git.Worktree, defaultBranch string) string {
+	wt, ok := checkedOut[branch]
+	switch {
+	case branch == currentBranch:
+		return fmt.Sprintf("cannot delete the current branch '%s'; switch to another branch first: %s",
+			branch, switchCommand("", branch, defaultBranch))
+	case !ok:
+		return ""
+	case wt.Main:
+		return fmt.Sprintf("cannot delete '%s': it is checked out in the main worktree at '%s'; switch it to another branch there first: %s",
+			branch, wt.Path, switchCommand(wt.Path, branch, defaultBranch))
+	}
+	return fmt.Sprintf("cannot delete '%s': it is checked out in worktree '%s'; remove the worktree first (e.g. with gelete worktrees) or switch its branch: %s",
+		branch, wt.Path, switchCommand(wt.Path, branch, defaultBranch))
+}
+
+// checkedOutError returns the error refusing the checked-out branches, or nil if there are none
+func checkedOutError(problems []string) error {
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("%s (nothing was deleted)", problems[0])
+	}
+	return fmt.Errorf("%d branches are checked out, nothing was deleted:\n  %s", len(problems), strings.Join(problems, "\n  "))
+}
+
+// switchCommand returns the git switch command moving the worktree at dir (the current one if empty) off the branch,
+// onto the default branch if it is known and isn't the branch itself
+func switchCommand(dir, branch, defaultBranch string) string {
+	target := defaultBranch
+	if target == "" || target == branch {
+		target = "<another-branch>"
+	}
+	if dir == "" {
+		return "git switch " + target
+	}
+	return fmt.Sprintf("git -C '%s' switch %s", dir, target)
+}
+
+// checkNotCurrentBranch refuses the batch if it includes the currently checked-out branch, or the branch
+// checked out in the main worktree when running in a linked worktree, since the main worktree can't be removed
+func checkNotCurrentBranch(branches []string) error {
+	currentBranch, detached, err := git.GetHeadBranch()
+	if err != nil {
+		return err
+	}
+
+	main, err := mainWorktree()
+	if err != nil {
+		return err
+	}
+
+	for _, branch := range branches {
+		if branch == currentBranch && !detached {
+			return fmt.Errorf("cannot delete the current branch '%s'; switch to another branch first", branch)
+		}
+		if branch == main.Branch {
+			return fmt.Errorf("cannot delete '%s': it is checked out in the main worktree at '%s'", branch, main.Path)
+		}
+	}
+	return nil
+}
+
+// mainWorktree returns the main worktree of the repository, or a zero Worktree if there is none (bare repositories)
+func mainWorktree() (git.Worktree, error) {
+	worktrees, err := repo.ListWorktrees(context.Background())
+	if err != nil {
+		return git.Worktree{}, err
+	}
+	for _, wt := range worktrees {
+		if wt.Main {
+			return wt, nil
+		}
+	}
+	return git.Worktree{}, nil
+}
+
+// confirmBatch asks the user to confirm deleting the branches on stdin
+func confirmBatch(in io.Reader, out io.Writer, branches []string) (bool, error) {
+	action := "Delete"
+	if opts.force {
+		action = "Force delete"
+	}
+
+	defaultBranch := detectDefaultBranch()
+	for _, branch := range branches {
+		fmt.Fprintf(out, "  • %s\n", branch)
+		if branch == defaultBranch {
+			fmt.Fprintf(out, "    ⚠ '%s' is the DEFAULT branch of this repository: clones, CI and pull requests rely on it!\n", branch)
+		}
+	}
+	if opts.expireReflog {
+		fmt.Fprintln(out, "  ⚠ --expire-reflog: the reflog is expired and git gc run afterwards, so deleted commits become unrecoverable")
+	}
+	return askConfirmation(in, out, fmt.Sprintf("%s %d branch(es)?", action, len(branches)))
+}
+
+// askConfirmation asks a yes/no question on stdin; anything but y or yes is a no
+func askConfirmation(in io.Reader, out io.Writer, question string) (bool, error) {
+	fmt.Fprintf(out, "%s [y/N]: ", question)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// deleteBatchBranch deletes a single branch, with git branch -D if force is set, and returns the outcome.
+// Failed results carry a user-facing error describing why the branch was not deleted.
+func deleteBatchBranch(branch string, force bool) output.Result {
+	result := output.Result{Branch: branch, Status: output.StatusFailed, Forced: force}
+
+	if !git.BranchExists(branch) {
+		result.Error = "branch not found"
+		return result
+	}
+
+	if git.IsProtected(branch) {
+		result.Error = "branch is protected (use --no-protect to delete anyway)"
+		if branch == detectDefaultBranch() {
+			result.Error = "branch is the default branch (use --allow-default to delete anyway)"
+		}
+		return result
+	}
+
+	if git.IsIgnored(branch) {
+		result.Error = "ignored by .geleteignore (use --no-ignore to delete anyway)"
+		return result
+	}
+
+	if worktree, _ := git.GetWorktreeForBranch(branch); worktree != nil && !worktree.Prunable {
+		result.Error = fmt.Sprintf("checked out in worktree '%s' (remove the worktree first, e.g. with gelete worktrees)", worktree.Path)
+		return result
+	}
+
+	if opts.dryRun {
+		return previewBatchBranch(result, force)
+	}
+
+	// The tip is only used for the restore hint, so a failure to resolve it is not fatal
+	result.SHA, _ = git.GetBranchSHA(branch)
+
+	deleteBranch := repo.DeleteBranch
+	if force {
+		deleteBranch = repo.ForceDeleteBranch
+	}
+
+	if err := deleteWithPrune(branch, deleteBranch); err != nil {
+		result.Error = batchDeleteError(err)
+		return result
+	}
+
+	result.Status = output.StatusDeleted
+	return result
+}
+
+// batchDeleteError returns the user-facing error of a failed deletion: what to do about the errors git
+// classifies, otherwise git's message
+func batchDeleteError(err error) string {
+	var checkedOut *git.ErrCheckedOutElsewhere
+	var locked *git.ErrRefLocked
+	switch {
+	case errors.As(err, &checkedOut):
+		return fmt.Sprintf("checked out in worktree '%s' (remove that worktree or switch its branch first)", checkedOut.Path)
+	case errors.Is(err, git.ErrNotMerged):
+		return "not fully merged (use --force to delete anyway)"
+	case errors.Is(err, git.ErrNotFound):
+		return "branch not found"
+	case errors.As(err, &locked):
+		return fmt.Sprintf("the lock file '%s' exists (another git process or your IDE may be holding a lock; delete the file if none is running)", locked.LockFile)
+	}
+	return err.Error()
+}
+
+// deleteWithPrune deletes a branch, retrying while a lock file is in the way (see git.RetryOnLock), and pruning
+// its worktree and retrying if the deletion failed because the branch is still checked out in a prunable worktree,
+// e.g. one whose directory was deleted
+func deleteWithPrune(branch string, deleteBranch func(context.Context, string) error) error {
+	err := git.RetryOnLock(context.Background(), branch, deleteBranch, nil)
+	if err == nil {
+		return nil
+	}
+
+	worktree, wtErr := git.GetWorktreeForBranch(branch)
+	if wtErr != nil || worktree == nil || !worktree.Prunable {
+		return err
+	}
+	if pruneErr := git.PruneWorktrees(); pruneErr != nil {
+		return err
+	}
+	return git.RetryOnLock(context.Background(), branch, deleteBranch, nil)
+}
+
+// previewBatchBranch returns what would happen to a branch in dry-run mode
+func previewBatchBranch(result output.Result, force bool) output.Result {
+	merged, err := git.IsBranchMerged(result.Branch)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if !merged && !force {
+		result.Error = "not fully merged (use --force to delete anyway)"
+		return result
+	}
+
+	result.Status = output.StatusSkipped
+	result.Forced = !merged
+	result.Error = "dry run"
+	return result
+}
+
+// batchResultLine formats the outcome of a deletion as a line of text
+func batchResultLine(result output.Result) string {
+	switch {
+	case result.Status == output.StatusFailed:
+		return fmt.Sprintf("✗ %s: %s", result.Branch, result.Error)
+	case result.Status == output.StatusSkipped && result.Forced:
+		return fmt.Sprintf("Would force delete branch %s (unmerged)", result.Branch)
+	case result.Status == output.StatusSkipped:
+		return fmt.Sprintf("Would delete branch %s", result.Branch)
+	case result.Forced:
+		return fmt.Sprintf("✓ Force deleted branch %s%s", result.Branch, restoreHint(result.Branch, result.SHA))
+	}
+	return fmt.Sprintf("✓ Deleted branch %s%s", result.Branch, restoreHint(result.Branch, result.SHA))
+}
+
+// restoreHint returns the suffix telling how to restore a deleted branch, or "" if its tip is unknown
+func restoreHint(branch, sha string) string {
+	if sha == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (was %s — restore with: git branch %s %s)", sha, branch, sha)
+}
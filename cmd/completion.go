@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"slices"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// completeBranches completes branch names for the positional arguments, skipping branches already given.
+// Nothing is offered outside a git repository, or with --remote (see listCompletionBranches).
+func completeBranches(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	branches := listCompletionBranches()
+
+	var candidates []string
+	for _, branch := range branches {
+		if !slices.Contains(args, branch) {
+			candidates = append(candidates, branch)
+		}
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completePatterns completes --pattern values with branch names, which match themselves as patterns
+func completePatterns(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return listCompletionBranches(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// listCompletionBranches returns the branches gelete could delete, or nil if they can't be listed
+// (e.g. outside a git repository). Errors are swallowed since completion has no way to report them.
+// With --remote nothing is returned, since branch arguments and --pattern are refused there.
+func listCompletionBranches() []string {
+	if opts.remote {
+		return nil
+	}
+	git.Timeout = opts.gitTimeout
+	if err := openRepository(); err != nil {
+		return nil
+	}
+
+	branches, err := git.ListBranches()
+	if err != nil {
+		return nil
+	}
+	return branches
+}
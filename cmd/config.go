@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/config"
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// configCmd prints the configuration gelete runs with
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Print the effective configuration",
+	Long: `Print the configuration merged from the global config file ($XDG_CONFIG_HOME/gelete/config.yaml)
+and the repository's .gelete.yaml, with built-in defaults for unset keys.
+Command-line flags override these values.`,
+	Args: cobra.NoArgs,
+	RunE: runConfig,
+}
+
+// runConfig prints the effective configuration as YAML, preceded by the files it was read from
+func runConfig(cmd *cobra.Command, args []string) error {
+	if opts.repo != "" {
+		if err := openRepository(); err != nil {
+			return fmt.Errorf("not a git repository: %w", err)
+		}
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		return err
+	}
+	for _, warning := range loaded.Warnings {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", warning)
+	}
+
+	out := cmd.OutOrStdout()
+	if len(loaded.Sources) == 0 {
+		fmt.Fprintln(out, "# No config files found; showing defaults")
+	} else {
+		fmt.Fprintf(out, "# Loaded from: %s\n", strings.Join(loaded.Sources, ", "))
+	}
+
+	data, err := withDefaults(loaded).Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+// withDefaults fills the unset keys of a config with gelete's built-in defaults.
+// The base branch stays empty since it is detected per repository.
+func withDefaults(c config.Config) config.Config {
+	if c.Protect == nil {
+		c.Protect = []string{}
+	}
+	if c.Sort == "" {
+		c.Sort = "name"
+	}
+	if c.Color == nil {
+		color := true
+		c.Color = &color
+	}
+	if c.Theme == "" {
+		c.Theme = ui.ThemeAuto.String()
+	}
+	if c.DateFormat == "" {
+		c.DateFormat = ui.DatesRelative.String()
+	}
+	if c.AutoSelectGone == nil {
+		autoSelectGone := false
+		c.AutoSelectGone = &autoSelectGone
+	}
+	if c.ConfirmThreshold == nil {
+		threshold := defaultConfirmThreshold
+		c.ConfirmThreshold = &threshold
+	}
+	if c.WIPPatterns == nil {
+		c.WIPPatterns = git.DefaultWIPPatterns
+	}
+	return c
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+// docsCmd generates the documentation of the commands, for packaging and the website
+var docsCmd = &cobra.Command{
+	Use:    "docs",
+	Short:  "Generate man pages or Markdown documentation of the commands",
+	Args:   cobra.NoArgs,
+	Hidden: true,
+}
+
+// docsManCmd writes a man page per command
+var docsManCmd = &cobra.Command{
+	Use:   "man <dir>",
+	Short: "Write a man page per command to a directory",
+	Long: `Write a man page per command (gelete.1, gelete-prune.1, ...) to the directory, creating it if needed.
+The date of the pages is taken from SOURCE_DATE_EPOCH if set, so that release builds are reproducible.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDocsMan,
+}
+
+// docsMarkdownCmd writes a Markdown page per command
+var docsMarkdownCmd = &cobra.Command{
+	Use:   "markdown <dir>",
+	Short: "Write a Markdown page per command to a directory, for the website",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDocsMarkdown,
+}
+
+// runDocsMan writes the man pages of rootCmd and its visible subcommands to the directory
+func runDocsMan(cmd *cobra.Command, args []string) error {
+	date, err := sourceDate()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(args[0], 0o755); err != nil {
+		return err
+	}
+
+	// The generation tag would otherwise repeat the date in every page
+	rootCmd.DisableAutoGenTag = true
+	header := &doc.GenManHeader{
+		Title:   "GELETE",
+		Section: "1",
+		Date:    &date,
+		Source:  "gelete " + Version,
+		Manual:  "gelete manual",
+	}
+	return doc.GenManTree(rootCmd, header, args[0])
+}
+
+// runDocsMarkdown writes the Markdown pages of rootCmd and its visible subcommands to the directory
+func runDocsMarkdown(cmd *cobra.Command, args []string) error {
+	if err := os.MkdirAll(args[0], 0o755); err != nil {
+		return err
+	}
+
+	// The generation tag dates every page, which would change them on each run
+	rootCmd.DisableAutoGenTag = true
+	return doc.GenMarkdownTree(rootCmd, args[0])
+}
+
+// sourceDate returns the date to print in generated documentation: SOURCE_DATE_EPOCH (in seconds since the epoch)
+// if set, as in reproducible builds, and the current date otherwise. It is in UTC so the month doesn't depend
+// on the time zone of the build machine.
+func sourceDate() (time.Time, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return time.Now().UTC(), nil
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH '%s': must be seconds since the epoch", epoch)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Exit codes of gelete, documented in the help of the root command
+const (
+	// ExitSuccess: everything was deleted, or there was nothing to do
+	ExitSuccess = 0
+	// ExitError: gelete couldn't run, e.g. outside a git repository, without git, or with invalid flags
+	ExitError = 1
+	// ExitDeletionFailed: one or more deletions failed
+	ExitDeletionFailed = 2
+	// ExitCancelled: the user cancelled while branches were selected for deletion
+	ExitCancelled = 3
+	// ExitInterrupted: the TUI quit on SIGINT; like shells, other signals exit with 128 plus their number
+	ExitInterrupted = 130
+)
+
+// exitCodeHelp documents the exit codes in the help of the root command
+const exitCodeHelp = `Exit codes:
+  0    success, or nothing to delete
+  1    error: not a git repository, git missing, invalid flags, ...
+  2    one or more deletions failed
+  3    cancelled with branches still selected for deletion
+  130  interrupted by SIGINT (143 for SIGTERM)`
+
+// exitError is an error that makes gelete exit with a code other than ExitError
+type exitError struct {
+	code int
+	err  error
+
+	// reported means the user has already seen the error, so main doesn't print it
+	reported bool
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// deletionFailed returns the error for a run in which some deletions failed
+func deletionFailed(format string, args ...any) error {
+	return &exitError{code: ExitDeletionFailed, err: fmt.Errorf(format, args...)}
+}
+
+// cancelled returns the error for a run the user cancelled while branches were selected.
+// main doesn't print it, since the user knows they cancelled.
+func cancelled(format string, args ...any) error {
+	return &exitError{code: ExitCancelled, err: fmt.Errorf(format, args...), reported: true}
+}
+
+// interrupted returns the error for a TUI that quit on a signal, exiting with 128 plus the signal number.
+// Nothing is printed, since whoever sent the signal knows.
+func interrupted(sig os.Signal) error {
+	code := ExitInterrupted
+	if number, ok := sig.(syscall.Signal); ok {
+		code = 128 + int(number)
+	}
+	return &exitError{code: code, err: fmt.Errorf("interrupted by %s", sig), reported: true}
+}
+
+// loadFailed returns the error that prevented the TUI from loading the branches.
+// The TUI shows it as its last screen, so main doesn't print it again.
+func loadFailed(err error) error {
+	return &exitError{code: ExitError, err: err, reported: true}
+}
+
+// ExitCode returns the exit code for an error returned by Execute
+func ExitCode(err error) int {
+	var exitErr *exitError
+	switch {
+	case err == nil:
+		return ExitSuccess
+	case errors.As(err, &exitErr):
+		return exitErr.code
+	}
+	return ExitError
+}
+
+// Reported reports whether an error returned by Execute was already shown to the user, e.g. by the TUI,
+// or needs no message, like cancelling
+func Reported(err error) bool {
+	var exitErr *exitError
+	return errors.As(err, &exitErr) && exitErr.reported
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/spf13/cobra"
+)
+
+// runGoneBatch deletes the branches whose upstream is gone without the TUI, for --gone --yes
+func runGoneBatch(cmd *cobra.Command) error {
+	gone, err := listGoneBranches()
+	if err != nil {
+		return err
+	}
+	if len(gone) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), lang.T(i18n.NoGoneBranches))
+		return nil
+	}
+
+	branches, _ := indexBranches(gone)
+	return runBatch(cmd, branches)
+}
+
+// listGoneBranches returns the local branches whose upstream is gone that pass the merge filter and --mine
+func listGoneBranches() ([]git.BranchInfo, error) {
+	branchInfos, err := listLocalBranches()
+	if err != nil {
+		return nil, err
+	}
+
+	_, mergedBranches, err := detectMergeStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	var gone []git.BranchInfo
+	for _, info := range filterMine(filterGoneBranches(branchInfos)) {
+		if passesMergeFilter(info.Name, mergedBranches) {
+			gone = append(gone, info)
+		}
+	}
+	return gone, nil
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// listCmd prints the deletable local branches for scripts
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print the deletable local branches without the TUI",
+	Long: `Print the deletable local branches: the current branch, protected and ignored branches are left out.
+--format selects the output: names (one per line, the default), json, or a Go text/template applied
+to each branch, printed one per line; branches it prints nothing for are left out. \t and \n in
+the template stand for a tab and a newline.
+The template can use these fields:
+
+  .Name            branch name
+  .SHA             commit the branch points to
+  .LastCommitDate  committer date of the tip, in RFC 3339
+  .Age             how long ago the tip was committed, e.g. "3 weeks ago"
+  .Merged          whether the branch is merged into the base branch
+  .Upstream        remote branch it tracks, e.g. origin/feature-x
+  .UpstreamGone    whether the upstream branch was deleted
+  .WorktreePath    worktree the branch is checked out in
+  .Ahead, .Behind  commits only on the branch, and only on the base branch`,
+	Example: `  gelete list --format '{{.Name}}\t{{.LastCommitDate}}\t{{.Merged}}' | fzf
+  gelete list --format '{{if .UpstreamGone}}{{.Name}}{{end}}' | xargs gelete --yes`,
+	Args: cobra.NoArgs,
+	RunE: runListCommand,
+}
+
+// runListCommand prints the deletable local branches with --format. A template is checked before any git command runs.
+func runListCommand(cmd *cobra.Command, args []string) error {
+	tmpl, err := parseListFormat()
+	if err != nil {
+		return err
+	}
+	if err := setupList(cmd); err != nil {
+		return err
+	}
+
+	switch opts.listFormat {
+	case "json":
+		return runList(cmd)
+	case "names":
+		return printBranchNames(cmd)
+	}
+	return printFormattedBranches(cmd, tmpl)
+}
+
+// parseListFormat returns the template given with --format, or nil for the names and json formats
+func parseListFormat() (*template.Template, error) {
+	if opts.listFormat == "names" || opts.listFormat == "json" {
+		return nil, nil
+	}
+	tmpl, err := output.ParseFormat(opts.listFormat)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format: %w", err)
+	}
+	return tmpl, nil
+}
+
+// setupList opens the repository and sets the user's email and the base branch the branches are compared with
+func setupList(cmd *cobra.Command) error {
+	if err := setup(cmd); err != nil {
+		return err
+	}
+	if err := configureMine(); err != nil {
+		return err
+	}
+	return configureBase(cmd)
+}
+
+// printBranchNames prints the names of the deletable local branches, one per line
+func printBranchNames(cmd *cobra.Command) error {
+	listed, _, err := listBranchesForOutput()
+	if err != nil {
+		return err
+	}
+	for _, branch := range listed {
+		fmt.Fprintln(cmd.OutOrStdout(), branch.info.Name)
+	}
+	return nil
+}
+
+// printFormattedBranches prints the deletable local branches with the --format template
+func printFormattedBranches(cmd *cobra.Command, tmpl *template.Template) error {
+	listed, baseBranch, err := listBranchesForOutput()
+	if err != nil {
+		return err
+	}
+
+	divergence := formatDivergence(listed, baseBranch)
+	branches := make([]output.FormatBranch, 0, len(listed))
+	for _, branch := range listed {
+		var counts *git.AheadBehind
+		if c, ok := divergence[branch.info.Name]; ok {
+			counts = &c
+		}
+		merged := branch.merged != nil && *branch.merged
+		branches = append(branches, output.NewFormatBranch(branch.info, merged, branch.worktree, counts))
+	}
+	return output.WriteFormatted(cmd.OutOrStdout(), tmpl, branches)
+}
+
+// formatDivergence returns the ahead/behind counts of the listed branches for the --format template.
+// Counting commits runs git for each branch, so it is only done for templates showing the counts.
+func formatDivergence(listed []listedBranch, baseBranch string) map[string]git.AheadBehind {
+	if !strings.Contains(opts.listFormat, "Ahead") && !strings.Contains(opts.listFormat, "Behind") {
+		return nil
+	}
+	names := make([]string, len(listed))
+	for i, branch := range listed {
+		names[i] = branch.info.Name
+	}
+	return computeDivergence(names, baseBranch)
+}
+
+// listedBranch is a deletable local branch with what gelete list and --json tell about it
+type listedBranch struct {
+	info git.BranchInfo
+
+	// merged reports whether the branch is merged into the base branch (nil if unknown)
+	merged *bool
+
+	// worktree is the worktree the branch is checked out in (nil if none)
+	worktree *git.Worktree
+}
+
+// listBranchesForOutput returns the deletable local branches passing --mine and the merge filters,
+// with the base branch their merge status was computed against
+func listBranchesForOutput() ([]listedBranch, string, error) {
+	branchInfos, err := listLocalBranches()
+	if err != nil {
+		return nil, "", err
+	}
+	branchInfos = filterMine(branchInfos)
+
+	worktrees, err := repo.ListWorktrees(context.Background())
+	if err != nil {
+		return nil, "", err
+	}
+	branchWorktrees := make(map[string]git.Worktree)
+	for _, wt := range worktrees {
+		if wt.Branch != "" {
+			branchWorktrees[wt.Branch] = wt
+		}
+	}
+
+	baseBranch, mergedBranches, err := detectMergeStatus()
+	if err != nil {
+		return nil, "", err
+	}
+
+	branches := make([]listedBranch, 0, len(branchInfos))
+	for _, info := range branchInfos {
+		if !passesMergeFilter(info.Name, mergedBranches) {
+			continue
+		}
+
+		branch := listedBranch{info: info}
+		if mergedBranches != nil {
+			isMerged := mergedBranches[info.Name]
+			branch.merged = &isMerged
+		}
+		if wt, ok := branchWorktrees[info.Name]; ok {
+			branch.worktree = &wt
+		}
+		branches = append(branches, branch)
+	}
+	return branches, baseBranch, nil
+}
+
+// runList prints the deletable local branches as JSON instead of starting the TUI
+func runList(cmd *cobra.Command) error {
+	listed, _, err := listBranchesForOutput()
+	if err != nil {
+		return err
+	}
+
+	branches := make([]output.Branch, 0, len(listed))
+	for _, branch := range listed {
+		branches = append(branches, output.NewBranch(branch.info, branch.merged, branch.worktree))
+	}
+	return output.WriteJSON(cmd.OutOrStdout(), branches)
+}
+
+// runPlainList prints the branches one per line instead of starting the TUI when gelete doesn't run in a
+// terminal, e.g. when its output is piped or in a CI job. The --merged-only and --unmerged-only filters apply.
+func runPlainList(cmd *cobra.Command, branches []string) error {
+	_, mergedBranches, err := detectMergeStatus()
+	if err != nil {
+		return err
+	}
+
+	for _, branch := range filterByMergeStatus(branches, mergedBranches) {
+		fmt.Fprintln(cmd.OutOrStdout(), branch)
+	}
+	return nil
+}
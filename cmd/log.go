@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/audit"
+	"github.com/spf13/cobra"
+)
+
+// logCmd prints the most recent deletions recorded in the audit log
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show the most recent branch deletions from the audit log",
+	Long: `Print the most recent deletions recorded in the audit log, oldest first: when each branch was deleted,
+its tip before deletion, and whether it was forced or took a worktree or remote branch with it.
+Failed deletions are listed with git's error. Every gelete run appends to .git/gelete-log.jsonl,
+or to the file given with --audit-log.`,
+	Args: cobra.NoArgs,
+	RunE: runLog,
+}
+
+// runLog prints the last --limit entries of the audit log
+func runLog(cmd *cobra.Command, args []string) error {
+	if err := setup(cmd); err != nil {
+		return err
+	}
+	if opts.logLimit < 0 {
+		return fmt.Errorf("invalid --limit %d: must not be negative", opts.logLimit)
+	}
+
+	entries, err := audit.Last(opts.logLimit)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if len(entries) == 0 {
+		path, _ := audit.Path()
+		fmt.Fprintf(out, "No deletions recorded in %s.\n", displayPath(path))
+		return nil
+	}
+	for _, entry := range entries {
+		printLogEntry(out, entry)
+	}
+	return nil
+}
+
+// printLogEntry prints one deletion as a line: its local time, the outcome and the details of what was removed
+func printLogEntry(out io.Writer, entry audit.Entry) {
+	timestamp := entry.Time.Local().Format("2006-01-02 15:04:05")
+	if entry.Failed() {
+		fmt.Fprintf(out, "%s  ✗ %s: %s\n", timestamp, entry.Branch, entry.Error)
+		return
+	}
+
+	line := fmt.Sprintf("%s  ✓ %s", timestamp, entry.Branch)
+	if details := logEntryDetails(entry); len(details) > 0 {
+		line += " (" + strings.Join(details, " • ") + ")"
+	}
+	fmt.Fprintln(out, line)
+}
+
+// logEntryDetails lists what else is known about a successful deletion
+func logEntryDetails(entry audit.Entry) []string {
+	var details []string
+	if entry.SHA != "" {
+		details = append(details, "was "+entry.SHA)
+	}
+	if entry.Forced {
+		details = append(details, "forced")
+	}
+	if entry.Worktree != "" {
+		details = append(details, fmt.Sprintf("worktree %s removed", entry.Worktree))
+	}
+	switch {
+	case entry.RemoteError != "":
+		details = append(details, fmt.Sprintf("deleting %s failed: %s", entry.Remote, entry.RemoteError))
+	case entry.Remote != "" && entry.Remote != entry.Branch:
+		details = append(details, fmt.Sprintf("%s deleted", entry.Remote))
+	}
+	return details
+}
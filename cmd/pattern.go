@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// runPattern selects the branches matching --pattern. With --yes (or --json) they are deleted
+// without the TUI; otherwise the TUI opens with the matching branches pre-selected.
+func runPattern(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("branch arguments cannot be combined with --pattern")
+	}
+	if opts.remote {
+		return fmt.Errorf("--pattern cannot be combined with --remote")
+	}
+
+	branches, err := listDeletableBranches()
+	if err != nil {
+		return err
+	}
+
+	matched, unmatched := git.FilterBranches(branches, opts.patterns)
+	for _, pattern := range unmatched {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: pattern '%s' matched no branches\n", pattern)
+	}
+
+	_, mergedBranches, err := detectMergeStatus()
+	if err != nil {
+		return err
+	}
+	matched = filterByMergeStatus(matched, mergedBranches)
+	if matched, err = filterMineBranches(matched); err != nil {
+		return err
+	}
+
+	if len(matched) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No branches match the given patterns.")
+		return nil
+	}
+
+	if opts.yes || opts.json {
+		return runBatch(cmd, matched)
+	}
+	return runLocal(cmd, matched)
+}
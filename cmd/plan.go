@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/Kdaito/gelete/internal/output"
+	"github.com/Kdaito/gelete/internal/plan"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// planCmd writes the branches selected in the TUI to a plan file instead of deleting them
+var planCmd = &cobra.Command{
+	Use:   "plan -o <file>",
+	Short: "Select branches in the TUI and write them to a plan file for review instead of deleting them",
+	Long: `Select branches in the terminal UI as usual, but instead of deleting them, write a plan listing each
+branch with its tip SHA, its merge status and what deleting it takes (removing its worktree, deleting or
+force deleting it). Review the plan, e.g. in a pull request, then delete the branches with gelete apply.`,
+	Args: cobra.NoArgs,
+	RunE: runPlan,
+}
+
+// applyCmd deletes the branches of a plan written by gelete plan
+var applyCmd = &cobra.Command{
+	Use:   "apply <plan>",
+	Short: "Delete the branches of a plan written by gelete plan",
+	Long: `Delete the branches listed in a plan written by gelete plan, without asking for confirmation.
+Each branch is checked first: branches that no longer exist, whose tip moved since the plan was made
+or that are now checked out are skipped with a warning. A plan none of whose branches can be applied
+any more is refused.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runApply,
+}
+
+// runPlan runs the TUI in plan mode and writes the confirmed selection to the --output plan
+func runPlan(cmd *cobra.Command, args []string) error {
+	if err := setup(cmd); err != nil {
+		return err
+	}
+	if err := configureRun(cmd); err != nil {
+		return err
+	}
+	if !opts.interactive {
+		return errors.New("gelete plan selects the branches in the TUI, which needs a terminal")
+	}
+
+	branchInfos, err := listLocalBranches()
+	if err != nil {
+		return err
+	}
+	if reportNoPlanBranches(cmd, branchInfos) {
+		return nil
+	}
+
+	model, err := localModel(branchInfos, nil)
+	if err != nil {
+		return err
+	}
+	model.PlanPath = opts.planOutput
+
+	closeLog, err := logToFile(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+
+	result, err := runAppProgram(model)
+	if err != nil {
+		return err
+	}
+	if !result.Planned {
+		return appResult(result)
+	}
+
+	return writePlan(cmd, result)
+}
+
+// reportNoPlanBranches tells that there are no branches to plan the deletion of, the listed branches
+// being none or none of them the user's with --mine. Reports whether there are none.
+func reportNoPlanBranches(cmd *cobra.Command, branchInfos []git.BranchInfo) bool {
+	switch {
+	case len(branchInfos) == 0:
+		fmt.Fprintln(cmd.OutOrStdout(), lang.T(i18n.NoBranchesToDelete))
+		fmt.Fprintln(cmd.OutOrStdout(), lang.T(i18n.CurrentBranchExcluded))
+		return true
+	case len(filterMine(branchInfos)) == 0:
+		reportNoMineBranches(cmd)
+		return true
+	}
+	return false
+}
+
+// writePlan writes the plan of the branches confirmed in the TUI to the --output plan file
+func writePlan(cmd *cobra.Command, result ui.AppModel) error {
+	p, err := buildPlan(result)
+	if err != nil {
+		return err
+	}
+	if err := plan.Write(opts.planOutput, p); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Wrote a plan to delete %d branch(es) to %s; review it, then run: gelete apply %s\n",
+		len(p.Entries), opts.planOutput, opts.planOutput)
+	return nil
+}
+
+// buildPlan returns the plan of the branches confirmed in the TUI: their worktrees are removed first,
+// then they are deleted, or force deleted if force mode was on
+func buildPlan(result ui.AppModel) (plan.Plan, error) {
+	p := plan.Plan{
+		Version:    plan.Version,
+		Repository: repo.Root(),
+		Base:       result.BaseBranch,
+		Created:    time.Now().UTC().Truncate(time.Second),
+	}
+
+	for _, branch := range result.PlannedBranches() {
+		entry := plan.Entry{Branch: branch, SHA: result.BranchDetails[branch].SHA, Merged: result.MergedBranches[branch]}
+		if entry.SHA == "" {
+			sha, err := git.ResolveCommit("refs/heads/" + branch)
+			if err != nil {
+				return plan.Plan{}, fmt.Errorf("failed to resolve the tip of '%s': %w", branch, err)
+			}
+			entry.SHA = sha
+		}
+
+		if path, hasWorktree := result.BranchWorktrees[branch]; hasWorktree {
+			entry.Worktree = path
+			entry.Actions = append(entry.Actions, plan.RemoveWorktree)
+		}
+		if result.ForceMode {
+			entry.Actions = append(entry.Actions, plan.ForceDelete)
+		} else {
+			entry.Actions = append(entry.Actions, plan.Delete)
+		}
+		p.Entries = append(p.Entries, entry)
+	}
+	return p, nil
+}
+
+// runApply checks the entries of the plan given as argument against the repository and deletes the branches
+// of those still valid, printing a result line per branch and a summary
+func runApply(cmd *cobra.Command, args []string) error {
+	if err := setup(cmd); err != nil {
+		return err
+	}
+
+	p, err := plan.Read(args[0])
+	if err != nil {
+		return err
+	}
+	if !git.SamePath(p.Repository, repo.Root()) {
+		return fmt.Errorf("plan %s was made in %s, not in this repository (%s)", args[0], p.Repository, repo.Root())
+	}
+	if len(p.Entries) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "The plan lists no branches.")
+		return nil
+	}
+
+	out, errOut := cmd.OutOrStdout(), cmd.ErrOrStderr()
+	valid, err := validPlanEntries(errOut, p.Entries)
+	if err != nil {
+		return err
+	}
+	if len(valid) == 0 {
+		return fmt.Errorf("plan %s is stale: none of its %d branch(es) is as it was when the plan was made; make a new plan with gelete plan",
+			args[0], len(p.Entries))
+	}
+
+	failed := 0
+	for _, entry := range valid {
+		result := applyPlanEntry(entry)
+		reportBatchResult(out, errOut, result)
+		if result.Status == output.StatusFailed {
+			failed++
+		}
+	}
+
+	skipped := len(p.Entries) - len(valid)
+	fmt.Fprintf(out, "Applied %s: %d deleted • %d skipped • %d failed\n", args[0], len(valid)-failed, skipped, failed)
+	if failed > 0 {
+		return deletionFailed("failed to delete %d of %d planned branch(es)", failed, len(p.Entries))
+	}
+	return nil
+}
+
+// validPlanEntries returns the entries whose branch is unchanged since the plan was made, warning on errOut
+// about each entry that is skipped
+func validPlanEntries(errOut io.Writer, entries []plan.Entry) ([]plan.Entry, error) {
+	current, detached, err := git.GetHeadBranch()
+	if err != nil {
+		return nil, err
+	}
+
+	var valid []plan.Entry
+	for _, entry := range entries {
+		reason, err := staleReason(entry, current, detached)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			fmt.Fprintf(errOut, "Warning: skipping %s: %s\n", entry.Branch, reason)
+			continue
+		}
+		valid = append(valid, entry)
+	}
+	return valid, nil
+}
+
+// staleReason tells why a plan entry can't be applied any more, or returns "" if it still can
+func staleReason(entry plan.Entry, current string, detached bool) (string, error) {
+	if !git.BranchExists(entry.Branch) {
+		return "the branch no longer exists", nil
+	}
+	if entry.Branch == current && !detached {
+		return "it is now the current branch", nil
+	}
+
+	sha, err := git.ResolveCommit("refs/heads/" + entry.Branch)
+	if err != nil {
+		return "", err
+	}
+	if sha != entry.SHA {
+		return fmt.Sprintf("its tip moved from %s to %s since the plan was made", shortSHA(entry.SHA), shortSHA(sha)), nil
+	}
+	return "", nil
+}
+
+// shortSHA abbreviates a full SHA for messages
+func shortSHA(sha string) string {
+	return sha[:min(len(sha), 7)]
+}
+
+// applyPlanEntry removes the worktree of a planned branch if the plan says so, then deletes the branch
+func applyPlanEntry(entry plan.Entry) output.Result {
+	forced := entry.Has(plan.ForceDelete)
+	if entry.Has(plan.RemoveWorktree) {
+		if err := removePlannedWorktree(entry); err != nil {
+			return output.Result{Branch: entry.Branch, Status: output.StatusFailed, Forced: forced, Error: err.Error()}
+		}
+	}
+	return deleteBatchBranch(entry.Branch, forced)
+}
+
+// removePlannedWorktree removes the worktree a planned branch is checked out in. A worktree that is already
+// gone is fine, but one other than the planned worktree is left alone, since removing it wasn't reviewed.
+func removePlannedWorktree(entry plan.Entry) error {
+	worktree, err := git.GetWorktreeForBranch(entry.Branch)
+	if err != nil {
+		return err
+	}
+	if worktree == nil {
+		return nil
+	}
+	if !git.SamePath(worktree.Path, entry.Worktree) {
+		return fmt.Errorf("checked out in worktree '%s' instead of the planned '%s'", worktree.Path, entry.Worktree)
+	}
+	if err := repo.RemoveWorktree(context.Background(), worktree.Path); err != nil {
+		return fmt.Errorf("failed to remove worktree '%s': %w", worktree.Path, err)
+	}
+	return nil
+}
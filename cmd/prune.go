@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// pruneCmd cleans up what is left over once branches were merged or deleted on the remote
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete gone and merged branches and prune stale worktrees in one pass",
+	Long: `Gather the branches whose upstream branch was deleted, the branches fully merged into the base branch
+and the worktrees whose directory is missing, then prune the worktrees and delete the branches after a
+single confirmation (or right away with --yes). The current branch and protected branches are never included.`,
+	Args: cobra.NoArgs,
+	RunE: runPrune,
+}
+
+// pruneCandidates holds what gelete prune cleans up, by category
+type pruneCandidates struct {
+	// gone holds the branches whose upstream branch was deleted
+	gone []string
+
+	// merged holds the other branches fully merged into base
+	merged []string
+	base   string
+
+	// worktrees holds the prunable worktrees, e.g. those whose directory is missing
+	worktrees []git.Worktree
+}
+
+// empty reports whether there is nothing to prune
+func (c pruneCandidates) empty() bool {
+	return len(c.gone) == 0 && len(c.merged) == 0 && len(c.worktrees) == 0
+}
+
+// runPrune lists the gone branches, merged branches and stale worktrees, asks for confirmation
+// unless --yes or --dry-run is given, and cleans them up: worktrees first, so their branches can be deleted
+func runPrune(cmd *cobra.Command, args []string) error {
+	if err := setup(cmd); err != nil {
+		return err
+	}
+	if err := configureBase(cmd); err != nil {
+		return err
+	}
+
+	candidates, err := listPruneCandidates()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if candidates.empty() {
+		fmt.Fprintln(out, "Nothing to prune.")
+		return nil
+	}
+	printPruneCandidates(out, candidates)
+
+	if !opts.yes && !opts.dryRun {
+		question := fmt.Sprintf("Prune %d worktree(s) and delete %d branch(es)?",
+			len(candidates.worktrees), len(candidates.gone)+len(candidates.merged))
+		confirmed, err := askConfirmation(cmd.InOrStdin(), out, question)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(out, "Aborted.")
+			return cancelled("prune aborted")
+		}
+	}
+
+	return prune(out, cmd.ErrOrStderr(), candidates)
+}
+
+// listPruneCandidates gathers the deletable branches whose upstream is gone, the other deletable branches
+// merged into the base branch, and the prunable worktrees. Branches checked out in a worktree that isn't
+// prunable are left out, since they can't be deleted before the worktree is removed.
+func listPruneCandidates() (pruneCandidates, error) {
+	var candidates pruneCandidates
+
+	branchInfos, err := listLocalBranches()
+	if err != nil {
+		return candidates, err
+	}
+	branchWorktrees, _, err := listBranchWorktrees()
+	if err != nil {
+		return candidates, err
+	}
+
+	var mergedBranches map[string]bool
+	candidates.base, mergedBranches = detectMergedBranches()
+
+	for _, info := range branchInfos {
+		switch {
+		case branchWorktrees[info.Name] != "":
+		case info.Gone:
+			candidates.gone = append(candidates.gone, info.Name)
+		case mergedBranches[info.Name]:
+			candidates.merged = append(candidates.merged, info.Name)
+		}
+	}
+
+	candidates.worktrees, err = listPrunableWorktrees()
+	return candidates, err
+}
+
+// listPrunableWorktrees returns the linked worktrees git worktree prune would clean up
+func listPrunableWorktrees() ([]git.Worktree, error) {
+	worktrees, err := repo.ListWorktrees(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var prunable []git.Worktree
+	for _, wt := range worktrees {
+		if wt.Prunable && !wt.Main {
+			prunable = append(prunable, wt)
+		}
+	}
+	return prunable, nil
+}
+
+// printPruneCandidates prints what will be cleaned up, by category
+func printPruneCandidates(out io.Writer, c pruneCandidates) {
+	printCategory(out, "Branches whose upstream is gone:", c.gone)
+	printCategory(out, fmt.Sprintf("Branches merged into %s:", c.base), c.merged)
+
+	var worktrees []string
+	for _, wt := range c.worktrees {
+		worktrees = append(worktrees, fmt.Sprintf("%s (%s)", wt.Path, worktreeLabel(wt)))
+	}
+	printCategory(out, "Worktrees whose directory is missing:", worktrees)
+}
+
+// printCategory prints a heading followed by its items, or nothing if there are none
+func printCategory(out io.Writer, heading string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintln(out, heading)
+	for _, item := range items {
+		fmt.Fprintf(out, "  • %s\n", item)
+	}
+}
+
+// worktreeLabel returns the branch checked out in a worktree, or a note if HEAD is detached
+func worktreeLabel(wt git.Worktree) string {
+	if wt.Branch == "" {
+		return "detached HEAD"
+	}
+	return wt.Branch
+}
+
+// prune prunes the stale worktrees, then deletes the gone and merged branches,
+// printing a result line for each and a summary with the counts per category
+func prune(out, errOut io.Writer, c pruneCandidates) error {
+	if err := pruneStaleWorktrees(out, c.worktrees); err != nil {
+		return deletionFailed("%w", err)
+	}
+	goneResults, goneFailed := deleteBatchBranches(out, errOut, c.gone)
+	mergedResults, mergedFailed := deleteBatchBranches(out, errOut, c.merged)
+
+	if opts.dryRun {
+		fmt.Fprintln(out, "Dry run: nothing was pruned or deleted.")
+		return nil
+	}
+
+	var summary []string
+	if len(c.worktrees) > 0 {
+		summary = append(summary, fmt.Sprintf("%d stale worktree(s) pruned", len(c.worktrees)))
+	}
+	if len(goneResults) > 0 {
+		summary = append(summary, fmt.Sprintf("%d of %d gone branch(es) deleted", len(goneResults)-goneFailed, len(goneResults)))
+	}
+	if len(mergedResults) > 0 {
+		summary = append(summary, fmt.Sprintf("%d of %d merged branch(es) deleted", len(mergedResults)-mergedFailed, len(mergedResults)))
+	}
+	fmt.Fprintln(out, strings.Join(summary, " • "))
+
+	if failed := goneFailed + mergedFailed; failed > 0 {
+		return deletionFailed("failed to delete %d of %d branch(es)", failed, len(goneResults)+len(mergedResults))
+	}
+	return nil
+}
+
+// pruneStaleWorktrees removes the administrative data of the stale worktrees with a single git worktree prune
+func pruneStaleWorktrees(out io.Writer, worktrees []git.Worktree) error {
+	if len(worktrees) == 0 {
+		return nil
+	}
+	if !opts.dryRun {
+		if err := git.PruneWorktrees(); err != nil {
+			return err
+		}
+	}
+
+	for _, wt := range worktrees {
+		if opts.dryRun {
+			fmt.Fprintf(out, "Would prune worktree %s\n", wt.Path)
+		} else {
+			fmt.Fprintf(out, "✓ Pruned worktree %s\n", wt.Path)
+		}
+	}
+	return nil
+}
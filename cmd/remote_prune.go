@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// remotePruneCmd deletes the remote-tracking refs of branches that were deleted on the remote
+var remotePruneCmd = &cobra.Command{
+	Use:   "remote-prune",
+	Short: "Delete stale remote-tracking refs of branches deleted on the remote",
+	Long: `List the remote-tracking refs (e.g. origin/feature-x) whose branch no longer exists on the remote,
+as found by git remote prune --dry-run, then delete the refs selected in a terminal UI, or all of them
+with --yes. Only the local refs are deleted; the remotes and local branches are left untouched.`,
+	Args: cobra.NoArgs,
+	RunE: runRemotePrune,
+}
+
+// runRemotePrune lists the stale remote-tracking refs of the --remote remotes and prunes them
+// in the TUI, or all of them with --yes; without a terminal they are printed one per line
+func runRemotePrune(cmd *cobra.Command, args []string) error {
+	if err := setup(cmd); err != nil {
+		return err
+	}
+	resolveInteractive(cmd)
+
+	out := cmd.OutOrStdout()
+	remotes, err := git.ListRemotes()
+	if err != nil {
+		return err
+	}
+	if len(remotes) == 0 {
+		fmt.Fprintln(out, "No remotes configured; nothing to prune.")
+		return nil
+	}
+	if err := checkPruneRemotes(remotes); err != nil {
+		return err
+	}
+
+	refs, err := git.ListStaleTrackingRefs(opts.pruneRemotes...)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(refs) == 0:
+		fmt.Fprintln(out, "No stale remote-tracking refs.")
+		return nil
+	case opts.yes:
+		return pruneTrackingRefs(out, cmd.ErrOrStderr(), refs)
+	case !opts.interactive:
+		for _, ref := range refs {
+			fmt.Fprintln(out, ref.Ref)
+		}
+		return nil
+	}
+	return runTrackingRefUI(cmd, refs)
+}
+
+// checkPruneRemotes refuses the --remote remotes the repository doesn't have, before git is asked about them
+func checkPruneRemotes(remotes []string) error {
+	for _, remote := range opts.pruneRemotes {
+		if !slices.Contains(remotes, remote) {
+			return fmt.Errorf("unknown remote '%s' (remotes: %s); choose one with --remote", remote, strings.Join(remotes, ", "))
+		}
+	}
+	return nil
+}
+
+// pruneTrackingRefs deletes the stale refs one by one, printing a result line for each
+func pruneTrackingRefs(out, errOut io.Writer, refs []git.StaleTrackingRef) error {
+	failed := 0
+	for _, ref := range refs {
+		if err := git.DeleteRemoteTrackingRef(ref.Ref); err != nil {
+			fmt.Fprintf(errOut, "✗ Failed to prune %s: %v\n", ref.Ref, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(out, "✓ Pruned %s\n", ref.Ref)
+	}
+
+	if failed > 0 {
+		return deletionFailed("failed to prune %d of %d remote-tracking ref(s)", failed, len(refs))
+	}
+	return nil
+}
+
+// runTrackingRefUI runs the TUI over the stale refs, all of them selected to start with
+func runTrackingRefUI(cmd *cobra.Command, refs []git.StaleTrackingRef) error {
+	selected := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		selected[ref.Ref] = true
+	}
+	model := ui.TrackingRefModel{
+		Refs:     refs,
+		Selected: selected,
+		State:    ui.TrackingRefStateSelection,
+		Styles:   styles(),
+		Lang:     lang,
+	}
+
+	closeLog, err := logToFile(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+
+	final, err := runProgram(model)
+	if err != nil {
+		return fmt.Errorf("error running UI: %w", err)
+	}
+	result, ok := final.(ui.TrackingRefModel)
+	switch {
+	case !ok:
+	case result.Interrupted != nil:
+		return interrupted(result.Interrupted)
+	case result.HasFailures():
+		return deletionFailed("failed to prune %d remote-tracking ref(s)", len(result.Failures))
+	case result.WasCancelled():
+		return cancelled("cancelled with remote-tracking refs selected for pruning")
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Kdaito/gelete/internal/git"
+)
+
+// branchReportEnabled reports whether a batch deletion lists the local branches before and after the run,
+// so unattended runs (--yes, and every --json run) leave a record of what existed and what remains.
+// Nothing is listed with --quiet, whose output is discarded anyway.
+func branchReportEnabled() bool {
+	return (opts.yes || opts.json) && !opts.quiet
+}
+
+// listReportBranches returns every local branch for the before/after report, never nil so JSON has an array
+func listReportBranches() ([]string, error) {
+	branches, err := git.ListAllBranches()
+	if branches == nil {
+		branches = []string{}
+	}
+	return branches, err
+}
+
+// printBranchSnapshot lists the local branches for the report, before the deletions of the targets or after them,
+// and prints its section unless --json is set, which reports them in its document instead.
+// Returns nil when the report is disabled (see branchReportEnabled).
+func printBranchSnapshot(out io.Writer, targets []string, deleted bool) ([]string, error) {
+	if !branchReportEnabled() {
+		return nil, nil
+	}
+	branches, err := listReportBranches()
+	if err != nil || opts.json {
+		return branches, err
+	}
+
+	if deleted {
+		printAfterSection(out, branches)
+	} else {
+		printBeforeSection(out, branches, targets)
+	}
+	return branches, nil
+}
+
+// printBeforeSection prints the "Before" section of the report: every local branch, with the ones about
+// to be deleted marked with a *
+func printBeforeSection(out io.Writer, before, targets []string) {
+	targeted := make(map[string]bool, len(targets))
+	for _, branch := range targets {
+		targeted[branch] = true
+	}
+
+	marked := 0
+	for _, branch := range before {
+		if targeted[branch] {
+			marked++
+		}
+	}
+
+	fmt.Fprintf(out, "Before: %d local branch(es), %d to delete (*)\n", len(before), marked)
+	for _, branch := range before {
+		marker := " "
+		if targeted[branch] {
+			marker = "*"
+		}
+		fmt.Fprintf(out, "  %s %s\n", marker, branch)
+	}
+	fmt.Fprintln(out)
+}
+
+// printAfterSection prints the "After" section of the report: the local branches left after the run
+func printAfterSection(out io.Writer, after []string) {
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "After: %d local branch(es)\n", len(after))
+	for _, branch := range after {
+		fmt.Fprintf(out, "    %s\n", branch)
+	}
+}
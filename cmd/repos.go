@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/Kdaito/gelete/internal/config"
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/output"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// runAllRepos runs gelete over the repositories directly inside the --all-repos directory: the TUI picks
+// a repository and opens its branch list, --gone --yes deletes the gone branches of every repository, and
+// otherwise the repositories are printed with their branch counts.
+func runAllRepos(cmd *cobra.Command, args []string) error {
+	if err := setupGit(cmd); err != nil {
+		return err
+	}
+	if err := checkAllReposFlags(cmd, args); err != nil {
+		return err
+	}
+	resolveInteractive(cmd)
+	dirs, err := git.DiscoverRepositories(opts.allRepos)
+	if err != nil {
+		return err
+	}
+
+	// Each repository is configured from its own config files, starting over from the flags
+	baseline := opts
+	switch {
+	case opts.gone && opts.yes:
+		return cleanGoneAcrossRepositories(cmd, dirs, baseline)
+	case opts.interactive:
+		return pickRepositories(cmd, dirs, baseline)
+	}
+	return printRepositories(cmd, dirs)
+}
+
+// checkAllReposFlags refuses the flags that select a single repository or branches by name, which don't
+// apply across repositories
+func checkAllReposFlags(cmd *cobra.Command, args []string) error {
+	for _, name := range []string{"repo", "pattern", "stale", "select-wip", "json", "remote", "backup-bundle"} {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("--all-repos cannot be combined with --%s", name)
+		}
+	}
+	switch {
+	case len(args) > 0:
+		return fmt.Errorf("--all-repos cannot be combined with branch arguments")
+	case opts.yes && !opts.gone:
+		return fmt.Errorf("--all-repos only deletes without the TUI with --gone")
+	}
+	if _, err := ui.ParseTheme(opts.theme); err != nil {
+		return err
+	}
+	return checkMergeFilter(args)
+}
+
+// openRepositoryAt opens the repository in dir and configures gelete for it like setup does, from the
+// flags in baseline and the repository's config files
+func openRepositoryAt(cmd *cobra.Command, dir string, baseline options) error {
+	opts = baseline
+	opts.repo = dir
+	cfg = config.Config{}
+
+	if err := openRepository(); err != nil {
+		return err
+	}
+	if err := configureRepository(cmd); err != nil {
+		return err
+	}
+	if err := checkRepositoryState(); err != nil {
+		return err
+	}
+	return configureBase(cmd)
+}
+
+// summarizeRepositories counts the branches of the repositories concurrently. The counts only apply the
+// built-in protected branches and --protect: the configuration of a repository applies once it is opened.
+func summarizeRepositories(dirs []string) []git.RepositorySummary {
+	git.SetIgnoreRules(nil)
+	if opts.noProtect {
+		git.SetProtectedPatterns(nil)
+	} else {
+		git.SetProtectedPatterns(slices.Concat(git.DefaultProtectedBranches, opts.protect))
+	}
+	return git.SummarizeRepositories(dirs)
+}
+
+// printRepositories prints each repository with its branch counts instead of starting the TUI.
+// Repositories that can't be read are printed with their error and make the run fail once all are printed.
+func printRepositories(cmd *cobra.Command, dirs []string) error {
+	summaries := summarizeRepositories(dirs)
+	broken := 0
+	for _, summary := range summaries {
+		if summary.Err != nil {
+			broken++
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), repositoryLine(summary))
+	}
+
+	if broken > 0 {
+		return fmt.Errorf("failed to read %d of %d repositories", broken, len(summaries))
+	}
+	return nil
+}
+
+// repositoryLine describes a repository and its branch counts, e.g. "~/repos/api: 3 branch(es), 1 gone"
+func repositoryLine(summary git.RepositorySummary) string {
+	path := displayPath(summary.Dir)
+	switch {
+	case summary.Err != nil:
+		return fmt.Sprintf("%s: error: %v", path, summary.Err)
+	case summary.Branches == 0:
+		return fmt.Sprintf("%s: no branches to delete", path)
+	}
+	return fmt.Sprintf("%s: %d branch(es), %d gone", path, summary.Branches, summary.Gone)
+}
+
+// repoRun tallies the deletions of the branch lists opened from the repository picker
+type repoRun struct {
+	failed int
+	total  int
+}
+
+// add counts the results of a branch list
+func (r *repoRun) add(result ui.AppModel) {
+	r.failed += result.FailedCount()
+	r.total += len(result.Results)
+}
+
+// err returns the error of the whole run, which fails if a deletion failed in any repository
+func (r repoRun) err() error {
+	if r.failed > 0 {
+		return deletionFailed("failed to delete %d of %d branch(es)", r.failed, r.total)
+	}
+	return nil
+}
+
+// finish returns the error of the whole run once the user quit the branch list of the last repository.
+// Failed deletions are counted across repositories; the other outcomes come from the last branch list.
+func (r repoRun) finish(last ui.AppModel) error {
+	if err := appResult(last); err != nil && ExitCode(err) != ExitDeletionFailed {
+		return err
+	}
+	return r.err()
+}
+
+// pickRepositories alternates between the repository picker and the branch list of the picked repository,
+// until the user quits either of them. The branch counts are gathered again each time the picker opens,
+// so they reflect the deletions made in between.
+func pickRepositories(cmd *cobra.Command, dirs []string, baseline options) error {
+	closeLog, err := logToFile(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+
+	root, err := filepath.Abs(baseline.allRepos)
+	if err != nil {
+		return fmt.Errorf("failed to resolve '%s': %w", baseline.allRepos, err)
+	}
+	var (
+		run     repoRun
+		cursor  int
+		message string
+	)
+	for {
+		opts = baseline
+		picked, err := runPicker(ui.NewRepoPickerModel(displayPath(root), func() []git.RepositorySummary {
+			return summarizeRepositories(dirs)
+		}, cursor, styles()), message)
+		switch {
+		case err != nil:
+			return err
+		case picked.Interrupted != nil:
+			return interrupted(picked.Interrupted)
+		case picked.Chosen == "":
+			return run.err()
+		}
+
+		cursor = picked.CursorIndex
+		var result ui.AppModel
+		if result, message, err = browseRepository(cmd, picked.Chosen, baseline); err != nil {
+			return err
+		}
+		run.add(result)
+		if message == "" && !result.WentBack {
+			return run.finish(result)
+		}
+	}
+}
+
+// runPicker runs the repository picker with message shown until the first key press, and returns its final state
+func runPicker(picker ui.RepoPickerModel, message string) (ui.RepoPickerModel, error) {
+	picker.Message = message
+	final, err := runProgram(picker)
+	if err != nil {
+		return ui.RepoPickerModel{}, fmt.Errorf("error running UI: %w", err)
+	}
+	result, _ := final.(ui.RepoPickerModel)
+	return result, nil
+}
+
+// browseRepository runs the TUI over the local branches of the repository in dir, with a back key that
+// returns to the picker. If the repository can't be opened or has no branches to delete, the TUI doesn't
+// start and the reason is returned as a message for the picker instead.
+func browseRepository(cmd *cobra.Command, dir string, baseline options) (ui.AppModel, string, error) {
+	if err := openRepositoryAt(cmd, dir, baseline); err != nil {
+		return ui.AppModel{}, fmt.Sprintf("%s can't be opened: %v", filepath.Base(dir), err), nil
+	}
+	branchInfos, err := listLocalBranches()
+	if err != nil {
+		return ui.AppModel{}, fmt.Sprintf("%s can't be opened: %v", filepath.Base(dir), err), nil
+	}
+	if len(branchInfos) == 0 {
+		return ui.AppModel{}, fmt.Sprintf("No branches to delete in %s.", filepath.Base(dir)), nil
+	}
+
+	model, err := localModel(branchInfos, nil)
+	if err != nil {
+		return ui.AppModel{}, fmt.Sprintf("%s can't be opened: %v", filepath.Base(dir), err), nil
+	}
+	model.CanGoBack = true
+	result, err := runAppProgram(model)
+	return result, "", err
+}
+
+// repoCleanup holds the outcome of deleting the gone branches of a repository
+type repoCleanup struct {
+	dir     string
+	deleted int
+	failed  int
+
+	// skipped counts the branches --dry-run would have deleted
+	skipped int
+
+	err error
+}
+
+// summaryLine describes the outcome for the summary printed after all repositories were cleaned
+func (c repoCleanup) summaryLine() string {
+	path := displayPath(c.dir)
+	switch {
+	case c.err != nil:
+		return fmt.Sprintf("  %s: error", path)
+	case c.deleted+c.failed+c.skipped == 0:
+		return fmt.Sprintf("  %s: no gone branches", path)
+	case c.skipped > 0:
+		return fmt.Sprintf("  %s: %d would be deleted, %d failed", path, c.skipped, c.failed)
+	}
+	return fmt.Sprintf("  %s: %d deleted, %d failed", path, c.deleted, c.failed)
+}
+
+// cleanGoneAcrossRepositories deletes the branches with a gone upstream in every repository without asking,
+// printing the results of each repository under its path and a summary at the end. A repository that can't
+// be opened is reported and skipped. Fails if a deletion failed, or else if a repository couldn't be cleaned.
+func cleanGoneAcrossRepositories(cmd *cobra.Command, dirs []string, baseline options) error {
+	out := cmd.OutOrStdout()
+	cleanups := make([]repoCleanup, 0, len(dirs))
+	for _, dir := range dirs {
+		fmt.Fprintf(out, "%s:\n", displayPath(dir))
+		cleanup := cleanGoneBranchesIn(cmd, dir, baseline)
+		if cleanup.err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", cleanup.err)
+		}
+		cleanups = append(cleanups, cleanup)
+	}
+	opts = baseline
+
+	fmt.Fprintln(out, "\nSummary:")
+	var failed, total, broken int
+	for _, cleanup := range cleanups {
+		fmt.Fprintln(out, cleanup.summaryLine())
+		failed += cleanup.failed
+		total += cleanup.deleted + cleanup.failed + cleanup.skipped
+		if cleanup.err != nil {
+			broken++
+		}
+	}
+
+	switch {
+	case failed > 0:
+		return deletionFailed("failed to delete %d of %d branch(es)", failed, total)
+	case broken > 0:
+		return fmt.Errorf("failed to clean %d of %d repositories", broken, len(dirs))
+	}
+	return nil
+}
+
+// cleanGoneBranchesIn deletes the branches with a gone upstream in the repository in dir, like
+// gelete --gone --yes run inside it
+func cleanGoneBranchesIn(cmd *cobra.Command, dir string, baseline options) repoCleanup {
+	cleanup := repoCleanup{dir: dir}
+	if cleanup.err = openRepositoryAt(cmd, dir, baseline); cleanup.err != nil {
+		return cleanup
+	}
+	branchInfos, err := listLocalBranches()
+	if err != nil {
+		cleanup.err = err
+		return cleanup
+	}
+	if len(branchInfos) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No branches with a gone upstream.")
+		return cleanup
+	}
+
+	names := make([]string, len(branchInfos))
+	for i, info := range branchInfos {
+		names[i] = info.Name
+	}
+	results, failed := deleteBatchBranches(cmd.OutOrStdout(), cmd.ErrOrStderr(), names)
+	for _, result := range results {
+		switch result.Status {
+		case output.StatusDeleted:
+			cleanup.deleted++
+		case output.StatusSkipped:
+			cleanup.skipped++
+		}
+	}
+	cleanup.failed = failed
+	cleanup.err = compactAfterBatch(cmd.OutOrStdout(), cmd.ErrOrStderr(), cleanup.deleted)
+	return cleanup
+}
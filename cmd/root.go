@@ -1,11 +1,28 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/Kdaito/gelete/internal/audit"
+	"github.com/Kdaito/gelete/internal/cache"
+	"github.com/Kdaito/gelete/internal/config"
 	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/Kdaito/gelete/internal/ignore"
+	"github.com/Kdaito/gelete/internal/log"
+	"github.com/Kdaito/gelete/internal/pr"
+	"github.com/Kdaito/gelete/internal/session"
 	"github.com/Kdaito/gelete/internal/ui"
-	tea "github.com/charmbracelet/bubbletea"
+	"github.com/Kdaito/gelete/pkg/gitops"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
@@ -14,13 +31,45 @@ var (
 	Version = "dev"
 )
 
+// defaultGitTimeout bounds each git command unless --git-timeout is given
+const defaultGitTimeout = 30 * time.Second
+
+// defaultConfirmThreshold is the number of selected branches above which the TUI asks to type the confirmation,
+// unless --confirm-threshold or the confirm_threshold config key is given
+const defaultConfirmThreshold = 10
+
+// rootExamples shows the common flows in the help of the root command
+const rootExamples = `  # Pick the branches to delete in the terminal UI
+  gelete
+
+  # Delete the branches whose upstream was deleted, e.g. after their pull requests were merged
+  gelete --gone --yes
+
+  # Open the terminal UI with the branches matching a glob pattern selected
+  gelete --pattern 'tmp/*'
+
+  # Delete branches given as arguments, force deleting unmerged ones
+  gelete --yes --force old-feature experiment
+
+  # Preview what deleting the branches without commits in 90 days would do
+  gelete --stale 90d --dry-run
+
+  # Remove linked worktrees, then delete the branches they had checked out
+  gelete worktrees`
+
 // rootCmd represents the base command
 var rootCmd = &cobra.Command{
 	Use:     "gelete",
 	Short:   "Interactive git branch deletion tool",
-	Long:    `gelete provides an interactive terminal UI for selecting and deleting local git branches.`,
+	Long:    "gelete provides an interactive terminal UI for selecting and deleting local git branches.\n\n" + exitCodeHelp,
+	Example: rootExamples,
 	Version: Version,
+	Args:    cobra.ArbitraryArgs,
 	RunE:    run,
+
+	// main prints returned errors, and usage is noise for runtime failures
+	SilenceUsage:  true,
+	SilenceErrors: true,
 }
 
 // Execute runs the root command
@@ -28,60 +77,1314 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
+// options holds the values of the command-line flags
+type options struct {
+	dryRun bool
+	remote bool
+	force  bool
+	yes    bool
+	gone   bool
+	json   bool
+
+	// mergedOnly and unmergedOnly limit the branches to those merged or not merged into the base branch
+	mergedOnly   bool
+	unmergedOnly bool
+
+	// mine limits the branches to those whose last commit has user.email in this field: author or committer
+	mine string
+
+	noSession bool
+	noCache   bool
+	noColor   bool
+
+	// theme is the color theme of the TUI: auto, dark or light
+	theme string
+
+	// noIgnore shows and deletes the branches of the .geleteignore files
+	noIgnore bool
+
+	// backupBundle is the path of a git bundle the branches are written to before they are force deleted
+	backupBundle string
+
+	// skipRefCheck skips looking for tags and notes on the own commits of unmerged branches before deletion
+	skipRefCheck bool
+
+	// checkPRs annotates branches with their open pull requests in the TUI
+	checkPRs bool
+
+	// showAuthors adds a column with who started each branch to the TUI
+	showAuthors bool
+
+	// quiet discards all output but errors; verbose logs every git command
+	quiet   bool
+	verbose bool
+
+	// interactive starts the TUI; without a terminal the branches are printed instead (see resolveInteractive)
+	interactive bool
+
+	// sort is the initial branch order given with --sort
+	sort string
+
+	// patterns holds the branch patterns given with --pattern
+	patterns []string
+
+	// base is the ref merge status is computed against, given with --base
+	base string
+
+	// stale is the age given with --stale; branches last committed to before it are selected
+	stale string
+
+	// selectWIP selects the branches whose own commits are all WIP commits
+	selectWIP bool
+
+	// protect holds extra protected branch patterns given with --protect
+	protect   []string
+	noProtect bool
+
+	// expireReflog expires the reflog and runs git gc after deleting, so deleted commits are removed from disk
+	expireReflog bool
+
+	// allowDefault lets the repository's default branch be deleted, which is protected like main and master
+	allowDefault bool
+
+	// gitTimeout bounds each git command; zero disables the limit
+	gitTimeout time.Duration
+
+	// confirmThreshold is the number of selected branches above which the TUI asks to type the confirmation
+	confirmThreshold int
+
+	// allRepos is the directory given with --all-repos, whose repositories gelete works on
+	allRepos string
+
+	// repo is the repository given with --repo; the working directory's repository if empty
+	repo string
+
+	// auditLog is the audit log given with --audit-log; gelete-log.jsonl in the git directory if empty
+	auditLog string
+
+	// logLimit is the number of audit log entries gelete log prints
+	logLimit int
+
+	// pruneRemotes are the remotes gelete remote-prune looks for stale remote-tracking refs in
+	pruneRemotes []string
+
+	// lang is the language given with --lang; GELETE_LANG or the locale of the environment if empty
+	lang string
+
+	// planOutput is the plan file gelete plan writes
+	planOutput string
+
+	// notify rings the bell and shows a desktop notification when a long deletion run in the TUI ends
+	notify bool
+
+	// listFormat is how gelete list prints each branch: names, json or a Go template
+	listFormat string
+
+	// forceStart starts gelete even though a rebase, merge, cherry-pick or bisect is in progress
+	forceStart bool
+}
+
+var opts options
+
+// cfg holds the defaults loaded from the config files (see loadConfig)
+var cfg config.Config
+
+// repo is the repository opened with openRepository
+var repo gitops.Repo
+
+// lang is the language of the messages, selected with configureLanguage
+var lang i18n.Lang
+
+// repoState is the operation in progress in the worktree, found by checkRepositoryState
+var repoState git.State
+
+// userEmail is the email set with git config user.email, read by configureMine
+var userEmail string
+
 // run is the main command execution function
 func run(cmd *cobra.Command, args []string) error {
-	// Validate we're in a git repository
-	if err := git.ValidateRepository(); err != nil {
-		return fmt.Errorf("not a git repository: %w", err)
+	if opts.allRepos != "" {
+		return runAllRepos(cmd, args)
+	}
+	if err := setup(cmd); err != nil {
+		return err
+	}
+
+	if err := checkFlags(args); err != nil {
+		return err
+	}
+	if empty, err := reportEmptyRepository(cmd, args); err != nil || empty {
+		return err
+	}
+	if err := configureRun(cmd); err != nil {
+		return err
 	}
+	return runMode(cmd, args)
+}
+
+// runMode runs the mode selected by the flags and arguments: a pattern, stale or WIP selection,
+// the --gone --yes batch, the branch arguments, the JSON listing, or the TUI over remote or local branches
+func runMode(cmd *cobra.Command, args []string) error {
+	switch {
+	case len(opts.patterns) > 0:
+		return runPattern(cmd, args)
+	case opts.stale != "":
+		return runStale(cmd, args)
+	case opts.selectWIP:
+		return runSelectWIP(cmd, args)
+	case isGoneBatch(args):
+		return runGoneBatch(cmd)
+	case len(args) > 0:
+		return runBranchArgs(cmd, args)
+	case opts.json:
+		return runList(cmd)
+	case opts.remote:
+		return runRemote(cmd)
+	}
+	return runLocal(cmd, nil)
+}
+
+// isGoneBatch reports whether --gone --yes deletes the branches whose upstream is gone without the TUI
+func isGoneBatch(args []string) bool {
+	return opts.gone && opts.yes && len(args) == 0 && !opts.remote
+}
+
+// configureRun sets the base branch and the user's email the branches are compared with,
+// and decides whether the TUI can start
+func configureRun(cmd *cobra.Command) error {
+	if err := configureBase(cmd); err != nil {
+		return err
+	}
+	if !opts.remote {
+		if err := configureMine(); err != nil {
+			return err
+		}
+	}
+	resolveInteractive(cmd)
+	return nil
+}
 
-	// Get list of deletable branches
-	branches, err := git.ListBranches()
+// reportEmptyRepository is reportNoCommits for the runs listing the local branches,
+// that is all but those with branch arguments, --json or --remote
+func reportEmptyRepository(cmd *cobra.Command, args []string) (bool, error) {
+	if len(args) > 0 || opts.json || opts.remote {
+		return false, nil
+	}
+	return reportNoCommits(cmd)
+}
+
+// reportNoCommits tells that there is nothing to delete in a repository without commits, e.g. right after git init,
+// rather than listing no branches as if they were all excluded. Reports whether the repository has no commits.
+func reportNoCommits(cmd *cobra.Command) (bool, error) {
+	hasCommits, err := git.HasCommits()
+	if err != nil || hasCommits {
+		return false, err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), lang.T(i18n.NoCommitsYet))
+	return true, nil
+}
+
+// checkRepositoryState refuses to start while a rebase, merge, cherry-pick or bisect is in progress in the worktree,
+// unless --force-start is given: deleting the branches it works on would leave it in a confusing state
+func checkRepositoryState() error {
+	state, err := git.GetRepositoryState()
 	if err != nil {
-		return fmt.Errorf("failed to list branches: %w", err)
+		return err
+	}
+	repoState = state
+	if state != git.StateClean && !opts.forceStart {
+		return errors.New(lang.T(i18n.OperationInProgress, state))
+	}
+	return nil
+}
+
+// resolveInteractive decides whether the TUI can start: only when stdin and stdout are terminals,
+// unless --interactive says otherwise, e.g. for scripts driving gelete through a pseudo-terminal
+func resolveInteractive(cmd *cobra.Command) {
+	if !cmd.Flags().Changed("interactive") {
+		opts.interactive = isTerminal(os.Stdin) && isTerminal(os.Stdout)
+	}
+}
+
+// setup validates the repository and configures the git package from the config files and flags.
+// Like every command working on the repository, it refuses to go on during a rebase, merge, cherry-pick
+// or bisect (see checkRepositoryState).
+func setup(cmd *cobra.Command) error {
+	if err := setupGit(cmd); err != nil {
+		return err
+	}
+
+	// Validate the repository and run all git commands from its root
+	if err := openRepository(); err != nil {
+		if git.IsInterrupted(err) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", lang.T(i18n.NotARepository), err)
+	}
+	if err := configureRepository(cmd); err != nil {
+		return err
+	}
+	return checkRepositoryState()
+}
+
+// setupGit applies the output and language flags and --git-timeout, and checks that git is recent enough
+func setupGit(cmd *cobra.Command) error {
+	configureOutput(cmd)
+	if err := configureLanguage(); err != nil {
+		return err
+	}
+
+	if opts.gitTimeout < 0 {
+		return fmt.Errorf("invalid --git-timeout '%s': must not be negative", opts.gitTimeout)
+	}
+	git.Timeout = opts.gitTimeout
+
+	// An old git fails in confusing ways later on, so it is refused up front
+	return git.ValidateEnvironment()
+}
+
+// configureLanguage selects the language of the messages from --lang, GELETE_LANG or the environment's locale
+func configureLanguage() error {
+	selected, err := i18n.Resolve(opts.lang, os.Getenv)
+	if err != nil {
+		return err
+	}
+	lang = selected
+	return nil
+}
+
+// configureRepository configures the git package for the opened repository from its config files and the flags
+func configureRepository(cmd *cobra.Command) error {
+	audit.SetPath(opts.auditLog)
+
+	// git runs from the repository root, so the bundle path is resolved against the working directory up front
+	if opts.backupBundle != "" {
+		bundle, err := filepath.Abs(opts.backupBundle)
+		if err != nil {
+			return fmt.Errorf("invalid --backup-bundle '%s': %w", opts.backupBundle, err)
+		}
+		opts.backupBundle = bundle
+	}
+
+	if err := loadConfig(cmd); err != nil {
+		return err
+	}
+	if _, err := ui.ParseTheme(opts.theme); err != nil {
+		return err
+	}
+	if cfg.DateFormat != "" {
+		if _, err := ui.ParseDateFormat(cfg.DateFormat); err != nil {
+			return err
+		}
+	}
+	if err := configureWIP(); err != nil {
+		return err
+	}
+	if opts.confirmThreshold < 0 {
+		return fmt.Errorf("invalid confirm threshold %d: must not be negative", opts.confirmThreshold)
+	}
+
+	if err := configureProtection(); err != nil {
+		return err
+	}
+	return configureIgnore()
+}
+
+// openRepository opens the repository given with --repo, or the one containing the working directory.
+// Git commands that aren't part of the gitops API run from its root as well.
+func openRepository() error {
+	opened, err := gitops.Open(context.Background(), opts.repo)
+	if err != nil {
+		return err
+	}
+	git.UseRepositoryRoot(opened.Root())
+	repo = opened
+	return nil
+}
+
+// checkFlags refuses flag combinations that don't make sense together
+func checkFlags(args []string) error {
+	if err := checkJSONMode(args); err != nil {
+		return err
+	}
+	if err := checkMergeFilter(args); err != nil {
+		return err
+	}
+	if err := checkMineFilter(args); err != nil {
+		return err
+	}
+	if opts.expireReflog && opts.remote {
+		// Deleting remote branches leaves nothing to reclaim locally
+		return errors.New(lang.T(i18n.ExpireReflogWithRemote))
+	}
+	if opts.backupBundle != "" && opts.remote {
+		return errors.New(lang.T(i18n.BackupBundleWithRemote))
+	}
+	if len(args) > 0 && opts.remote {
+		// Arguments are deleted as local branches, which would miss origin/foo or hit a local branch of that name
+		return errors.New(lang.T(i18n.ArgsWithRemote))
+	}
+	return checkQuietMode(args)
+}
+
+// checkJSONMode refuses --json in combinations that would need the TUI or a stdin prompt
+func checkJSONMode(args []string) error {
+	switch {
+	case !opts.json:
+		return nil
+	case opts.remote:
+		return errors.New(lang.T(i18n.JSONWithRemote))
+	case (len(args) > 0 || len(opts.patterns) > 0 || opts.stale != "" || opts.selectWIP) && !opts.yes && !opts.dryRun:
+		return errors.New(lang.T(i18n.JSONNeedsYes))
+	}
+	return nil
+}
+
+// configureOutput applies --quiet, which discards everything but the returned error,
+// and --verbose, which logs every git command with its duration to stderr
+func configureOutput(cmd *cobra.Command) {
+	switch {
+	case opts.quiet:
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+	case opts.verbose:
+		log.SetOutput(cmd.ErrOrStderr())
+	}
+}
+
+// checkQuietMode refuses --quiet where the output is the point of the run or a prompt would be hidden
+func checkQuietMode(args []string) error {
+	switch {
+	case !opts.quiet:
+		return nil
+	case opts.json:
+		return errors.New(lang.T(i18n.QuietWithJSON))
+	case len(args) > 0 && !opts.yes && !opts.dryRun:
+		return errors.New(lang.T(i18n.QuietNeedsYes))
+	}
+	return nil
+}
+
+// checkMergeFilter refuses --merged-only and --unmerged-only where the merge status isn't used
+func checkMergeFilter(args []string) error {
+	if !opts.mergedOnly && !opts.unmergedOnly {
+		return nil
+	}
+	switch {
+	case opts.remote:
+		return errors.New(lang.T(i18n.MergeFilterWithRemote))
+	case len(args) > 0:
+		return errors.New(lang.T(i18n.MergeFilterWithArgs))
+	}
+	return nil
+}
+
+// checkMineFilter refuses --mine where the branches aren't listed from their local metadata
+func checkMineFilter(args []string) error {
+	switch {
+	case opts.mine == "":
+		return nil
+	case opts.remote:
+		return errors.New(lang.T(i18n.MineWithRemote))
+	case len(args) > 0:
+		return errors.New(lang.T(i18n.MineWithArgs))
+	}
+	return nil
+}
+
+// configureMine reads user.email, which the mine filter of --mine and the TUI compares the branches with.
+// --mine fails without it, since no branch could be told to be the user's.
+func configureMine() error {
+	if _, err := mineField(); err != nil {
+		return err
+	}
+
+	email, err := git.UserEmail()
+	if err != nil {
+		return err
+	}
+	if email == "" && opts.mine != "" {
+		return errors.New(lang.T(i18n.MineNeedsEmail))
+	}
+	userEmail = email
+	return nil
+}
+
+// mineField returns the field of the last commit compared with user.email, given with --mine
+func mineField() (ui.MineField, error) {
+	if opts.mine == "" {
+		return ui.MineAuthor, nil
+	}
+	return ui.ParseMineField(opts.mine)
+}
+
+// filterMine keeps the branches whose last commit is the user's when listing with --mine
+func filterMine(branchInfos []git.BranchInfo) []git.BranchInfo {
+	if opts.mine == "" {
+		return branchInfos
+	}
+	field, _ := mineField()
+	return slices.DeleteFunc(slices.Clone(branchInfos), func(info git.BranchInfo) bool {
+		return !field.IsMine(info, userEmail)
+	})
+}
+
+// filterMineBranches is filterMine for branch names
+func filterMineBranches(branches []string) ([]string, error) {
+	if opts.mine == "" {
+		return branches, nil
+	}
+	branchInfos, err := repo.ListBranchesWithInfo(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	mine := make(map[string]bool)
+	for _, info := range filterMine(branchInfos) {
+		mine[info.Name] = true
+	}
+	return slices.DeleteFunc(slices.Clone(branches), func(branch string) bool { return !mine[branch] }), nil
+}
+
+// reportNoMineBranches tells that --mine leaves no branches, naming the email compared
+func reportNoMineBranches(cmd *cobra.Command) {
+	field, _ := mineField()
+	fmt.Fprintln(cmd.OutOrStdout(), lang.T(i18n.NoMineBranches, field.Describe(lang, userEmail)))
+}
+
+// configureBase sets the base branch merge status, divergence and squash merges are computed against:
+// --base, the gelete.base git config or base_branch from the config files, in that order.
+// The base must resolve to a commit; if it doesn't, similarly named branches are suggested.
+func configureBase(cmd *cobra.Command) error {
+	source := "base_branch"
+	if cmd.Flags().Changed("base") {
+		cfg.BaseBranch, source = opts.base, "--base"
+	} else {
+		configured, err := git.ConfiguredBase()
+		if err != nil {
+			return err
+		}
+		if configured != "" {
+			cfg.BaseBranch, source = configured, git.BaseConfigKey
+		}
+	}
+
+	if cfg.BaseBranch == "" {
+		return nil
+	}
+	verifyErr := git.VerifyCommit(cfg.BaseBranch)
+	if verifyErr == nil || git.IsInterrupted(verifyErr) {
+		return verifyErr
+	}
+
+	suggestions, _ := git.SuggestBranches(cfg.BaseBranch)
+	if len(suggestions) == 0 {
+		return fmt.Errorf("invalid %s: %w", source, verifyErr)
+	}
+	return fmt.Errorf("invalid %s: %w (did you mean %s?)", source, verifyErr, quoteList(suggestions))
+}
+
+// quoteList joins names in single quotes with "or", e.g. 'develop' or 'dev'
+func quoteList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + name + "'"
+	}
+	return strings.Join(quoted, " or ")
+}
+
+// mergeFilter returns the merge filter selected with --merged-only or --unmerged-only
+func mergeFilter() ui.MergeFilter {
+	switch {
+	case opts.mergedOnly:
+		return ui.MergeFilterMerged
+	case opts.unmergedOnly:
+		return ui.MergeFilterUnmerged
+	}
+	return ui.MergeFilterAll
+}
+
+// detectMergeStatus is detectMergedBranches for the --merged-only and --unmerged-only filters,
+// which fail instead of silently showing every branch when the merge status is unknown
+func detectMergeStatus() (string, map[string]bool, error) {
+	baseBranch, mergedBranches := detectMergedBranches()
+	if mergedBranches == nil && mergeFilter() != ui.MergeFilterAll {
+		return "", nil, errors.New(lang.T(i18n.MergeFilterNeedsBase))
+	}
+	return baseBranch, mergedBranches, nil
+}
+
+// filterByMergeStatus keeps the branches passing the --merged-only or --unmerged-only filter
+func filterByMergeStatus(branches []string, mergedBranches map[string]bool) []string {
+	return slices.DeleteFunc(slices.Clone(branches), func(branch string) bool {
+		return !passesMergeFilter(branch, mergedBranches)
+	})
+}
+
+// passesMergeFilter reports whether a branch passes the --merged-only or --unmerged-only filter
+func passesMergeFilter(branch string, mergedBranches map[string]bool) bool {
+	switch mergeFilter() {
+	case ui.MergeFilterMerged:
+		return mergedBranches[branch]
+	case ui.MergeFilterUnmerged:
+		return !mergedBranches[branch]
+	}
+	return true
+}
+
+// loadConfig loads the config files and applies them as defaults for the flags that weren't given.
+// Problems that don't prevent loading, like unknown keys, are printed as warnings.
+func loadConfig(cmd *cobra.Command) error {
+	loaded, err := config.Load()
+	if err != nil {
+		return err
+	}
+	for _, warning := range loaded.Warnings {
+		fmt.Fprintln(cmd.ErrOrStderr(), lang.T(i18n.WarningLine, warning))
+	}
+
+	if loaded.Sort != "" && !cmd.Flags().Changed("sort") {
+		opts.sort = loaded.Sort
+	}
+	if loaded.Theme != "" && !cmd.Flags().Changed("theme") {
+		opts.theme = loaded.Theme
+	}
+	if !loaded.ColorEnabled() {
+		opts.noColor = true
+	}
+	if loaded.ConfirmThreshold != nil && !cmd.Flags().Changed("confirm-threshold") {
+		opts.confirmThreshold = *loaded.ConfirmThreshold
+	}
+
+	cfg = loaded
+	return nil
+}
+
+// configureProtection sets the protected branch patterns from the defaults,
+// the gelete.protect git config key, the config files and the --protect flags
+func configureProtection() error {
+	if opts.noProtect {
+		git.SetProtectedPatterns(nil)
+		return nil
+	}
+
+	configured, err := git.ConfiguredProtectedPatterns()
+	if err != nil {
+		return fmt.Errorf("failed to read protected branches: %w", err)
+	}
+
+	patterns := slices.Concat(builtinProtectedBranches(), configured, cfg.Protect, opts.protect)
+	git.SetProtectedPatterns(patterns)
+	return nil
+}
+
+// configureIgnore hides the branches of the .geleteignore files, unless --no-ignore is set
+func configureIgnore() error {
+	if opts.noIgnore {
+		git.SetIgnoreRules(nil)
+		return nil
+	}
+
+	rules, err := ignore.Load()
+	if err != nil {
+		return err
+	}
+	git.SetIgnoreRules(rules)
+	return nil
+}
+
+// builtinProtectedBranches returns the branches protected without configuration: main, master, develop
+// and the repository's default branch, if it has another name. --allow-default lifts the protection
+// of the default branch, whatever its name.
+func builtinProtectedBranches() []string {
+	builtin := slices.Clone(git.DefaultProtectedBranches)
+	defaultBranch := detectDefaultBranch()
+	switch {
+	case defaultBranch == "":
+	case opts.allowDefault:
+		builtin = slices.DeleteFunc(builtin, func(branch string) bool { return branch == defaultBranch })
+	case !slices.Contains(builtin, defaultBranch):
+		builtin = append(builtin, defaultBranch)
+	}
+	return builtin
+}
+
+// detectDefaultBranch returns the local name of the repository's default branch, or "" if it is unknown
+func detectDefaultBranch() string {
+	branch, err := git.GetDefaultBranch()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(branch, "origin/")
+}
+
+// runLocal runs the TUI over local branches with the given branches pre-selected
+func runLocal(cmd *cobra.Command, preselected []string) error {
+	if _, err := ui.ParseSortMode(opts.sort); err != nil {
+		return err
+	}
+
+	branchInfos, err := listLocalBranches()
+	if err != nil {
+		return err
+	}
+	if opts.gone && len(branchInfos) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), lang.T(i18n.NoGoneBranches))
+		return nil
 	}
 
 	// Check if there are any branches to delete
-	if len(branches) == 0 {
-		fmt.Println("No branches to delete.")
-		fmt.Println("(Current branch is excluded from the list)")
+	if len(branchInfos) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), lang.T(i18n.NoBranchesToDelete))
+		fmt.Fprintln(cmd.OutOrStdout(), lang.T(i18n.CurrentBranchExcluded))
 		return nil
 	}
+	// The TUI gets every branch, so that the o key can show the others too
+	mine := filterMine(branchInfos)
+	if len(mine) == 0 {
+		reportNoMineBranches(cmd)
+		return nil
+	}
+
+	if !opts.interactive {
+		return runPlainList(cmd, plainListBranches(mine, preselected))
+	}
+	model, err := localModel(branchInfos, preselected)
+	if err != nil {
+		return err
+	}
+	return runUI(cmd, model)
+}
+
+// localModel returns the TUI over the listed local branches with the given branches pre-selected.
+// The metadata of the branches is gathered behind a spinner.
+func localModel(branchInfos []git.BranchInfo, preselected []string) (ui.AppModel, error) {
+	sortMode, err := ui.ParseSortMode(opts.sort)
+	if err != nil {
+		return ui.AppModel{}, err
+	}
+	keys, err := keyMap()
+	if err != nil {
+		return ui.AppModel{}, err
+	}
+	field, err := mineField()
+	if err != nil {
+		return ui.AppModel{}, err
+	}
+
+	return ui.NewLoadingModel(ui.AppModel{
+		Repo:             repo,
+		RepoPath:         displayPath(repo.Root()),
+		SuperprojectPath: superprojectPath(),
+		RepositoryState:  repoState,
+		Loader:           branchLoader(branchInfos, preselected),
+		UnmergedBranches: make(map[string]string),
+		PersistSession:   !opts.noSession,
+		Styles:           styles(),
+		Lang:             lang,
+		SortMode:         sortMode,
+		DateFormat:       dateFormat(),
+		MergeFilter:      mergeFilter(),
+		OnlyMine:         opts.mine != "",
+		MineField:        field,
+		UserEmail:        userEmail,
+		DryRun:           opts.dryRun,
+		ForceMode:        opts.force,
+		ConfirmThreshold: opts.confirmThreshold,
+		ExpireReflog:     opts.expireReflog,
+		PRProvider:       prProvider(),
+		ShowAuthors:      opts.showAuthors,
+		WIPPatterns:      wipPatterns,
+		SelectWIP:        opts.selectWIP,
+		SkipRefCheck:     opts.skipRefCheck,
+		BackupBundle:     opts.backupBundle,
+		Clipboard:        terminalClipboard(),
+		Notifier:         terminalNotifier(),
+		Keys:             keys,
+		AuditLog:         true,
+	}), nil
+}
 
-	// Get list of worktrees (FR-010)
-	worktrees, err := git.ListWorktrees()
+// branchLoader returns the loader of the TUI. The first call gathers the metadata of the branches already
+// listed; later calls, refreshing the list with the r key, list the branches again.
+func branchLoader(branchInfos []git.BranchInfo, preselected []string) ui.BranchLoader {
+	listed := true
+	return func() (ui.BranchData, error) {
+		if !listed {
+			relisted, err := listLocalBranches()
+			if err != nil {
+				return ui.BranchData{}, err
+			}
+			branchInfos = relisted
+		}
+		listed = false
+		return loadBranchData(branchInfos, preselected)
+	}
+}
+
+// plainListBranches returns the branches printed instead of the TUI: the pre-selected ones if any,
+// e.g. those matching --pattern, or else all the listed branches
+func plainListBranches(branchInfos []git.BranchInfo, preselected []string) []string {
+	if len(preselected) > 0 {
+		return preselected
+	}
+	branches, _ := indexBranches(branchInfos)
+	return branches
+}
+
+// loadBranchData gathers the metadata of the listed branches the TUI shows: worktrees, merge status,
+// squash merges and divergence from the base branch
+func loadBranchData(branchInfos []git.BranchInfo, preselected []string) (ui.BranchData, error) {
+	branches, branchDetails := indexBranches(branchInfos)
+
+	branchWorktrees, staleWorktrees, err := listBranchWorktrees()
 	if err != nil {
-		return fmt.Errorf("failed to list worktrees: %w", err)
+		return ui.BranchData{}, err
+	}
+
+	current, detached, err := git.GetHeadBranch()
+	if err != nil {
+		return ui.BranchData{}, err
+	}
+
+	// Classify branches as merged/unmerged against the default branch.
+	// This is informational only, so failures just hide the annotations,
+	// unless the list is filtered by merge status.
+	baseBranch, mergedBranches, err := detectMergeStatus()
+	if err != nil {
+		return ui.BranchData{}, err
+	}
+
+	squashMerged, divergence := analyzeBranches(branchInfos, baseBranch, mergedBranches)
+	return ui.BranchData{
+		CurrentBranch:   current,
+		DetachedHead:    detached,
+		Branches:        branches,
+		Selected:        initialSelection(branchInfos, preselected),
+		BranchDetails:   branchDetails,
+		BranchWorktrees: branchWorktrees,
+		StaleWorktrees:  staleWorktrees,
+		Upstreams:       upstreamsOf(branchInfos),
+		BaseBranch:      baseBranch,
+		MergedBranches:  mergedBranches,
+		SquashMerged:    squashMerged,
+		Divergence:      divergence,
+		DefaultBranch:   detectDefaultBranch(),
+	}, nil
+}
+
+// indexBranches returns the names of the listed branches in order, and their metadata by name
+func indexBranches(branchInfos []git.BranchInfo) ([]string, map[string]git.BranchInfo) {
+	branches := make([]string, 0, len(branchInfos))
+	branchDetails := make(map[string]git.BranchInfo, len(branchInfos))
+	for _, info := range branchInfos {
+		branches = append(branches, info.Name)
+		branchDetails[info.Name] = info
+	}
+	return branches, branchDetails
+}
+
+// initialSelection returns the selection the TUI starts with.
+// Branches whose upstream is gone are almost always safe to delete, so --gone pre-selects them all,
+// and the auto_select_gone config option pre-selects them among the other branches.
+func initialSelection(branchInfos []git.BranchInfo, preselected []string) map[string]bool {
+	selected := make(map[string]bool)
+	for _, branch := range preselected {
+		selected[branch] = true
+	}
+	for _, info := range branchInfos {
+		if opts.gone || (cfg.SelectGone() && info.Gone) {
+			selected[info.Name] = true
+		}
+	}
+
+	// Restore the selection of a previous session that was quit before deleting.
+	// The session is a convenience, so a broken session file is ignored.
+	if !opts.noSession {
+		saved, _ := session.Load()
+		for _, info := range branchInfos {
+			if saved[info.Name] {
+				selected[info.Name] = true
+			}
+		}
+	}
+	return selected
+}
+
+// listLocalBranches returns the deletable branches with their last commit metadata,
+// narrowed to branches with a gone upstream when --gone is set
+func listLocalBranches() ([]git.BranchInfo, error) {
+	branchInfos, err := repo.ListBranchesWithInfo(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	// Drop the branch checked out in the main worktree when running in a linked one
+	deletable, err := listDeletableBranches()
+	if err != nil {
+		return nil, err
+	}
+	keep := make(map[string]bool, len(deletable))
+	for _, branch := range deletable {
+		keep[branch] = true
+	}
+	branchInfos = slices.DeleteFunc(branchInfos, func(info git.BranchInfo) bool { return !keep[info.Name] })
+
+	if opts.gone {
+		return filterGoneBranches(branchInfos), nil
+	}
+	return branchInfos, nil
+}
+
+// listDeletableBranches returns the names of the branches that can be deleted: the branches of
+// ListBranches except the one checked out in the main worktree, which is in use when gelete runs
+// in a linked worktree although it isn't the current branch
+func listDeletableBranches() ([]string, error) {
+	deletable, err := repo.ListDeletableBranches(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	names := make([]string, 0, len(deletable))
+	for _, branch := range deletable {
+		names = append(names, branch.Name)
+	}
+	return names, nil
+}
+
+// listBranchWorktrees returns the worktree path of each branch checked out in a worktree (FR-010),
+// and separately the branches whose worktree is prunable, e.g. because its directory no longer exists.
+// Stale worktrees are pruned rather than removed, so they don't need the worktree removal prompt.
+func listBranchWorktrees() (map[string]string, map[string]bool, error) {
+	worktrees, err := repo.ListWorktrees(context.Background())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
 	// Build branch -> worktree path mapping
 	branchWorktrees := make(map[string]string)
+	staleWorktrees := make(map[string]bool)
 	for _, wt := range worktrees {
-		if wt.Branch != "" {
+		switch {
+		case wt.Branch == "":
+		case wt.Prunable:
+			staleWorktrees[wt.Branch] = true
+		default:
 			branchWorktrees[wt.Branch] = wt.Path
 		}
 	}
+	return branchWorktrees, staleWorktrees, nil
+}
+
+// filterGoneBranches keeps only the branches whose upstream tracking branch no longer exists
+func filterGoneBranches(branchInfos []git.BranchInfo) []git.BranchInfo {
+	var filtered []git.BranchInfo
+	for _, info := range branchInfos {
+		if info.Gone {
+			filtered = append(filtered, info)
+		}
+	}
+	return filtered
+}
+
+// detectMergedBranches returns the base branch and the set of branches merged into it.
+// The base branch is the one set with configureBase, or the detected default branch.
+// Returns empty values if the base branch cannot be determined.
+func detectMergedBranches() (string, map[string]bool) {
+	baseBranch := cfg.BaseBranch
+	if baseBranch == "" {
+		detected, err := git.GetDefaultBranch()
+		if err != nil {
+			return "", nil
+		}
+		baseBranch = detected
+	}
+
+	mergedBranches, err := git.GetMergedBranches(baseBranch)
+	if err != nil {
+		return "", nil
+	}
+
+	return baseBranch, mergedBranches
+}
+
+// computeDivergence returns the ahead/behind counts of each branch relative to the base branch.
+// Returns nil (hiding the counts) when the base branch doesn't resolve to a commit.
+func computeDivergence(branches []string, baseBranch string) map[string]git.AheadBehind {
+	if baseBranch == "" || git.VerifyCommit(baseBranch) != nil {
+		return nil
+	}
+	return git.GetAheadBehindCounts(branches, baseBranch)
+}
+
+// analyzeBranches returns the squash-merged branches and the ahead/behind counts of each branch relative
+// to the base branch. Unless --no-cache is given, the results of branches whose tip and base didn't move
+// since a previous run are read from the cache, and only the other branches are analyzed.
+func analyzeBranches(branchInfos []git.BranchInfo, baseBranch string, mergedBranches map[string]bool) (map[string]bool, map[string]git.AheadBehind) {
+	branches, _ := indexBranches(branchInfos)
+	if opts.noCache || baseBranch == "" {
+		return detectSquashMerged(branches, baseBranch, mergedBranches), computeDivergence(branches, baseBranch)
+	}
+	baseSHA, err := git.ResolveCommit(baseBranch)
+	if err != nil {
+		return detectSquashMerged(branches, baseBranch, mergedBranches), nil
+	}
+
+	analysisCache := cache.Load()
+	results := analysisCache.Analyze(branchInfos, baseSHA, func(misses []string) map[string]cache.Result {
+		squashMerged := detectSquashMerged(misses, baseBranch, mergedBranches)
+		computed := make(map[string]cache.Result)
+		for branch, counts := range git.GetAheadBehindCounts(misses, baseBranch) {
+			computed[branch] = cache.Result{SquashMerged: squashMerged[branch], Divergence: counts}
+		}
+		return computed
+	})
+	// The cache is an optimization, so failing to save it just means analyzing again next time
+	_ = analysisCache.Save()
+
+	squashMerged := make(map[string]bool)
+	divergence := make(map[string]git.AheadBehind, len(results))
+	for branch, result := range results {
+		if result.SquashMerged {
+			squashMerged[branch] = true
+		}
+		divergence[branch] = result.Divergence
+	}
+	return squashMerged, divergence
+}
+
+// detectSquashMerged returns the unmerged branches whose content appears merged into the base branch,
+// e.g. with "squash and merge". Like the merge status this is informational, so branches that can't be
+// checked are left out.
+func detectSquashMerged(branches []string, baseBranch string, mergedBranches map[string]bool) map[string]bool {
+	if mergedBranches == nil {
+		return nil
+	}
+
+	var unmerged []string
+	for _, branch := range branches {
+		if !mergedBranches[branch] {
+			unmerged = append(unmerged, branch)
+		}
+	}
+	return git.ListSquashMerged(unmerged, baseBranch)
+}
+
+// upstreamsOf returns the upstream of each listed branch that tracks one, offered for deletion alongside it
+func upstreamsOf(branchInfos []git.BranchInfo) map[string]git.Upstream {
+	upstreams := make(map[string]git.Upstream)
+	for _, info := range branchInfos {
+		if info.Upstream.Remote != "" {
+			upstreams[info.Name] = info.Upstream
+		}
+	}
+	return upstreams
+}
+
+// runRemote runs the TUI over remote-tracking branches instead of local branches
+func runRemote(cmd *cobra.Command) error {
+	branches, err := git.ListRemoteBranches()
+	if err != nil {
+		return fmt.Errorf("failed to list remote branches: %w", err)
+	}
+
+	if len(branches) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), lang.T(i18n.NoRemoteBranches))
+		return nil
+	}
+
+	if !opts.interactive {
+		return runPlainList(cmd, branches)
+	}
+
+	current, _, err := git.GetHeadBranch()
+	if err != nil {
+		return err
+	}
+	keys, err := keyMap()
+	if err != nil {
+		return err
+	}
 
-	// Initialize the UI model
 	model := ui.AppModel{
+		RepoPath:         displayPath(repo.Root()),
+		SuperprojectPath: superprojectPath(),
+		RepositoryState:  repoState,
+		CurrentBranch:    current,
 		Branches:         branches,
 		Selected:         make(map[string]bool),
-		CursorIndex:      0,
 		State:            ui.StateSelection,
-		FailedBranches:   make(map[string]string),
 		UnmergedBranches: make(map[string]string),
-		BranchWorktrees:  branchWorktrees,
+		Remote:           true,
+		Loader:           remoteBranchLoader(current),
+		Styles:           styles(),
+		Lang:             lang,
+		DryRun:           opts.dryRun,
+		ConfirmThreshold: opts.confirmThreshold,
+		PRProvider:       prProvider(),
+		Clipboard:        terminalClipboard(),
+		Notifier:         terminalNotifier(),
+		Keys:             keys,
+		AuditLog:         true,
+	}
+
+	return runUI(cmd, model)
+}
+
+// remoteBranchLoader returns the loader listing the remote-tracking branches again when the TUI is refreshed
+func remoteBranchLoader(current string) ui.BranchLoader {
+	return func() (ui.BranchData, error) {
+		branches, err := git.ListRemoteBranches()
+		if err != nil {
+			return ui.BranchData{}, fmt.Errorf("failed to list remote branches: %w", err)
+		}
+		return ui.BranchData{CurrentBranch: current, Branches: branches}, nil
 	}
+}
 
-	// Start the bubbletea program
-	p := tea.NewProgram(model)
-	if _, err := p.Run(); err != nil {
-		return fmt.Errorf("error running UI: %w", err)
+// keyMap returns the keys of the TUI with the keys section of the config files applied
+func keyMap() (*ui.KeyMap, error) {
+	keys, err := ui.NewKeyMap(cfg.KeyBindings())
+	if err != nil {
+		return nil, fmt.Errorf("invalid keys in config: %w", err)
 	}
+	return &keys, nil
+}
 
+// prProvider returns the provider looking up open pull requests with --check-prs, nil otherwise
+func prProvider() pr.Provider {
+	if !opts.checkPRs {
+		return nil
+	}
+	return pr.GitHub{Dir: repo.Root()}
+}
+
+// superprojectPath returns the superproject of the repository for the TUI header when it is a submodule.
+// The header is informational, so a failure just leaves it out.
+func superprojectPath() string {
+	superproject, err := git.GetSuperproject()
+	if err != nil || superproject == "" {
+		return ""
+	}
+	return displayPath(superproject)
+}
+
+// displayPath abbreviates the home directory at the start of path to ~ for the TUI header
+func displayPath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(home, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.Join("~", rel)
+}
+
+// colorEnabled reports whether the UI may use colors: not with --no-color,
+// when the NO_COLOR environment variable is set (https://no-color.org), or when stdout is not a terminal
+func colorEnabled() bool {
+	if opts.noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	return stdoutIsTerminal()
+}
+
+// dateFormat returns how the TUI shows commit dates at first, from the date_format config key
+func dateFormat() ui.DateFormat {
+	// The format is validated by setup
+	format, _ := ui.ParseDateFormat(cfg.DateFormat)
+	return format
+}
+
+// styles returns the TUI styles in the --theme colors, plain if colors are disabled
+func styles() ui.Styles {
+	// The theme is validated by setup
+	theme, _ := ui.ParseTheme(opts.theme)
+	return ui.NewStyles(theme, colorEnabled())
+}
+
+// stdoutIsTerminal reports whether stdout is a terminal rather than a pipe or a file
+func stdoutIsTerminal() bool {
+	return isTerminal(os.Stdout)
+}
+
+// isTerminal reports whether the file is a terminal, including the Cygwin and MSYS terminals on Windows.
+// Unlike checking for a character device, it isn't fooled by /dev/null.
+func isTerminal(file *os.File) bool {
+	return isatty.IsTerminal(file.Fd()) || isatty.IsCygwinTerminal(file.Fd())
+}
+
+// terminalClipboard returns the clipboard branch names are copied to with the y key,
+// or nil when stdout isn't a terminal, which disables copying
+func terminalClipboard() ui.Clipboard {
+	if !stdoutIsTerminal() {
+		return nil
+	}
+	return ui.NewTerminalClipboard(os.Stdout, os.Getenv)
+}
+
+// terminalNotifier returns the notifier of the end of long deletion runs: the terminal bell and a desktop
+// notification with --notify, unless escape sequences are disabled like colors, e.g. when stdout isn't a terminal
+func terminalNotifier() ui.Notifier {
+	if !opts.notify || !colorEnabled() {
+		return ui.NoNotifier{}
+	}
+	return ui.NewTerminalNotifier(os.Stdout, os.Getenv)
+}
+
+// runUI starts the bubbletea program with the given model.
+// Returns an error, and so a non-zero exit code, if any deletion failed;
+// branches that were deleted or intentionally skipped don't count as failures.
+func runUI(cmd *cobra.Command, model ui.AppModel) error {
+	closeLog, err := logToFile(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+
+	result, err := runAppProgram(model)
+	if err != nil {
+		return err
+	}
+	return appResult(result)
+}
+
+// runAppProgram runs the TUI of model until it quits and returns its final state
+func runAppProgram(model ui.AppModel) (ui.AppModel, error) {
+	final, err := runProgram(model)
+	if err != nil {
+		return ui.AppModel{}, fmt.Errorf("error running UI: %w", err)
+	}
+	result, _ := final.(ui.AppModel)
+	return result, nil
+}
+
+// appResult returns the error, and so the exit code, of a TUI run that quit in the given state
+func appResult(result ui.AppModel) error {
+	switch {
+	case result.Interrupted != nil:
+		return interrupted(result.Interrupted)
+	case result.LoadErr != nil:
+		return loadFailed(result.LoadErr)
+	case result.HasFailures():
+		return deletionFailed("failed to delete %d of %d branch(es)", result.FailedCount(), len(result.Results))
+	case result.WasCancelled():
+		return cancelled("cancelled with branches selected for deletion")
+	}
 	return nil
 }
 
+// logToFile sends the --verbose log to a file while the TUI occupies the terminal.
+// The returned function restores logging to stderr and tells where the log was written.
+func logToFile(cmd *cobra.Command) (func(), error) {
+	if !opts.verbose {
+		return func() {}, nil
+	}
+
+	file, err := os.CreateTemp("", "gelete-*.log")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create verbose log file: %w", err)
+	}
+	log.SetOutput(file)
+
+	return func() {
+		log.SetOutput(cmd.ErrOrStderr())
+		_ = file.Close()
+		fmt.Fprintln(cmd.ErrOrStderr(), lang.T(i18n.VerboseLogWritten, file.Name()))
+	}, nil
+}
+
 func init() {
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(worktreesCmd)
+	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(logCmd)
+	rootCmd.AddCommand(themesCmd)
+	rootCmd.AddCommand(remotePruneCmd)
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(docsCmd)
+	docsCmd.AddCommand(docsManCmd)
+	docsCmd.AddCommand(docsMarkdownCmd)
+	rootCmd.ValidArgsFunction = completeBranches
+
+	rootCmd.PersistentFlags().StringVar(&opts.repo, "repo", "", "Run on the repository at this path instead of the one containing the working directory")
+	rootCmd.PersistentFlags().BoolVarP(&opts.quiet, "quiet", "q", false, "Print nothing but errors (ignored by the TUI)")
+	rootCmd.PersistentFlags().BoolVar(&opts.verbose, "verbose", false, "Print every git command with its duration to stderr; the TUI writes them to a log file instead")
+	rootCmd.PersistentFlags().StringVar(&opts.lang, "lang", "", "Language of the messages: en or ja (default: GELETE_LANG, or else the LC_ALL, LC_MESSAGES or LANG locale)")
+	rootCmd.PersistentFlags().StringVar(&opts.auditLog, "audit-log", "", "Record deletions in this file instead of gelete-log.jsonl in the repository's git directory")
+	rootCmd.PersistentFlags().BoolVar(&opts.forceStart, "force-start", false, "Start even though a rebase, merge, cherry-pick or bisect is in progress, which gelete otherwise refuses")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
+
 	rootCmd.Flags().BoolP("version", "v", false, "Print version information")
+	rootCmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Preview deletions without deleting any branches")
+	rootCmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Force delete unmerged branches (git branch -D) without a second confirmation")
+	rootCmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Delete branches given as arguments, or those selected by --gone, --pattern, --stale or --select-wip, without asking for confirmation")
+	rootCmd.Flags().BoolVar(&opts.interactive, "interactive", false, "Start the TUI (default: only when stdin and stdout are terminals; otherwise the branches are printed one per line)")
+	rootCmd.Flags().BoolVar(&opts.json, "json", false, "Print results as JSON: lists deletable branches, or reports each deletion when branches are given as arguments")
+	rootCmd.Flags().BoolVarP(&opts.gone, "gone", "g", false, "Only list branches whose upstream branch was deleted, pre-selected for deletion; deletes them directly with --yes")
+	rootCmd.Flags().BoolVar(&opts.mergedOnly, "merged-only", false, "Only list branches fully merged into the base branch (toggle with m in the TUI)")
+	rootCmd.Flags().BoolVar(&opts.unmergedOnly, "unmerged-only", false, "Only list branches with commits not merged into the base branch (toggle with m in the TUI)")
+	rootCmd.Flags().StringVar(&opts.mine, "mine", "", "Only list branches whose last commit has your git config user.email as author, or as committer with --mine=committer (toggle with o in the TUI)")
+	rootCmd.Flags().StringArrayVarP(&opts.patterns, "pattern", "p", nil, "Select branches matching a glob pattern (e.g. 'tmp/*'); deletes them directly with --yes; can be repeated")
+	rootCmd.Flags().StringVar(&opts.base, "base", "", "Compute merge status, ahead/behind counts and squash merges against this ref instead of the default branch (also set with git config gelete.base)")
+	rootCmd.Flags().StringVar(&opts.stale, "stale", "", "Select branches without commits in this long (e.g. 90d, 6m, 1y, 36h); deletes them directly with --yes")
+	rootCmd.Flags().BoolVar(&opts.selectWIP, "select-wip", false, "Select branches whose own commits all look like work in progress (subjects matching wip_patterns, by default wip, tmp, fixup! or squash!); deletes them directly with --yes (select them with w in the TUI)")
+	rootCmd.Flags().StringVar(&opts.sort, "sort", "name", "Branch order: name, age (oldest first) or -age (newest first)")
+	rootCmd.Flags().StringVar(&opts.theme, "theme", "auto", "Colors of the TUI: dark, light or auto (picked from the terminal background)")
+	rootCmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable colored output (also disabled by the NO_COLOR environment variable)")
+	rootCmd.Flags().BoolVar(&opts.checkPRs, "check-prs", false, "Annotate branches with their open GitHub pull requests in the TUI (needs the gh CLI)")
+	rootCmd.Flags().BoolVar(&opts.showAuthors, "show-authors", false, "Show who started each branch (the author of its first commit not on the base branch) in a column of the TUI; local branches only")
+	rootCmd.Flags().StringVar(&opts.backupBundle, "backup-bundle", "", "Write the branches to this git bundle before force deleting them, so they can be fetched back from it")
+	rootCmd.Flags().BoolVar(&opts.skipRefCheck, "skip-ref-check", false, "Don't look for tags and notes on the commits only an unmerged branch has before deleting it, for speed in huge repositories")
+	rootCmd.Flags().BoolVar(&opts.noSession, "no-session", false, "Don't restore or save the branch selection of interrupted sessions")
+	rootCmd.Flags().BoolVar(&opts.noCache, "no-cache", false, "Analyze every branch again instead of reusing the results cached by previous runs")
+	rootCmd.Flags().StringArrayVar(&opts.protect, "protect", nil, "Protect branches matching a pattern (e.g. 'release/*') from deletion; can be repeated")
+	rootCmd.Flags().BoolVar(&opts.noProtect, "no-protect", false, "Disable branch protection, allowing main, master, develop and configured patterns to be deleted")
+	rootCmd.Flags().BoolVar(&opts.noIgnore, "no-ignore", false, "Don't hide the branches matching the .geleteignore files")
+	rootCmd.Flags().BoolVar(&opts.allowDefault, "allow-default", false, "Allow deleting the repository's default branch (e.g. the branch origin/HEAD points to), with an extra warning")
+	rootCmd.Flags().BoolVar(&opts.expireReflog, "expire-reflog", false, "After deleting, expire the reflog and run git gc --prune=now to reclaim the space of deleted branches; their commits become unrecoverable")
+	rootCmd.Flags().IntVar(&opts.confirmThreshold, "confirm-threshold", defaultConfirmThreshold, "Ask to type 'delete' or the number of branches to confirm deleting more than this many branches in the TUI; 0 disables it (force deletions always ask)")
+	rootCmd.Flags().DurationVar(&opts.gitTimeout, "git-timeout", defaultGitTimeout, "Abort git commands that run longer than this (e.g. 10s, 2m); 0 disables the limit")
+	rootCmd.Flags().StringVar(&opts.allRepos, "all-repos", "", "Work on every git repository directly inside this directory: pick one in the TUI, list them with their branch counts, or delete their gone branches with --gone --yes")
+	rootCmd.Flags().BoolVarP(&opts.remote, "remote", "r", false, "Delete remote-tracking branches from their remote instead of local branches")
+	rootCmd.Flags().BoolVar(&opts.notify, "notify", false, "Ring the bell and show a desktop notification (OSC 9 or OSC 777) when deleting in the TUI takes longer than 5 seconds")
+
+	rootCmd.Flags().Lookup("mine").NoOptDefVal = "author"
+	rootCmd.MarkFlagsMutuallyExclusive("merged-only", "unmerged-only")
+	rootCmd.MarkFlagsMutuallyExclusive("pattern", "stale", "select-wip")
+	rootCmd.MarkFlagsMutuallyExclusive("force", "remote")
+
+	// The flag is registered above, so this can't fail
+	_ = rootCmd.RegisterFlagCompletionFunc("pattern", completePatterns)
+
+	pruneCmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Prune and delete without asking for confirmation")
+	pruneCmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print what would be pruned and deleted without changing anything")
+	pruneCmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Force delete gone branches that aren't merged, e.g. after a squash merge (git branch -D)")
+	pruneCmd.Flags().StringVar(&opts.base, "base", "", "Find merged branches against this ref instead of the default branch")
+
+	listCmd.Flags().StringVar(&opts.listFormat, "format", "names", "Print the branches as names (one per line), json, or with a Go template like '{{.Name}}\\t{{.LastCommitDate}}'")
+	listCmd.Flags().StringVar(&opts.base, "base", "", "Compute merge status and ahead/behind counts against this ref instead of the default branch")
+	listCmd.Flags().StringVar(&opts.mine, "mine", "", "Only list branches whose last commit has your git config user.email as author, or as committer with --mine=committer")
+	listCmd.Flags().Lookup("mine").NoOptDefVal = "author"
+
+	worktreesCmd.Flags().StringVar(&opts.base, "base", "", "Tell whether freed branches are merged against this ref instead of the default branch")
+
+	remotePruneCmd.Flags().StringArrayVar(&opts.pruneRemotes, "remote", []string{"origin"}, "Look for stale remote-tracking refs of this remote; can be repeated")
+	remotePruneCmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Prune all stale remote-tracking refs without the TUI")
+	remotePruneCmd.Flags().BoolVar(&opts.interactive, "interactive", false, "Start the TUI (default: only when stdin and stdout are terminals; otherwise the refs are printed one per line)")
+
+	planCmd.Flags().StringVarP(&opts.planOutput, "output", "o", "", "Write the plan to this file (required)")
+	planCmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Plan force deletions (git branch -D) of the selected branches")
+	planCmd.Flags().BoolVarP(&opts.gone, "gone", "g", false, "Only list branches whose upstream branch was deleted, pre-selected for planning")
+	planCmd.Flags().StringVar(&opts.base, "base", "", "Compute merge status against this ref instead of the default branch")
+	planCmd.Flags().StringVar(&opts.mine, "mine", "", "Only list branches whose last commit has your git config user.email as author, or as committer with --mine=committer")
+	planCmd.Flags().Lookup("mine").NoOptDefVal = "author"
+	_ = planCmd.MarkFlagRequired("output")
+
+	logCmd.Flags().IntVarP(&opts.logLimit, "limit", "n", 20, "Number of most recent deletions to show; 0 shows all of them")
 }
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Kdaito/gelete/internal/ui"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runProgram runs a TUI until it quits. SIGINT and SIGTERM are sent to the model as a ui.InterruptMsg,
+// so it stops git and quits through bubbletea, which restores the terminal (cursor, raw mode) on the way out;
+// panics are caught and restore it too. A second signal kills the program if the model doesn't quit.
+func runProgram(model tea.Model) (tea.Model, error) {
+	p := tea.NewProgram(model, tea.WithoutSignalHandler())
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	done := make(chan struct{})
+	defer close(done)
+	go forwardSignals(p, signals, done)
+
+	return p.Run()
+}
+
+// forwardSignals sends the first signal to the program as a ui.InterruptMsg and kills it on the second one
+func forwardSignals(p *tea.Program, signals <-chan os.Signal, done <-chan struct{}) {
+	select {
+	case sig := <-signals:
+		p.Send(ui.InterruptMsg{Signal: sig})
+	case <-done:
+		return
+	}
+
+	select {
+	case <-signals:
+		p.Kill()
+	case <-done:
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/config"
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// runStale selects the branches whose last commit is older than --stale. With --yes (or --json)
+// they are deleted without the TUI; otherwise the TUI opens with them pre-selected.
+func runStale(cmd *cobra.Command, args []string) error {
+	switch {
+	case len(args) > 0:
+		return fmt.Errorf("branch arguments cannot be combined with --stale")
+	case opts.remote:
+		return fmt.Errorf("--stale cannot be combined with --remote")
+	}
+
+	stale, err := listStaleBranches()
+	if err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No branches without commits in the last %s.\n", opts.stale)
+		return nil
+	}
+
+	branches, _ := indexBranches(stale)
+	if !opts.yes && !opts.json {
+		return runLocal(cmd, branches)
+	}
+
+	if !opts.json {
+		printStaleBranches(cmd, stale)
+	}
+	return runBatch(cmd, branches)
+}
+
+// listStaleBranches returns the local branches older than --stale that pass the merge filter and --mine
+func listStaleBranches() ([]git.BranchInfo, error) {
+	age, err := config.ParseAge(opts.stale)
+	if err != nil {
+		return nil, err
+	}
+
+	branchInfos, err := listLocalBranches()
+	if err != nil {
+		return nil, err
+	}
+
+	_, mergedBranches, err := detectMergeStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []git.BranchInfo
+	for _, info := range staleBranches(filterMine(branchInfos), time.Now().Add(-age)) {
+		if passesMergeFilter(info.Name, mergedBranches) {
+			stale = append(stale, info)
+		}
+	}
+	return stale, nil
+}
+
+// staleBranches returns the branches last committed to before cutoff.
+// Branches without a commit date are treated as infinitely old.
+func staleBranches(branchInfos []git.BranchInfo, cutoff time.Time) []git.BranchInfo {
+	var stale []git.BranchInfo
+	for _, info := range branchInfos {
+		if info.LastCommitDate.IsZero() || info.LastCommitDate.Before(cutoff) {
+			stale = append(stale, info)
+		}
+	}
+	return stale
+}
+
+// printStaleBranches lists the stale branches with the age of their last commit before they are deleted
+func printStaleBranches(cmd *cobra.Command, stale []git.BranchInfo) {
+	fmt.Fprintf(cmd.OutOrStdout(), "Branches without commits in the last %s:\n", opts.stale)
+	for _, info := range stale {
+		age := ui.HumanizeDuration(i18n.English, info.LastCommitDate, time.Now())
+		if info.LastCommitDate.IsZero() {
+			age = "no commit date"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "  • %s (%s)\n", info.Name, age)
+	}
+	fmt.Fprintln(cmd.OutOrStdout())
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// themesCmd renders a sample of every style in each theme, for checking the colors
+var themesCmd = &cobra.Command{
+	Use:    "themes",
+	Short:  "Render a sample of every style in the dark and light themes",
+	Args:   cobra.NoArgs,
+	Hidden: true,
+	RunE:   runThemes,
+}
+
+// runThemes prints the style samples of the dark and the light theme, and which one auto picks here
+func runThemes(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+	for _, theme := range []ui.Theme{ui.ThemeDark, ui.ThemeLight} {
+		fmt.Fprintf(out, "%s:\n%s\n", theme, ui.RenderStyleSample(ui.NewStyles(theme, colorEnabled())))
+	}
+
+	detected := ui.ThemeLight
+	if lipgloss.HasDarkBackground() {
+		detected = ui.ThemeDark
+	}
+	fmt.Fprintf(out, "auto: %s (detected from the terminal background)\n", detected)
+	return nil
+}
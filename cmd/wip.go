@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// wipPatterns match the subjects of WIP commits: the wip_patterns of the config, or git.DefaultWIPPatterns
+var wipPatterns []*regexp.Regexp
+
+// configureWIP compiles the wip_patterns of the config, so an invalid regular expression is reported at startup
+// rather than when WIP branches are first looked for
+func configureWIP() error {
+	patterns := cfg.WIPPatterns
+	if patterns == nil {
+		patterns = git.DefaultWIPPatterns
+	}
+	compiled, err := git.CompileWIPPatterns(patterns)
+	if err != nil {
+		return fmt.Errorf("invalid wip_patterns in config: %w", err)
+	}
+	wipPatterns = compiled
+	return nil
+}
+
+// runSelectWIP selects the branches whose own commits are all WIP commits. With --yes (or --json) they are
+// deleted without the TUI; otherwise the TUI opens and selects them once it looked for them behind its spinner.
+func runSelectWIP(cmd *cobra.Command, args []string) error {
+	switch {
+	case len(args) > 0:
+		return fmt.Errorf("branch arguments cannot be combined with --select-wip")
+	case opts.remote:
+		return fmt.Errorf("--select-wip cannot be combined with --remote")
+	}
+
+	if opts.interactive && !opts.yes && !opts.json {
+		return runLocal(cmd, nil)
+	}
+
+	wip, err := listWIPBranches()
+	if err != nil {
+		return err
+	}
+	if len(wip) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No WIP branches.")
+		return nil
+	}
+
+	if !opts.yes && !opts.json {
+		return runLocal(cmd, wip)
+	}
+	return runBatch(cmd, wip)
+}
+
+// listWIPBranches returns the local branches passing the merge filter and --mine whose own commits,
+// those not on the base branch, all have a subject matching wipPatterns
+func listWIPBranches() ([]string, error) {
+	branchInfos, err := listLocalBranches()
+	if err != nil {
+		return nil, err
+	}
+
+	baseBranch, mergedBranches, err := detectMergeStatus()
+	if err != nil {
+		return nil, err
+	}
+	if baseBranch == "" {
+		baseBranch = "HEAD"
+	}
+
+	var wip []string
+	for _, info := range filterMine(branchInfos) {
+		if !passesMergeFilter(info.Name, mergedBranches) {
+			continue
+		}
+		isWIP, err := git.IsWIPBranch(git.BranchRef(info.Name), baseBranch, wipPatterns)
+		if err != nil {
+			return nil, err
+		}
+		if isWIP {
+			wip = append(wip, info.Name)
+		}
+	}
+	return wip, nil
+}
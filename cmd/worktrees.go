@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// worktreesCmd removes linked worktrees interactively
+var worktreesCmd = &cobra.Command{
+	Use:   "worktrees",
+	Short: "Interactively remove linked worktrees",
+	Long: `Select linked worktrees in a terminal UI and remove them with git worktree remove.
+Locked worktrees are only force removed after confirmation, and the branches that were
+checked out in removed worktrees can be deleted afterwards, one by one; those git refuses
+as not fully merged are only force deleted after confirmation. The main worktree is never listed.`,
+	Args: cobra.NoArgs,
+	RunE: runWorktrees,
+}
+
+// runWorktrees runs the worktree cleanup TUI over the linked worktrees of the repository
+func runWorktrees(cmd *cobra.Command, args []string) error {
+	if err := setup(cmd); err != nil {
+		return err
+	}
+
+	if err := configureBase(cmd); err != nil {
+		return err
+	}
+
+	items, mainBranch, err := listLinkedWorktrees()
+	if err != nil {
+		return err
+	}
+	if reportNoWorktrees(cmd, items) {
+		return nil
+	}
+
+	baseBranch, mergedBranches := detectMergedBranches()
+	model := ui.WorktreeModel{
+		Repo:           repo,
+		Worktrees:      items,
+		Selected:       make(map[string]bool),
+		State:          ui.WorktreeStateSelection,
+		MainBranch:     mainBranch,
+		BaseBranch:     baseBranch,
+		MergedBranches: mergedBranches,
+		Styles:         styles(),
+		Lang:           lang,
+	}
+
+	closeLog, err := logToFile(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+
+	final, err := runProgram(model)
+	if err != nil {
+		return fmt.Errorf("error running UI: %w", err)
+	}
+	result, _ := final.(ui.WorktreeModel)
+	return worktreeResult(result)
+}
+
+// reportNoWorktrees tells that there are no linked worktrees to remove. Reports whether there are none.
+func reportNoWorktrees(cmd *cobra.Command, items []ui.WorktreeItem) bool {
+	if len(items) > 0 {
+		return false
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "No worktrees to remove.")
+	fmt.Fprintln(cmd.OutOrStdout(), "(The main worktree is excluded from the list)")
+	return true
+}
+
+// worktreeResult returns the error, and so the exit code, of a worktree TUI run that quit in the given state
+func worktreeResult(result ui.WorktreeModel) error {
+	switch {
+	case result.Interrupted != nil:
+		return interrupted(result.Interrupted)
+	case result.HasFailures():
+		return deletionFailed("failed to remove %d worktree(s) or branch(es)", len(result.Failures))
+	case result.WasCancelled():
+		return cancelled("cancelled with worktrees selected for removal")
+	}
+	return nil
+}
+
+// listLinkedWorktrees returns the worktrees other than the main worktree, with the modification time of their directory,
+// and the branch checked out in the main worktree (empty if HEAD is detached there or the repository is bare)
+func listLinkedWorktrees() ([]ui.WorktreeItem, string, error) {
+	worktrees, err := repo.ListWorktrees(context.Background())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var items []ui.WorktreeItem
+	mainBranch := ""
+	for _, wt := range worktrees {
+		if wt.Main {
+			mainBranch = wt.Branch
+			continue
+		}
+		item := ui.WorktreeItem{Worktree: wt}
+		if info, err := os.Stat(wt.Path); err == nil {
+			item.Modified = info.ModTime()
+		}
+		items = append(items, item)
+	}
+	return items, mainBranch, nil
+}
@@ -0,0 +1,154 @@
+// Package audit appends a record of every branch deletion to a JSON Lines file, so a team can look up
+// what was deleted, when, and how to get it back.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/git"
+)
+
+// FileName is the name of the audit log inside the repository's git directory
+const FileName = "gelete-log.jsonl"
+
+var (
+	// mu guards path
+	mu sync.Mutex
+
+	// path is the audit log set with SetPath; empty uses FileName in the repository's git directory
+	path string
+)
+
+// Entry is the record of one branch deletion
+type Entry struct {
+	// Time is when the deletion happened; Append fills it in if it is zero
+	Time time.Time `json:"time"`
+
+	// Branch is the deleted branch, e.g. "feature/x", or "origin/feature/x" for a remote branch
+	Branch string `json:"branch"`
+
+	// SHA is the branch tip before deletion, empty if it could not be resolved
+	SHA string `json:"sha,omitempty"`
+
+	// Forced reports whether the branch was force deleted (git branch -D)
+	Forced bool `json:"forced,omitempty"`
+
+	// Worktree is the path of the worktree removed along with the branch, if any
+	Worktree string `json:"worktree,omitempty"`
+
+	// Remote is the remote branch deleted along with the branch, e.g. "origin/feature/x", if any.
+	// If RemoteError is set, deleting it was attempted but failed.
+	Remote string `json:"remote,omitempty"`
+
+	// RemoteError is why deleting the remote branch failed, if it did
+	RemoteError string `json:"remote_error,omitempty"`
+
+	// Error is git's error if the deletion failed; empty if the branch was deleted
+	Error string `json:"error,omitempty"`
+}
+
+// Failed reports whether the branch could not be deleted
+func (e Entry) Failed() bool {
+	return e.Error != ""
+}
+
+// SetPath writes the audit log to p instead of FileName in the repository's git directory; "" restores the default
+func SetPath(p string) {
+	mu.Lock()
+	defer mu.Unlock()
+	path = p
+}
+
+// Path returns the audit log of the current repository.
+// The default is shared by all worktrees, so deletions from any of them end up in the same log.
+func Path() (string, error) {
+	mu.Lock()
+	configured := path
+	mu.Unlock()
+	if configured != "" {
+		return configured, nil
+	}
+
+	gitDir, err := git.GetCommonDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, FileName), nil
+}
+
+// Append adds an entry to the end of the audit log, creating the file if needed.
+// Each entry is written with a single append-mode write, so concurrent gelete processes don't interleave lines.
+func Append(entry Entry) error {
+	logPath, err := Path()
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit log entry: %w", err)
+	}
+
+	file, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write audit log '%s': %w", logPath, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write audit log '%s': %w", logPath, err)
+	}
+	return nil
+}
+
+// Last returns the last n entries of the audit log, oldest first; n <= 0 returns all of them.
+// Returns no entries if nothing was logged yet.
+func Last(n int) ([]Entry, error) {
+	logPath, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(logPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	// The log is only read, so failing to close it loses nothing
+	defer func() { _ = file.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse audit log '%s' line %d: %w", logPath, lineNumber, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log '%s': %w", logPath, err)
+	}
+
+	if n > 0 && len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries, nil
+}
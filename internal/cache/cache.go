@@ -0,0 +1,153 @@
+// Package cache keeps the per-branch analysis of previous gelete runs (squash-merge detection and
+// ahead/behind counts), so branches whose tip and base didn't move aren't analyzed again on every launch.
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Kdaito/gelete/internal/git"
+)
+
+// FileName is the name of the cache file inside the repository's git directory
+const FileName = "gelete-cache.json"
+
+// version is bumped whenever the meaning of the cached results changes, so older caches are rebuilt
+const version = 1
+
+// Result is the analysis of a branch against the base branch
+type Result struct {
+	// SquashMerged indicates the branch content appears merged into the base, e.g. with "squash and merge"
+	SquashMerged bool `json:"squash_merged"`
+
+	// Divergence holds how far the branch has diverged from the base
+	Divergence git.AheadBehind `json:"divergence"`
+}
+
+// entry is a cached result with the tips it was computed for; it is stale once either of them moves
+type entry struct {
+	BranchSHA string `json:"branch_sha"`
+	BaseSHA   string `json:"base_sha"`
+	Result    Result `json:"result"`
+}
+
+// file is the on-disk format of the cache
+type file struct {
+	Version int              `json:"version"`
+	Entries map[string]entry `json:"entries"`
+}
+
+// Cache holds the results of previous runs by branch name
+type Cache struct {
+	path    string
+	entries map[string]entry
+	changed bool
+}
+
+// Path returns the cache file path of the current repository.
+// The cache lives in the git directory shared by all worktrees, since they share the branches too.
+func Path() (string, error) {
+	gitDir, err := git.GetCommonDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, FileName), nil
+}
+
+// Load reads the cache of the current repository. The cache is only an optimization, so a missing,
+// unreadable or corrupt file gives an empty cache, which the next Save replaces.
+func Load() *Cache {
+	path, err := Path()
+	if err != nil {
+		return &Cache{entries: make(map[string]entry)}
+	}
+	return LoadFile(path)
+}
+
+// LoadFile is like Load but reads the cache file at path
+func LoadFile(path string) *Cache {
+	c := &Cache{path: path, entries: make(map[string]entry)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+
+	var cached file
+	if err := json.Unmarshal(data, &cached); err != nil || cached.Version != version {
+		return c
+	}
+	for branch, e := range cached.Entries {
+		c.entries[branch] = e
+	}
+	return c
+}
+
+// Len returns the number of cached branches
+func (c *Cache) Len() int {
+	return len(c.entries)
+}
+
+// Analyze returns the result of every branch against the base commit baseSHA. Results cached for the
+// same branch and base tips are reused, and compute is called once with the branches that have none;
+// branches compute leaves out get no result. Cached branches that aren't listed anymore are evicted.
+func (c *Cache) Analyze(branches []git.BranchInfo, baseSHA string, compute func(branches []string) map[string]Result) map[string]Result {
+	results := make(map[string]Result, len(branches))
+	entries := make(map[string]entry, len(branches))
+	var misses []string
+	for _, info := range branches {
+		if e, ok := c.entries[info.Name]; ok && e.BranchSHA == info.SHA && e.BaseSHA == baseSHA {
+			results[info.Name] = e.Result
+			entries[info.Name] = e
+			continue
+		}
+		misses = append(misses, info.Name)
+	}
+
+	if len(misses) > 0 {
+		computed := compute(misses)
+		for _, info := range branches {
+			if result, ok := computed[info.Name]; ok {
+				results[info.Name] = result
+				entries[info.Name] = entry{BranchSHA: info.SHA, BaseSHA: baseSHA, Result: result}
+			}
+		}
+	}
+
+	c.changed = c.changed || len(misses) > 0 || len(entries) != len(c.entries)
+	c.entries = entries
+	return results
+}
+
+// Save writes the cache back to its file if Analyze changed it. The file is replaced atomically,
+// so a concurrent run never reads a half-written cache.
+func (c *Cache) Save() error {
+	if !c.changed || c.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(file{Version: version, Entries: c.entries})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), FileName+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save cache: %w", err)
+	}
+	_, err = tmp.Write(data)
+	err = errors.Join(err, tmp.Close())
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save cache: %w", err)
+	}
+
+	c.changed = false
+	return nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// ageUnits are the friendly age units: days, weeks, months of 30 days and years of 365 days
+var ageUnits = map[byte]time.Duration{
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+	'm': 30 * 24 * time.Hour,
+	'y': 365 * 24 * time.Hour,
+}
+
+// ParseAge parses an age like "90d", "2w", "6m" or "1y", or a Go duration like "36h".
+// Friendly ages are a whole number followed by d (days), w (weeks), m (months of 30 days) or
+// y (years of 365 days); note that "m" means months, not minutes as in Go durations.
+func ParseAge(value string) (time.Duration, error) {
+	age, err := parseFriendlyAge(value)
+	if err != nil {
+		age, err = time.ParseDuration(value)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid age '%s' (expected e.g. 90d, 2w, 6m, 1y or 36h)", value)
+	}
+	if age <= 0 {
+		return 0, fmt.Errorf("invalid age '%s': must be positive", value)
+	}
+	return age, nil
+}
+
+// parseFriendlyAge parses a whole number followed by one of ageUnits
+func parseFriendlyAge(value string) (time.Duration, error) {
+	if len(value) < 2 {
+		return 0, fmt.Errorf("age too short")
+	}
+
+	unit, ok := ageUnits[value[len(value)-1]]
+	if !ok {
+		return 0, fmt.Errorf("unknown age unit")
+	}
+	count, err := strconv.ParseInt(value[:len(value)-1], 10, 64)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("invalid age count")
+	}
+	if count > math.MaxInt64/int64(unit) {
+		return 0, fmt.Errorf("age too large")
+	}
+	return time.Duration(count) * unit, nil
+}
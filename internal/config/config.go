@@ -0,0 +1,233 @@
+// Package config loads gelete's defaults from a global config file and a per-repository
+// .gelete.yaml, so frequently used flags don't have to be repeated on every run.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"gopkg.in/yaml.v3"
+)
+
+// RepoFileName is the name of the per-repository config file in the top-level directory of the working tree
+const RepoFileName = ".gelete.yaml"
+
+// Config holds the defaults read from config files.
+// Unset fields keep gelete's built-in defaults; command-line flags override every field.
+type Config struct {
+	// Protect lists additional protected branch patterns (e.g. "release/*")
+	Protect []string `yaml:"protect,omitempty"`
+
+	// Sort is the initial branch order: name, age or -age
+	Sort string `yaml:"sort,omitempty"`
+
+	// BaseBranch is the branch merge status and divergence are computed against.
+	// The default branch is detected when empty.
+	BaseBranch string `yaml:"base_branch,omitempty"`
+
+	// Color enables colored output; nil means enabled when stdout is a terminal
+	Color *bool `yaml:"color,omitempty"`
+
+	// Theme is the color theme of the TUI: auto, dark or light
+	Theme string `yaml:"theme,omitempty"`
+
+	// DateFormat is how the TUI shows commit dates at first: relative or absolute (toggled with the t key)
+	DateFormat string `yaml:"date_format,omitempty"`
+
+	// AutoSelectGone pre-selects branches whose upstream is gone; nil means disabled
+	AutoSelectGone *bool `yaml:"auto_select_gone,omitempty"`
+
+	// ConfirmThreshold is the number of selected branches above which the TUI asks to type the confirmation;
+	// nil means 10, and 0 disables it
+	ConfirmThreshold *int `yaml:"confirm_threshold,omitempty"`
+
+	// WIPPatterns lists regular expressions matching the subjects of throwaway commits, e.g. "^wip".
+	// Branches whose own commits all match are flagged as WIP; nil means git.DefaultWIPPatterns.
+	WIPPatterns []string `yaml:"wip_patterns,omitempty"`
+
+	// Keys rebinds the keys of the TUI by action, e.g. delete: x (see ui.NewKeyMap)
+	Keys map[string]KeyList `yaml:"keys,omitempty"`
+
+	// Sources lists the config files that were read, in order of increasing precedence
+	Sources []string `yaml:"-"`
+
+	// Warnings describe problems that didn't prevent loading, such as unknown keys
+	Warnings []string `yaml:"-"`
+}
+
+// knownKeys are the top-level keys of a config file; other keys produce a warning
+var knownKeys = []string{"protect", "sort", "base_branch", "color", "theme", "date_format", "auto_select_gone", "confirm_threshold", "wip_patterns", "keys"}
+
+// KeyList holds the keys bound to an action in the keys section: a single key (delete: x)
+// or a list of them (up: [up, ctrl+p])
+type KeyList []string
+
+// UnmarshalYAML accepts a single key as well as a list of keys
+func (l *KeyList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = KeyList{node.Value}
+		return nil
+	}
+
+	var keys []string
+	if err := node.Decode(&keys); err != nil {
+		return err
+	}
+	*l = keys
+	return nil
+}
+
+// GlobalPath returns the path of the global config file:
+// $XDG_CONFIG_HOME/gelete/config.yaml, or ~/.config/gelete/config.yaml if XDG_CONFIG_HOME is unset.
+// Returns an empty string if neither location can be determined.
+func GlobalPath() string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "gelete", "config.yaml")
+}
+
+// RepoPath returns the path of the config file of the current repository.
+// Returns an empty string outside a working tree (e.g. in a bare repository).
+func RepoPath() string {
+	root, err := git.GetTopLevel()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(root, RepoFileName)
+}
+
+// Load reads the global and the repository config file and merges them, the repository file taking precedence.
+// Unknown keys are reported in Warnings rather than failing the load.
+func Load() (Config, error) {
+	return LoadFiles(GlobalPath(), RepoPath())
+}
+
+// LoadFiles merges the given config files in order, later files overriding earlier ones.
+// Missing files and empty paths are skipped.
+func LoadFiles(paths ...string) (Config, error) {
+	var merged Config
+
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to read config '%s': %w", path, err)
+		}
+
+		cfg, unknown, err := parse(data)
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to parse config '%s': %w", path, err)
+		}
+		for _, key := range unknown {
+			merged.Warnings = append(merged.Warnings, fmt.Sprintf("unknown key '%s' in %s", key, path))
+		}
+
+		merged.merge(cfg)
+		merged.Sources = append(merged.Sources, path)
+	}
+
+	return merged, nil
+}
+
+// parse decodes a config file and returns the top-level keys it doesn't know
+func parse(data []byte) (Config, []string, error) {
+	var cfg Config
+	if len(bytes.TrimSpace(data)) == 0 {
+		return cfg, nil, nil
+	}
+
+	var keys map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &keys); err != nil {
+		return cfg, nil, err
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, nil, err
+	}
+
+	var unknown []string
+	for key := range keys {
+		if !slices.Contains(knownKeys, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	slices.Sort(unknown)
+
+	return cfg, unknown, nil
+}
+
+// merge overrides the fields of c with the fields set in other
+func (c *Config) merge(other Config) {
+	overrideList(&c.Protect, other.Protect)
+	override(&c.Sort, other.Sort)
+	override(&c.BaseBranch, other.BaseBranch)
+	override(&c.Color, other.Color)
+	override(&c.Theme, other.Theme)
+	override(&c.DateFormat, other.DateFormat)
+	override(&c.AutoSelectGone, other.AutoSelectGone)
+	override(&c.ConfirmThreshold, other.ConfirmThreshold)
+	overrideList(&c.WIPPatterns, other.WIPPatterns)
+
+	// Keys are merged by action, so a repository can rebind one key without repeating the others
+	for action, keys := range other.Keys {
+		if c.Keys == nil {
+			c.Keys = make(map[string]KeyList)
+		}
+		c.Keys[action] = keys
+	}
+}
+
+// override sets field to value if value is set, that is not the zero value (an empty string or a nil pointer)
+func override[T comparable](field *T, value T) {
+	var unset T
+	if value != unset {
+		*field = value
+	}
+}
+
+// overrideList sets field to list if list is set, that is not nil; an empty list is set too
+func overrideList(field *[]string, list []string) {
+	if list != nil {
+		*field = list
+	}
+}
+
+// ColorEnabled reports whether the config allows colored output
+func (c Config) ColorEnabled() bool {
+	return c.Color == nil || *c.Color
+}
+
+// KeyBindings returns the keys rebound in the config by action, for ui.NewKeyMap
+func (c Config) KeyBindings() map[string][]string {
+	bindings := make(map[string][]string, len(c.Keys))
+	for action, keys := range c.Keys {
+		bindings[action] = keys
+	}
+	return bindings
+}
+
+// SelectGone reports whether branches with a gone upstream should be pre-selected
+func (c Config) SelectGone() bool {
+	return c.AutoSelectGone != nil && *c.AutoSelectGone
+}
+
+// Marshal encodes the config as YAML
+func (c Config) Marshal() ([]byte, error) {
+	return yaml.Marshal(c)
+}
@@ -0,0 +1,91 @@
+// Package fsutil estimates how much disk space directories use, e.g. to tell how much removing a worktree frees.
+package fsutil
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// MaxDepth is how many directory levels below the root DirSize descends; deeper files aren't counted
+const MaxDepth = 64
+
+// DirSize returns the total size of the regular files under path, without following symlinks.
+// Unreadable subdirectories are skipped, so the result is an estimate; only an unreadable path is an error.
+func DirSize(path string) (int64, error) {
+	return DirSizeContext(context.Background(), path)
+}
+
+// DirSizeContext is like DirSize but stops walking when ctx is done, returning the size counted so far
+// together with ctx's error, so callers can bound the time spent on huge trees.
+func DirSizeContext(ctx context.Context, path string) (int64, error) {
+	root := filepath.Clean(path)
+	walker := sizeWalker{ctx: ctx, root: root, rootDepth: strings.Count(root, string(filepath.Separator))}
+
+	err := filepath.WalkDir(root, walker.visit)
+	if err != nil && ctx.Err() == nil {
+		return walker.size, fmt.Errorf("failed to compute the size of '%s': %w", path, err)
+	}
+	return walker.size, err
+}
+
+// sizeWalker adds up the sizes of the regular files filepath.WalkDir visits below root
+type sizeWalker struct {
+	ctx       context.Context
+	root      string
+	rootDepth int
+	size      int64
+}
+
+// visit counts a regular file, and skips what can't be read and directories below MaxDepth
+func (w *sizeWalker) visit(current string, d fs.DirEntry, err error) error {
+	if ctxErr := w.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if err != nil {
+		return w.skipUnreadable(current, d, err)
+	}
+
+	switch {
+	case d.IsDir() && strings.Count(current, string(filepath.Separator))-w.rootDepth >= MaxDepth:
+		return fs.SkipDir
+	case !d.Type().IsRegular():
+		return nil
+	}
+
+	// Files removed while walking are skipped like unreadable ones
+	if info, err := d.Info(); err == nil {
+		w.size += info.Size()
+	}
+	return nil
+}
+
+// skipUnreadable skips an entry that couldn't be read; only an unreadable root stops the walk
+func (w *sizeWalker) skipUnreadable(current string, d fs.DirEntry, err error) error {
+	switch {
+	case current == w.root:
+		return err
+	case d != nil && d.IsDir():
+		return fs.SkipDir
+	}
+	return nil
+}
+
+// FormatSize formats a size in bytes for humans with binary units, e.g. "512 B", "1.5 KB" or "4.2 GB"
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	value := float64(bytes) / unit
+	units := []string{"KB", "MB", "GB", "TB", "PB"}
+	i := 0
+	for value >= unit && i < len(units)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", value, units[i])
+}
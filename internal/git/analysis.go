@@ -0,0 +1,128 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MergeStatus is whether a branch is merged into HEAD, as far as it could be determined
+type MergeStatus int
+
+const (
+	// StatusUnknown: the merge status couldn't be determined
+	StatusUnknown MergeStatus = iota
+	// StatusMerged: the branch tip is reachable from HEAD, so `git branch -d` accepts deleting it
+	StatusMerged
+	// StatusUnmerged: the branch has commits not in HEAD and can only be force deleted
+	StatusUnmerged
+)
+
+// BranchAnalysis describes the risks of deleting a branch, determined before deletion starts
+type BranchAnalysis struct {
+	// Name is the branch name
+	Name string
+
+	// Status is whether the branch is merged into HEAD
+	Status MergeStatus
+
+	// Worktree is the path of the worktree the branch is checked out in (empty if none)
+	Worktree string
+
+	// Protected indicates the branch matches a protected pattern
+	Protected bool
+
+	// Stashes is the number of stash entries made while the branch was checked out
+	Stashes int
+
+	// Refs are the tags and notes attached to the commits only the branch has (see GetDanglingRefsForBranch).
+	// AnalyzeBranches leaves them empty; they are looked up separately since it takes a few git commands.
+	Refs DanglingRefs
+}
+
+// AnalyzeBranches classifies the given branches before deleting them: whether they are merged,
+// checked out in a worktree, protected, or have stash entries. A branch whose merge status can't be
+// determined is reported with StatusUnknown. Returns an error if worktrees can't be listed.
+func AnalyzeBranches(names []string) ([]BranchAnalysis, error) {
+	return AnalyzeBranchesContext(context.Background(), names)
+}
+
+// AnalyzeBranchesContext is like AnalyzeBranches but kills git when ctx is done.
+func AnalyzeBranchesContext(ctx context.Context, names []string) ([]BranchAnalysis, error) {
+	worktrees, err := ListWorktreesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	branchWorktrees := make(map[string]string)
+	for _, wt := range worktrees {
+		if wt.Branch != "" {
+			branchWorktrees[wt.Branch] = wt.Path
+		}
+	}
+
+	// Stashes are informational, and bare repositories can't list them
+	stashes, _ := countStashesByBranchContext(ctx)
+
+	analyses := make([]BranchAnalysis, 0, len(names))
+	for _, name := range names {
+		analyses = append(analyses, BranchAnalysis{
+			Name:      name,
+			Status:    mergeStatus(ctx, name),
+			Worktree:  branchWorktrees[name],
+			Protected: IsProtected(name),
+			Stashes:   stashes[name],
+		})
+	}
+	return analyses, nil
+}
+
+// mergeStatus returns whether the branch is merged into HEAD, or StatusUnknown if the check failed
+func mergeStatus(ctx context.Context, name string) MergeStatus {
+	merged, err := IsBranchMergedContext(ctx, name)
+	switch {
+	case err != nil:
+		return StatusUnknown
+	case merged:
+		return StatusMerged
+	}
+	return StatusUnmerged
+}
+
+// countStashesByBranchContext returns how many stash entries were made on each branch.
+// Stash subjects start with "WIP on <branch>:" or "On <branch>:" (see git-stash(1)).
+func countStashesByBranchContext(ctx context.Context) (map[string]int, error) {
+	output, err := gitCombinedOutput(ctx, "stash", "list", "--format=%gs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stashes: %w", commandError(output, err))
+	}
+
+	counts := make(map[string]int)
+	for _, subject := range splitLines(string(output)) {
+		if branch, ok := stashBranch(subject); ok {
+			counts[branch]++
+		}
+	}
+	return counts, nil
+}
+
+// stashBranch returns the branch a stash entry was made on, parsed from its subject
+func stashBranch(subject string) (string, bool) {
+	for _, prefix := range []string{"WIP on ", "On "} {
+		if rest, ok := strings.CutPrefix(subject, prefix); ok {
+			branch, _, found := strings.Cut(rest, ":")
+			return branch, found
+		}
+	}
+	return "", false
+}
+
+// String returns a human readable description of the merge status
+func (s MergeStatus) String() string {
+	switch s {
+	case StatusMerged:
+		return "merged"
+	case StatusUnmerged:
+		return "unmerged"
+	}
+	return "unknown status"
+}
@@ -0,0 +1,60 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BackupTagPrefix is the namespace of the tags CreateBackupTag creates
+const BackupTagPrefix = "gelete-backup/"
+
+// CreateBackupTag tags the tip of a local branch as gelete-backup/<branch>-<date>, so the branch can be
+// restored with `git branch <branch> <tag>` after it is force deleted. If that tag exists already, e.g. from
+// an earlier backup the same day, a numeric suffix is added (gelete-backup/x-2024-05-01-2).
+// Returns the name of the tag created.
+func CreateBackupTag(branch string, date time.Time) (string, error) {
+	return CreateBackupTagContext(context.Background(), branch, date)
+}
+
+// CreateBackupTagContext is like CreateBackupTag but kills git when ctx is done.
+func CreateBackupTagContext(ctx context.Context, branch string, date time.Time) (string, error) {
+	base := fmt.Sprintf("%s%s-%s", BackupTagPrefix, branch, date.Format("2006-01-02"))
+	name := base
+	for suffix := 2; TagExistsContext(ctx, name); suffix++ {
+		name = fmt.Sprintf("%s-%d", base, suffix)
+	}
+
+	if err := CreateTagContext(ctx, name, BranchRef(branch)); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// CreateBundle writes the given local branches into a git bundle at path (git bundle create), from which
+// they can be fetched back with `git fetch <path> <branch>:<branch>`. An existing file at path is overwritten.
+func CreateBundle(path string, branches []string) error {
+	return CreateBundleContext(context.Background(), path, branches)
+}
+
+// CreateBundleContext is like CreateBundle but kills git when ctx is done.
+func CreateBundleContext(ctx context.Context, path string, branches []string) error {
+	// bundle create takes everything after the path as rev-list arguments, so it has no --;
+	// a relative path starting with a dash is made explicit instead
+	if strings.HasPrefix(path, "-") {
+		path = "." + string(filepath.Separator) + path
+	}
+	args := []string{"bundle", "create", path}
+	for _, branch := range branches {
+		args = append(args, BranchRef(branch))
+	}
+	output, err := gitCombinedOutput(ctx, args...)
+
+	if err != nil {
+		return fmt.Errorf("failed to create bundle '%s': %w", path, commandError(output, err))
+	}
+
+	return nil
+}
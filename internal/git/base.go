@@ -0,0 +1,132 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// BaseConfigKey is the git config key holding the base branch merge status is computed against
+const BaseConfigKey = "gelete.base"
+
+// maxSuggestions bounds the branch names suggested for a ref that doesn't resolve
+const maxSuggestions = 3
+
+// ConfiguredBase returns the base set with `git config gelete.base <ref>`.
+// Returns an empty string if the key is not set.
+func ConfiguredBase() (string, error) {
+	return ConfiguredBaseContext(context.Background())
+}
+
+// ConfiguredBaseContext is like ConfiguredBase but kills git when ctx is done.
+func ConfiguredBaseContext(ctx context.Context) (string, error) {
+	output, err := gitCombinedOutput(ctx, "config", "--get", BaseConfigKey)
+
+	if err != nil {
+		// Exit code 1 means the key is not set
+		if isExitCode(err, 1) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read '%s' config: %w", BaseConfigKey, commandError(output, err))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// UserEmail returns the email set with `git config user.email`, which commits are authored with.
+// Returns an empty string if the key is not set.
+func UserEmail() (string, error) {
+	return UserEmailContext(context.Background())
+}
+
+// UserEmailContext is like UserEmail but kills git when ctx is done.
+func UserEmailContext(ctx context.Context) (string, error) {
+	output, err := gitCombinedOutput(ctx, "config", "--get", "user.email")
+
+	if err != nil {
+		// Exit code 1 means the key is not set
+		if isExitCode(err, 1) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read 'user.email' config: %w", commandError(output, err))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// VerifyCommit checks that ref resolves to a commit using `git rev-parse --verify`
+func VerifyCommit(ref string) error {
+	return VerifyCommitContext(context.Background(), ref)
+}
+
+// VerifyCommitContext is like VerifyCommit but kills git when ctx is done.
+func VerifyCommitContext(ctx context.Context, ref string) error {
+	if strings.HasPrefix(ref, "-") {
+		// rev-parse would take the ref for an option; a branch named like one can still be given in full
+		return fmt.Errorf("'%s' %w; name a branch starting with a dash in full, e.g. refs/heads/%s", ref, ErrLooksLikeOption, ref)
+	}
+	output, err := gitCombinedOutput(ctx, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+
+	switch {
+	case err == nil:
+		return nil
+	case IsInterrupted(err):
+		return fmt.Errorf("failed to resolve '%s': %w", ref, err)
+	case len(strings.TrimSpace(string(output))) > 0:
+		return fmt.Errorf("failed to resolve '%s': %w", ref, commandError(output, err))
+	}
+	return fmt.Errorf("'%s' is not a branch or commit", ref)
+}
+
+// ResolveCommit returns the full object name of the commit ref points to
+func ResolveCommit(ref string) (string, error) {
+	return ResolveCommitContext(context.Background(), ref)
+}
+
+// ResolveCommitContext is like ResolveCommit but kills git when ctx is done.
+func ResolveCommitContext(ctx context.Context, ref string) (string, error) {
+	output, err := gitOutput(ctx, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+
+	switch {
+	case err == nil:
+		return strings.TrimSpace(string(output)), nil
+	case IsInterrupted(err):
+		return "", fmt.Errorf("failed to resolve '%s': %w", ref, err)
+	}
+	return "", fmt.Errorf("'%s' is not a branch or commit", ref)
+}
+
+// SuggestBranches returns up to maxSuggestions local branches whose name shares a prefix with ref,
+// to suggest what a mistyped ref may have meant. Protected branches like develop are included.
+func SuggestBranches(ref string) ([]string, error) {
+	return SuggestBranchesContext(context.Background(), ref)
+}
+
+// SuggestBranchesContext is like SuggestBranches but kills git when ctx is done.
+func SuggestBranchesContext(ctx context.Context, ref string) ([]string, error) {
+	output, err := gitCombinedOutput(ctx, "for-each-ref", "--format=%(refname:lstrip=2)", "refs/heads")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", commandError(output, err))
+	}
+
+	// Half of the ref (but at least a few characters) must match, so "develp" suggests "develop"
+	// without every branch sharing a single letter with it
+	minPrefix := min(len(ref), max(3, len(ref)/2))
+
+	var suggestions []string
+	for _, branch := range splitLines(string(output)) {
+		if commonPrefixLength(branch, ref) >= minPrefix && len(suggestions) < maxSuggestions {
+			suggestions = append(suggestions, branch)
+		}
+	}
+	return suggestions, nil
+}
+
+// commonPrefixLength returns the number of leading bytes a and b have in common
+func commonPrefixLength(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
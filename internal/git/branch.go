@@ -1,36 +1,153 @@
 package git
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
-// ListBranches returns a list of all local git branches, excluding the current branch.
-// Branches are returned in alphabetical order.
+// BranchInfo holds a local branch together with metadata about its last commit
+type BranchInfo struct {
+	// Name is the short branch name (e.g. "feature/login")
+	Name string
+
+	// LastCommitDate is the committer date of the branch tip
+	LastCommitDate time.Time
+
+	// RelativeAge is the age of the branch tip as git formats it (e.g. "3 weeks ago"), in English and fixed
+	// when the branches were listed.
+	//
+	// Deprecated: format LastCommitDate instead, e.g. in the reader's language; gelete itself no longer uses this field.
+	RelativeAge string
+
+	// LastCommitter is the committer name of the branch tip
+	LastCommitter string
+
+	// AuthorEmail and CommitterEmail are the author and committer emails of the branch tip, without angle brackets
+	AuthorEmail    string
+	CommitterEmail string
+
+	// SHA is the full object name of the branch tip
+	SHA string
+
+	// Upstream is the remote branch the branch tracks; its Remote is empty if it tracks none
+	Upstream Upstream
+
+	// Gone indicates the upstream is configured but no longer exists, typically because
+	// it was deleted on the remote after its pull request was merged
+	Gone bool
+
+	// Track is the sync state of the branch with its upstream
+	Track UpstreamTrack
+
+	// Creator is the author of the first commit on the branch that isn't on the base branch, i.e. who
+	// started it (empty until loaded with GetBranchCreator, or if the branch has no such commit)
+	Creator string
+
+	// CreatedAt is when the branch was created according to its reflog (zero until loaded with
+	// GetBranchCreatedAt, or if the branch has no reflog, e.g. a branch fetched with a refspec)
+	CreatedAt time.Time
+}
+
+// UpstreamTrack is the sync state of a branch with its upstream, as reported by %(upstream:track)
+type UpstreamTrack struct {
+	// Ahead is the number of commits on the branch that aren't on its upstream, i.e. unpushed commits
+	Ahead int
+
+	// Behind is the number of commits on the upstream that aren't on the branch
+	Behind int
+
+	// Gone indicates the upstream is configured but no longer exists
+	Gone bool
+}
+
+// ParseUpstreamTrack parses a %(upstream:track) value: "[ahead 2]", "[behind 1]", "[ahead 2, behind 1]",
+// "[gone]", or empty when the branch is in sync or has no upstream. Unknown parts are ignored.
+func ParseUpstreamTrack(track string) UpstreamTrack {
+	var parsed UpstreamTrack
+	inner, ok := strings.CutPrefix(strings.TrimSpace(track), "[")
+	if !ok {
+		return parsed
+	}
+	inner = strings.TrimSuffix(inner, "]")
+
+	for _, part := range strings.Split(inner, ",") {
+		word, count, _ := strings.Cut(strings.TrimSpace(part), " ")
+		n, _ := strconv.Atoi(count)
+		switch word {
+		case "ahead":
+			parsed.Ahead = n
+		case "behind":
+			parsed.Behind = n
+		case "gone":
+			parsed.Gone = true
+		}
+	}
+	return parsed
+}
+
+// branchInfoFormat is the `git for-each-ref` format parsed by ParseBranchInfo: whether the branch
+// is checked out, its name, tip, last commit, upstream and the emails of the last commit. Fields are NUL-separated since
+// committer names may contain any printable character.
+const branchInfoFormat = "%(HEAD)%00%(refname:lstrip=2)%00%(objectname)%00%(committerdate:unix)%00%(committerdate:relative)%00%(committername)" +
+	"%00%(upstream:remotename)%00%(upstream:remoteref)%00%(upstream:track)%00%(authoremail)%00%(committeremail)"
+
+// branchInfoFields is the number of fields in branchInfoFormat
+const branchInfoFields = 11
+
+// ListBranches returns a list of all local git branches, excluding the current branch,
+// protected branches and ignored branches (see IsIgnored). In detached HEAD state no branch is checked out,
+// so only protected and ignored branches are excluded. Branches are returned in alphabetical order.
 func ListBranches() ([]string, error) {
-	// Get current branch to exclude it
-	currentBranch, err := GetCurrentBranch()
+	return ListBranchesContext(context.Background())
+}
+
+// ListBranchesContext is like ListBranches but kills git when ctx is done.
+func ListBranchesContext(ctx context.Context) ([]string, error) {
+	// Get current branch to exclude it (empty when HEAD is detached)
+	currentBranch, _, err := GetHeadBranchContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current branch: %w", err)
 	}
 
-	// List all branches using git branch --format
-	cmd := exec.Command("git", "branch", "--format=%(refname:short)")
-	output, err := cmd.Output()
+	all, err := ListAllBranchesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []string
+	for _, branch := range all {
+		// Skip the current branch, protected and ignored branches
+		if branch != currentBranch && !IsProtected(branch) && !IsIgnored(branch) {
+			branches = append(branches, branch)
+		}
+	}
+	return branches, nil
+}
+
+// ListAllBranches returns every local git branch in alphabetical order, including the current,
+// protected and ignored branches that ListBranches leaves out.
+func ListAllBranches() ([]string, error) {
+	return ListAllBranchesContext(context.Background())
+}
+
+// ListAllBranchesContext is like ListAllBranches but kills git when ctx is done.
+func ListAllBranchesContext(ctx context.Context) ([]string, error) {
+	// List all branches from refs/heads; unlike `git branch`, this never
+	// includes a "(HEAD detached at ...)" pseudo-entry. Names are taken with refname:lstrip=2, since
+	// refname:short prefixes a branch that shares its name with a tag with "heads/".
+	output, err := gitOutput(ctx, "for-each-ref", "--format=%(refname:lstrip=2)", "refs/heads")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list branches: %w", err)
 	}
 
 	// Parse output (one branch per line)
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
 	var branches []string
-
-	for _, line := range lines {
-		branch := strings.TrimSpace(line)
-		// Skip empty lines and current branch
-		if branch != "" && branch != currentBranch {
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if branch := strings.TrimSpace(line); branch != "" {
 			branches = append(branches, branch)
 		}
 	}
@@ -41,15 +158,458 @@ func ListBranches() ([]string, error) {
 	return branches, nil
 }
 
+// DeletableBranch is a local branch returned by ListDeletableBranches
+type DeletableBranch struct {
+	// Name is the short branch name
+	Name string
+
+	// Worktree is the linked worktree the branch is checked out in, or nil if it isn't checked out.
+	// git refuses to delete the branch until the worktree is removed (or pruned, if it is Missing).
+	Worktree *Worktree
+}
+
+// ListDeletableBranches returns the branches of ListBranches that can be deleted, each annotated with
+// the linked worktree it is checked out in. Branches checked out in the main worktree are excluded, since
+// the main worktree can't be removed: when running from a linked worktree, its branch is in use
+// although it isn't the current branch. Branches are returned in alphabetical order.
+func ListDeletableBranches() ([]DeletableBranch, error) {
+	return ListDeletableBranchesContext(context.Background())
+}
+
+// ListDeletableBranchesContext is like ListDeletableBranches but kills git when ctx is done.
+func ListDeletableBranchesContext(ctx context.Context) ([]DeletableBranch, error) {
+	names, err := ListBranchesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	worktrees, err := ListWorktreesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	checkedOut := make(map[string]Worktree)
+	for _, wt := range worktrees {
+		if wt.Branch != "" {
+			checkedOut[wt.Branch] = wt
+		}
+	}
+
+	branches := make([]DeletableBranch, 0, len(names))
+	for _, name := range names {
+		wt, ok := checkedOut[name]
+		switch {
+		case !ok:
+			branches = append(branches, DeletableBranch{Name: name})
+		case !wt.Main:
+			branches = append(branches, DeletableBranch{Name: name, Worktree: &wt})
+		}
+	}
+	return branches, nil
+}
+
+// ListRemoteBranches returns all remote-tracking branches (e.g. "origin/feature-x").
+// Symbolic refs such as origin/HEAD are excluded. Branches are returned in alphabetical order.
+func ListRemoteBranches() ([]string, error) {
+	return ListRemoteBranchesContext(context.Background())
+}
+
+// ListRemoteBranchesContext is like ListRemoteBranches but kills git when ctx is done.
+func ListRemoteBranchesContext(ctx context.Context) ([]string, error) {
+	output, err := gitOutput(ctx, "branch", "-r", "--format=%(refname:lstrip=2)%00%(symref)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote branches: %w", err)
+	}
+
+	var branches []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		name, symref, _ := strings.Cut(strings.TrimSpace(line), "\x00")
+		// Skip empty lines and symbolic refs like origin/HEAD
+		if name != "" && symref == "" {
+			branches = append(branches, name)
+		}
+	}
+
+	sort.Strings(branches)
+
+	return branches, nil
+}
+
+// ListGoneBranches returns the local branches whose upstream tracking branch no longer exists,
+// typically because it was deleted on the remote after its pull request was merged.
+// Branches without a configured upstream are not included. Branches are returned in alphabetical order.
+func ListGoneBranches() ([]string, error) {
+	return ListGoneBranchesContext(context.Background())
+}
+
+// ListGoneBranchesContext is like ListGoneBranches but kills git when ctx is done.
+func ListGoneBranchesContext(ctx context.Context) ([]string, error) {
+	output, err := gitOutput(ctx, "for-each-ref", "--format=%(refname:lstrip=2) %(upstream:track)", "refs/heads")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branch upstreams: %w", err)
+	}
+
+	return parseGoneBranches(string(output)), nil
+}
+
+// parseGoneBranches parses the output of `git for-each-ref --format='%(refname:lstrip=2) %(upstream:track)'`.
+// The track field is empty for branches without an upstream and "[gone]" when the upstream was deleted.
+func parseGoneBranches(output string) []string {
+	var branches []string
+	for _, line := range strings.Split(output, "\n") {
+		name, track, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if name != "" && track == "[gone]" {
+			branches = append(branches, name)
+		}
+	}
+
+	sort.Strings(branches)
+
+	return branches
+}
+
+// Upstream identifies the remote branch a local branch tracks
+type Upstream struct {
+	// Remote is the remote name (e.g. "origin")
+	Remote string
+
+	// Branch is the branch name on the remote (e.g. "feature/x")
+	Branch string
+}
+
+// String returns the remote-tracking name of the upstream (e.g. "origin/feature/x")
+func (u Upstream) String() string {
+	return u.Remote + "/" + u.Branch
+}
+
+// ListUpstreams returns the remote upstream of every local branch that tracks one.
+// Branches without an upstream, or tracking another local branch, are not included.
+func ListUpstreams() (map[string]Upstream, error) {
+	return ListUpstreamsContext(context.Background())
+}
+
+// ListUpstreamsContext is like ListUpstreams but kills git when ctx is done.
+func ListUpstreamsContext(ctx context.Context) (map[string]Upstream, error) {
+	output, err := gitOutput(ctx, "for-each-ref", "--format=%(refname:lstrip=2)%00%(upstream:remotename)%00%(upstream:remoteref)", "refs/heads")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branch upstreams: %w", err)
+	}
+
+	upstreams := make(map[string]Upstream)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(strings.TrimRight(line, "\r"), "\x00")
+		// A remote of "." means the branch tracks another local branch
+		if len(fields) != 3 || fields[0] == "" || fields[1] == "" || fields[1] == "." {
+			continue
+		}
+		upstreams[fields[0]] = Upstream{Remote: fields[1], Branch: strings.TrimPrefix(fields[2], "refs/heads/")}
+	}
+
+	return upstreams, nil
+}
+
+// SplitRemoteBranch splits a remote-tracking branch name like "origin/feature/x"
+// into its remote ("origin") and branch ("feature/x") parts. Remote names can contain slashes themselves,
+// e.g. "team/origin", so the longest of remotes the name starts with is taken; if none matches,
+// the name is split at its first slash.
+func SplitRemoteBranch(remoteBranch string, remotes []string) (remote, branch string) {
+	for _, name := range remotes {
+		if len(name) > len(remote) && strings.HasPrefix(remoteBranch, name+"/") {
+			remote = name
+		}
+	}
+	if remote == "" {
+		remote, branch, _ = strings.Cut(remoteBranch, "/")
+		return remote, branch
+	}
+	return remote, strings.TrimPrefix(remoteBranch, remote+"/")
+}
+
+// ResolveRemoteBranch is SplitRemoteBranch against the remotes of the repository
+func ResolveRemoteBranch(remoteBranch string) (remote, branch string, err error) {
+	return ResolveRemoteBranchContext(context.Background(), remoteBranch)
+}
+
+// ResolveRemoteBranchContext is like ResolveRemoteBranch but kills git when ctx is done.
+func ResolveRemoteBranchContext(ctx context.Context, remoteBranch string) (remote, branch string, err error) {
+	remotes, err := ListRemotesContext(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	remote, branch = SplitRemoteBranch(remoteBranch, remotes)
+	return remote, branch, nil
+}
+
+// ListBranchesWithInfo returns all local branches except the current, protected and ignored branches
+// along with their tip, last commit metadata and upstream, gathered in a single `git for-each-ref`
+// invocation so large repositories don't pay for per-branch commands.
+// In detached HEAD state no branch is excluded as current. Branches are returned in alphabetical order.
+func ListBranchesWithInfo() ([]BranchInfo, error) {
+	return ListBranchesWithInfoContext(context.Background())
+}
+
+// ListBranchesWithInfoContext is like ListBranchesWithInfo but kills git when ctx is done.
+func ListBranchesWithInfoContext(ctx context.Context) ([]BranchInfo, error) {
+	output, err := gitOutput(ctx, "for-each-ref", "--format="+branchInfoFormat, "refs/heads")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	infos, currentBranch := ParseBranchInfo(string(output))
+	var branches []BranchInfo
+	for _, info := range infos {
+		if info.Name != currentBranch && !IsProtected(info.Name) && !IsIgnored(info.Name) {
+			branches = append(branches, info)
+		}
+	}
+
+	sort.Slice(branches, func(i, j int) bool {
+		return branches[i].Name < branches[j].Name
+	})
+
+	return branches, nil
+}
+
+// ParseBranchInfo parses the output of `git for-each-ref --format=<branchInfoFormat>` and returns
+// every branch along with the checked-out one (empty if HEAD is detached).
+// Lines that do not contain every field are skipped, and CRLF line endings are accepted.
+func ParseBranchInfo(output string) (branches []BranchInfo, current string) {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimRight(line, "\r"), "\x00")
+		if len(fields) != branchInfoFields || fields[1] == "" {
+			continue
+		}
+
+		if fields[0] == "*" {
+			current = fields[1]
+		}
+		branches = append(branches, branchInfoFromFields(fields))
+	}
+
+	return branches, current
+}
+
+// branchInfoFromFields builds a BranchInfo from the fields of one branchInfoFormat line
+func branchInfoFromFields(fields []string) BranchInfo {
+	track := ParseUpstreamTrack(fields[8])
+	info := BranchInfo{
+		Name:           fields[1],
+		SHA:            fields[2],
+		RelativeAge:    fields[4],
+		LastCommitter:  fields[5],
+		AuthorEmail:    trimEmail(fields[9]),
+		CommitterEmail: trimEmail(fields[10]),
+		Gone:           track.Gone,
+		Track:          track,
+	}
+	if unix, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
+		info.LastCommitDate = time.Unix(unix, 0)
+	}
+	// A remote of "." means the branch tracks another local branch
+	if remote := fields[6]; remote != "" && remote != "." {
+		info.Upstream = Upstream{Remote: remote, Branch: strings.TrimPrefix(fields[7], "refs/heads/")}
+	}
+	return info
+}
+
+// trimEmail strips the angle brackets git puts around %(authoremail) and %(committeremail)
+func trimEmail(email string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(email, "<"), ">")
+}
+
+// GetDefaultBranch detects the repository's default branch.
+// It prefers the branch origin/HEAD points to and falls back to a local main or master branch.
+// The local branch name is returned when it exists; otherwise the remote-tracking name (e.g. "origin/main").
+func GetDefaultBranch() (string, error) {
+	return GetDefaultBranchContext(context.Background())
+}
+
+// GetDefaultBranchContext is like GetDefaultBranch but kills git when ctx is done.
+func GetDefaultBranchContext(ctx context.Context) (string, error) {
+	output, err := gitOutput(ctx, "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	if err == nil {
+		remoteBranch := strings.TrimSpace(string(output))
+		localBranch := strings.TrimPrefix(remoteBranch, "origin/")
+		if BranchExistsContext(ctx, localBranch) {
+			return localBranch, nil
+		}
+		return remoteBranch, nil
+	}
+
+	if IsInterrupted(err) {
+		return "", err
+	}
+
+	for _, candidate := range []string{"main", "master"} {
+		if BranchExistsContext(ctx, candidate) {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("could not determine the default branch")
+}
+
+// BranchRef returns the full ref name of a local branch. Unlike the branch name, it can't
+// resolve to a tag of the same name when used as a revision.
+func BranchRef(branchName string) string {
+	return "refs/heads/" + branchName
+}
+
+// BranchExists reports whether a local branch with the given name exists
+func BranchExists(branchName string) bool {
+	return BranchExistsContext(context.Background(), branchName)
+}
+
+// BranchExistsContext is like BranchExists but kills git when ctx is done.
+// A branch whose lookup timed out or was cancelled is reported as missing.
+func BranchExistsContext(ctx context.Context, branchName string) bool {
+	_, err := gitCombinedOutput(ctx, "rev-parse", "--verify", "--quiet", "refs/heads/"+branchName)
+	return err == nil
+}
+
+// GetBranchSHA returns the abbreviated commit SHA of the branch tip.
+// The SHA is enough to restore the branch after deletion (git branch <name> <sha>).
+func GetBranchSHA(branchName string) (string, error) {
+	return GetBranchSHAContext(context.Background(), branchName)
+}
+
+// GetBranchSHAContext is like GetBranchSHA but kills git when ctx is done.
+func GetBranchSHAContext(ctx context.Context, branchName string) (string, error) {
+	output, err := gitCombinedOutput(ctx, "rev-parse", "--verify", "--short", "refs/heads/"+branchName)
+
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve branch '%s': %w", branchName, commandError(output, err))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetBranchCommits returns up to limit commits on the branch that are not on base,
+// newest first, formatted as `git log --oneline` lines (abbreviated SHA and subject).
+func GetBranchCommits(branch, base string, limit int) ([]string, error) {
+	return GetBranchCommitsContext(context.Background(), branch, base, limit)
+}
+
+// GetBranchCommitsContext is like GetBranchCommits but kills git when ctx is done.
+func GetBranchCommitsContext(ctx context.Context, branch, base string, limit int) ([]string, error) {
+	output, err := gitCombinedOutput(ctx, "log", "--oneline", "--no-decorate", "-n", strconv.Itoa(limit), base+".."+branch)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits of '%s': %w", branch, commandError(output, err))
+	}
+
+	var commits []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			commits = append(commits, line)
+		}
+	}
+	return commits, nil
+}
+
+// GetBranchDiffStat returns a one-line summary of the changes the branch introduces
+// since it diverged from base (e.g. "3 files changed, 10 insertions(+), 2 deletions(-)").
+// Returns an empty string if the branch introduces no changes.
+func GetBranchDiffStat(branch, base string) (string, error) {
+	return GetBranchDiffStatContext(context.Background(), branch, base)
+}
+
+// GetBranchDiffStatContext is like GetBranchDiffStat but kills git when ctx is done.
+func GetBranchDiffStatContext(ctx context.Context, branch, base string) (string, error) {
+	output, err := gitCombinedOutput(ctx, "diff", "--shortstat", base+"..."+branch)
+
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize changes of '%s': %w", branch, commandError(output, err))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetBranchCreator returns the author of the first commit on the branch that is not on base,
+// or an empty string if the branch has no such commit.
+func GetBranchCreator(branch, base string) (string, error) {
+	return GetBranchCreatorContext(context.Background(), branch, base)
+}
+
+// GetBranchCreatorContext is like GetBranchCreator but kills git when ctx is done.
+func GetBranchCreatorContext(ctx context.Context, branch, base string) (string, error) {
+	// -n would be applied before --reverse and pick the newest commit, so all the authors are listed
+	output, err := gitCombinedOutput(ctx, "log", "--reverse", "--format=%an", base+".."+branch, "--")
+
+	if err != nil {
+		return "", fmt.Errorf("failed to find who started '%s': %w", branch, commandError(output, err))
+	}
+
+	first, _, _ := strings.Cut(string(output), "\n")
+	return strings.TrimSpace(first), nil
+}
+
+// GetBranchCreatedAt returns when a local branch was created: the date of the oldest entry of its reflog.
+// Returns the zero time if the branch has no reflog. An expired reflog makes the branch look younger.
+func GetBranchCreatedAt(branchName string) (time.Time, error) {
+	return GetBranchCreatedAtContext(context.Background(), branchName)
+}
+
+// GetBranchCreatedAtContext is like GetBranchCreatedAt but kills git when ctx is done.
+func GetBranchCreatedAtContext(ctx context.Context, branchName string) (time.Time, error) {
+	output, err := gitCombinedOutput(ctx, "reflog", "show", "--date=unix", "--format=%gd", BranchRef(branchName), "--")
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read the reflog of '%s': %w", branchName, commandError(output, err))
+	}
+
+	lines := strings.Fields(string(output))
+	if len(lines) == 0 {
+		return time.Time{}, nil
+	}
+	// Entries are newest first and look like "refs/heads/feature@{1713432000}"
+	oldest := lines[len(lines)-1]
+	start := strings.LastIndex(oldest, "@{")
+	if start == -1 || !strings.HasSuffix(oldest, "}") {
+		return time.Time{}, fmt.Errorf("unexpected reflog entry for '%s': %s", branchName, oldest)
+	}
+	seconds, err := strconv.ParseInt(oldest[start+2:len(oldest)-1], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected reflog entry for '%s': %s", branchName, oldest)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// GetMergedBranches returns the set of local branches that are fully merged into base.
+func GetMergedBranches(base string) (map[string]bool, error) {
+	return GetMergedBranchesContext(context.Background(), base)
+}
+
+// GetMergedBranchesContext is like GetMergedBranches but kills git when ctx is done.
+func GetMergedBranchesContext(ctx context.Context, base string) (map[string]bool, error) {
+	output, err := gitCombinedOutput(ctx, "branch", "--merged", base, "--format=%(refname:lstrip=2)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches merged into '%s': %w", base, commandError(output, err))
+	}
+
+	merged := make(map[string]bool)
+	for _, line := range strings.Split(string(output), "\n") {
+		if branch := strings.TrimSpace(line); branch != "" {
+			merged[branch] = true
+		}
+	}
+
+	return merged, nil
+}
+
 // DeleteBranch deletes the specified git branch using safe deletion (git branch -d).
-// Returns an error if the branch cannot be deleted (e.g., unmerged changes, doesn't exist).
+// Returns an error if the branch cannot be deleted: one wrapping ErrNotMerged if it has unmerged changes,
+// ErrNotFound if it doesn't exist, or an ErrCheckedOutElsewhere if it is checked out in a worktree.
 func DeleteBranch(branchName string) error {
-	cmd := exec.Command("git", "branch", "-d", branchName)
-	output, err := cmd.CombinedOutput()
+	return DeleteBranchContext(context.Background(), branchName)
+}
+
+// DeleteBranchContext is like DeleteBranch but kills git when ctx is done.
+func DeleteBranchContext(ctx context.Context, branchName string) error {
+	// The -- keeps a branch named like an option, e.g. -f, from being taken for one
+	output, err := gitCombinedOutput(ctx, "branch", "-d", "--", branchName)
 
 	if err != nil {
-		outputStr := strings.TrimSpace(string(output))
-		return fmt.Errorf("failed to delete branch '%s': %s", branchName, outputStr)
+		return fmt.Errorf("failed to delete branch '%s': %w", branchName, deleteError(output, err))
 	}
 
 	return nil
@@ -57,14 +617,184 @@ func DeleteBranch(branchName string) error {
 
 // ForceDeleteBranch forcefully deletes the specified git branch (git branch -D).
 // This bypasses safety checks and will delete branches with unmerged changes.
-// Use with caution. Returns an error if the branch doesn't exist.
+// Use with caution. Returns an error wrapping ErrNotFound if the branch doesn't exist,
+// or an ErrCheckedOutElsewhere if it is checked out in a worktree.
 func ForceDeleteBranch(branchName string) error {
-	cmd := exec.Command("git", "branch", "-D", branchName)
-	output, err := cmd.CombinedOutput()
+	return ForceDeleteBranchContext(context.Background(), branchName)
+}
+
+// ForceDeleteBranchContext is like ForceDeleteBranch but kills git when ctx is done.
+func ForceDeleteBranchContext(ctx context.Context, branchName string) error {
+	output, err := gitCombinedOutput(ctx, "branch", "-D", "--", branchName)
+
+	if err != nil {
+		return fmt.Errorf("failed to force delete branch '%s': %w", branchName, deleteError(output, err))
+	}
+
+	return nil
+}
+
+// RestoreBranch recreates a deleted branch at the given commit (git branch <name> <sha>).
+// Fails rather than overwriting if a branch with the same name was created in the meantime.
+func RestoreBranch(branchName, sha string) error {
+	return RestoreBranchContext(context.Background(), branchName, sha)
+}
+
+// RestoreBranchContext is like RestoreBranch but kills git when ctx is done.
+func RestoreBranchContext(ctx context.Context, branchName, sha string) error {
+	output, err := gitCombinedOutput(ctx, "branch", "--", branchName, sha)
+
+	if err != nil {
+		return fmt.Errorf("failed to restore branch '%s': %w", branchName, commandError(output, err))
+	}
+
+	return nil
+}
+
+// RenameBranch renames a local branch (git branch -m), keeping its upstream and reflog.
+// Branches checked out in a worktree can be renamed too. The new name is checked with ValidateNewBranchName first.
+func RenameBranch(oldName, newName string) error {
+	return RenameBranchContext(context.Background(), oldName, newName)
+}
+
+// RenameBranchContext is like RenameBranch but kills git when ctx is done.
+func RenameBranchContext(ctx context.Context, oldName, newName string) error {
+	if err := ValidateNewBranchNameContext(ctx, newName); err != nil {
+		return err
+	}
+
+	output, err := gitCombinedOutput(ctx, "branch", "-m", "--", oldName, newName)
+
+	if err != nil {
+		return fmt.Errorf("failed to rename branch '%s': %w", oldName, commandError(output, err))
+	}
+
+	return nil
+}
+
+// ValidateNewBranchName checks that a branch could be created with the name: it must not be empty,
+// must be a valid branch name according to `git check-ref-format --branch`, and must not exist yet.
+func ValidateNewBranchName(name string) error {
+	return ValidateNewBranchNameContext(context.Background(), name)
+}
+
+// ValidateNewBranchNameContext is like ValidateNewBranchName but kills git when ctx is done.
+func ValidateNewBranchNameContext(ctx context.Context, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("branch name must not be empty")
+	}
+
+	output, err := gitCombinedOutput(ctx, "check-ref-format", "--branch", name)
+	switch {
+	case IsInterrupted(err):
+		return fmt.Errorf("failed to check branch name '%s': %w", name, err)
+	case err != nil:
+		return fmt.Errorf("'%s' is not a valid branch name", name)
+	case strings.TrimSpace(string(output)) != name:
+		// check-ref-format expands @{-1} and the like to the branch they refer to
+		return fmt.Errorf("'%s' is not a valid branch name", name)
+	}
+
+	if BranchExistsContext(ctx, name) {
+		return fmt.Errorf("a branch named '%s' already exists", name)
+	}
+	return nil
+}
+
+// IsBranchMerged reports whether the branch tip is reachable from HEAD,
+// i.e. whether `git branch -d` would accept deleting it.
+func IsBranchMerged(branchName string) (bool, error) {
+	return IsBranchMergedContext(context.Background(), branchName)
+}
+
+// IsBranchMergedContext is like IsBranchMerged but kills git when ctx is done.
+func IsBranchMergedContext(ctx context.Context, branchName string) (bool, error) {
+	output, err := gitCombinedOutput(ctx, "merge-base", "--is-ancestor", BranchRef(branchName), "HEAD")
+
+	if err != nil {
+		if isExitCode(err, 1) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check merge status of '%s': %w", branchName, commandError(output, err))
+	}
+
+	return true, nil
+}
+
+// IsTipReachableElsewhere reports whether the branch tip is reachable from any other local branch,
+// remote-tracking branch or tag. If it isn't, force deleting the branch leaves its commits dangling.
+func IsTipReachableElsewhere(branch string) (bool, error) {
+	return IsTipReachableElsewhereContext(context.Background(), branch)
+}
+
+// IsTipReachableElsewhereContext is like IsTipReachableElsewhere but kills git when ctx is done.
+func IsTipReachableElsewhereContext(ctx context.Context, branch string) (bool, error) {
+	ref := "refs/heads/" + branch
+	output, err := gitCombinedOutput(ctx, "for-each-ref", "--contains", ref, "--format=%(refname)",
+		"refs/heads", "refs/remotes", "refs/tags")
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check reachability of '%s': %w", branch, commandError(output, err))
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		if name := strings.TrimSpace(line); name != "" && name != ref {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// DeleteRemoteBranch deletes the branch on the remote server (git push <remote> --delete <branch>)
+// and then removes the local remote-tracking ref if git did not already prune it.
+func DeleteRemoteBranch(remote, branch string) error {
+	return DeleteRemoteBranchContext(context.Background(), remote, branch)
+}
+
+// DeleteRemoteBranchContext is like DeleteRemoteBranch but kills git when ctx is done.
+func DeleteRemoteBranchContext(ctx context.Context, remote, branch string) error {
+	output, err := gitCombinedOutput(ctx, "push", remote, "--delete", "--", branch)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete remote branch '%s/%s': %w", remote, branch, commandError(output, err))
+	}
+
+	trackingRef := "refs/remotes/" + remote + "/" + branch
+	if _, err := gitCombinedOutput(ctx, "rev-parse", "--verify", "--quiet", trackingRef); err != nil {
+		return nil
+	}
+
+	output, err = gitCombinedOutput(ctx, "branch", "-dr", "--", remote+"/"+branch)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete remote-tracking branch '%s/%s': %w", remote, branch, commandError(output, err))
+	}
+
+	return nil
+}
+
+// CheckoutBranch switches the working tree to the branch using `git switch`, or `git checkout` with git
+// versions before 2.23. Returns git's error if local changes would be overwritten or the branch is checked
+// out in another worktree, and an error wrapping ErrLooksLikeOption for a branch starting with a dash
+// with git versions before 2.23, whose checkout can only take it for an option.
+func CheckoutBranch(name string) error {
+	return CheckoutBranchContext(context.Background(), name)
+}
+
+// CheckoutBranchContext is like CheckoutBranch but kills git when ctx is done.
+func CheckoutBranchContext(ctx context.Context, name string) error {
+	output, err := gitCombinedOutput(ctx, "switch", "--", name)
+	if err != nil && strings.Contains(string(output), "'switch' is not a git command") {
+		if strings.HasPrefix(name, "-") {
+			return fmt.Errorf("failed to switch to branch '%s': it %w", name, ErrLooksLikeOption)
+		}
+		// The trailing -- makes checkout take name as a branch, never as a path
+		output, err = gitCombinedOutput(ctx, "checkout", name, "--")
+	}
 
 	if err != nil {
-		outputStr := strings.TrimSpace(string(output))
-		return fmt.Errorf("failed to force delete branch '%s': %s", branchName, outputStr)
+		return fmt.Errorf("failed to switch to branch '%s': %w", name, commandError(output, err))
 	}
 
 	return nil
@@ -0,0 +1,127 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/log"
+)
+
+// Timeout bounds how long a single git command may run. Zero means no limit.
+var Timeout time.Duration
+
+var (
+	// ErrTimeout is returned when a git command was killed because it ran longer than allowed
+	ErrTimeout = errors.New("timed out")
+
+	// ErrCancelled is returned when a git command was killed because its context was cancelled
+	ErrCancelled = errors.New("cancelled")
+)
+
+// dirKey is the context key of the directory set with WithDir
+type dirKey struct{}
+
+// WithDir returns a context that runs git commands in dir instead of RepoRoot.
+// This lets callers work on several repositories without depending on the opened one.
+func WithDir(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, dirKey{}, dir)
+}
+
+// unboundedKey is the context key set by withoutTimeout
+type unboundedKey struct{}
+
+// withoutTimeout returns a context whose git commands aren't limited by Timeout, for commands expected
+// to run long, like git gc. Cancelling ctx still kills them.
+func withoutTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unboundedKey{}, true)
+}
+
+// commandDir returns the directory git commands run in: the one set with WithDir, or RepoRoot
+func commandDir(ctx context.Context) string {
+	if dir, ok := ctx.Value(dirKey{}).(string); ok && dir != "" {
+		return dir
+	}
+	return repoRoot
+}
+
+// gitOutput runs git with the given arguments and returns its standard output
+func gitOutput(ctx context.Context, args ...string) ([]byte, error) {
+	return runGit(ctx, Command{Args: args})
+}
+
+// gitCombinedOutput runs git with the given arguments and returns its combined standard output and error
+func gitCombinedOutput(ctx context.Context, args ...string) ([]byte, error) {
+	return runGit(ctx, Command{Args: args, Combined: true})
+}
+
+// gitOutputWithInput runs git with the given arguments, feeding input to its standard input, and returns its standard output
+func gitOutputWithInput(ctx context.Context, input []byte, args ...string) ([]byte, error) {
+	return runGit(ctx, Command{Args: args, Stdin: input})
+}
+
+// runGit runs the git command with the Runner of ctx, in the directory set with WithDir or RepoRoot, bound to ctx
+// and limited by Timeout (unless ctx comes from withoutTimeout). Returns the standard output, or the combined
+// output if cmd.Combined is set. If the command was killed because the deadline passed or ctx was cancelled,
+// the returned error wraps ErrTimeout or ErrCancelled.
+func runGit(ctx context.Context, cmd Command) ([]byte, error) {
+	if unbounded, _ := ctx.Value(unboundedKey{}).(bool); Timeout > 0 && !unbounded {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, Timeout)
+		defer cancel()
+	}
+
+	cmd.Dir = commandDir(ctx)
+	start := time.Now()
+	out, _, err := runnerOf(ctx).Run(ctx, cmd)
+	detail := failureDetail(out, err)
+
+	if err != nil {
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			err = fmt.Errorf("git %s %w%s", cmd.Args[0], ErrTimeout, timeoutSuffix())
+		case errors.Is(ctx.Err(), context.Canceled):
+			err = fmt.Errorf("git %s %w", cmd.Args[0], ErrCancelled)
+		}
+	}
+
+	log.Command(cmd.Dir, cmd.Args, time.Since(start), err, detail)
+	return out, err
+}
+
+// failureDetail returns what a failed git command printed, for logging: its standard error
+// if only standard output was captured, otherwise its output. Nil if the command succeeded.
+func failureDetail(out []byte, err error) []byte {
+	if err == nil {
+		return nil
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return exitErr.Stderr
+	}
+	return out
+}
+
+// timeoutSuffix describes the configured timeout for timeout errors
+func timeoutSuffix() string {
+	if Timeout <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" after %s", Timeout)
+}
+
+// commandError returns the error to report for a failed git command: why it was stopped
+// if it timed out or was cancelled, otherwise the message git printed.
+func commandError(out []byte, err error) error {
+	if IsInterrupted(err) {
+		return err
+	}
+	return errors.New(strings.TrimSpace(string(out)))
+}
+
+// IsInterrupted reports whether err means a git command timed out or was cancelled
+func IsInterrupted(err error) bool {
+	return errors.Is(err, ErrTimeout) || errors.Is(err, ErrCancelled)
+}
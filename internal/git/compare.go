@@ -0,0 +1,110 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CompareCommitLimit is the maximum number of commits listed on each side of a Comparison
+const CompareCommitLimit = 10
+
+// Comparison holds how two local branches relate to each other
+type Comparison struct {
+	// A and B are the compared branches
+	A string
+	B string
+
+	// Ahead is the number of commits on A that are not on B, and Behind the number on B that are not on A
+	Ahead  int
+	Behind int
+
+	// AContainsB is true when B is an ancestor of A, so A has every commit of B; BContainsA the other way round.
+	// Both are true when the branches point at the same commit.
+	AContainsB bool
+	BContainsA bool
+
+	// OnlyA and OnlyB are the newest commits only on A and only on B, at most CompareCommitLimit each,
+	// in `git log --oneline` format
+	OnlyA []string
+	OnlyB []string
+}
+
+// Contained returns the branch the other one fully contains, which can be deleted without losing commits,
+// or "" if each has commits of its own. B is returned when both point at the same commit.
+func (c Comparison) Contained() string {
+	switch {
+	case c.AContainsB:
+		return c.B
+	case c.BContainsA:
+		return c.A
+	}
+	return ""
+}
+
+// CompareBranches compares the local branches a and b: how many commits each has that the other lacks,
+// whether one contains the other (`git merge-base --is-ancestor`), and the subjects of their own commits.
+func CompareBranches(a, b string) (Comparison, error) {
+	return CompareBranchesContext(context.Background(), a, b)
+}
+
+// CompareBranchesContext is like CompareBranches but kills git when ctx is done.
+func CompareBranchesContext(ctx context.Context, a, b string) (Comparison, error) {
+	comparison := Comparison{A: a, B: b}
+
+	var err error
+	if comparison.Ahead, comparison.Behind, err = countDivergence(ctx, a, b); err != nil {
+		return Comparison{}, err
+	}
+	if comparison.AContainsB, err = isAncestor(ctx, b, a); err != nil {
+		return Comparison{}, err
+	}
+	if comparison.BContainsA, err = isAncestor(ctx, a, b); err != nil {
+		return Comparison{}, err
+	}
+	if comparison.OnlyA, err = GetBranchCommitsContext(ctx, BranchRef(a), BranchRef(b), CompareCommitLimit); err != nil {
+		return Comparison{}, err
+	}
+	if comparison.OnlyB, err = GetBranchCommitsContext(ctx, BranchRef(b), BranchRef(a), CompareCommitLimit); err != nil {
+		return Comparison{}, err
+	}
+	return comparison, nil
+}
+
+// countDivergence counts the commits on the local branch a that are not on b, and those on b that are not on a,
+// using `git rev-list --left-right --count a...b`
+func countDivergence(ctx context.Context, a, b string) (onlyA, onlyB int, err error) {
+	output, err := gitCombinedOutput(ctx, "rev-list", "--left-right", "--count", BranchRef(a)+"..."+BranchRef(b))
+
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compare '%s' with '%s': %w", a, b, commandError(output, err))
+	}
+
+	// Output is "<a-only>\t<b-only>"
+	fields := strings.Fields(string(output))
+	if len(fields) == 2 {
+		onlyA, errA := strconv.Atoi(fields[0])
+		onlyB, errB := strconv.Atoi(fields[1])
+		if errA == nil && errB == nil {
+			return onlyA, onlyB, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("unexpected rev-list output comparing '%s' with '%s': %s", a, b, strings.TrimSpace(string(output)))
+}
+
+// isAncestor reports whether the local branch ancestor is an ancestor of the local branch descendant,
+// using `git merge-base --is-ancestor`
+func isAncestor(ctx context.Context, ancestor, descendant string) (bool, error) {
+	output, err := gitCombinedOutput(ctx, "merge-base", "--is-ancestor", BranchRef(ancestor), BranchRef(descendant))
+
+	if err != nil {
+		// Exit code 1 means it isn't an ancestor
+		if isExitCode(err, 1) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check whether '%s' contains '%s': %w", descendant, ancestor, commandError(output, err))
+	}
+
+	return true, nil
+}
@@ -0,0 +1,91 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// AheadBehind holds how far a branch has diverged from a base branch
+type AheadBehind struct {
+	// Ahead is the number of commits on the branch that are not on the base
+	Ahead int
+
+	// Behind is the number of commits on the base that are not on the branch
+	Behind int
+}
+
+// aheadBehindWorkers bounds the number of concurrent `git rev-list` processes
+const aheadBehindWorkers = 8
+
+// GetAheadBehind counts the commits the local branch is ahead of and behind base
+// using `git rev-list --left-right --count base...branch`.
+func GetAheadBehind(branch, base string) (ahead, behind int, err error) {
+	return GetAheadBehindContext(context.Background(), branch, base)
+}
+
+// GetAheadBehindContext is like GetAheadBehind but kills git when ctx is done.
+func GetAheadBehindContext(ctx context.Context, branch, base string) (ahead, behind int, err error) {
+	output, err := gitCombinedOutput(ctx, "rev-list", "--left-right", "--count", base+"..."+BranchRef(branch))
+
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compare '%s' with '%s': %w", branch, base, commandError(output, err))
+	}
+
+	// Output is "<behind>\t<ahead>": left is base-only commits, right is branch-only commits
+	fields := strings.Fields(string(output))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output for '%s': %s", branch, strings.TrimSpace(string(output)))
+	}
+
+	behind, errBehind := strconv.Atoi(fields[0])
+	ahead, errAhead := strconv.Atoi(fields[1])
+	if errBehind != nil || errAhead != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output for '%s': %s", branch, strings.TrimSpace(string(output)))
+	}
+
+	return ahead, behind, nil
+}
+
+// GetAheadBehindCounts computes GetAheadBehind for every branch against base using a small
+// worker pool, so large branch lists don't pay for one sequential subprocess per branch.
+// Branches whose counts cannot be computed are omitted from the result.
+func GetAheadBehindCounts(branches []string, base string) map[string]AheadBehind {
+	return GetAheadBehindCountsContext(context.Background(), branches, base)
+}
+
+// GetAheadBehindCountsContext is like GetAheadBehindCounts but kills the running git commands when ctx is done.
+func GetAheadBehindCountsContext(ctx context.Context, branches []string, base string) map[string]AheadBehind {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		counts = make(map[string]AheadBehind, len(branches))
+		queue  = make(chan string)
+	)
+
+	for range min(aheadBehindWorkers, len(branches)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for branch := range queue {
+				ahead, behind, err := GetAheadBehindContext(ctx, branch, base)
+				if err != nil {
+					continue
+				}
+				mu.Lock()
+				counts[branch] = AheadBehind{Ahead: ahead, Behind: behind}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, branch := range branches {
+		queue <- branch
+	}
+	close(queue)
+	wg.Wait()
+
+	return counts
+}
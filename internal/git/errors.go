@@ -0,0 +1,112 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrNotMerged is wrapped by the error of DeleteBranch when the branch has commits not merged into HEAD
+	// or its upstream, so only ForceDeleteBranch can delete it
+	ErrNotMerged = errors.New("not fully merged")
+
+	// ErrNotFound is wrapped by the errors of DeleteBranch and ForceDeleteBranch when the branch doesn't exist
+	ErrNotFound = errors.New("branch not found")
+
+	// ErrLooksLikeOption is wrapped by the errors of commands given a name starting with a dash, like a branch
+	// created with `git update-ref refs/heads/-d`, where git would take the name for an option and has no `--`
+	// to tell them apart
+	ErrLooksLikeOption = errors.New("starts with a dash, so git would take it for an option")
+
+	// ErrWorktreeMissing is returned by WorktreeStatus when the worktree directory no longer exists,
+	// e.g. because it was removed with rm -rf, so there are no changes to look at
+	ErrWorktreeMissing = errors.New("worktree directory is missing")
+)
+
+// ErrCheckedOutElsewhere is returned by DeleteBranch and ForceDeleteBranch when git refuses to delete a branch
+// checked out in a worktree, e.g. the branch of the main worktree when gelete runs in a linked one
+type ErrCheckedOutElsewhere struct {
+	// Branch is the branch that couldn't be deleted
+	Branch string
+
+	// Path is the worktree the branch is checked out in
+	Path string
+}
+
+// Error returns the branch and the worktree it is checked out in
+func (e *ErrCheckedOutElsewhere) Error() string {
+	return fmt.Sprintf("branch '%s' is checked out in %s", e.Branch, e.Path)
+}
+
+// ErrRefLocked is returned by DeleteBranch and ForceDeleteBranch when git couldn't take a lock because its lock file
+// exists, e.g. the .lock of the branch ref or packed-refs.lock. Another git process, often one an IDE runs in the
+// background, usually holds it for a moment, so the deletion is worth retrying (see RetryOnLock).
+type ErrRefLocked struct {
+	// LockFile is the lock file git found, as named in its message
+	LockFile string
+
+	// message is the message git printed
+	message string
+}
+
+// Error returns the message git printed
+func (e *ErrRefLocked) Error() string {
+	return e.message
+}
+
+// classifiedError is the message git printed for a failed command, classified as one of the errors above
+type classifiedError struct {
+	message string
+	kind    error
+}
+
+// Error returns the message git printed
+func (e *classifiedError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the class of the error, e.g. ErrNotMerged
+func (e *classifiedError) Unwrap() error {
+	return e.kind
+}
+
+// checkedOutPattern matches git refusing to delete a branch checked out in a worktree:
+// "Cannot delete branch 'x' checked out at '/path'", or "cannot delete branch 'x' used by worktree at '/path'"
+// since git 2.42
+var checkedOutPattern = regexp.MustCompile(`(?im)cannot delete branch '(.+)' (?:checked out|used by worktree) at '(.+)'$`)
+
+// lockPattern matches git failing to create a lock file that exists:
+// "Unable to create '/repo/.git/refs/heads/x.lock': File exists."
+var lockPattern = regexp.MustCompile(`(?i)unable to create '(.+?\.lock)': file exists`)
+
+// deleteError classifies the error of `git branch -d` or `git branch -D` as ErrCheckedOutElsewhere, ErrRefLocked,
+// ErrNotMerged or ErrNotFound. Other errors are returned like commandError does.
+func deleteError(output []byte, err error) error {
+	if IsInterrupted(err) {
+		return err
+	}
+
+	message := strings.TrimSpace(string(output))
+	if match := checkedOutPattern.FindStringSubmatch(message); match != nil {
+		return &ErrCheckedOutElsewhere{Branch: match[1], Path: match[2]}
+	}
+	if match := lockPattern.FindStringSubmatch(message); match != nil {
+		return &ErrRefLocked{LockFile: match[1], message: message}
+	}
+
+	lower := strings.ToLower(message)
+	switch {
+	case strings.Contains(lower, "is not fully merged"):
+		return &classifiedError{message: message, kind: ErrNotMerged}
+	case strings.HasPrefix(lower, "error: branch '") && strings.Contains(lower, "' not found"):
+		return &classifiedError{message: message, kind: ErrNotFound}
+	}
+	return errors.New(message)
+}
+
+// IsUnmergedError reports whether a deletion error means the branch has unmerged changes
+func IsUnmergedError(err error) bool {
+	return errors.Is(err, ErrNotMerged)
+}
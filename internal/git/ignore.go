@@ -0,0 +1,31 @@
+package git
+
+// IgnoreRule is a line of a .geleteignore file: branches matching Pattern are ignored, or included again
+// if Negate is set (a line starting with "!")
+type IgnoreRule struct {
+	// Pattern is a glob matched like the protected patterns (see MatchesAnyPattern)
+	Pattern string
+
+	// Negate includes the matching branches again, overriding earlier rules
+	Negate bool
+}
+
+// ignoreRules holds the rules of the ignore files, which hide branches from ListBranches and ListBranchesWithInfo
+var ignoreRules []IgnoreRule
+
+// SetIgnoreRules replaces the rules of the ignore files. Passing an empty list ignores no branch.
+func SetIgnoreRules(rules []IgnoreRule) {
+	ignoreRules = rules
+}
+
+// IsIgnored reports whether the branch is ignored by the rules in effect.
+// Like in .gitignore, the last rule matching the branch decides, so "!" rules can include branches again.
+func IsIgnored(branchName string) bool {
+	ignored := false
+	for _, rule := range ignoreRules {
+		if matchPattern(rule.Pattern, branchName) {
+			ignored = !rule.Negate
+		}
+	}
+	return ignored
+}
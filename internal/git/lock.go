@@ -0,0 +1,50 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// LockRetryDelays are the waits before retrying a deletion that failed with ErrRefLocked, one per retry.
+// Locks held by other git processes are usually released within a second.
+var LockRetryDelays = []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 900 * time.Millisecond}
+
+// NextLockRetry returns how long to wait before retrying a deletion that failed with err for the
+// attempt-th time (counting from 1). It returns false if err isn't an ErrRefLocked or the deletion
+// was already retried after each of LockRetryDelays.
+func NextLockRetry(err error, attempt int) (time.Duration, bool) {
+	var locked *ErrRefLocked
+	if !errors.As(err, &locked) || attempt < 1 || attempt > len(LockRetryDelays) {
+		return 0, false
+	}
+	return LockRetryDelays[attempt-1], true
+}
+
+// RetryOnLock deletes a branch with deleteBranch (e.g. DeleteBranchContext or ForceDeleteBranchContext),
+// retrying as long as NextLockRetry allows while it fails with ErrRefLocked. waiting, unless nil, is called
+// with the lock before each wait. Returns the error of the last attempt, which is the lock error if ctx
+// is done while waiting.
+func RetryOnLock(ctx context.Context, branch string, deleteBranch func(context.Context, string) error,
+	waiting func(*ErrRefLocked)) error {
+	for attempt := 1; ; attempt++ {
+		err := deleteBranch(ctx, branch)
+		delay, retry := NextLockRetry(err, attempt)
+		if !retry {
+			return err
+		}
+
+		if waiting != nil {
+			var locked *ErrRefLocked
+			errors.As(err, &locked)
+			waiting(locked)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
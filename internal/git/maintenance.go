@@ -0,0 +1,133 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExpireReflog drops the reflog entries of commits no longer reachable from any ref
+// (git reflog expire --expire-unreachable=now --all), so git gc can prune the commits of deleted branches.
+// Those commits can't be recovered from the reflog afterwards.
+func ExpireReflog() error {
+	return ExpireReflogContext(context.Background())
+}
+
+// ExpireReflogContext is like ExpireReflog but kills git when ctx is done.
+func ExpireReflogContext(ctx context.Context) error {
+	output, err := gitCombinedOutput(ctx, "reflog", "expire", "--expire-unreachable=now", "--all")
+
+	if err != nil {
+		return fmt.Errorf("failed to expire the reflog: %w", commandError(output, err))
+	}
+
+	return nil
+}
+
+// GarbageCollect packs the repository and prunes unreachable objects right away (git gc --prune=now --quiet).
+// Repacking a big repository takes longer than a single git command usually may, so Timeout doesn't apply.
+func GarbageCollect() error {
+	return GarbageCollectContext(context.Background())
+}
+
+// GarbageCollectContext is like GarbageCollect but kills git when ctx is done.
+func GarbageCollectContext(ctx context.Context) error {
+	output, err := gitCombinedOutput(withoutTimeout(ctx), "gc", "--prune=now", "--quiet")
+
+	if err != nil {
+		return fmt.Errorf("failed to run git gc: %w", commandError(output, err))
+	}
+
+	return nil
+}
+
+// ObjectStats is the disk space used by the object database, as reported by git count-objects -v
+type ObjectStats struct {
+	// Bytes is the size of the loose objects, packs and garbage files
+	Bytes int64
+}
+
+// CountObjects returns the disk space used by the object database
+func CountObjects() (ObjectStats, error) {
+	return CountObjectsContext(context.Background())
+}
+
+// CountObjectsContext is like CountObjects but kills git when ctx is done.
+func CountObjectsContext(ctx context.Context) (ObjectStats, error) {
+	output, err := gitCombinedOutput(ctx, "count-objects", "-v")
+
+	if err != nil {
+		return ObjectStats{}, fmt.Errorf("failed to count objects: %w", commandError(output, err))
+	}
+
+	return parseObjectStats(string(output)), nil
+}
+
+// parseObjectStats adds up the sizes in the output of git count-objects -v, which are given in KiB
+func parseObjectStats(output string) ObjectStats {
+	var stats ObjectStats
+	for _, line := range splitLines(output) {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "size", "size-pack", "size-garbage":
+			kib, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err == nil {
+				stats.Bytes += kib * 1024
+			}
+		}
+	}
+	return stats
+}
+
+// CompactionReport is the outcome of CompactRepository
+type CompactionReport struct {
+	// Duration is how long git gc took
+	Duration time.Duration
+
+	// Before and After are the sizes of the object database around the compaction.
+	// SizeKnown is false if git count-objects failed, leaving them zero.
+	Before, After ObjectStats
+	SizeKnown     bool
+}
+
+// Reclaimed returns the disk space freed by the compaction, or 0 if it is unknown or nothing was freed
+func (r CompactionReport) Reclaimed() int64 {
+	if !r.SizeKnown {
+		return 0
+	}
+	return max(0, r.Before.Bytes-r.After.Bytes)
+}
+
+// CompactRepository expires the reflog and runs git gc, so the objects of deleted branches are removed
+// from disk. The commits of deleted branches become unrecoverable.
+// The space reclaimed is measured with git count-objects, on a best-effort basis.
+func CompactRepository() (CompactionReport, error) {
+	return CompactRepositoryContext(context.Background())
+}
+
+// CompactRepositoryContext is like CompactRepository but kills git when ctx is done.
+func CompactRepositoryContext(ctx context.Context) (CompactionReport, error) {
+	var report CompactionReport
+	before, beforeErr := CountObjectsContext(ctx)
+
+	if err := ExpireReflogContext(ctx); err != nil {
+		return report, err
+	}
+
+	start := time.Now()
+	if err := GarbageCollectContext(ctx); err != nil {
+		return report, err
+	}
+	report.Duration = time.Since(start)
+
+	after, afterErr := CountObjectsContext(ctx)
+	if beforeErr == nil && afterErr == nil {
+		report.Before, report.After, report.SizeKnown = before, after, true
+	}
+	return report, nil
+}
@@ -0,0 +1,116 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// summaryWorkers bounds the number of repositories summarized concurrently
+const summaryWorkers = 8
+
+// RepositorySummary holds the deletable branches of a repository found by DiscoverRepositories
+type RepositorySummary struct {
+	// Dir is the directory of the repository
+	Dir string
+
+	// Branches is the number of branches ListBranches returns in the repository
+	Branches int
+
+	// Gone is how many of them track an upstream that no longer exists
+	Gone int
+
+	// Err is set if the repository couldn't be read, e.g. because it is corrupt; the counts are zero then
+	Err error
+}
+
+// DiscoverRepositories returns the git repositories directly inside dir, in name order: the subdirectories
+// that contain a .git directory, or a .git file like linked worktrees and submodules. Deeper directories are
+// not searched.
+func DiscoverRepositories(dir string) ([]string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve '%s': %w", dir, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %w", dir, err)
+	}
+
+	var repositories []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			continue
+		}
+		if _, err := os.Lstat(filepath.Join(path, ".git")); err == nil {
+			repositories = append(repositories, path)
+		}
+	}
+	return repositories, nil
+}
+
+// SummarizeRepositories counts the deletable and gone branches of each repository, with the protected
+// patterns and ignore rules in effect. The repositories are read concurrently by a small worker pool, and
+// one that can't be read gets an Err instead of failing the others. Summaries are in the order of dirs.
+func SummarizeRepositories(dirs []string) []RepositorySummary {
+	return SummarizeRepositoriesContext(context.Background(), dirs)
+}
+
+// SummarizeRepositoriesContext is like SummarizeRepositories but kills the running git commands when ctx is done.
+func SummarizeRepositoriesContext(ctx context.Context, dirs []string) []RepositorySummary {
+	var (
+		wg        sync.WaitGroup
+		summaries = make([]RepositorySummary, len(dirs))
+		queue     = make(chan int)
+	)
+
+	for range min(summaryWorkers, len(dirs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				summaries[i] = summarizeRepository(ctx, dirs[i])
+			}
+		}()
+	}
+
+	for i := range dirs {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	return summaries
+}
+
+// summarizeRepository counts the deletable and gone branches of the repository in dir
+func summarizeRepository(ctx context.Context, dir string) RepositorySummary {
+	summary := RepositorySummary{Dir: dir}
+
+	root, err := FindRepositoryRootContext(ctx, dir)
+	if err != nil {
+		summary.Err = err
+		return summary
+	}
+	// With a broken .git, git finds the repository around dir instead, if there is one
+	if !SamePath(root, dir) {
+		summary.Err = fmt.Errorf("'%s' is not the top of a git repository", dir)
+		return summary
+	}
+	branches, err := ListBranchesWithInfoContext(WithDir(ctx, root))
+	if err != nil {
+		summary.Err = err
+		return summary
+	}
+
+	summary.Branches = len(branches)
+	for _, branch := range branches {
+		if branch.Gone {
+			summary.Gone++
+		}
+	}
+	return summary
+}
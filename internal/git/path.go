@@ -0,0 +1,34 @@
+package git
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// CanonicalPath returns path in the form paths are compared in: cleaned, with the separators of the OS and
+// symlinks resolved when it exists. Git reports paths with forward slashes even on Windows (C:/repo/wt),
+// so paths from git output and from the file system only compare equal once both are canonical.
+func CanonicalPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(filepath.FromSlash(path))
+}
+
+// SamePath reports whether a and b are the same path once canonical. File systems on Windows ignore case,
+// so there the comparison does too.
+func SamePath(a, b string) bool {
+	return EqualPaths(a, b, caseInsensitivePaths)
+}
+
+// EqualPaths is like SamePath, with caseInsensitive choosing whether case is ignored whatever the OS
+func EqualPaths(a, b string, caseInsensitive bool) bool {
+	a, b = CanonicalPath(a), CanonicalPath(b)
+	if caseInsensitive {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
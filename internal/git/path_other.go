@@ -0,0 +1,6 @@
+//go:build !windows
+
+package git
+
+// caseInsensitivePaths is set on Windows, whose file systems ignore the case of paths
+const caseInsensitivePaths = false
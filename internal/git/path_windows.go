@@ -0,0 +1,4 @@
+package git
+
+// caseInsensitivePaths is set on Windows, whose file systems ignore the case of paths
+const caseInsensitivePaths = true
@@ -0,0 +1,82 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// MatchesAnyPattern reports whether the branch matches any of the given patterns.
+// Patterns use path.Match glob syntax, so `*` does not cross `/`: "release/*" matches
+// "release/1.0" but neither "release" nor "release/1.0/hotfix".
+// Malformed patterns only match the branch with exactly the same name.
+func MatchesAnyPattern(branchName string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(pattern, branchName) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesPattern reports whether the branch matches a single glob pattern, like MatchesAnyPattern
+func MatchesPattern(branchName, pattern string) bool {
+	return matchPattern(pattern, branchName)
+}
+
+// ValidatePattern returns an error if the glob pattern is empty or malformed, e.g. "release/[0-9",
+// for callers that refuse such patterns instead of matching them literally
+func ValidatePattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("pattern must not be empty")
+	}
+	if _, err := path.Match(strings.TrimSpace(pattern), ""); err != nil {
+		return fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+	}
+	return nil
+}
+
+// matchPattern reports whether the branch matches a single glob pattern
+func matchPattern(pattern, branchName string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return false
+	}
+
+	matched, err := path.Match(pattern, branchName)
+	if err != nil {
+		return pattern == branchName
+	}
+	return matched
+}
+
+// FilterBranches returns the branches matching any of the patterns, in their original order,
+// along with the patterns that matched no branch. Protected and ignored branches never match.
+// Pass a list from ListBranches so the current branch is never matched either.
+func FilterBranches(branches []string, patterns []string) (matched []string, unmatched []string) {
+	used := make(map[string]bool, len(patterns))
+	for _, branch := range branches {
+		if IsProtected(branch) || IsIgnored(branch) {
+			continue
+		}
+
+		isMatch := false
+		for _, pattern := range patterns {
+			if matchPattern(pattern, branch) {
+				used[pattern] = true
+				isMatch = true
+			}
+		}
+		if isMatch {
+			matched = append(matched, branch)
+		}
+	}
+
+	for _, pattern := range patterns {
+		if !used[pattern] {
+			unmatched = append(unmatched, pattern)
+		}
+	}
+
+	return matched, unmatched
+}
@@ -0,0 +1,60 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DefaultProtectedBranches are excluded from deletion unless protection is disabled
+var DefaultProtectedBranches = []string{"main", "master", "develop"}
+
+// ProtectConfigKey is the multi-valued git config key holding additional protected branch patterns
+const ProtectConfigKey = "gelete.protect"
+
+// protectedPatterns holds the patterns excluded by ListBranches and ListBranchesWithInfo
+var protectedPatterns = DefaultProtectedBranches
+
+// SetProtectedPatterns replaces the protected branch patterns.
+// Passing an empty list disables branch protection.
+func SetProtectedPatterns(patterns []string) {
+	protectedPatterns = patterns
+}
+
+// ProtectedPatterns returns the protected branch patterns currently in effect
+func ProtectedPatterns() []string {
+	return protectedPatterns
+}
+
+// IsProtected reports whether the branch matches one of the protected patterns in effect
+func IsProtected(branchName string) bool {
+	return MatchesAnyPattern(branchName, protectedPatterns)
+}
+
+// ConfiguredProtectedPatterns returns the patterns set with `git config --add gelete.protect <pattern>`.
+// Returns an empty list if the key is not set.
+func ConfiguredProtectedPatterns() ([]string, error) {
+	return ConfiguredProtectedPatternsContext(context.Background())
+}
+
+// ConfiguredProtectedPatternsContext is like ConfiguredProtectedPatterns but kills git when ctx is done.
+func ConfiguredProtectedPatternsContext(ctx context.Context) ([]string, error) {
+	output, err := gitCombinedOutput(ctx, "config", "--get-all", ProtectConfigKey)
+
+	if err != nil {
+		// Exit code 1 means the key is not set
+		if isExitCode(err, 1) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read '%s' config: %w", ProtectConfigKey, commandError(output, err))
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if pattern := strings.TrimSpace(line); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return patterns, nil
+}
@@ -0,0 +1,94 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// StaleTrackingRef is a remote-tracking ref whose branch no longer exists on its remote,
+// as reported by `git remote prune --dry-run`
+type StaleTrackingRef struct {
+	// Remote is the remote the ref tracks a branch of, e.g. origin
+	Remote string
+
+	// Ref is the short name of the remote-tracking ref, e.g. origin/feature-x
+	Ref string
+}
+
+// ListRemotes returns the names of the remotes of the repository, e.g. origin
+func ListRemotes() ([]string, error) {
+	return ListRemotesContext(context.Background())
+}
+
+// ListRemotesContext is like ListRemotes but kills git when ctx is done.
+func ListRemotesContext(ctx context.Context) ([]string, error) {
+	output, err := gitCombinedOutput(ctx, "remote")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remotes: %w", commandError(output, err))
+	}
+
+	return strings.Fields(string(output)), nil
+}
+
+// ListStaleTrackingRefs returns the remote-tracking refs of the given remotes whose branch was deleted on the
+// remote, using `git remote prune --dry-run`, which contacts each remote. Nothing is pruned.
+func ListStaleTrackingRefs(remotes ...string) ([]StaleTrackingRef, error) {
+	return ListStaleTrackingRefsContext(context.Background(), remotes...)
+}
+
+// ListStaleTrackingRefsContext is like ListStaleTrackingRefs but kills git when ctx is done.
+func ListStaleTrackingRefsContext(ctx context.Context, remotes ...string) ([]StaleTrackingRef, error) {
+	args := append([]string{"remote", "prune", "--dry-run"}, remotes...)
+	// The markers ParseRemotePrune matches are translated, so git runs in the C locale
+	output, err := runGit(ctx, Command{Args: args, Env: []string{"LC_ALL=C"}})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stale remote-tracking refs of %s: %w", strings.Join(remotes, ", "),
+			commandError(failureDetail(output, err), err))
+	}
+
+	return ParseRemotePrune(string(output)), nil
+}
+
+// ParseRemotePrune parses the output of `git remote prune --dry-run`, which lists the refs it would prune
+// under a header per remote:
+//
+//	Pruning origin
+//	URL: git@github.com:owner/repo.git
+//	 * [would prune] origin/feature-x
+//
+// Other lines are ignored. The markers are matched in English, so the output must not be localized.
+func ParseRemotePrune(output string) []StaleTrackingRef {
+	var refs []StaleTrackingRef
+	remote := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if name, ok := strings.CutPrefix(line, "Pruning "); ok {
+			remote = name
+			continue
+		}
+		if ref, ok := strings.CutPrefix(line, "* [would prune] "); ok && ref != "" {
+			refs = append(refs, StaleTrackingRef{Remote: remote, Ref: ref})
+		}
+	}
+	return refs
+}
+
+// DeleteRemoteTrackingRef deletes a remote-tracking ref such as origin/feature-x using `git branch -d -r`.
+// Only the local ref is deleted; the remote isn't contacted.
+func DeleteRemoteTrackingRef(ref string) error {
+	return DeleteRemoteTrackingRefContext(context.Background(), ref)
+}
+
+// DeleteRemoteTrackingRefContext is like DeleteRemoteTrackingRef but kills git when ctx is done.
+func DeleteRemoteTrackingRefContext(ctx context.Context, ref string) error {
+	output, err := gitCombinedOutput(ctx, "branch", "-d", "-r", "--", ref)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete remote-tracking ref '%s': %w", ref, commandError(output, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package git
+
+import "context"
+
+// Repository is a git repository given by its directory. Its methods mirror the package-level functions,
+// but always run git in Dir, so several repositories can be used side by side without changing directory.
+// The zero Repository is the default repository the package-level functions use: the one opened with
+// OpenRepository, or the process working directory.
+type Repository struct {
+	// Dir is the absolute directory git runs in; empty uses the default repository
+	Dir string
+
+	// Runner runs git in the repository; nil uses DefaultRunner
+	Runner Runner
+}
+
+// NewRepository validates the repository containing dir and returns it, running git from its top-level
+// directory (or its git directory if it is bare). Relative paths and symlinks are resolved first.
+func NewRepository(dir string) (Repository, error) {
+	root, err := FindRepositoryRoot(dir)
+	if err != nil {
+		return Repository{}, err
+	}
+	return Repository{Dir: root}, nil
+}
+
+// Context returns ctx set up to run git in the repository, for the Context variants of the package-level functions
+func (r Repository) Context(ctx context.Context) context.Context {
+	if r.Runner != nil {
+		ctx = WithRunner(ctx, r.Runner)
+	}
+	return WithDir(ctx, r.Dir)
+}
+
+// background returns a context running git in the repository without a deadline of its own
+func (r Repository) background() context.Context {
+	return r.Context(context.Background())
+}
+
+// ValidateRepository is like the package-level ValidateRepository, in the repository
+func (r Repository) ValidateRepository() error {
+	return ValidateRepositoryContext(r.background())
+}
+
+// GetCurrentBranch is like the package-level GetCurrentBranch, in the repository
+func (r Repository) GetCurrentBranch() (string, error) {
+	return GetCurrentBranchContext(r.background())
+}
+
+// GetHeadBranch is like the package-level GetHeadBranch, in the repository
+func (r Repository) GetHeadBranch() (branch string, detached bool, err error) {
+	return GetHeadBranchContext(r.background())
+}
+
+// UserEmail is like the package-level UserEmail, in the repository
+func (r Repository) UserEmail() (string, error) {
+	return UserEmailContext(r.background())
+}
+
+// HasCommits is like the package-level HasCommits, in the repository
+func (r Repository) HasCommits() (bool, error) {
+	return HasCommitsContext(r.background())
+}
+
+// GetGitDir is like the package-level GetGitDir, in the repository
+func (r Repository) GetGitDir() (string, error) {
+	return GetGitDirContext(r.background())
+}
+
+// GetCommonDir is like the package-level GetCommonDir, in the repository
+func (r Repository) GetCommonDir() (string, error) {
+	return GetCommonDirContext(r.background())
+}
+
+// GetSuperproject is like the package-level GetSuperproject, in the repository
+func (r Repository) GetSuperproject() (string, error) {
+	return GetSuperprojectContext(r.background())
+}
+
+// ListBranches is like the package-level ListBranches, in the repository
+func (r Repository) ListBranches() ([]string, error) {
+	return ListBranchesContext(r.background())
+}
+
+// ListDeletableBranches is like the package-level ListDeletableBranches, in the repository
+func (r Repository) ListDeletableBranches() ([]DeletableBranch, error) {
+	return ListDeletableBranchesContext(r.background())
+}
+
+// ListRemoteBranches is like the package-level ListRemoteBranches, in the repository
+func (r Repository) ListRemoteBranches() ([]string, error) {
+	return ListRemoteBranchesContext(r.background())
+}
+
+// ListGoneBranches is like the package-level ListGoneBranches, in the repository
+func (r Repository) ListGoneBranches() ([]string, error) {
+	return ListGoneBranchesContext(r.background())
+}
+
+// ListUpstreams is like the package-level ListUpstreams, in the repository
+func (r Repository) ListUpstreams() (map[string]Upstream, error) {
+	return ListUpstreamsContext(r.background())
+}
+
+// ListBranchesWithInfo is like the package-level ListBranchesWithInfo, in the repository
+func (r Repository) ListBranchesWithInfo() ([]BranchInfo, error) {
+	return ListBranchesWithInfoContext(r.background())
+}
+
+// GetDefaultBranch is like the package-level GetDefaultBranch, in the repository
+func (r Repository) GetDefaultBranch() (string, error) {
+	return GetDefaultBranchContext(r.background())
+}
+
+// BranchExists is like the package-level BranchExists, in the repository
+func (r Repository) BranchExists(branchName string) bool {
+	return BranchExistsContext(r.background(), branchName)
+}
+
+// GetBranchSHA is like the package-level GetBranchSHA, in the repository
+func (r Repository) GetBranchSHA(branchName string) (string, error) {
+	return GetBranchSHAContext(r.background(), branchName)
+}
+
+// GetBranchCommits is like the package-level GetBranchCommits, in the repository
+func (r Repository) GetBranchCommits(branch, base string, limit int) ([]string, error) {
+	return GetBranchCommitsContext(r.background(), branch, base, limit)
+}
+
+// GetBranchDiffStat is like the package-level GetBranchDiffStat, in the repository
+func (r Repository) GetBranchDiffStat(branch, base string) (string, error) {
+	return GetBranchDiffStatContext(r.background(), branch, base)
+}
+
+// GetMergedBranches is like the package-level GetMergedBranches, in the repository
+func (r Repository) GetMergedBranches(base string) (map[string]bool, error) {
+	return GetMergedBranchesContext(r.background(), base)
+}
+
+// DeleteBranch is like the package-level DeleteBranch, in the repository
+func (r Repository) DeleteBranch(branchName string) error {
+	return DeleteBranchContext(r.background(), branchName)
+}
+
+// ForceDeleteBranch is like the package-level ForceDeleteBranch, in the repository
+func (r Repository) ForceDeleteBranch(branchName string) error {
+	return ForceDeleteBranchContext(r.background(), branchName)
+}
+
+// RestoreBranch is like the package-level RestoreBranch, in the repository
+func (r Repository) RestoreBranch(branchName, sha string) error {
+	return RestoreBranchContext(r.background(), branchName, sha)
+}
+
+// RenameBranch is like the package-level RenameBranch, in the repository
+func (r Repository) RenameBranch(oldName, newName string) error {
+	return RenameBranchContext(r.background(), oldName, newName)
+}
+
+// IsBranchMerged is like the package-level IsBranchMerged, in the repository
+func (r Repository) IsBranchMerged(branchName string) (bool, error) {
+	return IsBranchMergedContext(r.background(), branchName)
+}
+
+// IsTipReachableElsewhere is like the package-level IsTipReachableElsewhere, in the repository
+func (r Repository) IsTipReachableElsewhere(branch string) (bool, error) {
+	return IsTipReachableElsewhereContext(r.background(), branch)
+}
+
+// DeleteRemoteBranch is like the package-level DeleteRemoteBranch, in the repository
+func (r Repository) DeleteRemoteBranch(remote, branch string) error {
+	return DeleteRemoteBranchContext(r.background(), remote, branch)
+}
+
+// CheckoutBranch is like the package-level CheckoutBranch, in the repository
+func (r Repository) CheckoutBranch(name string) error {
+	return CheckoutBranchContext(r.background(), name)
+}
+
+// ListWorktrees is like the package-level ListWorktrees, in the repository
+func (r Repository) ListWorktrees() ([]Worktree, error) {
+	return ListWorktreesContext(r.background())
+}
+
+// GetWorktreeForBranch is like the package-level GetWorktreeForBranch, in the repository
+func (r Repository) GetWorktreeForBranch(branchName string) (*Worktree, error) {
+	return GetWorktreeForBranchContext(r.background(), branchName)
+}
+
+// RemoveWorktree is like the package-level RemoveWorktree, in the repository
+func (r Repository) RemoveWorktree(worktreePath string) error {
+	return RemoveWorktreeContext(r.background(), worktreePath)
+}
+
+// ForceRemoveWorktree is like the package-level ForceRemoveWorktree, in the repository
+func (r Repository) ForceRemoveWorktree(worktreePath string) error {
+	return ForceRemoveWorktreeContext(r.background(), worktreePath)
+}
+
+// PruneWorktrees is like the package-level PruneWorktrees, in the repository
+func (r Repository) PruneWorktrees() error {
+	return PruneWorktreesContext(r.background())
+}
+
+// ExpireReflog is like the package-level ExpireReflog, in the repository
+func (r Repository) ExpireReflog() error {
+	return ExpireReflogContext(r.background())
+}
+
+// GarbageCollect is like the package-level GarbageCollect, in the repository
+func (r Repository) GarbageCollect() error {
+	return GarbageCollectContext(r.background())
+}
+
+// CompactRepository is like the package-level CompactRepository, in the repository
+func (r Repository) CompactRepository() (CompactionReport, error) {
+	return CompactRepositoryContext(r.background())
+}
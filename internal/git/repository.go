@@ -1,16 +1,103 @@
 package git
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
-// ValidateRepository checks if the current directory is a valid git repository.
+// repoRoot is the directory git commands run in; empty runs them in the process working directory
+var repoRoot string
+
+// RepoRoot returns the directory git commands run in: the top-level directory of the repository
+// opened with OpenRepository, or its git directory if it is bare. Empty if no repository was opened.
+func RepoRoot() string {
+	return repoRoot
+}
+
+// OpenRepository validates the repository containing dir (the working directory if empty) and
+// runs all later git commands from its root, so they don't depend on the process working directory.
+func OpenRepository(dir string) error {
+	return OpenRepositoryContext(context.Background(), dir)
+}
+
+// OpenRepositoryContext is like OpenRepository but kills git when ctx is done.
+func OpenRepositoryContext(ctx context.Context, dir string) error {
+	root, err := FindRepositoryRootContext(ctx, dir)
+	if err != nil {
+		return err
+	}
+
+	repoRoot = root
+	return nil
+}
+
+// FindRepositoryRoot validates the repository containing dir (the working directory if empty) and returns
+// its top-level directory, or its git directory if it is bare. Relative paths and symlinks are resolved to an
+// absolute path first. Unlike OpenRepository, it doesn't change where later git commands run.
+func FindRepositoryRoot(dir string) (string, error) {
+	return FindRepositoryRootContext(context.Background(), dir)
+}
+
+// FindRepositoryRootContext is like FindRepositoryRoot but kills git when ctx is done.
+func FindRepositoryRootContext(ctx context.Context, dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve repository path: %w", err)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("repository path '%s' is not a directory", dir)
+	}
+	// Resolve symlinks up front, so the directory git runs in is the same however the path was given
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		return "", fmt.Errorf("failed to resolve repository path: %w", err)
+	}
+
+	return resolveRoot(WithDir(ctx, dir))
+}
+
+// resolveRoot returns the top-level directory of the repository git commands run in,
+// falling back to the git directory for bare repositories, which have no working tree
+func resolveRoot(ctx context.Context) (string, error) {
+	if err := ValidateRepositoryContext(ctx); err != nil {
+		return "", err
+	}
+
+	root, err := GetTopLevelContext(ctx)
+	if err == nil {
+		return root, nil
+	}
+	if IsInterrupted(err) {
+		return "", err
+	}
+	return GetGitDirContext(ctx)
+}
+
+// UseRepositoryRoot runs all later git commands from root, which must have been found with
+// FindRepositoryRoot. It is OpenRepository without validating the repository again.
+func UseRepositoryRoot(root string) {
+	repoRoot = root
+}
+
+// CloseRepository forgets the repository opened with OpenRepository;
+// later git commands run in the process working directory again.
+func CloseRepository() {
+	repoRoot = ""
+}
+
+// ValidateRepository checks if git commands run in a valid git repository (see OpenRepository).
 // Returns an error if not in a git repository or if git is not installed.
 func ValidateRepository() error {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
-	output, err := cmd.CombinedOutput()
+	return ValidateRepositoryContext(context.Background())
+}
+
+// ValidateRepositoryContext is like ValidateRepository but kills git when ctx is done.
+func ValidateRepositoryContext(ctx context.Context) error {
+	output, err := gitCombinedOutput(ctx, "rev-parse", "--git-dir")
 
 	if err != nil {
 		// Check if git command is not found
@@ -24,29 +111,164 @@ func ValidateRepository() error {
 			return fmt.Errorf("not a git repository. Run gelete from within a git repository")
 		}
 
-		// Other git error
-		return fmt.Errorf("git error: %s", outputStr)
+		// Other git error, including a timeout
+		return fmt.Errorf("git error: %w", commandError(output, err))
 	}
 
 	return nil
 }
 
 // GetCurrentBranch returns the name of the currently checked-out branch.
-// Returns "HEAD" if in detached HEAD state.
+// Returns "HEAD" if in detached HEAD state; use GetHeadBranch to tell the two apart.
 func GetCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "branch", "--show-current")
-	output, err := cmd.Output()
+	return GetCurrentBranchContext(context.Background())
+}
 
+// GetCurrentBranchContext is like GetCurrentBranch but kills git when ctx is done.
+func GetCurrentBranchContext(ctx context.Context) (string, error) {
+	branch, detached, err := GetHeadBranchContext(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to get current branch: %w", err)
+		return "", err
 	}
 
-	branch := strings.TrimSpace(string(output))
-
-	// Handle detached HEAD state (empty output)
-	if branch == "" {
+	if detached {
 		return "HEAD", nil
 	}
 
 	return branch, nil
 }
+
+// GetHeadBranch returns the currently checked-out branch and whether HEAD is detached.
+// In detached HEAD state no branch is checked out and the returned branch is empty.
+func GetHeadBranch() (branch string, detached bool, err error) {
+	return GetHeadBranchContext(context.Background())
+}
+
+// GetHeadBranchContext is like GetHeadBranch but kills git when ctx is done.
+func GetHeadBranchContext(ctx context.Context) (branch string, detached bool, err error) {
+	output, err := gitOutput(ctx, "branch", "--show-current")
+
+	if err != nil {
+		if IsInterrupted(err) {
+			return "", false, fmt.Errorf("failed to get current branch: %w", err)
+		}
+		// git before 2.22 has no --show-current
+		return headBranchFromSymbolicRef(ctx)
+	}
+
+	branch = strings.TrimSpace(string(output))
+
+	// Detached HEAD state prints nothing
+	return branch, branch == "", nil
+}
+
+// headBranchFromSymbolicRef is GetHeadBranch for git versions without `git branch --show-current`:
+// `git symbolic-ref --quiet` fails with exit code 1 and no output when HEAD is detached
+func headBranchFromSymbolicRef(ctx context.Context) (branch string, detached bool, err error) {
+	output, err := gitCombinedOutput(ctx, "symbolic-ref", "--quiet", "--short", "HEAD")
+
+	if isExitCode(err, 1) && len(bytes.TrimSpace(output)) == 0 {
+		return "", true, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get current branch: %w", commandError(output, err))
+	}
+
+	return strings.TrimSpace(string(output)), false, nil
+}
+
+// HasCommits reports whether HEAD points to a commit. It doesn't in a repository without commits, right
+// after git init, where HEAD names an unborn branch that doesn't exist yet.
+func HasCommits() (bool, error) {
+	return HasCommitsContext(context.Background())
+}
+
+// HasCommitsContext is like HasCommits but kills git when ctx is done.
+func HasCommitsContext(ctx context.Context) (bool, error) {
+	output, err := gitCombinedOutput(ctx, "rev-parse", "--verify", "--quiet", "HEAD")
+
+	// `git rev-parse --verify --quiet` fails with exit code 1 and no output when HEAD is unborn
+	if isExitCode(err, 1) && len(bytes.TrimSpace(output)) == 0 {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve HEAD: %w", commandError(output, err))
+	}
+	return true, nil
+}
+
+// GetGitDir returns the absolute path of the repository's git directory (usually <root>/.git).
+func GetGitDir() (string, error) {
+	return GetGitDirContext(context.Background())
+}
+
+// GetGitDirContext is like GetGitDir but kills git when ctx is done.
+func GetGitDirContext(ctx context.Context) (string, error) {
+	output, err := gitCombinedOutput(ctx, "rev-parse", "--absolute-git-dir")
+
+	if err != nil {
+		return "", fmt.Errorf("failed to locate git directory: %w", commandError(output, err))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetCommonDir returns the absolute path of the git directory shared by all worktrees of the repository.
+// It differs from GetGitDir in linked worktrees, which have a git directory of their own.
+func GetCommonDir() (string, error) {
+	return GetCommonDirContext(context.Background())
+}
+
+// GetCommonDirContext is like GetCommonDir but kills git when ctx is done.
+func GetCommonDirContext(ctx context.Context) (string, error) {
+	// --path-format=absolute needs git 2.31, so relative paths are resolved here instead
+	output, err := gitCombinedOutput(ctx, "rev-parse", "--git-common-dir")
+
+	if err != nil {
+		return "", fmt.Errorf("failed to locate git directory: %w", commandError(output, err))
+	}
+
+	dir := strings.TrimSpace(string(output))
+	if filepath.IsAbs(dir) {
+		return filepath.Clean(dir), nil
+	}
+	base, err := filepath.Abs(commandDir(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to locate git directory: %w", err)
+	}
+	return filepath.Join(base, dir), nil
+}
+
+// GetTopLevel returns the absolute path of the top-level directory of the working tree.
+// Returns an error in bare repositories, which have no working tree.
+func GetTopLevel() (string, error) {
+	return GetTopLevelContext(context.Background())
+}
+
+// GetTopLevelContext is like GetTopLevel but kills git when ctx is done.
+func GetTopLevelContext(ctx context.Context) (string, error) {
+	output, err := gitCombinedOutput(ctx, "rev-parse", "--show-toplevel")
+
+	if err != nil {
+		return "", fmt.Errorf("failed to locate repository root: %w", commandError(output, err))
+	}
+
+	return CanonicalPath(strings.TrimSpace(string(output))), nil
+}
+
+// GetSuperproject returns the absolute path of the top-level directory of the superproject when the repository
+// is a submodule checked out in it, or an empty string otherwise. Git commands still run in the submodule.
+func GetSuperproject() (string, error) {
+	return GetSuperprojectContext(context.Background())
+}
+
+// GetSuperprojectContext is like GetSuperproject but kills git when ctx is done.
+func GetSuperprojectContext(ctx context.Context) (string, error) {
+	output, err := gitCombinedOutput(ctx, "rev-parse", "--show-superproject-working-tree")
+
+	if err != nil {
+		return "", fmt.Errorf("failed to locate superproject: %w", commandError(output, err))
+	}
+
+	return CanonicalPath(strings.TrimSpace(string(output))), nil
+}
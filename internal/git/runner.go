@@ -0,0 +1,114 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// waitDelay is how long to wait for a killed git process to release its output pipes.
+// Helpers spawned by git (e.g. credential helpers or ssh) may keep them open after git is killed.
+const waitDelay = time.Second
+
+// Command is a git command for a Runner to run
+type Command struct {
+	// Dir is the directory git runs in
+	Dir string
+
+	// Args are the arguments given to git, e.g. ["branch", "-d", "--", "feature-x"]
+	Args []string
+
+	// Stdin is fed to git's standard input; nil gives it none
+	Stdin []byte
+
+	// Env is added to the environment git inherits, e.g. ["LC_ALL=C"]
+	Env []string
+
+	// Combined returns git's standard output and error interleaved as the standard output, e.g. to show
+	// its message when it fails; the standard error returned is then empty
+	Combined bool
+}
+
+// Runner runs git commands. Every function of the package runs git through the Runner of its context
+// (see WithRunner), or DefaultRunner, so tests can answer with canned output instead of a real repository.
+type Runner interface {
+	// Run runs the command until it exits or ctx is done, returning what git wrote to its standard output
+	// and error. If git exits with a non-zero code, err is an *ExitError.
+	Run(ctx context.Context, cmd Command) (stdout, stderr []byte, err error)
+}
+
+// ExitError is returned by a Runner when git exits with a non-zero code
+type ExitError struct {
+	// Code is the exit code, or -1 if git was killed by a signal
+	Code int
+
+	// Stderr is what git wrote to its standard error, unless the command was Combined
+	Stderr []byte
+}
+
+// Error returns the exit status like os/exec does, e.g. "exit status 128"
+func (e *ExitError) Error() string {
+	if e.Code < 0 {
+		return "signal: killed"
+	}
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// isExitCode reports whether err means git exited with the given code
+func isExitCode(err error, code int) bool {
+	var exitErr *ExitError
+	return errors.As(err, &exitErr) && exitErr.Code == code
+}
+
+// ExecRunner runs the git executable found in PATH
+type ExecRunner struct{}
+
+// Run runs git as a child process, killed when ctx is done
+func (ExecRunner) Run(ctx context.Context, cmd Command) (stdout, stderr []byte, err error) {
+	c := exec.CommandContext(ctx, "git", cmd.Args...)
+	c.Dir = cmd.Dir
+	c.WaitDelay = waitDelay
+	if cmd.Stdin != nil {
+		c.Stdin = bytes.NewReader(cmd.Stdin)
+	}
+	if len(cmd.Env) > 0 {
+		c.Env = append(os.Environ(), cmd.Env...)
+	}
+
+	var outBuf, errBuf bytes.Buffer
+	c.Stdout = &outBuf
+	c.Stderr = &errBuf
+	if cmd.Combined {
+		c.Stderr = &outBuf
+	}
+
+	err = c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		err = &ExitError{Code: exitErr.ExitCode(), Stderr: errBuf.Bytes()}
+	}
+	return outBuf.Bytes(), errBuf.Bytes(), err
+}
+
+// DefaultRunner runs the git commands whose context has no Runner of its own
+var DefaultRunner Runner = ExecRunner{}
+
+// runnerKey is the context key of the Runner set with WithRunner
+type runnerKey struct{}
+
+// WithRunner returns a context that runs git commands with r instead of DefaultRunner
+func WithRunner(ctx context.Context, r Runner) context.Context {
+	return context.WithValue(ctx, runnerKey{}, r)
+}
+
+// runnerOf returns the Runner git commands run with: the one set with WithRunner, or DefaultRunner
+func runnerOf(ctx context.Context) Runner {
+	if r, ok := ctx.Value(runnerKey{}).(Runner); ok && r != nil {
+		return r
+	}
+	return DefaultRunner
+}
@@ -0,0 +1,123 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// squashWorkers bounds the number of concurrent squash-merge checks, each of which runs several git commands
+const squashWorkers = 4
+
+// IsSquashMerged reports whether the content of an unmerged branch is already in base, as happens when
+// it was merged with "squash and merge" or rebased onto base: `git branch -d` refuses to delete such a
+// branch although nothing would be lost. It is a heuristic: the branch counts as squash-merged when each
+// of its commits has an equivalent patch in base (see git-cherry(1)), or when the combined diff of its
+// commits equals the patch of a single commit in base. Returns false for branches already merged into base.
+func IsSquashMerged(branch, base string) (bool, error) {
+	return IsSquashMergedContext(context.Background(), branch, base)
+}
+
+// IsSquashMergedContext is like IsSquashMerged but kills git when ctx is done.
+func IsSquashMergedContext(ctx context.Context, branch, base string) (bool, error) {
+	ref := BranchRef(branch)
+
+	applied, err := allCommitsApplied(ctx, branch, base)
+	if err != nil || applied {
+		return applied, err
+	}
+
+	output, err := gitCombinedOutput(ctx, "merge-base", base, ref)
+	if err != nil {
+		return false, fmt.Errorf("failed to find the merge base of '%s' and '%s': %w", branch, base, commandError(output, err))
+	}
+	mergeBase := strings.TrimSpace(string(output))
+
+	branchPatch, err := patchIDs(ctx, "diff", mergeBase, ref)
+	if err != nil || len(branchPatch) == 0 {
+		return false, err
+	}
+	basePatches, err := patchIDs(ctx, "log", "--patch", "--no-merges", "--format=commit %H", mergeBase+".."+base)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(basePatches, branchPatch[0]), nil
+}
+
+// allCommitsApplied reports whether the branch has commits not in base, all of which have an equivalent
+// patch in base. `git cherry` prefixes such commits with "-" and the others with "+".
+func allCommitsApplied(ctx context.Context, branch, base string) (bool, error) {
+	output, err := gitCombinedOutput(ctx, "cherry", base, BranchRef(branch))
+	if err != nil {
+		return false, fmt.Errorf("failed to compare '%s' with '%s': %w", branch, base, commandError(output, err))
+	}
+
+	commits := splitLines(string(output))
+	for _, commit := range commits {
+		if !strings.HasPrefix(commit, "- ") {
+			return false, nil
+		}
+	}
+	return len(commits) > 0, nil
+}
+
+// patchIDs runs git with the given arguments and returns the stable patch ID (see git-patch-id(1))
+// of each patch it prints, in order
+func patchIDs(ctx context.Context, args ...string) ([]string, error) {
+	patch, err := gitOutput(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute patch IDs: %w", err)
+	}
+
+	output, err := gitOutputWithInput(ctx, patch, "patch-id", "--stable")
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute patch IDs: %w", err)
+	}
+
+	var ids []string
+	for _, line := range splitLines(string(output)) {
+		id, _, _ := strings.Cut(line, " ")
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// ListSquashMerged runs IsSquashMerged for every branch against base using a small worker pool,
+// and returns the branches found squash-merged. Branches that can't be checked are left out.
+func ListSquashMerged(branches []string, base string) map[string]bool {
+	return ListSquashMergedContext(context.Background(), branches, base)
+}
+
+// ListSquashMergedContext is like ListSquashMerged but kills the running git commands when ctx is done.
+func ListSquashMergedContext(ctx context.Context, branches []string, base string) map[string]bool {
+	var (
+		mu           sync.Mutex
+		wg           sync.WaitGroup
+		squashMerged = make(map[string]bool)
+		queue        = make(chan string)
+	)
+
+	for range min(squashWorkers, len(branches)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for branch := range queue {
+				if merged, err := IsSquashMergedContext(ctx, branch, base); err == nil && merged {
+					mu.Lock()
+					squashMerged[branch] = true
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, branch := range branches {
+		queue <- branch
+	}
+	close(queue)
+	wg.Wait()
+
+	return squashMerged
+}
@@ -0,0 +1,79 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// State is an operation left in progress in the working tree, which deleting branches could get in the way of
+type State int
+
+const (
+	// StateClean: no operation is in progress
+	StateClean State = iota
+	// StateRebase: a rebase (or git am) stopped, e.g. on a conflict
+	StateRebase
+	// StateMerge: a merge stopped before its commit, e.g. on a conflict
+	StateMerge
+	// StateCherryPick: a cherry-pick stopped on a conflict
+	StateCherryPick
+	// StateBisect: a bisect session is running
+	StateBisect
+)
+
+// String returns the git command of the operation in progress, e.g. "rebase", or "" if there is none
+func (s State) String() string {
+	switch s {
+	case StateRebase:
+		return "rebase"
+	case StateMerge:
+		return "merge"
+	case StateCherryPick:
+		return "cherry-pick"
+	case StateBisect:
+		return "bisect"
+	}
+	return ""
+}
+
+// stateFiles are the files git keeps in the git directory while each operation is in progress,
+// in the order they are checked: a rebase also leaves the files of the cherry-picks it is made of
+var stateFiles = []struct {
+	state State
+	names []string
+}{
+	{StateRebase, []string{"rebase-merge", "rebase-apply"}},
+	{StateMerge, []string{"MERGE_HEAD"}},
+	{StateCherryPick, []string{"CHERRY_PICK_HEAD"}},
+	{StateBisect, []string{"BISECT_LOG"}},
+}
+
+// GetRepositoryState returns the operation in progress in the current worktree, if any.
+// Each worktree has a git directory of its own, so an operation in another worktree isn't reported.
+func GetRepositoryState() (State, error) {
+	return GetRepositoryStateContext(context.Background())
+}
+
+// GetRepositoryStateContext is like GetRepositoryState but kills git when ctx is done.
+func GetRepositoryStateContext(ctx context.Context) (State, error) {
+	gitDir, err := GetGitDirContext(ctx)
+	if err != nil {
+		return StateClean, err
+	}
+
+	for _, candidate := range stateFiles {
+		for _, name := range candidate.names {
+			_, err := os.Stat(filepath.Join(gitDir, name))
+			if err == nil {
+				return candidate.state, nil
+			}
+			if !errors.Is(err, os.ErrNotExist) {
+				return StateClean, fmt.Errorf("failed to check for a %s in progress: %w", candidate.state, err)
+			}
+		}
+	}
+	return StateClean, nil
+}
@@ -0,0 +1,184 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// GetTagsAtBranch returns the tags, lightweight or annotated, that point exactly at the branch tip.
+// Tags are returned in alphabetical order.
+func GetTagsAtBranch(branch string) ([]string, error) {
+	return GetTagsAtBranchContext(context.Background(), branch)
+}
+
+// GetTagsAtBranchContext is like GetTagsAtBranch but kills git when ctx is done.
+func GetTagsAtBranchContext(ctx context.Context, branch string) ([]string, error) {
+	output, err := gitCombinedOutput(ctx, "tag", "--list", "--points-at", "refs/heads/"+branch)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of '%s': %w", branch, commandError(output, err))
+	}
+
+	return splitLines(string(output)), nil
+}
+
+// GetBranchesAtTag returns the local branches whose tip is the commit the tag points at.
+// Annotated tags are peeled to their commit. Branches are returned in alphabetical order.
+func GetBranchesAtTag(tag string) ([]string, error) {
+	return GetBranchesAtTagContext(context.Background(), tag)
+}
+
+// GetBranchesAtTagContext is like GetBranchesAtTag but kills git when ctx is done.
+func GetBranchesAtTagContext(ctx context.Context, tag string) ([]string, error) {
+	output, err := gitCombinedOutput(ctx, "for-each-ref", "--points-at", "refs/tags/"+tag+"^{commit}",
+		"--format=%(refname:lstrip=2)", "refs/heads")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches at tag '%s': %w", tag, commandError(output, err))
+	}
+
+	return splitLines(string(output)), nil
+}
+
+// DanglingRefs are the tags and notes attached to the commits only a branch has. Deleting the branch leaves
+// them pointing at commits nothing else reaches.
+type DanglingRefs struct {
+	// Tags are the tags, lightweight or annotated, pointing at the branch's own commits, in alphabetical order
+	Tags []string
+
+	// Notes is the number of notes, in any notes ref, attached to the branch's own commits
+	Notes int
+}
+
+// IsEmpty reports whether no tags or notes point into the branch's own commits
+func (r DanglingRefs) IsEmpty() bool {
+	return len(r.Tags) == 0 && r.Notes == 0
+}
+
+// GetDanglingRefsForBranch finds the tags and notes attached to the commits of a local branch that aren't
+// reachable from base. Tags and notes on commits base shares with the branch are not reported.
+func GetDanglingRefsForBranch(branch, base string) (DanglingRefs, error) {
+	return GetDanglingRefsForBranchContext(context.Background(), branch, base)
+}
+
+// GetDanglingRefsForBranchContext is like GetDanglingRefsForBranch but kills git when ctx is done.
+func GetDanglingRefsForBranchContext(ctx context.Context, branch, base string) (DanglingRefs, error) {
+	output, err := gitCombinedOutput(ctx, "rev-list", base+".."+BranchRef(branch), "--")
+	if err != nil {
+		return DanglingRefs{}, fmt.Errorf("failed to list the commits of '%s' not on '%s': %w", branch, base, commandError(output, err))
+	}
+	commits := make(map[string]bool)
+	for _, commit := range splitLines(string(output)) {
+		commits[commit] = true
+	}
+	if len(commits) == 0 {
+		return DanglingRefs{}, nil
+	}
+
+	tags, err := tagsAtCommits(ctx, commits)
+	if err != nil {
+		return DanglingRefs{}, err
+	}
+	notes, err := countNotesOnCommits(ctx, commits)
+	if err != nil {
+		return DanglingRefs{}, err
+	}
+	return DanglingRefs{Tags: tags, Notes: notes}, nil
+}
+
+// tagsAtCommits returns the tags pointing at one of the commits, annotated tags peeled to their commit
+func tagsAtCommits(ctx context.Context, commits map[string]bool) ([]string, error) {
+	output, err := gitCombinedOutput(ctx, "for-each-ref", "--format=%(refname:lstrip=2) %(objectname) %(*objectname)", "refs/tags")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", commandError(output, err))
+	}
+
+	var tags []string
+	for _, line := range splitLines(string(output)) {
+		// Lightweight tags have no peeled object, so the commit is the last field either way
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && commits[fields[len(fields)-1]] {
+			tags = append(tags, fields[0])
+		}
+	}
+	return tags, nil
+}
+
+// countNotesOnCommits returns how many notes, across every notes ref, are attached to one of the commits
+func countNotesOnCommits(ctx context.Context, commits map[string]bool) (int, error) {
+	output, err := gitCombinedOutput(ctx, "for-each-ref", "--format=%(refname)", "refs/notes")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list notes refs: %w", commandError(output, err))
+	}
+
+	count := 0
+	for _, ref := range splitLines(string(output)) {
+		notes, err := gitCombinedOutput(ctx, "notes", "--ref="+ref, "list")
+		if err != nil {
+			return 0, fmt.Errorf("failed to list notes of '%s': %w", ref, commandError(notes, err))
+		}
+		// Each line is "<note object> <annotated object>"
+		for _, line := range splitLines(string(notes)) {
+			if _, object, ok := strings.Cut(line, " "); ok && commits[object] {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+// CreateTag creates a lightweight tag pointing at ref (git tag <name> <ref>).
+// Fails if a tag with that name already exists.
+func CreateTag(name, ref string) error {
+	return CreateTagContext(context.Background(), name, ref)
+}
+
+// CreateTagContext is like CreateTag but kills git when ctx is done.
+func CreateTagContext(ctx context.Context, name, ref string) error {
+	output, err := gitCombinedOutput(ctx, "tag", "--", name, ref)
+
+	if err != nil {
+		return fmt.Errorf("failed to create tag '%s': %w", name, commandError(output, err))
+	}
+
+	return nil
+}
+
+// TagExists reports whether a tag with the given name exists
+func TagExists(name string) bool {
+	return TagExistsContext(context.Background(), name)
+}
+
+// TagExistsContext is like TagExists but kills git when ctx is done.
+func TagExistsContext(ctx context.Context, name string) bool {
+	_, err := gitCombinedOutput(ctx, "rev-parse", "--verify", "--quiet", "refs/tags/"+name)
+	return err == nil
+}
+
+// DeleteTag deletes a local tag (git tag -d). Tags pushed to a remote are not affected.
+func DeleteTag(name string) error {
+	return DeleteTagContext(context.Background(), name)
+}
+
+// DeleteTagContext is like DeleteTag but kills git when ctx is done.
+func DeleteTagContext(ctx context.Context, name string) error {
+	output, err := gitCombinedOutput(ctx, "tag", "-d", "--", name)
+
+	if err != nil {
+		return fmt.Errorf("failed to delete tag '%s': %w", name, commandError(output, err))
+	}
+
+	return nil
+}
+
+// splitLines returns the non-empty, trimmed lines of git output
+func splitLines(output string) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,125 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// MinimumVersion is the oldest git gelete supports. The branch list reads the upstream of each branch with
+// %(upstream:remoteref), which git 2.21 introduced; newer commands like `git branch --show-current` and
+// `git switch` fall back to older equivalents.
+const MinimumVersion = "2.21"
+
+// Version is a git release, e.g. 2.39.3
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// String returns the version as major.minor.patch
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Less reports whether v is an older release than other
+func (v Version) Less(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// ParseVersion parses the output of `git --version`, e.g. "git version 2.39.3 (Apple Git-146)", or a bare
+// version like "2.22". Vendor suffixes such as ".windows.1", ".vfs.0.0" or "-rc1" are ignored, and missing
+// minor or patch numbers are zero.
+func ParseVersion(s string) (Version, error) {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(s), "git version"))
+	if len(fields) == 0 {
+		return Version{}, fmt.Errorf("invalid git version '%s'", strings.TrimSpace(s))
+	}
+
+	var numbers [3]int
+	for i, part := range strings.SplitN(fields[0], ".", len(numbers)+1) {
+		if i == len(numbers) {
+			break
+		}
+		number, ok := leadingNumber(part)
+		if !ok {
+			if i == 0 {
+				return Version{}, fmt.Errorf("invalid git version '%s'", strings.TrimSpace(s))
+			}
+			break
+		}
+		numbers[i] = number
+	}
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// leadingNumber parses the digits at the start of s, e.g. 0 in "0-rc1", and reports whether there were any
+func leadingNumber(s string) (int, bool) {
+	end := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	if end == -1 {
+		end = len(s)
+	}
+	number, err := strconv.Atoi(s[:end])
+	return number, err == nil
+}
+
+// GetVersion returns the version of the git gelete runs
+func GetVersion() (Version, error) {
+	return GetVersionContext(context.Background())
+}
+
+// GetVersionContext is like GetVersion but kills git when ctx is done.
+func GetVersionContext(ctx context.Context) (Version, error) {
+	output, err := gitCombinedOutput(ctx, "--version")
+
+	if err != nil {
+		if _, lookErr := exec.LookPath("git"); lookErr != nil {
+			return Version{}, errors.New("git command not found. Please install git and ensure it's in your PATH")
+		}
+		return Version{}, fmt.Errorf("failed to get git version: %w", commandError(output, err))
+	}
+
+	return ParseVersion(string(output))
+}
+
+// CheckVersion returns an error like "gelete requires git >= 2.21, found 2.17.1" if git is older than minimum
+func CheckVersion(minimum string) error {
+	return CheckVersionContext(context.Background(), minimum)
+}
+
+// CheckVersionContext is like CheckVersion but kills git when ctx is done.
+func CheckVersionContext(ctx context.Context, minimum string) error {
+	required, err := ParseVersion(minimum)
+	if err != nil {
+		return err
+	}
+
+	version, err := GetVersionContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if version.Less(required) {
+		return fmt.Errorf("gelete requires git >= %s, found %s", minimum, version)
+	}
+	return nil
+}
+
+// ValidateEnvironment checks that git is installed and at least MinimumVersion, so an old git is reported
+// with one clear error instead of confusing failures of individual commands
+func ValidateEnvironment() error {
+	return ValidateEnvironmentContext(context.Background())
+}
+
+// ValidateEnvironmentContext is like ValidateEnvironment but kills git when ctx is done.
+func ValidateEnvironmentContext(ctx context.Context) error {
+	return CheckVersionContext(ctx, MinimumVersion)
+}
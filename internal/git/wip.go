@@ -0,0 +1,66 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// wipCommitLimit caps the commits read per branch to tell whether it is a WIP branch.
+// Branches with more commits of their own are real work, whatever their subjects say.
+const wipCommitLimit = 50
+
+// DefaultWIPPatterns are the commit subjects of throwaway commits: "wip", "tmp", and the fixup and squash
+// commits made for an autosquash rebase. They also match the "WIP on main: …" commits of git stash.
+var DefaultWIPPatterns = []string{`^(wip|tmp|fixup!|squash!)`}
+
+// CompileWIPPatterns compiles regular expressions matching the subjects of WIP commits, case-insensitively
+func CompileWIPPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid WIP pattern '%s': %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// IsWIPBranch reports whether every commit on the branch, given as a revision, that is not on base has a subject
+// matching one of patterns. Branches without such commits, or with more than wipCommitLimit, are not WIP.
+func IsWIPBranch(revision, base string, patterns []*regexp.Regexp) (bool, error) {
+	return IsWIPBranchContext(context.Background(), revision, base, patterns)
+}
+
+// IsWIPBranchContext is like IsWIPBranch but kills git when ctx is done.
+func IsWIPBranchContext(ctx context.Context, revision, base string, patterns []*regexp.Regexp) (bool, error) {
+	output, err := gitCombinedOutput(ctx, "log", "--format=%s", "-n", strconv.Itoa(wipCommitLimit+1), base+".."+revision, "--")
+
+	if err != nil {
+		return false, fmt.Errorf("failed to list commits of '%s': %w", revision, commandError(output, err))
+	}
+
+	subjects := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+	if subjects[0] == "" || len(subjects) > wipCommitLimit {
+		return false, nil
+	}
+	for _, subject := range subjects {
+		if !matchesAny(strings.TrimSpace(subject), patterns) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchesAny reports whether s matches one of patterns
+func matchesAny(s string, patterns []*regexp.Regexp) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
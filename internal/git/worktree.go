@@ -1,9 +1,10 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os/exec"
-	"path/filepath"
+	"os"
 	"strings"
 )
 
@@ -12,40 +13,81 @@ type Worktree struct {
 	// Path is the absolute path to the worktree directory
 	Path string
 
-	// Branch is the branch name checked out in this worktree
+	// HEAD is the commit checked out in this worktree
+	HEAD string
+
+	// Branch is the branch name checked out in this worktree (empty if HEAD is detached)
 	Branch string
 
+	// Detached indicates HEAD is detached, so no branch is checked out in this worktree
+	Detached bool
+
 	// Locked indicates if the worktree is locked
 	Locked bool
+
+	// LockReason is the reason given with `git worktree lock --reason` (empty if none was given)
+	LockReason string
+
+	// Prunable indicates git would clean up the worktree with `git worktree prune`,
+	// e.g. because its directory no longer exists. Such worktrees are pruned instead of removed.
+	Prunable bool
+
+	// Missing indicates the worktree directory no longer exists, e.g. because it was removed
+	// with rm -rf instead of `git worktree remove`. Unless it is locked, such a worktree is also Prunable.
+	Missing bool
+
+	// Main indicates the main worktree, i.e. the repository itself, which can't be removed.
+	// It is always the first worktree listed.
+	Main bool
 }
 
 // ListWorktrees returns all git worktrees in the current repository.
 // Uses `git worktree list --porcelain` for machine-readable output.
 func ListWorktrees() ([]Worktree, error) {
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	output, err := cmd.CombinedOutput()
+	return ListWorktreesContext(context.Background())
+}
+
+// ListWorktreesContext is like ListWorktrees but kills git when ctx is done.
+func ListWorktreesContext(ctx context.Context) ([]Worktree, error) {
+	output, err := gitCombinedOutput(ctx, "worktree", "list", "--porcelain")
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to list worktrees: %s", strings.TrimSpace(string(output)))
+		return nil, fmt.Errorf("failed to list worktrees: %w", commandError(output, err))
+	}
+
+	worktrees := ParseWorktrees(string(output))
+	for i := range worktrees {
+		wt := &worktrees[i]
+		wt.Missing = isMissing(wt.Path)
+		wt.Main = i == 0
+		// git before 2.31 doesn't report prunable worktrees; prune skips locked ones
+		wt.Prunable = wt.Prunable || (wt.Missing && !wt.Locked && !wt.Main)
 	}
+	return worktrees, nil
+}
 
-	return parseWorktrees(string(output)), nil
+// isMissing reports whether the directory at path no longer exists
+func isMissing(path string) bool {
+	_, err := os.Stat(path)
+	return errors.Is(err, os.ErrNotExist)
 }
 
-// parseWorktrees parses the porcelain format output from `git worktree list --porcelain`
-// Format:
+// ParseWorktrees parses the porcelain format output of `git worktree list --porcelain`.
+// Each worktree is a block of lines ended by a blank line (optional after the last one):
 //
 //	worktree /path/to/worktree
 //	HEAD <commit-hash>
-//	branch refs/heads/branch-name
-//	<blank line>
-func parseWorktrees(output string) []Worktree {
+//	branch refs/heads/branch-name | detached
+//	locked [<reason>]
+//	prunable [<reason>]
+//
+// Missing and Main aren't part of the output and are left unset.
+func ParseWorktrees(output string) []Worktree {
 	var worktrees []Worktree
-	lines := strings.Split(output, "\n")
 	var current *Worktree
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
 		if line == "" {
 			if current != nil {
 				worktrees = append(worktrees, *current)
@@ -53,10 +95,8 @@ func parseWorktrees(output string) []Worktree {
 			}
 			continue
 		}
-		parts := strings.SplitN(line, " ", 2)
-		if len(parts) >= 2 {
-			current = applyWorktreeLine(current, parts[0], parts[1])
-		}
+		key, value, _ := strings.Cut(line, " ")
+		current = applyWorktreeLine(current, key, value)
 	}
 
 	if current != nil {
@@ -66,22 +106,28 @@ func parseWorktrees(output string) []Worktree {
 	return worktrees
 }
 
+// applyWorktreeLine applies one attribute line of the porcelain output to the worktree it describes.
+// A worktree line starts a new worktree; attributes before the first one are ignored.
 func applyWorktreeLine(wt *Worktree, key, value string) *Worktree {
+	if key == "worktree" {
+		return &Worktree{Path: CanonicalPath(value)}
+	}
+	if wt == nil {
+		return nil
+	}
+
 	switch key {
-	case "worktree":
-		canonicalPath, err := filepath.EvalSymlinks(value)
-		if err != nil {
-			canonicalPath = value
-		}
-		return &Worktree{Path: canonicalPath}
+	case "HEAD":
+		wt.HEAD = value
 	case "branch":
-		if wt != nil {
-			wt.Branch = strings.TrimPrefix(value, "refs/heads/")
-		}
+		wt.Branch = strings.TrimPrefix(value, "refs/heads/")
+	case "detached":
+		wt.Detached = true
 	case "locked":
-		if wt != nil {
-			wt.Locked = true
-		}
+		wt.Locked = true
+		wt.LockReason = value
+	case "prunable":
+		wt.Prunable = true
 	}
 	return wt
 }
@@ -89,12 +135,32 @@ func applyWorktreeLine(wt *Worktree, key, value string) *Worktree {
 // RemoveWorktree removes the specified worktree using `git worktree remove`.
 // Returns an error if the worktree is locked or doesn't exist.
 func RemoveWorktree(worktreePath string) error {
-	cmd := exec.Command("git", "worktree", "remove", worktreePath)
-	output, err := cmd.CombinedOutput()
+	return RemoveWorktreeContext(context.Background(), worktreePath)
+}
+
+// RemoveWorktreeContext is like RemoveWorktree but kills git when ctx is done.
+func RemoveWorktreeContext(ctx context.Context, worktreePath string) error {
+	output, err := gitCombinedOutput(ctx, "worktree", "remove", "--", worktreePath)
 
 	if err != nil {
-		outputStr := strings.TrimSpace(string(output))
-		return fmt.Errorf("failed to remove worktree '%s': %s", worktreePath, outputStr)
+		return fmt.Errorf("failed to remove worktree '%s': %w", worktreePath, commandError(output, err))
+	}
+
+	return nil
+}
+
+// RemoveDirtyWorktree removes the specified worktree using `git worktree remove --force`, which discards
+// its uncommitted changes and untracked files. Unlike ForceRemoveWorktree, locked worktrees are still refused.
+func RemoveDirtyWorktree(worktreePath string) error {
+	return RemoveDirtyWorktreeContext(context.Background(), worktreePath)
+}
+
+// RemoveDirtyWorktreeContext is like RemoveDirtyWorktree but kills git when ctx is done.
+func RemoveDirtyWorktreeContext(ctx context.Context, worktreePath string) error {
+	output, err := gitCombinedOutput(ctx, "worktree", "remove", "--force", "--", worktreePath)
+
+	if err != nil {
+		return fmt.Errorf("failed to remove worktree '%s': %w", worktreePath, commandError(output, err))
 	}
 
 	return nil
@@ -104,12 +170,61 @@ func RemoveWorktree(worktreePath string) error {
 // This bypasses safety checks and will remove locked worktrees.
 // Note: Double --force is required to remove locked worktrees.
 func ForceRemoveWorktree(worktreePath string) error {
-	cmd := exec.Command("git", "worktree", "remove", "--force", "--force", worktreePath)
-	output, err := cmd.CombinedOutput()
+	return ForceRemoveWorktreeContext(context.Background(), worktreePath)
+}
+
+// ForceRemoveWorktreeContext is like ForceRemoveWorktree but kills git when ctx is done.
+func ForceRemoveWorktreeContext(ctx context.Context, worktreePath string) error {
+	output, err := gitCombinedOutput(ctx, "worktree", "remove", "--force", "--force", "--", worktreePath)
 
 	if err != nil {
-		outputStr := strings.TrimSpace(string(output))
-		return fmt.Errorf("failed to force remove worktree '%s': %s", worktreePath, outputStr)
+		return fmt.Errorf("failed to force remove worktree '%s': %w", worktreePath, commandError(output, err))
+	}
+
+	return nil
+}
+
+// WorktreeStatus reports whether the worktree at path has uncommitted changes, using
+// `git -C <path> status --porcelain`: clean is false when files are modified, staged or untracked,
+// and changedFiles counts them. Ignored files don't count. If the worktree directory no longer exists,
+// the error wraps ErrWorktreeMissing rather than reporting it as dirty.
+func WorktreeStatus(path string) (clean bool, changedFiles int, err error) {
+	return WorktreeStatusContext(context.Background(), path)
+}
+
+// WorktreeStatusContext is like WorktreeStatus but kills git when ctx is done.
+func WorktreeStatusContext(ctx context.Context, path string) (clean bool, changedFiles int, err error) {
+	if isMissing(path) {
+		return false, 0, fmt.Errorf("failed to check worktree '%s': %w", path, ErrWorktreeMissing)
+	}
+
+	output, err := gitOutput(ctx, "-C", path, "status", "--porcelain")
+
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to check worktree '%s': %w", path, commandError(failureDetail(output, err), err))
+	}
+
+	// Each changed or untracked file is a line, e.g. " M main.go" or "?? notes.txt"
+	for _, line := range strings.Split(string(output), "\n") {
+		if line != "" {
+			changedFiles++
+		}
+	}
+	return changedFiles == 0, changedFiles, nil
+}
+
+// PruneWorktrees removes the administrative data of worktrees whose directories no longer exist
+// using `git worktree prune`, so their branches are no longer considered checked out.
+func PruneWorktrees() error {
+	return PruneWorktreesContext(context.Background())
+}
+
+// PruneWorktreesContext is like PruneWorktrees but kills git when ctx is done.
+func PruneWorktreesContext(ctx context.Context) error {
+	output, err := gitCombinedOutput(ctx, "worktree", "prune")
+
+	if err != nil {
+		return fmt.Errorf("failed to prune worktrees: %w", commandError(output, err))
 	}
 
 	return nil
@@ -118,7 +233,12 @@ func ForceRemoveWorktree(worktreePath string) error {
 // GetWorktreeForBranch returns the worktree associated with a branch, if any.
 // Returns nil if the branch is not checked out in any worktree.
 func GetWorktreeForBranch(branchName string) (*Worktree, error) {
-	worktrees, err := ListWorktrees()
+	return GetWorktreeForBranchContext(context.Background(), branchName)
+}
+
+// GetWorktreeForBranchContext is like GetWorktreeForBranch but kills git when ctx is done.
+func GetWorktreeForBranchContext(ctx context.Context, branchName string) (*Worktree, error) {
+	worktrees, err := ListWorktreesContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,281 @@
+package i18n
+
+import "strconv"
+
+// english is the catalog every other language falls back to
+var english = map[MessageID]string{
+	SwitchedToBranch:     "Switched to branch %s",
+	SelectionTitle:       "gelete - Interactive Branch Deletion",
+	RemoteSelectionTitle: "gelete - Interactive Remote Branch Deletion",
+	NoBranchesToDelete:   "No branches to delete.",
+	PressKeyToQuit:       "Press %s to quit.",
+	ErrorLine:            "Error: %s",
+	WarningLine:          "Warning: %s",
+	ShowingRows:          "showing %d–%d of %d",
+	RenamePrompt:         "Rename %s to: ",
+	SelectMatchingPrompt: "Select matching: ",
+	FilterHelp:           "type to filter • ↑/↓: move • enter: apply • esc: clear",
+	RenameHelp:           "type the new name • enter: rename • esc: cancel",
+	PatternHelp:          "type a glob (e.g. tmp/*; !glob deselects) • enter: apply to visible branches • esc: cancel",
+	HelpScroll:           "scroll",
+	HelpFlatList:         "flat list",
+	HelpSelectGroup:      "%s on group: select group",
+	HelpCollapseGroup:    "%s on group: collapse/expand",
+	StatusSelected:       "%d/%d selected",
+	StatusRefreshing:     "refreshing…",
+	StatusDetachedHead:   "detached HEAD (all branches listed)",
+	StatusMergeBase:      "merge status against %s",
+	StatusShowing:        "(showing %s)",
+	StatusSortedBy:       "sorted by %s",
+	SortByName:           "name",
+	SortOldestFirst:      "oldest first",
+	SortNewestFirst:      "newest first",
+	FilterAll:            "all",
+	FilterMergedOnly:     "merged only",
+	FilterUnmergedOnly:   "unmerged only",
+	FilterAuthoredBy:     "authored by %s",
+	FilterCommittedBy:    "committed by %s",
+	NoFilterMatch:        "No branches match the filter.",
+	NoFilterMatchWith:    "No branches match the filter (%s).",
+	NoMergedBranches:     "No branches merged into %s.",
+	NoUnmergedBranches:   "No branches with commits not in %s.",
+	NoCommitDate:         "⚠ no commit date",
+	Ago:                  "%s ago",
+	JustNow:              "just now",
+	UnknownDate:          "unknown",
+	BadgeMerged:          "merged",
+	BadgeSquashed:        "squashed",
+	BadgeUnmerged:        "unmerged",
+	WorktreeTag:          "[⎇ worktree]",
+	StaleWorktreeTag:     "[⎇ stale worktree]",
+
+	HeaderOnBranch:            "on %s",
+	HeaderDetachedHead:        "detached HEAD",
+	HeaderBase:                "base %s",
+	HeaderSubmodule:           "(submodule of %s)",
+	HeaderOperationInProgress: "⚠ %s in progress",
+	HeaderMine:                "only branches %s",
+
+	RemoteDeletionWarning:        "⚠ These branches will be deleted from the REMOTE server!",
+	RemoteDeletionQuestion:       "Are you sure you want to delete these remote branches?",
+	ForceDeletionWarning:         "⚠ FORCE DELETE: unmerged commits on these branches will be lost (git branch -D)",
+	ForceDeletionQuestion:        "Are you sure you want to FORCE delete these branches?",
+	DeletionQuestion:             "Are you sure you want to delete these branches?",
+	DefaultBranchWarning:         "⚠ '%s' is the DEFAULT branch of this repository: clones, CI and pull requests rely on it!",
+	ConfirmationTotal:            "Total: %s",
+	RemoteFetchPruneWarning:      "Other clones will lose these branches on their next fetch --prune.",
+	ExpireReflogWarning:          "The reflog is expired and git gc run afterwards (--expire-reflog): deleted commits become unrecoverable.",
+	DryRunNote:                   "Dry run: no branches will actually be deleted.",
+	PlanNote:                     "Plan: the branches are written to %[1]s for review instead of being deleted (delete them with gelete apply %[1]s).",
+	HelpMove:                     "move",
+	HelpDeleteSafely:             "delete safely instead",
+	AlsoDelete:                   "also delete %s",
+	WorktreesTitle:               "⚠ Some branches are checked out in worktrees",
+	WorktreesIntro:               "The following worktrees must be removed before their branches can be deleted:",
+	DirtyWorktreesSkipped:        "Uncommitted changes in %s: their branches are skipped unless the changes are discarded.",
+	HelpRemoveWorktrees:          "remove worktrees",
+	HelpRemoveCleanWorktrees:     "remove clean worktrees",
+	HelpSkipBranches:             "skip these branches",
+	HelpBackToSelection:          "back to selection",
+	LockedWorktreesTitle:         "⚠ Warning: Locked Worktrees Detected",
+	LockedWorktreesIntro:         "The following worktrees are locked:",
+	LockedWorktreesRemoval:       "Force removal will delete %s and any uncommitted changes in them.",
+	HelpForceRemove:              "force remove",
+	HelpForceRemoveClean:         "force remove clean worktrees",
+	CheckedOutTitle:              "⚠ Warning: Branches Checked Out in Worktrees",
+	CheckedOutIntro:              "Git refused to delete these branches because they are checked out in other worktrees:",
+	CheckedOutRemoval:            "Removing %s lets their branches be deleted.",
+	HelpRemoveAndRetry:           "remove worktrees and retry",
+	HelpRemoveCleanAndRetry:      "remove clean worktrees and retry",
+	UnmergedTitle:                "⚠ Warning: Unmerged Branches Detected",
+	UnmergedIntro:                "The following branches have unmerged changes:",
+	UnmergedRemoval:              "Force delete will permanently remove %s.",
+	CannotBeUndone:               "This action cannot be undone!",
+	SquashTitle:                  "✓ Branches Merged via Squash",
+	SquashIntro:                  "git considers these branches unmerged, but their content appears merged:",
+	SquashRemoval:                "Force deleting %s should lose no changes.",
+	SquashedInto:                 "content appears merged via squash into %s",
+	TipUnreachable:               "commits on this branch are not reachable from any other ref and will become dangling",
+	TipUnchecked:                 "reachability from other refs not checked (the check failed or took longer than %s)",
+	HelpForceDelete:              "force delete",
+	HelpForceDeleteAll:           "force delete all",
+	HelpReviewEach:               "decide one by one",
+	HelpSkipAll:                  "skip all",
+	ForceReviewTitle:             "Force delete unmerged branches one by one (%d/%d)",
+	DecisionForceDelete:          "force delete",
+	DecisionSkip:                 "skip",
+	LoadingCommits:               "Loading commits…",
+	CommitsOnlyOn:                "Commits not on %s, lost if force deleted:",
+	NoCommitsOnlyOn:              "No commits that are not on %s.",
+	LatestCommitsOnly:            "(only the latest %d are shown)",
+	HelpSkipBranch:               "skip",
+	HelpPreviousNext:             "previous/next",
+	HelpBackToChoices:            "back",
+	TypeToConfirm:                "type %s or %d to confirm",
+	TypedConfirmationThreshold:   "Deleting more than %s",
+	TypedConfirmationForce:       "Force deleting",
+	TypedConfirmationRequirement: "%s takes typing %q or the number of branches (%d) to confirm.",
+	TypedConfirmationHelp:        "type %s or %d • enter: confirm • esc: cancel",
+	AnalyzingBranches:            "Analyzing selected branches…",
+	AnalysisFailed:               "Could not analyze the branches: %s",
+	RiskProtected:                "Protected (deletable because protection is disabled):",
+	RiskWorktree:                 "Checked out in a worktree (the worktree is removed first):",
+	RiskUnmerged:                 "Unmerged (can only be force deleted):",
+	RiskUnknown:                  "Unknown status:",
+	RiskMerged:                   "Merged:",
+	StashesKept:                  "%s made on this branch are kept",
+	AlsoDeleteTag:                "also delete tag %s",
+	AlsoDeleteTags:               "also delete tags %s",
+	TagKept:                      "tag %s is kept: also at the tip of %s",
+
+	DeletingTitle:             "Deleting branches...",
+	PleaseWait:                "Please wait...",
+	CancellingAfter:           "Cancelling after %s… (ctrl+c again to quit immediately)",
+	HelpCancelRun:             "ctrl+c: cancel",
+	EarlierResults:            "… %s",
+	PhaseRemovingWorktree:     "Removing worktree",
+	PhaseForceDeleting:        "Force deleting",
+	PhaseWritingBundle:        "Writing backup bundle",
+	PhaseBackingUp:            "Backing up",
+	PhaseChecking:             "Checking",
+	PhaseDeleting:             "Deleting",
+	WaitingForLock:            "waiting for lock %s…",
+	DoneTitle:                 "Deletion Complete",
+	CancelledTitle:            "Deletion Cancelled",
+	DoneCounts:                "%d deleted • %d skipped • %d failed",
+	NotifyDeleted:             "gelete: deleted %s",
+	NotifyDeletedWithFailures: "gelete: deleted %s, %d failed",
+	AlreadyGoneOne:            "1 branch was already deleted",
+	AlreadyGoneOther:          "%d branches were already deleted",
+	AlsoDeletedRemote:         "✓ Also deleted %s",
+	RemoteDeletionFailed:      "✗ Failed to delete %s (the local branches were deleted):",
+	AlsoDeletedTags:           "✓ Also deleted %s",
+	TagDeletionFailed:         "✗ Failed to delete %s (their branches were deleted):",
+	BackedUpAsTags:            "✓ Backed up %s as tags:",
+	BackupTagRestore:          "%s (restore: %s)",
+	BackedUpToBundle:          "✓ Backed up %s to %s",
+	BundleRestore:             "restore: %s",
+	ResultRestored:            "↺ %s: restored at %s",
+	ResultDeleted:             "✓ %s: deleted%s",
+	ResultForceDeleted:        "✓ %s: force-deleted%s",
+	ResultSkipped:             "⊘ %s: skipped (%s)",
+	ResultAlreadyGone:         "○ %s: already deleted outside gelete",
+	ResultCheckedOut:          "✗ %s is checked out in %s — remove that worktree or switch its branch first",
+	ResultLocked:              "✗ %s: the lock file %s exists — another git process or your IDE may be holding a lock; delete the file if none is running",
+	ResultFailed:              "✗ %s: failed: %s",
+	RestoreHint:               " (was %[1]s — restore with: git branch %[2]s %[1]s)",
+	RestoreTitle:              "Restore Deleted Branches",
+	RestoreHelp:               "↑/k: up • ↓/j: down • space: toggle • enter/r: restore selected • esc: back",
+	RestoredCount:             "↺ Restored %s",
+	RestoreFailed:             "✗ Failed to restore %s:",
+	DoneHelpCompacting:        "ctrl+c: stop git gc and quit",
+	DoneHelpCompaction:        "g: expire reflog and run git gc • u: undo (restore deleted branches) • any other key: exit",
+	DoneHelpUndo:              "u: undo (restore deleted branches) • any other key: exit",
+	PressAnyKey:               "Press any key to exit.",
+	DryRunTitle:               "Dry Run Summary",
+	DryRunIntro:               "No branches were deleted. The following actions would have been performed:",
+	DryRunCheckFailed:         "✗ Could not check %s:",
+	DryRunRemote:              "%s: would delete from remote",
+	DryRunMerged:              "%s (merged): would delete",
+	DryRunForce:               "%s (unmerged): would force delete",
+	DryRunSkip:                "%s (unmerged): would skip",
+	DryRunWorktree:            "would remove worktree at %s",
+	DryRunAlsoRemote:          "would also delete %s",
+	DryRunAlsoTag:             "would also delete tag %s",
+	DryRunAlsoTags:            "would also delete tags %s",
+
+	NoGoneBranches:         "No branches with a gone upstream.",
+	NoCommitsYet:           "Repository has no commits yet; nothing to delete.",
+	NoMineBranches:         "No branches of yours (%s).",
+	CurrentBranchExcluded:  "(Current branch is excluded from the list)",
+	NoRemoteBranches:       "No remote branches to delete.",
+	VerboseLogWritten:      "Verbose log written to %s",
+	NotARepository:         "not a git repository",
+	ExpireReflogWithRemote: "--expire-reflog cannot be combined with --remote",
+	BackupBundleWithRemote: "--backup-bundle cannot be combined with --remote",
+	JSONWithRemote:         "--json cannot be combined with --remote",
+	ArgsWithRemote:         "branch arguments cannot be combined with --remote; run gelete --remote to pick the remote branches in the TUI",
+	JSONNeedsYes:           "--json requires --yes (or --dry-run) when deleting branches",
+	QuietWithJSON:          "--quiet cannot be combined with --json",
+	QuietNeedsYes:          "--quiet requires --yes when deleting branches, since the confirmation prompt would be hidden",
+	MergeFilterWithRemote:  "--merged-only and --unmerged-only cannot be combined with --remote",
+	MergeFilterWithArgs:    "--merged-only and --unmerged-only cannot be combined with branch arguments",
+	MergeFilterNeedsBase:   "cannot determine the base branch for --merged-only/--unmerged-only; use --base or set base_branch in the config",
+	OperationInProgress:    "a %s is in progress; finish or abort it before running gelete (or start anyway with --force-start)",
+	MineWithRemote:         "--mine cannot be combined with --remote",
+	MineWithArgs:           "--mine cannot be combined with branch arguments",
+	MineNeedsEmail:         "--mine needs your email; set it with git config user.email <email>",
+
+	WorktreeSelectionTitle:   "gelete - Interactive Worktree Removal",
+	NoWorktreesToRemove:      "No worktrees to remove.",
+	WorktreeSelectionHelp:    "↑/k: up • ↓/j: down • space/enter: toggle • a: select/deselect all • d: remove selected • q: quit",
+	WorktreeLockedTag:        "[locked]",
+	WorktreeMissingTag:       "[missing]",
+	WorktreeModified:         "modified %s",
+	WorktreeDetachedHead:     "(detached HEAD)",
+	WorktreeRemovalQuestion:  "Are you sure you want to remove these worktrees?",
+	WorktreeConfirmHelp:      "y: confirm • n: cancel",
+	WorktreeLockedTitle:      "⚠ Some worktrees are locked",
+	WorktreeLockedHelp:       "y: force remove • n: keep these worktrees",
+	FreedBranchQuestion:      "Also delete branch %s?",
+	FreedBranchProgress:      "Its worktree was removed (%d/%d)",
+	FreedBranchMerged:        "Merged into %s",
+	FreedBranchUnmerged:      "Not merged into %s: force deletion will be confirmed separately",
+	FreedBranchHelp:          "y: delete branch • n: keep branch",
+	FreedUnmergedTitle:       "⚠ Some branches are not fully merged",
+	FreedUnmergedRemoval:     "Force deletion will delete %s with commits not merged anywhere else.",
+	FreedUnmergedHelp:        "y: force delete • n: keep these branches",
+	CleaningUpTitle:          "Cleaning up...",
+	PhaseDeletingBranch:      "Deleting branch",
+	PhaseForceDeletingBranch: "Force deleting branch",
+	CancellingCleanup:        "Cancelling… (ctrl+c again to quit immediately)",
+	CleanupDoneTitle:         "Cleanup Complete",
+	CleanupCancelledTitle:    "Cleanup Cancelled",
+	WorktreeRemoved:          "✓ %s: removed",
+	FreedBranchDeleted:       "✓ branch %s: deleted",
+	FreedBranchFailed:        "✗ branch %s: failed: %s",
+	FreedBranchKept:          "• branch %s: kept",
+	CleanupCounts:            "%s removed • %s deleted • %d failed",
+
+	TrackingRefSelectionTitle:    "gelete - Prune Stale Remote-Tracking Refs",
+	TrackingRefRemoteGroup:       "%s: branch deleted on the remote",
+	TrackingRefSelectionHelp:     "↑/k: up • ↓/j: down • space/enter: toggle • a: select/deselect all • d: prune selected • q: quit",
+	TrackingRefRemovalQuestion:   "Are you sure you want to prune these remote-tracking refs?",
+	TrackingRefConfirmationTotal: "Total: %s. Only the local refs are deleted; the remotes are left untouched.",
+	TrackingRefConfirmHelp:       "y: confirm • n: cancel",
+	PruningTitle:                 "Pruning...",
+	CancellingPrune:              "Cancelling… (ctrl+c again to quit immediately)",
+	PruningDoneTitle:             "Pruning Complete",
+	PruningCancelledTitle:        "Pruning Cancelled",
+	TrackingRefPruned:            "✓ %s: pruned",
+	PruneCounts:                  "%s pruned • %d failed",
+}
+
+// englishNouns holds the singular and plural form of each noun
+var englishNouns = map[Noun][2]string{
+	Branch:         {"branch", "branches"},
+	RemoteBranch:   {"remote branch", "remote branches"},
+	UnmergedBranch: {"unmerged branch", "unmerged branches"},
+	Worktree:       {"worktree", "worktrees"},
+	LockedWorktree: {"locked worktree", "locked worktrees"},
+	EarlierResult:  {"earlier result", "earlier results"},
+	TrackingRef:    {"ref", "refs"},
+	Tag:            {"tag", "tags"},
+	StashEntry:     {"stash entry", "stash entries"},
+	Second:         {"second", "seconds"},
+	Minute:         {"minute", "minutes"},
+	Hour:           {"hour", "hours"},
+	Day:            {"day", "days"},
+	Week:           {"week", "weeks"},
+	Month:          {"month", "months"},
+	Year:           {"year", "years"},
+}
+
+// countEnglish uses the singular for exactly one and the plural otherwise, including zero
+func countEnglish(n int, noun Noun) string {
+	forms := englishNouns[noun]
+	if n == 1 {
+		return "1 " + forms[0]
+	}
+	return strconv.Itoa(n) + " " + forms[1]
+}
@@ -0,0 +1,141 @@
+// Package i18n holds the translations of the messages gelete shows, and selects the language they are shown in.
+//
+// Each locale is a message catalog keyed by MessageID and a helper counting nouns, since languages don't agree
+// on plurals. The zero Lang is English, so models built without a language keep working.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lang is a language gelete's messages are translated to
+type Lang string
+
+const (
+	// English is the default language, used when no other is selected
+	English Lang = "en"
+	// Japanese is selected with --lang ja, GELETE_LANG=ja or a ja_* locale
+	Japanese Lang = "ja"
+)
+
+// Langs lists the supported languages
+var Langs = []Lang{English, Japanese}
+
+// Noun is a counted thing whose form depends on the count in some languages, e.g. "1 branch" and "2 branches"
+type Noun int
+
+const (
+	Branch Noun = iota
+	RemoteBranch
+	UnmergedBranch
+	Worktree
+	LockedWorktree
+	EarlierResult
+	TrackingRef
+	Tag
+	StashEntry
+
+	// The units of the age of a commit, e.g. "3 months" in "3 months ago"
+	Second
+	Minute
+	Hour
+	Day
+	Week
+	Month
+	Year
+)
+
+// locale is the catalog of a language: its messages, and how it counts nouns
+type locale struct {
+	messages map[MessageID]string
+	count    func(n int, noun Noun) string
+}
+
+var locales = map[Lang]locale{
+	English:  {messages: english, count: countEnglish},
+	Japanese: {messages: japanese, count: countJapanese},
+}
+
+// locale returns the catalog of l, English for the zero Lang
+func (l Lang) locale() locale {
+	if loc, ok := locales[l]; ok {
+		return loc
+	}
+	return locales[English]
+}
+
+// T returns the message in l, formatted with args like fmt.Sprintf.
+// Messages missing from a translation fall back to English.
+func (l Lang) T(id MessageID, args ...any) string {
+	format, ok := l.locale().messages[id]
+	if !ok {
+		format = english[id]
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Count returns n with the noun in the form the count calls for in l, e.g. "1 branch" or "3 branches"
+func (l Lang) Count(n int, noun Noun) string {
+	return l.locale().count(n, noun)
+}
+
+// Parse returns the language named by a language tag or a locale, e.g. "ja", "ja-JP" or "ja_JP.UTF-8"
+func Parse(name string) (Lang, error) {
+	tag := strings.ToLower(strings.TrimSpace(name))
+	tag, _, _ = strings.Cut(tag, ".")
+	tag, _, _ = strings.Cut(tag, "@")
+	tag, _, _ = strings.Cut(strings.ReplaceAll(tag, "-", "_"), "_")
+
+	for _, lang := range Langs {
+		if tag == string(lang) {
+			return lang, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported language '%s': must be one of %s", name, joinLangs())
+}
+
+// joinLangs returns the supported languages separated by commas, for error messages
+func joinLangs() string {
+	names := make([]string, len(Langs))
+	for i, lang := range Langs {
+		names[i] = string(lang)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Resolve selects the language from the --lang flag, the GELETE_LANG environment variable, or else the locale
+// of the environment (LC_ALL, LC_MESSAGES and LANG, in the order POSIX gives them precedence).
+// An unsupported flag or GELETE_LANG is an error, while a locale without a translation, like C or fr_FR,
+// falls back to English.
+func Resolve(flag string, getenv func(string) string) (Lang, error) {
+	if flag != "" {
+		lang, err := Parse(flag)
+		if err != nil {
+			return "", fmt.Errorf("invalid --lang: %w", err)
+		}
+		return lang, nil
+	}
+	if name := getenv("GELETE_LANG"); name != "" {
+		lang, err := Parse(name)
+		if err != nil {
+			return "", fmt.Errorf("invalid GELETE_LANG: %w", err)
+		}
+		return lang, nil
+	}
+
+	for _, variable := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		name := getenv(variable)
+		if name == "" {
+			continue
+		}
+		if lang, err := Parse(name); err == nil {
+			return lang, nil
+		}
+		return English, nil
+	}
+	return English, nil
+}
@@ -0,0 +1,284 @@
+package i18n
+
+import "strconv"
+
+// japanese is the Japanese catalog. Git commands, flags and key names are left as they are typed.
+var japanese = map[MessageID]string{
+	SwitchedToBranch:     "ブランチ %s に切り替えました",
+	SelectionTitle:       "gelete - 対話式ブランチ削除",
+	RemoteSelectionTitle: "gelete - 対話式リモートブランチ削除",
+	NoBranchesToDelete:   "削除できるブランチはありません。",
+	PressKeyToQuit:       "%s で終了します。",
+	ErrorLine:            "エラー: %s",
+	WarningLine:          "警告: %s",
+	ShowingRows:          "%[3]d 件中 %[1]d–%[2]d 件を表示",
+	RenamePrompt:         "%s の新しい名前: ",
+	SelectMatchingPrompt: "パターンで選択: ",
+	FilterHelp:           "入力して絞り込み • ↑/↓: 移動 • enter: 適用 • esc: クリア",
+	RenameHelp:           "新しい名前を入力 • enter: 名前を変更 • esc: キャンセル",
+	PatternHelp:          "glob を入力 (例: tmp/*、!glob で選択解除) • enter: 表示中のブランチに適用 • esc: キャンセル",
+	HelpScroll:           "スクロール",
+	HelpFlatList:         "フラット表示",
+	HelpSelectGroup:      "グループ上で %s: グループを選択",
+	HelpCollapseGroup:    "グループ上で %s: 折りたたみ/展開",
+	StatusSelected:       "%d/%d 件選択中",
+	StatusRefreshing:     "再読み込み中…",
+	StatusDetachedHead:   "detached HEAD (全ブランチを表示)",
+	StatusMergeBase:      "%s に対するマージ状態",
+	StatusShowing:        "(%s を表示)",
+	StatusSortedBy:       "並び順: %s",
+	SortByName:           "名前",
+	SortOldestFirst:      "古い順",
+	SortNewestFirst:      "新しい順",
+	FilterAll:            "すべて",
+	FilterMergedOnly:     "マージ済みのみ",
+	FilterUnmergedOnly:   "未マージのみ",
+	FilterAuthoredBy:     "作成者 %s",
+	FilterCommittedBy:    "コミッター %s",
+	NoFilterMatch:        "フィルタに一致するブランチはありません。",
+	NoFilterMatchWith:    "フィルタに一致するブランチはありません (%s)。",
+	NoMergedBranches:     "%s にマージ済みのブランチはありません。",
+	NoUnmergedBranches:   "%s にないコミットを持つブランチはありません。",
+	NoCommitDate:         "⚠ コミット日時なし",
+	Ago:                  "%s前",
+	JustNow:              "たった今",
+	UnknownDate:          "不明",
+	BadgeMerged:          "マージ済",
+	BadgeSquashed:        "squash済",
+	BadgeUnmerged:        "未マージ",
+	WorktreeTag:          "[⎇ ワークツリー]",
+	StaleWorktreeTag:     "[⎇ 古いワークツリー]",
+
+	HeaderOnBranch:            "%s 上",
+	HeaderDetachedHead:        "detached HEAD",
+	HeaderBase:                "ベース %s",
+	HeaderSubmodule:           "(%s のサブモジュール)",
+	HeaderOperationInProgress: "⚠ %s の途中",
+	HeaderMine:                "%s のブランチのみ",
+
+	RemoteDeletionWarning:        "⚠ これらのブランチはリモートサーバーから削除されます!",
+	RemoteDeletionQuestion:       "これらのリモートブランチを削除してもよろしいですか?",
+	ForceDeletionWarning:         "⚠ 強制削除: これらのブランチの未マージのコミットは失われます (git branch -D)",
+	ForceDeletionQuestion:        "これらのブランチを強制削除してもよろしいですか?",
+	DeletionQuestion:             "これらのブランチを削除してもよろしいですか?",
+	DefaultBranchWarning:         "⚠ '%s' はこのリポジトリのデフォルトブランチです: クローン、CI、プルリクエストが依存しています!",
+	ConfirmationTotal:            "合計: %s",
+	RemoteFetchPruneWarning:      "他のクローンでも次回の fetch --prune でこれらのブランチが消えます。",
+	ExpireReflogWarning:          "削除後に reflog を期限切れにして git gc を実行します (--expire-reflog): 削除したコミットは復元できなくなります。",
+	DryRunNote:                   "ドライラン: 実際にはブランチを削除しません。",
+	PlanNote:                     "プラン: ブランチは削除されず、レビュー用に %[1]s に書き込まれます (削除するには gelete apply %[1]s)。",
+	HelpMove:                     "移動",
+	HelpDeleteSafely:             "通常の削除に戻す",
+	AlsoDelete:                   "%s も削除",
+	WorktreesTitle:               "⚠ ワークツリーでチェックアウトされているブランチがあります",
+	WorktreesIntro:               "ブランチを削除する前に、次のワークツリーを削除する必要があります:",
+	DirtyWorktreesSkipped:        "%sに未コミットの変更があります: 変更を破棄しない限り、そのブランチはスキップされます。",
+	HelpRemoveWorktrees:          "ワークツリーを削除",
+	HelpRemoveCleanWorktrees:     "変更のないワークツリーを削除",
+	HelpSkipBranches:             "これらのブランチをスキップ",
+	HelpBackToSelection:          "選択に戻る",
+	LockedWorktreesTitle:         "⚠ 警告: ロックされたワークツリーがあります",
+	LockedWorktreesIntro:         "次のワークツリーはロックされています:",
+	LockedWorktreesRemoval:       "強制削除すると %sとその未コミットの変更がすべて削除されます。",
+	HelpForceRemove:              "強制削除",
+	HelpForceRemoveClean:         "変更のないワークツリーを強制削除",
+	CheckedOutTitle:              "⚠ 警告: ワークツリーでチェックアウトされているブランチ",
+	CheckedOutIntro:              "次のブランチは他のワークツリーでチェックアウトされているため、git が削除を拒否しました:",
+	CheckedOutRemoval:            "%sを削除すると、そのブランチを削除できます。",
+	HelpRemoveAndRetry:           "ワークツリーを削除して再試行",
+	HelpRemoveCleanAndRetry:      "変更のないワークツリーを削除して再試行",
+	UnmergedTitle:                "⚠ 警告: 未マージのブランチがあります",
+	UnmergedIntro:                "次のブランチには未マージの変更があります:",
+	UnmergedRemoval:              "強制削除すると %sが完全に削除されます。",
+	CannotBeUndone:               "この操作は取り消せません!",
+	SquashTitle:                  "✓ squash でマージ済みのブランチ",
+	SquashIntro:                  "git はこれらのブランチを未マージと判断していますが、内容はマージ済みのようです:",
+	SquashRemoval:                "%sを強制削除しても変更は失われないはずです。",
+	SquashedInto:                 "内容は squash で %s にマージ済みのようです",
+	TipUnreachable:               "このブランチのコミットは他の参照から到達できず、dangling になります",
+	TipUnchecked:                 "他の参照からの到達可能性は未確認です (確認に失敗したか、%s 以上かかりました)",
+	HelpForceDelete:              "強制削除",
+	HelpForceDeleteAll:           "すべて強制削除",
+	HelpReviewEach:               "1 つずつ決める",
+	HelpSkipAll:                  "すべてスキップ",
+	ForceReviewTitle:             "未マージのブランチを 1 つずつ強制削除 (%d/%d)",
+	DecisionForceDelete:          "強制削除",
+	DecisionSkip:                 "スキップ",
+	LoadingCommits:               "コミットを読み込んでいます…",
+	CommitsOnlyOn:                "%s にないコミット (強制削除すると失われます):",
+	NoCommitsOnlyOn:              "%s にないコミットはありません。",
+	LatestCommitsOnly:            "(最新の %d 件のみ表示)",
+	HelpSkipBranch:               "スキップ",
+	HelpPreviousNext:             "前へ/次へ",
+	HelpBackToChoices:            "戻る",
+	TypeToConfirm:                "確認するには %s または %d を入力してください",
+	TypedConfirmationThreshold:   "%sを超える削除",
+	TypedConfirmationForce:       "強制削除",
+	TypedConfirmationRequirement: "%s を確定するには %q またはブランチ数 (%d) の入力が必要です。",
+	TypedConfirmationHelp:        "%s または %d を入力 • enter: 確定 • esc: キャンセル",
+	AnalyzingBranches:            "選択したブランチを分析しています…",
+	AnalysisFailed:               "ブランチを分析できませんでした: %s",
+	RiskProtected:                "保護対象 (保護が無効なため削除可能):",
+	RiskWorktree:                 "ワークツリーでチェックアウト中 (先にワークツリーを削除):",
+	RiskUnmerged:                 "未マージ (強制削除のみ可能):",
+	RiskUnknown:                  "状態不明:",
+	RiskMerged:                   "マージ済み:",
+	StashesKept:                  "このブランチで作成された %sは残ります",
+	AlsoDeleteTag:                "タグ %s も削除",
+	AlsoDeleteTags:               "タグ %s も削除",
+	TagKept:                      "タグ %s は残ります: %s の先端にもあります",
+
+	DeletingTitle:             "ブランチを削除しています...",
+	PleaseWait:                "お待ちください...",
+	CancellingAfter:           "%s の後でキャンセルします… (もう一度 ctrl+c ですぐに終了)",
+	HelpCancelRun:             "ctrl+c: キャンセル",
+	EarlierResults:            "… 以前の %s",
+	PhaseRemovingWorktree:     "ワークツリーを削除中",
+	PhaseForceDeleting:        "強制削除中",
+	PhaseWritingBundle:        "バックアップ bundle を書き込み中",
+	PhaseBackingUp:            "バックアップ中",
+	PhaseChecking:             "確認中",
+	PhaseDeleting:             "削除中",
+	WaitingForLock:            "ロック %s の解放を待っています…",
+	DoneTitle:                 "削除完了",
+	CancelledTitle:            "削除をキャンセルしました",
+	DoneCounts:                "削除 %d 件 • スキップ %d 件 • 失敗 %d 件",
+	NotifyDeleted:             "gelete: %s を削除しました",
+	NotifyDeletedWithFailures: "gelete: %s を削除しました (失敗 %d 件)",
+	AlreadyGoneOne:            "1 件のブランチは既に削除されていました",
+	AlreadyGoneOther:          "%d 件のブランチは既に削除されていました",
+	AlsoDeletedRemote:         "✓ %sも削除しました",
+	RemoteDeletionFailed:      "✗ %sを削除できませんでした (ローカルブランチは削除済み):",
+	AlsoDeletedTags:           "✓ %sも削除しました",
+	TagDeletionFailed:         "✗ %sを削除できませんでした (ブランチは削除済み):",
+	BackedUpAsTags:            "✓ %sをタグにバックアップしました:",
+	BackupTagRestore:          "%s (復元: %s)",
+	BackedUpToBundle:          "✓ %sを %s にバックアップしました",
+	BundleRestore:             "復元: %s",
+	ResultRestored:            "↺ %s: %s に復元しました",
+	ResultDeleted:             "✓ %s: 削除しました%s",
+	ResultForceDeleted:        "✓ %s: 強制削除しました%s",
+	ResultSkipped:             "⊘ %s: スキップ (%s)",
+	ResultAlreadyGone:         "○ %s: gelete の外で既に削除されていました",
+	ResultCheckedOut:          "✗ %s は %s でチェックアウトされています — 先にそのワークツリーを削除するか、ブランチを切り替えてください",
+	ResultLocked:              "✗ %s: ロックファイル %s が存在します — 別の git プロセスか IDE がロックを保持している可能性があります。どちらも動いていなければファイルを削除してください",
+	ResultFailed:              "✗ %s: 失敗: %s",
+	RestoreHint:               " (元は %[1]s — 復元: git branch %[2]s %[1]s)",
+	RestoreTitle:              "削除したブランチの復元",
+	RestoreHelp:               "↑/k: 上へ • ↓/j: 下へ • space: 選択切り替え • enter/r: 選択したものを復元 • esc: 戻る",
+	RestoredCount:             "↺ %sを復元しました",
+	RestoreFailed:             "✗ %sを復元できませんでした:",
+	DoneHelpCompacting:        "ctrl+c: git gc を止めて終了",
+	DoneHelpCompaction:        "g: reflog を期限切れにして git gc を実行 • u: 元に戻す (削除したブランチを復元) • その他のキー: 終了",
+	DoneHelpUndo:              "u: 元に戻す (削除したブランチを復元) • その他のキー: 終了",
+	PressAnyKey:               "いずれかのキーで終了します。",
+	DryRunTitle:               "ドライランの概要",
+	DryRunIntro:               "ブランチは削除されていません。実行されるはずだった操作は次のとおりです:",
+	DryRunCheckFailed:         "✗ %sを確認できませんでした:",
+	DryRunRemote:              "%s: リモートから削除されます",
+	DryRunMerged:              "%s (マージ済み): 削除されます",
+	DryRunForce:               "%s (未マージ): 強制削除されます",
+	DryRunSkip:                "%s (未マージ): スキップされます",
+	DryRunWorktree:            "%s のワークツリーが削除されます",
+	DryRunAlsoRemote:          "%s も削除されます",
+	DryRunAlsoTag:             "タグ %s も削除されます",
+	DryRunAlsoTags:            "タグ %s も削除されます",
+
+	NoGoneBranches:         "upstream が削除されたブランチはありません。",
+	NoCommitsYet:           "リポジトリにはまだコミットがありません。削除するものはありません。",
+	NoMineBranches:         "あなたのブランチはありません (%s)。",
+	CurrentBranchExcluded:  "(現在のブランチは一覧から除外されています)",
+	NoRemoteBranches:       "削除できるリモートブランチはありません。",
+	VerboseLogWritten:      "詳細ログを %s に書き込みました",
+	NotARepository:         "git リポジトリではありません",
+	ExpireReflogWithRemote: "--expire-reflog は --remote と併用できません",
+	BackupBundleWithRemote: "--backup-bundle は --remote と併用できません",
+	JSONWithRemote:         "--json は --remote と併用できません",
+	ArgsWithRemote:         "ブランチの引数は --remote と併用できません。リモートブランチは gelete --remote の TUI で選択してください",
+	JSONNeedsYes:           "ブランチを削除するときは --json に --yes (または --dry-run) が必要です",
+	QuietWithJSON:          "--quiet は --json と併用できません",
+	QuietNeedsYes:          "確認プロンプトが表示されないため、ブランチを削除するときは --quiet に --yes が必要です",
+	MergeFilterWithRemote:  "--merged-only と --unmerged-only は --remote と併用できません",
+	MergeFilterWithArgs:    "--merged-only と --unmerged-only はブランチの引数と併用できません",
+	MergeFilterNeedsBase:   "--merged-only/--unmerged-only のベースブランチを判定できません。--base を指定するか、設定で base_branch を指定してください",
+	OperationInProgress:    "%s の途中です。gelete を実行する前に完了または中止してください (それでも起動するには --force-start)",
+	MineWithRemote:         "--mine は --remote と併用できません",
+	MineWithArgs:           "--mine はブランチ引数と併用できません",
+	MineNeedsEmail:         "--mine にはメールアドレスが必要です。git config user.email <email> で設定してください",
+
+	WorktreeSelectionTitle:   "gelete - 対話式ワークツリー削除",
+	NoWorktreesToRemove:      "削除できるワークツリーはありません。",
+	WorktreeSelectionHelp:    "↑/k: 上へ • ↓/j: 下へ • space/enter: 選択切り替え • a: 全選択/全解除 • d: 選択したものを削除 • q: 終了",
+	WorktreeLockedTag:        "[ロック中]",
+	WorktreeMissingTag:       "[ディレクトリなし]",
+	WorktreeModified:         "更新 %s",
+	WorktreeDetachedHead:     "(detached HEAD)",
+	WorktreeRemovalQuestion:  "これらのワークツリーを削除してもよろしいですか?",
+	WorktreeConfirmHelp:      "y: 確定 • n: キャンセル",
+	WorktreeLockedTitle:      "⚠ ロックされたワークツリーがあります",
+	WorktreeLockedHelp:       "y: 強制削除 • n: これらのワークツリーを残す",
+	FreedBranchQuestion:      "ブランチ %s も削除しますか?",
+	FreedBranchProgress:      "ワークツリーは削除されました (%d/%d)",
+	FreedBranchMerged:        "%s にマージ済み",
+	FreedBranchUnmerged:      "%s に未マージ: 強制削除は別途確認します",
+	FreedBranchHelp:          "y: ブランチを削除 • n: ブランチを残す",
+	FreedUnmergedTitle:       "⚠ 完全にはマージされていないブランチがあります",
+	FreedUnmergedRemoval:     "強制削除すると、他のどこにもマージされていないコミットを持つ %sが削除されます。",
+	FreedUnmergedHelp:        "y: 強制削除 • n: これらのブランチを残す",
+	CleaningUpTitle:          "クリーンアップしています...",
+	PhaseDeletingBranch:      "ブランチを削除中",
+	PhaseForceDeletingBranch: "ブランチを強制削除中",
+	CancellingCleanup:        "キャンセルしています… (もう一度 ctrl+c ですぐに終了)",
+	CleanupDoneTitle:         "クリーンアップ完了",
+	CleanupCancelledTitle:    "クリーンアップをキャンセルしました",
+	WorktreeRemoved:          "✓ %s: 削除しました",
+	FreedBranchDeleted:       "✓ ブランチ %s: 削除しました",
+	FreedBranchFailed:        "✗ ブランチ %s: 失敗: %s",
+	FreedBranchKept:          "• ブランチ %s: 残しました",
+	CleanupCounts:            "%sを削除 • %sを削除 • 失敗 %d 件",
+
+	TrackingRefSelectionTitle:    "gelete - 古いリモート追跡参照の整理",
+	TrackingRefRemoteGroup:       "%s: リモートで削除されたブランチ",
+	TrackingRefSelectionHelp:     "↑/k: 上へ • ↓/j: 下へ • space/enter: 選択切り替え • a: 全選択/全解除 • d: 選択したものを整理 • q: 終了",
+	TrackingRefRemovalQuestion:   "これらのリモート追跡参照を削除してもよろしいですか?",
+	TrackingRefConfirmationTotal: "合計: %s。削除されるのはローカルの参照のみで、リモートはそのままです。",
+	TrackingRefConfirmHelp:       "y: 確定 • n: キャンセル",
+	PruningTitle:                 "整理しています...",
+	CancellingPrune:              "キャンセルしています… (もう一度 ctrl+c ですぐに終了)",
+	PruningDoneTitle:             "整理完了",
+	PruningCancelledTitle:        "整理をキャンセルしました",
+	TrackingRefPruned:            "✓ %s: 削除しました",
+	PruneCounts:                  "%sを削除 • 失敗 %d 件",
+}
+
+// japaneseNouns holds each noun; Japanese nouns don't change with the count
+var japaneseNouns = map[Noun]string{
+	Branch:         "ブランチ",
+	RemoteBranch:   "リモートブランチ",
+	UnmergedBranch: "未マージのブランチ",
+	Worktree:       "ワークツリー",
+	LockedWorktree: "ロックされたワークツリー",
+	EarlierResult:  "結果",
+	TrackingRef:    "参照",
+	Tag:            "タグ",
+	StashEntry:     "stash エントリ",
+}
+
+// japaneseUnits holds the units of time, which are counters themselves and so take no 件
+var japaneseUnits = map[Noun]string{
+	Second: "秒",
+	Minute: "分",
+	Hour:   "時間",
+	Day:    "日",
+	Week:   "週間",
+	Month:  "か月",
+	Year:   "年",
+}
+
+// countJapanese counts with the 件 counter, e.g. "3 件のブランチ", or with the unit for units of time, e.g. "3 か月"
+func countJapanese(n int, noun Noun) string {
+	if unit, ok := japaneseUnits[noun]; ok {
+		return strconv.Itoa(n) + " " + unit
+	}
+	return strconv.Itoa(n) + " 件の" + japaneseNouns[noun]
+}
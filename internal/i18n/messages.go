@@ -0,0 +1,271 @@
+package i18n
+
+// MessageID identifies a message in the catalogs of every language
+type MessageID int
+
+// Messages of the branch list
+const (
+	SwitchedToBranch MessageID = iota
+	SelectionTitle
+	RemoteSelectionTitle
+	NoBranchesToDelete
+	PressKeyToQuit
+	ErrorLine
+	WarningLine
+	ShowingRows
+	RenamePrompt
+	SelectMatchingPrompt
+	FilterHelp
+	RenameHelp
+	PatternHelp
+	HelpScroll
+	HelpFlatList
+	HelpSelectGroup
+	HelpCollapseGroup
+	StatusSelected
+	StatusRefreshing
+	StatusDetachedHead
+	StatusMergeBase
+	StatusShowing
+	StatusSortedBy
+	SortByName
+	SortOldestFirst
+	SortNewestFirst
+	FilterAll
+	FilterMergedOnly
+	FilterUnmergedOnly
+	FilterAuthoredBy
+	FilterCommittedBy
+	NoFilterMatch
+	NoFilterMatchWith
+	NoMergedBranches
+	NoUnmergedBranches
+	NoCommitDate
+	Ago
+	JustNow
+	UnknownDate
+	BadgeMerged
+	BadgeSquashed
+	BadgeUnmerged
+	WorktreeTag
+	StaleWorktreeTag
+)
+
+// Messages of the context header
+const (
+	HeaderOnBranch MessageID = iota + 1000
+	HeaderDetachedHead
+	HeaderBase
+	HeaderSubmodule
+	HeaderOperationInProgress
+	HeaderMine
+)
+
+// Messages of the prompts confirming a deletion
+const (
+	RemoteDeletionWarning MessageID = iota + 2000
+	RemoteDeletionQuestion
+	ForceDeletionWarning
+	ForceDeletionQuestion
+	DeletionQuestion
+	DefaultBranchWarning
+	ConfirmationTotal
+	RemoteFetchPruneWarning
+	ExpireReflogWarning
+	DryRunNote
+	PlanNote
+	HelpMove
+	HelpDeleteSafely
+	AlsoDelete
+	WorktreesTitle
+	WorktreesIntro
+	DirtyWorktreesSkipped
+	HelpRemoveWorktrees
+	HelpRemoveCleanWorktrees
+	HelpSkipBranches
+	HelpBackToSelection
+	LockedWorktreesTitle
+	LockedWorktreesIntro
+	LockedWorktreesRemoval
+	HelpForceRemove
+	HelpForceRemoveClean
+	CheckedOutTitle
+	CheckedOutIntro
+	CheckedOutRemoval
+	HelpRemoveAndRetry
+	HelpRemoveCleanAndRetry
+	UnmergedTitle
+	UnmergedIntro
+	UnmergedRemoval
+	CannotBeUndone
+	SquashTitle
+	SquashIntro
+	SquashRemoval
+	SquashedInto
+	TipUnreachable
+	TipUnchecked
+	HelpForceDelete
+	HelpForceDeleteAll
+	HelpReviewEach
+	HelpSkipAll
+	ForceReviewTitle
+	DecisionForceDelete
+	DecisionSkip
+	LoadingCommits
+	CommitsOnlyOn
+	NoCommitsOnlyOn
+	LatestCommitsOnly
+	HelpSkipBranch
+	HelpPreviousNext
+	HelpBackToChoices
+	TypeToConfirm
+	TypedConfirmationThreshold
+	TypedConfirmationForce
+	TypedConfirmationRequirement
+	TypedConfirmationHelp
+	AnalyzingBranches
+	AnalysisFailed
+	RiskProtected
+	RiskWorktree
+	RiskUnmerged
+	RiskUnknown
+	RiskMerged
+	StashesKept
+	AlsoDeleteTag
+	AlsoDeleteTags
+	TagKept
+)
+
+// Messages of the deletion run and the done screen
+const (
+	DeletingTitle MessageID = iota + 3000
+	PleaseWait
+	CancellingAfter
+	HelpCancelRun
+	EarlierResults
+	PhaseRemovingWorktree
+	PhaseForceDeleting
+	PhaseWritingBundle
+	PhaseBackingUp
+	PhaseChecking
+	PhaseDeleting
+	WaitingForLock
+	DoneTitle
+	CancelledTitle
+	DoneCounts
+	NotifyDeleted
+	NotifyDeletedWithFailures
+	AlreadyGoneOne
+	AlreadyGoneOther
+	AlsoDeletedRemote
+	RemoteDeletionFailed
+	AlsoDeletedTags
+	TagDeletionFailed
+	BackedUpAsTags
+	BackupTagRestore
+	BackedUpToBundle
+	BundleRestore
+	ResultRestored
+	ResultDeleted
+	ResultForceDeleted
+	ResultSkipped
+	ResultAlreadyGone
+	ResultCheckedOut
+	ResultLocked
+	ResultFailed
+	RestoreHint
+	RestoreTitle
+	RestoreHelp
+	RestoredCount
+	RestoreFailed
+	DoneHelpCompacting
+	DoneHelpCompaction
+	DoneHelpUndo
+	PressAnyKey
+	DryRunTitle
+	DryRunIntro
+	DryRunCheckFailed
+	DryRunRemote
+	DryRunMerged
+	DryRunForce
+	DryRunSkip
+	DryRunWorktree
+	DryRunAlsoRemote
+	DryRunAlsoTag
+	DryRunAlsoTags
+)
+
+// Messages of the command line
+const (
+	NoGoneBranches MessageID = iota + 4000
+	NoCommitsYet
+	NoMineBranches
+	CurrentBranchExcluded
+	NoRemoteBranches
+	VerboseLogWritten
+	NotARepository
+	ExpireReflogWithRemote
+	BackupBundleWithRemote
+	JSONWithRemote
+	ArgsWithRemote
+	JSONNeedsYes
+	QuietWithJSON
+	QuietNeedsYes
+	MergeFilterWithRemote
+	MergeFilterWithArgs
+	MergeFilterNeedsBase
+	OperationInProgress
+	MineWithRemote
+	MineWithArgs
+	MineNeedsEmail
+)
+
+// Messages of the worktrees command
+const (
+	WorktreeSelectionTitle MessageID = iota + 5000
+	NoWorktreesToRemove
+	WorktreeSelectionHelp
+	WorktreeLockedTag
+	WorktreeMissingTag
+	WorktreeModified
+	WorktreeDetachedHead
+	WorktreeRemovalQuestion
+	WorktreeConfirmHelp
+	WorktreeLockedTitle
+	WorktreeLockedHelp
+	FreedBranchQuestion
+	FreedBranchProgress
+	FreedBranchMerged
+	FreedBranchUnmerged
+	FreedBranchHelp
+	FreedUnmergedTitle
+	FreedUnmergedRemoval
+	FreedUnmergedHelp
+	CleaningUpTitle
+	PhaseDeletingBranch
+	PhaseForceDeletingBranch
+	CancellingCleanup
+	CleanupDoneTitle
+	CleanupCancelledTitle
+	WorktreeRemoved
+	FreedBranchDeleted
+	FreedBranchFailed
+	FreedBranchKept
+	CleanupCounts
+)
+
+// Messages of the remote prune command
+const (
+	TrackingRefSelectionTitle MessageID = iota + 6000
+	TrackingRefRemoteGroup
+	TrackingRefSelectionHelp
+	TrackingRefRemovalQuestion
+	TrackingRefConfirmationTotal
+	TrackingRefConfirmHelp
+	PruningTitle
+	CancellingPrune
+	PruningDoneTitle
+	PruningCancelledTitle
+	TrackingRefPruned
+	PruneCounts
+)
@@ -0,0 +1,87 @@
+// Package ignore reads .geleteignore files, which hide branches from gelete like .gitignore hides files:
+// one glob per line, "#" comments, blank lines, and "!" to include a branch again.
+package ignore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/git"
+)
+
+// FileName is the name of the ignore file, at the top of the working tree (checked in and shared)
+// or in the repository's git directory (personal)
+const FileName = ".geleteignore"
+
+// Paths returns the ignore files of the current repository, in order of increasing precedence:
+// the one at the top of the working tree, then the one in the git directory. Both are found from the
+// repository root, so gelete finds them from any subdirectory. Bare repositories only have the latter.
+func Paths() []string {
+	var paths []string
+	if root, err := git.GetTopLevel(); err == nil {
+		paths = append(paths, filepath.Join(root, FileName))
+	}
+	if gitDir, err := git.GetCommonDir(); err == nil {
+		paths = append(paths, filepath.Join(gitDir, FileName))
+	}
+	return paths
+}
+
+// Load reads the ignore files of the current repository (see Paths)
+func Load() ([]git.IgnoreRule, error) {
+	return LoadFiles(Paths()...)
+}
+
+// LoadFiles reads the rules of the given ignore files in order, so the rules of later files take precedence.
+// Missing files and empty paths are skipped.
+func LoadFiles(paths ...string) ([]git.IgnoreRule, error) {
+	var rules []git.IgnoreRule
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read '%s': %w", path, err)
+		}
+
+		parsed, err := Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse '%s': %w", path, err)
+		}
+		rules = append(rules, parsed...)
+	}
+	return rules, nil
+}
+
+// Parse parses the content of an ignore file. Lines starting with "#" and blank lines are skipped, and a leading
+// "!" includes the matching branches again; "\#" and "\!" start a pattern with a literal "#" or "!".
+// Malformed globs are refused with their line number.
+func Parse(content string) ([]git.IgnoreRule, error) {
+	var rules []git.IgnoreRule
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := git.IgnoreRule{Pattern: line}
+		if pattern, negated := strings.CutPrefix(line, "!"); negated {
+			rule = git.IgnoreRule{Pattern: strings.TrimSpace(pattern), Negate: true}
+		}
+		rule.Pattern = strings.TrimPrefix(rule.Pattern, `\`)
+
+		if err := git.ValidatePattern(rule.Pattern); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,72 @@
+// Package log records the git commands gelete runs, with their duration and why they failed.
+// Logging is off until an output is set, which gelete does for --verbose.
+package log
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// mu serializes writes, since the TUI runs git commands concurrently
+	mu sync.Mutex
+
+	// output receives the log; nil disables logging
+	output io.Writer
+)
+
+// SetOutput sends the log to w; nil disables logging
+func SetOutput(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	output = w
+}
+
+// Enabled reports whether logging is on
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return output != nil
+}
+
+// Command logs a git command that ran in dir for duration. If it failed, the error and
+// what git printed (detail) are logged as well, so the exact failing invocation is known.
+func Command(dir string, args []string, duration time.Duration, err error, detail []byte) {
+	mu.Lock()
+	defer mu.Unlock()
+	if output == nil {
+		return
+	}
+
+	line := fmt.Sprintf("git %s (%s)", quoteArgs(args), duration.Round(time.Microsecond))
+	if dir != "" {
+		line += " in " + dir
+	}
+	if err != nil {
+		line += ": " + err.Error()
+	}
+	fmt.Fprintln(output, line)
+
+	if err != nil {
+		for _, detailLine := range strings.Split(strings.TrimSpace(string(detail)), "\n") {
+			if detailLine != "" {
+				fmt.Fprintln(output, "    "+detailLine)
+			}
+		}
+	}
+}
+
+// quoteArgs joins command arguments, quoting those that a shell would split or that are empty
+func quoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\$*?[]{}()<>|&;#~\x00") {
+			arg = fmt.Sprintf("%q", arg)
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
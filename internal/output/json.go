@@ -0,0 +1,109 @@
+// Package output defines the machine-readable representation of gelete's results.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/git"
+)
+
+// Branch is the JSON representation of a deletable local branch
+type Branch struct {
+	// Name is the short branch name
+	Name string `json:"name"`
+
+	// Merged reports whether the branch is merged into the default branch (null if unknown)
+	Merged *bool `json:"merged"`
+
+	// LastCommitDate is the committer date of the branch tip
+	LastCommitDate time.Time `json:"last_commit_date"`
+
+	// LastCommitter is the committer name of the branch tip
+	LastCommitter string `json:"last_committer"`
+
+	// Worktree is the worktree the branch is checked out in, if any
+	Worktree *Worktree `json:"worktree,omitempty"`
+}
+
+// Worktree is the JSON representation of a git worktree
+type Worktree struct {
+	// Path is the absolute path to the worktree directory
+	Path string `json:"path"`
+
+	// Locked indicates if the worktree is locked
+	Locked bool `json:"locked"`
+
+	// Missing indicates the worktree directory no longer exists
+	Missing bool `json:"missing"`
+}
+
+// NewBranch builds the JSON representation of a branch.
+// merged is nil when the merge status could not be determined; worktree is nil if the branch has none.
+func NewBranch(info git.BranchInfo, merged *bool, worktree *git.Worktree) Branch {
+	branch := Branch{
+		Name:           info.Name,
+		Merged:         merged,
+		LastCommitDate: info.LastCommitDate,
+		LastCommitter:  info.LastCommitter,
+	}
+	if worktree != nil {
+		branch.Worktree = &Worktree{Path: worktree.Path, Locked: worktree.Locked, Missing: worktree.Missing}
+	}
+	return branch
+}
+
+// Status is the outcome of a requested branch deletion
+type Status string
+
+const (
+	// StatusDeleted means the branch was deleted
+	StatusDeleted Status = "deleted"
+	// StatusSkipped means the branch was intentionally left in place (e.g. in dry-run mode)
+	StatusSkipped Status = "skipped"
+	// StatusFailed means the branch could not be deleted
+	StatusFailed Status = "failed"
+)
+
+// Result is the JSON representation of the outcome of deleting one branch
+type Result struct {
+	// Branch is the requested branch name
+	Branch string `json:"branch"`
+
+	// Status is the outcome of the deletion
+	Status Status `json:"status"`
+
+	// Forced reports whether the branch was (or would be) force deleted
+	Forced bool `json:"forced,omitempty"`
+
+	// SHA is the branch tip before deletion, for restoring it
+	SHA string `json:"sha,omitempty"`
+
+	// Error explains why the branch was skipped or not deleted
+	Error string `json:"error,omitempty"`
+}
+
+// BatchReport is the JSON representation of a deletion run with --yes or --json: the results along with the
+// local branches before and after the run, for auditing what it changed
+type BatchReport struct {
+	// Before lists every local branch before the run, in alphabetical order
+	Before []string `json:"before"`
+
+	// Results is the outcome of each requested deletion, in the order requested
+	Results []Result `json:"results"`
+
+	// After lists every local branch left after the run (the same as Before in a dry run)
+	After []string `json:"after"`
+}
+
+// WriteJSON writes v to w as indented JSON followed by a newline
+func WriteJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to write JSON output: %w", err)
+	}
+	return nil
+}
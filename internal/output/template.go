@@ -0,0 +1,105 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/git"
+)
+
+// FormatBranch is a deletable local branch as the templates of gelete list --format see it
+type FormatBranch struct {
+	// Name is the short branch name
+	Name string
+
+	// SHA is the commit the branch points to
+	SHA string
+
+	// LastCommitDate is the committer date of the branch tip in RFC 3339, e.g. 2024-05-01T12:00:00+02:00
+	LastCommitDate string
+
+	// Age is how long ago the branch tip was committed, e.g. "3 weeks ago"
+	Age string
+
+	// Merged reports whether the branch is merged into the base branch; false if that is unknown
+	Merged bool
+
+	// Upstream is the remote branch the branch tracks, e.g. origin/feature-x (empty if none)
+	Upstream string
+
+	// UpstreamGone reports whether the upstream branch was deleted on the remote
+	UpstreamGone bool
+
+	// WorktreePath is the worktree the branch is checked out in (empty if none)
+	WorktreePath string
+
+	// Ahead and Behind count the commits only on the branch and only on the base branch (0 if unknown)
+	Ahead  int
+	Behind int
+}
+
+// NewFormatBranch builds what a template sees of a branch. worktree and divergence are nil if the branch
+// has no worktree or its divergence from the base branch wasn't computed.
+func NewFormatBranch(info git.BranchInfo, merged bool, worktree *git.Worktree, divergence *git.AheadBehind) FormatBranch {
+	branch := FormatBranch{
+		Name:         info.Name,
+		SHA:          info.SHA,
+		Age:          info.RelativeAge,
+		Merged:       merged,
+		UpstreamGone: info.Gone,
+	}
+	if !info.LastCommitDate.IsZero() {
+		branch.LastCommitDate = info.LastCommitDate.Format(time.RFC3339)
+	}
+	if info.Upstream.Remote != "" {
+		branch.Upstream = info.Upstream.String()
+	}
+	if worktree != nil {
+		branch.WorktreePath = worktree.Path
+	}
+	if divergence != nil {
+		branch.Ahead, branch.Behind = divergence.Ahead, divergence.Behind
+	}
+	return branch
+}
+
+// formatEscapes turns the escapes a format typed in single quotes keeps, like \t, into the characters they stand for
+var formatEscapes = strings.NewReplacer(`\t`, "\t", `\n`, "\n")
+
+// ParseFormat parses a gelete list --format template, in which \t and \n stand for a tab and a newline.
+// The template is tried on an empty branch, so unknown fields are reported before listing any branch.
+// Errors tell where in the template the problem is, e.g. "template: format:1: unclosed action".
+func ParseFormat(format string) (*template.Template, error) {
+	tmpl, err := template.New("format").Option("missingkey=error").Parse(formatEscapes.Replace(format))
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(io.Discard, FormatBranch{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// WriteFormatted writes each branch with the template, one line per branch. Branches the template prints
+// nothing for are left out, so a template like {{if .Merged}}{{.Name}}{{end}} filters the list.
+func WriteFormatted(w io.Writer, tmpl *template.Template, branches []FormatBranch) error {
+	var line bytes.Buffer
+	for _, branch := range branches {
+		line.Reset()
+		if err := tmpl.Execute(&line, branch); err != nil {
+			return fmt.Errorf("failed to format branch '%s': %w", branch.Name, err)
+		}
+		if line.Len() == 0 {
+			continue
+		}
+		line.WriteByte('\n')
+		if _, err := w.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Package plan reads and writes deletion plans: the branches selected with gelete plan, written to a file
+// for review instead of being deleted, and deleted later with gelete apply.
+package plan
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the version of the plan format written by this gelete; other versions are refused
+const Version = 1
+
+// Action is a step of deleting a planned branch
+type Action string
+
+const (
+	// RemoveWorktree removes the worktree the branch is checked out in before deleting it
+	RemoveWorktree Action = "remove-worktree"
+	// Delete deletes the branch with git branch -d, which refuses unmerged branches
+	Delete Action = "delete"
+	// ForceDelete deletes the branch with git branch -D
+	ForceDelete Action = "force-delete"
+)
+
+// Plan is the content of a plan file
+type Plan struct {
+	// Version is the version of the plan format
+	Version int `yaml:"version"`
+
+	// Repository is the top-level directory of the repository the plan was made in
+	Repository string `yaml:"repository"`
+
+	// Base is the branch the merge status of the entries was computed against, if known
+	Base string `yaml:"base,omitempty"`
+
+	// Created is when the plan was made
+	Created time.Time `yaml:"created"`
+
+	// Entries lists the planned branches in the order they are deleted
+	Entries []Entry `yaml:"branches"`
+}
+
+// Entry is a planned branch and what is done to it
+type Entry struct {
+	// Branch is the name of the branch
+	Branch string `yaml:"branch"`
+
+	// SHA is the full SHA of the branch tip when the plan was made; the entry is skipped if the tip moved
+	SHA string `yaml:"sha"`
+
+	// Merged tells whether the branch was fully merged into the base branch when the plan was made
+	Merged bool `yaml:"merged"`
+
+	// Worktree is the path of the worktree the branch was checked out in, if any
+	Worktree string `yaml:"worktree,omitempty"`
+
+	// Actions lists the steps of deleting the branch, in order
+	Actions []Action `yaml:"actions"`
+}
+
+// Has reports whether the entry includes the action
+func (e Entry) Has(action Action) bool {
+	return slices.Contains(e.Actions, action)
+}
+
+// header is written at the top of plan files, for whoever reviews them
+const header = "# gelete deletion plan: review the branches below, then delete them with gelete apply <this file>.\n" +
+	"# Branches whose tip moved since the plan was made are skipped.\n"
+
+// Write writes the plan to path, replacing the file if it exists
+func Write(path string, p Plan) error {
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+	if err := os.WriteFile(path, append([]byte(header), data...), 0o644); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	return nil
+}
+
+// Read reads the plan at path. Unknown keys, other format versions and malformed entries are errors,
+// so that a plan edited during review never does something other than what it reads as.
+func Read(path string) (Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Plan{}, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	var p Plan
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&p); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Plan{}, fmt.Errorf("invalid plan %s: the file is empty", path)
+		}
+		return Plan{}, fmt.Errorf("invalid plan %s: %w", path, err)
+	}
+	if err := p.validate(); err != nil {
+		return Plan{}, fmt.Errorf("invalid plan %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// validate checks the version of the plan and that every entry names a branch, its tip and a way to delete it
+func (p Plan) validate() error {
+	if p.Version != Version {
+		return fmt.Errorf("unsupported version %d (this gelete reads version %d)", p.Version, Version)
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range p.Entries {
+		switch {
+		case entry.Branch == "":
+			return errors.New("an entry has no branch")
+		case seen[entry.Branch]:
+			return fmt.Errorf("branch '%s' is listed twice", entry.Branch)
+		case entry.SHA == "":
+			return fmt.Errorf("branch '%s' has no sha", entry.Branch)
+		case entry.Has(Delete) == entry.Has(ForceDelete):
+			return fmt.Errorf("branch '%s' must have exactly one of the actions %s and %s", entry.Branch, Delete, ForceDelete)
+		}
+		seen[entry.Branch] = true
+
+		for _, action := range entry.Actions {
+			if !slices.Contains([]Action{RemoveWorktree, Delete, ForceDelete}, action) {
+				return fmt.Errorf("branch '%s' has unknown action '%s': must be %s, %s or %s",
+					entry.Branch, action, RemoveWorktree, Delete, ForceDelete)
+			}
+		}
+	}
+	return nil
+}
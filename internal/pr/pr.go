@@ -0,0 +1,93 @@
+// Package pr looks up the open pull requests of a repository, so gelete can warn before deleting
+// a branch that is still under review. Providers shell out to the forge's own CLI, which handles
+// authentication; gelete never sees a token.
+package pr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ErrUnavailable is returned when the CLI a provider relies on isn't installed
+var ErrUnavailable = errors.New("not installed")
+
+// Info describes the pull request of a branch
+type Info struct {
+	// Number is the pull request number
+	Number int
+
+	// State is the state reported by the forge, e.g. "OPEN"
+	State string
+}
+
+// String returns the annotation shown for the pull request, e.g. "PR #123 (open)"
+func (i Info) String() string {
+	return fmt.Sprintf("PR #%d (%s)", i.Number, strings.ToLower(i.State))
+}
+
+// Provider looks up the open pull requests of a repository
+type Provider interface {
+	// OpenPRs returns the open pull requests keyed by the name of their head branch
+	OpenPRs(ctx context.Context) (map[string]Info, error)
+}
+
+// ghLimit is the number of pull requests requested from gh, which only returns 30 by default
+const ghLimit = 1000
+
+// GitHub looks up pull requests with the gh CLI (https://cli.github.com)
+type GitHub struct {
+	// Dir is the repository directory gh runs in; the working directory if empty
+	Dir string
+}
+
+// OpenPRs lists the open pull requests with `gh pr list`. It fails with ErrUnavailable if gh isn't installed,
+// and with gh's message if the repository has no GitHub remote or gh isn't logged in.
+// If several pull requests share a head branch name (e.g. from forks), the most recent one is kept.
+func (g GitHub) OpenPRs(ctx context.Context) (map[string]Info, error) {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return nil, fmt.Errorf("gh %w", ErrUnavailable)
+	}
+
+	cmd := exec.CommandContext(ctx, "gh", "pr", "list", "--state", "open",
+		"--limit", strconv.Itoa(ghLimit), "--json", "headRefName,number,state")
+	cmd.Dir = g.Dir
+	// Never let gh wait for input the TUI can't give it
+	cmd.Env = append(os.Environ(), "GH_PROMPT_DISABLED=1")
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", ghError(err))
+	}
+
+	var listed []struct {
+		HeadRefName string `json:"headRefName"`
+		Number      int    `json:"number"`
+		State       string `json:"state"`
+	}
+	if err := json.Unmarshal(output, &listed); err != nil {
+		return nil, fmt.Errorf("unexpected gh pr list output: %w", err)
+	}
+
+	prs := make(map[string]Info, len(listed))
+	for _, pr := range listed {
+		if _, seen := prs[pr.HeadRefName]; !seen {
+			prs[pr.HeadRefName] = Info{Number: pr.Number, State: pr.State}
+		}
+	}
+	return prs, nil
+}
+
+// ghError returns the error to report for a failed gh command: the message it printed, if any
+func ghError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return errors.New(strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+// Package session persists branch selections between gelete runs, so an accidental
+// quit doesn't lose a carefully built selection.
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Kdaito/gelete/internal/git"
+)
+
+// FileName is the name of the session file inside the repository's git directory
+const FileName = "gelete-session.json"
+
+// file is the on-disk format of a session
+type file struct {
+	// Repository is the git directory the selection belongs to
+	Repository string `json:"repository"`
+
+	// Selected lists the selected branch names
+	Selected []string `json:"selected"`
+}
+
+// Path returns the session file path of the current repository
+func Path() (string, error) {
+	gitDir, err := git.GetGitDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, FileName), nil
+}
+
+// Save writes the selected branches of the current repository to its session file
+func Save(selected map[string]bool) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	session := file{Repository: filepath.Dir(path), Selected: []string{}}
+	for branch, isSelected := range selected {
+		if isSelected {
+			session.Selected = append(session.Selected, branch)
+		}
+	}
+	sort.Strings(session.Selected)
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// Load reads the selected branches saved for the current repository.
+// Branches that no longer exist are dropped. Returns an empty selection if there is no session.
+func Load() (map[string]bool, error) {
+	selected := make(map[string]bool)
+
+	path, err := Path()
+	if err != nil {
+		return selected, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return selected, nil
+	}
+	if err != nil {
+		return selected, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var session file
+	if err := json.Unmarshal(data, &session); err != nil {
+		return selected, fmt.Errorf("failed to parse session '%s': %w", path, err)
+	}
+
+	// Ignore sessions written for another repository (e.g. a copied .git directory)
+	if session.Repository != filepath.Dir(path) {
+		return selected, nil
+	}
+
+	for _, branch := range session.Selected {
+		if git.BranchExists(branch) {
+			selected[branch] = true
+		}
+	}
+	return selected, nil
+}
+
+// Clear deletes the session file of the current repository, if any
+func Clear() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// branchesAnalyzedMsg carries the pre-flight analysis of the selected branches
+type branchesAnalyzedMsg struct {
+	analyses []git.BranchAnalysis
+	err      error
+}
+
+// riskCategory groups the branches of the confirmation list, riskiest first
+type riskCategory int
+
+const (
+	riskProtected riskCategory = iota
+	riskWorktree
+	riskUnmerged
+	riskUnknown
+	riskMerged
+)
+
+// maxListedRefs is how many tags the warning about a branch's tags and notes names before eliding the rest
+const maxListedRefs = 3
+
+// analyzeBranches returns a command that classifies the selected branches before they are deleted.
+// Unless SkipRefCheck is set, the tags and notes on the own commits of unmerged branches are looked up too.
+func (m AppModel) analyzeBranches(branches []string) tea.Cmd {
+	base, checkRefs := m.previewBase(), !m.SkipRefCheck
+	return func() tea.Msg {
+		analyses, err := git.AnalyzeBranches(branches)
+		for i, analysis := range analyses {
+			if checkRefs && analysis.Status == git.StatusUnmerged {
+				// The check is a warning, so a branch whose refs can't be listed is left without one
+				analyses[i].Refs, _ = git.GetDanglingRefsForBranch(analysis.Name, base)
+			}
+		}
+		return branchesAnalyzedMsg{analyses: analyses, err: err}
+	}
+}
+
+// handleBranchesAnalyzed stores the analysis and regroups the confirmation list,
+// keeping the cursor on the same branch. If the analysis failed, every branch is of unknown status.
+func (m AppModel) handleBranchesAnalyzed(msg branchesAnalyzedMsg) (tea.Model, tea.Cmd) {
+	if m.State != StateConfirmation {
+		return m, nil
+	}
+
+	highlighted, _ := m.confirmedBranch()
+
+	m.Analysis = make(map[string]git.BranchAnalysis)
+	m.AnalysisErr = ""
+	if msg.err != nil {
+		m.AnalysisErr = msg.err.Error()
+	}
+	for _, analysis := range msg.analyses {
+		m.Analysis[analysis.Name] = analysis
+	}
+
+	m.ConfirmCursor = max(0, slices.Index(m.confirmationBranches(), highlighted))
+	return m, nil
+}
+
+// riskOf returns the confirmation group of an analyzed branch
+func (m AppModel) riskOf(branch string) riskCategory {
+	analysis, ok := m.Analysis[branch]
+	switch {
+	case !ok:
+		return riskUnknown
+	case analysis.Protected:
+		return riskProtected
+	case analysis.Worktree != "":
+		return riskWorktree
+	}
+
+	switch analysis.Status {
+	case git.StatusUnmerged:
+		return riskUnmerged
+	case git.StatusUnknown:
+		return riskUnknown
+	}
+	return riskMerged
+}
+
+// confirmationBranches returns the selected branches in the order of the confirmation list:
+// grouped by risk once the analysis is done, in list order before that
+func (m AppModel) confirmationBranches() []string {
+	selected := m.selectedBranches()
+	if m.Analysis == nil {
+		return selected
+	}
+	slices.SortStableFunc(selected, func(a, b string) int { return int(m.riskOf(a)) - int(m.riskOf(b)) })
+	return selected
+}
+
+// confirmedBranch returns the branch highlighted in the confirmation list
+func (m AppModel) confirmedBranch() (string, bool) {
+	branches := m.confirmationBranches()
+	if m.ConfirmCursor >= len(branches) {
+		return "", false
+	}
+	return branches[m.ConfirmCursor], true
+}
+
+// riskHeading returns the heading and style of a confirmation group
+func (m AppModel) riskHeading(risk riskCategory) (string, lipgloss.Style) {
+	switch risk {
+	case riskProtected:
+		return m.t(i18n.RiskProtected), m.Styles.Error
+	case riskWorktree:
+		return m.t(i18n.RiskWorktree), m.Styles.Warning
+	case riskUnmerged:
+		return m.t(i18n.RiskUnmerged), m.Styles.Warning
+	case riskUnknown:
+		return m.t(i18n.RiskUnknown), m.Styles.Help.UnsetMarginTop()
+	}
+	return m.t(i18n.RiskMerged), m.Styles.Success
+}
+
+// renderConfirmationList renders the branches to be deleted, grouped by risk once the analysis is done
+func (m AppModel) renderConfirmationList() string {
+	var b strings.Builder
+
+	if m.Analysis == nil && !m.Remote {
+		b.WriteString(m.Styles.Help.UnsetMarginTop().Render(m.t(i18n.AnalyzingBranches)))
+		b.WriteString("\n")
+	}
+	if m.AnalysisErr != "" {
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.AnalysisFailed, firstLine(m.AnalysisErr))))
+		b.WriteString("\n")
+	}
+
+	group := riskCategory(-1)
+	for i, branch := range m.confirmationBranches() {
+		if risk := m.riskOf(branch); m.Analysis != nil && risk != group {
+			group = risk
+			heading, style := m.riskHeading(risk)
+			b.WriteString(style.Render(heading))
+			b.WriteString("\n")
+		}
+		b.WriteString(m.renderConfirmationRow(branch, i == m.ConfirmCursor))
+	}
+	return b.String()
+}
+
+// renderStashWarning renders a warning for a branch with stash entries, which are kept after deletion
+func (m AppModel) renderStashWarning(branch string) string {
+	stashes := m.Analysis[branch].Stashes
+	if stashes == 0 {
+		return ""
+	}
+	return m.Styles.Warning.Render("      "+m.t(i18n.StashesKept, m.Lang.Count(stashes, i18n.StashEntry))) + "\n"
+}
+
+// renderRefsWarning renders a warning for a branch whose own commits carry tags or notes, which deleting the
+// branch leaves dangling
+func (m AppModel) renderRefsWarning(branch string) string {
+	description := refsDescription(m.Analysis[branch].Refs)
+	if description == "" {
+		return ""
+	}
+	return m.Styles.Warning.Render(fmt.Sprintf("      %s on commits only this branch has; needs force delete", description)) + "\n"
+}
+
+// refsDescription describes the tags and notes on a branch's own commits,
+// e.g. "contains 2 tag(s): v0.9-rc1, v0.9-rc2 and 1 note(s)", or "" if there are none
+func refsDescription(refs git.DanglingRefs) string {
+	var parts []string
+	if len(refs.Tags) > 0 {
+		names := refs.Tags
+		if len(names) > maxListedRefs {
+			names = append(slices.Clone(names[:maxListedRefs]), "…")
+		}
+		parts = append(parts, fmt.Sprintf("%d tag(s): %s", len(refs.Tags), strings.Join(names, ", ")))
+	}
+	if refs.Notes > 0 {
+		parts = append(parts, fmt.Sprintf("%d note(s)", refs.Notes))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "contains " + strings.Join(parts, " and ")
+}
+
+// holdBranchesWithRefs takes the branches whose own commits carry tags or notes out of a safe deletion run:
+// git would delete them if they are merged into HEAD, so they are sent to the force confirmation instead,
+// which names their tags and notes. Returns the branches left to delete safely.
+func (m *AppModel) holdBranchesWithRefs(branches []string) []string {
+	return slices.DeleteFunc(branches, func(branch string) bool {
+		description := refsDescription(m.Analysis[branch].Refs)
+		if description != "" {
+			m.UnmergedBranches[branch] = description
+		}
+		return description != ""
+	})
+}
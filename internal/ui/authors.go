@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/git"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// authorColumnWidth is the width of the author column added to the list by ShowAuthors
+const authorColumnWidth = 16
+
+// originLoadedMsg carries who started a branch and when it was created, loaded for the author column
+type originLoadedMsg struct {
+	branch    string
+	creator   string
+	createdAt time.Time
+}
+
+// loadVisibleOrigins starts loading who started the branches in the list viewport, for the author column.
+// Each branch takes two git commands, so only the branches scrolled into view are looked up, once.
+func loadVisibleOrigins(model tea.Model, cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	m, ok := model.(AppModel)
+	if !ok || !m.ShowAuthors || m.Remote || m.State != StateSelection {
+		return model, cmd
+	}
+
+	cmds := []tea.Cmd{cmd}
+	for _, branch := range m.visibleBranches() {
+		if _, requested := m.OriginsLoaded[branch]; requested {
+			continue
+		}
+		if m.OriginsLoaded == nil {
+			m.OriginsLoaded = make(map[string]bool)
+		}
+		m.OriginsLoaded[branch] = false
+		cmds = append(cmds, m.loadOrigin(branch))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// loadOrigin returns a command that loads who started a branch and when it was created
+func (m AppModel) loadOrigin(branch string) tea.Cmd {
+	base := m.previewBase()
+	return func() tea.Msg {
+		creator, createdAt := fetchOrigin(branch, git.BranchRef(branch), base, true)
+		return originLoadedMsg{branch: branch, creator: creator, createdAt: createdAt}
+	}
+}
+
+// fetchOrigin looks up the author of the first commit of a branch, given as a revision, that isn't on base,
+// and if withReflog, when the branch was created according to its reflog.
+// The origin is supplementary, so what can't be found is left blank.
+func fetchOrigin(branch, revision, base string, withReflog bool) (string, time.Time) {
+	creator, _ := git.GetBranchCreator(revision, base)
+	var createdAt time.Time
+	if withReflog {
+		createdAt, _ = git.GetBranchCreatedAt(branch)
+	}
+	return creator, createdAt
+}
+
+// handleOriginLoaded stores the origin of a branch in its BranchDetails
+func (m AppModel) handleOriginLoaded(msg originLoadedMsg) (tea.Model, tea.Cmd) {
+	if _, requested := m.OriginsLoaded[msg.branch]; !requested {
+		return m, nil
+	}
+	m.OriginsLoaded[msg.branch] = true
+	if info, ok := m.BranchDetails[msg.branch]; ok {
+		info.Creator = msg.creator
+		info.CreatedAt = msg.createdAt
+		m.BranchDetails[msg.branch] = info
+	}
+	return m, nil
+}
+
+// visibleBranches returns the branches in the list viewport
+func (m AppModel) visibleBranches() []string {
+	rows := m.listRows()
+	start, end := m.viewportRange(rows)
+
+	var branches []string
+	for _, row := range rows[start:end] {
+		if !row.isHeader() {
+			branches = append(branches, row.branch)
+		}
+	}
+	return branches
+}
+
+// authorBadge renders the author column of a branch, padded to a fixed width: who started the branch,
+// "…" while it is looked up, or "–" if the branch has no commits of its own.
+// Returns an empty string without ShowAuthors.
+func (m AppModel) authorBadge(branch string) string {
+	if !m.ShowAuthors || m.Remote {
+		return ""
+	}
+
+	author := "…"
+	if m.OriginsLoaded[branch] {
+		author = m.BranchDetails[branch].Creator
+	}
+	if author == "" {
+		author = "–"
+	}
+	author = ansi.Truncate(author, authorColumnWidth-1, "…")
+	return m.Styles.Metadata.Render(author + strings.Repeat(" ", authorColumnWidth-ansi.StringWidth(author)))
+}
+
+// originLine describes who started a branch and when it was created, e.g.
+// "started by Jane Doe • created 2024-04-18 09:31", or "" if neither is known
+func originLine(creator string, createdAt time.Time) string {
+	var parts []string
+	if creator != "" {
+		parts = append(parts, "started by "+creator)
+	}
+	if !createdAt.IsZero() {
+		parts = append(parts, "created "+createdAt.Format("2006-01-02 15:04"))
+	}
+	return strings.Join(parts, " • ")
+}
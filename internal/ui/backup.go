@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/audit"
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// backupCreatedMsg reports the backup tag created at the tip of a branch before force deleting it
+type backupCreatedMsg struct {
+	branch string
+	tag    string
+	err    error
+}
+
+// bundleWrittenMsg reports writing the branches to be force deleted into the BackupBundle
+type bundleWrittenMsg struct {
+	err error
+}
+
+// startForceDeletion force deletes branches, after writing them to the BackupBundle if one is set and,
+// with backupTags, tagging each of them as gelete-backup/<branch>-<date>. A branch that couldn't be backed up
+// is skipped rather than deleted without its backup.
+func (m AppModel) startForceDeletion(branches []string, backupTags bool) (tea.Model, tea.Cmd) {
+	m.forceBranches = branches
+	m.backupTags = backupTags
+	switch {
+	case m.BackupBundle != "" && len(branches) > 0:
+		return m.startPhase(phaseBundle, []string{m.BackupBundle})
+	case backupTags:
+		return m.startPhase(phaseBackup, branches)
+	}
+	return m.startPhase(phaseForceDelete, branches)
+}
+
+// continueForceDeletion starts the phase after a backup phase: the backup tags after the bundle if asked for,
+// then the force deletion of the branches that were backed up
+func (m AppModel) continueForceDeletion() (tea.Model, tea.Cmd) {
+	if m.phase == phaseBundle && m.backupTags {
+		return m.startPhase(phaseBackup, m.pendingForceBranches())
+	}
+	return m.startPhase(phaseForceDelete, m.pendingForceBranches())
+}
+
+// pendingForceBranches returns the branches of the force deletion that don't have an outcome yet,
+// i.e. weren't skipped because their backup failed
+func (m AppModel) pendingForceBranches() []string {
+	var branches []string
+	for _, branch := range m.forceBranches {
+		if !m.isResolved(branch) {
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
+
+// backupCmd returns the command of a backup phase: writing the bundle, or tagging a branch
+func (m AppModel) backupCmd(item string) tea.Cmd {
+	ctx := m.ctx
+	if m.phase == phaseBundle {
+		branches := m.forceBranches
+		return func() tea.Msg { return bundleWrittenMsg{err: git.CreateBundleContext(ctx, item, branches)} }
+	}
+	return func() tea.Msg {
+		tag, err := git.CreateBackupTagContext(ctx, item, time.Now())
+		return backupCreatedMsg{branch: item, tag: tag, err: err}
+	}
+}
+
+// handleBackupCreated records the backup tag of a branch, or skips the branch if it couldn't be tagged
+func (m AppModel) handleBackupCreated(msg backupCreatedMsg) (tea.Model, tea.Cmd) {
+	m.Progress++
+	if msg.err != nil {
+		m.skipWithoutBackup(msg.branch, msg.err)
+		return m, m.nextOperation()
+	}
+
+	if m.BackupTags == nil {
+		m.BackupTags = make(map[string]string)
+	}
+	m.BackupTags[msg.branch] = msg.tag
+	return m, m.nextOperation()
+}
+
+// handleBundleWritten records the branches written to the bundle, or skips them all if it couldn't be written
+func (m AppModel) handleBundleWritten(msg bundleWrittenMsg) (tea.Model, tea.Cmd) {
+	m.Progress++
+	for _, branch := range m.forceBranches {
+		if msg.err != nil {
+			m.skipWithoutBackup(branch, msg.err)
+			continue
+		}
+		m.BundledBranches = append(m.BundledBranches, branch)
+	}
+	return m, m.nextOperation()
+}
+
+// skipWithoutBackup skips force deleting a branch whose backup failed
+func (m *AppModel) skipWithoutBackup(branch string, err error) {
+	if git.IsInterrupted(err) {
+		return
+	}
+	reason := fmt.Sprintf("backup failed, not force deleted: %s", err.Error())
+	m.recordResult(DeletionResult{Branch: branch, Status: ResultSkipped, Reason: reason})
+	m.recordAudit(audit.Entry{Branch: branch, Error: reason})
+}
+
+// renderBackups lists the backup tags and bundle created before force deleting, with how to restore from them
+func (m AppModel) renderBackups() string {
+	var b strings.Builder
+	if len(m.BackupTags) > 0 {
+		b.WriteString("\n")
+		b.WriteString(m.Styles.Success.Render(m.t(i18n.BackedUpAsTags, m.Lang.Count(len(m.BackupTags), i18n.Branch))))
+		b.WriteString("\n")
+		for _, branch := range m.orderedBranches(m.BackupTags) {
+			restore := fmt.Sprintf("git branch %s %s", branch, m.BackupTags[branch])
+			b.WriteString(m.Styles.Help.UnsetMarginTop().Render("  • " + m.t(i18n.BackupTagRestore, m.BackupTags[branch], restore)))
+			b.WriteString("\n")
+		}
+	}
+
+	if len(m.BundledBranches) > 0 {
+		b.WriteString("\n")
+		b.WriteString(m.Styles.Success.Render(m.t(i18n.BackedUpToBundle, m.Lang.Count(len(m.BundledBranches), i18n.Branch), m.BackupBundle)))
+		b.WriteString("\n")
+		restore := fmt.Sprintf("git fetch %s %s:%s", m.BackupBundle, m.BundledBranches[0], m.BundledBranches[0])
+		b.WriteString(m.Styles.Help.UnsetMarginTop().Render("  " + m.t(i18n.BundleRestore, restore)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
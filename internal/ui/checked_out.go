@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// checkedOutWorktree returns the linked worktree a branch is checked out in when git refused to delete it
+// with an ErrCheckedOutElsewhere, e.g. because the worktree was added after the list was loaded.
+// Returns "" for other errors and for the main worktree, which can't be removed.
+func checkedOutWorktree(ctx context.Context, err error) string {
+	var checkedOut *git.ErrCheckedOutElsewhere
+	if !errors.As(err, &checkedOut) {
+		return ""
+	}
+
+	worktrees, listErr := git.ListWorktreesContext(ctx)
+	if listErr != nil {
+		return ""
+	}
+	for _, wt := range worktrees {
+		if git.SamePath(wt.Path, checkedOut.Path) && !wt.Main {
+			return wt.Path
+		}
+	}
+	return ""
+}
+
+// promptCheckedOut asks whether to remove the worktrees the branches of the finished phase are checked out in
+// and delete them again, looking for uncommitted changes in those worktrees meanwhile
+func (m AppModel) promptCheckedOut() (tea.Model, tea.Cmd) {
+	m.State = StateCheckedOutConfirmation
+	return m, checkWorktrees(m.CheckedOutWorktrees)
+}
+
+// handleCheckedOutConfirmationInput handles keyboard input in the checked-out worktree confirmation state
+func (m AppModel) handleCheckedOutConfirmationInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := m.keyMap()
+	switch {
+	case key.Matches(msg, keys.Confirm):
+		return m.retryCheckedOut(false)
+
+	case key.Matches(msg, keys.DiscardChanges) && len(m.dirtyWorktrees(m.CheckedOutWorktrees)) > 0:
+		return m.retryCheckedOut(true)
+
+	case key.Matches(msg, keys.Cancel), isPromptExit(msg):
+		// Skip the checked-out branches but continue with the rest of the run
+		for _, branch := range m.orderedBranches(m.CheckedOutWorktrees) {
+			reason := fmt.Sprintf("checked out in %s, worktree removal declined", m.CheckedOutWorktrees[branch])
+			m.recordResult(DeletionResult{Branch: branch, Status: ResultSkipped, Reason: reason})
+		}
+		m.CheckedOutWorktrees = make(map[string]string)
+		return m.handlePhaseComplete()
+	}
+
+	return m, nil
+}
+
+// retryCheckedOut removes the worktrees of the checked-out branches and deletes the branches again, the way
+// the phase that found them deletes. Like removeWorktrees, dirty worktrees are only removed with discard;
+// otherwise their branches are skipped. Branches found checked out again are failures, not asked about twice.
+func (m AppModel) retryCheckedOut(discard bool) (tea.Model, tea.Cmd) {
+	m.DiscardChanges = m.DiscardChanges || discard
+	worktrees := m.CheckedOutWorktrees
+	if !m.DiscardChanges {
+		for _, branch := range m.orderedBranches(m.dirtyWorktrees(worktrees)) {
+			m.recordResult(DeletionResult{Branch: branch, Status: ResultSkipped, Reason: "worktree has uncommitted changes"})
+			delete(worktrees, branch)
+		}
+	}
+
+	for branch, path := range worktrees {
+		m.BranchWorktrees[branch] = path
+	}
+	m.CheckedOutWorktrees = make(map[string]string)
+	m.retryingCheckedOut = true
+	return m.startPhase(m.phase, m.orderedBranches(worktrees))
+}
+
+// withCheckedOutRemoval returns deleteCmd, preceded by the removal of the worktree the branch is checked out in
+// while the checked-out branches are retried. A failed removal is reported as the failure of the branch.
+func (m AppModel) withCheckedOutRemoval(branch string, deleteCmd tea.Cmd) tea.Cmd {
+	if !m.retryingCheckedOut {
+		return deleteCmd
+	}
+
+	remove := m.removeWorktreeCmd(branch)
+	return func() tea.Msg {
+		if removed := remove().(worktreeRemovedMsg); removed.err != nil {
+			return branchDeletedMsg{branch: branch, err: fmt.Errorf("worktree removal failed: %w", removed.err)}
+		}
+		return deleteCmd()
+	}
+}
+
+func (m AppModel) renderCheckedOutConfirmation() string {
+	var b strings.Builder
+
+	b.WriteString(m.Styles.Error.Render(m.t(i18n.CheckedOutTitle)))
+	b.WriteString("\n\n")
+	b.WriteString(m.t(i18n.CheckedOutIntro) + "\n\n")
+
+	for _, branch := range m.Branches {
+		if path, ok := m.CheckedOutWorktrees[branch]; ok {
+			b.WriteString(m.worktreeBranchLine(branch, path))
+			b.WriteString(m.Styles.Help.UnsetMarginTop().Render(fmt.Sprintf("    %s", path)))
+			b.WriteString("\n")
+			b.WriteString(m.worktreeChangesLine(path))
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Warning.Render(m.t(i18n.CheckedOutRemoval, m.Lang.Count(len(m.CheckedOutWorktrees), i18n.Worktree))))
+	b.WriteString("\n\n")
+	keys := m.keyMap()
+	dirty := len(m.dirtyWorktrees(m.CheckedOutWorktrees))
+	b.WriteString(m.Styles.Help.Render(m.worktreeRemovalHelp(dirty, m.t(i18n.HelpRemoveAndRetry), m.t(i18n.HelpRemoveCleanAndRetry),
+		helpItemAs(keys.Cancel, m.t(i18n.HelpSkipBranches)))))
+	return b.String()
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/Kdaito/gelete/internal/git"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// checkedOutMsg reports the result of switching to a branch
+type checkedOutMsg struct {
+	branch string
+	err    error
+}
+
+// checkoutCurrent switches to the branch under the cursor instead of deleting anything.
+// Branches checked out in another worktree can't be switched to, so they are refused up front.
+func (m AppModel) checkoutCurrent() (tea.Model, tea.Cmd) {
+	branch, ok := m.cursorBranch()
+	if m.Remote || !ok {
+		return m, nil
+	}
+
+	if path, hasWorktree := m.BranchWorktrees[branch]; hasWorktree {
+		m.ErrorMsg = fmt.Sprintf("'%s' is checked out in the worktree at %s; cd there to work on it", branch, path)
+		return m, nil
+	}
+
+	return m, func() tea.Msg {
+		return checkedOutMsg{branch: branch, err: git.CheckoutBranch(branch)}
+	}
+}
+
+// handleCheckedOut quits after a successful checkout, or shows git's error and stays in the selection
+func (m AppModel) handleCheckedOut(msg checkedOutMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.ErrorMsg = msg.err.Error()
+		return m, nil
+	}
+
+	m.CheckedOut = msg.branch
+	return m, tea.Quit
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Clipboard copies text to the clipboard, for the y key of the selection list
+type Clipboard interface {
+	Copy(text string) error
+}
+
+// OSC52 copies text by writing the OSC 52 escape sequence to the terminal, which sets the clipboard of the
+// machine the terminal runs on, so copying works over SSH too
+type OSC52 struct {
+	// Out is the terminal the sequence is written to
+	Out io.Writer
+
+	// Tmux wraps the sequence so tmux passes it through to the terminal it runs in
+	Tmux bool
+}
+
+// Copy writes the sequence setting the system clipboard to text
+func (c OSC52) Copy(text string) error {
+	seq := ansi.SetSystemClipboard(text)
+	if c.Tmux {
+		seq = ansi.TmuxPassthrough(seq)
+	}
+	_, err := io.WriteString(c.Out, seq)
+	return err
+}
+
+// SystemClipboard copies text with the clipboard tools of the OS: pbcopy on macOS, xclip, xsel or wl-copy
+// on Linux, or the clipboard API on Windows
+type SystemClipboard struct{}
+
+// Copy sets the system clipboard to text
+func (SystemClipboard) Copy(text string) error {
+	if clipboard.Unsupported {
+		return errors.New("no clipboard tool found (install xclip, xsel or wl-clipboard)")
+	}
+	return clipboard.WriteAll(text)
+}
+
+// NewTerminalClipboard returns the clipboard to use with the terminal out writes to: OSC 52 if the terminal
+// supports it, judging by the TERM environment variable, or else the clipboard tools of the OS.
+// getenv looks up environment variables, e.g. os.Getenv.
+func NewTerminalClipboard(out io.Writer, getenv func(string) string) Clipboard {
+	switch getenv("TERM") {
+	case "", "dumb", "linux":
+		// The Linux console and dumb terminals ignore OSC 52
+		return SystemClipboard{}
+	}
+	return OSC52{Out: out, Tmux: getenv("TMUX") != ""}
+}
+
+// branchesCopiedMsg reports that branch names were copied to the clipboard
+type branchesCopiedMsg struct {
+	count int
+	err   error
+}
+
+// copyBranches copies the names of the selected branches, one per line, or the branch under the cursor
+// if none are selected. Does nothing without a Clipboard, e.g. when stdout isn't a terminal.
+func (m AppModel) copyBranches() (tea.Model, tea.Cmd) {
+	if m.Clipboard == nil {
+		return m, nil
+	}
+
+	branches := m.selectedBranches()
+	if len(branches) == 0 {
+		branch, ok := m.cursorBranch()
+		if !ok {
+			return m, nil
+		}
+		branches = []string{branch}
+	}
+
+	board := m.Clipboard
+	return m, func() tea.Msg {
+		return branchesCopiedMsg{count: len(branches), err: board.Copy(strings.Join(branches, "\n"))}
+	}
+}
+
+// handleBranchesCopied shows the outcome of copying branch names until the next key press
+func (m AppModel) handleBranchesCopied(msg branchesCopiedMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.err != nil:
+		m.ErrorMsg = fmt.Sprintf("failed to copy to the clipboard: %v", msg.err)
+	case msg.count == 1:
+		m.SuccessMsg = "copied 1 branch name"
+	default:
+		m.SuccessMsg = fmt.Sprintf("copied %d branch names", msg.count)
+	}
+	return m, nil
+}
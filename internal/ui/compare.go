@@ -0,0 +1,197 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/git"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// BranchComparison holds the comparison of two branches shown in the compare view
+type BranchComparison struct {
+	// Anchor is the branch marked first, and Other the branch it is compared with
+	Anchor string
+	Other  string
+
+	// Result is the comparison, with Anchor as A and Other as B
+	Result git.Comparison
+
+	// Err is set if the comparison could not be loaded
+	Err string
+
+	// Loading is true until the comparison arrives
+	Loading bool
+}
+
+// comparisonLoadedMsg carries the comparison of two branches
+type comparisonLoadedMsg BranchComparison
+
+// compareCurrent marks the branch under the cursor as the comparison anchor, or compares it with the anchor.
+// Pressing the key on the anchor again unmarks it. Remote branches can't be compared.
+func (m AppModel) compareCurrent() (tea.Model, tea.Cmd) {
+	branch, ok := m.cursorBranch()
+	if !ok || m.Remote {
+		return m, nil
+	}
+
+	switch {
+	case branch == m.CompareAnchor:
+		m.CompareAnchor = ""
+		return m, nil
+	case m.CompareAnchor == "" || !slices.Contains(m.Branches, m.CompareAnchor):
+		m.CompareAnchor = branch
+		return m, nil
+	}
+
+	anchor := m.CompareAnchor
+	m.CompareAnchor = ""
+	m.CompareOpen = true
+	m.Comparison = BranchComparison{Anchor: anchor, Other: branch, Loading: true}
+	return m, func() tea.Msg {
+		comparison := BranchComparison{Anchor: anchor, Other: branch}
+		result, err := git.CompareBranches(anchor, branch)
+		if err != nil {
+			comparison.Err = err.Error()
+		}
+		comparison.Result = result
+		return comparisonLoadedMsg(comparison)
+	}
+}
+
+// handleComparisonLoaded stores the comparison unless the compare view was closed or shows other branches
+func (m AppModel) handleComparisonLoaded(msg comparisonLoadedMsg) (tea.Model, tea.Cmd) {
+	if m.CompareOpen && m.Comparison.Anchor == msg.Anchor && m.Comparison.Other == msg.Other {
+		m.Comparison = BranchComparison(msg)
+	}
+	return m, nil
+}
+
+// handleCompareInput handles keyboard input while the compare view is shown: s selects the contained branch
+// for deletion, and the other keys close the view
+func (m AppModel) handleCompareInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "s":
+		return m.selectContained()
+	case "x", "esc", "q":
+		m.CompareOpen = false
+	}
+	return m, nil
+}
+
+// selectContained selects the branch the other compared branch fully contains, and closes the compare view
+func (m AppModel) selectContained() (tea.Model, tea.Cmd) {
+	contained := m.containedBranch()
+	if contained == "" || !slices.Contains(m.Branches, contained) {
+		return m, nil
+	}
+
+	m.CompareOpen = false
+	m.Selected[contained] = true
+	m.recordSelectionOrder()
+	m.moveCursorTo(contained)
+	m.SuccessMsg = fmt.Sprintf("Selected %s for deletion", contained)
+	if m.PersistSession {
+		return m, m.saveSession()
+	}
+	return m, nil
+}
+
+// containedBranch returns the compared branch the other one fully contains, or "" if there is none
+// or the comparison isn't loaded
+func (m AppModel) containedBranch() string {
+	if m.Comparison.Loading || m.Comparison.Err != "" {
+		return ""
+	}
+	return m.Comparison.Result.Contained()
+}
+
+// compareStatus describes the comparison anchor for the status line, or "" if none is marked
+// or it is no longer listed
+func (m AppModel) compareStatus() string {
+	if m.CompareAnchor == "" || !slices.Contains(m.Branches, m.CompareAnchor) {
+		return ""
+	}
+	return fmt.Sprintf(" • comparing %s (%s on another branch)", m.CompareAnchor, m.keyMap().Compare.Help().Key)
+}
+
+// renderComparison renders the compare view: the ahead/behind counts, which branch contains the other,
+// and the commits only on each side
+func (m AppModel) renderComparison() string {
+	var b strings.Builder
+	c := m.Comparison
+
+	b.WriteString(m.Styles.Title.Render(fmt.Sprintf("Compare: %s ↔ %s", c.Anchor, c.Other)))
+	b.WriteString("\n\n")
+	switch {
+	case c.Loading:
+		b.WriteString(m.Styles.Help.UnsetMarginTop().Render("Loading..."))
+		b.WriteString("\n")
+	case c.Err != "":
+		b.WriteString(m.Styles.Error.Render(c.Err))
+		b.WriteString("\n")
+	default:
+		b.WriteString(m.renderComparisonBody())
+	}
+
+	help := "x/esc: close"
+	if contained := m.containedBranch(); contained != "" {
+		help = fmt.Sprintf("s: select %s for deletion • %s", contained, help)
+	}
+	b.WriteString(m.Styles.Help.Render(help))
+	return b.String()
+}
+
+// renderComparisonBody renders the loaded comparison
+func (m AppModel) renderComparisonBody() string {
+	var b strings.Builder
+	r := m.Comparison.Result
+
+	b.WriteString(fmt.Sprintf("%s is %d ahead and %d behind %s\n", r.A, r.Ahead, r.Behind, r.B))
+	b.WriteString(m.containmentLine(r))
+	b.WriteString("\n")
+	b.WriteString(m.renderOwnCommits(r.A, r.OnlyA, r.Ahead))
+	b.WriteString("\n")
+	b.WriteString(m.renderOwnCommits(r.B, r.OnlyB, r.Behind))
+	return b.String()
+}
+
+// containmentLine tells which branch contains the other, highlighting the redundant one
+func (m AppModel) containmentLine(r git.Comparison) string {
+	switch {
+	case r.AContainsB && r.BContainsA:
+		return m.Styles.Warning.Render(fmt.Sprintf("%s and %s point at the same commit: either is redundant", r.A, r.B)) + "\n"
+	case r.AContainsB:
+		return m.Styles.Warning.Render(fmt.Sprintf("%s contains %s: %s is redundant", r.A, r.B, r.B)) + "\n"
+	case r.BContainsA:
+		return m.Styles.Warning.Render(fmt.Sprintf("%s contains %s: %s is redundant", r.B, r.A, r.A)) + "\n"
+	}
+	return m.Styles.Metadata.Render("neither branch contains the other") + "\n"
+}
+
+// renderOwnCommits renders the commits only on branch, of total, truncated to the terminal width
+func (m AppModel) renderOwnCommits(branch string, commits []string, total int) string {
+	var b strings.Builder
+	b.WriteString(m.Styles.Help.UnsetMarginTop().Render(fmt.Sprintf("only on %s (%d):", branch, total)))
+	b.WriteString("\n")
+	if total == 0 {
+		b.WriteString(m.Styles.Metadata.Render("  none"))
+		b.WriteString("\n")
+	}
+
+	width := m.Width
+	if width <= 0 {
+		width = defaultPreviewWidth
+	}
+	for _, commit := range commits {
+		b.WriteString(ansi.Truncate("  "+commit, width, "…"))
+		b.WriteString("\n")
+	}
+	if more := total - len(commits); more > 0 {
+		b.WriteString(m.Styles.Metadata.Render(fmt.Sprintf("  … and %d more", more)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
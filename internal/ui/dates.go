@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/i18n"
+)
+
+// DateFormat is how the commit dates of the branch list are shown, switched with the t key
+type DateFormat int
+
+const (
+	// DatesRelative shows how long ago each branch was last committed to, e.g. "3 months ago"
+	DatesRelative DateFormat = iota
+	// DatesAbsolute shows the ISO date of the last commit, e.g. "2024-05-01", which doesn't depend on when it is read
+	DatesAbsolute
+)
+
+// String returns the name of the date format used in the config file
+func (f DateFormat) String() string {
+	if f == DatesAbsolute {
+		return "absolute"
+	}
+	return "relative"
+}
+
+// ParseDateFormat parses the date_format config key: relative or absolute
+func ParseDateFormat(s string) (DateFormat, error) {
+	switch s {
+	case "relative":
+		return DatesRelative, nil
+	case "absolute":
+		return DatesAbsolute, nil
+	}
+	return DatesRelative, fmt.Errorf("invalid date format '%s' (expected relative or absolute)", s)
+}
+
+// absoluteDateLayout is the ISO 8601 layout of absolute dates
+const absoluteDateLayout = "2006-01-02"
+
+// HumanizeDuration returns how long before now t was, in its largest whole unit and in the given language,
+// e.g. "59 seconds ago", "1 minute ago", "23 hours ago", "1 day ago" or "1 year ago".
+// Dates in the future, e.g. from a skewed clock, are "just now".
+func HumanizeDuration(lang i18n.Lang, t, now time.Time) string {
+	d := now.Sub(t)
+	day := 24 * time.Hour
+
+	var n int
+	var unit i18n.Noun
+	switch {
+	case d < time.Second:
+		return lang.T(i18n.JustNow)
+	case d < time.Minute:
+		n, unit = int(d/time.Second), i18n.Second
+	case d < time.Hour:
+		n, unit = int(d/time.Minute), i18n.Minute
+	case d < day:
+		n, unit = int(d/time.Hour), i18n.Hour
+	case d < 7*day:
+		n, unit = int(d/day), i18n.Day
+	case d < 30*day:
+		n, unit = int(d/(7*day)), i18n.Week
+	case d < 365*day:
+		n, unit = int(d/(30*day)), i18n.Month
+	default:
+		n, unit = int(d/(365*day)), i18n.Year
+	}
+	return lang.T(i18n.Ago, lang.Count(n, unit))
+}
+
+// now returns the current time of the model, from its clock if it has one
+func (m AppModel) now() time.Time {
+	if m.Clock != nil {
+		return m.Clock()
+	}
+	return time.Now()
+}
+
+// formatDate renders a commit date in the date format of the model, or "unknown" for a branch without one,
+// which would otherwise show as the zero time
+func (m AppModel) formatDate(t time.Time) string {
+	switch {
+	case t.IsZero():
+		return m.t(i18n.UnknownDate)
+	case m.DateFormat == DatesAbsolute:
+		return t.Local().Format(absoluteDateLayout)
+	}
+	return HumanizeDuration(m.Lang, t, m.now())
+}
+
+// toggleDateFormat switches the dates of the branch list between relative and absolute
+func (m *AppModel) toggleDateFormat() {
+	if m.DateFormat == DatesAbsolute {
+		m.DateFormat = DatesRelative
+	} else {
+		m.DateFormat = DatesAbsolute
+	}
+}
@@ -0,0 +1,519 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/audit"
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// deletionPhase identifies the operation the deletion queue is running
+type deletionPhase int
+
+const (
+	// phaseDelete safely deletes branches (git branch -d), or plans deletions in dry-run mode
+	phaseDelete deletionPhase = iota
+	// phaseForceDelete force deletes unmerged branches (git branch -D)
+	phaseForceDelete
+	// phaseRemoveWorktrees removes worktrees attached to selected branches (FR-013)
+	phaseRemoveWorktrees
+	// phaseForceRemoveWorktrees force removes locked worktrees (FR-014)
+	phaseForceRemoveWorktrees
+	// phaseBundle writes the branches to be force deleted into the backup bundle, as a single operation
+	phaseBundle
+	// phaseBackup tags the branches to be force deleted as gelete-backup/<branch>-<date>
+	phaseBackup
+)
+
+// worktreeRemovedMsg reports the result of removing the worktree of one branch
+type worktreeRemovedMsg struct {
+	branch string
+	err    error
+}
+
+// branchDeletedMsg reports the result of deleting one branch
+type branchDeletedMsg struct {
+	branch string
+	// sha is the branch tip before deletion, empty if it could not be resolved
+	sha string
+	err error
+	// remote is the upstream deleted along with the branch (empty if none was requested)
+	remote    string
+	remoteErr error
+	// reachability tells whether the tip of a branch refused as unmerged is reachable from another ref
+	reachability TipReachability
+	// tags are the tags deleted along with the branch; tagFailures holds the errors of those that failed
+	tags        []string
+	tagFailures map[string]string
+	// worktree is the linked worktree git refused to delete the branch for, since it is checked out there
+	worktree string
+}
+
+// branchPlannedMsg reports the merge status of one branch in dry-run mode
+type branchPlannedMsg struct {
+	branch       string
+	merged       bool
+	reachability TipReachability
+	err          error
+}
+
+// deletionCompleteMsg is sent once every branch queued for the current phase was processed
+type deletionCompleteMsg struct{}
+
+// startPhase queues branches for the given phase and issues the command for the first one.
+// Each operation runs as its own tea.Cmd so the deleting view can show live progress.
+func (m AppModel) startPhase(phase deletionPhase, branches []string) (tea.Model, tea.Cmd) {
+	m.State = StateDeleting
+	m.phase = phase
+	if m.DeletionStarted.IsZero() {
+		m.DeletionStarted = m.now()
+	}
+	if m.cancel == nil {
+		m.ctx, m.cancel = context.WithCancel(context.Background())
+	}
+	m.Queue = branches
+	m.Progress = 0
+	m.ProgressTotal = len(branches)
+	m.Spinner = spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(m.Styles.Cursor))
+
+	cmd := m.nextOperation()
+	return m, tea.Batch(m.Spinner.Tick, cmd)
+}
+
+// startDeletion starts deleting the selected branches that don't have an outcome yet:
+// safely, or with git branch -D in force mode. Dry runs always plan the deletions first.
+// Branches whose own commits carry tags or notes always go through the force confirmation.
+func (m AppModel) startDeletion() (tea.Model, tea.Cmd) {
+	switch {
+	case m.ForceMode && !m.DryRun:
+		return m.startForceDeletion(m.branchesToDelete(), false)
+	case m.DryRun:
+		return m.startPhase(phaseDelete, m.branchesToDelete())
+	}
+	return m.startPhase(phaseDelete, m.holdBranchesWithRefs(m.branchesToDelete()))
+}
+
+// nextOperation pops the next branch off the queue and returns the command processing it,
+// or a command reporting completion when the queue is empty
+func (m *AppModel) nextOperation() tea.Cmd {
+	if len(m.Queue) == 0 {
+		m.Current = ""
+		return func() tea.Msg { return deletionCompleteMsg{} }
+	}
+
+	m.Current = m.Queue[0]
+	m.Queue = m.Queue[1:]
+	return m.operationCmd(m.Current)
+}
+
+// operationCmd returns the command that performs the current phase's operation on a branch.
+// Every git command runs with the run's context so ctrl+c can kill it.
+func (m AppModel) operationCmd(branch string) tea.Cmd {
+	ctx := m.ctx
+	switch m.phase {
+	case phaseRemoveWorktrees:
+		return m.removeWorktreeCmd(branch)
+	case phaseForceRemoveWorktrees:
+		path := m.LockedWorktrees[branch]
+		return func() tea.Msg {
+			return worktreeRemovedMsg{branch: branch, err: git.ForceRemoveWorktreeContext(ctx, path)}
+		}
+	case phaseForceDelete:
+		return m.withCheckedOutRemoval(branch, m.deleteLocalBranch(branch, m.Repo.ForceDeleteBranch))
+	case phaseBundle, phaseBackup:
+		return m.backupCmd(branch)
+	}
+	return m.withCheckedOutRemoval(branch, m.deleteCmd(branch))
+}
+
+// deleteCmd returns the command of phaseDelete: deleting a branch safely, or planning its deletion in dry runs
+func (m AppModel) deleteCmd(branch string) tea.Cmd {
+	ctx := m.ctx
+	switch {
+	case m.DryRun && m.Remote:
+		return func() tea.Msg { return branchPlannedMsg{branch: branch, merged: true} }
+	case m.DryRun:
+		return func() tea.Msg {
+			merged, err := git.IsBranchMergedContext(ctx, branch)
+			msg := branchPlannedMsg{branch: branch, merged: merged, err: err}
+			if err == nil && !merged {
+				msg.reachability = checkTipReachability(ctx, branch)
+			}
+			return msg
+		}
+	case m.Remote:
+		return func() tea.Msg {
+			remote, name, err := git.ResolveRemoteBranchContext(ctx, branch)
+			if err != nil {
+				return branchDeletedMsg{branch: branch, err: err}
+			}
+			return branchDeletedMsg{branch: branch, err: git.DeleteRemoteBranchContext(ctx, remote, name)}
+		}
+	}
+	return m.deleteLocalBranch(branch, m.Repo.DeleteBranch)
+}
+
+// deleteLocalBranch returns a command that records the branch tip and then deletes the branch,
+// followed by its upstream and tags if the user asked for it. A failure to resolve the tip doesn't prevent
+// the deletion; it only omits the restore hint. If the branch is still registered in a worktree
+// whose directory was deleted, the stale worktree is pruned and the deletion retried; if it is checked out
+// in a linked worktree, that worktree is reported so its removal can be offered.
+func (m AppModel) deleteLocalBranch(branch string, deleteBranch func(context.Context, string) error) tea.Cmd {
+	ctx := m.ctx
+	stale := m.StaleWorktrees[branch]
+	deleteAlongside := m.alongsideDeletion(branch)
+
+	return func() tea.Msg {
+		sha, _ := git.GetBranchSHAContext(ctx, branch)
+		err := deleteBranch(ctx, branch)
+		if err != nil && stale && git.PruneWorktreesContext(ctx) == nil {
+			err = deleteBranch(ctx, branch)
+		}
+
+		msg := branchDeletedMsg{branch: branch, sha: sha, err: err}
+		switch {
+		case err == nil:
+			deleteAlongside(ctx, &msg)
+		case git.IsUnmergedError(err):
+			msg.reachability = checkTipReachability(ctx, branch)
+		default:
+			msg.worktree = checkedOutWorktree(ctx, err)
+		}
+		return msg
+	}
+}
+
+// alongsideDeletion returns a function that deletes the upstream and tags the user asked to delete
+// along with a deleted branch, recording the outcomes in its message
+func (m AppModel) alongsideDeletion(branch string) func(context.Context, *branchDeletedMsg) {
+	upstream, deleteUpstream := m.Upstreams[branch]
+	deleteUpstream = deleteUpstream && m.DeleteRemote[branch]
+	tags := m.tagsToDelete(branch)
+
+	return func(ctx context.Context, msg *branchDeletedMsg) {
+		if deleteUpstream {
+			msg.remote = upstream.String()
+			msg.remoteErr = git.DeleteRemoteBranchContext(ctx, upstream.Remote, upstream.Branch)
+		}
+		if len(tags) > 0 {
+			msg.tags = tags
+			msg.tagFailures = deleteTags(ctx, tags)
+		}
+	}
+}
+
+// checkTipReachability checks whether the tip of an unmerged branch is reachable from another ref.
+// The check is given tipCheckTimeout; if it takes longer or fails, the branch is reported as unchecked.
+func checkTipReachability(ctx context.Context, branch string) TipReachability {
+	ctx, cancel := context.WithTimeout(ctx, tipCheckTimeout)
+	defer cancel()
+
+	reachable, err := git.IsTipReachableElsewhereContext(ctx, branch)
+	switch {
+	case err != nil:
+		return TipUnchecked
+	case reachable:
+		return TipReachable
+	}
+	return TipUnreachable
+}
+
+// handleWorktreeRemoved records a worktree removal result and continues with the next branch.
+// Locked worktrees are collected for an explicit force-removal prompt (FR-014).
+func (m AppModel) handleWorktreeRemoved(msg worktreeRemovedMsg) (tea.Model, tea.Cmd) {
+	m.Progress++
+
+	switch {
+	case msg.err == nil:
+		m.recordFreedSpace(m.BranchWorktrees[msg.branch])
+	case m.phase == phaseRemoveWorktrees && strings.Contains(msg.err.Error(), "locked"):
+		m.LockedWorktrees[msg.branch] = m.BranchWorktrees[msg.branch]
+	default:
+		reason := fmt.Sprintf("worktree removal failed: %s", msg.err.Error())
+		m.recordResult(DeletionResult{Branch: msg.branch, Status: ResultFailed, Reason: reason})
+		m.recordAudit(audit.Entry{Branch: msg.branch, Error: reason})
+	}
+
+	return m, m.nextOperation()
+}
+
+// handleBranchDeleted records a branch deletion result and continues with the next branch
+func (m AppModel) handleBranchDeleted(msg branchDeletedMsg) (tea.Model, tea.Cmd) {
+	if delay, retry := m.lockRetry(msg); retry {
+		return m.waitForLock(msg, delay)
+	}
+	m.lockAttempts, m.WaitingForLock = 0, ""
+	m.Progress++
+
+	switch {
+	case msg.err == nil:
+		m.recordBranchDeleted(msg)
+	case m.phase == phaseDelete && !m.Remote && git.IsUnmergedError(msg.err):
+		// Unmerged branches are candidates for force deletion
+		m.UnmergedBranches[msg.branch] = msg.err.Error()
+		m.TipReachability[msg.branch] = msg.reachability
+	case !m.Remote && errors.Is(msg.err, git.ErrNotFound):
+		// Deleted since the list was loaded, e.g. from another terminal: there is nothing left to do.
+		// The audit log still records git's error, since gelete didn't delete the branch.
+		m.recordResult(DeletionResult{Branch: msg.branch, Status: ResultAlreadyGone})
+		m.recordAudit(m.auditEntry(msg))
+	case msg.worktree != "" && !m.retryingCheckedOut:
+		// Checked out in a worktree added since the list was loaded: its removal is offered once the phase completes
+		m.CheckedOutWorktrees[msg.branch] = msg.worktree
+	default:
+		m.recordResult(failedResult(msg.branch, msg.err))
+		m.recordAudit(m.auditEntry(msg))
+	}
+
+	return m, m.nextOperation()
+}
+
+// recordBranchDeleted records a branch that was deleted, along with its remote branch and tags if they were deleted too
+func (m *AppModel) recordBranchDeleted(msg branchDeletedMsg) {
+	m.DeletedCount++
+	delete(m.UnmergedBranches, msg.branch)
+	status := ResultDeleted
+	if m.phase == phaseForceDelete {
+		status = ResultForceDeleted
+	}
+	m.recordResult(DeletionResult{Branch: msg.branch, Status: status, SHA: msg.sha})
+	m.recordRemoteDeletion(msg)
+	m.recordTagDeletion(msg)
+	m.recordAudit(m.auditEntry(msg))
+}
+
+// failedResult returns the result of a branch whose deletion failed with err
+func failedResult(branch string, err error) DeletionResult {
+	result := DeletionResult{Branch: branch, Status: ResultFailed, Reason: err.Error()}
+	var checkedOut *git.ErrCheckedOutElsewhere
+	var locked *git.ErrRefLocked
+	switch {
+	case errors.As(err, &checkedOut):
+		result.CheckedOutIn = checkedOut.Path
+	case errors.As(err, &locked):
+		result.LockFile = locked.LockFile
+	}
+	return result
+}
+
+// auditEntry builds the audit log entry of a branch deletion. A deleted local branch took its worktree with it,
+// since branches whose worktree wasn't removed are never deleted.
+func (m AppModel) auditEntry(msg branchDeletedMsg) audit.Entry {
+	entry := audit.Entry{Branch: msg.branch, SHA: msg.sha, Forced: m.phase == phaseForceDelete, Remote: msg.remote}
+	switch {
+	case msg.err != nil:
+		entry.Error = msg.err.Error()
+	case m.Remote:
+		entry.Remote = msg.branch
+	default:
+		entry.Worktree = m.BranchWorktrees[msg.branch]
+	}
+	if msg.remoteErr != nil {
+		entry.RemoteError = msg.remoteErr.Error()
+	}
+	return entry
+}
+
+// recordAudit appends an entry to the audit log if AuditLog is set. Failing to write it doesn't stop the run;
+// the first failure is shown with the results.
+func (m *AppModel) recordAudit(entry audit.Entry) {
+	if !m.AuditLog {
+		return
+	}
+	if err := audit.Append(entry); err != nil && m.AuditError == "" {
+		m.AuditError = err.Error()
+	}
+}
+
+// recordRemoteDeletion records the outcome of deleting a branch's upstream, if one was requested.
+// Remote failures are tracked separately so the local deletion still counts as successful.
+func (m *AppModel) recordRemoteDeletion(msg branchDeletedMsg) {
+	switch {
+	case msg.remote == "":
+	case msg.remoteErr != nil:
+		m.RemoteFailures[msg.branch] = msg.remoteErr.Error()
+	default:
+		m.RemoteDeletedCount++
+	}
+}
+
+// handleBranchPlanned records what would happen to a branch in dry-run mode
+func (m AppModel) handleBranchPlanned(msg branchPlannedMsg) (tea.Model, tea.Cmd) {
+	m.Progress++
+
+	if msg.err != nil {
+		m.recordResult(DeletionResult{Branch: msg.branch, Status: ResultFailed, Reason: msg.err.Error()})
+		return m, m.nextOperation()
+	}
+
+	if !msg.merged {
+		m.UnmergedBranches[msg.branch] = fmt.Sprintf("branch '%s' is not fully merged", msg.branch)
+		m.TipReachability[msg.branch] = msg.reachability
+	}
+	m.DryRunActions = append(m.DryRunActions, DryRunAction{
+		Branch:       msg.branch,
+		Merged:       msg.merged,
+		WorktreePath: m.BranchWorktrees[msg.branch],
+		Remote:       m.Remote,
+		AlsoRemote:   m.upstreamToDelete(msg.branch),
+		AlsoTags:     m.tagsToDelete(msg.branch),
+	})
+
+	return m, m.nextOperation()
+}
+
+// handlePhaseComplete moves to the next phase or prompt once the queue is drained
+func (m AppModel) handlePhaseComplete() (tea.Model, tea.Cmd) {
+	m.retryingCheckedOut = false
+	if m.Cancelled {
+		return m.finishDeletion()
+	}
+
+	switch m.phase {
+	case phaseRemoveWorktrees:
+		if len(m.LockedWorktrees) > 0 {
+			m.State = StateLockedWorktreeConfirmation
+			return m, nil
+		}
+		return m.startDeletion()
+	case phaseForceRemoveWorktrees:
+		return m.startDeletion()
+	case phaseBundle, phaseBackup:
+		return m.continueForceDeletion()
+	case phaseDelete, phaseForceDelete:
+		return m.deletionPhaseComplete()
+	}
+
+	return m.finishDeletion()
+}
+
+// deletionPhaseComplete moves on once phaseDelete or phaseForceDelete processed its branches: first to the
+// removal of the worktrees some of them turned out to be checked out in, then, after a safe deletion,
+// to StateForceConfirmation for the unmerged branches, unless in force mode
+func (m AppModel) deletionPhaseComplete() (tea.Model, tea.Cmd) {
+	switch {
+	case len(m.CheckedOutWorktrees) > 0:
+		return m.promptCheckedOut()
+	case m.phase == phaseForceDelete, len(m.UnmergedBranches) == 0:
+		return m.finishDeletion()
+	case m.ForceMode:
+		// Only dry runs get here in force mode; the force deletion was confirmed up front
+		m.planForceDeletion()
+		return m, nil
+	}
+	m.State = StateForceConfirmation
+	return m, nil
+}
+
+// finishDeletion ends a deletion run. Unmerged branches that weren't force deleted are skipped, and so are
+// branches found checked out in a worktree whose removal was never offered because the run was cancelled.
+// The saved session is cleared afterwards so selections that were acted on don't reappear on the next start.
+func (m AppModel) finishDeletion() (tea.Model, tea.Cmd) {
+	for _, branch := range m.orderedBranches(m.UnmergedBranches) {
+		if m.isResolved(branch) {
+			continue
+		}
+		m.recordResult(DeletionResult{Branch: branch, Status: ResultSkipped, Reason: m.declinedReason(branch)})
+	}
+	for _, branch := range m.orderedBranches(m.CheckedOutWorktrees) {
+		path := m.CheckedOutWorktrees[branch]
+		reason := fmt.Sprintf("checked out in %s, deletion cancelled", path)
+		m.recordResult(DeletionResult{Branch: branch, Status: ResultSkipped, Reason: reason, CheckedOutIn: path})
+	}
+	m.CheckedOutWorktrees = make(map[string]string)
+	m.State = StateDone
+	m.releaseContext()
+	m.dropGoneBranches()
+
+	cmds := []tea.Cmd{m.notifyDone()}
+	m.DeletionStarted = time.Time{}
+	if m.PersistSession && !m.DryRun {
+		cmds = append(cmds, clearSession)
+	}
+	if m.ExpireReflog && !m.Cancelled && m.canCompact() {
+		next, compact := m.startCompaction()
+		m = next.(AppModel)
+		cmds = append(cmds, compact)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// declinedReason explains why a branch awaiting force deletion was skipped when force deletion was declined
+func (m AppModel) declinedReason(branch string) string {
+	if !m.Analysis[branch].Refs.IsEmpty() {
+		return "has tags or notes on its own commits, force delete declined"
+	}
+	return "not fully merged, force delete declined"
+}
+
+// planForceDeletion marks the unmerged branches as force-deleted without deleting them (dry-run mode)
+func (m *AppModel) planForceDeletion() {
+	for i, action := range m.DryRunActions {
+		if _, unmerged := m.UnmergedBranches[action.Branch]; unmerged {
+			m.DryRunActions[i].Force = true
+		}
+	}
+	m.UnmergedBranches = make(map[string]string)
+	m.State = StateDone
+	m.releaseContext()
+}
+
+// cancelDeletion skips the branches still queued. The operation in flight is left to finish,
+// so the run ends with a partial summary once it reports back.
+func (m *AppModel) cancelDeletion() {
+	m.Cancelled = true
+	for _, branch := range m.Queue {
+		m.recordResult(DeletionResult{Branch: branch, Status: ResultSkipped, Reason: "deletion cancelled"})
+	}
+	m.Queue = nil
+}
+
+// releaseContext releases the context of a finished deletion run
+func (m *AppModel) releaseContext() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.ctx, m.cancel = nil, nil
+}
+
+// upstreamToDelete returns the upstream to delete along with the branch, or "" if none was requested
+func (m AppModel) upstreamToDelete(branch string) string {
+	if upstream, ok := m.Upstreams[branch]; ok && m.DeleteRemote[branch] {
+		return upstream.String()
+	}
+	return ""
+}
+
+// branchesToDelete returns the selected branches that don't have an outcome yet, in list order
+func (m AppModel) branchesToDelete() []string {
+	var branches []string
+	for _, branch := range m.Branches {
+		if m.Selected[branch] && !m.isResolved(branch) {
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
+
+// orderedBranches returns the keys of a branch-keyed map in list order
+func (m AppModel) orderedBranches(set map[string]string) []string {
+	var branches []string
+	for _, branch := range m.Branches {
+		if _, ok := set[branch]; ok {
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
+
+// isResolved reports whether a branch already has an outcome (e.g. skipped or failed) for this run
+func (m AppModel) isResolved(branch string) bool {
+	_, resolved := m.Result(branch)
+	return resolved
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/fsutil"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// worktreeSizeTimeout bounds measuring one worktree, so a huge tree can't keep its estimate pending for long
+const worktreeSizeTimeout = 10 * time.Second
+
+// WorktreeSize is the estimated disk space used by a worktree directory
+type WorktreeSize struct {
+	// Bytes is the total size of the files in the worktree
+	Bytes int64
+
+	// Partial means the worktree uses at least Bytes: measuring it took longer than worktreeSizeTimeout,
+	// or the size of some of the worktrees added up is unknown
+	Partial bool
+
+	// Unknown means the size could not be measured, e.g. because the directory is unreadable
+	Unknown bool
+}
+
+// String formats the size for display, e.g. "~4.2 GB", "> 4.2 GB" if partial, or "size unknown"
+func (s WorktreeSize) String() string {
+	switch {
+	case s.Unknown:
+		return "size unknown"
+	case s.Partial:
+		return "> " + fsutil.FormatSize(s.Bytes)
+	}
+	return "~" + fsutil.FormatSize(s.Bytes)
+}
+
+// add returns the combined size of two worktrees; unknown sizes make the total partial
+func (s WorktreeSize) add(other WorktreeSize) WorktreeSize {
+	return WorktreeSize{
+		Bytes:   s.Bytes + other.Bytes,
+		Partial: s.Partial || s.Unknown || other.Partial || other.Unknown,
+	}
+}
+
+// worktreeSizedMsg carries the measured size of a worktree directory
+type worktreeSizedMsg struct {
+	path string
+	size WorktreeSize
+}
+
+// measureWorktrees returns a command that measures the worktrees whose size isn't known yet, in parallel
+func (m AppModel) measureWorktrees(paths map[string]string) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, path := range paths {
+		if _, measured := m.WorktreeSizes[path]; !measured {
+			cmds = append(cmds, measureWorktree(path))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// measureWorktree returns a command that measures one worktree directory within worktreeSizeTimeout
+func measureWorktree(path string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), worktreeSizeTimeout)
+		defer cancel()
+
+		bytes, err := fsutil.DirSizeContext(ctx, path)
+		size := WorktreeSize{Bytes: bytes}
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			size.Partial = true
+		case err != nil:
+			size.Unknown = true
+		}
+		return worktreeSizedMsg{path: path, size: size}
+	}
+}
+
+// handleWorktreeSized stores the measured size of a worktree
+func (m AppModel) handleWorktreeSized(msg worktreeSizedMsg) (tea.Model, tea.Cmd) {
+	if m.WorktreeSizes == nil {
+		m.WorktreeSizes = make(map[string]WorktreeSize)
+	}
+	m.WorktreeSizes[msg.path] = msg.size
+	return m, nil
+}
+
+// worktreeSizeLabel returns the size of a worktree for display, or "calculating…" until it is measured
+func (m AppModel) worktreeSizeLabel(path string) string {
+	size, measured := m.WorktreeSizes[path]
+	if !measured {
+		return "calculating…"
+	}
+	return size.String()
+}
+
+// totalWorktreeSize adds up the sizes of the worktrees. Returns false while some are still being measured.
+func (m AppModel) totalWorktreeSize(paths map[string]string) (WorktreeSize, bool) {
+	var total WorktreeSize
+	for _, path := range paths {
+		size, measured := m.WorktreeSizes[path]
+		if !measured {
+			return WorktreeSize{}, false
+		}
+		total = total.add(size)
+	}
+	return total, true
+}
+
+// recordFreedSpace adds the size of a removed worktree to the space freed by the run.
+// Worktrees removed before their size was known make the total partial.
+func (m *AppModel) recordFreedSpace(path string) {
+	size, measured := m.WorktreeSizes[path]
+	if !measured {
+		size.Unknown = true
+	}
+	m.FreedSpace = m.FreedSpace.add(size)
+	m.RemovedWorktreeCount++
+}
+
+// freedSpaceLine describes the disk space freed by removing worktrees, or "" if none were removed
+// or nothing is known about their size
+func (m AppModel) freedSpaceLine() string {
+	switch {
+	case m.RemovedWorktreeCount == 0, m.FreedSpace.Partial && m.FreedSpace.Bytes == 0:
+		return ""
+	case m.FreedSpace.Partial:
+		return "freed at least " + fsutil.FormatSize(m.FreedSpace.Bytes)
+	}
+	return "freed " + m.FreedSpace.String()
+}
+
+// worktreeTotalLine tells how much disk space removing the worktrees frees, once all of them are measured
+func (m AppModel) worktreeTotalLine(paths map[string]string) string {
+	total, done := m.totalWorktreeSize(paths)
+	if !done {
+		return "Disk space freed: calculating…"
+	}
+	return fmt.Sprintf("Disk space freed: %s in %d worktree(s)", total, len(paths))
+}
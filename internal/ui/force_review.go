@@ -0,0 +1,224 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// reviewCommitsLoadedMsg carries the commits only an unmerged branch has, for StateForceReview
+type reviewCommitsLoadedMsg BranchPreview
+
+// startForceReview leaves the force confirmation for StateForceReview, where the unmerged branches are
+// decided on one by one with their commits in view, starting with the first one
+func (m AppModel) startForceReview() (tea.Model, tea.Cmd) {
+	m.State = StateForceReview
+	m.ReviewCursor = 0
+	m.ForceDecisions = make(map[string]bool)
+	m.ReviewCommits = make(map[string]BranchPreview)
+	return m, m.loadReviewCommits()
+}
+
+// reviewBranches returns the branches decided on in StateForceReview: the unmerged branches in list order
+func (m AppModel) reviewBranches() []string {
+	return m.orderedBranches(m.UnmergedBranches)
+}
+
+// reviewBranch returns the branch under the review cursor
+func (m AppModel) reviewBranch() (string, bool) {
+	branches := m.reviewBranches()
+	if m.ReviewCursor < 0 || m.ReviewCursor >= len(branches) {
+		return "", false
+	}
+	return branches[m.ReviewCursor], true
+}
+
+// loadReviewCommits returns a command that loads the commits of the branch under the review cursor,
+// unless they were loaded already
+func (m AppModel) loadReviewCommits() tea.Cmd {
+	branch, ok := m.reviewBranch()
+	if !ok {
+		return nil
+	}
+	if _, loaded := m.ReviewCommits[branch]; loaded {
+		return nil
+	}
+
+	base := m.previewBase()
+	m.ReviewCommits[branch] = BranchPreview{Branch: branch, Base: base, Loading: true}
+	return func() tea.Msg {
+		review := BranchPreview{Branch: branch, Base: base}
+		commits, err := git.GetBranchCommits(git.BranchRef(branch), base, previewCommitLimit)
+		if err != nil {
+			review.Err = err.Error()
+		}
+		review.Commits = commits
+		return reviewCommitsLoadedMsg(review)
+	}
+}
+
+// handleReviewCommitsLoaded stores the commits of an unmerged branch
+func (m AppModel) handleReviewCommitsLoaded(msg reviewCommitsLoadedMsg) (tea.Model, tea.Cmd) {
+	if m.ReviewCommits != nil {
+		m.ReviewCommits[msg.Branch] = BranchPreview(msg)
+	}
+	return m, nil
+}
+
+// handleForceReviewInput handles keyboard input in StateForceReview. The force and skip keys decide on the
+// branch under the cursor and move on to the next one; the up and down keys revisit decisions already made,
+// and esc or q returns to the force confirmation, forgetting them.
+func (m AppModel) handleForceReviewInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := m.keyMap()
+	switch {
+	case key.Matches(msg, keys.ForceAll, keys.Confirm):
+		return m.decideReviewBranch(true)
+	case key.Matches(msg, keys.SkipAll, keys.Cancel):
+		return m.decideReviewBranch(false)
+	case key.Matches(msg, keys.Up):
+		m.ReviewCursor = max(0, m.ReviewCursor-1)
+		return m, m.loadReviewCommits()
+	case key.Matches(msg, keys.Down):
+		m.ReviewCursor = min(m.ReviewCursor+1, len(m.reviewBranches())-1, len(m.ForceDecisions))
+		return m, m.loadReviewCommits()
+	case msg.Type == tea.KeyEsc, isPromptExit(msg):
+		m.State = StateForceConfirmation
+		m.ForceDecisions = nil
+		return m, nil
+	}
+	return m, nil
+}
+
+// decideReviewBranch records whether the branch under the review cursor is force deleted, then moves on to the
+// first branch without a decision, or carries out the decisions once every branch has one
+func (m AppModel) decideReviewBranch(force bool) (tea.Model, tea.Cmd) {
+	branch, ok := m.reviewBranch()
+	if !ok {
+		return m, nil
+	}
+	m.ForceDecisions[branch] = force
+
+	for i, next := range m.reviewBranches() {
+		if _, decided := m.ForceDecisions[next]; !decided {
+			m.ReviewCursor = i
+			return m, m.loadReviewCommits()
+		}
+	}
+	return m.applyForceDecisions()
+}
+
+// applyForceDecisions force deletes the branches decided to be force deleted, or plans their deletion in dry runs.
+// The other unmerged branches are skipped when the run finishes.
+func (m AppModel) applyForceDecisions() (tea.Model, tea.Cmd) {
+	var forced []string
+	for _, branch := range m.reviewBranches() {
+		if m.ForceDecisions[branch] {
+			forced = append(forced, branch)
+		}
+	}
+
+	switch {
+	case len(forced) == 0:
+		return m.finishDeletion()
+	case m.DryRun:
+		for i, action := range m.DryRunActions {
+			if m.ForceDecisions[action.Branch] {
+				m.DryRunActions[i].Force = true
+				delete(m.UnmergedBranches, action.Branch)
+			}
+		}
+		return m.finishDeletion()
+	}
+	return m.startForceDeletion(forced, false)
+}
+
+// renderForceReview renders the unmerged branches with the decisions made so far, and the commits
+// of the branch under the cursor that would be lost by force deleting it
+func (m AppModel) renderForceReview() string {
+	var b strings.Builder
+	branches := m.reviewBranches()
+	b.WriteString(m.Styles.Error.Render(m.t(i18n.ForceReviewTitle, min(m.ReviewCursor+1, len(branches)), len(branches))))
+	b.WriteString("\n\n")
+
+	for i, branch := range branches {
+		b.WriteString(m.renderReviewRow(branch, i == m.ReviewCursor))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if branch, ok := m.reviewBranch(); ok {
+		b.WriteString(m.renderReviewDetails(branch))
+	}
+
+	if m.DryRun {
+		b.WriteString(m.Styles.Help.Render(m.t(i18n.DryRunNote)))
+		b.WriteString("\n")
+	}
+	keys := m.keyMap()
+	b.WriteString(m.Styles.Help.Render(strings.Join([]string{
+		helpItemAs(keys.ForceAll, m.t(i18n.HelpForceDelete)),
+		helpItemAs(keys.SkipAll, m.t(i18n.HelpSkipBranch)),
+		firstKeyLabel(keys.Up) + "/" + firstKeyLabel(keys.Down) + ": " + m.t(i18n.HelpPreviousNext),
+		"esc: " + m.t(i18n.HelpBackToChoices),
+	}, " • ")))
+	return b.String()
+}
+
+// renderReviewRow renders an unmerged branch in StateForceReview with its decision, if any
+func (m AppModel) renderReviewRow(branch string, isCursor bool) string {
+	cursor := "  "
+	if isCursor {
+		cursor = m.Styles.Cursor.Render("> ")
+	}
+
+	force, decided := m.ForceDecisions[branch]
+	switch {
+	case !decided:
+		return cursor + "[ ] " + branch
+	case force:
+		return cursor + m.Styles.Error.Render("[✗] "+branch+" — "+m.t(i18n.DecisionForceDelete))
+	}
+	return cursor + m.Styles.Help.UnsetMarginTop().Render("[–] "+branch+" — "+m.t(i18n.DecisionSkip))
+}
+
+// renderReviewDetails renders why git refused to delete the branch under the cursor and the commits only it has
+func (m AppModel) renderReviewDetails(branch string) string {
+	var b strings.Builder
+	b.WriteString(m.renderForceConfirmationBranch(branch))
+	b.WriteString("\n")
+
+	review := m.ReviewCommits[branch]
+	switch {
+	case review.Loading:
+		b.WriteString(m.Styles.Help.UnsetMarginTop().Render(m.t(i18n.LoadingCommits)))
+		b.WriteString("\n")
+	case review.Err != "":
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.ErrorLine, review.Err)))
+		b.WriteString("\n")
+	case len(review.Commits) == 0:
+		b.WriteString(m.Styles.Help.UnsetMarginTop().Render(m.t(i18n.NoCommitsOnlyOn, review.Base)))
+		b.WriteString("\n")
+	default:
+		width := m.Width
+		if width <= 0 {
+			width = defaultPreviewWidth
+		}
+		b.WriteString(m.t(i18n.CommitsOnlyOn, review.Base))
+		b.WriteString("\n")
+		for _, commit := range review.Commits {
+			// Long subjects are truncated so they never wrap and break the layout
+			b.WriteString(ansi.Truncate("    "+commit, width, "…"))
+			b.WriteString("\n")
+		}
+		if len(review.Commits) == previewCommitLimit {
+			b.WriteString(m.Styles.Help.UnsetMarginTop().Render("    " + m.t(i18n.LatestCommitsOnly, previewCommitLimit)))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
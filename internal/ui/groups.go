@@ -0,0 +1,197 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// otherGroup is the group of branches without a slash in the grouped view
+const otherGroup = "(other)"
+
+// listRow is a row of the selection list: a branch, or a group header in the grouped view
+type listRow struct {
+	// branch is the branch shown in the row; empty for group headers
+	branch string
+
+	// group is the group the row belongs to (empty in the flat view)
+	group string
+}
+
+// isHeader reports whether the row is a group header
+func (r listRow) isHeader() bool {
+	return r.branch == ""
+}
+
+// groupOf returns the group of a branch: its first path segment, or otherGroup if it has no slash.
+// Remote branches are grouped by the segment after the remote name.
+func (m AppModel) groupOf(branch string) string {
+	if m.Remote {
+		_, branch, _ = strings.Cut(branch, "/")
+	}
+	if prefix, _, found := strings.Cut(branch, "/"); found {
+		return prefix
+	}
+	return otherGroup
+}
+
+// groupBranches returns the groups of the given branches in alphabetical order with otherGroup last,
+// and the branches of each group in list order
+func (m AppModel) groupBranches(branches []string) ([]string, map[string][]string) {
+	members := make(map[string][]string)
+	var groups []string
+	for _, branch := range branches {
+		group := m.groupOf(branch)
+		if _, seen := members[group]; !seen {
+			groups = append(groups, group)
+		}
+		members[group] = append(members[group], branch)
+	}
+
+	slices.SortFunc(groups, func(a, b string) int {
+		switch {
+		case a == otherGroup:
+			return 1
+		case b == otherGroup:
+			return -1
+		}
+		return strings.Compare(a, b)
+	})
+	return groups, members
+}
+
+// listRows returns the rows of the selection list: the visible branches, and in the grouped view a header
+// before the branches of each group. The branches of collapsed groups are left out.
+func (m AppModel) listRows() []listRow {
+	visible := m.VisibleBranches()
+	if !m.Grouped {
+		rows := make([]listRow, 0, len(visible))
+		for _, branch := range visible {
+			rows = append(rows, listRow{branch: branch})
+		}
+		return rows
+	}
+
+	groups, members := m.groupBranches(visible)
+	rows := make([]listRow, 0, len(groups)+len(visible))
+	for _, group := range groups {
+		rows = append(rows, listRow{group: group})
+		if m.CollapsedGroups[group] {
+			continue
+		}
+		for _, branch := range members[group] {
+			rows = append(rows, listRow{branch: branch, group: group})
+		}
+	}
+	return rows
+}
+
+// cursorRow returns the row under the cursor, if the list isn't empty
+func (m AppModel) cursorRow() (listRow, bool) {
+	rows := m.listRows()
+	if m.CursorIndex >= len(rows) {
+		return listRow{}, false
+	}
+	return rows[m.CursorIndex], true
+}
+
+// cursorBranch returns the branch under the cursor; false if the list is empty or the cursor is on a group header
+func (m AppModel) cursorBranch() (string, bool) {
+	row, ok := m.cursorRow()
+	if !ok || row.isHeader() {
+		return "", false
+	}
+	return row.branch, true
+}
+
+// moveCursorTo puts the cursor on the row of the branch, or on its group header if its group is collapsed.
+// The cursor stays where it is if the branch isn't visible.
+func (m *AppModel) moveCursorTo(branch string) {
+	for i, row := range m.listRows() {
+		if row.branch == branch || (row.isHeader() && m.CollapsedGroups[row.group] && row.group == m.groupOf(branch)) {
+			m.CursorIndex = i
+			break
+		}
+	}
+	m.moveCursor(0)
+}
+
+// toggleGrouped switches between the flat and the grouped view, keeping the cursor on the same branch.
+// On a group header, the cursor moves to the first branch of the group in the flat view.
+func (m *AppModel) toggleGrouped() {
+	row, ok := m.cursorRow()
+	m.Grouped = !m.Grouped
+	if !ok {
+		return
+	}
+
+	branch := row.branch
+	if row.isHeader() {
+		_, members := m.groupBranches(m.VisibleBranches())
+		branch = members[row.group][0]
+	}
+	m.moveCursorTo(branch)
+}
+
+// handleGroupKey handles the keys of the grouped view and reports whether the key was handled:
+// Group (g) switches between the flat and grouped views, and Collapse (enter) on a group header collapses
+// or expands it
+func (m *AppModel) handleGroupKey(msg tea.KeyMsg) bool {
+	keys := m.keyMap()
+	if key.Matches(msg, keys.Group) {
+		m.toggleGrouped()
+		return true
+	}
+
+	row, ok := m.cursorRow()
+	if !key.Matches(msg, keys.Collapse) || !ok || !row.isHeader() {
+		return false
+	}
+	m.toggleCollapsed(row.group)
+	return true
+}
+
+// toggleCollapsed collapses or expands a group
+func (m *AppModel) toggleCollapsed(group string) {
+	if m.CollapsedGroups == nil {
+		m.CollapsedGroups = make(map[string]bool)
+	}
+	m.CollapsedGroups[group] = !m.CollapsedGroups[group]
+	m.moveCursor(0)
+}
+
+// toggleGroupSelection selects the visible branches of a group, or deselects them if all are already selected
+func (m *AppModel) toggleGroupSelection(group string) {
+	_, members := m.groupBranches(m.VisibleBranches())
+	selectAll := slices.ContainsFunc(members[group], func(branch string) bool { return !m.Selected[branch] })
+	for _, branch := range members[group] {
+		m.Selected[branch] = selectAll
+	}
+}
+
+// renderGroupHeader renders the header row of a group with its selected and total branch counts
+func (m AppModel) renderGroupHeader(group string, isCursor bool) string {
+	cursor := "  "
+	if isCursor {
+		cursor = m.Styles.Cursor.Render("> ")
+	}
+
+	arrow := "▾"
+	if m.CollapsedGroups[group] {
+		arrow = "▸"
+	}
+
+	_, members := m.groupBranches(m.VisibleBranches())
+	selected := 0
+	for _, branch := range members[group] {
+		if m.Selected[branch] {
+			selected++
+		}
+	}
+
+	header := m.Styles.Title.UnsetMarginBottom().Render(fmt.Sprintf("%s %s", arrow, group))
+	return fmt.Sprintf("%s%s %s", cursor, header, m.Styles.Metadata.Render(fmt.Sprintf("(%d/%d selected)", selected, len(members[group]))))
+}
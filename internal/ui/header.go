@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// headerLines returns the number of lines the context header takes: one, or none without a repository path
+func (m AppModel) headerLines() int {
+	if m.RepoPath == "" {
+		return 0
+	}
+	return 1
+}
+
+// renderHeader renders the context header shown above every screen: the repository and its superproject
+// if it is a submodule, the current branch, any operation in progress, the base branch, the number of branches and the mine filter. The paths are
+// truncated from the left to fit the terminal width, keeping the repository's directory name visible.
+func (m AppModel) renderHeader() string {
+	if m.RepoPath == "" {
+		return ""
+	}
+
+	current := m.t(i18n.HeaderOnBranch, m.CurrentBranch)
+	if m.CurrentBranch == "" {
+		current = m.t(i18n.HeaderDetachedHead)
+	}
+	details := " • " + current
+	if m.RepositoryState != git.StateClean {
+		details += " • " + m.t(i18n.HeaderOperationInProgress, m.RepositoryState)
+	}
+	if m.BaseBranch != "" {
+		details += " • " + m.t(i18n.HeaderBase, m.BaseBranch)
+	}
+	details += " • " + m.Lang.Count(len(m.Branches), i18n.Branch)
+	if m.OnlyMine {
+		details += " • " + m.t(i18n.HeaderMine, m.MineField.Describe(m.Lang, m.UserEmail))
+	}
+
+	path := m.RepoPath
+	if m.SuperprojectPath != "" {
+		path += " " + m.t(i18n.HeaderSubmodule, m.SuperprojectPath)
+	}
+	if overflow := lipgloss.Width(path) + lipgloss.Width(details) - m.Width; m.Width > 0 && overflow > 0 {
+		path = ansi.TruncateLeft(path, overflow+1, "…")
+	}
+	return m.Styles.Header.Render(path+details) + "\n"
+}
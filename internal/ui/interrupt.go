@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// InterruptMsg makes the program quit because gelete received Signal, e.g. SIGINT from kill or SIGTERM
+// from a closing terminal. Git commands in flight are killed and nothing else is deleted.
+// Send it with Program.Send; the terminal is restored as the program quits.
+type InterruptMsg struct {
+	Signal os.Signal
+}
+
+// handleInterrupt quits on a signal, skipping the branches still queued in a deletion run
+func (m AppModel) handleInterrupt(msg InterruptMsg) (tea.Model, tea.Cmd) {
+	m.Interrupted = msg.Signal
+	if m.State == StateDeleting {
+		m.cancelDeletion()
+	}
+	m.releaseContext()
+	return m, tea.Quit
+}
+
+// handleCtrlC handles ctrl+c in every state, whatever the key map. It quits, except in a deletion run:
+// while deleting, the first ctrl+c cancels the run after the operation in flight and a second one kills it
+// and quits; the prompts in the middle of a run cancel it with a partial summary.
+func (m AppModel) handleCtrlC() (tea.Model, tea.Cmd) {
+	switch {
+	case m.State == StateDeleting && !m.Cancelled:
+		m.cancelDeletion()
+		return m, nil
+	case m.State == StateForceConfirmation, m.State == StateForceReview, m.State == StateLockedWorktreeConfirmation, m.State == StateCheckedOutConfirmation:
+		return m.cancelAtPrompt()
+	}
+	m.releaseContext()
+	return m, tea.Quit
+}
+
+// cancelAtPrompt cancels a run from one of the prompts in the middle of it: the branches the prompt asks
+// about and those not deleted yet are skipped, and the run ends with a partial summary
+func (m AppModel) cancelAtPrompt() (tea.Model, tea.Cmd) {
+	m.Queue = m.branchesToDelete()
+	m.cancelDeletion()
+	return m.finishDeletion()
+}
+
+// handleInterrupt quits on a signal, killing the git command in flight
+func (m WorktreeModel) handleInterrupt(msg InterruptMsg) (tea.Model, tea.Cmd) {
+	m.Interrupted = msg.Signal
+	m.Queue = nil
+	if m.cancel != nil {
+		m.cancel()
+	}
+	return m, tea.Quit
+}
@@ -0,0 +1,384 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyMap holds the keys of the branch list and of the prompts confirming a deletion.
+// ctrl+c always quits or cancels and can't be bound to an action.
+type KeyMap struct {
+	// Up and Down move the cursor, in the branch list and in the confirmation list
+	Up   key.Binding
+	Down key.Binding
+
+	// PageUp, PageDown, Home and End scroll the branch list
+	PageUp   key.Binding
+	PageDown key.Binding
+	Home     key.Binding
+	End      key.Binding
+
+	// Toggle selects or deselects the branch under the cursor, or the whole group on a group header
+	Toggle key.Binding
+
+	// SelectAll selects every visible branch, or deselects them if all are selected; Invert flips the selection
+	SelectAll key.Binding
+	Invert    key.Binding
+
+	// SelectPattern selects the visible branches matching a typed glob
+	SelectPattern key.Binding
+
+	// SelectWIP selects the visible branches whose own commits are all WIP commits (see git.IsWIPBranch)
+	SelectWIP key.Binding
+
+	// Sort cycles the branch order and MergeFilter the merged/unmerged filter
+	Sort        key.Binding
+	MergeFilter key.Binding
+
+	// DateFormat switches the commit dates between relative and absolute
+	DateFormat key.Binding
+
+	// Mine switches between all branches and those whose last commit is the user's
+	Mine key.Binding
+
+	// Group switches between the flat list and the grouped view; Collapse collapses or expands the group
+	// under the cursor, taking precedence over Toggle on group headers
+	Group    key.Binding
+	Collapse key.Binding
+
+	// Filter starts typing a filter query and ClearFilter clears it
+	Filter      key.Binding
+	ClearFilter key.Binding
+
+	// SelectionPane shows or hides the pane listing the selected branches right of the list
+	SelectionPane key.Binding
+
+	// Preview, Checkout, Rename and Copy act on the branch under the cursor (Copy on the selection, if any)
+	Preview  key.Binding
+	Checkout key.Binding
+	Rename   key.Binding
+	Copy     key.Binding
+
+	// Compare marks the branch under the cursor as the comparison anchor, then compares the anchor with
+	// the branch it is pressed on next
+	Compare key.Binding
+
+	// Refresh lists the branches again, dropping those deleted since the list was loaded
+	Refresh key.Binding
+
+	// Delete asks to confirm deleting the selected branches
+	Delete key.Binding
+
+	// Quit quits without deleting anything
+	Quit key.Binding
+
+	// Back quits the branch list to return to the repository picker, when the model offers it (see CanGoBack)
+	Back key.Binding
+
+	// Confirm and Cancel answer the prompts confirming a deletion
+	Confirm key.Binding
+	Cancel  key.Binding
+
+	// DiscardChanges answers the worktree removal prompts by also removing the worktrees with uncommitted
+	// changes, which a plain Confirm skips
+	DiscardChanges key.Binding
+
+	// ForceAll, SkipAll and ReviewEach answer the force confirmation besides Confirm and Cancel: force deleting
+	// all the unmerged branches, skipping them all, or deciding on them one by one with their commits in view,
+	// where ForceAll and SkipAll decide on a single branch
+	ForceAll   key.Binding
+	SkipAll    key.Binding
+	ReviewEach key.Binding
+
+	// Backup answers the force confirmation by tagging each branch as gelete-backup/<branch>-<date>
+	// before force deleting it
+	Backup key.Binding
+
+	// ToggleRemote, ToggleTags and ToggleForce toggle the options of the confirmation screen:
+	// deleting the upstream and the tags of the highlighted branch, and force deleting
+	ToggleRemote key.Binding
+	ToggleTags   key.Binding
+	ToggleForce  key.Binding
+}
+
+// DefaultKeyMap returns the built-in keys: vim-style j/k along with the arrow keys
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up:             newBinding("up", "up", "k"),
+		Down:           newBinding("down", "down", "j"),
+		PageUp:         newBinding("page up", "pgup"),
+		PageDown:       newBinding("page down", "pgdown"),
+		Home:           newBinding("first branch", "home"),
+		End:            newBinding("last branch", "end"),
+		Toggle:         newBinding("toggle", " ", "enter"),
+		SelectAll:      newBinding("select/deselect all", "a"),
+		Invert:         newBinding("invert", "A"),
+		SelectPattern:  newBinding("select by pattern", "*"),
+		SelectWIP:      newBinding("select WIP", "w"),
+		Sort:           newBinding("sort", "s"),
+		MergeFilter:    newBinding("merged/unmerged", "m"),
+		DateFormat:     newBinding("relative/absolute dates", "t"),
+		Mine:           newBinding("all/mine", "o"),
+		Group:          newBinding("group by prefix", "g"),
+		Collapse:       newBinding("collapse/expand", "enter"),
+		Filter:         newBinding("filter", "/"),
+		ClearFilter:    newBinding("clear filter", "esc"),
+		SelectionPane:  newBinding("selection pane", "tab"),
+		Preview:        newBinding("preview", "p"),
+		Checkout:       newBinding("checkout", "c"),
+		Rename:         newBinding("rename", "e"),
+		Copy:           newBinding("copy name(s)", "y"),
+		Compare:        newBinding("compare", "x"),
+		Refresh:        newBinding("refresh", "r"),
+		Delete:         newBinding("delete selected", "d"),
+		Quit:           newBinding("quit", "q"),
+		Back:           newBinding("back to repositories", "b"),
+		Confirm:        newBinding("confirm", "y"),
+		DiscardChanges: newBinding("discard changes and remove all", "D"),
+		Cancel:         newBinding("cancel", "n"),
+		Backup:         newBinding("backup then delete", "b"),
+		ForceAll:       newBinding("force delete all", "f"),
+		SkipAll:        newBinding("skip all", "s"),
+		ReviewEach:     newBinding("decide one by one", "i"),
+		ToggleRemote:   newBinding("also delete remote", "r"),
+		ToggleTags:     newBinding("also delete tags", "t"),
+		ToggleForce:    newBinding("force delete", "f"),
+	}
+}
+
+// keyScope is where the keys of an action are active; keys of actions sharing a scope must differ
+type keyScope int
+
+const (
+	// scopeList: the branch list
+	scopeList keyScope = 1 << iota
+	// scopeHeader: the group headers of the branch list, where Collapse replaces Toggle
+	scopeHeader
+	// scopePrompt: the prompts confirming a deletion
+	scopePrompt
+	// scopeForce: the prompt confirming the force deletion of unmerged branches, and their review one by one
+	scopeForce
+)
+
+// scopeRows: the branch list, including its group headers
+const scopeRows = scopeList | scopeHeader
+
+// keyAction is an action of the keys section of the config file, e.g. "delete"
+type keyAction struct {
+	name    string
+	binding *key.Binding
+	scope   keyScope
+}
+
+// actions returns the rebindable actions of the key map with their names in the config file
+func (k *KeyMap) actions() []keyAction {
+	return []keyAction{
+		{"up", &k.Up, scopeRows | scopePrompt | scopeForce},
+		{"down", &k.Down, scopeRows | scopePrompt | scopeForce},
+		{"page_up", &k.PageUp, scopeRows},
+		{"page_down", &k.PageDown, scopeRows},
+		{"home", &k.Home, scopeRows},
+		{"end", &k.End, scopeRows},
+		{"toggle", &k.Toggle, scopeList},
+		{"select_all", &k.SelectAll, scopeRows},
+		{"invert", &k.Invert, scopeRows},
+		{"select_pattern", &k.SelectPattern, scopeRows},
+		{"select_wip", &k.SelectWIP, scopeRows},
+		{"sort", &k.Sort, scopeRows},
+		{"merge_filter", &k.MergeFilter, scopeRows},
+		{"date_format", &k.DateFormat, scopeRows},
+		{"mine_filter", &k.Mine, scopeRows},
+		{"group", &k.Group, scopeRows},
+		{"collapse", &k.Collapse, scopeHeader},
+		{"filter", &k.Filter, scopeRows},
+		{"clear_filter", &k.ClearFilter, scopeRows},
+		{"selection_pane", &k.SelectionPane, scopeRows},
+		{"preview", &k.Preview, scopeRows},
+		{"checkout", &k.Checkout, scopeRows},
+		{"rename", &k.Rename, scopeRows},
+		{"copy", &k.Copy, scopeRows},
+		{"compare", &k.Compare, scopeRows},
+		{"refresh", &k.Refresh, scopeRows},
+		{"delete", &k.Delete, scopeRows},
+		{"quit", &k.Quit, scopeRows},
+		{"back", &k.Back, scopeRows},
+		{"confirm", &k.Confirm, scopePrompt | scopeForce},
+		{"discard_changes", &k.DiscardChanges, scopePrompt},
+		{"cancel", &k.Cancel, scopePrompt | scopeForce},
+		{"backup", &k.Backup, scopePrompt | scopeForce},
+		{"force_all", &k.ForceAll, scopeForce},
+		{"skip_all", &k.SkipAll, scopeForce},
+		{"review_each", &k.ReviewEach, scopeForce},
+		{"toggle_remote", &k.ToggleRemote, scopePrompt},
+		{"toggle_tags", &k.ToggleTags, scopePrompt},
+		{"toggle_force", &k.ToggleForce, scopePrompt},
+	}
+}
+
+// KeyActions returns the names of the actions that can be rebound in the keys section of the config file
+func KeyActions() []string {
+	var keys KeyMap
+	var names []string
+	for _, action := range keys.actions() {
+		names = append(names, action.name)
+	}
+	return names
+}
+
+// NewKeyMap returns the default keys with the actions in bindings rebound, e.g. {"delete": {"x"}}.
+// Keys are named like Bubble Tea names them ("enter", "ctrl+d", "pgdown"), plus "space".
+// Unknown actions, ctrl+c and keys bound to two actions active on the same screen are refused.
+func NewKeyMap(bindings map[string][]string) (KeyMap, error) {
+	keys := DefaultKeyMap()
+	actions := keys.actions()
+
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		i := slices.IndexFunc(actions, func(action keyAction) bool { return action.name == name })
+		if i == -1 {
+			return KeyMap{}, fmt.Errorf("unknown key action '%s' (known actions: %s)", name, strings.Join(KeyActions(), ", "))
+		}
+		rebound, err := rebind(*actions[i].binding, name, bindings[name])
+		if err != nil {
+			return KeyMap{}, err
+		}
+		*actions[i].binding = rebound
+	}
+
+	if err := checkDuplicateKeys(actions); err != nil {
+		return KeyMap{}, err
+	}
+	return keys, nil
+}
+
+// rebind returns the binding with its keys replaced, keeping its help description
+func rebind(binding key.Binding, action string, keys []string) (key.Binding, error) {
+	if len(keys) == 0 {
+		return binding, fmt.Errorf("no keys given for key action '%s'", action)
+	}
+
+	normalized := make([]string, len(keys))
+	for i, k := range keys {
+		// " " is the space bar, as Bubble Tea names it
+		if k != " " {
+			k = strings.TrimSpace(k)
+		}
+		switch k {
+		case "":
+			return binding, fmt.Errorf("empty key given for key action '%s'", action)
+		case "ctrl+c":
+			return binding, fmt.Errorf("ctrl+c can't be bound to '%s': it always quits", action)
+		case "space":
+			k = " "
+		}
+		normalized[i] = k
+	}
+	return newBinding(binding.Help().Desc, normalized...), nil
+}
+
+// checkDuplicateKeys returns an error if a key is bound to two actions active on the same screen
+func checkDuplicateKeys(actions []keyAction) error {
+	for i, action := range actions {
+		for _, other := range actions[i+1:] {
+			if action.scope&other.scope == 0 {
+				continue
+			}
+			for _, k := range action.binding.Keys() {
+				if slices.Contains(other.binding.Keys(), k) {
+					return fmt.Errorf("key '%s' is bound to both '%s' and '%s'", keyLabel(k), action.name, other.name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// defaultKeys is the key map of models without one
+var defaultKeys = DefaultKeyMap()
+
+// keyMap returns the keys of the model: Keys, or the default keys if none were set
+func (m AppModel) keyMap() KeyMap {
+	if m.Keys == nil {
+		return defaultKeys
+	}
+	return *m.Keys
+}
+
+// newBinding returns a binding of keys whose help shows the keys as they are labeled on the keyboard
+func newBinding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(keyLabels(keys), desc))
+}
+
+// keyLabels returns how keys are shown in the help, e.g. "↑/k"
+func keyLabels(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		labels[i] = keyLabel(k)
+	}
+	return strings.Join(labels, "/")
+}
+
+// keyLabel returns how a key is shown in the help: arrows as symbols, the space bar as "space"
+func keyLabel(k string) string {
+	switch k {
+	case " ":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case "pgdown":
+		return "pgdn"
+	}
+	return k
+}
+
+// helpItem returns the help of a binding, e.g. "d: delete selected"
+func helpItem(b key.Binding) string {
+	return helpItemAs(b, b.Help().Desc)
+}
+
+// helpItemAs returns the help of a binding with another description, for actions whose effect depends on the state
+func helpItemAs(b key.Binding, desc string) string {
+	return b.Help().Key + ": " + desc
+}
+
+// helpItemOf returns the help of several bindings sharing a description, e.g. "pgup/pgdn/home/end: scroll"
+func helpItemOf(desc string, bindings ...key.Binding) string {
+	var keys []string
+	for _, b := range bindings {
+		keys = append(keys, b.Keys()...)
+	}
+	return keyLabels(keys) + ": " + desc
+}
+
+// firstKeyLabel returns the label of the first key of a binding, for compact help like "↑/↓: move"
+func firstKeyLabel(b key.Binding) string {
+	keys := b.Keys()
+	if len(keys) == 0 {
+		return ""
+	}
+	return keyLabel(keys[0])
+}
+
+// keysExcept returns the label of the keys of b that aren't keys of other, e.g. the keys toggling
+// the selection of a group, since Collapse takes precedence on group headers
+func keysExcept(b, other key.Binding) string {
+	keys := slices.DeleteFunc(slices.Clone(b.Keys()), func(k string) bool {
+		return slices.Contains(other.Keys(), k)
+	})
+	return keyLabels(keys)
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// BranchData is the branch list and its metadata, gathered by a BranchLoader while the TUI starts
+type BranchData struct {
+	// CurrentBranch is the checked-out branch (empty when HEAD is detached)
+	CurrentBranch string
+
+	// DetachedHead is true when no branch is checked out
+	DetachedHead bool
+
+	// Branches contains the deletable branches, in list order
+	Branches []string
+
+	// Selected holds the branches selected when the list opens
+	Selected map[string]bool
+
+	// BranchDetails maps branch names to their last commit metadata
+	BranchDetails map[string]git.BranchInfo
+
+	// BranchWorktrees maps branch names to their worktree paths
+	BranchWorktrees map[string]string
+
+	// StaleWorktrees marks branches whose worktree directory no longer exists
+	StaleWorktrees map[string]bool
+
+	// Upstreams maps local branches to the remote branch they track
+	Upstreams map[string]git.Upstream
+
+	// BaseBranch is the branch merge status is computed against (empty if unknown)
+	BaseBranch string
+
+	// MergedBranches tracks which branches are fully merged into BaseBranch (nil if unknown)
+	MergedBranches map[string]bool
+
+	// SquashMerged tracks the unmerged branches whose content appears merged into BaseBranch
+	SquashMerged map[string]bool
+
+	// Divergence holds ahead/behind counts of each branch relative to BaseBranch (nil if not computed)
+	Divergence map[string]git.AheadBehind
+
+	// DefaultBranch is the repository's default branch (empty if unknown)
+	DefaultBranch string
+}
+
+// BranchLoader gathers the branches and their metadata. It runs in the background behind a spinner,
+// since classifying branches (merge status, squash merges, divergence) can take a while in big repositories.
+type BranchLoader func() (BranchData, error)
+
+// branchesLoadedMsg carries the branches gathered by the Loader
+type branchesLoadedMsg struct {
+	data BranchData
+	err  error
+}
+
+// startLoading returns the command loading the branches with the Loader, animating the spinner meanwhile
+func (m AppModel) startLoading() tea.Cmd {
+	loader := m.Loader
+	load := func() tea.Msg {
+		data, err := loader()
+		return branchesLoadedMsg{data: data, err: err}
+	}
+	return tea.Batch(m.Spinner.Tick, load)
+}
+
+// NewLoadingModel returns m waiting for its Loader, showing a spinner until the branches are loaded
+func NewLoadingModel(m AppModel) AppModel {
+	m.State = StateLoading
+	m.Spinner = spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(m.Styles.Cursor))
+	return m
+}
+
+// handleBranchesLoaded opens the selection list with the loaded branches.
+// A failed load ends the program with the error as its last screen.
+func (m AppModel) handleBranchesLoaded(msg branchesLoadedMsg) (tea.Model, tea.Cmd) {
+	if m.State != StateLoading {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.LoadErr = msg.err
+		return m, tea.Quit
+	}
+
+	m.applyBranchData(msg.data)
+	m.State = StateSelection
+	// A spinner without an ID ignores the pending tick, which stops the animation
+	m.Spinner = spinner.Model{}
+	m.SetMergeFilter(m.MergeFilter)
+	m.SortBranches()
+	if m.SelectWIP {
+		return m.selectWIP()
+	}
+	return m, nil
+}
+
+// applyBranchData stores the loaded branches and their metadata in the model
+func (m *AppModel) applyBranchData(data BranchData) {
+	m.CurrentBranch = data.CurrentBranch
+	m.DetachedHead = data.DetachedHead
+	m.Branches = data.Branches
+	m.Selected = data.Selected
+	if m.Selected == nil {
+		m.Selected = make(map[string]bool)
+	}
+	m.BranchDetails = data.BranchDetails
+	m.BranchWorktrees = data.BranchWorktrees
+	m.StaleWorktrees = data.StaleWorktrees
+	m.Upstreams = data.Upstreams
+	m.BaseBranch = data.BaseBranch
+	m.MergedBranches = data.MergedBranches
+	m.SquashMerged = data.SquashMerged
+	m.Divergence = data.Divergence
+	m.DefaultBranch = data.DefaultBranch
+}
+
+// handleLoadingInput handles keyboard input while the branches load: q and esc quit right away, like ctrl+c
+func (m AppModel) handleLoadingInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// renderLoading renders the spinner shown while the branches load, or the error that prevented loading them
+func (m AppModel) renderLoading() string {
+	if m.LoadErr != nil {
+		return m.Styles.Error.Render(fmt.Sprintf("Error: %v", m.LoadErr)) + "\n"
+	}
+	return fmt.Sprintf("%s Loading branches…\n\n", m.Spinner.View()) + m.Styles.Help.Render("ctrl+c: quit") + "\n"
+}
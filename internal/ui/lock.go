@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/git"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// lockRetryMsg is sent once the wait before retrying the deletion of a branch that found a lock file is over
+type lockRetryMsg struct {
+	branch string
+}
+
+// lockRetry returns the delay before retrying the deletion of a branch that found a lock file, and whether it is
+// retried at all: not once the run is cancelled, nor once git.NextLockRetry gives up
+func (m AppModel) lockRetry(msg branchDeletedMsg) (time.Duration, bool) {
+	delay, retry := git.NextLockRetry(msg.err, m.lockAttempts+1)
+	return delay, retry && !m.Cancelled
+}
+
+// waitForLock shows that the deletion of a branch is waiting for a lock file, held e.g. by an IDE running git
+// in the background, and retries it after delay. The attempts are counted so that a lock that isn't released
+// fails the branch once git.NextLockRetry gives up.
+func (m AppModel) waitForLock(msg branchDeletedMsg, delay time.Duration) (tea.Model, tea.Cmd) {
+	var locked *git.ErrRefLocked
+	errors.As(msg.err, &locked)
+	m.lockAttempts++
+	m.WaitingForLock = locked.LockFile
+
+	branch := msg.branch
+	return m, tea.Tick(delay, func(time.Time) tea.Msg { return lockRetryMsg{branch: branch} })
+}
+
+// handleLockRetry deletes the branch waiting for a lock file again
+func (m AppModel) handleLockRetry(msg lockRetryMsg) (tea.Model, tea.Cmd) {
+	if m.State != StateDeleting || msg.branch != m.Current {
+		return m, nil
+	}
+	return m, m.operationCmd(msg.branch)
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/fsutil"
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CompactionPromptThreshold is the number of force-deleted branches above which the done screen offers
+// to expire the reflog and run git gc, since their commits would otherwise stay on disk for weeks
+const CompactionPromptThreshold = 10
+
+// compactionWarning warns about what compacting the repository costs
+const compactionWarning = "deleted commits become unrecoverable"
+
+// repositoryCompactedMsg carries the outcome of expiring the reflog and running git gc
+type repositoryCompactedMsg struct {
+	report git.CompactionReport
+	err    error
+}
+
+// canCompact reports whether the repository can be compacted after this run: once, and only if
+// local branches were actually deleted
+func (m AppModel) canCompact() bool {
+	if m.DryRun || m.Remote || m.Compacting || m.Compaction != nil || m.CompactionErr != "" {
+		return false
+	}
+	return m.countResults(ResultDeleted, ResultForceDeleted) > 0
+}
+
+// offersCompaction reports whether the done screen offers compacting the repository with g:
+// only after force-deleting more than CompactionPromptThreshold branches, unless --expire-reflog already did
+func (m AppModel) offersCompaction() bool {
+	return m.canCompact() && m.countResults(ResultForceDeleted) > CompactionPromptThreshold
+}
+
+// startCompaction expires the reflog and runs git gc in the background, behind a spinner on the done screen.
+// The branches deleted in this run can't be restored afterwards, so undo is no longer offered.
+func (m AppModel) startCompaction() (tea.Model, tea.Cmd) {
+	m.Compacting = true
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.Spinner = spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(m.Styles.Cursor))
+
+	ctx := m.ctx
+	compact := func() tea.Msg {
+		report, err := git.CompactRepositoryContext(ctx)
+		return repositoryCompactedMsg{report: report, err: err}
+	}
+	return m, tea.Batch(m.Spinner.Tick, compact)
+}
+
+// handleRepositoryCompacted shows the outcome of compacting the repository on the done screen
+func (m AppModel) handleRepositoryCompacted(msg repositoryCompactedMsg) (tea.Model, tea.Cmd) {
+	m.Compacting = false
+	m.releaseContext()
+	// A spinner without an ID ignores the pending tick, which stops the animation
+	m.Spinner = spinner.Model{}
+	if msg.err != nil {
+		m.CompactionErr = msg.err.Error()
+		return m, nil
+	}
+	m.Compaction = &msg.report
+	return m, nil
+}
+
+// compactionLine describes the compaction of the repository for the done screen, or "" if none was run
+func (m AppModel) compactionLine() string {
+	switch {
+	case m.Compacting:
+		return fmt.Sprintf("%s Expiring the reflog and running git gc (%s)…", m.Spinner.View(), compactionWarning)
+	case m.Compaction == nil:
+		return ""
+	}
+
+	line := fmt.Sprintf("git gc took %s", m.Compaction.Duration.Round(100*time.Millisecond))
+	if m.Compaction.SizeKnown {
+		line += ", reclaimed ~" + fsutil.FormatSize(m.Compaction.Reclaimed())
+	}
+	return line
+}
+
+// compactionOffer is the prompt of the done screen offering to compact the repository
+func (m AppModel) compactionOffer() string {
+	return fmt.Sprintf("%d branches were force deleted: press g to expire the reflog and run git gc to reclaim their space (%s)",
+		m.countResults(ResultForceDeleted), compactionWarning)
+}
+
+// compacted reports whether git gc ran or is running after the deletions, pruning the commits of deleted branches
+func (m AppModel) compacted() bool {
+	return m.Compacting || m.Compaction != nil
+}
+
+// renderCompaction renders the progress or outcome of compacting the repository, or the offer to do it
+func (m AppModel) renderCompaction() string {
+	switch {
+	case m.CompactionErr != "":
+		return "\n" + m.Styles.Error.Render("Error: "+m.CompactionErr) + "\n"
+	case m.offersCompaction():
+		return "\n" + m.Styles.Warning.Render(m.compactionOffer()) + "\n"
+	}
+	if line := m.compactionLine(); line != "" {
+		return "\n" + m.Styles.Help.UnsetMarginTop().Render(line) + "\n"
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/i18n"
+)
+
+// MergeFilter narrows the branch list by merge status against BaseBranch
+type MergeFilter int
+
+const (
+	// MergeFilterAll shows merged and unmerged branches
+	MergeFilterAll MergeFilter = iota
+	// MergeFilterMerged shows only branches fully merged into the base branch
+	MergeFilterMerged
+	// MergeFilterUnmerged shows only branches with commits not in the base branch
+	MergeFilterUnmerged
+)
+
+// message returns the description of the merge filter shown in the status line
+func (f MergeFilter) message() i18n.MessageID {
+	switch f {
+	case MergeFilterMerged:
+		return i18n.FilterMergedOnly
+	case MergeFilterUnmerged:
+		return i18n.FilterUnmergedOnly
+	}
+	return i18n.FilterAll
+}
+
+// next returns the merge filter that follows f when cycling with the m key
+func (f MergeFilter) next() MergeFilter {
+	return (f + 1) % 3
+}
+
+// matchesMergeFilter reports whether a branch passes the merge filter
+func (m AppModel) matchesMergeFilter(branch string) bool {
+	switch m.MergeFilter {
+	case MergeFilterMerged:
+		return m.MergedBranches[branch]
+	case MergeFilterUnmerged:
+		return !m.MergedBranches[branch]
+	}
+	return true
+}
+
+// SetMergeFilter changes the merge filter.
+// Selected branches hidden by the new filter are deselected, so only visible branches get deleted.
+func (m *AppModel) SetMergeFilter(filter MergeFilter) {
+	m.MergeFilter = filter
+	m.deselectHidden()
+	m.moveCursor(0)
+}
+
+// deselectHidden deselects the branches hidden by the merge filter or the mine filter
+func (m *AppModel) deselectHidden() {
+	for branch, selected := range m.Selected {
+		if selected && (!m.matchesMergeFilter(branch) || !m.matchesMineFilter(branch)) {
+			delete(m.Selected, branch)
+		}
+	}
+}
+
+// cycleMergeFilter switches to the next merge filter.
+// Does nothing when the merge status of the branches is unknown.
+func (m *AppModel) cycleMergeFilter() {
+	if m.MergedBranches == nil {
+		return
+	}
+	m.SetMergeFilter(m.MergeFilter.next())
+}
+
+// emptyFilterMessage explains why the filters hide every branch
+func (m AppModel) emptyFilterMessage() string {
+	switch {
+	case m.OnlyMine:
+		return m.t(i18n.NoFilterMatchWith, m.filterDescription())
+	case m.MergeFilter == MergeFilterAll:
+		return m.t(i18n.NoFilterMatch)
+	case m.FilterQuery != "":
+		return m.t(i18n.NoFilterMatchWith, m.t(m.MergeFilter.message()))
+	case m.MergeFilter == MergeFilterMerged:
+		return m.t(i18n.NoMergedBranches, m.BaseBranch)
+	}
+	return m.t(i18n.NoUnmergedBranches, m.BaseBranch)
+}
+
+// filterDescription describes the active merge and mine filters, e.g. "merged only, authored by me@example.com"
+func (m AppModel) filterDescription() string {
+	var filters []string
+	if m.MergeFilter != MergeFilterAll {
+		filters = append(filters, m.t(m.MergeFilter.message()))
+	}
+	if m.OnlyMine {
+		filters = append(filters, m.MineField.Describe(m.Lang, m.UserEmail))
+	}
+	return strings.Join(filters, ", ")
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+)
+
+// MineField is the email of the last commit of a branch compared with the user's to tell whether the branch is theirs
+type MineField int
+
+const (
+	// MineAuthor compares the author email: branches whose last commit the user wrote
+	MineAuthor MineField = iota
+	// MineCommitter compares the committer email, which also counts someone else's work the user rebased or amended
+	MineCommitter
+)
+
+// String returns the name of the field given with --mine
+func (f MineField) String() string {
+	if f == MineCommitter {
+		return "committer"
+	}
+	return "author"
+}
+
+// ParseMineField parses the value of --mine: author or committer
+func ParseMineField(s string) (MineField, error) {
+	switch s {
+	case "author":
+		return MineAuthor, nil
+	case "committer":
+		return MineCommitter, nil
+	}
+	return MineAuthor, fmt.Errorf("invalid --mine '%s' (expected author or committer)", s)
+}
+
+// IsMine reports whether the last commit of the branch has the given email in the field, ignoring case
+func (f MineField) IsMine(info git.BranchInfo, email string) bool {
+	branchEmail := info.AuthorEmail
+	if f == MineCommitter {
+		branchEmail = info.CommitterEmail
+	}
+	return email != "" && strings.EqualFold(branchEmail, email)
+}
+
+// Describe describes the mine filter with the user's email in the language, e.g. "authored by me@example.com"
+func (f MineField) Describe(lang i18n.Lang, email string) string {
+	if f == MineCommitter {
+		return lang.T(i18n.FilterCommittedBy, email)
+	}
+	return lang.T(i18n.FilterAuthoredBy, email)
+}
+
+// matchesMineFilter reports whether a branch passes the mine filter
+func (m AppModel) matchesMineFilter(branch string) bool {
+	return !m.OnlyMine || m.MineField.IsMine(m.BranchDetails[branch], m.UserEmail)
+}
+
+// SetMineFilter shows only the user's branches, or all of them.
+// Selected branches hidden by the filter are deselected, so only visible branches get deleted.
+func (m *AppModel) SetMineFilter(onlyMine bool) {
+	m.OnlyMine = onlyMine
+	m.deselectHidden()
+	m.moveCursor(0)
+}
+
+// toggleMineFilter switches between all branches and the user's.
+// Does nothing when user.email isn't set, since no branch could be told to be theirs.
+func (m *AppModel) toggleMineFilter() {
+	if m.UserEmail == "" {
+		return
+	}
+	m.SetMineFilter(!m.OnlyMine)
+}
@@ -1,6 +1,19 @@
 package ui
 
 import (
+	"context"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/Kdaito/gelete/internal/pr"
+	"github.com/Kdaito/gelete/pkg/gitops"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -14,53 +27,542 @@ const (
 	StateConfirmation
 	// StateForceConfirmation: User is confirming force deletion of unmerged branches
 	StateForceConfirmation
+	// StateWorktreeConfirmation: User is confirming removal of worktrees attached to selected branches
+	StateWorktreeConfirmation
+	// StateLockedWorktreeConfirmation: User is confirming force removal of locked worktrees
+	StateLockedWorktreeConfirmation
+	// StateCheckedOutConfirmation: User is confirming removal of the worktrees git refused to delete branches for
+	StateCheckedOutConfirmation
 	// StateDeleting: Deletion is in progress
 	StateDeleting
 	// StateDone: Deletion complete or cancelled
 	StateDone
+	// StateRestore: User is picking branches deleted in this run to restore
+	StateRestore
+	// StateLoading: Branches and their metadata are being loaded by the Loader
+	StateLoading
+	// StateForceReview: User is deciding branch by branch which unmerged branches to force delete
+	StateForceReview
 )
 
 // AppModel represents the application state following bubbletea's Elm architecture
 type AppModel struct {
+	// Repo is the repository branches and worktrees are deleted in; the zero Repo uses the opened repository
+	Repo gitops.Repo
+
+	// RepoPath is the top-level directory of the repository, shown in the header (no header if empty)
+	RepoPath string
+
+	// SuperprojectPath is the top-level directory of the superproject when the repository is a submodule,
+	// shown in the header (empty otherwise)
+	SuperprojectPath string
+
+	// RepositoryState is the operation in progress in the worktree, shown in the header as a warning.
+	// gelete only starts during one with --force-start.
+	RepositoryState git.State
+
+	// CurrentBranch is the checked-out branch, shown in the header (empty when HEAD is detached)
+	CurrentBranch string
+
 	// Branches contains all deletable branches (excludes current branch)
 	Branches []string
 
 	// Selected tracks which branches are selected for deletion (branch name -> bool)
 	Selected map[string]bool
 
-	// CursorIndex is the current cursor position in the branch list
+	// SelectionOrder holds the selected branches in the order they were selected, as shown in the selection
+	// pane (see selectedInOrder); it may lag behind Selected until the next key press
+	SelectionOrder []string
+
+	// SelectionPane tells whether the selected branches are shown in a pane right of the list, toggled with tab
+	SelectionPane PaneMode
+
+	// CursorIndex is the current cursor position in the rows of the selection list (see listRows)
 	CursorIndex int
 
+	// ScrollOffset is the index of the first row rendered in the list viewport
+	ScrollOffset int
+
+	// Width and Height are the terminal dimensions reported by tea.WindowSizeMsg (0 if unknown)
+	Width  int
+	Height int
+
+	// Divergence holds ahead/behind counts of each branch relative to BaseBranch (nil if not computed)
+	Divergence map[string]git.AheadBehind
+
+	// Upstreams maps local branches to the remote branch they track
+	Upstreams map[string]git.Upstream
+
+	// DeleteRemote marks selected branches whose upstream should be deleted along with them
+	DeleteRemote map[string]bool
+
+	// BranchTags maps selected branches to the tags pointing at their tip, loaded when the confirmation opens
+	BranchTags map[string][]BranchTag
+
+	// DeleteTags marks selected branches whose tags should be deleted along with them
+	DeleteTags map[string]bool
+
+	// DeletedTagCount is the number of tags deleted along with their branches
+	DeletedTagCount int
+
+	// TagFailures maps tags to the error of deleting them.
+	// A failed tag deletion doesn't undo or fail the branch deletion.
+	TagFailures map[string]string
+
+	// ConfirmCursor is the index of the highlighted branch in the confirmation list
+	ConfirmCursor int
+
+	// RemoteDeletedCount is the number of upstreams deleted along with local branches
+	RemoteDeletedCount int
+
+	// RemoteFailures maps local branches to the error of deleting their upstream.
+	// A failed upstream deletion doesn't undo or fail the local deletion.
+	RemoteFailures map[string]string
+
+	// PersistSession saves the selection to the session file whenever it changes
+	PersistSession bool
+
+	// DetachedHead is true when no branch is checked out, so no branch is excluded as current
+	DetachedHead bool
+
+	// PreviewOpen shows the preview of a branch instead of the branch list
+	PreviewOpen bool
+
+	// Preview is the branch preview being shown
+	Preview BranchPreview
+
+	// PreviewOffset is the index of the first commit shown in the preview
+	PreviewOffset int
+
+	// CompareAnchor is the branch marked to be compared with the next branch the compare key is pressed on
+	CompareAnchor string
+
+	// CompareOpen shows the comparison of two branches instead of the branch list
+	CompareOpen bool
+
+	// Comparison is the comparison being shown
+	Comparison BranchComparison
+
+	// SortMode is the current order of Branches
+	SortMode SortMode
+
+	// DateFormat is how the commit dates of the branches are shown: relative or absolute
+	DateFormat DateFormat
+
+	// Clock returns the time relative dates are computed from (time.Now if nil)
+	Clock func() time.Time
+
+	// FilterQuery narrows the visible branches to those containing it (case-insensitive)
+	FilterQuery string
+
+	// MergeFilter narrows the visible branches by merge status against BaseBranch
+	MergeFilter MergeFilter
+
+	// OnlyMine narrows the visible branches to those whose last commit has UserEmail in MineField,
+	// toggled with the o key; UserEmail is empty if user.email isn't set, which disables the toggle
+	OnlyMine  bool
+	MineField MineField
+	UserEmail string
+
+	// Grouped shows the branches in sections by their first path segment, toggled with the g key
+	Grouped bool
+
+	// CollapsedGroups marks the groups whose branches are hidden in the grouped view
+	CollapsedGroups map[string]bool
+
+	// Filtering indicates the user is typing a filter query
+	Filtering bool
+
+	// Renaming is the branch being renamed with the e key; empty when no name is being typed
+	Renaming string
+
+	// RenameInput holds the new name typed for the branch being renamed
+	RenameInput textinput.Model
+
+	// SelectingPattern indicates the user is typing a glob pattern opened with the * key, which selects the
+	// visible branches matching it (or deselects them when prefixed with "!")
+	SelectingPattern bool
+
+	// PatternInput holds the selection pattern being typed
+	PatternInput textinput.Model
+
+	// TypingConfirmation indicates the user is typing the confirmation of a deletion prompt that takes more
+	// than a key press (see needsTypedConfirmation)
+	TypingConfirmation bool
+
+	// ConfirmInput holds the confirmation being typed
+	ConfirmInput textinput.Model
+
+	// ConfirmBackup remembers that a force deletion was confirmed with the Backup key while it is typed
+	ConfirmBackup bool
+
 	// State represents the current application state
 	State AppState
 
+	// Loader gathers the branches in the background while the model is in StateLoading (see NewLoadingModel),
+	// and again when the list is refreshed with the Refresh key (nil disables refreshing)
+	Loader BranchLoader
+
+	// LoadErr is the error that prevented loading the branches; the program quits once it is set
+	LoadErr error
+
+	// Refreshing is true while the branches are listed again with the Loader after pressing the Refresh key (r)
+	Refreshing bool
+
 	// ErrorMsg holds any error message to display
 	ErrorMsg string
 
 	// SuccessMsg holds any success message to display
 	SuccessMsg string
 
+	// Clipboard receives the branch names copied with the Copy key (nil disables copying)
+	Clipboard Clipboard
+
+	// Notifier tells the user that a deletion run taking longer than a few seconds ended (nil notifies nothing)
+	Notifier Notifier
+
+	// Keys are the keys of the branch list and the deletion prompts (nil uses DefaultKeyMap)
+	Keys *KeyMap
+
+	// AuditLog appends every deletion of the run to the audit log (see the audit package)
+	AuditLog bool
+
+	// AuditError holds the first failure to write the audit log; deletions go on regardless
+	AuditError string
+
+	// CheckedOut is the branch switched to with the c key; the program quits once it is set
+	CheckedOut string
+
 	// DeletedCount tracks how many branches were successfully deleted
 	DeletedCount int
 
-	// FailedBranches tracks branches that failed to delete with error messages
-	FailedBranches map[string]string
+	// Results holds the outcome of each processed branch of the deletion run, in list order
+	Results []DeletionResult
+
+	// ResultLog holds the outcomes of the deletion run in the order they came in,
+	// shown as a live log while the run is in progress
+	ResultLog []DeletionResult
 
 	// UnmergedBranches tracks branches that failed due to unmerged changes
 	// and are candidates for force deletion
 	UnmergedBranches map[string]string
 
+	// TipReachability records whether the commits of each unmerged branch are reachable from another ref
+	TipReachability map[string]TipReachability
+
+	// ReviewCursor is the index of the unmerged branch being decided on in StateForceReview,
+	// among the unmerged branches in list order
+	ReviewCursor int
+
+	// ForceDecisions holds the decisions made in StateForceReview: true to force delete a branch, false to skip it.
+	// Nothing is deleted until every unmerged branch is decided.
+	ForceDecisions map[string]bool
+
+	// ReviewCommits holds the commits only each unmerged branch has, loaded as StateForceReview reaches it
+	ReviewCommits map[string]BranchPreview
+
+	// Analysis holds the pre-flight analysis of the selected branches, keyed by branch name.
+	// Nil while the analysis runs; branches missing from it are of unknown status.
+	Analysis map[string]git.BranchAnalysis
+
+	// AnalysisErr holds the error that prevented analyzing the selected branches
+	AnalysisErr string
+
+	// SkipRefCheck skips looking for tags and notes on the own commits of unmerged branches in the analysis,
+	// which takes a few git commands per branch in huge repositories
+	SkipRefCheck bool
+
+	// RestoreCursor is the highlighted branch in the restore list
+	RestoreCursor int
+
+	// RestoreSelected tracks the deleted branches picked for restoring
+	RestoreSelected map[string]bool
+
+	// Restored tracks the deleted branches that were recreated at their old tip
+	Restored map[string]bool
+
+	// RestoreFailures holds the error of each branch that couldn't be restored
+	RestoreFailures map[string]string
+
 	// BranchWorktrees maps branch names to their worktree paths (if they have worktrees)
 	BranchWorktrees map[string]string
+
+	// WorktreeSizes holds the measured disk usage of worktree directories, by path
+	WorktreeSizes map[string]WorktreeSize
+
+	// WorktreeChanges holds the number of modified, staged and untracked files of the checked worktree
+	// directories, by path (0 when clean; see checkWorktrees)
+	WorktreeChanges map[string]int
+
+	// DiscardChanges is set once the user confirmed removing worktrees with uncommitted changes,
+	// which are then removed with git worktree remove --force
+	DiscardChanges bool
+
+	// FreedSpace is the disk space freed by the worktrees removed in the deletion run
+	FreedSpace WorktreeSize
+
+	// RemovedWorktreeCount is the number of worktrees removed in the deletion run
+	RemovedWorktreeCount int
+
+	// ExpireReflog expires the reflog and runs git gc once the deletions complete (--expire-reflog),
+	// so the commits of deleted branches are removed from disk and can't be recovered
+	ExpireReflog bool
+
+	// Compacting is true while the reflog is expired and git gc runs, after the deletions
+	Compacting bool
+
+	// Compaction is the outcome of expiring the reflog and running git gc (nil if not run or failed)
+	Compaction *git.CompactionReport
+
+	// CompactionErr holds the error that stopped expiring the reflog or running git gc
+	CompactionErr string
+
+	// StaleWorktrees marks branches whose worktree directory no longer exists.
+	// Deleting such a branch prunes the stale worktree and retries.
+	StaleWorktrees map[string]bool
+
+	// LockedWorktrees maps branch names to locked worktree paths awaiting force removal
+	LockedWorktrees map[string]string
+
+	// CheckedOutWorktrees maps the branches git refused to delete, since they are checked out in a linked
+	// worktree not known when the list was loaded, to that worktree, awaiting its removal and a retry
+	CheckedOutWorktrees map[string]string
+
+	// BranchDetails maps branch names to their last commit metadata
+	BranchDetails map[string]git.BranchInfo
+
+	// ShowAuthors adds a column to the list of local branches with who started each branch
+	// (see git.BranchInfo.Creator), looked up in the background for the branches scrolled into view
+	ShowAuthors bool
+
+	// OriginsLoaded tracks the branches whose Creator and CreatedAt were looked up for the author column:
+	// false while the lookup runs, true once BranchDetails holds them
+	OriginsLoaded map[string]bool
+
+	// Remote indicates Branches are remote-tracking branches (e.g. "origin/feature-x")
+	// that are deleted from the remote server
+	Remote bool
+
+	// BaseBranch is the branch merge status is computed against (empty if unknown)
+	BaseBranch string
+
+	// DefaultBranch is the repository's default branch (empty if unknown). It is protected unless
+	// --allow-default or --no-protect is given, and the confirmation warns when it is selected.
+	DefaultBranch string
+
+	// MergedBranches tracks which branches are fully merged into BaseBranch.
+	// A nil map means merge status is unknown and no annotations are shown.
+	MergedBranches map[string]bool
+
+	// SquashMerged tracks the branches not merged into BaseBranch whose content appears merged anyway,
+	// e.g. with "squash and merge" (see git.IsSquashMerged). They are safe to force delete.
+	SquashMerged map[string]bool
+
+	// WIPPatterns match the subjects of throwaway commits (see git.IsWIPBranch); nil means git.DefaultWIPPatterns
+	WIPPatterns []*regexp.Regexp
+
+	// SelectWIP looks for WIP branches as soon as the branches are loaded and selects them, like the SelectWIP key
+	SelectWIP bool
+
+	// WIPBranches tracks the branches whose own commits are all WIP commits. It stays nil until WIP branches
+	// are looked for with the SelectWIP key, since that takes a git command per branch.
+	WIPBranches map[string]bool
+
+	// DetectingWIP is true while WIP branches are looked for in the background
+	DetectingWIP bool
+
+	// PRProvider looks up the open pull requests of the branches in the background (nil disables the lookup)
+	PRProvider pr.Provider
+
+	// OpenPRs maps branch names on the remote to their open pull request (nil until the lookup finished)
+	OpenPRs map[string]pr.Info
+
+	// Queue holds the branches still to be processed in the current deletion phase
+	Queue []string
+
+	// Current is the branch currently being processed (empty when idle)
+	Current string
+
+	// Progress and ProgressTotal count processed and total branches in the current phase
+	Progress      int
+	ProgressTotal int
+
+	// DeletionStarted is when the first phase of the current deletion run started (zero outside of a run)
+	DeletionStarted time.Time
+
+	// Spinner animates the deleting view
+	Spinner spinner.Model
+
+	// phase is the operation the deletion queue is currently running
+	phase deletionPhase
+
+	// retryingCheckedOut is set while the branches of CheckedOutWorktrees are deleted again after removing
+	// their worktrees; branches found checked out again then fail instead of being asked about twice
+	retryingCheckedOut bool
+
+	// WaitingForLock is the lock file the deletion of the current branch is waiting for before it is retried
+	// (empty if none); lockAttempts counts the attempts that found it (see handleBranchDeleted)
+	WaitingForLock string
+	lockAttempts   int
+
+	// forceBranches are the branches of a force deletion that is backing them up first;
+	// backupTags tags each of them before it is force deleted (see startForceDeletion)
+	forceBranches []string
+	backupTags    bool
+
+	// ctx is passed to the git commands of a deletion run; cancel kills the one in flight when quitting mid-run
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Cancelled indicates the user interrupted the deletion run with ctrl+c
+	Cancelled bool
+
+	// Interrupted is the signal that made the program quit, e.g. SIGINT (nil if none; see InterruptMsg)
+	Interrupted os.Signal
+
+	// CanGoBack offers the Back key in the branch list, which quits with WentBack set, e.g. to return to
+	// the repository picker of --all-repos
+	CanGoBack bool
+
+	// WentBack is set when the program quit with the Back key
+	WentBack bool
+
+	// Styles are used to render the UI (see NewStyles)
+	Styles Styles
+
+	// Lang is the language of the messages (the zero Lang is English)
+	Lang i18n.Lang
+
+	// BackupBundle is the path of a git bundle the branches are written to before they are force deleted
+	// (--backup-bundle); no bundle is written if empty
+	BackupBundle string
+
+	// BackupTags maps the branches force deleted in this run to the backup tags created at their tip,
+	// when the force deletion was confirmed with the Backup key
+	BackupTags map[string]string
+
+	// BundledBranches lists the branches written to BackupBundle in this run
+	BundledBranches []string
+
+	// ForceMode deletes the selected local branches with `git branch -D` right away, so unmerged branches
+	// don't need a second confirmation. Set with --force, or with the f key on the confirmation screen.
+	ForceMode bool
+
+	// ConfirmThreshold is the number of selected branches above which confirming their deletion takes typing
+	// "delete" or their number instead of a key press (0 disables it). Force deletions always take typing.
+	ConfirmThreshold int
+
+	// DryRun previews deletions without executing any git commands that modify the repository
+	DryRun bool
+
+	// DryRunActions records what would have happened to each selected branch in dry-run mode
+	DryRunActions []DryRunAction
+
+	// PlanPath is the plan file of gelete plan. When set, confirming quits with the selection planned
+	// instead of deleting it, and the caller writes the plan.
+	PlanPath string
+
+	// Planned is set once the selection was confirmed in plan mode
+	Planned bool
+}
+
+// TipReachability tells whether force deleting an unmerged branch would leave its commits dangling
+type TipReachability int
+
+const (
+	// TipReachable: another branch, remote-tracking branch or tag contains the branch tip
+	TipReachable TipReachability = iota
+	// TipUnreachable: no other ref contains the branch tip, so its commits become dangling
+	TipUnreachable
+	// TipUnchecked: the check failed or was skipped because it took longer than tipCheckTimeout
+	TipUnchecked
+)
+
+// tipCheckTimeout bounds the reachability check of an unmerged branch, which can be slow in huge repositories
+const tipCheckTimeout = time.Second
+
+// DryRunAction describes the deletion that would be performed for a branch in dry-run mode
+type DryRunAction struct {
+	// Branch is the branch name
+	Branch string
+
+	// Merged indicates the branch could be deleted safely with `git branch -d`
+	Merged bool
+
+	// Force indicates the user confirmed force deletion of this unmerged branch
+	Force bool
+
+	// WorktreePath is the worktree that would be removed first (empty if none)
+	WorktreePath string
+
+	// Remote indicates the branch would be deleted from the remote server
+	Remote bool
+
+	// AlsoRemote is the upstream that would be deleted along with the local branch (empty if none)
+	AlsoRemote string
+
+	// AlsoTags are the tags that would be deleted along with the local branch
+	AlsoTags []string
+}
+
+// VisibleBranches returns the branches matching the current filter query, merge filter and mine filter, in list order
+func (m AppModel) VisibleBranches() []string {
+	if m.FilterQuery == "" && m.MergeFilter == MergeFilterAll && !m.OnlyMine {
+		return m.Branches
+	}
+
+	query := foldCase(m.FilterQuery)
+	var visible []string
+	for _, branch := range m.Branches {
+		if strings.Contains(foldCase(branch), query) && m.matchesMergeFilter(branch) && m.matchesMineFilter(branch) {
+			visible = append(visible, branch)
+		}
+	}
+	return visible
 }
 
-// deletionResultMsg is returned by the deleteBranches command
-type deletionResultMsg AppModel
+// foldCase maps every letter to a canonical case, so strings differing only in case compare equal.
+// Unlike strings.ToLower it also equates letters with more than two cases, like Σ, σ and ς.
+func foldCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		canonical := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			canonical = min(canonical, f)
+		}
+		return canonical
+	}, s)
+}
+
+// selectionChromeLines is the number of lines the selection view uses around the branch list
+// (title, selection status, blank lines, scroll indicator, and help text)
+const selectionChromeLines = 9
 
-// forceDeletionResultMsg is returned by the forceDeleteBranches command
-type forceDeletionResultMsg AppModel
+// listHeight returns how many rows fit in the selection viewport.
+// Returns the number of rows when the terminal height is unknown.
+func (m AppModel) listHeight() int {
+	if m.Height <= 0 {
+		return max(1, len(m.listRows()))
+	}
+
+	chrome := selectionChromeLines + m.headerLines()
+	if m.Filtering || m.FilterQuery != "" {
+		chrome += 2
+	}
+	if m.Renaming != "" || m.SelectingPattern {
+		chrome += 2
+	}
+	return max(1, m.Height-chrome)
+}
 
-// Init initializes the bubbletea model
+// Init initializes the bubbletea model: it starts loading the branches in StateLoading,
+// and looking up open pull requests if a provider is set
 func (m AppModel) Init() tea.Cmd {
-	return nil
+	var cmds []tea.Cmd
+	if m.State == StateLoading {
+		cmds = append(cmds, m.startLoading())
+	}
+	if m.PRProvider != nil {
+		cmds = append(cmds, loadPullRequests(m.PRProvider))
+	}
+	return tea.Batch(cmds...)
 }
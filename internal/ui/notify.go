@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/i18n"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// notifyAfter is how long a deletion run must take before its end is notified; shorter runs end while
+// the user is still watching
+const notifyAfter = 5 * time.Second
+
+// Notifier tells the user that a long deletion run ended, e.g. while they look at another window
+type Notifier interface {
+	Notify(summary string) error
+}
+
+// NoNotifier notifies nothing, for when --notify isn't given or the terminal can't take escape sequences
+type NoNotifier struct{}
+
+// Notify does nothing
+func (NoNotifier) Notify(string) error {
+	return nil
+}
+
+// TerminalNotifier rings the terminal bell and shows a desktop notification with an escape sequence:
+// OSC 9, supported by iTerm2, kitty and WezTerm among others, or OSC 777 for the terminals that only know it
+type TerminalNotifier struct {
+	// Out is the terminal the sequences are written to
+	Out io.Writer
+
+	// OSC777 writes the OSC 777 sequence instead of OSC 9
+	OSC777 bool
+
+	// Tmux wraps the notification so tmux passes it through to the terminal it runs in
+	Tmux bool
+}
+
+// Notify rings the bell and writes the notification sequence with the summary as its text
+func (n TerminalNotifier) Notify(summary string) error {
+	// The sequences end at BEL, so control characters can't be part of the text
+	summary = strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return ' '
+		}
+		return r
+	}, summary)
+
+	seq := ansi.Notify(summary)
+	if n.OSC777 {
+		seq = "\x1b]777;notify;gelete;" + summary + "\a"
+	}
+	if n.Tmux {
+		seq = ansi.TmuxPassthrough(seq)
+	}
+	_, err := io.WriteString(n.Out, "\a"+seq)
+	return err
+}
+
+// NewTerminalNotifier returns the notifier for the terminal out writes to, judging by the TERM environment
+// variable: OSC 777 for urxvt, whose notification extension only knows it, or else OSC 9.
+// getenv looks up environment variables, e.g. os.Getenv.
+func NewTerminalNotifier(out io.Writer, getenv func(string) string) Notifier {
+	return TerminalNotifier{
+		Out:    out,
+		OSC777: strings.HasPrefix(getenv("TERM"), "rxvt"),
+		Tmux:   getenv("TMUX") != "",
+	}
+}
+
+// notifyDone returns the command notifying the end of the deletion run if it took longer than notifyAfter,
+// or nil. Dry runs and runs without a Notifier aren't notified.
+func (m AppModel) notifyDone() tea.Cmd {
+	if m.Notifier == nil || m.DryRun || m.DeletionStarted.IsZero() || m.now().Sub(m.DeletionStarted) < notifyAfter {
+		return nil
+	}
+
+	deleted := m.Lang.Count(m.countResults(ResultDeleted, ResultForceDeleted), i18n.Branch)
+	summary := m.t(i18n.NotifyDeleted, deleted)
+	if failed := m.FailedCount(); failed > 0 {
+		summary = m.t(i18n.NotifyDeletedWithFailures, deleted, failed)
+	}
+
+	notifier := m.Notifier
+	return func() tea.Msg {
+		// The notification is a courtesy: the summary is on screen either way
+		_ = notifier.Notify(summary)
+		return nil
+	}
+}
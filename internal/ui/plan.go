@@ -0,0 +1,18 @@
+package ui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// confirmPlan ends a gelete plan run: the selection is planned rather than deleted, so the program quits
+// with Planned set and the caller writes the plan file
+func (m AppModel) confirmPlan() (tea.Model, tea.Cmd) {
+	m.Planned = true
+	return m, tea.Quit
+}
+
+// PlannedBranches returns the branches of a confirmed plan in list order, or nil if nothing was planned
+func (m AppModel) PlannedBranches() []string {
+	if !m.Planned {
+		return nil
+	}
+	return m.selectedBranches()
+}
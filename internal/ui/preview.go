@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/git"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// previewCommitLimit is the maximum number of commits listed in the branch preview
+const previewCommitLimit = 20
+
+// previewChromeLines is the number of lines the preview uses around the commit list
+// (title, base line, origin line, diffstat, blank lines, scroll indicator, and help text)
+const previewChromeLines = 10
+
+// defaultPreviewWidth is used to truncate commit subjects when the terminal width is unknown
+const defaultPreviewWidth = 80
+
+// BranchPreview holds what a branch contains compared to the base branch
+type BranchPreview struct {
+	// Branch is the previewed branch
+	Branch string
+
+	// Base is the branch the commits are compared against
+	Base string
+
+	// Commits are the commits on Branch that are not on Base, in `git log --oneline` format
+	Commits []string
+
+	// DiffStat summarizes the changes Branch introduces (empty if none)
+	DiffStat string
+
+	// Creator is the author of the first commit on Branch that is not on Base (empty if none)
+	Creator string
+
+	// CreatedAt is when Branch was created according to its reflog (zero if it has none, e.g. remote branches)
+	CreatedAt time.Time
+
+	// Err is set if the preview could not be loaded
+	Err string
+
+	// Loading is true until the preview data arrives
+	Loading bool
+}
+
+// previewLoadedMsg carries the data of a branch preview
+type previewLoadedMsg BranchPreview
+
+// openPreview shows the preview of the branch under the cursor and starts loading it
+func (m AppModel) openPreview() (tea.Model, tea.Cmd) {
+	branch, ok := m.cursorBranch()
+	if !ok {
+		return m, nil
+	}
+
+	base := m.previewBase()
+	m.PreviewOpen = true
+	m.PreviewOffset = 0
+	m.Preview = BranchPreview{Branch: branch, Base: base, Loading: true}
+
+	// Local branches are previewed by their full ref, so a tag of the same name isn't shown instead
+	revision := m.Preview.Branch
+	if !m.Remote {
+		revision = git.BranchRef(revision)
+	}
+	return m, loadPreview(m.Preview.Branch, revision, base, !m.Remote)
+}
+
+// previewBase returns the branch previews compare against: BaseBranch, or HEAD if it is unknown
+func (m AppModel) previewBase() string {
+	if m.BaseBranch == "" {
+		return "HEAD"
+	}
+	return m.BaseBranch
+}
+
+// loadPreview returns a command that gathers the commits, diffstat and origin of a branch, given as a revision.
+// Only local branches have a creation date: the reflog of a remote-tracking branch records fetches.
+func loadPreview(branch, revision, base string, local bool) tea.Cmd {
+	return func() tea.Msg {
+		preview := BranchPreview{Branch: branch, Base: base}
+
+		commits, err := git.GetBranchCommits(revision, base, previewCommitLimit)
+		if err != nil {
+			preview.Err = err.Error()
+			return previewLoadedMsg(preview)
+		}
+		preview.Commits = commits
+
+		// The diffstat is supplementary, so the commits are still shown if it fails
+		preview.DiffStat, _ = git.GetBranchDiffStat(revision, base)
+		preview.Creator, preview.CreatedAt = fetchOrigin(branch, revision, base, local)
+		return previewLoadedMsg(preview)
+	}
+}
+
+// handlePreviewLoaded stores the preview data unless the preview was closed or moved on
+func (m AppModel) handlePreviewLoaded(msg previewLoadedMsg) (tea.Model, tea.Cmd) {
+	if m.PreviewOpen && m.Preview.Branch == msg.Branch {
+		m.Preview = BranchPreview(msg)
+	}
+	return m, nil
+}
+
+// handlePreviewInput handles keyboard input while the branch preview is shown
+func (m AppModel) handlePreviewInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "p", "tab", "esc", "q":
+		m.PreviewOpen = false
+	case "up", "k":
+		m.scrollPreview(-1)
+	case "down", "j":
+		m.scrollPreview(1)
+	}
+	return m, nil
+}
+
+// scrollPreview scrolls the commit list by delta, staying within its bounds
+func (m *AppModel) scrollPreview(delta int) {
+	m.PreviewOffset = max(0, min(m.PreviewOffset+delta, len(m.Preview.Commits)-m.previewHeight()))
+}
+
+// previewHeight returns how many commits fit in the preview
+func (m AppModel) previewHeight() int {
+	if m.Height <= 0 {
+		return max(1, len(m.Preview.Commits))
+	}
+	return max(1, m.Height-previewChromeLines-m.headerLines())
+}
+
+// renderPreview renders the commits and diffstat of the previewed branch
+func (m AppModel) renderPreview() string {
+	var b strings.Builder
+	p := m.Preview
+
+	b.WriteString(m.Styles.Title.Render(fmt.Sprintf("Preview: %s", p.Branch)))
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.UnsetMarginTop().Render(fmt.Sprintf("commits not in %s", p.Base)))
+	b.WriteString("\n")
+	if origin := originLine(p.Creator, p.CreatedAt); origin != "" {
+		b.WriteString(m.Styles.Metadata.Render(origin))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.renderPreviewBody())
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.Render("↑/k: scroll up • ↓/j: scroll down • p/esc: close preview"))
+	return b.String()
+}
+
+// renderPreviewBody renders the commit list, or the loading, error or empty state
+func (m AppModel) renderPreviewBody() string {
+	p := m.Preview
+	switch {
+	case p.Loading:
+		return m.Styles.Help.UnsetMarginTop().Render("Loading...") + "\n"
+	case p.Err != "":
+		return m.Styles.Error.Render(p.Err) + "\n"
+	case len(p.Commits) == 0:
+		return m.Styles.Help.UnsetMarginTop().Render(fmt.Sprintf("Nothing on %s that is not in %s.", p.Branch, p.Base)) + "\n"
+	}
+
+	width := m.Width
+	if width <= 0 {
+		width = defaultPreviewWidth
+	}
+
+	var b strings.Builder
+	start := min(m.PreviewOffset, len(p.Commits))
+	end := min(start+m.previewHeight(), len(p.Commits))
+	for _, commit := range p.Commits[start:end] {
+		// Long subjects are truncated so they never wrap and break the layout
+		b.WriteString(ansi.Truncate("  "+commit, width, "…"))
+		b.WriteString("\n")
+	}
+
+	if end-start < len(p.Commits) {
+		b.WriteString(m.Styles.Help.UnsetMarginTop().Render(fmt.Sprintf("showing %d–%d of %d", start+1, end, len(p.Commits))))
+		b.WriteString("\n")
+	}
+	if p.DiffStat != "" {
+		b.WriteString("\n")
+		b.WriteString(m.Styles.Metadata.Render(ansi.Truncate(p.DiffStat, width, "…")))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
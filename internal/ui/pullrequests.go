@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/pr"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prLookupTimeout bounds the lookup of open pull requests, which needs the network
+const prLookupTimeout = 30 * time.Second
+
+// pullRequestsLoadedMsg carries the open pull requests looked up in the background
+type pullRequestsLoadedMsg struct {
+	prs map[string]pr.Info
+	err error
+}
+
+// loadPullRequests returns a command that looks up the open pull requests with the provider
+func loadPullRequests(provider pr.Provider) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), prLookupTimeout)
+		defer cancel()
+
+		prs, err := provider.OpenPRs(ctx)
+		return pullRequestsLoadedMsg{prs: prs, err: err}
+	}
+}
+
+// handlePullRequestsLoaded stores the open pull requests so the list annotates them.
+// A failed lookup (e.g. gh isn't installed or there is no GitHub remote) leaves the list as it is.
+func (m AppModel) handlePullRequestsLoaded(msg pullRequestsLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.err == nil {
+		m.OpenPRs = msg.prs
+	}
+	return m, nil
+}
+
+// pullRequestOf returns the open pull request of a branch. Pull requests are matched by the branch's name
+// on the remote: the upstream of a local branch, or the name after the remote of a remote branch.
+func (m AppModel) pullRequestOf(branch string) (pr.Info, bool) {
+	name := branch
+	if m.Remote {
+		_, name, _ = strings.Cut(branch, "/")
+	} else if upstream, ok := m.Upstreams[branch]; ok {
+		name = upstream.Branch
+	}
+	info, ok := m.OpenPRs[name]
+	return info, ok
+}
+
+// pullRequestBadge renders the open pull request of a branch in the selection list, e.g. "PR #123 (open)"
+func (m AppModel) pullRequestBadge(branch string) string {
+	info, ok := m.pullRequestOf(branch)
+	if !ok {
+		return ""
+	}
+	return " " + m.Styles.Warning.Render(info.String())
+}
+
+// renderPullRequestWarning warns in the confirmation list that a branch has an open pull request
+func (m AppModel) renderPullRequestWarning(branch string) string {
+	info, ok := m.pullRequestOf(branch)
+	if !ok {
+		return ""
+	}
+	consequence := "it may still be under review"
+	if m.Remote {
+		consequence = "deleting the branch closes it"
+	}
+	return m.Styles.Error.Render(fmt.Sprintf("      ⚠ %s: %s", info, consequence)) + "\n"
+}
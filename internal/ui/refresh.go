@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/i18n"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// branchesRefreshedMsg carries the branches gathered again by the Loader for a refresh
+type branchesRefreshedMsg struct {
+	data BranchData
+	err  error
+}
+
+// refresh lists the branches again with the Loader, e.g. after branches were deleted from another terminal
+func (m AppModel) refresh() (tea.Model, tea.Cmd) {
+	switch {
+	case m.Loader == nil:
+		m.ErrorMsg = "refreshing is not available for this list"
+		return m, nil
+	case m.Refreshing:
+		return m, nil
+	}
+
+	m.Refreshing = true
+	loader := m.Loader
+	return m, func() tea.Msg {
+		data, err := loader()
+		return branchesRefreshedMsg{data: data, err: err}
+	}
+}
+
+// handleBranchesRefreshed replaces the list with the refreshed branches. The selection is kept for the
+// branches that still exist, and the cursor stays on its branch if it still exists.
+func (m AppModel) handleBranchesRefreshed(msg branchesRefreshedMsg) (tea.Model, tea.Cmd) {
+	m.Refreshing = false
+	if m.State != StateSelection {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.ErrorMsg = fmt.Sprintf("failed to refresh branches: %v", msg.err)
+		return m, nil
+	}
+
+	current, onBranch := m.cursorBranch()
+	selected := m.Selected
+	vanished := slices.DeleteFunc(slices.Clone(m.Branches), func(branch string) bool {
+		return slices.Contains(msg.data.Branches, branch)
+	})
+	added := slices.DeleteFunc(slices.Clone(msg.data.Branches), func(branch string) bool {
+		return slices.Contains(m.Branches, branch)
+	})
+
+	msg.data.Selected = make(map[string]bool)
+	for branch := range selected {
+		if selected[branch] && slices.Contains(msg.data.Branches, branch) {
+			msg.data.Selected[branch] = true
+		}
+	}
+	m.applyBranchData(msg.data)
+	// Branches may have new commits, so WIP branches are looked for again the next time they are selected
+	m.WIPBranches = nil
+	m.SetMergeFilter(m.MergeFilter)
+	m.SortBranches()
+	m.recordSelectionOrder()
+	if onBranch {
+		m.moveCursorTo(current)
+	}
+
+	m.SuccessMsg = refreshSummary(len(vanished), len(added))
+	if m.PersistSession {
+		return m, m.saveSession()
+	}
+	return m, nil
+}
+
+// refreshSummary describes what a refresh changed, e.g. "Refreshed: 2 branch(es) gone, 1 new"
+func refreshSummary(vanished, added int) string {
+	var changes []string
+	if vanished > 0 {
+		changes = append(changes, fmt.Sprintf("%d branch(es) gone", vanished))
+	}
+	if added > 0 {
+		changes = append(changes, fmt.Sprintf("%d new", added))
+	}
+	if len(changes) == 0 {
+		return "Refreshed: no changes"
+	}
+	return "Refreshed: " + strings.Join(changes, ", ")
+}
+
+// dropGoneBranches removes the branches found already deleted in the deletion run from the list,
+// once their results are recorded
+func (m *AppModel) dropGoneBranches() {
+	for _, result := range m.Results {
+		if result.Status != ResultAlreadyGone {
+			continue
+		}
+		m.Branches = slices.DeleteFunc(m.Branches, func(branch string) bool { return branch == result.Branch })
+		delete(m.Selected, result.Branch)
+	}
+	m.moveCursor(0)
+}
+
+// alreadyGoneLine tells how many branches had already been deleted outside gelete, or "" if none
+func (m AppModel) alreadyGoneLine() string {
+	switch gone := m.countResults(ResultAlreadyGone); gone {
+	case 0:
+		return ""
+	case 1:
+		return m.t(i18n.AlreadyGoneOne)
+	default:
+		return m.t(i18n.AlreadyGoneOther, gone)
+	}
+}
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// branchRenamedMsg reports the result of renaming a branch
+type branchRenamedMsg struct {
+	from string
+	to   string
+	err  error
+}
+
+// openRename starts renaming the branch under the cursor with an input prefilled with its name.
+// Remote branches can't be renamed.
+func (m AppModel) openRename() (tea.Model, tea.Cmd) {
+	branch, ok := m.cursorBranch()
+	if m.Remote || !ok {
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Prompt = ""
+	input.SetValue(branch)
+	input.CursorEnd()
+	// A static cursor like the filter line's, so no blink ticks have to be routed to the input
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.Focus()
+
+	m.Renaming = branch
+	m.RenameInput = input
+	return m, nil
+}
+
+// handleRenameInput handles keyboard input while a new branch name is being typed.
+// Enter renames the branch in the background, esc cancels.
+func (m AppModel) handleRenameInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.Renaming = ""
+		m.ErrorMsg = ""
+		return m, nil
+
+	case tea.KeyEnter:
+		from, to := m.Renaming, strings.TrimSpace(m.RenameInput.Value())
+		if to == from {
+			m.Renaming = ""
+			return m, nil
+		}
+		repo := m.Repo
+		return m, func() tea.Msg {
+			return branchRenamedMsg{from: from, to: to, err: repo.RenameBranch(context.Background(), from, to)}
+		}
+	}
+
+	// Errors are shown until the name is edited
+	m.ErrorMsg = ""
+	var cmd tea.Cmd
+	m.RenameInput, cmd = m.RenameInput.Update(msg)
+	return m, cmd
+}
+
+// handleBranchRenamed renames the branch in the list and its metadata, or shows git's error
+// and keeps the input open so the name can be corrected
+func (m AppModel) handleBranchRenamed(msg branchRenamedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.ErrorMsg = msg.err.Error()
+		return m, nil
+	}
+
+	m.Renaming = ""
+	m.renameBranch(msg.from, msg.to)
+	if m.PersistSession {
+		return m, m.saveSession()
+	}
+	return m, nil
+}
+
+// renameBranch replaces the branch name in Branches and the maps keyed by branch, and keeps the cursor on it
+func (m *AppModel) renameBranch(from, to string) {
+	if i := slices.Index(m.Branches, from); i >= 0 {
+		m.Branches[i] = to
+	}
+	if i := slices.Index(m.SelectionOrder, from); i >= 0 {
+		m.SelectionOrder[i] = to
+	}
+
+	if info, ok := m.BranchDetails[from]; ok {
+		info.Name = to
+		m.BranchDetails[from] = info
+	}
+	renameKey(m.BranchDetails, from, to)
+	renameKey(m.Selected, from, to)
+	renameKey(m.Divergence, from, to)
+	renameKey(m.Upstreams, from, to)
+	renameKey(m.Analysis, from, to)
+	renameKey(m.MergedBranches, from, to)
+	renameKey(m.SquashMerged, from, to)
+	renameKey(m.BranchWorktrees, from, to)
+	renameKey(m.StaleWorktrees, from, to)
+	renameKey(m.WIPBranches, from, to)
+	// The author column looks the renamed branch up again, as the lookup of its old name may be in flight
+	delete(m.OriginsLoaded, from)
+
+	m.moveCursorTo(to)
+}
+
+// renameKey moves the value stored under from to the key to, if there is one
+func renameKey[V any](values map[string]V, from, to string) {
+	if value, ok := values[from]; ok {
+		delete(values, from)
+		values[to] = value
+	}
+}
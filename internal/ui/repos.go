@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RepoLoader gathers the branch counts of the repositories offered by the RepoPickerModel
+type RepoLoader func() []git.RepositorySummary
+
+// RepoPickerModel is the first level of the TUI of --all-repos: a list of the repositories found in a directory
+// with their branch counts, gathered in the background. Picking one quits with it as Chosen, so its branch
+// list can be opened; the branch list returns here with its back key.
+type RepoPickerModel struct {
+	// Root is the directory the repositories were found in, shown in the title
+	Root string
+
+	// Repos are the repositories with their branch counts, in list order; empty while loading
+	Repos []git.RepositorySummary
+
+	// Loader gathers Repos when the picker starts
+	Loader RepoLoader
+
+	// Loading is true until Repos are gathered
+	Loading bool
+
+	// CursorIndex is the current cursor position in the repository list
+	CursorIndex int
+
+	// Chosen is the directory of the repository picked with enter; empty if the user quit
+	Chosen string
+
+	// Message tells why the repository under the cursor can't be opened, until the next key press
+	Message string
+
+	// Interrupted is the signal that made the program quit, e.g. SIGINT (nil if none; see InterruptMsg)
+	Interrupted os.Signal
+
+	// Spinner animates the loading view
+	Spinner spinner.Model
+
+	// Styles holds the lipgloss styles used for rendering
+	Styles Styles
+}
+
+// reposLoadedMsg carries the repositories gathered by the Loader
+type reposLoadedMsg []git.RepositorySummary
+
+// NewRepoPickerModel returns a picker over the repositories found in root, showing a spinner until the
+// Loader has counted their branches. The cursor starts on the repository at cursor.
+func NewRepoPickerModel(root string, loader RepoLoader, cursor int, styles Styles) RepoPickerModel {
+	return RepoPickerModel{
+		Root:        root,
+		Loader:      loader,
+		Loading:     true,
+		CursorIndex: cursor,
+		Spinner:     spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(styles.Cursor)),
+		Styles:      styles,
+	}
+}
+
+// Init starts gathering the repositories if they aren't loaded yet
+func (m RepoPickerModel) Init() tea.Cmd {
+	if !m.Loading {
+		return nil
+	}
+	loader := m.Loader
+	return tea.Batch(m.Spinner.Tick, func() tea.Msg { return reposLoadedMsg(loader()) })
+}
+
+// Update handles messages and updates the model state
+func (m RepoPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case reposLoadedMsg:
+		m.Repos = msg
+		m.Loading = false
+		m.CursorIndex = max(0, min(m.CursorIndex, len(m.Repos)-1))
+		// A spinner without an ID ignores the pending tick, which stops the animation
+		m.Spinner = spinner.Model{}
+		return m, nil
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.Spinner, cmd = m.Spinner.Update(msg)
+		return m, cmd
+	case InterruptMsg:
+		m.Interrupted = msg.Signal
+		return m, tea.Quit
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+// handleKey handles keyboard input; only quitting is possible while loading
+func (m RepoPickerModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.Message = ""
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	}
+	if m.Loading {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		m.CursorIndex = max(0, m.CursorIndex-1)
+	case "down", "j":
+		m.CursorIndex = min(len(m.Repos)-1, m.CursorIndex+1)
+	case "enter", " ":
+		return m.choose()
+	}
+	return m, nil
+}
+
+// choose quits with the repository under the cursor as Chosen, unless it can't be read or has nothing to delete
+func (m RepoPickerModel) choose() (tea.Model, tea.Cmd) {
+	if len(m.Repos) == 0 {
+		return m, nil
+	}
+
+	repo := m.Repos[m.CursorIndex]
+	switch {
+	case repo.Err != nil:
+		m.Message = fmt.Sprintf("%s can't be opened: %v", filepath.Base(repo.Dir), repo.Err)
+		return m, nil
+	case repo.Branches == 0:
+		m.Message = fmt.Sprintf("No branches to delete in %s.", filepath.Base(repo.Dir))
+		return m, nil
+	}
+	m.Chosen = repo.Dir
+	return m, tea.Quit
+}
+
+// View renders the repository list, or the spinner while loading
+func (m RepoPickerModel) View() string {
+	var b strings.Builder
+	b.WriteString(m.Styles.Title.Render("gelete - Repositories in " + m.Root))
+	b.WriteString("\n")
+
+	if m.Loading {
+		b.WriteString(fmt.Sprintf("\n%s Counting branches…\n", m.Spinner.View()))
+		return b.String()
+	}
+
+	b.WriteString(m.Styles.Help.UnsetMarginTop().Render(fmt.Sprintf("%d repositories", len(m.Repos))))
+	b.WriteString("\n\n")
+	if len(m.Repos) == 0 {
+		b.WriteString(m.Styles.Help.Render("No git repositories found."))
+		b.WriteString("\n\n")
+		b.WriteString(m.Styles.Help.Render("Press q to quit."))
+		return b.String()
+	}
+
+	nameWidth := 0
+	for _, repo := range m.Repos {
+		nameWidth = max(nameWidth, lipgloss.Width(filepath.Base(repo.Dir)))
+	}
+	for i, repo := range m.Repos {
+		b.WriteString(m.renderRepoRow(repo, i == m.CursorIndex, nameWidth))
+		b.WriteString("\n")
+	}
+
+	if m.Message != "" {
+		b.WriteString("\n")
+		b.WriteString(m.Styles.Warning.Render(m.Message))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.Render("↑/k: up • ↓/j: down • enter: open • q: quit"))
+	return b.String()
+}
+
+// renderRepoRow renders a repository with its branch counts, or the error that prevented reading it
+func (m RepoPickerModel) renderRepoRow(repo git.RepositorySummary, isCursor bool, nameWidth int) string {
+	cursor := "  "
+	if isCursor {
+		cursor = m.Styles.Cursor.Render("> ")
+	}
+
+	name := filepath.Base(repo.Dir)
+	row := cursor + name + strings.Repeat(" ", nameWidth-lipgloss.Width(name)) + "  "
+	switch {
+	case repo.Err != nil:
+		return row + m.Styles.Error.Render("✗ "+firstLine(repo.Err.Error()))
+	case repo.Branches == 0:
+		return row + m.Styles.Metadata.Render("no branches to delete")
+	}
+
+	counts := fmt.Sprintf("%d branch(es)", repo.Branches)
+	if repo.Gone > 0 {
+		counts += fmt.Sprintf(" • %d gone", repo.Gone)
+	}
+	return row + m.Styles.Metadata.Render(counts)
+}
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// branchesRestoredMsg carries the outcome of restoring deleted branches
+type branchesRestoredMsg struct {
+	restored []string
+	failures map[string]string
+}
+
+// restorableResults returns the branches deleted in this run that can still be restored, in list order.
+// Remote branches and branches whose tip wasn't captured can't be restored.
+func (m AppModel) restorableResults() []DeletionResult {
+	if m.Remote || m.DryRun || m.compacted() {
+		return nil
+	}
+
+	var restorable []DeletionResult
+	for _, result := range m.Results {
+		deleted := result.Status == ResultDeleted || result.Status == ResultForceDeleted
+		if deleted && result.SHA != "" && !m.Restored[result.Branch] {
+			restorable = append(restorable, result)
+		}
+	}
+	return restorable
+}
+
+// handleDoneInput handles keyboard input on the done screen: u opens the restore list, g compacts the
+// repository when offered, any other key exits
+func (m AppModel) handleDoneInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case m.Compacting:
+		// Only ctrl+c, which kills git gc and quits
+		return m, nil
+	case msg.String() == "g" && m.offersCompaction():
+		return m.startCompaction()
+	}
+	if msg.String() != "u" || len(m.restorableResults()) == 0 {
+		return m, tea.Quit
+	}
+
+	m.State = StateRestore
+	m.RestoreCursor = 0
+	m.RestoreSelected = make(map[string]bool)
+	return m, nil
+}
+
+// handleRestoreInput handles keyboard input in the restore list
+func (m AppModel) handleRestoreInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	restorable := m.restorableResults()
+
+	switch msg.String() {
+	case "up", "k":
+		m.RestoreCursor = max(0, m.RestoreCursor-1)
+
+	case "down", "j":
+		m.RestoreCursor = min(len(restorable)-1, m.RestoreCursor+1)
+
+	case " ":
+		branch := restorable[m.RestoreCursor].Branch
+		m.RestoreSelected[branch] = !m.RestoreSelected[branch]
+
+	case "enter", "r":
+		var selected []DeletionResult
+		for _, result := range restorable {
+			if m.RestoreSelected[result.Branch] {
+				selected = append(selected, result)
+			}
+		}
+		if len(selected) > 0 {
+			return m, restoreBranches(selected)
+		}
+
+	case "esc", "q":
+		m.State = StateDone
+	}
+
+	return m, nil
+}
+
+// restoreBranches returns a command that recreates the given deleted branches at their old tips
+func restoreBranches(results []DeletionResult) tea.Cmd {
+	return func() tea.Msg {
+		msg := branchesRestoredMsg{failures: make(map[string]string)}
+		for _, result := range results {
+			if err := git.RestoreBranch(result.Branch, result.SHA); err != nil {
+				msg.failures[result.Branch] = err.Error()
+				continue
+			}
+			msg.restored = append(msg.restored, result.Branch)
+		}
+		return msg
+	}
+}
+
+// handleBranchesRestored records the restored branches and returns to the done screen
+func (m AppModel) handleBranchesRestored(msg branchesRestoredMsg) (tea.Model, tea.Cmd) {
+	if m.Restored == nil {
+		m.Restored = make(map[string]bool)
+	}
+	m.RestoreFailures = msg.failures
+	for _, branch := range msg.restored {
+		m.Restored[branch] = true
+	}
+	m.State = StateDone
+	return m, nil
+}
+
+func (m AppModel) renderRestore() string {
+	var b strings.Builder
+
+	b.WriteString(m.Styles.Title.Render(m.t(i18n.RestoreTitle)))
+	b.WriteString("\n\n")
+
+	for i, result := range m.restorableResults() {
+		cursor := "  "
+		if i == m.RestoreCursor {
+			cursor = m.Styles.Cursor.Render("> ")
+		}
+
+		checkbox := "[ ]"
+		style := m.Styles.UnselectedItem
+		if m.RestoreSelected[result.Branch] {
+			checkbox = "[✓]"
+			style = m.Styles.SelectedItem
+		}
+
+		b.WriteString(fmt.Sprintf("%s%s %s  %s", cursor, checkbox, style.Render(result.Branch), m.Styles.Metadata.Render(result.SHA)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.Render(m.t(i18n.RestoreHelp)))
+	return b.String()
+}
+
+// renderRestoreResults renders how many deleted branches were restored and which couldn't be
+func (m AppModel) renderRestoreResults() string {
+	var b strings.Builder
+	if len(m.Restored) > 0 {
+		b.WriteString(m.Styles.Success.Render(m.t(i18n.RestoredCount, m.Lang.Count(len(m.Restored), i18n.Branch))))
+		b.WriteString("\n")
+	}
+
+	if len(m.RestoreFailures) > 0 {
+		b.WriteString("\n")
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.RestoreFailed, m.Lang.Count(len(m.RestoreFailures), i18n.Branch))))
+		b.WriteString("\n")
+		for _, branch := range m.orderedBranches(m.RestoreFailures) {
+			b.WriteString(m.Styles.Error.Render(fmt.Sprintf("  • %s: %s", branch, firstLine(m.RestoreFailures[branch]))))
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// doneHelp returns the key help of the done screen, offering undo while deleted branches can be restored
+func (m AppModel) doneHelp() string {
+	switch {
+	case m.Compacting:
+		return m.t(i18n.DoneHelpCompacting)
+	case m.offersCompaction():
+		return m.t(i18n.DoneHelpCompaction)
+	case len(m.restorableResults()) > 0:
+		return m.t(i18n.DoneHelpUndo)
+	}
+	return m.t(i18n.PressAnyKey)
+}
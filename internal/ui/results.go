@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/i18n"
+)
+
+// ResultStatus is the outcome of processing one selected branch in a deletion run
+type ResultStatus int
+
+const (
+	// ResultDeleted: the branch was deleted (git branch -d, or from the remote in remote mode)
+	ResultDeleted ResultStatus = iota
+	// ResultForceDeleted: the unmerged branch was deleted after confirmation (git branch -D)
+	ResultForceDeleted
+	// ResultSkipped: the branch was intentionally not deleted (e.g. force deletion declined)
+	ResultSkipped
+	// ResultFailed: deleting the branch, or removing its worktree, failed
+	ResultFailed
+	// ResultAlreadyGone: the branch no longer existed when its turn came, e.g. it was deleted from another
+	// terminal while the list was open. It doesn't count as a failure.
+	ResultAlreadyGone
+)
+
+// DeletionResult is the outcome of one branch of a deletion run
+type DeletionResult struct {
+	// Branch is the branch name
+	Branch string
+
+	// Status is what happened to the branch
+	Status ResultStatus
+
+	// SHA is the branch tip before deletion, for restoring it (empty if unknown or not deleted)
+	SHA string
+
+	// Reason explains why the branch was skipped, or holds the error of a failed branch
+	Reason string
+
+	// CheckedOutIn is the worktree git refused to delete the failed or skipped branch for, since the branch is
+	// checked out there, e.g. the main worktree when gelete runs in a linked one (empty for other outcomes)
+	CheckedOutIn string
+
+	// LockFile is the lock file that still existed after the failed deletion was retried
+	// (empty for other failures)
+	LockFile string
+}
+
+// recordResult stores the outcome of a branch, replacing any earlier outcome of the same branch.
+// Results are kept in list order so the summary follows the order branches were selected in.
+func (m *AppModel) recordResult(result DeletionResult) {
+	m.ResultLog = append(m.ResultLog, result)
+	if i := slices.IndexFunc(m.Results, func(r DeletionResult) bool { return r.Branch == result.Branch }); i >= 0 {
+		m.Results[i] = result
+		return
+	}
+
+	position := slices.Index(m.Branches, result.Branch)
+	i := slices.IndexFunc(m.Results, func(r DeletionResult) bool {
+		return slices.Index(m.Branches, r.Branch) > position
+	})
+	if i < 0 {
+		i = len(m.Results)
+	}
+	m.Results = slices.Insert(m.Results, i, result)
+}
+
+// Result returns the outcome of a branch in the current deletion run
+func (m AppModel) Result(branch string) (DeletionResult, bool) {
+	i := slices.IndexFunc(m.Results, func(r DeletionResult) bool { return r.Branch == branch })
+	if i < 0 {
+		return DeletionResult{}, false
+	}
+	return m.Results[i], true
+}
+
+// HasFailures reports whether any branch of the deletion run failed
+func (m AppModel) HasFailures() bool {
+	return m.FailedCount() > 0
+}
+
+// WasCancelled reports whether the user cancelled with branches still to delete: by quitting the selection
+// or the prompts before the deletion run while branches were selected, or by cancelling the deletion run
+func (m AppModel) WasCancelled() bool {
+	beforeRun := m.State == StateSelection || m.State == StateConfirmation || m.State == StateWorktreeConfirmation
+	return m.Cancelled || (beforeRun && m.CheckedOut == "" && !m.Planned && m.hasSelectedBranches())
+}
+
+// FailedCount returns how many branches of the deletion run failed
+func (m AppModel) FailedCount() int {
+	return m.countResults(ResultFailed)
+}
+
+// countResults returns how many branches of the deletion run ended with one of the given statuses
+func (m AppModel) countResults(statuses ...ResultStatus) int {
+	count := 0
+	for _, result := range m.Results {
+		if slices.Contains(statuses, result.Status) {
+			count++
+		}
+	}
+	return count
+}
+
+// renderResult renders the line describing the outcome of one branch
+func (m AppModel) renderResult(result DeletionResult) string {
+	if m.Restored[result.Branch] {
+		return m.Styles.Success.Render(m.t(i18n.ResultRestored, result.Branch, result.SHA))
+	}
+
+	switch result.Status {
+	case ResultDeleted:
+		return m.Styles.Success.Render(m.t(i18n.ResultDeleted, result.Branch, m.restoreHint(result)))
+	case ResultForceDeleted:
+		return m.Styles.Success.Render(m.t(i18n.ResultForceDeleted, result.Branch, m.restoreHint(result)))
+	case ResultSkipped:
+		return m.Styles.Warning.Render(m.t(i18n.ResultSkipped, result.Branch, result.Reason))
+	case ResultAlreadyGone:
+		return m.Styles.Metadata.Render(m.t(i18n.ResultAlreadyGone, result.Branch))
+	}
+	if result.CheckedOutIn != "" {
+		return m.Styles.Error.Render(m.t(i18n.ResultCheckedOut, result.Branch, result.CheckedOutIn))
+	}
+	if result.LockFile != "" {
+		return m.Styles.Error.Render(m.t(i18n.ResultLocked, result.Branch, result.LockFile))
+	}
+	return m.Styles.Error.Render(m.t(i18n.ResultFailed, result.Branch, firstLine(result.Reason)))
+}
+
+// restoreHint returns the suffix telling how to restore a deleted branch, or "" if its tip is unknown
+// or its commits were pruned by git gc
+func (m AppModel) restoreHint(result DeletionResult) string {
+	if result.SHA == "" || m.compacted() {
+		return ""
+	}
+	return m.t(i18n.RestoreHint, result.SHA, result.Branch)
+}
+
+// firstLine returns the first line of a possibly multi-line git error
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return line
+}
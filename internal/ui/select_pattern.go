@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openPatternSelection starts typing a glob pattern selecting the visible branches matching it, with the * key
+func (m AppModel) openPatternSelection() (tea.Model, tea.Cmd) {
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "tmp/*"
+	// A static cursor like the filter line's, so no blink ticks have to be routed to the input
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.Focus()
+
+	m.SelectingPattern = true
+	m.PatternInput = input
+	return m, nil
+}
+
+// handlePatternInput handles keyboard input while a selection pattern is being typed.
+// Enter applies the pattern, esc cancels without changing the selection.
+func (m AppModel) handlePatternInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.SelectingPattern = false
+		m.ErrorMsg = ""
+		return m, nil
+
+	case tea.KeyEnter:
+		return m.applyPatternSelection()
+	}
+
+	// Errors are shown until the pattern is edited
+	m.ErrorMsg = ""
+	var cmd tea.Cmd
+	m.PatternInput, cmd = m.PatternInput.Update(msg)
+	return m, cmd
+}
+
+// applyPatternSelection selects the visible branches matching the typed pattern, or deselects them if the
+// pattern starts with "!". Patterns match like --pattern (see git.MatchesPattern). An invalid pattern is
+// shown as an error and the input stays open so it can be corrected.
+func (m AppModel) applyPatternSelection() (tea.Model, tea.Cmd) {
+	typed := strings.TrimSpace(m.PatternInput.Value())
+	pattern, deselect := strings.CutPrefix(typed, "!")
+	if err := git.ValidatePattern(pattern); err != nil {
+		m.ErrorMsg = err.Error()
+		return m, nil
+	}
+
+	matched := 0
+	for _, branch := range m.VisibleBranches() {
+		if git.MatchesPattern(branch, pattern) {
+			m.Selected[branch] = !deselect
+			matched++
+		}
+	}
+
+	m.recordSelectionOrder()
+	m.SelectingPattern = false
+	action := "selected"
+	if deselect {
+		action = "deselected"
+	}
+	noun := "branches"
+	if matched == 1 {
+		noun = "branch"
+	}
+	m.SuccessMsg = fmt.Sprintf("%s %d %s matching %s", action, matched, noun, pattern)
+	if m.PersistSession && matched > 0 {
+		return m, m.saveSession()
+	}
+	return m, nil
+}
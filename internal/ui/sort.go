@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Kdaito/gelete/internal/i18n"
+)
+
+// SortMode controls the order of the branch list
+type SortMode int
+
+const (
+	// SortByName sorts branches alphabetically
+	SortByName SortMode = iota
+	// SortOldestFirst sorts branches by last commit date, least recently active first
+	SortOldestFirst
+	// SortNewestFirst sorts branches by last commit date, most recently active first
+	SortNewestFirst
+)
+
+// ParseSortMode parses the value of the --sort flag ("name", "age" or "-age")
+func ParseSortMode(value string) (SortMode, error) {
+	switch value {
+	case "name":
+		return SortByName, nil
+	case "age":
+		return SortOldestFirst, nil
+	case "-age":
+		return SortNewestFirst, nil
+	}
+	return SortByName, fmt.Errorf("invalid sort order '%s' (expected name, age or -age)", value)
+}
+
+// String returns the --sort flag value of the sort mode
+func (s SortMode) String() string {
+	switch s {
+	case SortOldestFirst:
+		return "age"
+	case SortNewestFirst:
+		return "-age"
+	}
+	return "name"
+}
+
+// message returns the description of the sort mode shown in the status line
+func (s SortMode) message() i18n.MessageID {
+	switch s {
+	case SortOldestFirst:
+		return i18n.SortOldestFirst
+	case SortNewestFirst:
+		return i18n.SortNewestFirst
+	}
+	return i18n.SortByName
+}
+
+// next returns the sort mode that follows s when cycling with the s key
+func (s SortMode) next() SortMode {
+	return (s + 1) % 3
+}
+
+// SortBranches orders Branches according to SortMode.
+// The cursor follows the branch it was on rather than staying at the same index.
+func (m *AppModel) SortBranches() {
+	current, onBranch := m.cursorBranch()
+
+	sort.SliceStable(m.Branches, func(i, j int) bool {
+		return m.branchLess(m.Branches[i], m.Branches[j])
+	})
+
+	// Group headers don't move, since groups are always in alphabetical order
+	if onBranch {
+		m.moveCursorTo(current)
+	}
+	m.moveCursor(0)
+}
+
+// branchLess reports whether branch a sorts before branch b in the current sort mode.
+// Branches with equal (or unknown) commit dates fall back to alphabetical order.
+func (m AppModel) branchLess(a, b string) bool {
+	dateA := m.BranchDetails[a].LastCommitDate
+	dateB := m.BranchDetails[b].LastCommitDate
+
+	switch {
+	case m.SortMode == SortOldestFirst && !dateA.Equal(dateB):
+		return dateA.Before(dateB)
+	case m.SortMode == SortNewestFirst && !dateA.Equal(dateB):
+		return dateA.After(dateB)
+	}
+	return a < b
+}
+
+// cycleSort switches to the next sort mode and re-sorts the branch list.
+// Sorting is unavailable without commit metadata (e.g. for remote branches).
+func (m *AppModel) cycleSort() {
+	if len(m.BranchDetails) == 0 {
+		return
+	}
+	m.SortMode = m.SortMode.next()
+	m.SortBranches()
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// PaneMode tells whether the selection list is split in two panes, with the selected branches on the right
+type PaneMode int
+
+const (
+	// PaneAuto: shown when the terminal is at least splitAutoWidth columns wide
+	PaneAuto PaneMode = iota
+	// PaneShown: shown when the terminal is at least splitMinWidth columns wide
+	PaneShown
+	// PaneHidden: never shown
+	PaneHidden
+)
+
+const (
+	// splitAutoWidth is the terminal width from which the selection pane is shown without pressing tab
+	splitAutoWidth = 140
+
+	// splitMinWidth is the terminal width below which the layout collapses to a single column
+	splitMinWidth = 100
+
+	// selectionPaneMaxWidth caps the width of the selection pane, borders included
+	selectionPaneMaxWidth = 56
+
+	// splitGap is the number of columns between the branch list and the selection pane
+	splitGap = 2
+)
+
+// splitActive reports whether the selection list is rendered in two panes
+func (m AppModel) splitActive() bool {
+	switch {
+	case m.Width < splitMinWidth, m.SelectionPane == PaneHidden:
+		return false
+	case m.SelectionPane == PaneShown:
+		return true
+	}
+	return m.Width >= splitAutoWidth
+}
+
+// toggleSelectionPane shows or hides the selection pane; narrow terminals keep a single column
+func (m *AppModel) toggleSelectionPane() {
+	if m.splitActive() {
+		m.SelectionPane = PaneHidden
+		return
+	}
+	if m.Width < splitMinWidth {
+		m.ErrorMsg = fmt.Sprintf("the terminal is too narrow for the selection pane (at least %d columns needed)", splitMinWidth)
+		return
+	}
+	m.SelectionPane = PaneShown
+}
+
+// handleSelectionPaneKey toggles the selection pane on SelectionPane (tab) and reports whether the key was handled
+func (m *AppModel) handleSelectionPaneKey(msg tea.KeyMsg) bool {
+	if !key.Matches(msg, m.keyMap().SelectionPane) {
+		return false
+	}
+	m.toggleSelectionPane()
+	return true
+}
+
+// selectionPaneWidth returns the width of the selection pane, borders included: a third of the terminal,
+// up to selectionPaneMaxWidth
+func (m AppModel) selectionPaneWidth() int {
+	return min(m.Width/3, selectionPaneMaxWidth)
+}
+
+// selectedInOrder returns the selected branches in the order they were selected.
+// Branches selected together, e.g. with select all, and branches selected before the list was shown
+// are in list order.
+func (m AppModel) selectedInOrder() []string {
+	ordered := slices.DeleteFunc(slices.Clone(m.SelectionOrder), func(branch string) bool { return !m.Selected[branch] })
+	for _, branch := range m.Branches {
+		if m.Selected[branch] && !slices.Contains(ordered, branch) {
+			ordered = append(ordered, branch)
+		}
+	}
+	return ordered
+}
+
+// recordSelectionOrder updates SelectionOrder after the selection changed
+func (m *AppModel) recordSelectionOrder() {
+	m.SelectionOrder = m.selectedInOrder()
+}
+
+// renderSplit renders the branch list on the left and the selection pane on the right, truncating the list
+// to the columns left by the pane
+func (m AppModel) renderSplit(list string) string {
+	paneWidth := m.selectionPaneWidth()
+	listWidth := m.Width - paneWidth - splitGap
+
+	lines := strings.Split(strings.TrimSuffix(list, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, listWidth, "…")
+	}
+	left := lipgloss.NewStyle().Width(listWidth + splitGap).Render(strings.Join(lines, "\n"))
+	// The pane may be taller than a short list, up to the height the list could take
+	height := max(len(lines), m.listHeight())
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, m.renderSelectionPane(paneWidth, height)) + "\n"
+}
+
+// renderSelectionPane renders the selected branches in selection order with their merge and worktree badges,
+// under a running count. Branches that don't fit in height lines are summed up on the last line.
+func (m AppModel) renderSelectionPane(width, height int) string {
+	selected := m.selectedInOrder()
+	// The border takes a column on the left and the padding another
+	contentWidth := width - 2
+
+	lines := []string{m.Styles.Confirmation.UnsetMarginTop().Render(fmt.Sprintf("Selected (%d)", len(selected)))}
+	if len(selected) == 0 {
+		lines = append(lines, m.Styles.Help.UnsetMarginTop().Render("nothing yet"))
+	}
+
+	rows := max(1, height-1)
+	shown := selected
+	if len(selected) > rows {
+		shown = selected[:rows-1]
+	}
+	for _, branch := range shown {
+		lines = append(lines, ansi.Truncate(m.selectionPaneRow(branch), contentWidth, "…"))
+	}
+	if hidden := len(selected) - len(shown); hidden > 0 {
+		lines = append(lines, m.Styles.Help.UnsetMarginTop().Render(fmt.Sprintf("… and %d more", hidden)))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(m.Styles.Metadata.GetForeground()).
+		PaddingLeft(1).
+		Width(width - 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+// selectionPaneRow renders a selected branch in the selection pane: its name, then its badges
+func (m AppModel) selectionPaneRow(branch string) string {
+	row := m.Styles.SelectedItem.Render(branch)
+	if status, style := m.mergeStatus(branch); status != "" {
+		row += " " + style.Render(status)
+	}
+	if tag := m.worktreeTag(branch); tag != "" {
+		row += " " + m.Styles.Warning.Render(tag)
+	}
+	return row
+}
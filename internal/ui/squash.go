@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/i18n"
+)
+
+// unmergedCount returns how many of the branches awaiting force deletion are unmerged and not squash-merged
+func (m AppModel) unmergedCount() int {
+	count := 0
+	for branch := range m.UnmergedBranches {
+		if !m.SquashMerged[branch] {
+			count++
+		}
+	}
+	return count
+}
+
+// renderUnmergedConfirmation renders the force deletion warning when some branches have changes that would be lost
+func (m AppModel) renderUnmergedConfirmation() string {
+	var b strings.Builder
+
+	b.WriteString(m.Styles.Error.Render(m.t(i18n.UnmergedTitle)))
+	b.WriteString("\n\n")
+	b.WriteString(m.t(i18n.UnmergedIntro) + "\n\n")
+
+	for _, branch := range m.orderedBranches(m.UnmergedBranches) {
+		b.WriteString(m.renderForceConfirmationBranch(branch))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Warning.Render(m.t(i18n.UnmergedRemoval, m.Lang.Count(m.unmergedCount(), i18n.UnmergedBranch))))
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Error.Render(m.t(i18n.CannotBeUndone)))
+	b.WriteString("\n\n")
+	return b.String()
+}
+
+// renderSquashMergedConfirmation renders the softer force deletion prompt when every branch appears squash-merged
+func (m AppModel) renderSquashMergedConfirmation() string {
+	var b strings.Builder
+
+	b.WriteString(m.Styles.Success.Render(m.t(i18n.SquashTitle)))
+	b.WriteString("\n\n")
+	b.WriteString(m.t(i18n.SquashIntro) + "\n\n")
+
+	for _, branch := range m.orderedBranches(m.UnmergedBranches) {
+		b.WriteString(m.renderForceConfirmationBranch(branch))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Confirmation.UnsetMarginTop().Render(m.t(i18n.SquashRemoval, m.Lang.Count(len(m.UnmergedBranches), i18n.Branch))))
+	b.WriteString("\n\n")
+	return b.String()
+}
+
+// renderForceConfirmationBranch renders a branch awaiting force deletion: why git refused to delete it,
+// or that its content appears merged via squash
+func (m AppModel) renderForceConfirmationBranch(branch string) string {
+	if m.SquashMerged[branch] {
+		return m.Styles.Success.Render(fmt.Sprintf("  • %s", branch)) + "\n" +
+			m.Styles.Help.Render("    "+m.t(i18n.SquashedInto, m.BaseBranch)) + "\n"
+	}
+
+	return m.Styles.Warning.Render(fmt.Sprintf("  • %s", branch)) + "\n" +
+		m.Styles.Help.Render(fmt.Sprintf("    %s", m.UnmergedBranches[branch])) + "\n" +
+		m.renderTipReachability(branch)
+}
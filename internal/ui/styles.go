@@ -1,52 +1,250 @@
 package ui
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Styles holds the lipgloss styles used to render the UI
+type Styles struct {
+	// Title is used for the application title
+	Title lipgloss.Style
+
+	// Header is used for the context header above every screen (repository, current and base branch)
+	Header lipgloss.Style
+
+	// SelectedItem is used for selected branches in the list
+	SelectedItem lipgloss.Style
+
+	// UnselectedItem is used for unselected branches in the list
+	UnselectedItem lipgloss.Style
+
+	// Cursor is used for the cursor indicator
+	Cursor lipgloss.Style
+
+	// Help is used for help text
+	Help lipgloss.Style
+
+	// Metadata is used for dimmed branch metadata (commit age, committer)
+	Metadata lipgloss.Style
+
+	// Merged is used for the "merged" branch annotation
+	Merged lipgloss.Style
+
+	// Unmerged is used for the "unmerged" branch annotation
+	Unmerged lipgloss.Style
+
+	// UpstreamSynced is used for the upstream badge of a branch whose commits are all pushed ("origin ✓")
+	UpstreamSynced lipgloss.Style
+
+	// UpstreamAhead is used for the upstream badge of a branch with unpushed commits ("origin ↑2")
+	UpstreamAhead lipgloss.Style
+
+	// UpstreamGone is used for the badge of a branch whose upstream was deleted ("gone")
+	UpstreamGone lipgloss.Style
+
+	// Error is used for error messages
+	Error lipgloss.Style
+
+	// Success is used for success messages
+	Success lipgloss.Style
+
+	// Warning is used for warning messages
+	Warning lipgloss.Style
+
+	// Confirmation is used for confirmation prompts
+	Confirmation lipgloss.Style
+}
+
+// Theme selects the colors of the UI for a dark or a light terminal background
+type Theme int
+
+const (
+	// ThemeAuto picks the dark or the light colors from the terminal background (see lipgloss.HasDarkBackground)
+	ThemeAuto Theme = iota
+	// ThemeDark uses the colors for dark terminal backgrounds
+	ThemeDark
+	// ThemeLight uses the colors for light terminal backgrounds
+	ThemeLight
+)
+
+// Themes lists the themes, in the order of the --theme flag help
+var Themes = []Theme{ThemeAuto, ThemeDark, ThemeLight}
+
+// ParseTheme parses the value of the --theme flag ("auto", "dark" or "light")
+func ParseTheme(value string) (Theme, error) {
+	for _, theme := range Themes {
+		if theme.String() == value {
+			return theme, nil
+		}
+	}
+	return ThemeAuto, fmt.Errorf("invalid theme '%s' (expected auto, dark or light)", value)
+}
+
+// String returns the --theme flag value of the theme
+func (t Theme) String() string {
+	switch t {
+	case ThemeDark:
+		return "dark"
+	case ThemeLight:
+		return "light"
+	}
+	return "auto"
+}
+
+// The colors of the UI, each defined for light and dark backgrounds
 var (
-	// TitleStyle is used for the application title
-	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#7D56F4")).
-			MarginBottom(1)
-
-	// SelectedItemStyle is used for selected branches in the list
-	SelectedItemStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#04B575")).
-				Bold(true)
-
-	// UnselectedItemStyle is used for unselected branches in the list
-	UnselectedItemStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#FFFFFF"))
-
-	// CursorStyle is used for the cursor indicator
-	CursorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF69B4"))
-
-	// HelpStyle is used for help text
-	HelpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#626262")).
-			MarginTop(1)
-
-	// ErrorStyle is used for error messages
-	ErrorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF0000")).
-			Bold(true)
-
-	// SuccessStyle is used for success messages
-	SuccessStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#04B575")).
-			Bold(true)
-
-	// WarningStyle is used for warning messages
-	WarningStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFA500")).
-			Bold(true)
-
-	// ConfirmationStyle is used for confirmation prompts
-	ConfirmationStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#FFA500")).
-				Bold(true).
-				MarginTop(1)
+	colorAccent           = lipgloss.AdaptiveColor{Light: "#5A3FC0", Dark: "#7D56F4"}
+	colorHeaderText       = lipgloss.AdaptiveColor{Light: "#4A4A4A", Dark: "#A8A8A8"}
+	colorHeaderBackground = lipgloss.AdaptiveColor{Light: "#E4E4E4", Dark: "#3C3C3C"}
+	colorText             = lipgloss.AdaptiveColor{Light: "#1A1A1A", Dark: "#FFFFFF"}
+	colorDim              = lipgloss.AdaptiveColor{Light: "#767676", Dark: "#626262"}
+	colorPositive         = lipgloss.AdaptiveColor{Light: "#00875A", Dark: "#04B575"}
+	colorCursor           = lipgloss.AdaptiveColor{Light: "#D7007D", Dark: "#FF69B4"}
+	colorCaution          = lipgloss.AdaptiveColor{Light: "#9A6700", Dark: "#E5C07B"}
+	colorInfo             = lipgloss.AdaptiveColor{Light: "#0969DA", Dark: "#61AFEF"}
+	colorAlert            = lipgloss.AdaptiveColor{Light: "#CF222E", Dark: "#FF6B6B"}
+	colorGone             = lipgloss.AdaptiveColor{Light: "#6E7781", Dark: "#A0A0A0"}
+	colorError            = lipgloss.AdaptiveColor{Light: "#CF222E", Dark: "#FF0000"}
+	colorWarning          = lipgloss.AdaptiveColor{Light: "#BC4C00", Dark: "#FFA500"}
 )
+
+// color returns the variant of a color for the theme. ThemeAuto keeps both variants and lets lipgloss
+// pick one from the terminal background when rendering.
+func (t Theme) color(color lipgloss.AdaptiveColor) lipgloss.TerminalColor {
+	switch t {
+	case ThemeDark:
+		return lipgloss.Color(color.Dark)
+	case ThemeLight:
+		return lipgloss.Color(color.Light)
+	}
+	return color
+}
+
+// NewStyles returns the UI styles in the colors of a theme. When colorEnabled is false, every style is plain:
+// it keeps the layout (margins) but renders no colors or other escape sequences.
+func NewStyles(theme Theme, colorEnabled bool) Styles {
+	if !colorEnabled {
+		return plainStyles()
+	}
+
+	return Styles{
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.color(colorAccent)).
+			MarginBottom(1),
+
+		Header: lipgloss.NewStyle().
+			Foreground(theme.color(colorHeaderText)).
+			Background(theme.color(colorHeaderBackground)),
+
+		SelectedItem: lipgloss.NewStyle().
+			Foreground(theme.color(colorPositive)).
+			Bold(true),
+
+		UnselectedItem: lipgloss.NewStyle().
+			Foreground(theme.color(colorText)),
+
+		Cursor: lipgloss.NewStyle().
+			Foreground(theme.color(colorCursor)),
+
+		Help: lipgloss.NewStyle().
+			Foreground(theme.color(colorDim)).
+			MarginTop(1),
+
+		Metadata: lipgloss.NewStyle().
+			Foreground(theme.color(colorDim)),
+
+		Merged: lipgloss.NewStyle().
+			Foreground(theme.color(colorPositive)),
+
+		Unmerged: lipgloss.NewStyle().
+			Foreground(theme.color(colorCaution)),
+
+		UpstreamSynced: lipgloss.NewStyle().
+			Foreground(theme.color(colorInfo)),
+
+		UpstreamAhead: lipgloss.NewStyle().
+			Foreground(theme.color(colorAlert)).
+			Bold(true),
+
+		UpstreamGone: lipgloss.NewStyle().
+			Foreground(theme.color(colorGone)).
+			Italic(true),
+
+		Error: lipgloss.NewStyle().
+			Foreground(theme.color(colorError)).
+			Bold(true),
+
+		Success: lipgloss.NewStyle().
+			Foreground(theme.color(colorPositive)).
+			Bold(true),
+
+		Warning: lipgloss.NewStyle().
+			Foreground(theme.color(colorWarning)).
+			Bold(true),
+
+		Confirmation: lipgloss.NewStyle().
+			Foreground(theme.color(colorWarning)).
+			Bold(true).
+			MarginTop(1),
+	}
+}
+
+// plainStyles returns unstyled equivalents of the UI styles, keeping only their margins
+func plainStyles() Styles {
+	plain := lipgloss.NewStyle()
+	return Styles{
+		Title:          plain.MarginBottom(1),
+		Header:         plain,
+		SelectedItem:   plain,
+		UnselectedItem: plain,
+		Cursor:         plain,
+		Help:           plain.MarginTop(1),
+		Metadata:       plain,
+		Merged:         plain,
+		Unmerged:       plain,
+		UpstreamSynced: plain,
+		UpstreamAhead:  plain,
+		UpstreamGone:   plain,
+		Error:          plain,
+		Success:        plain,
+		Warning:        plain,
+		Confirmation:   plain.MarginTop(1),
+	}
+}
+
+// RenderStyleSample renders a line of sample text in every style, so the colors of a theme can be checked
+// at a glance (see gelete themes)
+func RenderStyleSample(styles Styles) string {
+	samples := []struct {
+		name   string
+		style  lipgloss.Style
+		sample string
+	}{
+		{"Title", styles.Title, "gelete - Interactive Branch Deletion"},
+		{"Header", styles.Header, " ~/src/gelete • on main • base main "},
+		{"SelectedItem", styles.SelectedItem, "[✓] feature-a"},
+		{"UnselectedItem", styles.UnselectedItem, "[ ] feature-b"},
+		{"Cursor", styles.Cursor, ">"},
+		{"Help", styles.Help, "space: toggle • d: delete • q: quit"},
+		{"Metadata", styles.Metadata, "2 days ago • Jane Doe"},
+		{"Merged", styles.Merged, "merged"},
+		{"Unmerged", styles.Unmerged, "unmerged"},
+		{"UpstreamSynced", styles.UpstreamSynced, "origin ✓"},
+		{"UpstreamAhead", styles.UpstreamAhead, "origin ↑2"},
+		{"UpstreamGone", styles.UpstreamGone, "gone"},
+		{"Error", styles.Error, "✗ feature-b: not fully merged"},
+		{"Success", styles.Success, "✓ feature-a deleted"},
+		{"Warning", styles.Warning, "⚠ feature-c is checked out in a worktree"},
+		{"Confirmation", styles.Confirmation, "Delete 2 branches? (y/n)"},
+	}
+
+	var b strings.Builder
+	for _, s := range samples {
+		fmt.Fprintf(&b, "%-15s %s\n", s.name, s.style.UnsetMargins().Render(s.sample))
+	}
+	return b.String()
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// BranchTag is a tag pointing exactly at the tip of a selected branch
+type BranchTag struct {
+	// Name is the tag name
+	Name string
+
+	// SharedWith lists the other local branches whose tip the tag also points at.
+	// Shared tags are never deleted along with a branch, since other branches still rely on them.
+	SharedWith []string
+}
+
+// tagsLoadedMsg carries the tags found at the tips of the selected branches
+type tagsLoadedMsg struct {
+	tags map[string][]BranchTag
+}
+
+// loadBranchTags returns a command that finds the tags at the tips of the given branches.
+// Tags are informational, so branches whose tags can't be listed are left out.
+func loadBranchTags(branches []string) tea.Cmd {
+	return func() tea.Msg {
+		tags := make(map[string][]BranchTag)
+		for _, branch := range branches {
+			names, err := git.GetTagsAtBranch(branch)
+			if err != nil {
+				continue
+			}
+			for _, name := range names {
+				tags[branch] = append(tags[branch], BranchTag{Name: name, SharedWith: otherBranchesAtTag(name, branch)})
+			}
+		}
+		return tagsLoadedMsg{tags: tags}
+	}
+}
+
+// otherBranchesAtTag returns the branches other than branch whose tip the tag points at
+func otherBranchesAtTag(tag, branch string) []string {
+	branches, _ := git.GetBranchesAtTag(tag)
+	return slices.DeleteFunc(branches, func(b string) bool { return b == branch })
+}
+
+// handleTagsLoaded stores the tags found at the selected branch tips
+func (m AppModel) handleTagsLoaded(msg tagsLoadedMsg) (tea.Model, tea.Cmd) {
+	m.BranchTags = msg.tags
+	return m, nil
+}
+
+// toggleDeleteTags toggles deleting the tags of the highlighted branch along with it.
+// Only branches with at least one tag no other branch relies on can be toggled.
+func (m *AppModel) toggleDeleteTags() {
+	branch, ok := m.confirmedBranch()
+	if !ok {
+		return
+	}
+
+	if len(m.deletableTags(branch)) > 0 {
+		if m.DeleteTags == nil {
+			m.DeleteTags = make(map[string]bool)
+		}
+		m.DeleteTags[branch] = !m.DeleteTags[branch]
+	}
+}
+
+// deletableTags returns the tags of a branch that no other branch relies on
+func (m AppModel) deletableTags(branch string) []string {
+	var names []string
+	for _, tag := range m.BranchTags[branch] {
+		if len(tag.SharedWith) == 0 {
+			names = append(names, tag.Name)
+		}
+	}
+	return names
+}
+
+// tagsToDelete returns the tags to delete along with the branch, or nil if none were requested
+func (m AppModel) tagsToDelete(branch string) []string {
+	if !m.DeleteTags[branch] {
+		return nil
+	}
+	return m.deletableTags(branch)
+}
+
+// deleteTags deletes the given tags and returns the errors of those that couldn't be deleted
+func deleteTags(ctx context.Context, tags []string) map[string]string {
+	failures := make(map[string]string)
+	for _, tag := range tags {
+		if err := git.DeleteTagContext(ctx, tag); err != nil {
+			failures[tag] = err.Error()
+		}
+	}
+	return failures
+}
+
+// recordTagDeletion records the outcome of deleting the tags of a deleted branch.
+// Tag failures are tracked separately so the branch deletion still counts as successful.
+func (m *AppModel) recordTagDeletion(msg branchDeletedMsg) {
+	for _, tag := range msg.tags {
+		if reason, failed := msg.tagFailures[tag]; failed {
+			m.TagFailures[tag] = reason
+		} else {
+			m.DeletedTagCount++
+		}
+	}
+}
+
+// renderTagToggle renders the tags of a branch in the confirmation list: a checkbox for deleting
+// the tags only this branch points at, and the shared tags that are kept regardless
+func (m AppModel) renderTagToggle(branch string) string {
+	var row string
+	if deletable := m.deletableTags(branch); len(deletable) > 0 {
+		checkbox := "[ ]"
+		if m.DeleteTags[branch] {
+			checkbox = "[✓]"
+		}
+		label := m.t(i18n.AlsoDeleteTag, deletable[0])
+		if len(deletable) > 1 {
+			label = m.t(i18n.AlsoDeleteTags, strings.Join(deletable, ", "))
+		}
+		row += m.Styles.Help.UnsetMarginTop().Render(fmt.Sprintf("      %s %s", checkbox, label)) + "\n"
+	}
+	for _, tag := range m.BranchTags[branch] {
+		if len(tag.SharedWith) > 0 {
+			row += m.Styles.Warning.Render("      "+m.t(i18n.TagKept, tag.Name, strings.Join(tag.SharedWith, ", "))) + "\n"
+		}
+	}
+	return row
+}
+
+// renderTagResults renders how many tags were deleted along with their branches and which failed
+func (m AppModel) renderTagResults() string {
+	var b strings.Builder
+	if m.DeletedTagCount > 0 {
+		b.WriteString(m.Styles.Success.Render(m.t(i18n.AlsoDeletedTags, m.Lang.Count(m.DeletedTagCount, i18n.Tag))))
+		b.WriteString("\n")
+	}
+
+	if len(m.TagFailures) > 0 {
+		b.WriteString("\n")
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.TagDeletionFailed, m.Lang.Count(len(m.TagFailures), i18n.Tag))))
+		b.WriteString("\n")
+		for _, tag := range slices.Sorted(maps.Keys(m.TagFailures)) {
+			b.WriteString(m.Styles.Error.Render(fmt.Sprintf("  • %s: %s", tag, firstLine(m.TagFailures[tag]))))
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
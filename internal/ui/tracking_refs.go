@@ -0,0 +1,277 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"slices"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TrackingRefState represents the current state of the remote-tracking ref pruning UI
+type TrackingRefState int
+
+const (
+	// TrackingRefStateSelection: User is selecting the stale refs to prune
+	TrackingRefStateSelection TrackingRefState = iota
+	// TrackingRefStateConfirmation: User is confirming pruning the selected refs
+	TrackingRefStateConfirmation
+	// TrackingRefStateRunning: The selected refs are being deleted
+	TrackingRefStateRunning
+	// TrackingRefStateDone: Pruning complete or cancelled
+	TrackingRefStateDone
+)
+
+// TrackingRefModel is the UI of gelete remote-prune: a checkbox list of the remote-tracking refs whose branch
+// was deleted on the remote, like WorktreeModel's worktree list. The selected refs are deleted one by one with
+// git branch -d -r, since git remote prune can only prune all of them.
+type TrackingRefModel struct {
+	// Refs contains the stale remote-tracking refs, grouped by remote
+	Refs []git.StaleTrackingRef
+
+	// Selected tracks which refs are selected for pruning (ref -> bool)
+	Selected map[string]bool
+
+	// CursorIndex is the current cursor position in the ref list
+	CursorIndex int
+
+	// State represents the current UI state
+	State TrackingRefState
+
+	// Pruned lists the deleted refs, in list order
+	Pruned []string
+
+	// Failures maps refs to the error that prevented their deletion
+	Failures map[string]string
+
+	// Cancelled is set once the user interrupted a running prune with ctrl+c
+	Cancelled bool
+
+	// Interrupted is the signal that made the program quit, e.g. SIGINT (nil if none; see InterruptMsg)
+	Interrupted os.Signal
+
+	// Queue holds the refs still to be deleted
+	Queue []string
+
+	// Current is the ref being deleted
+	Current string
+
+	// Progress and ProgressTotal count the deleted and total refs
+	Progress      int
+	ProgressTotal int
+
+	// Spinner animates the running view
+	Spinner spinner.Model
+
+	// Styles holds the lipgloss styles used for rendering
+	Styles Styles
+
+	// Lang is the language of the messages (the zero Lang is English)
+	Lang i18n.Lang
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// trackingRefDeletedMsg reports the result of deleting one selected ref
+type trackingRefDeletedMsg struct {
+	ref string
+	err error
+}
+
+// Init initializes the bubbletea model
+func (m TrackingRefModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the model state
+func (m TrackingRefModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case trackingRefDeletedMsg:
+		return m.handleRefDeleted(msg)
+	case deletionCompleteMsg:
+		return m.finish()
+	case InterruptMsg:
+		return m.handleInterrupt(msg)
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.Spinner, cmd = m.Spinner.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+
+	return m, nil
+}
+
+// handleKey dispatches keyboard input to the handler for the current state
+func (m TrackingRefModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.State {
+	case TrackingRefStateSelection:
+		return m.handleSelectionInput(msg)
+	case TrackingRefStateConfirmation:
+		return m.handleConfirmationInput(msg)
+	case TrackingRefStateRunning:
+		return m.handleRunningInput(msg)
+	case TrackingRefStateDone:
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+// handleSelectionInput handles keyboard input in the selection state
+func (m TrackingRefModel) handleSelectionInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		m.CursorIndex = max(0, m.CursorIndex-1)
+	case "down", "j":
+		m.CursorIndex = min(len(m.Refs)-1, m.CursorIndex+1)
+	case " ", "enter":
+		if len(m.Refs) > 0 {
+			ref := m.Refs[m.CursorIndex].Ref
+			m.Selected[ref] = !m.Selected[ref]
+		}
+	case "a":
+		all := m.selectedCount() < len(m.Refs)
+		for _, ref := range m.Refs {
+			m.Selected[ref.Ref] = all
+		}
+	case "d":
+		if m.selectedCount() > 0 {
+			m.State = TrackingRefStateConfirmation
+		}
+	}
+
+	return m, nil
+}
+
+// handleConfirmationInput prunes the selected refs on "y" and returns to the list on "n" or esc; ctrl+c quits
+func (m TrackingRefModel) handleConfirmationInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		return m.startPruning()
+	case "n", "N", "esc":
+		m.State = TrackingRefStateSelection
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// handleRunningInput handles keyboard input while refs are being deleted.
+// The first ctrl+c cancels pruning; a second one quits without waiting for it.
+func (m TrackingRefModel) handleRunningInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() != "ctrl+c" {
+		return m, nil
+	}
+	if m.Cancelled {
+		return m, tea.Quit
+	}
+	m.Cancelled = true
+	m.Queue = nil
+	return m, nil
+}
+
+// handleInterrupt stops pruning and quits on SIGINT or SIGTERM
+func (m TrackingRefModel) handleInterrupt(msg InterruptMsg) (tea.Model, tea.Cmd) {
+	m.Interrupted = msg.Signal
+	m.Cancelled = m.Cancelled || m.State == TrackingRefStateRunning
+	if m.cancel != nil {
+		m.cancel()
+	}
+	return m, tea.Quit
+}
+
+// startPruning deletes the selected refs in list order
+func (m TrackingRefModel) startPruning() (tea.Model, tea.Cmd) {
+	m.Pruned = nil
+	m.Failures = make(map[string]string)
+	m.Queue = nil
+	for _, ref := range m.Refs {
+		if m.Selected[ref.Ref] {
+			m.Queue = append(m.Queue, ref.Ref)
+		}
+	}
+
+	m.State = TrackingRefStateRunning
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.Progress = 0
+	m.ProgressTotal = len(m.Queue)
+	m.Spinner = spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(m.Styles.Cursor))
+
+	cmd := m.nextOperation()
+	return m, tea.Batch(m.Spinner.Tick, cmd)
+}
+
+// nextOperation pops the next ref off the queue and returns the command deleting it,
+// or a command reporting completion when the queue is empty
+func (m *TrackingRefModel) nextOperation() tea.Cmd {
+	if len(m.Queue) == 0 {
+		m.Current = ""
+		return func() tea.Msg { return deletionCompleteMsg{} }
+	}
+
+	m.Current = m.Queue[0]
+	m.Queue = m.Queue[1:]
+	ctx, ref := m.ctx, m.Current
+	return func() tea.Msg {
+		return trackingRefDeletedMsg{ref: ref, err: git.DeleteRemoteTrackingRefContext(ctx, ref)}
+	}
+}
+
+// handleRefDeleted records a ref deletion result and continues with the next ref
+func (m TrackingRefModel) handleRefDeleted(msg trackingRefDeletedMsg) (tea.Model, tea.Cmd) {
+	m.Progress++
+
+	if msg.err != nil {
+		m.Failures[msg.ref] = msg.err.Error()
+	} else {
+		m.Pruned = append(m.Pruned, msg.ref)
+	}
+
+	return m, m.nextOperation()
+}
+
+// finish ends pruning
+func (m TrackingRefModel) finish() (tea.Model, tea.Cmd) {
+	m.State = TrackingRefStateDone
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.ctx, m.cancel = nil, nil
+	return m, nil
+}
+
+// WasCancelled reports whether the user cancelled with refs still to prune: by quitting before pruning
+// started while refs were selected, or by cancelling the running prune
+func (m TrackingRefModel) WasCancelled() bool {
+	beforePruning := m.State == TrackingRefStateSelection || m.State == TrackingRefStateConfirmation
+	return m.Cancelled || (beforePruning && m.selectedCount() > 0)
+}
+
+// HasFailures reports whether deleting a ref failed
+func (m TrackingRefModel) HasFailures() bool {
+	return len(m.Failures) > 0
+}
+
+// selectedCount returns the number of selected refs
+func (m TrackingRefModel) selectedCount() int {
+	count := 0
+	for _, ref := range m.Refs {
+		if m.Selected[ref.Ref] {
+			count++
+		}
+	}
+	return count
+}
+
+// isPruned reports whether a ref was deleted
+func (m TrackingRefModel) isPruned(ref string) bool {
+	return slices.Contains(m.Pruned, ref)
+}
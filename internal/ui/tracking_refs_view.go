@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/i18n"
+)
+
+// t returns the message in the language of the model, formatted with args
+func (m TrackingRefModel) t(id i18n.MessageID, args ...any) string {
+	return m.Lang.T(id, args...)
+}
+
+// View renders the remote-tracking ref pruning UI based on the current state
+func (m TrackingRefModel) View() string {
+	switch m.State {
+	case TrackingRefStateSelection:
+		return m.renderSelection()
+	case TrackingRefStateConfirmation:
+		return m.renderConfirmation()
+	case TrackingRefStateRunning:
+		return m.renderRunning()
+	case TrackingRefStateDone:
+		return m.renderDone()
+	}
+	return ""
+}
+
+func (m TrackingRefModel) renderSelection() string {
+	var b strings.Builder
+
+	b.WriteString(m.Styles.Title.Render(m.t(i18n.TrackingRefSelectionTitle)))
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.UnsetMarginTop().Render(m.t(i18n.StatusSelected, m.selectedCount(), len(m.Refs))))
+	b.WriteString("\n\n")
+
+	remote := ""
+	for i, ref := range m.Refs {
+		if ref.Remote != remote {
+			remote = ref.Remote
+			b.WriteString(m.Styles.Metadata.Render(m.t(i18n.TrackingRefRemoteGroup, remote)))
+			b.WriteString("\n")
+		}
+		b.WriteString(m.renderRefRow(ref.Ref, i == m.CursorIndex))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.Render(m.t(i18n.TrackingRefSelectionHelp)))
+	return b.String()
+}
+
+// renderRefRow renders a remote-tracking ref with its checkbox
+func (m TrackingRefModel) renderRefRow(ref string, isCursor bool) string {
+	cursor := "  "
+	if isCursor {
+		cursor = m.Styles.Cursor.Render("> ")
+	}
+
+	checkbox := "[ ]"
+	style := m.Styles.UnselectedItem
+	if m.Selected[ref] {
+		checkbox = "[✓]"
+		style = m.Styles.SelectedItem
+	}
+	return fmt.Sprintf("%s%s %s", cursor, checkbox, style.Render(ref))
+}
+
+func (m TrackingRefModel) renderConfirmation() string {
+	var b strings.Builder
+
+	b.WriteString(m.Styles.Confirmation.Render(m.t(i18n.TrackingRefRemovalQuestion)))
+	b.WriteString("\n\n")
+
+	for _, ref := range m.Refs {
+		if m.Selected[ref.Ref] {
+			b.WriteString(m.Styles.Warning.Render(fmt.Sprintf("  • %s", ref.Ref)))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.Render(m.t(i18n.TrackingRefConfirmationTotal, m.Lang.Count(m.selectedCount(), i18n.TrackingRef))))
+	b.WriteString("\n\n")
+	b.WriteString(m.Styles.Help.Render(m.t(i18n.TrackingRefConfirmHelp)))
+	return b.String()
+}
+
+func (m TrackingRefModel) renderRunning() string {
+	var b strings.Builder
+
+	b.WriteString(m.Styles.Title.Render(m.t(i18n.PruningTitle)))
+	b.WriteString("\n\n")
+
+	if m.Current == "" {
+		b.WriteString(m.t(i18n.PleaseWait))
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("%s %s %d/%d: %s…", m.Spinner.View(), m.t(i18n.PhaseDeleting), m.Progress+1, m.ProgressTotal, m.Current))
+	b.WriteString("\n\n")
+	if m.Cancelled {
+		b.WriteString(m.Styles.Warning.Render(m.t(i18n.CancellingPrune)))
+	} else {
+		b.WriteString(m.Styles.Help.Render(m.t(i18n.HelpCancelRun)))
+	}
+	return b.String()
+}
+
+func (m TrackingRefModel) renderDone() string {
+	var b strings.Builder
+
+	title := m.t(i18n.PruningDoneTitle)
+	if m.Cancelled {
+		title = m.t(i18n.PruningCancelledTitle)
+	}
+	b.WriteString(m.Styles.Title.Render(title))
+	b.WriteString("\n\n")
+
+	for _, ref := range m.Refs {
+		switch {
+		case m.isPruned(ref.Ref):
+			b.WriteString(m.Styles.Success.Render(m.t(i18n.TrackingRefPruned, ref.Ref)))
+			b.WriteString("\n")
+		case m.Failures[ref.Ref] != "":
+			b.WriteString(m.Styles.Error.Render(m.t(i18n.ResultFailed, ref.Ref, firstLine(m.Failures[ref.Ref]))))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.UnsetMarginTop().Render(m.t(i18n.PruneCounts, m.Lang.Count(len(m.Pruned), i18n.TrackingRef), len(m.Failures))))
+	b.WriteString("\n\n")
+	b.WriteString(m.Styles.Help.Render(m.t(i18n.PressAnyKey)))
+	return b.String()
+}
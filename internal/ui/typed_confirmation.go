@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typedConfirmationWord is typed to confirm a deletion that takes more than a key press,
+// as an alternative to the number of branches
+const typedConfirmationWord = "delete"
+
+// needsTypedConfirmation reports whether confirming the current deletion prompt takes typing instead of
+// a key press: always for force deletions, otherwise when more than ConfirmThreshold branches are deleted.
+// Dry runs delete nothing, so a key press confirms them.
+func (m AppModel) needsTypedConfirmation() bool {
+	switch {
+	case m.DryRun, m.PlanPath != "":
+		return false
+	case m.forceConfirmation():
+		return true
+	}
+	return m.ConfirmThreshold > 0 && m.confirmationCount() > m.ConfirmThreshold
+}
+
+// forceConfirmation reports whether the current prompt confirms a force deletion
+func (m AppModel) forceConfirmation() bool {
+	return m.State == StateForceConfirmation || (m.ForceMode && !m.Remote)
+}
+
+// confirmationCount returns the number of branches the current prompt deletes,
+// which can be typed instead of typedConfirmationWord
+func (m AppModel) confirmationCount() int {
+	if m.State == StateForceConfirmation {
+		return len(m.UnmergedBranches)
+	}
+	return m.selectedCount()
+}
+
+// openTypedConfirmation opens the input the confirmation is typed into, once the confirm key was pressed.
+// backup remembers that a force deletion was confirmed with the Backup key.
+func (m AppModel) openTypedConfirmation(backup bool) (tea.Model, tea.Cmd) {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = typedConfirmationWord
+	// A static cursor like the filter line's, so no blink ticks have to be routed to the input
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.Focus()
+
+	m.TypingConfirmation = true
+	m.ConfirmInput = input
+	m.ConfirmBackup = backup
+	m.ErrorMsg = ""
+	return m, nil
+}
+
+// handleTypedConfirmationInput handles keyboard input while the confirmation is typed.
+// Enter confirms; esc, or backspace with nothing typed, cancels.
+func (m AppModel) handleTypedConfirmationInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Type == tea.KeyEsc, msg.Type == tea.KeyBackspace && m.ConfirmInput.Value() == "":
+		return m.cancelTypedConfirmation()
+	case msg.Type == tea.KeyEnter:
+		return m.submitTypedConfirmation()
+	}
+
+	// Errors are shown until the confirmation is edited
+	m.ErrorMsg = ""
+	var cmd tea.Cmd
+	m.ConfirmInput, cmd = m.ConfirmInput.Update(msg)
+	return m, cmd
+}
+
+// submitTypedConfirmation starts the deletion if typedConfirmationWord or the number of branches was typed.
+// Anything else is shown as an error and the input stays open so it can be corrected.
+func (m AppModel) submitTypedConfirmation() (tea.Model, tea.Cmd) {
+	typed := strings.TrimSpace(m.ConfirmInput.Value())
+	count := m.confirmationCount()
+	if !strings.EqualFold(typed, typedConfirmationWord) && typed != strconv.Itoa(count) {
+		m.ErrorMsg = m.t(i18n.TypeToConfirm, typedConfirmationWord, count)
+		return m, nil
+	}
+
+	m.TypingConfirmation = false
+	if m.State == StateForceConfirmation {
+		return m.confirmForceDeletion(m.ConfirmBackup)
+	}
+	return m.confirmDeletion()
+}
+
+// cancelTypedConfirmation closes the typed confirmation: the confirmation list returns to the selection, and
+// the force prompt of a running deletion stays open, so its branches can still be skipped or confirmed again
+func (m AppModel) cancelTypedConfirmation() (tea.Model, tea.Cmd) {
+	m.TypingConfirmation = false
+	m.ErrorMsg = ""
+	if m.State == StateConfirmation {
+		m.State = StateSelection
+	}
+	return m, nil
+}
+
+// renderConfirmationPrompt renders the help line of a deletion prompt. When confirming takes typing, the
+// requirement is explained above it, and once the confirm key was pressed the input replaces the help.
+func (m AppModel) renderConfirmationPrompt(help string) string {
+	if !m.needsTypedConfirmation() {
+		return m.Styles.Help.Render(help)
+	}
+
+	reason := m.t(i18n.TypedConfirmationThreshold, m.Lang.Count(m.ConfirmThreshold, i18n.Branch))
+	if m.forceConfirmation() {
+		reason = m.t(i18n.TypedConfirmationForce)
+	}
+	count := m.confirmationCount()
+	requirement := m.t(i18n.TypedConfirmationRequirement, reason, typedConfirmationWord, count)
+	if !m.TypingConfirmation {
+		return m.Styles.Warning.Render(requirement) + "\n" + m.Styles.Help.Render(help)
+	}
+
+	var b strings.Builder
+	b.WriteString(m.Styles.Warning.Render(requirement))
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Cursor.Render(m.ConfirmInput.View()))
+	b.WriteString("\n")
+	if m.ErrorMsg != "" {
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.ErrorLine, m.ErrorMsg)))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.Styles.Help.Render(m.t(i18n.TypedConfirmationHelp, typedConfirmationWord, count)))
+	return b.String()
+}
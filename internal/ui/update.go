@@ -1,35 +1,157 @@
 package ui
 
 import (
-	"fmt"
-	"strings"
+	"maps"
 
-	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/session"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// Update handles messages and updates the model state
+// Update handles messages and updates the model state, then looks up the authors of the branches
+// scrolled into view when the author column is shown
 func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return loadVisibleOrigins(m.update(msg))
+}
+
+// update dispatches messages to their handlers
+func (m AppModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
-	case deletionResultMsg:
-		return AppModel(msg), nil
-	case forceDeletionResultMsg:
-		return AppModel(msg), nil
+	case worktreeRemovedMsg, branchDeletedMsg, lockRetryMsg, branchPlannedMsg, backupCreatedMsg, bundleWrittenMsg, deletionCompleteMsg:
+		return m.handleDeletionMsg(msg)
+	case branchesLoadedMsg:
+		return m.handleBranchesLoaded(msg)
+	case previewLoadedMsg, tagsLoadedMsg, branchesAnalyzedMsg, pullRequestsLoadedMsg, worktreeSizedMsg, worktreeCheckedMsg,
+		originLoadedMsg, branchesRefreshedMsg, comparisonLoadedMsg, wipDetectedMsg, reviewCommitsLoadedMsg:
+		return m.handleLoadedMsg(msg)
+	case checkedOutMsg, branchRenamedMsg, branchesCopiedMsg:
+		return m.handleActionMsg(msg)
+	case branchesRestoredMsg, repositoryCompactedMsg:
+		return m.handleDoneMsg(msg)
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.Spinner, cmd = m.Spinner.Update(msg)
+		return m, cmd
+	case InterruptMsg:
+		return m.handleInterrupt(msg)
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		m.moveCursor(0)
+		return m, nil
 	case tea.KeyMsg:
-		switch m.State {
-		case StateSelection:
-			return m.handleSelectionInput(msg)
-		case StateConfirmation:
-			return m.handleConfirmationInput(msg)
-		case StateForceConfirmation:
-			return m.handleForceConfirmationInput(msg)
-		case StateDeleting:
-			if msg.String() == "ctrl+c" {
-				return m, tea.Quit
-			}
-		case StateDone:
-			return m, tea.Quit
-		}
+		return m.handleKey(msg)
+	}
+
+	return m, nil
+}
+
+// handleDeletionMsg dispatches the results of a running deletion to their handlers
+func (m AppModel) handleDeletionMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case worktreeRemovedMsg:
+		return m.handleWorktreeRemoved(msg)
+	case branchDeletedMsg:
+		return m.handleBranchDeleted(msg)
+	case lockRetryMsg:
+		return m.handleLockRetry(msg)
+	case branchPlannedMsg:
+		return m.handleBranchPlanned(msg)
+	case backupCreatedMsg:
+		return m.handleBackupCreated(msg)
+	case bundleWrittenMsg:
+		return m.handleBundleWritten(msg)
+	}
+	return m.handlePhaseComplete()
+}
+
+// handleLoadedMsg dispatches the results of background lookups to their handlers: those over the listed
+// branches here, the details of particular branches through handleDetailMsg
+func (m AppModel) handleLoadedMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case pullRequestsLoadedMsg:
+		return m.handlePullRequestsLoaded(msg)
+	case worktreeSizedMsg:
+		return m.handleWorktreeSized(msg)
+	case worktreeCheckedMsg:
+		return m.handleWorktreeChecked(msg)
+	case branchesRefreshedMsg:
+		return m.handleBranchesRefreshed(msg)
+	case originLoadedMsg:
+		return m.handleOriginLoaded(msg)
+	case wipDetectedMsg:
+		return m.handleWIPDetected(msg)
+	}
+	return m.handleDetailMsg(msg)
+}
+
+// handleDetailMsg dispatches the details looked up about particular branches to their handlers:
+// previews and comparisons, and the checks of the selected branches before they are deleted
+func (m AppModel) handleDetailMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case previewLoadedMsg:
+		return m.handlePreviewLoaded(msg)
+	case tagsLoadedMsg:
+		return m.handleTagsLoaded(msg)
+	case branchesAnalyzedMsg:
+		return m.handleBranchesAnalyzed(msg)
+	case comparisonLoadedMsg:
+		return m.handleComparisonLoaded(msg)
+	case reviewCommitsLoadedMsg:
+		return m.handleReviewCommitsLoaded(msg)
+	}
+	return m, nil
+}
+
+// handleActionMsg dispatches the results of the actions of the selection list that run git or the clipboard
+func (m AppModel) handleActionMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case checkedOutMsg:
+		return m.handleCheckedOut(msg)
+	case branchRenamedMsg:
+		return m.handleBranchRenamed(msg)
+	case branchesCopiedMsg:
+		return m.handleBranchesCopied(msg)
+	}
+	return m, nil
+}
+
+// handleDoneMsg dispatches the results of the actions of the done screen to their handlers
+func (m AppModel) handleDoneMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case branchesRestoredMsg:
+		return m.handleBranchesRestored(msg)
+	case repositoryCompactedMsg:
+		return m.handleRepositoryCompacted(msg)
+	}
+	return m, nil
+}
+
+// handleKey dispatches keyboard input to the handler for the current state. ctrl+c is handled the same
+// way everywhere; other keys are ignored while deleting.
+func (m AppModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyCtrlC {
+		return m.handleCtrlC()
+	}
+
+	switch m.State {
+	case StateSelection:
+		return m.handleSelectionInput(msg)
+	case StateConfirmation:
+		return m.handleConfirmationInput(msg)
+	case StateWorktreeConfirmation, StateLockedWorktreeConfirmation, StateCheckedOutConfirmation:
+		return m.handleWorktreePromptInput(msg)
+	case StateForceConfirmation:
+		return m.handleForceConfirmationInput(msg)
+	case StateForceReview:
+		return m.handleForceReviewInput(msg)
+	case StateDone:
+		return m.handleDoneInput(msg)
+	case StateRestore:
+		return m.handleRestoreInput(msg)
+	case StateLoading:
+		return m.handleLoadingInput(msg)
 	}
 
 	return m, nil
@@ -37,130 +159,517 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleSelectionInput handles keyboard input in the selection state
 func (m AppModel) handleSelectionInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "q", "ctrl+c":
-		return m, tea.Quit
+	if m.Filtering {
+		return m.handleFilterInput(msg)
+	}
+	if m.PreviewOpen {
+		return m.handlePreviewInput(msg)
+	}
+	if m.CompareOpen {
+		return m.handleCompareInput(msg)
+	}
+	if m.Renaming != "" {
+		return m.handleRenameInput(msg)
+	}
+	if m.SelectingPattern {
+		return m.handlePatternInput(msg)
+	}
 
-	case "up", "k":
-		if m.CursorIndex > 0 {
-			m.CursorIndex--
-		}
+	// Errors and status messages are shown until the next key press
+	m.ErrorMsg = ""
+	m.SuccessMsg = ""
+	return m.handleSelectionKey(msg)
+}
 
-	case "down", "j":
-		if m.CursorIndex < len(m.Branches)-1 {
-			m.CursorIndex++
-		}
+// handleSelectionKey handles a key of the selection list when no input line, preview or comparison is open
+func (m AppModel) handleSelectionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if next, cmd, handled := m.handleActionKey(msg); handled {
+		return next, cmd
+	}
 
-	case " ", "enter":
-		if len(m.Branches) > 0 {
-			branch := m.Branches[m.CursorIndex]
-			m.Selected[branch] = !m.Selected[branch]
-		}
+	m.handleListKey(msg)
+	m.recordSelectionOrder()
+	if m.PersistSession && m.isSelectionKey(msg) {
+		return m, m.saveSession()
+	}
+	return m, nil
+}
+
+// handleActionKey handles the keys of the selection list that open another screen, run git or quit,
+// and reports whether the key was handled
+func (m AppModel) handleActionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	var next tea.Model
+	var cmd tea.Cmd
+	keys := m.keyMap()
+	switch {
+	case key.Matches(msg, keys.Quit):
+		next, cmd = m, tea.Quit
+	case key.Matches(msg, keys.Back) && m.CanGoBack:
+		m.WentBack = true
+		next, cmd = m, tea.Quit
+	case key.Matches(msg, keys.Copy):
+		next, cmd = m.copyBranches()
+	case key.Matches(msg, keys.SelectPattern):
+		next, cmd = m.openPatternSelection()
+	case key.Matches(msg, keys.SelectWIP):
+		next, cmd = m.selectWIP()
+	case key.Matches(msg, keys.Refresh):
+		next, cmd = m.refresh()
+	case key.Matches(msg, keys.Delete):
+		next, cmd = m.openConfirmation()
+	default:
+		return m.handleCursorKey(msg)
+	}
+	return next, cmd, true
+}
+
+// handleCursorKey handles the keys of the selection list acting on the branch under the cursor,
+// and reports whether the key was handled
+func (m AppModel) handleCursorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	var next tea.Model
+	var cmd tea.Cmd
+	keys := m.keyMap()
+	switch {
+	case key.Matches(msg, keys.Preview):
+		next, cmd = m.openPreview()
+	case key.Matches(msg, keys.Checkout):
+		next, cmd = m.checkoutCurrent()
+	case key.Matches(msg, keys.Rename):
+		next, cmd = m.openRename()
+	case key.Matches(msg, keys.Compare):
+		next, cmd = m.compareCurrent()
+	default:
+		return m, nil, false
+	}
+	return next, cmd, true
+}
+
+// openConfirmation asks to confirm deleting the selected branches, if any.
+// The tags at the tips of local branches are looked up in the background so they can be deleted too.
+func (m AppModel) openConfirmation() (tea.Model, tea.Cmd) {
+	if !m.hasSelectedBranches() {
+		return m, nil
+	}
+
+	m.State = StateConfirmation
+	m.ConfirmCursor = 0
+	m.DeleteRemote = make(map[string]bool)
+	m.DeleteTags = make(map[string]bool)
+	m.BranchTags = nil
+	m.Analysis = nil
+	m.AnalysisErr = ""
+	if m.Remote {
+		return m, nil
+	}
+	return m, tea.Batch(m.analyzeBranches(m.selectedBranches()), loadBranchTags(m.selectedBranches()))
+}
+
+// isSelectionKey reports whether the key changes the selection in the selection state
+func (m AppModel) isSelectionKey(msg tea.KeyMsg) bool {
+	keys := m.keyMap()
+	return key.Matches(msg, keys.Toggle, keys.SelectAll, keys.Invert)
+}
+
+// saveSession returns a command that persists a snapshot of the current selection.
+// Saving is best-effort: a failure must never interrupt branch selection.
+func (m AppModel) saveSession() tea.Cmd {
+	selected := maps.Clone(m.Selected)
+	return func() tea.Msg {
+		_ = session.Save(selected)
+		return nil
+	}
+}
+
+// clearSession removes the persisted selection once a deletion run finished
+func clearSession() tea.Msg {
+	_ = session.Clear()
+	return nil
+}
 
-	case "d":
-		if m.hasSelectedBranches() {
-			m.State = StateConfirmation
+// handleListKey handles cursor movement, selection, and filter keys in the selection state
+func (m *AppModel) handleListKey(msg tea.KeyMsg) {
+	if m.handleViewKey(msg) || m.handleFilterKey(msg) {
+		return
+	}
+
+	keys := m.keyMap()
+	switch {
+	case key.Matches(msg, keys.Toggle):
+		m.toggleCurrent()
+
+	case key.Matches(msg, keys.SelectAll):
+		m.toggleSelectAll()
+
+	case key.Matches(msg, keys.Invert):
+		m.invertSelection()
+
+	case key.Matches(msg, keys.Sort):
+		m.cycleSort()
+
+	case key.Matches(msg, keys.DateFormat):
+		m.toggleDateFormat()
+	}
+}
+
+// handleFilterKey handles the keys that narrow the listed branches: the merge filter, the mine filter
+// and the text filter, and reports whether the key was handled
+func (m *AppModel) handleFilterKey(msg tea.KeyMsg) bool {
+	keys := m.keyMap()
+	switch {
+	case key.Matches(msg, keys.MergeFilter):
+		m.cycleMergeFilter()
+	case key.Matches(msg, keys.Mine):
+		m.toggleMineFilter()
+	case key.Matches(msg, keys.Filter):
+		m.Filtering = true
+	case key.Matches(msg, keys.ClearFilter):
+		m.setFilter("")
+	default:
+		return false
+	}
+	return true
+}
+
+// handleViewKey handles the keys that move the cursor or change how the list is laid out,
+// and reports whether the key was handled
+func (m *AppModel) handleViewKey(msg tea.KeyMsg) bool {
+	return m.handleNavigationKey(msg) || m.handleGroupKey(msg) || m.handleSelectionPaneKey(msg)
+}
+
+// handleNavigationKey moves the cursor for navigation keys and reports whether the key was handled
+func (m *AppModel) handleNavigationKey(msg tea.KeyMsg) bool {
+	keys := m.keyMap()
+	switch {
+	case key.Matches(msg, keys.Up):
+		m.moveCursor(-1)
+	case key.Matches(msg, keys.Down):
+		m.moveCursor(1)
+	case key.Matches(msg, keys.PageUp):
+		m.moveCursor(-m.listHeight())
+	case key.Matches(msg, keys.PageDown):
+		m.moveCursor(m.listHeight())
+	case key.Matches(msg, keys.Home):
+		m.moveCursor(-len(m.Branches))
+	case key.Matches(msg, keys.End):
+		m.moveCursor(len(m.Branches))
+	default:
+		return false
+	}
+	return true
+}
+
+// handleFilterInput handles keyboard input while the filter query is being typed
+func (m AppModel) handleFilterInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.Filtering = false
+		m.setFilter("")
+
+	case tea.KeyEnter:
+		m.Filtering = false
+
+	case tea.KeyBackspace:
+		query := []rune(m.FilterQuery)
+		if len(query) > 0 {
+			m.setFilter(string(query[:len(query)-1]))
 		}
+
+	case tea.KeyUp:
+		m.moveCursor(-1)
+
+	case tea.KeyDown:
+		m.moveCursor(1)
+
+	case tea.KeyRunes, tea.KeySpace:
+		m.setFilter(m.FilterQuery + string(msg.Runes))
 	}
 
 	return m, nil
 }
 
+// setFilter updates the filter query and keeps the cursor within the visible branches
+func (m *AppModel) setFilter(query string) {
+	m.FilterQuery = query
+	m.moveCursor(0)
+}
+
+// moveCursor moves the cursor by delta, staying within the rows of the list,
+// and scrolls the viewport so the cursor stays on screen
+func (m *AppModel) moveCursor(delta int) {
+	rows := len(m.listRows())
+	m.CursorIndex = max(0, min(m.CursorIndex+delta, rows-1))
+
+	height := m.listHeight()
+	if m.CursorIndex < m.ScrollOffset {
+		m.ScrollOffset = m.CursorIndex
+	}
+	if m.CursorIndex >= m.ScrollOffset+height {
+		m.ScrollOffset = m.CursorIndex - height + 1
+	}
+	m.ScrollOffset = max(0, min(m.ScrollOffset, rows-height))
+}
+
+// toggleCurrent toggles the selection of the branch under the cursor, or of the whole group on a group header
+func (m *AppModel) toggleCurrent() {
+	row, ok := m.cursorRow()
+	switch {
+	case !ok:
+	case row.isHeader():
+		m.toggleGroupSelection(row.group)
+	default:
+		m.Selected[row.branch] = !m.Selected[row.branch]
+	}
+}
+
+// toggleSelectAll selects every visible branch, or deselects them if all are already selected
+func (m *AppModel) toggleSelectAll() {
+	visible := m.VisibleBranches()
+	selectAll := false
+	for _, branch := range visible {
+		if !m.Selected[branch] {
+			selectAll = true
+			break
+		}
+	}
+
+	for _, branch := range visible {
+		m.Selected[branch] = selectAll
+	}
+}
+
+// invertSelection flips the selection state of every visible branch
+func (m *AppModel) invertSelection() {
+	for _, branch := range m.VisibleBranches() {
+		m.Selected[branch] = !m.Selected[branch]
+	}
+}
+
 // handleConfirmationInput handles keyboard input in the confirmation state
 func (m AppModel) handleConfirmationInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "y":
-		m.State = StateDeleting
-		return m, m.deleteBranches
+	if m.TypingConfirmation {
+		return m.handleTypedConfirmationInput(msg)
+	}
 
-	case "n", "q", "ctrl+c":
+	keys := m.keyMap()
+	switch {
+	case key.Matches(msg, keys.Confirm):
+		return m.confirmWithKey()
+
+	case key.Matches(msg, keys.Cancel), isPromptExit(msg):
 		m.State = StateSelection
+
+	case key.Matches(msg, keys.Up):
+		m.ConfirmCursor = max(0, m.ConfirmCursor-1)
+
+	case key.Matches(msg, keys.Down):
+		m.ConfirmCursor = min(m.ConfirmCursor+1, len(m.selectedBranches())-1)
+
+	case key.Matches(msg, keys.ToggleRemote):
+		m.toggleDeleteRemote()
+
+	case key.Matches(msg, keys.ToggleTags):
+		m.toggleDeleteTags()
+
+	case key.Matches(msg, keys.ToggleForce):
+		// Remote branches are deleted with git push --delete, which has no force variant
+		m.ForceMode = !m.ForceMode && !m.Remote
 	}
 
 	return m, nil
 }
 
-// handleForceConfirmationInput handles keyboard input in the force confirmation state
-func (m AppModel) handleForceConfirmationInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "y":
-		m.State = StateDeleting
-		return m, m.forceDeleteBranches
+// isPromptExit reports whether the key leaves a deletion prompt whatever the key map: q
+func isPromptExit(msg tea.KeyMsg) bool {
+	return msg.String() == "q"
+}
 
-	case "n", "q", "ctrl+c":
-		// Skip unmerged branches and mark as done
-		m.State = StateDone
+// confirmWithKey starts the deletion confirmed with the confirm key, unless confirming it takes typing
+func (m AppModel) confirmWithKey() (tea.Model, tea.Cmd) {
+	if m.needsTypedConfirmation() {
+		return m.openTypedConfirmation(false)
+	}
+	return m.confirmDeletion()
+}
+
+// confirmDeletion starts the confirmed deletion run, asking first whether to remove the worktrees
+// of selected branches
+func (m AppModel) confirmDeletion() (tea.Model, tea.Cmd) {
+	if m.PlanPath != "" {
+		return m.confirmPlan()
+	}
+	m.resetResults()
+	if worktrees := m.selectedWorktrees(); !m.DryRun && len(worktrees) > 0 {
+		// The changes are checked again each time, since they may have been committed or made meanwhile
+		m.State = StateWorktreeConfirmation
+		m.WorktreeChanges = nil
+		return m, tea.Batch(m.measureWorktrees(worktrees), checkWorktrees(worktrees))
+	}
+	return m.startDeletion()
+}
+
+// toggleDeleteRemote toggles deleting the upstream of the highlighted branch along with it
+func (m *AppModel) toggleDeleteRemote() {
+	branch, ok := m.confirmedBranch()
+	if !ok {
+		return
+	}
+
+	if _, hasUpstream := m.Upstreams[branch]; hasUpstream {
+		if m.DeleteRemote == nil {
+			m.DeleteRemote = make(map[string]bool)
+		}
+		m.DeleteRemote[branch] = !m.DeleteRemote[branch]
+	}
+}
+
+// selectedBranches returns the selected branches in list order
+func (m AppModel) selectedBranches() []string {
+	var branches []string
+	for _, branch := range m.Branches {
+		if m.Selected[branch] {
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
+
+// handleWorktreePromptInput dispatches keyboard input to the handler of the current worktree removal prompt
+func (m AppModel) handleWorktreePromptInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.State {
+	case StateLockedWorktreeConfirmation:
+		return m.handleLockedWorktreeConfirmationInput(msg)
+	case StateCheckedOutConfirmation:
+		return m.handleCheckedOutConfirmationInput(msg)
+	}
+	return m.handleWorktreeConfirmationInput(msg)
+}
+
+// handleWorktreeConfirmationInput handles keyboard input in the worktree removal confirmation state
+func (m AppModel) handleWorktreeConfirmationInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := m.keyMap()
+	switch {
+	case key.Matches(msg, keys.Confirm):
+		return m.removeWorktrees(false)
+
+	case key.Matches(msg, keys.DiscardChanges) && len(m.dirtyWorktrees(m.selectedWorktrees())) > 0:
+		return m.removeWorktrees(true)
+
+	case key.Matches(msg, keys.Cancel):
+		// Skip branches with worktrees but continue with the others
+		for _, branch := range m.orderedBranches(m.selectedWorktrees()) {
+			m.recordResult(DeletionResult{Branch: branch, Status: ResultSkipped, Reason: "worktree removal declined"})
+		}
+		return m.startDeletion()
+
+	case isPromptExit(msg):
+		m.State = StateSelection
+	}
+
+	return m, nil
+}
+
+// handleLockedWorktreeConfirmationInput handles keyboard input in the locked worktree confirmation state
+func (m AppModel) handleLockedWorktreeConfirmationInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := m.keyMap()
+	switch {
+	case key.Matches(msg, keys.Confirm):
+		return m.forceRemoveLockedWorktrees(false)
+
+	case key.Matches(msg, keys.DiscardChanges) && len(m.dirtyWorktrees(m.LockedWorktrees)) > 0:
+		return m.forceRemoveLockedWorktrees(true)
+
+	case key.Matches(msg, keys.Cancel), isPromptExit(msg):
+		// Skip branches with locked worktrees but continue with the others
+		for _, branch := range m.orderedBranches(m.LockedWorktrees) {
+			m.recordResult(DeletionResult{Branch: branch, Status: ResultSkipped, Reason: "locked worktree removal declined"})
+		}
+		return m.startDeletion()
 	}
 
 	return m, nil
 }
 
-// deleteBranches executes branch deletion and returns a command
-// If unmerged branches are detected, transitions to StateForceConfirmation
-// Handles worktree removal before branch deletion (FR-013)
-func (m AppModel) deleteBranches() tea.Msg {
+// resetResults clears the outcome of any previous deletion run
+func (m *AppModel) resetResults() {
 	m.DeletedCount = 0
-	m.FailedBranches = make(map[string]string)
+	m.Results = nil
+	m.ResultLog = nil
+	m.RemoteDeletedCount = 0
+	m.RemoteFailures = make(map[string]string)
+	m.DeletedTagCount = 0
+	m.TagFailures = make(map[string]string)
 	m.UnmergedBranches = make(map[string]string)
+	m.TipReachability = make(map[string]TipReachability)
+	m.LockedWorktrees = make(map[string]string)
+	m.CheckedOutWorktrees = make(map[string]string)
+	m.DiscardChanges = false
+	m.DryRunActions = nil
+	m.Restored = nil
+	m.RestoreFailures = nil
+	m.Cancelled = false
+	m.FreedSpace = WorktreeSize{}
+	m.RemovedWorktreeCount = 0
+	m.BackupTags = nil
+	m.BundledBranches = nil
+}
 
+// selectedWorktrees returns the worktree paths of selected branches, keyed by branch name
+func (m AppModel) selectedWorktrees() map[string]string {
+	worktrees := make(map[string]string)
 	for _, branch := range m.Branches {
-		if m.Selected[branch] {
-			// Check if branch has a worktree and remove it first (FR-013)
-			if worktreePath, hasWorktree := m.BranchWorktrees[branch]; hasWorktree {
-				// Try normal removal first
-				err := git.RemoveWorktree(worktreePath)
-				if err != nil {
-					// If locked, try force removal (FR-014)
-					if strings.Contains(err.Error(), "locked") {
-						err = git.ForceRemoveWorktree(worktreePath)
-					}
-
-					if err != nil {
-						m.FailedBranches[branch] = fmt.Sprintf("worktree removal failed: %s", err.Error())
-						continue
-					}
-				}
-			}
-
-			// Now attempt to delete the branch
-			err := git.DeleteBranch(branch)
-			if err != nil {
-				// Check if error is due to unmerged changes
-				if isUnmergedError(err.Error()) {
-					m.UnmergedBranches[branch] = err.Error()
-				} else {
-					m.FailedBranches[branch] = err.Error()
-				}
-			} else {
-				m.DeletedCount++
-			}
+		if path, hasWorktree := m.BranchWorktrees[branch]; hasWorktree && m.Selected[branch] {
+			worktrees[branch] = path
 		}
 	}
+	return worktrees
+}
 
-	// If there are unmerged branches, prompt for force delete
-	if len(m.UnmergedBranches) > 0 {
-		m.State = StateForceConfirmation
-	} else {
-		m.State = StateDone
+// handleForceConfirmationInput handles keyboard input in the force confirmation state.
+// Force deletions are always confirmed by typing (see needsTypedConfirmation).
+func (m AppModel) handleForceConfirmationInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.TypingConfirmation {
+		return m.handleTypedConfirmationInput(msg)
 	}
 
-	return deletionResultMsg(m)
+	keys := m.keyMap()
+	switch {
+	case key.Matches(msg, keys.ForceAll, keys.Confirm, keys.Backup):
+		backup := key.Matches(msg, keys.Backup)
+		if m.needsTypedConfirmation() {
+			return m.openTypedConfirmation(backup)
+		}
+		return m.confirmForceDeletion(backup)
+
+	case key.Matches(msg, keys.ReviewEach):
+		return m.startForceReview()
+
+	case key.Matches(msg, keys.SkipAll, keys.Cancel), isPromptExit(msg):
+		// Skip unmerged branches and mark as done
+		return m.finishDeletion()
+	}
+
+	return m, nil
 }
 
-// forceDeleteBranches executes force deletion of unmerged branches
-func (m AppModel) forceDeleteBranches() tea.Msg {
-	for branch := range m.UnmergedBranches {
-		err := git.ForceDeleteBranch(branch)
-		if err != nil {
-			m.FailedBranches[branch] = err.Error()
-		} else {
-			m.DeletedCount++
-			delete(m.UnmergedBranches, branch)
-		}
+// confirmForceDeletion force deletes the unmerged branches, backing them up first with backup,
+// or plans their deletion in dry runs
+func (m AppModel) confirmForceDeletion(backup bool) (tea.Model, tea.Cmd) {
+	if m.DryRun {
+		m.planForceDeletion()
+		return m, nil
 	}
+	return m.startForceDeletion(m.orderedBranches(m.UnmergedBranches), backup)
+}
 
-	m.State = StateDone
-	return forceDeletionResultMsg(m)
+// selectedCount returns how many listed branches are selected
+func (m AppModel) selectedCount() int {
+	count := 0
+	for _, branch := range m.Branches {
+		if m.Selected[branch] {
+			count++
+		}
+	}
+	return count
 }
 
 func (m AppModel) hasSelectedBranches() bool {
@@ -171,10 +680,3 @@ func (m AppModel) hasSelectedBranches() bool {
 	}
 	return false
 }
-
-// isUnmergedError checks if an error message indicates unmerged changes
-func isUnmergedError(errMsg string) bool {
-	// Git typically returns errors containing "not fully merged" for unmerged branches
-	return strings.Contains(errMsg, "not fully merged") ||
-		strings.Contains(errMsg, "not merged")
-}
@@ -0,0 +1,40 @@
+package ui
+
+import "fmt"
+
+// upstreamBadge renders whether a local branch is backed up on its upstream: "origin ✓" when every commit
+// is pushed, "origin ↑2" with unpushed commits, "gone" when the upstream was deleted, and nothing for
+// branches without upstream. Commits only on the upstream are shown as "↓1" but lose nothing.
+func (m AppModel) upstreamBadge(branch string) string {
+	info, ok := m.BranchDetails[branch]
+	switch {
+	case !ok:
+		return ""
+	case info.Gone:
+		return " " + m.Styles.UpstreamGone.Render("gone")
+	case info.Upstream.Remote == "":
+		return ""
+	}
+
+	track := info.Track
+	switch {
+	case track.Ahead > 0 && track.Behind > 0:
+		return " " + m.Styles.UpstreamAhead.Render(fmt.Sprintf("%s ↑%d↓%d", info.Upstream.Remote, track.Ahead, track.Behind))
+	case track.Ahead > 0:
+		return " " + m.Styles.UpstreamAhead.Render(fmt.Sprintf("%s ↑%d", info.Upstream.Remote, track.Ahead))
+	case track.Behind > 0:
+		return " " + m.Styles.UpstreamSynced.Render(fmt.Sprintf("%s ↓%d", info.Upstream.Remote, track.Behind))
+	}
+	return " " + m.Styles.UpstreamSynced.Render(info.Upstream.Remote+" ✓")
+}
+
+// renderUnpushedWarning warns in the confirmation list that a branch has commits its upstream doesn't have,
+// which exist nowhere else once the branch is deleted
+func (m AppModel) renderUnpushedWarning(branch string) string {
+	info, ok := m.BranchDetails[branch]
+	if !ok || info.Gone || info.Upstream.Remote == "" || info.Track.Ahead == 0 {
+		return ""
+	}
+	warning := fmt.Sprintf("      ⚠ %d unpushed commit(s) not on %s: deleting the branch loses them", info.Track.Ahead, info.Upstream)
+	return m.Styles.Error.Render(warning) + "\n"
+}
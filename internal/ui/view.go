@@ -2,22 +2,70 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
+
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // View renders the UI based on the current model state
 func (m AppModel) View() string {
+	if m.CheckedOut != "" {
+		return m.Styles.Success.Render(m.t(i18n.SwitchedToBranch, m.CheckedOut)) + "\n"
+	}
+	if m.Planned {
+		// The plan is reported once it is written, after the program quits
+		return ""
+	}
+	if m.State == StateLoading {
+		return m.renderLoading()
+	}
+	return m.renderHeader() + m.renderState()
+}
+
+// t returns the message in the language of the model, formatted with args
+func (m AppModel) t(id i18n.MessageID, args ...any) string {
+	return m.Lang.T(id, args...)
+}
+
+// renderState renders the screen of the current state
+func (m AppModel) renderState() string {
 	switch m.State {
 	case StateSelection:
+		if m.PreviewOpen {
+			return m.renderPreview()
+		}
+		if m.CompareOpen {
+			return m.renderComparison()
+		}
 		return m.renderSelection()
-	case StateConfirmation:
-		return m.renderConfirmation()
-	case StateForceConfirmation:
-		return m.renderForceConfirmation()
 	case StateDeleting:
 		return m.renderDeleting()
 	case StateDone:
 		return m.renderDone()
+	case StateRestore:
+		return m.renderRestore()
+	}
+	return m.renderConfirmationState()
+}
+
+// renderConfirmationState renders the prompts shown before deletion starts
+func (m AppModel) renderConfirmationState() string {
+	switch m.State {
+	case StateConfirmation:
+		return m.renderConfirmation()
+	case StateWorktreeConfirmation:
+		return m.renderWorktreeConfirmation()
+	case StateLockedWorktreeConfirmation:
+		return m.renderLockedWorktreeConfirmation()
+	case StateCheckedOutConfirmation:
+		return m.renderCheckedOutConfirmation()
+	case StateForceConfirmation:
+		return m.renderForceConfirmation()
+	case StateForceReview:
+		return m.renderForceReview()
 	}
 	return ""
 }
@@ -25,122 +73,735 @@ func (m AppModel) View() string {
 func (m AppModel) renderSelection() string {
 	var b strings.Builder
 
-	b.WriteString(TitleStyle.Render("gelete - Interactive Branch Deletion"))
+	if m.Remote {
+		b.WriteString(m.Styles.Title.Render(m.t(i18n.RemoteSelectionTitle)))
+	} else {
+		b.WriteString(m.Styles.Title.Render(m.t(i18n.SelectionTitle)))
+	}
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.UnsetMarginTop().Render(m.statusLine()))
 	b.WriteString("\n\n")
 
 	if len(m.Branches) == 0 {
-		b.WriteString(HelpStyle.Render("No branches to delete."))
+		b.WriteString(m.Styles.Help.Render(m.t(i18n.NoBranchesToDelete)))
 		b.WriteString("\n\n")
-		b.WriteString(HelpStyle.Render("Press q to quit."))
+		b.WriteString(m.Styles.Help.Render(m.t(i18n.PressKeyToQuit, m.keyMap().Quit.Help().Key)))
 		return b.String()
 	}
 
-	for i, branch := range m.Branches {
-		cursor := "  "
-		if i == m.CursorIndex {
-			cursor = CursorStyle.Render("> ")
+	b.WriteString(m.renderInputLines())
+
+	rows := m.listRows()
+	if len(rows) == 0 {
+		b.WriteString(m.Styles.Help.UnsetMarginTop().Render(m.emptyFilterMessage()))
+		b.WriteString("\n")
+	}
+
+	list := m.renderViewport(rows)
+	if m.splitActive() {
+		list = m.renderSplit(list)
+	}
+	b.WriteString(list)
+
+	if m.ErrorMsg != "" {
+		b.WriteString("\n")
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.ErrorLine, m.ErrorMsg)))
+		b.WriteString("\n")
+	}
+	if m.SuccessMsg != "" {
+		b.WriteString("\n")
+		b.WriteString(m.Styles.Success.Render(m.SuccessMsg))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.Render(m.selectionFooterHelp()))
+	return b.String()
+}
+
+// renderViewport renders the rows of the list that fit the viewport, with a scroll indicator if some don't
+func (m AppModel) renderViewport(rows []listRow) string {
+	start, end := m.viewportRange(rows)
+	list := m.renderRows(rows[start:end], start)
+
+	if end-start < len(rows) {
+		list += m.Styles.Help.UnsetMarginTop().Render(m.t(i18n.ShowingRows, start+1, end, len(rows))) + "\n"
+	}
+	return list
+}
+
+// viewportRange returns the bounds of the rows in the list viewport: rows[start:end]
+func (m AppModel) viewportRange(rows []listRow) (int, int) {
+	start := min(m.ScrollOffset, len(rows))
+	return start, min(start+m.listHeight(), len(rows))
+}
+
+// renderRows renders the rows of the list viewport; start is the index of the first one.
+// In the grouped view, branches are indented under their group header.
+func (m AppModel) renderRows(rows []listRow, start int) string {
+	var branches []string
+	for _, row := range rows {
+		if !row.isHeader() {
+			branches = append(branches, row.branch)
 		}
+	}
+	columnWidth := m.branchColumnWidth(branches)
 
-		checkbox := "[ ]"
-		style := UnselectedItemStyle
-		if m.Selected[branch] {
-			checkbox = "[✓]"
-			style = SelectedItemStyle
+	var b strings.Builder
+	for i, row := range rows {
+		isCursor := start+i == m.CursorIndex
+		switch {
+		case row.isHeader():
+			b.WriteString(m.renderGroupHeader(row.group, isCursor))
+		case m.Grouped:
+			b.WriteString("  " + m.renderBranchRow(row.branch, isCursor, columnWidth))
+		default:
+			b.WriteString(m.renderBranchRow(row.branch, isCursor, columnWidth))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderInputLines renders the lines typed into above the branch list: the filter, the new name of the
+// branch being renamed and the selection pattern, each followed by a blank line
+func (m AppModel) renderInputLines() string {
+	var b strings.Builder
+	if m.Filtering || m.FilterQuery != "" {
+		b.WriteString(m.renderFilterLine())
+		b.WriteString("\n\n")
+	}
+	if m.Renaming != "" {
+		b.WriteString(m.Styles.Cursor.Render(m.t(i18n.RenamePrompt, m.Renaming) + m.RenameInput.View()))
+		b.WriteString("\n\n")
+	}
+	if m.SelectingPattern {
+		b.WriteString(m.Styles.Cursor.Render(m.t(i18n.SelectMatchingPrompt) + m.PatternInput.View()))
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
+
+// selectionFooterHelp returns the key help under the selection list, which depends on the line being typed
+func (m AppModel) selectionFooterHelp() string {
+	switch {
+	case m.Filtering:
+		return m.t(i18n.FilterHelp)
+	case m.Renaming != "":
+		return m.t(i18n.RenameHelp)
+	case m.SelectingPattern:
+		return m.t(i18n.PatternHelp)
+	}
+	return m.selectionHelp()
+}
+
+// selectionHelp returns the key help of the selection list; remote branches can't be checked out, renamed or compared,
+// the merge filter is only offered when the merge status is known, the mine filter when user.email is set, and copying when there is a clipboard
+func (m AppModel) selectionHelp() string {
+	keys := m.keyMap()
+	items := []string{
+		helpItem(keys.Up), helpItem(keys.Down), helpItemOf(m.t(i18n.HelpScroll), keys.PageUp, keys.PageDown, keys.Home, keys.End),
+		helpItem(keys.Toggle), helpItem(keys.SelectAll), helpItem(keys.Invert), helpItem(keys.SelectPattern), helpItem(keys.SelectWIP),
+		helpItem(keys.Sort), helpItem(keys.DateFormat), helpItem(keys.Filter),
+	}
+	if m.MergedBranches != nil {
+		items = append(items, helpItem(keys.MergeFilter))
+	}
+	if m.UserEmail != "" {
+		items = append(items, helpItem(keys.Mine))
+	}
+	if m.Grouped {
+		items = append(items, helpItemAs(keys.Group, m.t(i18n.HelpFlatList)),
+			m.t(i18n.HelpSelectGroup, keysExcept(keys.Toggle, keys.Collapse)),
+			m.t(i18n.HelpCollapseGroup, keys.Collapse.Help().Key))
+	} else {
+		items = append(items, helpItem(keys.Group))
+	}
+	if m.Width >= splitMinWidth {
+		items = append(items, helpItem(keys.SelectionPane))
+	}
+	items = append(items, helpItem(keys.Preview))
+	if !m.Remote {
+		items = append(items, helpItem(keys.Checkout), helpItem(keys.Rename), helpItem(keys.Compare))
+	}
+	if m.Clipboard != nil {
+		items = append(items, helpItem(keys.Copy))
+	}
+	if m.Loader != nil {
+		items = append(items, helpItem(keys.Refresh))
+	}
+	items = append(items, helpItem(keys.Delete))
+	if m.CanGoBack {
+		items = append(items, helpItem(keys.Back))
+	}
+	items = append(items, helpItem(keys.Quit))
+	return strings.Join(items, " • ")
+}
+
+// statusLine renders the selection count, HEAD state, merge base and filter, and sort order shown under the title
+func (m AppModel) statusLine() string {
+	status := m.t(i18n.StatusSelected, m.selectedCount(), len(m.Branches))
+	if m.Refreshing {
+		status += " • " + m.t(i18n.StatusRefreshing)
+	}
+	status += m.compareStatus()
+	if m.DetachedHead {
+		status += " • " + m.t(i18n.StatusDetachedHead)
+	}
+	if m.MergedBranches != nil {
+		status += " • " + m.t(i18n.StatusMergeBase, m.BaseBranch)
+		if m.MergeFilter != MergeFilterAll {
+			status += " " + m.t(i18n.StatusShowing, m.t(m.MergeFilter.message()))
 		}
+	}
+	if len(m.BranchDetails) > 0 {
+		status += " • " + m.t(i18n.StatusSortedBy, m.t(m.SortMode.message()))
+	}
+	return status
+}
 
-		branchDisplay := branch
-		if _, hasWorktree := m.BranchWorktrees[branch]; hasWorktree {
-			branchDisplay = branch + " " + WarningStyle.Render("[worktree]")
+// renderFilterLine renders the filter query input line
+func (m AppModel) renderFilterLine() string {
+	line := "/" + m.FilterQuery
+	if m.Filtering {
+		line += "█"
+	}
+	return m.Styles.Cursor.Render(line)
+}
+
+// renderBranchRow renders a single branch line in the selection list
+func (m AppModel) renderBranchRow(branch string, isCursor bool, columnWidth int) string {
+	cursor := "  "
+	if isCursor {
+		cursor = m.Styles.Cursor.Render("> ")
+	}
+
+	checkbox := "[ ]"
+	style := m.Styles.UnselectedItem
+	if m.Selected[branch] {
+		checkbox = "[✓]"
+		style = m.Styles.SelectedItem
+	}
+
+	branchDisplay := style.Render(ansi.Truncate(branch, maxBranchNameWidth, "…"))
+	if tag := m.worktreeTag(branch); tag != "" {
+		branchDisplay += " " + m.Styles.Warning.Render(tag)
+	}
+
+	row := fmt.Sprintf("%s%s %s", cursor, checkbox, branchDisplay)
+	badges := m.rowBadges(branch)
+	meta := m.branchMetadata(branch)
+	if badges != "" || meta != "" {
+		row += strings.Repeat(" ", columnWidth-lipgloss.Width(m.branchLabel(branch))) + "  "
+	}
+	return row + badges + m.Styles.Metadata.Render(meta) + m.upstreamBadge(branch) + m.wipBadge(branch) + m.pullRequestBadge(branch) + m.commitDateWarning(branch)
+}
+
+// commitDateWarning flags a branch whose tip has no commit date (e.g. a ref to a non-commit object).
+// Such branches are treated as infinitely old by --stale.
+func (m AppModel) commitDateWarning(branch string) string {
+	info, ok := m.BranchDetails[branch]
+	if !ok || !info.LastCommitDate.IsZero() {
+		return ""
+	}
+	return " " + m.Styles.Warning.Render(m.t(i18n.NoCommitDate))
+}
+
+// rowBadges renders the merge, divergence and author annotations of a branch, each followed by a space
+func (m AppModel) rowBadges(branch string) string {
+	var badges string
+	for _, badge := range []string{m.mergeBadge(branch), m.divergenceBadge(branch), m.authorBadge(branch)} {
+		if badge != "" {
+			badges += badge + " "
 		}
+	}
+	return badges
+}
+
+// divergenceBadge renders the ahead/behind counts of a branch, padded to a fixed width.
+// Branches with no commits ahead of the base are safe to delete and shown as such.
+// Returns an empty string when the counts weren't computed.
+func (m AppModel) divergenceBadge(branch string) string {
+	if m.Divergence == nil {
+		return ""
+	}
 
-		fmt.Fprintf(&b, "%s%s %s\n", cursor, checkbox, style.Render(branchDisplay))
+	counts, ok := m.Divergence[branch]
+	if !ok {
+		return fmt.Sprintf("%-10s", "")
 	}
 
+	text := fmt.Sprintf("%-10s", fmt.Sprintf("↑%d ↓%d", counts.Ahead, counts.Behind))
+	if counts.Ahead == 0 {
+		return m.Styles.Merged.Render(text)
+	}
+	return m.Styles.Metadata.Render(text)
+}
+
+// mergeBadge renders the merged/squashed/unmerged annotation for a branch, padded to the widest annotation
+// of the language so the columns after it line up. Returns an empty string when merge status is unknown.
+func (m AppModel) mergeBadge(branch string) string {
+	status, style := m.mergeStatus(branch)
+	if status == "" {
+		return ""
+	}
+	width := 0
+	for _, id := range []i18n.MessageID{i18n.BadgeMerged, i18n.BadgeSquashed, i18n.BadgeUnmerged} {
+		width = max(width, lipgloss.Width(m.t(id)))
+	}
+	return style.Render(padRight(status, width))
+}
+
+// padRight pads s with spaces to the given display width, counting wide characters like CJK as two columns
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-lipgloss.Width(s)))
+}
+
+// mergeStatus returns the merge annotation of a branch and its style, or "" when merge status is unknown
+func (m AppModel) mergeStatus(branch string) (string, lipgloss.Style) {
+	switch {
+	case m.MergedBranches == nil:
+		return "", lipgloss.Style{}
+	case m.MergedBranches[branch]:
+		return m.t(i18n.BadgeMerged), m.Styles.Merged
+	case m.SquashMerged[branch]:
+		return m.t(i18n.BadgeSquashed), m.Styles.Merged
+	}
+	return m.t(i18n.BadgeUnmerged), m.Styles.Unmerged
+}
+
+// maxBranchNameWidth caps the branch name column so long names don't push metadata off-screen
+const maxBranchNameWidth = 48
+
+// branchLabel returns the unstyled text shown for a branch in the selection list
+func (m AppModel) branchLabel(branch string) string {
+	label := ansi.Truncate(branch, maxBranchNameWidth, "…")
+	if tag := m.worktreeTag(branch); tag != "" {
+		label += " " + tag
+	}
+	return label
+}
+
+// worktreeTag returns the indicator for a branch checked out in a worktree,
+// or an empty string if it has none
+func (m AppModel) worktreeTag(branch string) string {
+	if m.StaleWorktrees[branch] {
+		return m.t(i18n.StaleWorktreeTag)
+	}
+	if _, hasWorktree := m.BranchWorktrees[branch]; hasWorktree {
+		return m.t(i18n.WorktreeTag)
+	}
+	return ""
+}
+
+// branchColumnWidth returns the display width of the widest branch label
+func (m AppModel) branchColumnWidth(branches []string) int {
+	width := 0
+	for _, branch := range branches {
+		width = max(width, lipgloss.Width(m.branchLabel(branch)))
+	}
+	return width
+}
+
+// branchMetadata returns the dimmed metadata shown to the right of a branch
+func (m AppModel) branchMetadata(branch string) string {
+	info, ok := m.BranchDetails[branch]
+	if !ok {
+		return ""
+	}
+
+	parts := []string{m.formatDate(info.LastCommitDate)}
+	if info.LastCommitter != "" {
+		parts = append(parts, info.LastCommitter)
+	}
+	return strings.Join(parts, " • ")
+}
+
+func (m AppModel) renderConfirmation() string {
+	var b strings.Builder
+
+	switch {
+	case m.Remote:
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.RemoteDeletionWarning)))
+		b.WriteString("\n")
+		b.WriteString(m.Styles.Confirmation.Render(m.t(i18n.RemoteDeletionQuestion)))
+	case m.ForceMode:
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.ForceDeletionWarning)))
+		b.WriteString("\n")
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.ForceDeletionQuestion)))
+	default:
+		b.WriteString(m.Styles.Confirmation.Render(m.t(i18n.DeletionQuestion)))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(m.renderDefaultBranchWarning())
+
+	b.WriteString(m.renderConfirmationList())
+
 	b.WriteString("\n")
-	b.WriteString(HelpStyle.Render("↑/k: up • ↓/j: down • space/enter: toggle • d: delete selected • q: quit"))
+	b.WriteString(m.Styles.Help.Render(m.t(i18n.ConfirmationTotal, m.Lang.Count(len(m.selectedBranches()), i18n.Branch))))
+	b.WriteString("\n\n")
+	if m.Remote {
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.RemoteFetchPruneWarning)))
+		b.WriteString("\n")
+	}
+	if m.ExpireReflog && !m.DryRun {
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.ExpireReflogWarning)))
+		b.WriteString("\n")
+	}
+	if m.DryRun {
+		b.WriteString(m.Styles.Help.Render(m.t(i18n.DryRunNote)))
+		b.WriteString("\n")
+	}
+	if m.PlanPath != "" {
+		b.WriteString(m.Styles.Help.Render(m.t(i18n.PlanNote, m.PlanPath)))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.renderConfirmationPrompt(m.confirmationHelp()))
 	return b.String()
 }
 
-func (m AppModel) renderConfirmation() string {
+// renderDefaultBranchWarning warns when the repository's default branch is about to be deleted,
+// which is only possible when its protection was lifted
+func (m AppModel) renderDefaultBranchWarning() string {
+	if m.DefaultBranch == "" || !m.Selected[m.DefaultBranch] {
+		return ""
+	}
+	return m.Styles.Error.Render(m.t(i18n.DefaultBranchWarning, m.DefaultBranch)) + "\n\n"
+}
+
+// confirmationHelp returns the key help of the confirmation list, offering only the toggles that apply
+func (m AppModel) confirmationHelp() string {
+	keys := m.keyMap()
+	var items []string
+	if m.hasConfirmationToggles() {
+		items = append(items, firstKeyLabel(keys.Up)+"/"+firstKeyLabel(keys.Down)+": "+m.t(i18n.HelpMove))
+	}
+	if len(m.Upstreams) > 0 {
+		items = append(items, helpItem(keys.ToggleRemote))
+	}
+	if len(m.BranchTags) > 0 {
+		items = append(items, helpItem(keys.ToggleTags))
+	}
+	switch {
+	case m.Remote:
+	case m.ForceMode:
+		items = append(items, helpItemAs(keys.ToggleForce, m.t(i18n.HelpDeleteSafely)))
+	default:
+		items = append(items, helpItem(keys.ToggleForce))
+	}
+	items = append(items, helpItem(keys.Confirm), helpItem(keys.Cancel))
+	return strings.Join(items, " • ")
+}
+
+// hasConfirmationToggles reports whether any selected branch offers something to delete along with it
+func (m AppModel) hasConfirmationToggles() bool {
+	return len(m.Upstreams) > 0 || len(m.BranchTags) > 0
+}
+
+// renderConfirmationRow renders a branch to be deleted, with an "also delete remote"
+// checkbox if the branch tracks a remote branch and an "also delete tags" checkbox if tags point at its tip
+func (m AppModel) renderConfirmationRow(branch string, isCursor bool) string {
+	cursor := "  "
+	if isCursor && m.hasConfirmationToggles() {
+		cursor = m.Styles.Cursor.Render("> ")
+	}
+
+	row := cursor + m.Styles.Warning.Render("• "+branch) + "\n"
+	if upstream, ok := m.Upstreams[branch]; ok {
+		checkbox := "[ ]"
+		if m.DeleteRemote[branch] {
+			checkbox = "[✓]"
+		}
+		row += m.Styles.Help.UnsetMarginTop().Render(fmt.Sprintf("      %s %s", checkbox, m.t(i18n.AlsoDelete, upstream))) + "\n"
+	}
+	return row + m.renderUnpushedWarning(branch) + m.renderPullRequestWarning(branch) + m.renderStashWarning(branch) + m.renderRefsWarning(branch) +
+		m.renderTagToggle(branch)
+}
+
+func (m AppModel) renderWorktreeConfirmation() string {
 	var b strings.Builder
 
-	b.WriteString(ConfirmationStyle.Render("Are you sure you want to delete these branches?"))
+	b.WriteString(m.Styles.Warning.Render(m.t(i18n.WorktreesTitle)))
 	b.WriteString("\n\n")
+	b.WriteString(m.t(i18n.WorktreesIntro) + "\n\n")
 
-	selectedCount := 0
+	worktrees := m.selectedWorktrees()
 	for _, branch := range m.Branches {
-		if m.Selected[branch] {
-			b.WriteString(WarningStyle.Render(fmt.Sprintf("  • %s", branch)))
+		if path, ok := worktrees[branch]; ok {
+			b.WriteString(m.worktreeBranchLine(branch, path))
+			b.WriteString(m.Styles.Help.UnsetMarginTop().Render(fmt.Sprintf("    %s (%s)", path, m.worktreeSizeLabel(path))))
 			b.WriteString("\n")
-			selectedCount++
+			b.WriteString(m.worktreeChangesLine(path))
 		}
 	}
 
 	b.WriteString("\n")
-	b.WriteString(HelpStyle.Render(fmt.Sprintf("Total: %d branch(es)", selectedCount)))
-	b.WriteString("\n\n")
-	b.WriteString(HelpStyle.Render("y: confirm • n: cancel"))
+	b.WriteString(m.Styles.Help.UnsetMarginTop().Render(m.worktreeTotalLine(worktrees)))
+	b.WriteString("\n")
+	dirty := len(m.dirtyWorktrees(worktrees))
+	if dirty > 0 {
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.DirtyWorktreesSkipped, m.Lang.Count(dirty, i18n.Worktree))))
+		b.WriteString("\n")
+	}
+	keys := m.keyMap()
+	b.WriteString(m.Styles.Help.Render(m.worktreeRemovalHelp(dirty, m.t(i18n.HelpRemoveWorktrees), m.t(i18n.HelpRemoveCleanWorktrees),
+		helpItemAs(keys.Cancel, m.t(i18n.HelpSkipBranches))+" • q: "+m.t(i18n.HelpBackToSelection))))
 	return b.String()
 }
 
-func (m AppModel) renderForceConfirmation() string {
+func (m AppModel) renderLockedWorktreeConfirmation() string {
 	var b strings.Builder
 
-	b.WriteString(ErrorStyle.Render("⚠ Warning: Unmerged Branches Detected"))
+	b.WriteString(m.Styles.Error.Render(m.t(i18n.LockedWorktreesTitle)))
 	b.WriteString("\n\n")
-	b.WriteString("The following branches have unmerged changes:\n\n")
+	b.WriteString(m.t(i18n.LockedWorktreesIntro) + "\n\n")
 
-	for branch, errMsg := range m.UnmergedBranches {
-		b.WriteString(WarningStyle.Render(fmt.Sprintf("  • %s", branch)))
-		b.WriteString("\n")
-		b.WriteString(HelpStyle.Render(fmt.Sprintf("    %s", errMsg)))
-		b.WriteString("\n")
+	for _, branch := range m.Branches {
+		if path, ok := m.LockedWorktrees[branch]; ok {
+			b.WriteString(m.worktreeBranchLine(branch, path))
+			b.WriteString(m.Styles.Help.UnsetMarginTop().Render(fmt.Sprintf("    %s (%s)", path, m.worktreeSizeLabel(path))))
+			b.WriteString("\n")
+			b.WriteString(m.worktreeChangesLine(path))
+		}
 	}
 
 	b.WriteString("\n")
-	b.WriteString(WarningStyle.Render(fmt.Sprintf("Force delete will permanently remove %d unmerged branch(es).", len(m.UnmergedBranches))))
-	b.WriteString("\n")
-	b.WriteString(ErrorStyle.Render("This action cannot be undone!"))
+	b.WriteString(m.Styles.Warning.Render(m.t(i18n.LockedWorktreesRemoval, m.Lang.Count(len(m.LockedWorktrees), i18n.LockedWorktree))))
 	b.WriteString("\n\n")
-	b.WriteString(HelpStyle.Render("y: force delete • n: cancel and skip these branches"))
+	keys := m.keyMap()
+	dirty := len(m.dirtyWorktrees(m.LockedWorktrees))
+	b.WriteString(m.Styles.Help.Render(m.worktreeRemovalHelp(dirty, m.t(i18n.HelpForceRemove), m.t(i18n.HelpForceRemoveClean),
+		helpItemAs(keys.Cancel, m.t(i18n.HelpSkipBranches)))))
 	return b.String()
 }
 
+func (m AppModel) renderForceConfirmation() string {
+	var b strings.Builder
+
+	if m.unmergedCount() == 0 {
+		b.WriteString(m.renderSquashMergedConfirmation())
+	} else {
+		b.WriteString(m.renderUnmergedConfirmation())
+	}
+
+	if m.DryRun {
+		b.WriteString(m.Styles.Help.Render(m.t(i18n.DryRunNote)))
+		b.WriteString("\n")
+	}
+	keys := m.keyMap()
+	b.WriteString(m.renderConfirmationPrompt(helpItemOf(m.t(i18n.HelpForceDeleteAll), keys.ForceAll, keys.Confirm) + " • " +
+		helpItem(keys.Backup) + " • " + helpItemAs(keys.ReviewEach, m.t(i18n.HelpReviewEach)) + " • " +
+		helpItemOf(m.t(i18n.HelpSkipAll), keys.SkipAll, keys.Cancel)))
+	return b.String()
+}
+
+// renderTipReachability renders the warning for an unmerged branch whose commits would become dangling,
+// or a note if that couldn't be checked
+func (m AppModel) renderTipReachability(branch string) string {
+	switch m.TipReachability[branch] {
+	case TipUnreachable:
+		return m.Styles.Error.Render("    "+m.t(i18n.TipUnreachable)) + "\n"
+	case TipUnchecked:
+		return m.Styles.Help.Render("    "+m.t(i18n.TipUnchecked, tipCheckTimeout)) + "\n"
+	}
+	return ""
+}
+
+// deletingChromeLines is the number of lines the deleting view uses around the result log
+// (title, earlier results note, progress line, blank line and help text)
+const deletingChromeLines = 7
+
 func (m AppModel) renderDeleting() string {
 	var b strings.Builder
-	b.WriteString(TitleStyle.Render("Deleting branches..."))
+	b.WriteString(m.Styles.Title.Render(m.t(i18n.DeletingTitle)))
 	b.WriteString("\n\n")
-	b.WriteString("Please wait...")
+	b.WriteString(m.renderResultLog())
+
+	if m.Current == "" {
+		b.WriteString(m.t(i18n.PleaseWait))
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("%s %s %d/%d: %s…", m.Spinner.View(), m.phaseLabel(), m.Progress+1, m.ProgressTotal, m.Current))
+	if m.WaitingForLock != "" {
+		b.WriteString(" " + m.Styles.Warning.Render(m.t(i18n.WaitingForLock, filepath.Base(m.WaitingForLock))))
+	}
+	b.WriteString("\n\n")
+	if m.Cancelled {
+		b.WriteString(m.Styles.Warning.Render(m.t(i18n.CancellingAfter, m.Current)))
+	} else {
+		b.WriteString(m.Styles.Help.Render(m.t(i18n.HelpCancelRun)))
+	}
+	return b.String()
+}
+
+// renderResultLog renders the outcomes of the running deletion as they come in.
+// Only the most recent ones are shown if the log doesn't fit the terminal.
+func (m AppModel) renderResultLog() string {
+	log := m.ResultLog
+	if m.Height > 0 {
+		log = log[max(0, len(log)-max(1, m.Height-deletingChromeLines-m.headerLines())):]
+	}
+
+	var b strings.Builder
+	if earlier := len(m.ResultLog) - len(log); earlier > 0 {
+		b.WriteString(m.Styles.Help.UnsetMarginTop().Render(m.t(i18n.EarlierResults, m.Lang.Count(earlier, i18n.EarlierResult))))
+		b.WriteString("\n")
+	}
+	for _, result := range log {
+		b.WriteString(m.renderResult(result))
+		b.WriteString("\n")
+	}
+	if len(log) > 0 {
+		b.WriteString("\n")
+	}
 	return b.String()
 }
 
+// phaseLabel describes the operation of the current deletion phase
+func (m AppModel) phaseLabel() string {
+	switch {
+	case m.phase == phaseRemoveWorktrees, m.phase == phaseForceRemoveWorktrees:
+		return m.t(i18n.PhaseRemovingWorktree)
+	case m.phase == phaseForceDelete:
+		return m.t(i18n.PhaseForceDeleting)
+	case m.phase == phaseBundle:
+		return m.t(i18n.PhaseWritingBundle)
+	case m.phase == phaseBackup:
+		return m.t(i18n.PhaseBackingUp)
+	case m.DryRun:
+		return m.t(i18n.PhaseChecking)
+	}
+	return m.t(i18n.PhaseDeleting)
+}
+
 func (m AppModel) renderDone() string {
+	if m.DryRun {
+		return m.renderDryRunSummary()
+	}
+
 	var b strings.Builder
 
-	b.WriteString(TitleStyle.Render("Deletion Complete"))
+	title := m.t(i18n.DoneTitle)
+	if m.Cancelled {
+		title = m.t(i18n.CancelledTitle)
+	}
+	b.WriteString(m.Styles.Title.Render(title))
+	b.WriteString("\n\n")
+
+	for _, result := range m.Results {
+		b.WriteString(m.renderResult(result))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.UnsetMarginTop().Render(m.t(i18n.DoneCounts,
+		m.countResults(ResultDeleted, ResultForceDeleted), m.countResults(ResultSkipped), m.countResults(ResultFailed))))
+	b.WriteString("\n")
+	if gone := m.alreadyGoneLine(); gone != "" {
+		b.WriteString(m.Styles.Help.UnsetMarginTop().Render(gone))
+		b.WriteString("\n")
+	}
+
+	if freed := m.freedSpaceLine(); freed != "" {
+		b.WriteString(m.Styles.Help.UnsetMarginTop().Render(freed))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.renderRemoteResults())
+	b.WriteString(m.renderTagResults())
+	b.WriteString(m.renderBackups())
+	b.WriteString(m.renderRestoreResults())
+	b.WriteString(m.renderCompaction())
+
+	if m.ErrorMsg != "" {
+		b.WriteString("\n")
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.ErrorLine, m.ErrorMsg)))
+	}
+	if m.AuditError != "" {
+		b.WriteString("\n")
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.WarningLine, m.AuditError)))
+	}
+
 	b.WriteString("\n\n")
+	b.WriteString(m.Styles.Help.Render(m.doneHelp()))
+	return b.String()
+}
 
-	if m.DeletedCount > 0 {
-		b.WriteString(SuccessStyle.Render(fmt.Sprintf("✓ Successfully deleted %d branch(es)", m.DeletedCount)))
+// renderRemoteResults renders the outcome of deleting upstreams along with local branches
+func (m AppModel) renderRemoteResults() string {
+	var b strings.Builder
+	if m.RemoteDeletedCount > 0 {
+		b.WriteString(m.Styles.Success.Render(m.t(i18n.AlsoDeletedRemote, m.Lang.Count(m.RemoteDeletedCount, i18n.RemoteBranch))))
 		b.WriteString("\n")
 	}
 
-	if len(m.FailedBranches) > 0 {
+	if len(m.RemoteFailures) > 0 {
 		b.WriteString("\n")
-		b.WriteString(ErrorStyle.Render(fmt.Sprintf("✗ Failed to delete %d branch(es):", len(m.FailedBranches))))
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.RemoteDeletionFailed, m.Lang.Count(len(m.RemoteFailures), i18n.RemoteBranch))))
 		b.WriteString("\n")
-		for branch, err := range m.FailedBranches {
-			b.WriteString(ErrorStyle.Render(fmt.Sprintf("  • %s: %s", branch, err)))
+		for _, branch := range m.orderedBranches(m.RemoteFailures) {
+			b.WriteString(m.Styles.Error.Render(fmt.Sprintf("  • %s: %s", branch, firstLine(m.RemoteFailures[branch]))))
 			b.WriteString("\n")
 		}
 	}
+	return b.String()
+}
 
-	if m.ErrorMsg != "" {
+func (m AppModel) renderDryRunSummary() string {
+	var b strings.Builder
+
+	b.WriteString(m.Styles.Title.Render(m.t(i18n.DryRunTitle)))
+	b.WriteString("\n\n")
+	b.WriteString(m.t(i18n.DryRunIntro) + "\n\n")
+
+	for _, action := range m.DryRunActions {
+		b.WriteString(m.dryRunActionLine(action))
+		b.WriteString("\n")
+	}
+
+	if failed := m.countResults(ResultFailed); failed > 0 {
 		b.WriteString("\n")
-		b.WriteString(ErrorStyle.Render(fmt.Sprintf("Error: %s", m.ErrorMsg)))
+		b.WriteString(m.Styles.Error.Render(m.t(i18n.DryRunCheckFailed, m.Lang.Count(failed, i18n.Branch))))
+		b.WriteString("\n")
+		for _, result := range m.Results {
+			if result.Status == ResultFailed {
+				b.WriteString(m.Styles.Error.Render(fmt.Sprintf("  • %s: %s", result.Branch, firstLine(result.Reason))))
+				b.WriteString("\n")
+			}
+		}
 	}
 
 	b.WriteString("\n\n")
-	b.WriteString(HelpStyle.Render("Press any key to exit."))
+	b.WriteString(m.Styles.Help.Render(m.t(i18n.PressAnyKey)))
 	return b.String()
 }
+
+func (m AppModel) dryRunActionLine(action DryRunAction) string {
+	var line string
+	switch {
+	case action.Remote:
+		line = m.Styles.Warning.Render("  • " + m.t(i18n.DryRunRemote, action.Branch))
+	case action.Merged:
+		line = m.Styles.Success.Render("  • " + m.t(i18n.DryRunMerged, action.Branch))
+	case action.Force:
+		line = m.Styles.Warning.Render("  • " + m.t(i18n.DryRunForce, action.Branch))
+	default:
+		line = m.Styles.Help.Render("  • " + m.t(i18n.DryRunSkip, action.Branch))
+	}
+
+	if action.WorktreePath != "" {
+		line += "\n" + m.Styles.Help.Render("    "+m.t(i18n.DryRunWorktree, action.WorktreePath))
+	}
+	if action.AlsoRemote != "" {
+		line += "\n" + m.Styles.Help.UnsetMarginTop().Render("    "+m.t(i18n.DryRunAlsoRemote, action.AlsoRemote))
+	}
+	if len(action.AlsoTags) > 0 && (action.Merged || action.Force) {
+		tags := i18n.DryRunAlsoTags
+		if len(action.AlsoTags) == 1 {
+			tags = i18n.DryRunAlsoTag
+		}
+		line += "\n" + m.Styles.Help.UnsetMarginTop().Render("    "+m.t(tags, strings.Join(action.AlsoTags, ", ")))
+	}
+	return line
+}
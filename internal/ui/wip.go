@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/Kdaito/gelete/internal/git"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultWIPPatterns are the WIP patterns of models without WIPPatterns; the defaults compile
+var defaultWIPPatterns, _ = git.CompileWIPPatterns(git.DefaultWIPPatterns)
+
+// wipDetectedMsg carries the branches whose own commits are all WIP commits
+type wipDetectedMsg struct {
+	wip map[string]bool
+}
+
+// selectWIP selects the visible WIP branches with the SelectWIP key (w). WIP branches are looked for in the
+// background the first time, since that takes a git command per branch; later presses reuse the result.
+func (m AppModel) selectWIP() (tea.Model, tea.Cmd) {
+	switch {
+	case m.DetectingWIP:
+		return m, nil
+	case m.WIPBranches != nil:
+		return m.applyWIPSelection()
+	}
+
+	m.DetectingWIP = true
+	m.SuccessMsg = "looking for WIP branches…"
+	return m, m.detectWIP()
+}
+
+// detectWIP returns a command that finds the branches whose own commits all have a subject matching WIPPatterns.
+// WIP detection is a convenience, so a branch whose commits can't be listed is just not flagged.
+func (m AppModel) detectWIP() tea.Cmd {
+	branches := append([]string(nil), m.Branches...)
+	base := m.previewBase()
+	remote := m.Remote
+	patterns := m.wipPatterns()
+	return func() tea.Msg {
+		wip := make(map[string]bool)
+		for _, branch := range branches {
+			// Local branches are looked up by their full ref, so a tag of the same name isn't used instead
+			revision := branch
+			if !remote {
+				revision = git.BranchRef(branch)
+			}
+			if isWIP, err := git.IsWIPBranch(revision, base, patterns); err == nil && isWIP {
+				wip[branch] = true
+			}
+		}
+		return wipDetectedMsg{wip: wip}
+	}
+}
+
+// wipPatterns returns WIPPatterns, or the default patterns if none were set
+func (m AppModel) wipPatterns() []*regexp.Regexp {
+	if m.WIPPatterns == nil {
+		return defaultWIPPatterns
+	}
+	return m.WIPPatterns
+}
+
+// handleWIPDetected stores the WIP branches, which shows their badge, and selects the visible ones
+func (m AppModel) handleWIPDetected(msg wipDetectedMsg) (tea.Model, tea.Cmd) {
+	if !m.DetectingWIP {
+		return m, nil
+	}
+	m.DetectingWIP = false
+	m.WIPBranches = msg.wip
+	if m.State != StateSelection {
+		m.SuccessMsg = ""
+		return m, nil
+	}
+	return m.applyWIPSelection()
+}
+
+// applyWIPSelection selects the visible WIP branches and tells how many there are
+func (m AppModel) applyWIPSelection() (tea.Model, tea.Cmd) {
+	selected := 0
+	for _, branch := range m.VisibleBranches() {
+		if m.WIPBranches[branch] {
+			m.Selected[branch] = true
+			selected++
+		}
+	}
+
+	m.recordSelectionOrder()
+	switch selected {
+	case 0:
+		m.SuccessMsg = "no WIP branches"
+	case 1:
+		m.SuccessMsg = "selected 1 WIP branch"
+	default:
+		m.SuccessMsg = fmt.Sprintf("selected %d WIP branches", selected)
+	}
+	if m.PersistSession && selected > 0 {
+		return m, m.saveSession()
+	}
+	return m, nil
+}
+
+// wipBadge renders "wip" for a branch whose own commits are all WIP commits, once WIP branches were looked for
+func (m AppModel) wipBadge(branch string) string {
+	if !m.WIPBranches[branch] {
+		return ""
+	}
+	return " " + m.Styles.Metadata.Render("wip")
+}
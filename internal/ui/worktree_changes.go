@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/Kdaito/gelete/internal/git"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// worktreeCheckedMsg carries the number of changed files of a worktree directory
+type worktreeCheckedMsg struct {
+	path    string
+	changed int
+	err     error
+}
+
+// checkWorktrees returns a command that looks for uncommitted changes in the worktrees, in parallel
+func checkWorktrees(paths map[string]string) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, path := range paths {
+		cmds = append(cmds, func() tea.Msg {
+			_, changed, err := git.WorktreeStatus(path)
+			return worktreeCheckedMsg{path: path, changed: changed, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleWorktreeChecked stores the number of changed files of a worktree. Worktrees that couldn't be checked,
+// e.g. because their directory is missing, are left out: git still refuses to remove them if they are dirty.
+func (m AppModel) handleWorktreeChecked(msg worktreeCheckedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return m, nil
+	}
+	if m.WorktreeChanges == nil {
+		m.WorktreeChanges = make(map[string]int)
+	}
+	m.WorktreeChanges[msg.path] = msg.changed
+	return m, nil
+}
+
+// dirtyWorktrees returns the worktrees with uncommitted changes among the given ones, keyed by branch name
+func (m AppModel) dirtyWorktrees(worktrees map[string]string) map[string]string {
+	dirty := make(map[string]string)
+	for branch, path := range worktrees {
+		if m.WorktreeChanges[path] > 0 {
+			dirty[branch] = path
+		}
+	}
+	return dirty
+}
+
+// removeWorktrees starts removing the worktrees of the selected branches. Worktrees with uncommitted changes
+// are only removed with discard, which discards the changes; otherwise their branches are skipped.
+func (m AppModel) removeWorktrees(discard bool) (tea.Model, tea.Cmd) {
+	m.DiscardChanges = discard
+	worktrees := m.selectedWorktrees()
+	if !discard {
+		for _, branch := range m.orderedBranches(m.dirtyWorktrees(worktrees)) {
+			m.recordResult(DeletionResult{Branch: branch, Status: ResultSkipped, Reason: "worktree has uncommitted changes"})
+			delete(worktrees, branch)
+		}
+	}
+	return m.startPhase(phaseRemoveWorktrees, m.orderedBranches(worktrees))
+}
+
+// forceRemoveLockedWorktrees starts force removing the locked worktrees. Like removeWorktrees, those with
+// uncommitted changes are only removed with discard; otherwise their branches are skipped.
+func (m AppModel) forceRemoveLockedWorktrees(discard bool) (tea.Model, tea.Cmd) {
+	m.DiscardChanges = m.DiscardChanges || discard
+	locked := m.LockedWorktrees
+	if !m.DiscardChanges {
+		for _, branch := range m.orderedBranches(m.dirtyWorktrees(locked)) {
+			m.recordResult(DeletionResult{Branch: branch, Status: ResultSkipped, Reason: "locked worktree has uncommitted changes"})
+			delete(locked, branch)
+		}
+	}
+	return m.startPhase(phaseForceRemoveWorktrees, m.orderedBranches(locked))
+}
+
+// removeWorktreeCmd returns the command removing the worktree of a branch in phaseRemoveWorktrees,
+// discarding its changes once the user confirmed that
+func (m AppModel) removeWorktreeCmd(branch string) tea.Cmd {
+	ctx := m.ctx
+	path := m.BranchWorktrees[branch]
+	remove := m.Repo.RemoveWorktree
+	if m.DiscardChanges && m.WorktreeChanges[path] > 0 {
+		remove = git.RemoveDirtyWorktreeContext
+	}
+	return func() tea.Msg { return worktreeRemovedMsg{branch: branch, err: remove(ctx, path)} }
+}
+
+// worktreeChangesLine warns about the uncommitted changes of a worktree for the removal prompts,
+// or returns "" if it is clean or not checked yet
+func (m AppModel) worktreeChangesLine(path string) string {
+	changed := m.WorktreeChanges[path]
+	if changed == 0 {
+		return ""
+	}
+	return m.Styles.Error.Render(fmt.Sprintf("    %d modified or untracked file(s) would be lost", changed)) + "\n"
+}
+
+// worktreeBranchLine renders the branch of a worktree in the removal prompts, in red if the worktree is dirty
+func (m AppModel) worktreeBranchLine(branch, path string) string {
+	style := m.Styles.Warning
+	if m.WorktreeChanges[path] > 0 {
+		style = m.Styles.Error
+	}
+	return style.Render(fmt.Sprintf("  • %s", branch)) + "\n"
+}
+
+// worktreeRemovalHelp returns the help line of a worktree removal prompt: with dirty worktrees among them,
+// confirming only removes the clean ones (confirmClean) and the DiscardChanges key removes all of them
+func (m AppModel) worktreeRemovalHelp(dirty int, confirm, confirmClean, rest string) string {
+	keys := m.keyMap()
+	if dirty == 0 || m.DiscardChanges {
+		return helpItemAs(keys.Confirm, confirm) + " • " + rest
+	}
+	return helpItemAs(keys.Confirm, confirmClean) + " • " + helpItem(keys.DiscardChanges) + " • " + rest
+}
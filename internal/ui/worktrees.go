@@ -0,0 +1,506 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/Kdaito/gelete/pkg/gitops"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WorktreeState represents the current state of the worktree cleanup UI
+type WorktreeState int
+
+const (
+	// WorktreeStateSelection: User is selecting worktrees to remove
+	WorktreeStateSelection WorktreeState = iota
+	// WorktreeStateConfirmation: User is confirming removal of the selected worktrees
+	WorktreeStateConfirmation
+	// WorktreeStateLockedConfirmation: User is confirming force removal of locked worktrees
+	WorktreeStateLockedConfirmation
+	// WorktreeStateBranchConfirmation: User is deciding, branch by branch, whether to delete the branches of removed worktrees
+	WorktreeStateBranchConfirmation
+	// WorktreeStateForceConfirmation: User is confirming force deletion of freed branches git refused as unmerged
+	WorktreeStateForceConfirmation
+	// WorktreeStateRunning: Worktrees or branches are being removed
+	WorktreeStateRunning
+	// WorktreeStateDone: Cleanup complete or cancelled
+	WorktreeStateDone
+)
+
+// worktreePhase identifies the operation the worktree cleanup queue is running
+type worktreePhase int
+
+const (
+	// worktreePhaseRemove removes worktrees (git worktree remove), pruning those whose directory is gone
+	worktreePhaseRemove worktreePhase = iota
+	// worktreePhaseForceRemove force removes locked worktrees
+	worktreePhaseForceRemove
+	// worktreePhaseDeleteBranches deletes the branches freed by removing their worktrees (git branch -d)
+	worktreePhaseDeleteBranches
+	// worktreePhaseForceDeleteBranches force deletes the unmerged freed branches the user confirmed (git branch -D)
+	worktreePhaseForceDeleteBranches
+)
+
+// WorktreeItem is a linked worktree offered for removal
+type WorktreeItem struct {
+	git.Worktree
+
+	// Modified is the last modification time of the worktree directory (zero if it is missing)
+	Modified time.Time
+}
+
+// WorktreeModel is the worktree cleanup UI: a checkbox list of linked worktrees, like AppModel's branch list.
+// After removing worktrees it offers to delete the branches that were checked out in them.
+type WorktreeModel struct {
+	// Repo is the repository worktrees and branches are removed in; the zero Repo uses the opened repository
+	Repo gitops.Repo
+
+	// Worktrees contains the linked worktrees; the main worktree is never listed
+	Worktrees []WorktreeItem
+
+	// Selected tracks which worktrees are selected for removal (path -> bool)
+	Selected map[string]bool
+
+	// CursorIndex is the current cursor position in the worktree list
+	CursorIndex int
+
+	// State represents the current UI state
+	State WorktreeState
+
+	// Removed lists the paths of removed worktrees, in list order
+	Removed []string
+
+	// Failures maps worktree paths and branch names to the error that prevented their removal
+	Failures map[string]string
+
+	// LockedWorktrees lists the selected worktrees refused because they are locked, awaiting force removal
+	LockedWorktrees []string
+
+	// MainBranch is the branch checked out in the main worktree, which is never offered for deletion
+	MainBranch string
+
+	// BaseBranch is the branch merges are checked against, shown with the freed branches (empty if unknown)
+	BaseBranch string
+
+	// MergedBranches tracks which branches are merged into BaseBranch (nil if unknown)
+	MergedBranches map[string]bool
+
+	// FreedBranches lists the branches of removed worktrees offered for deletion: those no other worktree
+	// has checked out, except protected branches
+	FreedBranches []string
+
+	// BranchCursor is the index in FreedBranches of the branch the user is asked about
+	BranchCursor int
+
+	// ConfirmedBranches lists the freed branches the user chose to delete, deleted together once all are answered
+	ConfirmedBranches []string
+
+	// UnmergedBranches lists the confirmed branches git refused to delete as not fully merged, awaiting force deletion
+	UnmergedBranches []string
+
+	// KeptBranches lists the freed branches the user chose to keep, including unmerged ones not force deleted
+	KeptBranches []string
+
+	// DeletedBranches lists the freed branches that were deleted
+	DeletedBranches []string
+
+	// Cancelled is set once the user interrupted a running cleanup with ctrl+c
+	Cancelled bool
+
+	// Interrupted is the signal that made the program quit, e.g. SIGINT (nil if none; see InterruptMsg)
+	Interrupted os.Signal
+
+	// Queue holds the worktree paths or branches still to be processed in the current phase
+	Queue []string
+
+	// Current is the worktree path or branch being processed
+	Current string
+
+	// Progress and ProgressTotal count the processed and total operations of the current phase
+	Progress      int
+	ProgressTotal int
+
+	// Spinner animates the running view
+	Spinner spinner.Model
+
+	// Styles holds the lipgloss styles used for rendering
+	Styles Styles
+
+	// Lang is the language of the messages (the zero Lang is English)
+	Lang i18n.Lang
+
+	phase  worktreePhase
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// worktreeItemRemovedMsg reports the result of removing one selected worktree
+type worktreeItemRemovedMsg struct {
+	path string
+	err  error
+}
+
+// freedBranchDeletedMsg reports the result of deleting the branch of a removed worktree
+type freedBranchDeletedMsg struct {
+	branch string
+	err    error
+}
+
+// Init initializes the bubbletea model
+func (m WorktreeModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the model state
+func (m WorktreeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case worktreeItemRemovedMsg:
+		return m.handleItemRemoved(msg)
+	case freedBranchDeletedMsg:
+		return m.handleFreedBranchDeleted(msg)
+	case deletionCompleteMsg:
+		return m.handlePhaseComplete()
+	case InterruptMsg:
+		return m.handleInterrupt(msg)
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.Spinner, cmd = m.Spinner.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+
+	return m, nil
+}
+
+// handleKey dispatches keyboard input to the handler for the current state
+func (m WorktreeModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.State {
+	case WorktreeStateSelection:
+		return m.handleSelectionInput(msg)
+	case WorktreeStateConfirmation:
+		return m.handleYesNo(msg, m.startRemoval, m.backToSelection)
+	case WorktreeStateLockedConfirmation:
+		return m.handleYesNo(msg, m.startForceRemoval, m.offerFreedBranches)
+	case WorktreeStateBranchConfirmation:
+		return m.handleYesNo(msg, func() (tea.Model, tea.Cmd) { return m.answerFreedBranch(true) },
+			func() (tea.Model, tea.Cmd) { return m.answerFreedBranch(false) })
+	case WorktreeStateForceConfirmation:
+		return m.handleYesNo(msg, m.startForceBranchDeletion, m.keepUnmergedBranches)
+	case WorktreeStateRunning:
+		return m.handleRunningInput(msg)
+	case WorktreeStateDone:
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+// handleSelectionInput handles keyboard input in the selection state
+func (m WorktreeModel) handleSelectionInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		m.CursorIndex = max(0, m.CursorIndex-1)
+	case "down", "j":
+		m.CursorIndex = min(len(m.Worktrees)-1, m.CursorIndex+1)
+	case " ", "enter":
+		if len(m.Worktrees) > 0 {
+			path := m.Worktrees[m.CursorIndex].Path
+			m.Selected[path] = !m.Selected[path]
+		}
+	case "a":
+		all := m.selectedCount() < len(m.Worktrees)
+		for _, wt := range m.Worktrees {
+			m.Selected[wt.Path] = all
+		}
+	case "d":
+		if m.selectedCount() > 0 {
+			m.State = WorktreeStateConfirmation
+		}
+	}
+
+	return m, nil
+}
+
+// handleYesNo runs yes on "y" and no on "n" or esc; ctrl+c quits
+func (m WorktreeModel) handleYesNo(msg tea.KeyMsg, yes, no func() (tea.Model, tea.Cmd)) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		return yes()
+	case "n", "N", "esc":
+		return no()
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// handleRunningInput handles keyboard input while worktrees or branches are being removed.
+// The first ctrl+c cancels the cleanup; a second one quits without waiting for it.
+func (m WorktreeModel) handleRunningInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() != "ctrl+c" {
+		return m, nil
+	}
+	if m.Cancelled {
+		return m, tea.Quit
+	}
+	m.Cancelled = true
+	m.Queue = nil
+	if m.cancel != nil {
+		m.cancel()
+	}
+	return m, nil
+}
+
+// backToSelection returns to the worktree list without removing anything
+func (m WorktreeModel) backToSelection() (tea.Model, tea.Cmd) {
+	m.State = WorktreeStateSelection
+	return m, nil
+}
+
+// startRemoval removes the selected worktrees in list order
+func (m WorktreeModel) startRemoval() (tea.Model, tea.Cmd) {
+	m.Removed = nil
+	m.Failures = make(map[string]string)
+	m.LockedWorktrees = nil
+	m.FreedBranches = nil
+	m.ConfirmedBranches = nil
+	m.UnmergedBranches = nil
+	m.KeptBranches = nil
+	m.DeletedBranches = nil
+
+	var paths []string
+	for _, wt := range m.Worktrees {
+		if m.Selected[wt.Path] {
+			paths = append(paths, wt.Path)
+		}
+	}
+	return m.startPhase(worktreePhaseRemove, paths)
+}
+
+// startForceRemoval force removes the locked worktrees the user confirmed
+func (m WorktreeModel) startForceRemoval() (tea.Model, tea.Cmd) {
+	locked := m.LockedWorktrees
+	m.LockedWorktrees = nil
+	return m.startPhase(worktreePhaseForceRemove, locked)
+}
+
+// answerFreedBranch records whether to delete the freed branch the user was asked about and asks about the next one.
+// Once every branch is answered, the confirmed ones are deleted together.
+func (m WorktreeModel) answerFreedBranch(deleteBranch bool) (tea.Model, tea.Cmd) {
+	branch := m.FreedBranches[m.BranchCursor]
+	if deleteBranch {
+		m.ConfirmedBranches = append(m.ConfirmedBranches, branch)
+	} else {
+		m.KeptBranches = append(m.KeptBranches, branch)
+	}
+
+	m.BranchCursor++
+	if m.BranchCursor < len(m.FreedBranches) {
+		return m, nil
+	}
+	if len(m.ConfirmedBranches) == 0 {
+		return m.finish()
+	}
+	return m.startPhase(worktreePhaseDeleteBranches, m.ConfirmedBranches)
+}
+
+// startForceBranchDeletion force deletes the unmerged branches the user confirmed
+func (m WorktreeModel) startForceBranchDeletion() (tea.Model, tea.Cmd) {
+	return m.startPhase(worktreePhaseForceDeleteBranches, m.UnmergedBranches)
+}
+
+// keepUnmergedBranches keeps the branches git refused to delete as unmerged and ends the cleanup
+func (m WorktreeModel) keepUnmergedBranches() (tea.Model, tea.Cmd) {
+	m.KeptBranches = append(m.KeptBranches, m.UnmergedBranches...)
+	return m.finish()
+}
+
+// startPhase queues worktree paths or branches for the given phase and issues the command for the first one
+func (m WorktreeModel) startPhase(phase worktreePhase, items []string) (tea.Model, tea.Cmd) {
+	m.State = WorktreeStateRunning
+	m.phase = phase
+	if m.cancel == nil {
+		m.ctx, m.cancel = context.WithCancel(context.Background())
+	}
+	m.Queue = items
+	m.Progress = 0
+	m.ProgressTotal = len(items)
+	m.Spinner = spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(m.Styles.Cursor))
+
+	cmd := m.nextOperation()
+	return m, tea.Batch(m.Spinner.Tick, cmd)
+}
+
+// nextOperation pops the next item off the queue and returns the command processing it,
+// or a command reporting completion when the queue is empty
+func (m *WorktreeModel) nextOperation() tea.Cmd {
+	if len(m.Queue) == 0 {
+		m.Current = ""
+		return func() tea.Msg { return deletionCompleteMsg{} }
+	}
+
+	m.Current = m.Queue[0]
+	m.Queue = m.Queue[1:]
+	return m.operationCmd(m.Current)
+}
+
+// operationCmd returns the command that performs the current phase's operation on a worktree path or branch.
+// Prunable worktrees (e.g. whose directory is gone) can't be removed, so their administrative data is pruned instead.
+func (m WorktreeModel) operationCmd(item string) tea.Cmd {
+	ctx := m.ctx
+	switch m.phase {
+	case worktreePhaseForceRemove:
+		return func() tea.Msg {
+			return worktreeItemRemovedMsg{path: item, err: git.ForceRemoveWorktreeContext(ctx, item)}
+		}
+	case worktreePhaseDeleteBranches:
+		return func() tea.Msg {
+			return freedBranchDeletedMsg{branch: item, err: git.RetryOnLock(ctx, item, m.Repo.DeleteBranch, nil)}
+		}
+	case worktreePhaseForceDeleteBranches:
+		return func() tea.Msg {
+			return freedBranchDeletedMsg{branch: item, err: git.RetryOnLock(ctx, item, m.Repo.ForceDeleteBranch, nil)}
+		}
+	}
+
+	if wt, ok := m.worktree(item); ok && wt.Prunable {
+		return func() tea.Msg { return worktreeItemRemovedMsg{path: item, err: git.PruneWorktreesContext(ctx)} }
+	}
+	return func() tea.Msg { return worktreeItemRemovedMsg{path: item, err: m.Repo.RemoveWorktree(ctx, item)} }
+}
+
+// handleItemRemoved records a worktree removal result and continues with the next worktree.
+// Locked worktrees are collected for an explicit force-removal prompt.
+func (m WorktreeModel) handleItemRemoved(msg worktreeItemRemovedMsg) (tea.Model, tea.Cmd) {
+	m.Progress++
+
+	switch {
+	case msg.err == nil:
+		m.Removed = append(m.Removed, msg.path)
+	case m.phase == worktreePhaseRemove && strings.Contains(msg.err.Error(), "locked"):
+		m.LockedWorktrees = append(m.LockedWorktrees, msg.path)
+	default:
+		m.Failures[msg.path] = msg.err.Error()
+	}
+
+	return m, m.nextOperation()
+}
+
+// handleFreedBranchDeleted records a branch deletion result and continues with the next branch.
+// Branches git refuses as unmerged are collected for an explicit force-deletion prompt.
+func (m WorktreeModel) handleFreedBranchDeleted(msg freedBranchDeletedMsg) (tea.Model, tea.Cmd) {
+	m.Progress++
+
+	switch {
+	case msg.err == nil:
+		m.DeletedBranches = append(m.DeletedBranches, msg.branch)
+	case m.phase == worktreePhaseDeleteBranches && git.IsUnmergedError(msg.err):
+		m.UnmergedBranches = append(m.UnmergedBranches, msg.branch)
+	default:
+		m.Failures[msg.branch] = msg.err.Error()
+	}
+
+	return m, m.nextOperation()
+}
+
+// handlePhaseComplete moves to the next prompt once the queue is drained
+func (m WorktreeModel) handlePhaseComplete() (tea.Model, tea.Cmd) {
+	switch {
+	case m.Cancelled, m.phase == worktreePhaseForceDeleteBranches:
+		return m.finish()
+	case m.phase == worktreePhaseDeleteBranches:
+		if len(m.UnmergedBranches) == 0 {
+			return m.finish()
+		}
+		m.State = WorktreeStateForceConfirmation
+		return m, nil
+	case len(m.LockedWorktrees) > 0:
+		m.State = WorktreeStateLockedConfirmation
+		return m, nil
+	}
+	return m.offerFreedBranches()
+}
+
+// offerFreedBranches asks, branch by branch, whether to delete the branches that were checked out in the removed
+// worktrees. Branches still checked out in another worktree and protected branches are never offered;
+// the cleanup finishes if no branch is left.
+func (m WorktreeModel) offerFreedBranches() (tea.Model, tea.Cmd) {
+	m.LockedWorktrees = nil
+	m.FreedBranches = nil
+	m.BranchCursor = 0
+	for _, wt := range m.Worktrees {
+		if wt.Branch != "" && slices.Contains(m.Removed, wt.Path) && !git.IsProtected(wt.Branch) &&
+			!m.checkedOutElsewhere(wt.Branch) && !slices.Contains(m.FreedBranches, wt.Branch) {
+			m.FreedBranches = append(m.FreedBranches, wt.Branch)
+		}
+	}
+
+	if len(m.FreedBranches) == 0 {
+		return m.finish()
+	}
+	m.State = WorktreeStateBranchConfirmation
+	return m, nil
+}
+
+// checkedOutElsewhere reports whether the branch is checked out in the main worktree or a worktree that wasn't removed,
+// e.g. one added with git worktree add --force, so it can't be deleted yet
+func (m WorktreeModel) checkedOutElsewhere(branch string) bool {
+	if branch == m.MainBranch {
+		return true
+	}
+	return slices.ContainsFunc(m.Worktrees, func(wt WorktreeItem) bool {
+		return wt.Branch == branch && !slices.Contains(m.Removed, wt.Path)
+	})
+}
+
+// finish ends the cleanup
+func (m WorktreeModel) finish() (tea.Model, tea.Cmd) {
+	m.State = WorktreeStateDone
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.ctx, m.cancel = nil, nil
+	return m, nil
+}
+
+// WasCancelled reports whether the user cancelled with worktrees still to remove: by quitting before the
+// removal started while worktrees were selected, or by cancelling the running cleanup
+func (m WorktreeModel) WasCancelled() bool {
+	beforeRemoval := m.State == WorktreeStateSelection || m.State == WorktreeStateConfirmation
+	return m.Cancelled || (beforeRemoval && m.selectedCount() > 0)
+}
+
+// HasFailures reports whether removing a worktree or deleting a freed branch failed
+func (m WorktreeModel) HasFailures() bool {
+	return len(m.Failures) > 0
+}
+
+// worktree returns the listed worktree at path
+func (m WorktreeModel) worktree(path string) (WorktreeItem, bool) {
+	i := slices.IndexFunc(m.Worktrees, func(wt WorktreeItem) bool { return wt.Path == path })
+	if i < 0 {
+		return WorktreeItem{}, false
+	}
+	return m.Worktrees[i], true
+}
+
+// selectedCount returns the number of selected worktrees
+func (m WorktreeModel) selectedCount() int {
+	count := 0
+	for _, wt := range m.Worktrees {
+		if m.Selected[wt.Path] {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,256 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// t returns the message in the language of the model, formatted with args
+func (m WorktreeModel) t(id i18n.MessageID, args ...any) string {
+	return m.Lang.T(id, args...)
+}
+
+// View renders the worktree cleanup UI based on the current state
+func (m WorktreeModel) View() string {
+	switch m.State {
+	case WorktreeStateSelection:
+		return m.renderSelection()
+	case WorktreeStateConfirmation:
+		return m.renderConfirmation()
+	case WorktreeStateLockedConfirmation:
+		return m.renderLockedConfirmation()
+	case WorktreeStateBranchConfirmation:
+		return m.renderBranchConfirmation()
+	case WorktreeStateForceConfirmation:
+		return m.renderForceConfirmation()
+	case WorktreeStateRunning:
+		return m.renderRunning()
+	case WorktreeStateDone:
+		return m.renderDone()
+	}
+	return ""
+}
+
+func (m WorktreeModel) renderSelection() string {
+	var b strings.Builder
+
+	b.WriteString(m.Styles.Title.Render(m.t(i18n.WorktreeSelectionTitle)))
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.UnsetMarginTop().Render(m.t(i18n.StatusSelected, m.selectedCount(), len(m.Worktrees))))
+	b.WriteString("\n\n")
+
+	if len(m.Worktrees) == 0 {
+		b.WriteString(m.Styles.Help.Render(m.t(i18n.NoWorktreesToRemove)))
+		b.WriteString("\n\n")
+		b.WriteString(m.Styles.Help.Render(m.t(i18n.PressKeyToQuit, "q")))
+		return b.String()
+	}
+
+	pathWidth := 0
+	for _, wt := range m.Worktrees {
+		pathWidth = max(pathWidth, lipgloss.Width(wt.Path))
+	}
+	for i, wt := range m.Worktrees {
+		b.WriteString(m.renderWorktreeRow(wt, i == m.CursorIndex, pathWidth))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.Render(m.t(i18n.WorktreeSelectionHelp)))
+	return b.String()
+}
+
+// renderWorktreeRow renders a worktree with its checkbox, branch, lock status and modification time
+func (m WorktreeModel) renderWorktreeRow(wt WorktreeItem, isCursor bool, pathWidth int) string {
+	cursor := "  "
+	if isCursor {
+		cursor = m.Styles.Cursor.Render("> ")
+	}
+
+	checkbox := "[ ]"
+	style := m.Styles.UnselectedItem
+	if m.Selected[wt.Path] {
+		checkbox = "[✓]"
+		style = m.Styles.SelectedItem
+	}
+
+	padding := strings.Repeat(" ", pathWidth-lipgloss.Width(wt.Path))
+	row := fmt.Sprintf("%s%s %s%s  %s", cursor, checkbox, style.Render(wt.Path), padding, m.Styles.Metadata.Render(m.worktreeBranchLabel(wt)))
+	if wt.Locked {
+		row += " " + m.Styles.Warning.Render(m.t(i18n.WorktreeLockedTag))
+	}
+	if wt.Missing {
+		row += " " + m.Styles.Warning.Render(m.t(i18n.WorktreeMissingTag))
+	} else if !wt.Modified.IsZero() {
+		row += " " + m.Styles.Metadata.Render(m.t(i18n.WorktreeModified, wt.Modified.Format("2006-01-02 15:04")))
+	}
+	return row
+}
+
+// worktreeBranchLabel returns the branch checked out in a worktree, or a note if HEAD is detached
+func (m WorktreeModel) worktreeBranchLabel(wt WorktreeItem) string {
+	if wt.Branch == "" {
+		return m.t(i18n.WorktreeDetachedHead)
+	}
+	return wt.Branch
+}
+
+func (m WorktreeModel) renderConfirmation() string {
+	var b strings.Builder
+
+	b.WriteString(m.Styles.Confirmation.Render(m.t(i18n.WorktreeRemovalQuestion)))
+	b.WriteString("\n\n")
+
+	for _, wt := range m.Worktrees {
+		if m.Selected[wt.Path] {
+			b.WriteString(m.Styles.Warning.Render(fmt.Sprintf("  • %s (%s)", wt.Path, m.worktreeBranchLabel(wt))))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.Render(m.t(i18n.ConfirmationTotal, m.Lang.Count(m.selectedCount(), i18n.Worktree))))
+	b.WriteString("\n\n")
+	b.WriteString(m.Styles.Help.Render(m.t(i18n.WorktreeConfirmHelp)))
+	return b.String()
+}
+
+func (m WorktreeModel) renderLockedConfirmation() string {
+	var b strings.Builder
+
+	b.WriteString(m.Styles.Error.Render(m.t(i18n.WorktreeLockedTitle)))
+	b.WriteString("\n\n")
+
+	for _, path := range m.LockedWorktrees {
+		b.WriteString(m.Styles.Warning.Render(fmt.Sprintf("  • %s", path)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Warning.Render(m.t(i18n.LockedWorktreesRemoval, m.Lang.Count(len(m.LockedWorktrees), i18n.LockedWorktree))))
+	b.WriteString("\n\n")
+	b.WriteString(m.Styles.Help.Render(m.t(i18n.WorktreeLockedHelp)))
+	return b.String()
+}
+
+func (m WorktreeModel) renderBranchConfirmation() string {
+	var b strings.Builder
+
+	branch := m.FreedBranches[m.BranchCursor]
+	b.WriteString(m.Styles.Confirmation.Render(m.t(i18n.FreedBranchQuestion, branch)))
+	b.WriteString("\n\n")
+
+	b.WriteString(m.Styles.Metadata.Render("  " + m.t(i18n.FreedBranchProgress, m.BranchCursor+1, len(m.FreedBranches))))
+	b.WriteString("\n")
+	if merged, known := m.mergeStatus(branch); known && merged {
+		b.WriteString(m.Styles.Success.Render("  " + m.t(i18n.FreedBranchMerged, m.BaseBranch)))
+		b.WriteString("\n")
+	} else if known {
+		b.WriteString(m.Styles.Warning.Render("  " + m.t(i18n.FreedBranchUnmerged, m.BaseBranch)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.Render(m.t(i18n.FreedBranchHelp)))
+	return b.String()
+}
+
+// mergeStatus reports whether the branch is merged into the base branch, and whether that is known
+func (m WorktreeModel) mergeStatus(branch string) (merged, known bool) {
+	if m.MergedBranches == nil || m.BaseBranch == "" {
+		return false, false
+	}
+	return m.MergedBranches[branch], true
+}
+
+func (m WorktreeModel) renderForceConfirmation() string {
+	var b strings.Builder
+
+	b.WriteString(m.Styles.Error.Render(m.t(i18n.FreedUnmergedTitle)))
+	b.WriteString("\n\n")
+
+	for _, branch := range m.UnmergedBranches {
+		b.WriteString(m.Styles.Warning.Render(fmt.Sprintf("  • %s", branch)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Warning.Render(m.t(i18n.FreedUnmergedRemoval, m.Lang.Count(len(m.UnmergedBranches), i18n.Branch))))
+	b.WriteString("\n\n")
+	b.WriteString(m.Styles.Help.Render(m.t(i18n.FreedUnmergedHelp)))
+	return b.String()
+}
+
+func (m WorktreeModel) renderRunning() string {
+	var b strings.Builder
+
+	b.WriteString(m.Styles.Title.Render(m.t(i18n.CleaningUpTitle)))
+	b.WriteString("\n\n")
+
+	if m.Current == "" {
+		b.WriteString(m.t(i18n.PleaseWait))
+		return b.String()
+	}
+
+	label := m.t(i18n.PhaseRemovingWorktree)
+	switch m.phase {
+	case worktreePhaseDeleteBranches:
+		label = m.t(i18n.PhaseDeletingBranch)
+	case worktreePhaseForceDeleteBranches:
+		label = m.t(i18n.PhaseForceDeletingBranch)
+	}
+	b.WriteString(fmt.Sprintf("%s %s %d/%d: %s…", m.Spinner.View(), label, m.Progress+1, m.ProgressTotal, m.Current))
+	b.WriteString("\n\n")
+	if m.Cancelled {
+		b.WriteString(m.Styles.Warning.Render(m.t(i18n.CancellingCleanup)))
+	} else {
+		b.WriteString(m.Styles.Help.Render(m.t(i18n.HelpCancelRun)))
+	}
+	return b.String()
+}
+
+func (m WorktreeModel) renderDone() string {
+	var b strings.Builder
+
+	title := m.t(i18n.CleanupDoneTitle)
+	if m.Cancelled {
+		title = m.t(i18n.CleanupCancelledTitle)
+	}
+	b.WriteString(m.Styles.Title.Render(title))
+	b.WriteString("\n\n")
+
+	for _, wt := range m.Worktrees {
+		switch {
+		case slices.Contains(m.Removed, wt.Path):
+			b.WriteString(m.Styles.Success.Render(m.t(i18n.WorktreeRemoved, wt.Path)))
+			b.WriteString("\n")
+		case m.Failures[wt.Path] != "":
+			b.WriteString(m.Styles.Error.Render(m.t(i18n.ResultFailed, wt.Path, firstLine(m.Failures[wt.Path]))))
+			b.WriteString("\n")
+		}
+	}
+	for _, branch := range m.FreedBranches {
+		switch {
+		case slices.Contains(m.DeletedBranches, branch):
+			b.WriteString(m.Styles.Success.Render(m.t(i18n.FreedBranchDeleted, branch)))
+			b.WriteString("\n")
+		case m.Failures[branch] != "":
+			b.WriteString(m.Styles.Error.Render(m.t(i18n.FreedBranchFailed, branch, firstLine(m.Failures[branch]))))
+			b.WriteString("\n")
+		case slices.Contains(m.KeptBranches, branch):
+			b.WriteString(m.Styles.Metadata.Render(m.t(i18n.FreedBranchKept, branch)))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.Styles.Help.UnsetMarginTop().Render(m.t(i18n.CleanupCounts,
+		m.Lang.Count(len(m.Removed), i18n.Worktree), m.Lang.Count(len(m.DeletedBranches), i18n.Branch), len(m.Failures))))
+	b.WriteString("\n\n")
+	b.WriteString(m.Styles.Help.Render(m.t(i18n.PressAnyKey)))
+	return b.String()
+}
@@ -8,8 +8,12 @@ import (
 )
 
 func main() {
-	if err := cmd.Execute(); err != nil {
+	err := cmd.Execute()
+	code := cmd.ExitCode(err)
+
+	// Cancelling is the user's choice, and the TUI shows its own errors, so they aren't printed again
+	if err != nil && !cmd.Reported(err) {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
 	}
+	os.Exit(code)
 }
@@ -0,0 +1,76 @@
+package gitops
+
+import (
+	"context"
+
+	"github.com/Kdaito/gelete/internal/git"
+)
+
+// BranchInfo is a local branch with the metadata of its tip and upstream
+type BranchInfo = git.BranchInfo
+
+// Upstream is the remote branch a local branch tracks
+type Upstream = git.Upstream
+
+// ListBranches returns the names of the local branches that may be deleted: all branches
+// except the current one and the protected ones, main, master and develop. The protected branches
+// can't be configured, and the .geleteignore files of the gelete command are not read.
+func (r Repo) ListBranches(ctx context.Context) ([]string, error) {
+	return git.ListBranchesContext(r.context(ctx))
+}
+
+// DeletableBranch is a branch returned by ListDeletableBranches, with the linked worktree it is checked out in
+type DeletableBranch = git.DeletableBranch
+
+// ListDeletableBranches returns the branches of ListBranches that can be deleted, each with the linked
+// worktree it is checked out in (nil if none). Branches checked out in the main worktree are excluded,
+// which matters when the repository was opened from a linked worktree.
+func (r Repo) ListDeletableBranches(ctx context.Context) ([]DeletableBranch, error) {
+	return git.ListDeletableBranchesContext(r.context(ctx))
+}
+
+// ListBranchesWithInfo returns the branches of ListBranches with their metadata,
+// gathered with a single git command.
+func (r Repo) ListBranchesWithInfo(ctx context.Context) ([]BranchInfo, error) {
+	return git.ListBranchesWithInfoContext(r.context(ctx))
+}
+
+// DeleteBranch deletes a branch that is fully merged (git branch -d).
+// Returns an error if the branch has unmerged commits (see ErrNotMerged), doesn't exist (ErrNotFound)
+// or is checked out in a worktree (ErrCheckedOutElsewhere).
+func (r Repo) DeleteBranch(ctx context.Context, name string) error {
+	return git.DeleteBranchContext(r.context(ctx), name)
+}
+
+// ForceDeleteBranch deletes a branch even if it has unmerged commits (git branch -D).
+// Returns an error if the branch doesn't exist.
+func (r Repo) ForceDeleteBranch(ctx context.Context, name string) error {
+	return git.ForceDeleteBranchContext(r.context(ctx), name)
+}
+
+// RenameBranch renames a local branch (git branch -m), keeping its upstream.
+// Returns an error if the new name is empty, invalid or already taken.
+func (r Repo) RenameBranch(ctx context.Context, oldName, newName string) error {
+	return git.RenameBranchContext(r.context(ctx), oldName, newName)
+}
+
+// IsUnmergedError reports whether err means DeleteBranch refused to delete a branch with unmerged commits
+func IsUnmergedError(err error) bool {
+	return git.IsUnmergedError(err)
+}
+
+var (
+	// ErrNotMerged is wrapped by the error of DeleteBranch when the branch has unmerged commits
+	ErrNotMerged = git.ErrNotMerged
+
+	// ErrNotFound is wrapped by the errors of DeleteBranch and ForceDeleteBranch when the branch doesn't exist
+	ErrNotFound = git.ErrNotFound
+)
+
+// ErrCheckedOutElsewhere is returned by DeleteBranch and ForceDeleteBranch when the branch is checked out
+// in a worktree; use errors.As to get the worktree's Path
+type ErrCheckedOutElsewhere = git.ErrCheckedOutElsewhere
+
+// ErrRefLocked is returned by DeleteBranch and ForceDeleteBranch when a lock file of git exists, usually because
+// another git process holds the lock for a moment; use errors.As to get its LockFile
+type ErrRefLocked = git.ErrRefLocked
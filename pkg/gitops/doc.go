@@ -0,0 +1,18 @@
+// Package gitops exposes the branch and worktree operations of gelete for use by other Go programs.
+//
+// Every operation runs git in the repository of a Repo, bound to a context.Context; nothing depends on
+// the working directory of the process, so one program can work on several repositories at once:
+//
+//	repo, err := gitops.Open(ctx, "/path/to/repo")
+//	if err != nil {
+//		return err
+//	}
+//	branches, err := repo.ListBranchesWithInfo(ctx)
+//
+// # Compatibility
+//
+// The package follows semantic versioning together with the gelete module: exported identifiers are
+// only removed or changed incompatibly in a new major version. New operations, and new fields in
+// BranchInfo and Worktree, may be added in minor versions, so construct these types with field names.
+// The rest of gelete lives in internal packages and carries no compatibility guarantee.
+package gitops
@@ -0,0 +1,41 @@
+package gitops
+
+import (
+	"context"
+
+	"github.com/Kdaito/gelete/internal/git"
+)
+
+// Repo is a git repository the operations run in.
+// The zero Repo runs git in the working directory of the process.
+type Repo struct {
+	repo git.Repository
+}
+
+// Open validates the repository containing path (the working directory if empty) and returns it.
+// The operations of the returned Repo run from the repository's top-level directory,
+// or from its git directory if it is bare.
+func Open(ctx context.Context, path string) (Repo, error) {
+	root, err := git.FindRepositoryRootContext(ctx, path)
+	if err != nil {
+		return Repo{}, err
+	}
+	return Repo{repo: git.Repository{Dir: root}}, nil
+}
+
+// Root returns the directory git runs in: the top-level directory of the repository,
+// or its git directory if it is bare. Empty for the zero Repo.
+func (r Repo) Root() string {
+	return r.repo.Dir
+}
+
+// context returns ctx set up to run git in the repository
+func (r Repo) context(ctx context.Context) context.Context {
+	return r.repo.Context(ctx)
+}
+
+// IsInterrupted reports whether err means a git command was killed
+// because ctx was cancelled or its deadline passed
+func IsInterrupted(err error) bool {
+	return git.IsInterrupted(err)
+}
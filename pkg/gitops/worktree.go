@@ -0,0 +1,21 @@
+package gitops
+
+import (
+	"context"
+
+	"github.com/Kdaito/gelete/internal/git"
+)
+
+// Worktree is a working tree of the repository, as listed by `git worktree list`
+type Worktree = git.Worktree
+
+// ListWorktrees returns the worktrees of the repository, starting with the main worktree
+func (r Repo) ListWorktrees(ctx context.Context) ([]Worktree, error) {
+	return git.ListWorktreesContext(r.context(ctx))
+}
+
+// RemoveWorktree removes a linked worktree (git worktree remove).
+// Returns an error if the worktree is locked or has uncommitted changes.
+func (r Repo) RemoveWorktree(ctx context.Context, path string) error {
+	return git.RemoveWorktreeContext(r.context(ctx), path)
+}
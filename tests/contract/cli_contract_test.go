@@ -2,15 +2,45 @@ package contract
 
 import (
 	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
+// geleteBinary is the gelete binary the contract tests run, built once by TestMain
+var geleteBinary string
+
+// TestMain builds gelete into a temporary directory and runs the contract tests with English messages,
+// whatever the locale of the machine running them
+func TestMain(m *testing.M) {
+	os.Setenv("GELETE_LANG", "en")
+
+	dir, err := os.MkdirTemp("", "gelete-contract-")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to create the build directory:", err)
+		os.Exit(1)
+	}
+	code := 1
+	if err := buildGelete(dir); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to build gelete:", err)
+	} else {
+		code = m.Run()
+	}
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 // setupTestRepo creates a temporary git repository for contract testing.
 func setupTestRepo(t *testing.T) string {
 	t.Helper()
@@ -32,20 +62,13 @@ func TestContract_RepositoryValidation(t *testing.T) {
 	// Create a non-git directory
 	dir := t.TempDir()
 
-	// Build the gelete binary
-	buildCmd := exec.Command("go", "build", "-o", "gelete-test", ".")
-	buildCmd.Dir = getProjectRoot(t)
-	err := buildCmd.Run()
-	require.NoError(t, err, "Failed to build gelete")
-
 	// Run gelete in non-git directory
-	binaryPath := getProjectRoot(t) + "/gelete-test"
-	cmd := exec.Command(binaryPath)
+	cmd := exec.Command(geleteBinary)
 	cmd.Dir = dir
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	err = cmd.Run()
+	err := cmd.Run()
 
 	// Should fail with exit code 1
 	assert.Error(t, err)
@@ -62,19 +85,12 @@ func TestContract_RepositoryValidation(t *testing.T) {
 // Given: User runs `gelete --help`
 // Then: Display help text and exit with code 0
 func TestContract_HelpFlag(t *testing.T) {
-	// Build the gelete binary
-	buildCmd := exec.Command("go", "build", "-o", "gelete-test", ".")
-	buildCmd.Dir = getProjectRoot(t)
-	err := buildCmd.Run()
-	require.NoError(t, err, "Failed to build gelete")
-
 	// Run gelete with --help
-	cmd := exec.Command("./gelete-test", "--help")
-	cmd.Dir = getProjectRoot(t)
+	cmd := exec.Command(geleteBinary, "--help")
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	err = cmd.Run()
+	err := cmd.Run()
 
 	// Should succeed with exit code 0
 	assert.NoError(t, err, "Should exit with code 0")
@@ -84,25 +100,106 @@ func TestContract_HelpFlag(t *testing.T) {
 	assert.Contains(t, stdoutStr, "gelete", "Help should mention command name")
 	assert.Contains(t, stdoutStr, "Usage", "Help should include usage section")
 	assert.Contains(t, stdoutStr, "help", "Help should mention help flag")
+
+	// Should show the common flows and describe the major flags
+	assert.Contains(t, stdoutStr, "Examples:")
+	for _, example := range []string{"gelete --gone --yes", "gelete --pattern 'tmp/*'", "gelete worktrees"} {
+		assert.Contains(t, stdoutStr, example)
+	}
+	for _, flag := range []string{"--dry-run", "--force", "--yes", "--gone", "--pattern", "--stale", "--select-wip", "--remote", "--json", "--base"} {
+		assert.Regexp(t, regexp.MustCompile(regexp.QuoteMeta(flag)+` .*\w`), stdoutStr, "Help should describe %s", flag)
+	}
+	assert.NotContains(t, stdoutStr, "docs", "The docs command should be hidden")
+}
+
+// TestContract_DocsMan tests generating the man pages and the Markdown documentation
+// Given: User runs `gelete docs man <dir>` and `gelete docs markdown <dir>`
+// Then: Write a page per command, dated by SOURCE_DATE_EPOCH so that generating them again gives the same pages
+func TestContract_DocsMan(t *testing.T) {
+	generate := func(dir string, args ...string) {
+		cmd := exec.Command(geleteBinary, append([]string{"docs"}, append(args, dir)...)...)
+		cmd.Env = append(os.Environ(), "SOURCE_DATE_EPOCH=1700000000")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	first, second := filepath.Join(t.TempDir(), "man"), filepath.Join(t.TempDir(), "man")
+	generate(first, "man")
+	generate(second, "man")
+
+	page, err := os.ReadFile(filepath.Join(first, "gelete.1"))
+	require.NoError(t, err)
+	assert.Contains(t, string(page), `.TH "GELETE" "1" "Nov 2023"`)
+	assert.Contains(t, string(page), "gelete --gone --yes")
+	assert.FileExists(t, filepath.Join(first, "gelete-worktrees.1"))
+	assert.NoFileExists(t, filepath.Join(first, "gelete-docs.1"), "Hidden commands should not get a page")
+
+	pages, err := os.ReadDir(first)
+	require.NoError(t, err)
+	for _, entry := range pages {
+		again, err := os.ReadFile(filepath.Join(second, entry.Name()))
+		require.NoError(t, err)
+		page, _ := os.ReadFile(filepath.Join(first, entry.Name()))
+		assert.Equal(t, string(page), string(again), "%s should be generated the same way twice", entry.Name())
+	}
+
+	markdown := t.TempDir()
+	generate(markdown, "markdown")
+	assert.FileExists(t, filepath.Join(markdown, "gelete.md"))
+	assert.FileExists(t, filepath.Join(markdown, "gelete_prune.md"))
+
+	cmd := exec.Command(geleteBinary, "docs", "man", t.TempDir())
+	cmd.Env = append(os.Environ(), "SOURCE_DATE_EPOCH=yesterday")
+	output, err := cmd.CombinedOutput()
+	requireExitCode(t, err, 1)
+	assert.Contains(t, string(output), "invalid SOURCE_DATE_EPOCH 'yesterday'")
+}
+
+// TestContract_ReleaseManPages tests that the release configuration generates the man pages with the version
+// of the release, since the pages name it
+// Given: The before hook of .goreleaser.yml that writes the man pages
+// Then: It sets cmd.Version with the same -X flag as the builds
+func TestContract_ReleaseManPages(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(getProjectRoot(t), ".goreleaser.yml"))
+	require.NoError(t, err)
+	var release struct {
+		Before struct {
+			Hooks []string `yaml:"hooks"`
+		} `yaml:"before"`
+		Builds []struct {
+			Ldflags []string `yaml:"ldflags"`
+		} `yaml:"builds"`
+	}
+	require.NoError(t, yaml.Unmarshal(data, &release))
+
+	var versionFlag string
+	for _, flag := range release.Builds[0].Ldflags {
+		if strings.Contains(flag, "cmd.Version=") {
+			versionFlag = flag
+		}
+	}
+	require.NotEmpty(t, versionFlag, "The builds should set the version")
+
+	var hook string
+	for _, h := range release.Before.Hooks {
+		if strings.Contains(h, "docs man") {
+			hook = h
+		}
+	}
+	require.NotEmpty(t, hook, "A before hook should generate the man pages")
+	assert.Contains(t, hook, versionFlag)
 }
 
 // TestContract_VersionFlag tests Contract 13: Version flag
 // Given: User runs `gelete --version`
 // Then: Display version and exit with code 0
 func TestContract_VersionFlag(t *testing.T) {
-	// Build the gelete binary
-	buildCmd := exec.Command("go", "build", "-o", "gelete-test", ".")
-	buildCmd.Dir = getProjectRoot(t)
-	err := buildCmd.Run()
-	require.NoError(t, err, "Failed to build gelete")
-
 	// Run gelete with --version
-	cmd := exec.Command("./gelete-test", "--version")
-	cmd.Dir = getProjectRoot(t)
+	cmd := exec.Command(geleteBinary, "--version")
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	err = cmd.Run()
+	err := cmd.Run()
 
 	// Should succeed with exit code 0
 	assert.NoError(t, err, "Should exit with code 0")
@@ -118,20 +215,13 @@ func TestContract_VersionFlag(t *testing.T) {
 func TestContract_NoDeletableBranches(t *testing.T) {
 	repo := setupTestRepo(t)
 
-	// Build the gelete binary
-	buildCmd := exec.Command("go", "build", "-o", "gelete-test", ".")
-	buildCmd.Dir = getProjectRoot(t)
-	err := buildCmd.Run()
-	require.NoError(t, err, "Failed to build gelete")
-
 	// Run gelete in repo with only one branch
-	binaryPath := getProjectRoot(t) + "/gelete-test"
-	cmd := exec.Command(binaryPath)
+	cmd := exec.Command(geleteBinary)
 	cmd.Dir = repo
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	err = cmd.Run()
+	err := cmd.Run()
 
 	// Should succeed with exit code 0
 	assert.NoError(t, err, "Should exit with code 0 when no branches to delete")
@@ -152,12 +242,6 @@ func TestContract_UnmergedBranchHandling(t *testing.T) {
 	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Unmerged commit").Run()
 	exec.Command("git", "-C", repo, "checkout", "-").Run() // Switch back to main/master
 
-	// Build the gelete binary
-	buildCmd := exec.Command("go", "build", "-o", "gelete-test", ".")
-	buildCmd.Dir = getProjectRoot(t)
-	err := buildCmd.Run()
-	require.NoError(t, err, "Failed to build gelete")
-
 	// Note: This test verifies the error detection and message format
 	// Interactive TUI testing would require mock/simulation framework
 	// For now, we test that DeleteBranch correctly fails on unmerged branches
@@ -180,12 +264,6 @@ func TestContract_WorktreeDetection(t *testing.T) {
 	worktreePath := t.TempDir()
 	exec.Command("git", "-C", repo, "worktree", "add", worktreePath, "feature-branch").Run()
 
-	// Build the gelete binary
-	buildCmd := exec.Command("go", "build", "-o", "gelete-test", ".")
-	buildCmd.Dir = getProjectRoot(t)
-	err := buildCmd.Run()
-	require.NoError(t, err, "Failed to build gelete")
-
 	// Note: Interactive TUI testing for worktree detection and display
 	// is covered in integration tests
 
@@ -207,12 +285,6 @@ func TestContract_WorktreeRemoval(t *testing.T) {
 	worktreePath := t.TempDir()
 	exec.Command("git", "-C", repo, "worktree", "add", worktreePath, "feature-branch").Run()
 
-	// Build the gelete binary
-	buildCmd := exec.Command("go", "build", "-o", "gelete-test", ".")
-	buildCmd.Dir = getProjectRoot(t)
-	err := buildCmd.Run()
-	require.NoError(t, err, "Failed to build gelete")
-
 	// Note: Interactive TUI testing for worktree removal prompt and execution
 	// is covered in integration tests
 
@@ -223,30 +295,1486 @@ func TestContract_WorktreeRemoval(t *testing.T) {
 	exec.Command("git", "-C", repo, "worktree", "remove", worktreePath).Run()
 }
 
-// getProjectRoot returns the path to the project root directory.
+// TestContract_BatchDeletion tests non-interactive deletion of branches given as arguments
+// Given: User runs `gelete --yes <branch>...`
+// Then: Delete the branches without the TUI, print one line per branch, and exit with code 0
+func TestContract_BatchDeletion(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+	exec.Command("git", "-C", repo, "branch", "feature-b").Run()
+
+	stdout, _, err := runGelete(t, repo, "", "--yes", "feature-a", "feature-b")
+	assert.NoError(t, err, "Should exit with code 0")
+	assert.Contains(t, stdout, "Deleted branch feature-a")
+	assert.Contains(t, stdout, "Deleted branch feature-b")
+	assert.Contains(t, stdout, "restore with: git branch feature-a", "Should print how to restore each branch")
+
+	output, _ := exec.Command("git", "-C", repo, "branch", "--list", "feature-*").Output()
+	assert.Empty(t, strings.TrimSpace(string(output)), "Branches should be deleted")
+}
+
+// TestContract_BatchDeletionFailures tests failure reporting in non-interactive mode
+// Given: User passes a missing branch and an unmerged branch
+// Then: Report "branch not found" and "not fully merged" distinctly and exit with code 2
+func TestContract_BatchDeletionFailures(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "checkout", "-b", "unmerged").Run()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "-C", repo, "checkout", "-").Run()
+	exec.Command("git", "-C", repo, "branch", "merged").Run()
+
+	stdout, _, err := runGelete(t, repo, "", "--yes", "missing", "unmerged", "merged")
+	requireExitCode(t, err, 2)
+	assert.Contains(t, stdout, "missing: branch not found")
+	assert.Contains(t, stdout, "unmerged: not fully merged")
+	assert.Contains(t, stdout, "Deleted branch merged", "Other branches should still be deleted")
+
+	// --force escalates to force deletion
+	stdout, _, err = runGelete(t, repo, "", "--yes", "--force", "unmerged")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "Force deleted branch unmerged")
+}
+
+// TestContract_Submodule tests running gelete inside a git submodule
+// Given: A superproject and its submodule both have a branch of their own, and gelete runs in a directory of the submodule
+// Then: Only the submodule's branches are listed and deleted
+func TestContract_Submodule(t *testing.T) {
+	lib := setupTestRepo(t)
+	exec.Command("git", "-C", lib, "branch", "lib-feature").Run()
+	super := setupTestRepo(t)
+	exec.Command("git", "-C", super, "branch", "super-feature").Run()
+	output, err := exec.Command("git", "-C", super, "-c", "protocol.file.allow=always", "submodule", "add", lib, "lib").CombinedOutput()
+	require.NoError(t, err, string(output))
+	submodule := filepath.Join(super, "lib")
+	require.NoError(t, exec.Command("git", "-C", submodule, "branch", "lib-feature", "origin/lib-feature").Run())
+	nested := filepath.Join(submodule, "nested")
+	require.NoError(t, os.Mkdir(nested, 0o755))
+
+	stdout, _, err := runGelete(t, nested, "", "--json")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, `"lib-feature"`)
+	assert.NotContains(t, stdout, "super-feature", "The superproject's branches should not be listed")
+
+	stdout, _, err = runGelete(t, nested, "", "--yes", "lib-feature")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Deleted branch lib-feature")
+
+	branches, _ := exec.Command("git", "-C", submodule, "branch", "--list", "lib-feature").Output()
+	assert.Empty(t, strings.TrimSpace(string(branches)))
+	branches, _ = exec.Command("git", "-C", super, "branch", "--list", "super-feature").Output()
+	assert.Contains(t, string(branches), "super-feature", "The superproject's branches should be untouched")
+}
+
+// TestContract_BatchRefusesCurrentBranch tests that the current branch can't be deleted non-interactively
+// Given: User passes the current branch as an argument
+// Then: Display a clear error, delete nothing, and exit with code 1
+func TestContract_BatchRefusesCurrentBranch(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+	output, _ := exec.Command("git", "-C", repo, "branch", "--show-current").Output()
+	current := strings.TrimSpace(string(output))
+
+	_, stderr, err := runGelete(t, repo, "", "--yes", "feature-a", current)
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "cannot delete the current branch")
+
+	err = exec.Command("git", "-C", repo, "rev-parse", "--verify", "refs/heads/feature-a").Run()
+	assert.NoError(t, err, "No branch should be deleted when the current branch is requested")
+}
+
+// TestContract_BatchConfirmation tests the stdin confirmation prompt without --yes
+// Given: User runs `gelete <branch>` and answers the prompt
+// Then: Delete only when the answer is "y"
+func TestContract_BatchConfirmation(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+
+	stdout, stderr, err := runGelete(t, repo, "n\n", "feature-a")
+	requireExitCode(t, err, 3)
+	assert.Contains(t, stdout, "Delete 1 branch(es)? [y/N]")
+	assert.Contains(t, stdout, "Aborted.")
+	assert.Empty(t, stderr, "Cancelling should not be reported as an error")
+
+	stdout, _, err = runGelete(t, repo, "y\n", "feature-a")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "Deleted branch feature-a")
+}
+
+// TestContract_ProtectedBranches tests that protected branches are not deleted
+// Given: User passes default, configured and --protect patterns as arguments
+// Then: Refuse the protected branches unless --no-protect is given
+func TestContract_ProtectedBranches(t *testing.T) {
+	repo := setupTestRepo(t)
+	for _, branch := range []string{"develop", "release/1.0", "hotfix/1", "feature-a"} {
+		exec.Command("git", "-C", repo, "branch", branch).Run()
+	}
+	exec.Command("git", "-C", repo, "config", "--add", "gelete.protect", "release/*").Run()
+
+	stdout, _, err := runGelete(t, repo, "", "--yes", "--protect", "hotfix/*", "develop", "release/1.0", "hotfix/1", "feature-a")
+	requireExitCode(t, err, 2)
+	assert.Contains(t, stdout, "develop: branch is protected")
+	assert.Contains(t, stdout, "release/1.0: branch is protected")
+	assert.Contains(t, stdout, "hotfix/1: branch is protected")
+	assert.Contains(t, stdout, "Deleted branch feature-a")
+
+	stdout, _, err = runGelete(t, repo, "", "--yes", "--no-protect", "develop", "release/1.0")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "Deleted branch develop")
+	assert.Contains(t, stdout, "Deleted branch release/1.0")
+}
+
+// TestContract_IgnoredBranches tests that branches matching .geleteignore are hidden and refused
+// Given: A .geleteignore at the repository root, and gelete run from a subdirectory
+// Then: Hide and refuse the ignored branches unless --no-ignore is given, reporting protection first
+func TestContract_IgnoredBranches(t *testing.T) {
+	repo := setupTestRepo(t)
+	for _, branch := range []string{"release/1.0", "release/tmp", "staging", "feature-a"} {
+		exec.Command("git", "-C", repo, "branch", branch).Run()
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".geleteignore"), []byte("# shared branches\nrelease/*\n!release/tmp\nstaging\n"), 0o644))
+	exec.Command("git", "-C", repo, "config", "--add", "gelete.protect", "staging").Run()
+	subdir := filepath.Join(repo, "docs")
+	require.NoError(t, os.MkdirAll(subdir, 0o755))
+
+	stdout, _, err := runGelete(t, subdir, "", "--json")
+	require.NoError(t, err)
+	assert.NotContains(t, stdout, "release/1.0", "Ignored branches should be excluded from the list")
+	assert.Contains(t, stdout, "release/tmp", "Negated patterns should include branches again")
+	assert.Contains(t, stdout, "feature-a")
+
+	stdout, _, err = runGelete(t, subdir, "", "--yes", "release/1.0", "staging", "feature-a")
+	requireExitCode(t, err, 2)
+	assert.Contains(t, stdout, "release/1.0: ignored by .geleteignore")
+	assert.Contains(t, stdout, "staging: branch is protected", "Protection should take precedence over the ignore file")
+	assert.Contains(t, stdout, "Deleted branch feature-a")
+
+	stdout, _, err = runGelete(t, subdir, "", "--yes", "--no-protect", "staging")
+	requireExitCode(t, err, 2)
+	assert.Contains(t, stdout, "staging: ignored by .geleteignore")
+
+	stdout, _, err = runGelete(t, subdir, "", "--yes", "--no-ignore", "release/1.0")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "Deleted branch release/1.0")
+}
+
+// TestContract_DefaultBranchProtection tests that the default branch is protected whatever its name
+// Given: A clone whose origin/HEAD points to trunk, and a repository without remote whose default is main
+// Then: Refuse the default branch, unless --allow-default is given, which warns before deleting it
+func TestContract_DefaultBranchProtection(t *testing.T) {
+	origin := setupTestRepo(t)
+	exec.Command("git", "-C", origin, "branch", "-M", "trunk").Run()
+	clone := filepath.Join(t.TempDir(), "clone")
+	require.NoError(t, exec.Command("git", "clone", "-q", origin, clone).Run())
+	exec.Command("git", "-C", clone, "checkout", "-q", "-b", "feature-a").Run()
+
+	stdout, _, err := runGelete(t, clone, "", "--json")
+	require.NoError(t, err)
+	assert.NotContains(t, stdout, "trunk", "The default branch should be excluded from the list")
+
+	stdout, _, err = runGelete(t, clone, "", "--yes", "trunk")
+	requireExitCode(t, err, 2)
+	assert.Contains(t, stdout, "trunk: branch is the default branch (use --allow-default to delete anyway)")
+
+	stdout, _, err = runGelete(t, clone, "n\n", "--allow-default", "trunk")
+	requireExitCode(t, err, 3)
+	assert.Contains(t, stdout, "'trunk' is the DEFAULT branch of this repository")
+
+	stdout, _, err = runGelete(t, clone, "", "--allow-default", "--yes", "trunk")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "Deleted branch trunk")
+
+	// Without a remote, main is the default branch
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "-M", "main").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "feature-a").Run()
+
+	stdout, _, err = runGelete(t, repo, "", "--yes", "main")
+	requireExitCode(t, err, 2)
+	assert.Contains(t, stdout, "main: branch is the default branch")
+
+	stdout, _, err = runGelete(t, repo, "", "--allow-default", "--yes", "main")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "Deleted branch main")
+}
+
+// TestContract_ExpireReflog tests --expire-reflog
+// Given: User deletes an unmerged branch with `gelete --expire-reflog --force --yes`
+// Then: The reflog is expired and git gc run, with a warning, and the deleted commit is pruned
+func TestContract_ExpireReflog(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "feature-a").Run()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-").Run()
+	sha, err := exec.Command("git", "-C", repo, "rev-parse", "feature-a").Output()
+	require.NoError(t, err)
+
+	stdout, _, err := runGelete(t, repo, "n\n", "--expire-reflog", "feature-a")
+	requireExitCode(t, err, 3)
+	assert.Contains(t, stdout, "--expire-reflog: the reflog is expired and git gc run afterwards, so deleted commits become unrecoverable")
+
+	stdout, stderr, err := runGelete(t, repo, "", "--expire-reflog", "--force", "--yes", "feature-a")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Force deleted branch feature-a")
+	assert.Contains(t, stdout, "Expired the reflog and ran git gc in")
+	assert.Contains(t, stdout, "reclaimed ~")
+	assert.Contains(t, stderr, "the deleted commits become unrecoverable")
+	assert.Error(t, exec.Command("git", "-C", repo, "cat-file", "-e", strings.TrimSpace(string(sha))).Run(),
+		"The deleted commit should be pruned")
+
+	_, stderr, err = runGelete(t, repo, "", "--expire-reflog", "--remote")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "--expire-reflog cannot be combined with --remote")
+}
+
+// TestContract_BackupBundle tests --backup-bundle
+// Given: User force deletes an unmerged branch with `gelete --backup-bundle backup.bundle --force --yes`
+// Then: The branch is written to the bundle, relative to the working directory, and can be fetched back from it
+func TestContract_BackupBundle(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "feature-a").Run()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-").Run()
+	sha, err := exec.Command("git", "-C", repo, "rev-parse", "feature-a").Output()
+	require.NoError(t, err)
+	subdir := filepath.Join(repo, "sub")
+	require.NoError(t, os.MkdirAll(subdir, 0o755))
+
+	stdout, _, err := runGelete(t, subdir, "", "--backup-bundle", "backup.bundle", "--force", "--yes", "feature-a")
+	require.NoError(t, err)
+	bundle := filepath.Join(subdir, "backup.bundle")
+	assert.Contains(t, stdout, "Backed up 1 branch(es) to "+bundle)
+	assert.Contains(t, stdout, "Force deleted branch feature-a")
+
+	require.NoError(t, exec.Command("git", "-C", repo, "fetch", "-q", bundle, "feature-a:feature-a").Run())
+	restored, err := exec.Command("git", "-C", repo, "rev-parse", "feature-a").Output()
+	require.NoError(t, err)
+	assert.Equal(t, string(sha), string(restored), "The branch should be restored from the bundle")
+
+	_, stderr, err := runGelete(t, repo, "", "--backup-bundle", "backup.bundle", "--remote")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "--backup-bundle cannot be combined with --remote")
+}
+
+// TestContract_GoneNoBranches tests --gone when no upstream was deleted
+// Given: User runs `gelete --gone` in a repository without gone branches
+// Then: Display a message and exit with code 0 without starting the TUI
+func TestContract_GoneNoBranches(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+
+	stdout, _, err := runGelete(t, repo, "", "--gone")
+	assert.NoError(t, err, "Should exit with code 0")
+	assert.Contains(t, stdout, "No branches with a gone upstream.")
+}
+
+// TestContract_GoneYes tests deleting gone branches without the TUI
+// Given: User runs `gelete --gone --yes` in a repository with a gone branch
+// Then: Delete the gone branch and keep the others
+func TestContract_GoneYes(t *testing.T) {
+	repo := setupTestRepo(t)
+	remote := t.TempDir()
+	exec.Command("git", "init", "-q", "--bare", remote).Run()
+	exec.Command("git", "-C", repo, "remote", "add", "origin", remote).Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "merged-pr").Run()
+	exec.Command("git", "-C", repo, "push", "-q", "-u", "origin", "merged-pr").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-").Run()
+	exec.Command("git", "-C", repo, "push", "-q", "origin", "--delete", "merged-pr").Run()
+	exec.Command("git", "-C", repo, "branch", "feature").Run()
+
+	stdout, _, err := runGelete(t, repo, "", "--gone", "--yes")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Deleted branch merged-pr")
+
+	output, _ := exec.Command("git", "-C", repo, "branch", "--format=%(refname:short)").Output()
+	assert.NotContains(t, strings.Fields(string(output)), "merged-pr")
+	assert.Contains(t, strings.Fields(string(output)), "feature", "Branches with an upstream should be kept")
+
+	stdout, _, err = runGelete(t, repo, "", "--gone", "--yes")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "No branches with a gone upstream.")
+}
+
+// TestContract_JSONList tests listing deletable branches as JSON
+// Given: User runs `gelete --json` without branch arguments
+// Then: Print an array of branches with name, merge status, last commit date and worktree
+func TestContract_JSONList(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "merged").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "unmerged").Run()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-").Run()
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	exec.Command("git", "-C", repo, "worktree", "add", worktreePath, "unmerged").Run()
+
+	stdout, _, err := runGelete(t, repo, "", "--json")
+	require.NoError(t, err)
+
+	var branches []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(stdout), &branches), "Output should be valid JSON: %s", stdout)
+	require.Len(t, branches, 2)
+
+	assert.Equal(t, "merged", branches[0]["name"])
+	assert.Equal(t, true, branches[0]["merged"])
+	assert.NotEmpty(t, branches[0]["last_commit_date"])
+	assert.NotContains(t, branches[0], "worktree")
+
+	assert.Equal(t, "unmerged", branches[1]["name"])
+	assert.Equal(t, false, branches[1]["merged"])
+	require.Contains(t, branches[1], "worktree")
+	assert.Contains(t, branches[1]["worktree"].(map[string]any)["path"], "wt")
+}
+
+// TestContract_MergeFilterFlags tests listing only merged or unmerged branches
+// Given: User runs `gelete --json` with --merged-only, --unmerged-only or both
+// Then: List only the matching branches, and refuse the combination of both flags
+func TestContract_MergeFilterFlags(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "merged").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "unmerged").Run()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-").Run()
+
+	listed := func(args ...string) []string {
+		stdout, _, err := runGelete(t, repo, "", append([]string{"--json"}, args...)...)
+		require.NoError(t, err)
+		var branches []map[string]any
+		require.NoError(t, json.Unmarshal([]byte(stdout), &branches), "Output should be valid JSON: %s", stdout)
+		var names []string
+		for _, branch := range branches {
+			names = append(names, branch["name"].(string))
+		}
+		return names
+	}
+	assert.Equal(t, []string{"merged"}, listed("--merged-only"))
+	assert.Equal(t, []string{"unmerged"}, listed("--unmerged-only"))
+
+	_, stderr, err := runGelete(t, repo, "", "--merged-only", "--unmerged-only")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "none of the others can be")
+
+	_, stderr, err = runGelete(t, repo, "", "--merged-only", "--remote")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "cannot be combined with --remote")
+}
+
+// TestContract_BaseFlag tests choosing the base branch merge status is computed against
+// Given: A branch merged into develop but not into the default branch
+// Then: It is merged with --base develop or gelete.base set to develop, and a mistyped base is refused with suggestions
+func TestContract_BaseFlag(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "develop").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "feature").Run()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Feature commit").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "develop").Run()
+	exec.Command("git", "-C", repo, "merge", "-q", "--no-ff", "-m", "Merge feature", "feature").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-").Run()
+
+	listed := func(args ...string) []string {
+		stdout, stderr, err := runGelete(t, repo, "", append([]string{"--json", "--merged-only"}, args...)...)
+		require.NoError(t, err, stderr)
+		var branches []map[string]any
+		require.NoError(t, json.Unmarshal([]byte(stdout), &branches), "Output should be valid JSON: %s", stdout)
+		var names []string
+		for _, branch := range branches {
+			names = append(names, branch["name"].(string))
+		}
+		return names
+	}
+	assert.Empty(t, listed(), "feature is not merged into the default branch")
+	assert.Equal(t, []string{"feature"}, listed("--base", "develop"))
+
+	exec.Command("git", "-C", repo, "config", "gelete.base", "develop").Run()
+	assert.Equal(t, []string{"feature"}, listed())
+
+	_, stderr, err := runGelete(t, repo, "", "--json", "--base", "develp")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "invalid --base: 'develp' is not a branch or commit (did you mean 'develop'?)")
+
+	exec.Command("git", "-C", repo, "config", "gelete.base", "nope").Run()
+	_, stderr, err = runGelete(t, repo, "", "--json")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "invalid gelete.base: 'nope' is not a branch or commit")
+}
+
+// TestContract_ExitCodes tests the exit code contract documented in --help
+// Given: Runs with nothing to do, outside a repository, with a failed deletion and with a declined prompt
+// Then: Exit with 0, 1, 2 and 3 respectively
+func TestContract_ExitCodes(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	stdout, _, err := runGelete(t, repo, "", "--help")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Exit codes:")
+
+	_, _, err = runGelete(t, repo, "", "--pattern", "nothing/*", "--yes")
+	assert.NoError(t, err, "Nothing to do should exit with 0")
+
+	_, _, err = runGelete(t, t.TempDir(), "")
+	requireExitCode(t, err, 1)
+
+	_, _, err = runGelete(t, repo, "", "--yes", "missing")
+	requireExitCode(t, err, 2)
+
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+	_, _, err = runGelete(t, repo, "n\n", "feature-a")
+	requireExitCode(t, err, 3)
+}
+
+// TestContract_JSONDeletion tests per-branch JSON results for non-interactive deletion
+// Given: User runs `gelete --json --yes` with a deletable and a missing branch
+// Then: Print a result per branch along with the branches before and after, and exit with code 2
+// because one deletion failed
+func TestContract_JSONDeletion(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+	exec.Command("git", "-C", repo, "branch", "keep").Run()
+	output, _ := exec.Command("git", "-C", repo, "branch", "--show-current").Output()
+	current := strings.TrimSpace(string(output))
+
+	stdout, _, err := runGelete(t, repo, "", "--json", "--yes", "feature-a", "missing")
+	requireExitCode(t, err, 2)
+
+	var report struct {
+		Before  []string         `json:"before"`
+		Results []map[string]any `json:"results"`
+		After   []string         `json:"after"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &report), "JSON should be emitted even on failure: %s", stdout)
+	results := report.Results
+	require.Len(t, results, 2)
+	assert.Equal(t, "feature-a", results[0]["branch"])
+	assert.Equal(t, "deleted", results[0]["status"])
+	assert.NotEmpty(t, results[0]["sha"])
+	assert.Equal(t, "missing", results[1]["branch"])
+	assert.Equal(t, "failed", results[1]["status"])
+	assert.Equal(t, "branch not found", results[1]["error"])
+
+	assert.ElementsMatch(t, []string{"feature-a", "keep", current}, report.Before)
+	assert.ElementsMatch(t, []string{"keep", current}, report.After)
+}
+
+// TestContract_BatchReport tests the report of the branches before and after a deletion with --yes against
+// a golden file. Run with -update to accept changes to it.
+// Given: User runs `gelete --yes` with deletable, missing and unmerged branches in a fixed repository
+// Then: Print every branch before, marking the targeted ones, the result lines, and the branches left after.
+// With --quiet, print nothing but the error.
+func TestContract_BatchReport(t *testing.T) {
+	repo := setupFixedRepo(t)
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = fixedGitEnv()
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, out)
+	}
+	git("branch", "feature-a")
+	git("branch", "feature-b")
+	git("branch", "keep")
+	git("checkout", "-q", "-b", "unmerged")
+	git("commit", "-q", "--allow-empty", "-m", "Unmerged work")
+	git("checkout", "-q", "main")
+
+	stdout, _, err := runGelete(t, repo, "", "--yes", "feature-a", "feature-b", "missing", "unmerged")
+	requireExitCode(t, err, 2)
+	assertGolden(t, filepath.Join("testdata", "batch_report.golden"), stdout)
+
+	git("branch", "feature-c")
+	stdout, stderr, err := runGelete(t, repo, "", "--quiet", "--yes", "feature-c", "missing")
+	requireExitCode(t, err, 2)
+	assert.Empty(t, stdout, "--quiet should suppress the report")
+	assert.Contains(t, stderr, "failed to delete 1 of 2 branch(es)")
+}
+
+// TestContract_JSONRequiresNonInteractive tests that --json is refused where a prompt or the TUI is needed
+// Given: User runs `gelete --json` with branches but without --yes, or with --remote
+// Then: Display an error and exit with code 1 without deleting anything
+func TestContract_JSONRequiresNonInteractive(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+
+	_, stderr, err := runGelete(t, repo, "y\n", "--json", "feature-a")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "--json requires --yes")
+
+	_, stderr, err = runGelete(t, repo, "", "--json", "--remote")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "--json cannot be combined with --remote")
+
+	err = exec.Command("git", "-C", repo, "rev-parse", "--verify", "refs/heads/feature-a").Run()
+	assert.NoError(t, err, "No branch should be deleted")
+}
+
+// TestContract_PatternDeletion tests deleting branches matching glob patterns
+// Given: User runs `gelete --pattern <glob> --yes` with one pattern that matches nothing
+// Then: Warn about the unmatched pattern and delete the branches matching the others
+func TestContract_PatternDeletion(t *testing.T) {
+	repo := setupTestRepo(t)
+	for _, branch := range []string{"tmp/a", "tmp/b", "spike/c", "feature-x"} {
+		exec.Command("git", "-C", repo, "branch", branch).Run()
+	}
+
+	stdout, stderr, err := runGelete(t, repo, "", "--pattern", "tmp/*", "--pattern", "wip/*", "--yes")
+	assert.NoError(t, err)
+	assert.Contains(t, stderr, "Warning: pattern 'wip/*' matched no branches")
+	assert.Contains(t, stdout, "Deleted branch tmp/a")
+	assert.Contains(t, stdout, "Deleted branch tmp/b")
+
+	output, _ := exec.Command("git", "-C", repo, "branch", "--format=%(refname:short)").Output()
+	assert.Contains(t, string(output), "spike/c", "Non-matching branches should be kept")
+	assert.Contains(t, string(output), "feature-x", "Non-matching branches should be kept")
+	assert.NotContains(t, string(output), "tmp/")
+
+	stdout, _, err = runGelete(t, repo, "", "--pattern", "nothing/*", "--yes")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "No branches match the given patterns.")
+}
+
+// TestContract_StaleDeletion tests deleting branches without recent commits
+// Given: User runs `gelete --stale 90d --yes --force` with a branch last committed to 200 days ago
+// Then: List and delete only the stale branch, and refuse invalid ages
+func TestContract_StaleDeletion(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "recent").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "old").Run()
+	commit := exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Old commit")
+	commit.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+time.Now().AddDate(0, 0, -200).Format(time.RFC3339))
+	require.NoError(t, commit.Run())
+	exec.Command("git", "-C", repo, "checkout", "-q", "-").Run()
+
+	stdout, _, err := runGelete(t, repo, "", "--stale", "90d", "--yes", "--force")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "Branches without commits in the last 90d:")
+	assert.Contains(t, stdout, "• old")
+	assert.NotContains(t, stdout, "• recent")
+
+	output, _ := exec.Command("git", "-C", repo, "branch", "--format=%(refname:short)").Output()
+	assert.Contains(t, string(output), "recent", "Recent branches should be kept")
+	assert.NotContains(t, string(output), "old")
+
+	stdout, _, err = runGelete(t, repo, "", "--stale", "90d", "--yes")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "No branches without commits in the last 90d.")
+
+	_, stderr, err := runGelete(t, repo, "", "--stale", "90x", "--yes")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "invalid age '90x'")
+}
+
+// TestContract_SelectWIP tests deleting branches whose commits are all work in progress
+// Given: User runs `gelete --select-wip --yes --force` with a branch of "wip" commits and one with real work
+// Then: Delete only the WIP branch, and refuse invalid wip_patterns at startup
+func TestContract_SelectWIP(t *testing.T) {
+	repo := setupTestRepo(t)
+	for branch, subject := range map[string]string{"wip-a": "wip", "feature-b": "Add feature b"} {
+		exec.Command("git", "-C", repo, "checkout", "-q", "-b", branch).Run()
+		require.NoError(t, exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", subject).Run())
+		exec.Command("git", "-C", repo, "checkout", "-q", "-").Run()
+	}
+
+	stdout, _, err := runGelete(t, repo, "", "--select-wip", "--yes", "--force")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "Force deleted branch wip-a")
+	assert.NotContains(t, stdout, "branch feature-b")
+
+	stdout, _, err = runGelete(t, repo, "", "--select-wip", "--yes")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "No WIP branches.")
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".gelete.yaml"), []byte("wip_patterns: ['^(wip']\n"), 0o644))
+	_, stderr, err := runGelete(t, repo, "", "--yes", "feature-b")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "invalid wip_patterns in config: invalid WIP pattern '^(wip'")
+}
+
+// TestContract_OutputLevels tests the --quiet and --verbose output levels
+// Given: User runs non-interactive deletions with --quiet or --verbose
+// Then: Quiet prints only errors, verbose prints every git command to stderr
+func TestContract_OutputLevels(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+	exec.Command("git", "-C", repo, "branch", "feature-b").Run()
+
+	stdout, stderr, err := runGelete(t, repo, "", "--verbose", "--yes", "feature-a")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "Deleted branch feature-a")
+	assert.Contains(t, stderr, "git branch -d -- feature-a (")
+
+	stdout, stderr, err = runGelete(t, repo, "", "--quiet", "--yes", "feature-b", "missing")
+	requireExitCode(t, err, 2)
+	assert.Empty(t, stdout)
+	assert.Equal(t, "Error: failed to delete 1 of 2 branch(es)\n", stderr, "Only the error should be printed")
+
+	_, stderr, err = runGelete(t, repo, "", "--quiet", "--json")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "--quiet cannot be combined with --json")
+
+	_, stderr, err = runGelete(t, repo, "", "--quiet", "--verbose")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "none of the others can be")
+}
+
+// TestContract_GitTimeout tests that a hung git command doesn't freeze gelete
+// Given: git never finishes and the user runs `gelete --git-timeout 200ms`
+// Then: Report the timeout and exit with code 1
+func TestContract_GitTimeout(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	fakeBin := t.TempDir()
+	err := os.WriteFile(filepath.Join(fakeBin, "git"), []byte("#!/bin/sh\nexec sleep 30\n"), 0o755)
+	require.NoError(t, err)
+
+	cmd := exec.Command(geleteBinary, "--git-timeout", "200ms", "--yes", "feature-a")
+	cmd.Dir = repo
+	cmd.Env = append(os.Environ(), "PATH="+fakeBin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err = cmd.Run()
+	requireExitCode(t, err, 1)
+	assert.Less(t, time.Since(start), 10*time.Second, "gelete should give up after the timeout")
+	assert.Contains(t, stderr.String(), "git --version timed out after 200ms", "The git version is checked first")
+}
+
+// TestContract_BatchRefusesCheckedOutBranches tests that checked-out branches are refused before anything is deleted
+// Given: User passes a deletable branch, the current branch and a branch checked out in a linked worktree
+// Then: Report both checked-out branches with the git switch command naming the default branch,
+// delete nothing, and exit with code 1
+func TestContract_BatchRefusesCheckedOutBranches(t *testing.T) {
+	repo := setupTestRepo(t)
+	output, _ := exec.Command("git", "-C", repo, "branch", "--show-current").Output()
+	defaultBranch := strings.TrimSpace(string(output))
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+	exec.Command("git", "-C", repo, "branch", "feature-linked").Run()
+	require.NoError(t, exec.Command("git", "-C", repo, "switch", "-q", "-c", "feature-current").Run())
+	linked := filepath.Join(t.TempDir(), "linked")
+	require.NoError(t, exec.Command("git", "-C", repo, "worktree", "add", linked, "feature-linked").Run())
+
+	_, stderr, err := runGelete(t, repo, "", "--yes", "feature-a", "feature-current", "feature-linked")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "2 branches are checked out, nothing was deleted")
+	assert.Contains(t, stderr, "cannot delete the current branch 'feature-current'; switch to another branch first: git switch "+defaultBranch)
+	assert.Contains(t, stderr, "cannot delete 'feature-linked': it is checked out in worktree")
+	assert.Contains(t, stderr, "switch "+defaultBranch)
+
+	err = exec.Command("git", "-C", repo, "rev-parse", "--verify", "refs/heads/feature-a").Run()
+	assert.NoError(t, err, "No branch should be deleted when a checked-out branch is requested")
+}
+
+// TestContract_BatchDuplicateArguments tests that a branch given twice is deleted once
+// Given: User passes the same branch twice
+// Then: Note the repetition on stderr, delete the branch once, and exit with code 0
+func TestContract_BatchDuplicateArguments(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+
+	stdout, stderr, err := runGelete(t, repo, "", "--yes", "feature-a", "feature-a")
+	require.NoError(t, err)
+	assert.Contains(t, stderr, "Note: 'feature-a' was given more than once; deleting it once")
+	assert.Equal(t, 1, strings.Count(stdout, "Deleted branch feature-a"), "The branch should be reported once")
+	assert.NotContains(t, stdout, "not found")
+
+	err = exec.Command("git", "-C", repo, "rev-parse", "--verify", "refs/heads/feature-a").Run()
+	assert.Error(t, err, "The branch should be deleted")
+}
+
+// TestContract_LinkedWorktree tests running gelete from inside a linked worktree
+// Given: The main worktree has "trunk" checked out and another linked worktree has "feature-other"
+// Then: The JSON list excludes trunk, and deleting trunk or feature-other is refused with a clear error
+func TestContract_LinkedWorktree(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "trunk").Run()
+	exec.Command("git", "-C", repo, "branch", "feature-linked").Run()
+	exec.Command("git", "-C", repo, "branch", "feature-other").Run()
+	linked := filepath.Join(t.TempDir(), "linked")
+	other := filepath.Join(t.TempDir(), "other")
+	require.NoError(t, exec.Command("git", "-C", repo, "worktree", "add", linked, "feature-linked").Run())
+	require.NoError(t, exec.Command("git", "-C", repo, "worktree", "add", other, "feature-other").Run())
+
+	stdout, _, err := runGelete(t, linked, "", "--json")
+	require.NoError(t, err)
+	assert.NotContains(t, stdout, `"trunk"`, "The main worktree's branch should not be listed")
+	assert.Contains(t, stdout, `"feature-other"`)
+
+	_, stderr, err := runGelete(t, linked, "", "--yes", "trunk")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "checked out in the main worktree")
+
+	_, stderr, err = runGelete(t, linked, "", "--yes", "feature-other")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "checked out in worktree")
+
+	output, _ := exec.Command("git", "-C", repo, "branch", "--format=%(refname:short)").Output()
+	assert.Contains(t, string(output), "trunk")
+	assert.Contains(t, string(output), "feature-other")
+}
+
+// TestContract_ConfigFiles tests defaults from the global config file and the repository's .gelete.yaml
+// Given: A global config and a repo config protecting "staging", with an unknown key
+// Then: `gelete config` prints the merged config, and the protected branch is refused
+func TestContract_ConfigFiles(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "staging").Run()
+
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	require.NoError(t, os.MkdirAll(filepath.Join(configHome, "gelete"), 0o755))
+	err := os.WriteFile(filepath.Join(configHome, "gelete", "config.yaml"), []byte("sort: age\ncolor: false\n"), 0o644)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(repo, ".gelete.yaml"), []byte("protect: [staging]\nsort: -age\nfavourite: yes\n"), 0o644)
+	require.NoError(t, err)
+
+	stdout, stderr, err := runGelete(t, repo, "", "config")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "# Loaded from:")
+	assert.Contains(t, stdout, "- staging")
+	assert.Contains(t, stdout, "sort: -age", "The repo file should override the global file")
+	assert.Contains(t, stdout, "color: false")
+	assert.Contains(t, stdout, "theme: auto")
+	assert.Contains(t, stdout, "date_format: relative")
+	assert.Contains(t, stderr, "Warning: unknown key 'favourite'")
+
+	stdout, _, err = runGelete(t, repo, "", "--yes", "staging")
+	requireExitCode(t, err, 2)
+	assert.Contains(t, stdout, "staging: branch is protected")
+
+	_, stderr, err = runGelete(t, repo, "", "--theme", "solarized", "--yes", "staging")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "invalid theme 'solarized' (expected auto, dark or light)")
+}
+
+// TestContract_StaleWorktree tests deleting a branch whose worktree directory was removed by hand
+// Given: A branch checked out in a worktree whose directory was deleted with rm -rf
+// Then: Prune the stale worktree and delete the branch
+func TestContract_StaleWorktree(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "stale").Run()
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	require.NoError(t, exec.Command("git", "-C", repo, "worktree", "add", worktreePath, "stale").Run())
+	require.NoError(t, os.RemoveAll(worktreePath))
+
+	stdout, _, err := runGelete(t, repo, "", "--yes", "stale")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "Deleted branch stale")
+
+	err = exec.Command("git", "-C", repo, "rev-parse", "--verify", "refs/heads/stale").Run()
+	assert.Error(t, err, "Branch should be deleted")
+}
+
+// TestContract_RepoFlag tests running gelete on another repository and from a subdirectory
+// Given: User runs gelete with --repo outside the repository, or from a nested subdirectory
+// Then: Delete the branches of that repository and exit with code 0
+func TestContract_RepoFlag(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+	exec.Command("git", "-C", repo, "branch", "feature-b").Run()
+
+	stdout, _, err := runGelete(t, t.TempDir(), "", "--repo", repo, "--yes", "feature-a")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "Deleted branch feature-a")
+
+	nested := filepath.Join(repo, "src", "pkg")
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+	stdout, _, err = runGelete(t, nested, "", "--json")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, `"feature-b"`)
+
+	// Relative paths and symlinks are resolved before gelete runs anything
+	exec.Command("git", "-C", repo, "branch", "feature-c").Run()
+	link := filepath.Join(t.TempDir(), "link")
+	require.NoError(t, os.Symlink(repo, link))
+	stdout, _, err = runGelete(t, filepath.Dir(link), "", "--repo", "link", "--yes", "feature-c")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Deleted branch feature-c")
+
+	_, stderr, err := runGelete(t, repo, "", "--repo", filepath.Join(repo, "missing"))
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "is not a directory")
+}
+
+// TestContract_Completion tests dynamic shell completion of branch names
+// Given: User presses tab after `gelete`, after a branch, after --pattern, or after `gelete -r`
+// Then: Offer the deletable branches not given yet, and nothing outside a git repository or with --remote
+func TestContract_Completion(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+	exec.Command("git", "-C", repo, "branch", "feature-b").Run()
+
+	stdout, _, err := runGelete(t, repo, "", "__complete", "")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "feature-a\nfeature-b\n")
+
+	stdout, _, err = runGelete(t, repo, "", "__complete", "feature-a", "")
+	require.NoError(t, err)
+	assert.NotContains(t, stdout, "feature-a", "Branches already given should not be offered again")
+	assert.Contains(t, stdout, "feature-b")
+
+	stdout, _, err = runGelete(t, repo, "", "__complete", "--pattern", "")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "feature-a\nfeature-b\n")
+
+	stdout, _, err = runGelete(t, t.TempDir(), "", "__complete", "")
+	require.NoError(t, err, "Completion should not fail outside a git repository")
+	assert.NotContains(t, stdout, "feature-")
+
+	remote := t.TempDir()
+	exec.Command("git", "init", "-q", "--bare", remote).Run()
+	exec.Command("git", "-C", repo, "remote", "add", "origin", remote).Run()
+	require.NoError(t, exec.Command("git", "-C", repo, "push", "-q", "origin", "feature-a").Run())
+	exec.Command("git", "-C", repo, "fetch", "-q", "origin").Run()
+	stdout, _, err = runGelete(t, repo, "", "__complete", "-r", "")
+	require.NoError(t, err)
+	assert.NotContains(t, stdout, "feature-", "Branch arguments are refused with --remote, so none should be offered")
+
+	stdout, _, err = runGelete(t, repo, "", "completion", "zsh")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "compdef _gelete gelete")
+}
+
+// TestContract_WorktreesNone tests the worktrees subcommand in a repository without linked worktrees
+// Given: User runs `gelete worktrees` and only the main worktree exists
+// Then: Report that there is nothing to remove and exit with code 0
+func TestContract_WorktreesNone(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	stdout, _, err := runGelete(t, repo, "", "worktrees")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "No worktrees to remove.")
+}
+
+// TestContract_AllRepos tests --all-repos without a terminal
+// Given: A directory with a repository that has a gone branch, one without branches, a broken one and a plain directory
+// Then: List the repositories with their branch counts, and with --gone --yes delete the gone branches of each
+// repository with a summary, failing because of the broken repository
+func TestContract_AllRepos(t *testing.T) {
+	root := t.TempDir()
+	remote := t.TempDir()
+	exec.Command("git", "init", "-q", "--bare", remote).Run()
+
+	api := filepath.Join(root, "api")
+	initRepo(t, api)
+	exec.Command("git", "-C", api, "remote", "add", "origin", remote).Run()
+	exec.Command("git", "-C", api, "checkout", "-q", "-b", "gone").Run()
+	exec.Command("git", "-C", api, "push", "-q", "-u", "origin", "gone").Run()
+	exec.Command("git", "-C", api, "checkout", "-q", "-").Run()
+	exec.Command("git", "-C", api, "push", "-q", "origin", "--delete", "gone").Run()
+	exec.Command("git", "-C", api, "branch", "feature").Run()
+
+	initRepo(t, filepath.Join(root, "web"))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "broken"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "broken", ".git"), []byte("gitdir: /nonexistent\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "notes"), 0o755))
+
+	stdout, stderr, err := runGelete(t, t.TempDir(), "", "--all-repos", root)
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stdout, "/api: 2 branch(es), 1 gone\n")
+	assert.Contains(t, stdout, "/broken: error: ")
+	assert.Contains(t, stdout, "/web: no branches to delete\n")
+	assert.NotContains(t, stdout, "notes", "Directories without a repository should not be listed")
+	assert.Contains(t, stderr, "failed to read 1 of 3 repositories")
+
+	stdout, _, err = runGelete(t, t.TempDir(), "", "--all-repos", root, "--gone", "--yes")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stdout, "✓ Deleted branch gone")
+	assert.Regexp(t, `Summary:\n  \S+/api: 1 deleted, 0 failed\n  \S+/broken: error\n  \S+/web: no gone branches\n`, stdout)
+
+	output, _ := exec.Command("git", "-C", api, "branch", "--format=%(refname:short)").Output()
+	assert.NotContains(t, strings.Fields(string(output)), "gone")
+	assert.Contains(t, strings.Fields(string(output)), "feature", "Branches with an upstream should be kept")
+
+	_, stderr, err = runGelete(t, t.TempDir(), "", "--all-repos", root, "--yes")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "--all-repos only deletes without the TUI with --gone")
+
+	_, stderr, err = runGelete(t, t.TempDir(), "", "--all-repos", root, "--pattern", "tmp/*")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "--all-repos cannot be combined with --pattern")
+}
+
+// initRepo creates a git repository with an initial commit at dir.
+func initRepo(t *testing.T, dir string) {
+	t.Helper()
+
+	exec.Command("git", "init", "-q", dir).Run()
+	exec.Command("git", "-C", dir, "config", "user.name", "Test User").Run()
+	exec.Command("git", "-C", dir, "config", "user.email", "test@example.com").Run()
+	exec.Command("git", "-C", dir, "commit", "-q", "--allow-empty", "-m", "Initial commit").Run()
+}
+
+// fixedGitEnv returns the environment for git commands creating commits with fixed authors and dates,
+// so that their SHAs are the same on every run
+func fixedGitEnv() []string {
+	return append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test User", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_AUTHOR_DATE=2024-01-15T12:00:00Z",
+		"GIT_COMMITTER_NAME=Test User", "GIT_COMMITTER_EMAIL=test@example.com", "GIT_COMMITTER_DATE=2024-01-15T12:00:00Z")
+}
+
+// setupFixedRepo creates a temporary git repository on branch main whose initial commit has a fixed SHA,
+// for output compared against golden files
+func setupFixedRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main", "--object-format=sha1", dir},
+		{"-C", dir, "commit", "-q", "--allow-empty", "-m", "Initial commit"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Env = fixedGitEnv()
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, out)
+	}
+	return dir
+}
+
+// updateGolden rewrites the golden files in testdata instead of comparing them
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// assertGolden compares got with the golden file at path, or rewrites the file with -update
+func assertGolden(t *testing.T, path, got string) {
+	t.Helper()
+
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+	}
+
+	golden, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), got)
+}
+
+// TestContract_Lang tests that --lang selects the language of the messages and that an unsupported one is refused.
+func TestContract_Lang(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	stdout, _, err := runGelete(t, repo, "", "--lang", "ja")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "削除できるブランチはありません。")
+
+	_, stderr, err := runGelete(t, repo, "", "--lang", "fr")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "invalid --lang: unsupported language 'fr': must be one of en, ja")
+}
+
+// buildGelete builds the gelete binary into dir and sets geleteBinary to its path.
+func buildGelete(dir string) error {
+	root, err := findProjectRoot()
+	if err != nil {
+		return err
+	}
+
+	binary := filepath.Join(dir, "gelete")
+	buildCmd := exec.Command("go", "build", "-o", binary, ".")
+	buildCmd.Dir = root
+	if output, err := buildCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w\n%s", err, output)
+	}
+	geleteBinary = binary
+	return nil
+}
+
+// runGelete runs the built gelete binary in dir with the given stdin and arguments.
+func runGelete(t *testing.T, dir, stdin string, args ...string) (string, string, error) {
+	t.Helper()
+
+	cmd := exec.Command(geleteBinary, args...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+// requireExitCode asserts that err is an exit error with the given code.
+func requireExitCode(t *testing.T, err error, code int) {
+	t.Helper()
+
+	exitErr, ok := err.(*exec.ExitError)
+	require.True(t, ok, "Expected a non-zero exit, got %v", err)
+	assert.Equal(t, code, exitErr.ExitCode())
+}
+
+// getProjectRoot returns the path to the project root directory (see findProjectRoot).
 func getProjectRoot(t *testing.T) string {
 	t.Helper()
 
-	// Get current working directory
-	cwd, err := os.Getwd()
+	root, err := findProjectRoot()
 	require.NoError(t, err)
+	return root
+}
 
-	// Navigate up to project root (from tests/contract/ to project root)
-	// This assumes we're in tests/contract/
-	root := cwd
-	for i := 0; i < 2; i++ {
-		parent := strings.TrimSuffix(root, "/tests/contract")
-		parent = strings.TrimSuffix(parent, "/tests")
-		if parent != root {
-			root = parent
-			break
+// findProjectRoot returns the path to the project root directory: the closest directory
+// above the working directory (tests/contract) that holds go.mod.
+func findProjectRoot() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	// Walk up with filepath rather than splitting on "/", so Windows paths work too
+	for dir := cwd; ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
 		}
-		// If not in expected path, try going up one level
-		parts := strings.Split(root, "/")
-		if len(parts) > 1 {
-			root = strings.Join(parts[:len(parts)-1], "/")
+		if filepath.Dir(dir) == dir {
+			return "", fmt.Errorf("no go.mod found above %s", cwd)
 		}
 	}
+}
 
-	return root
+// TestContract_Prune tests `gelete prune`
+// Given: A branch whose upstream is gone, a merged branch, an unmerged branch and a worktree whose directory was deleted
+// Then: List them by category, abort on "n", and with --yes prune the worktree and delete the branches,
+// keeping the unmerged branch, the current branch and protected branches
+func TestContract_Prune(t *testing.T) {
+	repo := setupTestRepo(t)
+	remote := t.TempDir()
+	exec.Command("git", "init", "-q", "--bare", remote).Run()
+	exec.Command("git", "-C", repo, "remote", "add", "origin", remote).Run()
+
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "gone").Run()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Squash-merged upstream").Run()
+	exec.Command("git", "-C", repo, "push", "-q", "-u", "origin", "gone").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-").Run()
+	exec.Command("git", "-C", repo, "push", "-q", "origin", "--delete", "gone").Run()
+
+	exec.Command("git", "-C", repo, "branch", "merged").Run()
+	exec.Command("git", "-C", repo, "branch", "develop").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "unmerged").Run()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-").Run()
+
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	exec.Command("git", "-C", repo, "worktree", "add", "-q", "--detach", worktreePath).Run()
+	require.NoError(t, os.RemoveAll(worktreePath))
+
+	stdout, _, err := runGelete(t, repo, "n\n", "prune")
+	requireExitCode(t, err, 3)
+	assert.Contains(t, stdout, "Branches whose upstream is gone:\n  • gone\n")
+	assert.Regexp(t, `Branches merged into \S+:\n  • merged\n`, stdout)
+	assert.Contains(t, stdout, "Worktrees whose directory is missing:")
+	assert.Contains(t, stdout, "Prune 1 worktree(s) and delete 2 branch(es)? [y/N]")
+	assert.NotContains(t, stdout, "unmerged")
+	assert.NotContains(t, stdout, "develop")
+
+	stdout, stderr, err := runGelete(t, repo, "", "prune", "--yes", "--force")
+	require.NoError(t, err, stderr)
+	assert.Contains(t, stdout, "✓ Pruned worktree")
+	assert.Contains(t, stdout, "1 stale worktree(s) pruned • 1 of 1 gone branch(es) deleted • 1 of 1 merged branch(es) deleted")
+
+	current, _ := exec.Command("git", "-C", repo, "branch", "--show-current").Output()
+	output, _ := exec.Command("git", "-C", repo, "branch", "--format=%(refname:short)").Output()
+	assert.ElementsMatch(t, []string{strings.TrimSpace(string(current)), "develop", "unmerged"}, strings.Fields(string(output)))
+	worktrees, _ := exec.Command("git", "-C", repo, "worktree", "list").Output()
+	assert.NotContains(t, string(worktrees), worktreePath)
+
+	stdout, _, err = runGelete(t, repo, "", "prune")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Nothing to prune.")
+}
+
+// TestContract_RemotePrune tests gelete remote-prune without a terminal
+// Given: A repository with two remotes whose branches were deleted on the server, and one without remotes
+// Then: Print the stale refs of the --remote remotes, prune all of them with --yes, and exit 0 without remotes
+func TestContract_RemotePrune(t *testing.T) {
+	repo := setupTestRepo(t)
+	for _, remote := range []string{"origin", "upstream"} {
+		bare := filepath.Join(t.TempDir(), remote+".git")
+		exec.Command("git", "init", "-q", "--bare", bare).Run()
+		exec.Command("git", "-C", repo, "remote", "add", remote, bare).Run()
+		exec.Command("git", "-C", repo, "push", "-q", remote, "HEAD:refs/heads/stale", "HEAD:refs/heads/kept").Run()
+		exec.Command("git", "-C", bare, "branch", "-D", "stale").Run()
+	}
+
+	stdout, stderr, err := runGelete(t, repo, "", "remote-prune")
+	require.NoError(t, err, stderr)
+	assert.Equal(t, "origin/stale\n", stdout, "Only origin should be checked by default")
+
+	_, stderr, err = runGelete(t, repo, "", "remote-prune", "--remote", "nosuch")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "unknown remote 'nosuch' (remotes: origin, upstream)")
+
+	stdout, stderr, err = runGelete(t, repo, "", "remote-prune", "--remote", "origin", "--remote", "upstream", "--yes")
+	require.NoError(t, err, stderr)
+	assert.Equal(t, "✓ Pruned origin/stale\n✓ Pruned upstream/stale\n", stdout)
+
+	refs, _ := exec.Command("git", "-C", repo, "branch", "-r", "--format=%(refname:short)").Output()
+	assert.ElementsMatch(t, []string{"origin/kept", "upstream/kept"}, strings.Fields(string(refs)))
+
+	stdout, _, err = runGelete(t, repo, "", "remote-prune", "--remote", "upstream")
+	require.NoError(t, err)
+	assert.Equal(t, "No stale remote-tracking refs.\n", stdout)
+
+	stdout, _, err = runGelete(t, setupTestRepo(t), "", "remote-prune")
+	require.NoError(t, err)
+	assert.Equal(t, "No remotes configured; nothing to prune.\n", stdout)
+}
+
+// TestContract_AuditLog verifies that deletions are appended to the audit log and printed by gelete log,
+// and that a log that can't be written only warns.
+func TestContract_AuditLog(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "merged").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "unmerged").Run()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-").Run()
+	sha, _ := exec.Command("git", "-C", repo, "rev-parse", "--short", "merged").Output()
+
+	stdout, _, err := runGelete(t, repo, "", "log")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "No deletions recorded in")
+
+	_, _, err = runGelete(t, repo, "", "--yes", "merged", "unmerged")
+	requireExitCode(t, err, 2)
+	_, stderr, err := runGelete(t, repo, "", "--yes", "--force", "unmerged")
+	require.NoError(t, err, stderr)
+
+	data, err := os.ReadFile(filepath.Join(repo, ".git", "gelete-log.jsonl"))
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), 3, "Every deletion should be one line")
+
+	stdout, _, err = runGelete(t, repo, "", "log")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 3)
+	assert.Regexp(t, `^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}  ✓ merged \(was `+strings.TrimSpace(string(sha))+`\)$`, lines[0])
+	assert.Contains(t, lines[1], "✗ unmerged: not fully merged")
+	assert.Regexp(t, `✓ unmerged \(was \w+ • forced\)$`, lines[2])
+
+	stdout, _, err = runGelete(t, repo, "", "log", "-n", "1")
+	require.NoError(t, err)
+	assert.Equal(t, lines[2]+"\n", stdout)
+
+	// A log in a missing directory can't be written, but the deletion goes on
+	exec.Command("git", "-C", repo, "branch", "other").Run()
+	unwritable := filepath.Join(t.TempDir(), "missing", "audit.jsonl")
+	stdout, stderr, err = runGelete(t, repo, "", "--yes", "--audit-log", unwritable, "other")
+	require.NoError(t, err, stderr)
+	assert.Contains(t, stdout, "✓ Deleted branch other")
+	assert.Contains(t, stderr, "Warning: failed to write audit log")
+}
+
+// TestContract_PlainListWithoutTerminal tests the fallback for runs without a terminal
+// Given: User runs gelete with stdout redirected to a file, e.g. in a CI job
+// Then: Print the deletable branches one per line instead of starting the TUI, and exit with code 0
+func TestContract_PlainListWithoutTerminal(t *testing.T) {
+	repo := setupTestRepo(t)
+	for _, branch := range []string{"feature-b", "feature-a", "tmp/spike"} {
+		exec.Command("git", "-C", repo, "branch", branch).Run()
+	}
+	exec.Command("git", "-C", repo, "checkout", "-q", "feature-b").Run()
+	exec.Command("git", "-C", repo, "commit", "-q", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-").Run()
+
+	// runToFile runs gelete with stdout redirected to a file and returns its content
+	runToFile := func(args ...string) string {
+		t.Helper()
+		file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+		require.NoError(t, err)
+		defer file.Close()
+
+		cmd := exec.Command(geleteBinary, args...)
+		cmd.Dir = repo
+		cmd.Stdout = file
+		require.NoError(t, cmd.Run())
+
+		content, err := os.ReadFile(file.Name())
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	assert.Equal(t, "feature-a\nfeature-b\ntmp/spike\n", runToFile(), "Expected a plain listing without escape codes")
+	assert.Equal(t, "tmp/spike\n", runToFile("--pattern", "tmp/*"), "Patterns without --yes list the matching branches")
+	assert.Equal(t, "feature-b\n", runToFile("--unmerged-only"))
+	assert.Contains(t, runToFile("--json"), `"name": "feature-a"`)
+	assert.True(t, exec.Command("git", "-C", repo, "rev-parse", "--verify", "tmp/spike").Run() == nil, "Nothing should be deleted")
+
+	_, stderr, err := runGelete(t, repo, "", "--interactive")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "error running UI", "--interactive starts the TUI even without a terminal")
+
+	stdout, _, err := runGelete(t, repo, "", "--pattern", "tmp/*", "--yes")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "Deleted branch tmp/spike")
+}
+
+// writePlan writes a plan file of the repository listing the branches at their current tips,
+// with the given actions each
+func writePlan(t *testing.T, repo string, actions map[string]string) string {
+	t.Helper()
+
+	root, err := exec.Command("git", "-C", repo, "rev-parse", "--show-toplevel").Output()
+	require.NoError(t, err)
+	content := "version: 1\nrepository: " + strings.TrimSpace(string(root)) + "\ncreated: 2026-01-02T03:04:05Z\nbranches:\n"
+	for _, branch := range []string{"feature-a", "feature-b", "moved", "unmerged"} {
+		action, planned := actions[branch]
+		if !planned {
+			continue
+		}
+		sha, err := exec.Command("git", "-C", repo, "rev-parse", branch).Output()
+		require.NoError(t, err)
+		content += "  - branch: " + branch + "\n    sha: " + strings.TrimSpace(string(sha)) + "\n    actions: [" + action + "]\n"
+	}
+
+	path := filepath.Join(t.TempDir(), "cleanup.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+// TestContract_Apply tests applying a plan written by gelete plan
+// Given: User runs `gelete apply <plan>` after some planned branches changed
+// Then: Skip the branches that moved or disappeared with a warning, delete the others, and refuse a stale plan
+func TestContract_Apply(t *testing.T) {
+	repo := setupTestRepo(t)
+	for _, branch := range []string{"feature-a", "feature-b", "moved", "unmerged"} {
+		exec.Command("git", "-C", repo, "branch", branch).Run()
+	}
+	exec.Command("git", "-C", repo, "checkout", "-q", "unmerged").Run()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-").Run()
+
+	plan := writePlan(t, repo, map[string]string{"feature-a": "delete", "feature-b": "delete", "moved": "delete", "unmerged": "force-delete"})
+	exec.Command("git", "-C", repo, "branch", "-D", "feature-b").Run()
+	exec.Command("git", "-C", repo, "branch", "-f", "moved", "unmerged").Run()
+
+	stdout, stderr, err := runGelete(t, repo, "", "apply", plan)
+	require.NoError(t, err, stderr)
+	assert.Contains(t, stdout, "✓ Deleted branch feature-a")
+	assert.Contains(t, stdout, "✓ Force deleted branch unmerged")
+	assert.Contains(t, stdout, "2 deleted • 2 skipped • 0 failed")
+	assert.Contains(t, stderr, "Warning: skipping feature-b: the branch no longer exists")
+	assert.Regexp(t, `Warning: skipping moved: its tip moved from \w{7} to \w{7}`, stderr)
+
+	output, _ := exec.Command("git", "-C", repo, "branch", "--list", "moved").Output()
+	assert.NotEmpty(t, strings.TrimSpace(string(output)), "A branch that moved should be kept")
+
+	// Applying the plan again finds nothing left as planned
+	_, stderr, err = runGelete(t, repo, "", "apply", plan)
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "is stale: none of its 4 branch(es)")
+
+	// A plan of another repository is refused
+	other := setupTestRepo(t)
+	_, stderr, err = runGelete(t, other, "", "apply", plan)
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "not in this repository")
+}
+
+// startConflictingRebase commits a file in repo, creates feature-a and merged-one from that commit, and starts
+// a rebase of "ours" onto "theirs" that stops on a conflict
+func startConflictingRebase(t *testing.T, repo string) {
+	t.Helper()
+
+	commitFile := func(content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(repo, "file.txt"), []byte(content), 0o644))
+		exec.Command("git", "-C", repo, "add", "file.txt").Run()
+		exec.Command("git", "-C", repo, "commit", "-m", "Change file").Run()
+	}
+	commitFile("base\n")
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+	exec.Command("git", "-C", repo, "branch", "merged-one").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "theirs").Run()
+	commitFile("theirs\n")
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "ours", "HEAD~1").Run()
+	commitFile("ours\n")
+	exec.Command("git", "-C", repo, "rebase", "theirs").Run()
+}
+
+// TestContract_OperationInProgress tests the interlock against deleting branches in the middle of a rebase
+// Given: A rebase stopped on a conflict in the repository
+// Then: Refuse to start with exit code 1 and tell how to go on, unless --force-start is given
+func TestContract_OperationInProgress(t *testing.T) {
+	repo := setupTestRepo(t)
+	startConflictingRebase(t, repo)
+
+	_, stderr, err := runGelete(t, repo, "", "--yes", "feature-a")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "a rebase is in progress; finish or abort it before running gelete")
+
+	stdout, stderr, err := runGelete(t, repo, "", "--yes", "--force-start", "feature-a")
+	require.NoError(t, err, stderr)
+	assert.Contains(t, stdout, "Deleted branch feature-a")
+}
+
+// TestContract_OperationInProgressSubcommands tests that the interlock also applies to the subcommands
+// deleting branches and to --all-repos
+// Given: A rebase stopped on a conflict in the repository, with a merged branch
+// Then: gelete prune and gelete --all-repos --gone --yes delete nothing, and prune deletes the branch
+// with --force-start
+func TestContract_OperationInProgressSubcommands(t *testing.T) {
+	parent := t.TempDir()
+	repo := filepath.Join(parent, "project")
+	initRepo(t, repo)
+	startConflictingRebase(t, repo)
+	branchExists := func(branch string) bool {
+		return exec.Command("git", "-C", repo, "rev-parse", "--verify", "-q", "refs/heads/"+branch).Run() == nil
+	}
+
+	_, stderr, err := runGelete(t, repo, "", "prune", "--yes")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "a rebase is in progress; finish or abort it before running gelete")
+	assert.True(t, branchExists("merged-one"), "prune should not delete anything during a rebase")
+
+	stdout, stderr, err := runGelete(t, parent, "", "--all-repos", parent, "--gone", "--yes")
+	assert.Error(t, err)
+	assert.Contains(t, stdout+stderr, "a rebase is in progress")
+	assert.True(t, branchExists("merged-one"))
+
+	stdout, stderr, err = runGelete(t, repo, "", "prune", "--yes", "--force-start")
+	require.NoError(t, err, stderr)
+	assert.Contains(t, stdout, "merged-one")
+	assert.False(t, branchExists("merged-one"))
+}
+
+// TestContract_NoCommits tests running gelete right after git init
+// Given: A repository without any commit
+// Then: Say that there is nothing to delete yet and exit with code 0, while --json still prints an empty list
+func TestContract_NoCommits(t *testing.T) {
+	repo := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", repo).Run())
+
+	stdout, stderr, err := runGelete(t, repo, "")
+	require.NoError(t, err, stderr)
+	assert.Equal(t, "Repository has no commits yet; nothing to delete.\n", stdout)
+	assert.Empty(t, stderr)
+
+	stdout, _, err = runGelete(t, repo, "", "--json")
+	require.NoError(t, err)
+	assert.JSONEq(t, "[]", stdout)
+}
+
+// TestContract_Mine tests that --mine lists only the branches whose last commit has user.email
+// as author, or as committer with --mine=committer
+func TestContract_Mine(t *testing.T) {
+	repo := setupTestRepo(t)
+	commitOn := func(branch, authorEmail string) {
+		require.NoError(t, exec.Command("git", "-C", repo, "branch", branch).Run())
+		commit := exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", branch)
+		commit.Env = append(os.Environ(), "GIT_AUTHOR_EMAIL="+authorEmail)
+		require.NoError(t, exec.Command("git", "-C", repo, "checkout", "-q", branch).Run())
+		require.NoError(t, commit.Run())
+		require.NoError(t, exec.Command("git", "-C", repo, "checkout", "-q", "-").Run())
+	}
+	commitOn("mine", "TEST@example.com")
+	commitOn("rebased", "alice@example.com")
+
+	stdout, stderr, err := runGelete(t, repo, "", "--mine")
+	require.NoError(t, err, stderr)
+	assert.Equal(t, "mine\n", stdout, "Emails should be compared ignoring case")
+
+	stdout, stderr, err = runGelete(t, repo, "", "--mine=committer")
+	require.NoError(t, err, stderr)
+	assert.Equal(t, "mine\nrebased\n", stdout)
+
+	require.NoError(t, exec.Command("git", "-C", repo, "config", "user.email", "nobody@example.com").Run())
+	stdout, stderr, err = runGelete(t, repo, "", "--mine")
+	require.NoError(t, err, stderr)
+	assert.Equal(t, "No branches of yours (authored by nobody@example.com).\n", stdout)
+
+	_, stderr, err = runGelete(t, repo, "", "--mine=reviewer")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "invalid --mine 'reviewer' (expected author or committer)")
+
+	_, stderr, err = runGelete(t, repo, "", "--mine", "--remote")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "--mine cannot be combined with --remote")
+}
+
+// TestContract_ArgsWithRemote tests that branch arguments are refused with --remote, since they are deleted
+// as local branches
+// Given: User runs `gelete -r --yes origin/foo` with a local branch literally named origin/foo
+// Then: Display an error, exit with code 1 and keep the local branch
+func TestContract_ArgsWithRemote(t *testing.T) {
+	repo := setupTestRepo(t)
+	require.NoError(t, exec.Command("git", "-C", repo, "branch", "origin/foo").Run())
+
+	_, stderr, err := runGelete(t, repo, "", "-r", "--yes", "origin/foo")
+	requireExitCode(t, err, 1)
+	assert.Contains(t, stderr, "branch arguments cannot be combined with --remote")
+
+	err = exec.Command("git", "-C", repo, "rev-parse", "--verify", "refs/heads/origin/foo").Run()
+	assert.NoError(t, err, "The local branch origin/foo should not be deleted")
+}
+
+// TestContract_LeadingDashBranch tests that a branch named like an option, given after --, is deleted
+// rather than passed to git as a flag
+func TestContract_LeadingDashBranch(t *testing.T) {
+	repo := setupTestRepo(t)
+	require.NoError(t, exec.Command("git", "-C", repo, "update-ref", "refs/heads/-leading-dash", "HEAD").Run())
+
+	stdout, stderr, err := runGelete(t, repo, "")
+	require.NoError(t, err, stderr)
+	assert.Equal(t, "-leading-dash\n", stdout)
+
+	_, stderr, err = runGelete(t, repo, "", "--yes", "--", "-leading-dash")
+	require.NoError(t, err, stderr)
+	out, err := exec.Command("git", "-C", repo, "for-each-ref", "refs/heads/-leading-dash").Output()
+	require.NoError(t, err)
+	assert.Empty(t, string(out), "The branch should be deleted")
+}
+
+// TestContract_ListFormat tests printing the deletable branches with gelete list --format
+// Given: A merged branch and a branch two commits ahead, last committed on 2024-01-15
+// Then: A custom template prints each field, names and json print the same branches,
+// and an invalid template fails with its position before git runs
+func TestContract_ListFormat(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "merged").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "feature").Run()
+	committed := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for _, message := range []string{"First", "Second"} {
+		commit := exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", message)
+		commit.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+committed.Format(time.RFC3339))
+		require.NoError(t, commit.Run())
+	}
+	exec.Command("git", "-C", repo, "checkout", "-q", "-").Run()
+	sha, _ := exec.Command("git", "-C", repo, "rev-parse", "feature").Output()
+
+	stdout, _, err := runGelete(t, repo, "", "list", "--format",
+		`{{.Name}}\t{{.LastCommitDate}}\t{{.Merged}}\t{{.Ahead}}\t{{.Behind}}\t{{.SHA}}\t{{.Upstream}}|{{.UpstreamGone}}|{{.WorktreePath}}`)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 2, "One line per branch: %q", stdout)
+
+	fields := strings.Split(lines[0], "\t")
+	require.Len(t, fields, 7)
+	assert.Equal(t, "feature", fields[0])
+	date, err := time.Parse(time.RFC3339, fields[1])
+	require.NoError(t, err, "LastCommitDate should be RFC 3339")
+	assert.True(t, committed.Equal(date), "Expected %s, got %s", committed, date)
+	assert.Equal(t, []string{"false", "2", "0", strings.TrimSpace(string(sha)), "|false|"}, fields[2:])
+
+	assert.True(t, strings.HasPrefix(lines[1], "merged\t"))
+	assert.Equal(t, "true", strings.Split(lines[1], "\t")[2])
+
+	stdout, _, err = runGelete(t, repo, "", "list", "--format", "{{if .Merged}}{{.Name}}{{end}}")
+	require.NoError(t, err)
+	assert.Equal(t, "merged\n", stdout, "Branches the template prints nothing for should be left out")
+
+	stdout, _, err = runGelete(t, repo, "", "list", "--format", "names")
+	require.NoError(t, err)
+	assert.Equal(t, "feature\nmerged\n", stdout)
+
+	stdout, _, err = runGelete(t, repo, "", "list", "--format", "json")
+	require.NoError(t, err)
+	var branches []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(stdout), &branches))
+	require.Len(t, branches, 2)
+	assert.Equal(t, "feature", branches[0]["name"])
+
+	// Outside a repository, so the error can only come from the template
+	for format, want := range map[string]string{
+		"{{.Name":   "template: format:1: unclosed action",
+		"{{.Nmae}}": `template: format:1:2: executing "format" at <.Nmae>: can't evaluate field Nmae`,
+	} {
+		_, stderr, err := runGelete(t, t.TempDir(), "", "list", "--format", format)
+		requireExitCode(t, err, 1)
+		assert.Contains(t, stderr, "invalid --format: "+want)
+		assert.NotContains(t, stderr, "not a git repository")
+	}
 }
@@ -0,0 +1,124 @@
+//go:build linux
+
+package contract
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openPty opens a pseudo-terminal of 120x40 and returns its master side and the path of its slave side.
+func openPty(t *testing.T) (*os.File, string) {
+	t.Helper()
+
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("No pseudo-terminal available: %v", err)
+	}
+	t.Cleanup(func() { master.Close() })
+
+	unlock := 0
+	var number uint32
+	size := struct{ rows, cols, x, y uint16 }{rows: 40, cols: 120}
+	for _, ioctl := range []struct {
+		request uintptr
+		arg     unsafe.Pointer
+	}{
+		{syscall.TIOCSPTLCK, unsafe.Pointer(&unlock)},
+		{syscall.TIOCGPTN, unsafe.Pointer(&number)},
+		{syscall.TIOCSWINSZ, unsafe.Pointer(&size)},
+	} {
+		if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, master.Fd(), ioctl.request, uintptr(ioctl.arg)); errno != 0 {
+			t.Skipf("Failed to set up the pseudo-terminal: %v", errno)
+		}
+	}
+	return master, fmt.Sprintf("/dev/pts/%d", number)
+}
+
+// ptyOutput collects what a program writes to a pseudo-terminal.
+type ptyOutput struct {
+	mu     sync.Mutex
+	output bytes.Buffer
+}
+
+// Write appends to the output.
+func (o *ptyOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.output.Write(p)
+}
+
+// contains reports whether the program has written s so far.
+func (o *ptyOutput) contains(s string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return strings.Contains(o.output.String(), s)
+}
+
+// TestContract_InterruptedBySignal tests that the TUI quits cleanly on SIGINT
+// Given: User selects a branch in the TUI, then gelete receives SIGINT
+// Then: Exit within a second with code 130, leaving the selected branch undeleted
+func TestContract_InterruptedBySignal(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+	binary := geleteBinary
+
+	master, slavePath := openPty(t)
+	slave, err := os.OpenFile(slavePath, os.O_RDWR|syscall.O_NOCTTY, 0)
+	require.NoError(t, err)
+	defer slave.Close()
+
+	cmd := exec.Command(binary)
+	cmd.Dir = repo
+	cmd.Env = append(os.Environ(), "TERM=xterm-256color")
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	require.NoError(t, cmd.Start())
+	slave.Close()
+
+	output := &ptyOutput{}
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, err := master.Read(buf)
+			output.Write(buf[:n])
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	require.Eventually(t, func() bool { return output.contains("feature-a") }, 10*time.Second, 20*time.Millisecond,
+		"The branch list should render")
+	_, err = master.Write([]byte(" "))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return output.contains("1 selected") }, 5*time.Second, 20*time.Millisecond,
+		"The branch should be selected")
+
+	require.NoError(t, cmd.Process.Signal(os.Interrupt))
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	select {
+	case err := <-exited:
+		requireExitCode(t, err, 130)
+	case <-time.After(time.Second):
+		cmd.Process.Kill()
+		t.Fatal("gelete didn't exit within a second of SIGINT")
+	}
+
+	branches, err := exec.Command("git", "-C", repo, "branch", "--list", "feature-a").Output()
+	require.NoError(t, err)
+	assert.Contains(t, string(branches), "feature-a", "The selected branch should not be deleted")
+}
@@ -0,0 +1,82 @@
+package integration
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompareBranches_OneContainsTheOther tests comparing a branch with the branch it was continued as,
+// including the limit of listed commits.
+func TestCompareBranches_OneContainsTheOther(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	currentBranch, err := git.GetCurrentBranch()
+	require.NoError(t, err)
+	exec.Command("git", "checkout", "-q", "-b", "feature-login").Run()
+	exec.Command("git", "commit", "-q", "--allow-empty", "-m", "Add login form").Run()
+	exec.Command("git", "checkout", "-q", "-b", "feature-login-v2").Run()
+	for i := 1; i <= git.CompareCommitLimit+2; i++ {
+		exec.Command("git", "commit", "-q", "--allow-empty", "-m", fmt.Sprintf("Rework login %d", i)).Run()
+	}
+	exec.Command("git", "checkout", "-q", currentBranch).Run()
+
+	comparison, err := git.CompareBranches("feature-login", "feature-login-v2")
+	require.NoError(t, err)
+	assert.Equal(t, 0, comparison.Ahead)
+	assert.Equal(t, git.CompareCommitLimit+2, comparison.Behind)
+	assert.False(t, comparison.AContainsB)
+	assert.True(t, comparison.BContainsA)
+	assert.Empty(t, comparison.OnlyA)
+	require.Len(t, comparison.OnlyB, git.CompareCommitLimit, "Only the newest commits should be listed")
+	assert.Contains(t, comparison.OnlyB[0], fmt.Sprintf("Rework login %d", git.CompareCommitLimit+2))
+	assert.Equal(t, "feature-login", comparison.Contained())
+
+	// Branches at the same commit contain each other; the second one is reported as contained
+	exec.Command("git", "branch", "feature-login-copy", "feature-login").Run()
+	comparison, err = git.CompareBranches("feature-login", "feature-login-copy")
+	require.NoError(t, err)
+	assert.True(t, comparison.AContainsB && comparison.BContainsA)
+	assert.Equal(t, "feature-login-copy", comparison.Contained())
+}
+
+// TestCompareBranches_Diverged tests comparing branches that each have commits of their own,
+// and that an unknown branch is reported as an error.
+func TestCompareBranches_Diverged(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	currentBranch, err := git.GetCurrentBranch()
+	require.NoError(t, err)
+	exec.Command("git", "checkout", "-q", "-b", "left").Run()
+	exec.Command("git", "commit", "-q", "--allow-empty", "-m", "Left change").Run()
+	exec.Command("git", "checkout", "-q", "-b", "right", currentBranch).Run()
+	exec.Command("git", "commit", "-q", "--allow-empty", "-m", "Right change 1").Run()
+	exec.Command("git", "commit", "-q", "--allow-empty", "-m", "Right change 2").Run()
+	exec.Command("git", "checkout", "-q", currentBranch).Run()
+
+	comparison, err := git.CompareBranches("left", "right")
+	require.NoError(t, err)
+	assert.Equal(t, 1, comparison.Ahead)
+	assert.Equal(t, 2, comparison.Behind)
+	assert.False(t, comparison.AContainsB || comparison.BContainsA)
+	require.Len(t, comparison.OnlyA, 1)
+	assert.Contains(t, comparison.OnlyA[0], "Left change")
+	assert.Len(t, comparison.OnlyB, 2)
+	assert.Empty(t, comparison.Contained())
+
+	_, err = git.CompareBranches("left", "missing")
+	assert.ErrorContains(t, err, "failed to compare 'left' with 'missing'")
+}
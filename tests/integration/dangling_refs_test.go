@@ -0,0 +1,63 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDanglingRefs_TagsAndNotesOnBranchCommits tests that tags and notes on the commits only a branch has
+// are detected, and that those on commits shared with the base are not.
+func TestDanglingRefs_TagsAndNotesOnBranchCommits(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	currentBranch, err := git.GetCurrentBranch()
+	require.NoError(t, err)
+
+	// Tags and a note on the shared initial commit must not be flagged
+	exec.Command("git", "tag", "v0.1").Run()
+	exec.Command("git", "tag", "-a", "v0.2", "-m", "Release 0.2").Run()
+	exec.Command("git", "notes", "add", "-m", "Shared note").Run()
+
+	exec.Command("git", "checkout", "-q", "-b", "experimental").Run()
+	exec.Command("git", "commit", "-q", "--allow-empty", "-m", "First branch commit").Run()
+	exec.Command("git", "tag", "-a", "v0.9-rc1", "-m", "Release candidate").Run()
+	exec.Command("git", "notes", "--ref=review", "add", "-m", "Reviewed").Run()
+	exec.Command("git", "commit", "-q", "--allow-empty", "-m", "Second branch commit").Run()
+	exec.Command("git", "tag", "v0.9-rc2").Run()
+	exec.Command("git", "notes", "add", "-m", "Branch note").Run()
+	exec.Command("git", "checkout", "-q", currentBranch).Run()
+
+	refs, err := git.GetDanglingRefsForBranch("experimental", currentBranch)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v0.9-rc1", "v0.9-rc2"}, refs.Tags, "Only tags on branch-only commits should be reported")
+	assert.Equal(t, 2, refs.Notes, "Notes in every notes ref should be counted, except on shared commits")
+	assert.False(t, refs.IsEmpty())
+
+	// Once merged into the base, the branch has no commits of its own left
+	require.NoError(t, exec.Command("git", "merge", "-q", "--ff-only", "experimental").Run())
+	refs, err = git.GetDanglingRefsForBranch("experimental", currentBranch)
+	require.NoError(t, err)
+	assert.True(t, refs.IsEmpty(), "A merged branch should have no dangling refs")
+}
+
+// TestDanglingRefs_InvalidBase tests that an unknown base is reported as an error.
+func TestDanglingRefs_InvalidBase(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+	exec.Command("git", "branch", "feature").Run()
+
+	_, err := git.GetDanglingRefsForBranch("feature", "no-such-base")
+	assert.ErrorContains(t, err, "failed to list the commits of 'feature' not on 'no-such-base'")
+}
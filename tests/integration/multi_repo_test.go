@@ -0,0 +1,67 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiscoverRepositories tests that only the repositories directly inside a directory are found, in name order,
+// including linked worktrees whose .git is a file.
+func TestDiscoverRepositories(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"web", "api"} {
+		exec.Command("git", "init", "-q", filepath.Join(root, name)).Run()
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "notes"), 0o755))
+	exec.Command("git", "init", "-q", filepath.Join(root, "group", "nested")).Run()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README"), []byte("repositories\n"), 0o644))
+
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "worktree", "add", "-q", "-b", "linked", filepath.Join(root, "linked")).Run()
+
+	repositories, err := git.DiscoverRepositories(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, "api"), filepath.Join(root, "linked"), filepath.Join(root, "web")}, repositories)
+
+	_, err = git.DiscoverRepositories(filepath.Join(root, "missing"))
+	assert.Error(t, err)
+}
+
+// TestSummarizeRepositories tests counting the branches of several repositories, with a broken repository
+// reported on its own without failing the others.
+func TestSummarizeRepositories(t *testing.T) {
+	root := t.TempDir()
+
+	api := setupTestRepo(t)
+	exec.Command("git", "-C", api, "branch", "feature-a").Run()
+	exec.Command("git", "-C", api, "branch", "feature-b").Run()
+	exec.Command("git", "-C", api, "branch", "--set-upstream-to", "feature-a", "feature-b").Run()
+	exec.Command("git", "-C", api, "config", "branch.feature-b.merge", "refs/heads/deleted").Run()
+
+	web := setupTestRepo(t)
+
+	broken := filepath.Join(root, "broken")
+	require.NoError(t, os.MkdirAll(broken, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(broken, ".git"), []byte("gitdir: /nonexistent\n"), 0o644))
+
+	summaries := git.SummarizeRepositories([]string{api, broken, web})
+	require.Len(t, summaries, 3)
+
+	assert.Equal(t, api, summaries[0].Dir)
+	assert.NoError(t, summaries[0].Err)
+	assert.Equal(t, 2, summaries[0].Branches)
+	assert.Equal(t, 1, summaries[0].Gone)
+
+	assert.Equal(t, broken, summaries[1].Dir)
+	assert.Error(t, summaries[1].Err)
+	assert.Zero(t, summaries[1].Branches)
+
+	assert.NoError(t, summaries[2].Err)
+	assert.Zero(t, summaries[2].Branches)
+}
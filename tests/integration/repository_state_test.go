@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupConflictingBranches creates the branches "ours" and "theirs", both changing the same line of a file
+// from the initial commit, and checks out "ours"
+func setupConflictingBranches(t *testing.T) string {
+	t.Helper()
+
+	repo := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(originalDir) })
+	require.NoError(t, os.Chdir(repo))
+
+	commitFile := func(content, message string) {
+		require.NoError(t, os.WriteFile(filepath.Join(repo, "file.txt"), []byte(content), 0o644))
+		exec.Command("git", "add", "file.txt").Run()
+		exec.Command("git", "commit", "-m", message).Run()
+	}
+	commitFile("base\n", "Add file")
+	exec.Command("git", "checkout", "-q", "-b", "theirs").Run()
+	commitFile("theirs\n", "Change file on theirs")
+	exec.Command("git", "checkout", "-q", "-").Run()
+	exec.Command("git", "checkout", "-q", "-b", "ours").Run()
+	commitFile("ours\n", "Change file on ours")
+	return repo
+}
+
+// TestRepositoryState_Clean tests that a repository without an operation in progress is clean
+func TestRepositoryState_Clean(t *testing.T) {
+	setupConflictingBranches(t)
+
+	state, err := git.GetRepositoryState()
+	require.NoError(t, err)
+	assert.Equal(t, git.StateClean, state)
+	assert.Empty(t, state.String())
+}
+
+// TestRepositoryState_InProgress tests that an operation stopped on a conflict, or a running bisect,
+// is detected until it is aborted
+func TestRepositoryState_InProgress(t *testing.T) {
+	tests := []struct {
+		name  string
+		start []string
+		abort []string
+		want  git.State
+	}{
+		{name: "rebase", start: []string{"rebase", "theirs"}, abort: []string{"rebase", "--abort"}, want: git.StateRebase},
+		{name: "rebase apply", start: []string{"rebase", "--apply", "theirs"}, abort: []string{"rebase", "--abort"}, want: git.StateRebase},
+		{name: "merge", start: []string{"merge", "theirs"}, abort: []string{"merge", "--abort"}, want: git.StateMerge},
+		{name: "cherry-pick", start: []string{"cherry-pick", "theirs"}, abort: []string{"cherry-pick", "--abort"}, want: git.StateCherryPick},
+		{name: "bisect", start: []string{"bisect", "start", "ours", "theirs~1"}, abort: []string{"bisect", "reset"}, want: git.StateBisect},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupConflictingBranches(t)
+
+			// The conflict makes the operation stop, so git exits with an error here
+			exec.Command("git", tt.start...).Run()
+
+			state, err := git.GetRepositoryState()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, state)
+			assert.Equal(t, tt.start[0], state.String())
+
+			require.NoError(t, exec.Command("git", tt.abort...).Run())
+			state, err = git.GetRepositoryState()
+			require.NoError(t, err)
+			assert.Equal(t, git.StateClean, state, "Aborting should end the operation")
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupSuperproject creates a superproject with a submodule checked out at "lib", each with a branch of its
+// own: "super-feature" in the superproject and "lib-feature" in the submodule.
+// Returns the paths of the superproject and of the submodule.
+func setupSuperproject(t *testing.T) (string, string) {
+	t.Helper()
+
+	lib := setupTestRepo(t)
+	exec.Command("git", "-C", lib, "branch", "lib-feature").Run()
+
+	super := setupTestRepo(t)
+	exec.Command("git", "-C", super, "branch", "super-feature").Run()
+	// Cloning a submodule from a local path needs the file protocol, disallowed by default since git 2.38.1
+	output, err := exec.Command("git", "-C", super, "-c", "protocol.file.allow=always", "submodule", "add", lib, "lib").CombinedOutput()
+	require.NoError(t, err, string(output))
+	exec.Command("git", "-C", super, "commit", "-m", "Add submodule").Run()
+
+	// The submodule is a clone, so its branches are created again, from its remote-tracking branches
+	submodule := filepath.Join(super, "lib")
+	require.NoError(t, exec.Command("git", "-C", submodule, "branch", "lib-feature", "origin/lib-feature").Run())
+	exec.Command("git", "-C", submodule, "config", "user.name", "Test User").Run()
+	exec.Command("git", "-C", submodule, "config", "user.email", "test@example.com").Run()
+
+	super, err = filepath.EvalSymlinks(super)
+	require.NoError(t, err)
+	return super, filepath.Join(super, "lib")
+}
+
+// TestSubmodule_OperatesOnInnermostRepository tests that gelete run from inside a submodule, or from a
+// directory of it, lists and deletes the branches of the submodule and leaves the superproject alone.
+func TestSubmodule_OperatesOnInnermostRepository(t *testing.T) {
+	super, submodule := setupSuperproject(t)
+	nested := filepath.Join(submodule, "nested", "dir")
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(nested))
+	defer git.CloseRepository()
+
+	require.NoError(t, git.OpenRepository(""))
+	assert.Equal(t, submodule, git.RepoRoot(), "The submodule is the innermost repository")
+
+	branches, err := git.ListBranches()
+	require.NoError(t, err)
+	assert.Contains(t, branches, "lib-feature")
+	assert.NotContains(t, branches, "super-feature", "Branches of the superproject should not be listed")
+
+	superproject, err := git.GetSuperproject()
+	require.NoError(t, err)
+	assert.Equal(t, super, superproject)
+
+	require.NoError(t, git.DeleteBranch("lib-feature"))
+	assert.False(t, git.BranchExists("lib-feature"))
+
+	superRepo := git.Repository{Dir: super}
+	assert.True(t, superRepo.BranchExists("super-feature"), "The superproject's branches should be untouched")
+	superproject, err = superRepo.GetSuperproject()
+	require.NoError(t, err)
+	assert.Empty(t, superproject, "The superproject is not a submodule")
+}
+
+// TestSubmodule_SuperprojectOperatesOnItsOwnBranches tests that gelete run from the superproject
+// doesn't see the branches of its submodules.
+func TestSubmodule_SuperprojectOperatesOnItsOwnBranches(t *testing.T) {
+	super, submodule := setupSuperproject(t)
+
+	repo, err := git.NewRepository(super)
+	require.NoError(t, err)
+	assert.Equal(t, super, repo.Dir)
+
+	branches, err := repo.ListBranches()
+	require.NoError(t, err)
+	assert.Contains(t, branches, "super-feature")
+	assert.NotContains(t, branches, "lib-feature")
+
+	require.NoError(t, repo.DeleteBranch("super-feature"))
+	assert.True(t, git.Repository{Dir: submodule}.BranchExists("lib-feature"), "The submodule's branches should be untouched")
+}
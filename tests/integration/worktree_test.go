@@ -172,3 +172,110 @@ func TestWorktree_MultipleWorktrees(t *testing.T) {
 	git.DeleteBranch("feature-a")
 	git.DeleteBranch("feature-b")
 }
+
+// TestWorktree_DeletableBranchesFromLinkedWorktree tests listing deletable branches from inside a linked worktree.
+// The branch checked out in the main worktree is in use although it isn't the current branch,
+// so it must be excluded like the branch of the linked worktree itself.
+func TestWorktree_DeletableBranchesFromLinkedWorktree(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+
+	// Check out an unprotected branch in the main worktree, and two branches in linked worktrees
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "trunk").Run()
+	exec.Command("git", "-C", repo, "branch", "feature-linked").Run()
+	exec.Command("git", "-C", repo, "branch", "feature-other").Run()
+	exec.Command("git", "-C", repo, "branch", "plain").Run()
+	linkedPath := filepath.Join(t.TempDir(), "linked")
+	otherPath := filepath.Join(t.TempDir(), "other")
+	require.NoError(t, exec.Command("git", "-C", repo, "worktree", "add", linkedPath, "feature-linked").Run())
+	require.NoError(t, exec.Command("git", "-C", repo, "worktree", "add", otherPath, "feature-other").Run())
+	expectedOtherPath, _ := filepath.EvalSymlinks(otherPath)
+
+	require.NoError(t, os.Chdir(linkedPath))
+
+	branches, err := git.ListDeletableBranches()
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		names = append(names, branch.Name)
+		switch branch.Name {
+		case "feature-other":
+			require.NotNil(t, branch.Worktree, "Branches in other linked worktrees should be annotated")
+			assert.Equal(t, expectedOtherPath, branch.Worktree.Path)
+		case "plain":
+			assert.Nil(t, branch.Worktree, "Branches not checked out should have no worktree")
+		}
+	}
+	assert.Equal(t, []string{"feature-other", "plain"}, names,
+		"The main worktree's branch and the current branch should be excluded")
+
+	// From the main worktree, the linked worktree's branch is annotated rather than excluded
+	require.NoError(t, os.Chdir(repo))
+	branches, err = git.ListDeletableBranches()
+	require.NoError(t, err)
+	require.Len(t, branches, 3)
+	assert.Equal(t, "feature-linked", branches[0].Name)
+	require.NotNil(t, branches[0].Worktree)
+	assert.False(t, branches[0].Worktree.Main)
+}
+
+// TestWorktree_WorktreeStatus tests detecting modified and untracked files in a worktree,
+// and that a worktree whose directory is missing is reported as missing rather than dirty.
+func TestWorktree_WorktreeStatus(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	worktreePath := filepath.Join(t.TempDir(), "feature-1")
+	require.NoError(t, exec.Command("git", "worktree", "add", "-q", "-b", "feature-1", worktreePath).Run())
+	require.NoError(t, os.WriteFile(filepath.Join(worktreePath, "main.go"), []byte("package main\n"), 0o644))
+	exec.Command("git", "-C", worktreePath, "add", "main.go").Run()
+	exec.Command("git", "-C", worktreePath, "commit", "-q", "-m", "Add main.go").Run()
+
+	clean, changed, err := git.WorktreeStatus(worktreePath)
+	require.NoError(t, err)
+	assert.True(t, clean)
+	assert.Zero(t, changed)
+
+	// A modified tracked file and two untracked files, one of them in a new directory
+	require.NoError(t, os.WriteFile(filepath.Join(worktreePath, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(worktreePath, "notes.txt"), []byte("todo\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(worktreePath, "drafts"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(worktreePath, "drafts", "idea.md"), []byte("idea\n"), 0o644))
+
+	clean, changed, err = git.WorktreeStatus(worktreePath)
+	require.NoError(t, err)
+	assert.False(t, clean)
+	assert.Equal(t, 3, changed, "The untracked directory counts as one entry, like in git status")
+
+	// Removing a dirty worktree needs --force, which RemoveDirtyWorktree passes
+	assert.Error(t, git.RemoveWorktree(worktreePath))
+	require.NoError(t, git.RemoveDirtyWorktree(worktreePath))
+
+	_, _, err = git.WorktreeStatus(worktreePath)
+	assert.ErrorIs(t, err, git.ErrWorktreeMissing)
+}
+
+// TestWorktree_RemoveDirtyWorktreeRefusesLocked tests that discarding changes doesn't bypass a worktree lock.
+func TestWorktree_RemoveDirtyWorktreeRefusesLocked(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	worktreePath := filepath.Join(t.TempDir(), "feature-1")
+	require.NoError(t, exec.Command("git", "worktree", "add", "-q", "-b", "feature-1", worktreePath).Run())
+	require.NoError(t, os.WriteFile(filepath.Join(worktreePath, "notes.txt"), []byte("todo\n"), 0o644))
+	exec.Command("git", "worktree", "lock", worktreePath).Run()
+
+	err := git.RemoveDirtyWorktree(worktreePath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "locked")
+	assert.DirExists(t, worktreePath)
+}
@@ -0,0 +1,150 @@
+package unit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/audit"
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useAuditLog sends the audit log to path for the duration of the test.
+func useAuditLog(t *testing.T, path string) {
+	t.Helper()
+	audit.SetPath(path)
+	t.Cleanup(func() { audit.SetPath("") })
+}
+
+// TestAudit_AppendAndLast tests that the log is created on the first append and read back oldest first.
+func TestAudit_AppendAndLast(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	useAuditLog(t, path)
+
+	entries, err := audit.Last(10)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "A missing log has no entries")
+	assert.NoFileExists(t, path, "Reading must not create the log")
+
+	require.NoError(t, audit.Append(audit.Entry{Branch: "first", SHA: "abc1234"}))
+	require.NoError(t, audit.Append(audit.Entry{Branch: "second", Forced: true, Worktree: "/tmp/wt"}))
+	require.NoError(t, audit.Append(audit.Entry{Branch: "third", Error: "branch not found"}))
+
+	entries, err = audit.Last(0)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "first", entries[0].Branch)
+	assert.Equal(t, "abc1234", entries[0].SHA)
+	assert.False(t, entries[0].Time.IsZero(), "Append should stamp the time")
+	assert.True(t, entries[1].Forced)
+	assert.Equal(t, "/tmp/wt", entries[1].Worktree)
+	assert.True(t, entries[2].Failed())
+
+	entries, err = audit.Last(2)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "second", entries[0].Branch)
+	assert.Equal(t, "third", entries[1].Branch)
+}
+
+// TestAudit_ConcurrentAppends tests that concurrent appends don't interleave lines.
+func TestAudit_ConcurrentAppends(t *testing.T) {
+	useAuditLog(t, filepath.Join(t.TempDir(), "audit.jsonl"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, audit.Append(audit.Entry{Branch: strings.Repeat("b", 200), Error: strings.Repeat("e", 200)}))
+		}()
+	}
+	wg.Wait()
+
+	entries, err := audit.Last(0)
+	require.NoError(t, err, "Every line should be a complete entry")
+	assert.Len(t, entries, 50)
+}
+
+// TestAudit_DefaultPathInCommonGitDir tests that the default log is shared by all worktrees of a repository.
+func TestAudit_DefaultPathInCommonGitDir(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	require.NoError(t, exec.Command("git", "-C", repo, "worktree", "add", "-q", "-b", "wt", worktreePath).Run())
+	require.NoError(t, os.Chdir(worktreePath))
+
+	path, err := audit.Path()
+	require.NoError(t, err)
+	gitDir, err := filepath.EvalSymlinks(filepath.Join(repo, ".git"))
+	require.NoError(t, err)
+	resolved, err := filepath.EvalSymlinks(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Equal(t, gitDir, resolved)
+	assert.Equal(t, audit.FileName, filepath.Base(path))
+}
+
+// TestAudit_RecordsTUIDeletions tests that the TUI records deleted and failed branches, including the
+// worktree removed with a branch, and that a log that can't be written only shows a warning.
+func TestAudit_RecordsTUIDeletions(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "plain").Run()
+	exec.Command("git", "branch", "wt").Run()
+	worktreePath := t.TempDir()
+	require.NoError(t, exec.Command("git", "worktree", "add", worktreePath, "wt").Run())
+	sha, err := git.GetBranchSHA("plain")
+	require.NoError(t, err)
+
+	logPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	useAuditLog(t, logPath)
+
+	m := newTestModel("missing", "plain", "wt")
+	m.BranchWorktrees["wt"] = worktreePath
+	m.AuditLog = true
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateDone, m.State)
+	assert.Empty(t, m.AuditError)
+
+	entries, err := audit.Last(0)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	byBranch := make(map[string]audit.Entry)
+	for _, entry := range entries {
+		byBranch[entry.Branch] = entry
+	}
+	assert.True(t, byBranch["missing"].Failed())
+	assert.Equal(t, sha, byBranch["plain"].SHA)
+	assert.False(t, byBranch["plain"].Failed())
+	assert.Empty(t, byBranch["plain"].Worktree)
+	assert.Equal(t, worktreePath, byBranch["wt"].Worktree)
+
+	// The next run can't write the log, but still deletes the branch
+	exec.Command("git", "branch", "other").Run()
+	useAuditLog(t, filepath.Join(t.TempDir(), "missing", "audit.jsonl"))
+	m = newTestModel("other")
+	m.AuditLog = true
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	assert.False(t, git.BranchExists("other"), "A failure to write the audit log must not block deletions")
+	assert.Contains(t, m.AuditError, "failed to write audit log")
+	assert.Contains(t, ansi.Strip(m.View()), "Warning: failed to write audit log")
+}
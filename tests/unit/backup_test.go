@@ -0,0 +1,113 @@
+package unit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupUnmergedBranches creates the given branches with a commit of their own in a test repository,
+// and changes into it for the rest of the test. Returns the tip of each branch.
+func setupUnmergedBranches(t *testing.T, branches ...string) map[string]string {
+	t.Helper()
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(originalDir) })
+	require.NoError(t, os.Chdir(repo))
+
+	base, err := git.GetCurrentBranch()
+	require.NoError(t, err)
+	tips := make(map[string]string)
+	for _, branch := range branches {
+		exec.Command("git", "checkout", "-q", "-b", branch, base).Run()
+		exec.Command("git", "commit", "--allow-empty", "-m", "Only on "+branch).Run()
+		tip, err := exec.Command("git", "rev-parse", "HEAD").Output()
+		require.NoError(t, err)
+		tips[branch] = strings.TrimSpace(string(tip))
+	}
+	exec.Command("git", "checkout", "-q", base).Run()
+	return tips
+}
+
+// TestForceConfirmation_BackupThenDelete tests that b at the force confirmation tags each unmerged branch
+// at its tip before force deleting it, adding a suffix when the tag is taken, and lists the tags when done.
+func TestForceConfirmation_BackupThenDelete(t *testing.T) {
+	tips := setupUnmergedBranches(t, "feature/x", "spike")
+	today := time.Now().Format("2006-01-02")
+	// A backup made earlier today takes the plain name
+	exec.Command("git", "tag", "gelete-backup/spike-"+today, "HEAD").Run()
+
+	m := newTestModel("feature/x", "spike")
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateForceConfirmation, m.State)
+	assert.Contains(t, m.View(), "b: backup then delete")
+
+	m = sendKey(t, m, "b")
+	m = typeConfirmation(t, m, "delete")
+	require.Equal(t, ui.StateDone, m.State)
+	assertResult(t, m, "feature/x", ui.ResultForceDeleted)
+	assertResult(t, m, "spike", ui.ResultForceDeleted)
+	assert.False(t, git.BranchExists("feature/x"))
+	assert.False(t, git.BranchExists("spike"))
+
+	expected := map[string]string{
+		"feature/x": "gelete-backup/feature/x-" + today,
+		"spike":     "gelete-backup/spike-" + today + "-2",
+	}
+	assert.Equal(t, expected, m.BackupTags)
+	for branch, tag := range expected {
+		sha, err := exec.Command("git", "rev-parse", "refs/tags/"+tag).Output()
+		require.NoError(t, err)
+		assert.Equal(t, tips[branch], strings.TrimSpace(string(sha)), "The backup of %s should point at its tip", branch)
+	}
+
+	view := m.View()
+	assert.Contains(t, view, "✓ Backed up 2 branches as tags:")
+	assert.Contains(t, view, "gelete-backup/spike-"+today+"-2 (restore: git branch spike gelete-backup/spike-"+today+"-2)")
+}
+
+// TestForceMode_WritesBackupBundle tests that the branches are written to the backup bundle before they are
+// force deleted, and that they are kept if the bundle can't be written.
+func TestForceMode_WritesBackupBundle(t *testing.T) {
+	tips := setupUnmergedBranches(t, "spike")
+	bundle := filepath.Join(t.TempDir(), "backup.bundle")
+
+	m := newTestModel("spike")
+	m.ForceMode = true
+	m.BackupBundle = bundle
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	m = typeConfirmation(t, m, "delete")
+	require.Equal(t, ui.StateDone, m.State)
+	assertResult(t, m, "spike", ui.ResultForceDeleted)
+	assert.Contains(t, m.View(), "✓ Backed up 1 branch to "+bundle)
+
+	heads, err := exec.Command("git", "bundle", "list-heads", bundle).Output()
+	require.NoError(t, err)
+	assert.Equal(t, tips["spike"]+" refs/heads/spike", strings.TrimSpace(string(heads)))
+
+	exec.Command("git", "branch", "spike", tips["spike"]).Run()
+	m = newTestModel("spike")
+	m.ForceMode = true
+	m.BackupBundle = filepath.Join(t.TempDir(), "missing", "backup.bundle")
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	m = typeConfirmation(t, m, "1")
+	require.Equal(t, ui.StateDone, m.State)
+	assertResult(t, m, "spike", ui.ResultSkipped)
+	assert.Contains(t, m.View(), "backup failed, not force deleted")
+	assert.True(t, git.BranchExists("spike"), "A branch that couldn't be backed up should be kept")
+}
@@ -0,0 +1,123 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/cache"
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingAnalysis returns a compute function for Cache.Analyze that records the branches it is asked about,
+// and finds each of them one commit ahead of the base
+func countingAnalysis(computed *[]string) func([]string) map[string]cache.Result {
+	return func(branches []string) map[string]cache.Result {
+		*computed = append(*computed, branches...)
+		results := make(map[string]cache.Result, len(branches))
+		for _, branch := range branches {
+			results[branch] = cache.Result{Divergence: git.AheadBehind{Ahead: 1}}
+		}
+		return results
+	}
+}
+
+// TestCache_ReusesUnchangedBranches tests that cached results are reused across runs while the branch and
+// base tips stay the same, and that a moved tip is analyzed again.
+func TestCache_ReusesUnchangedBranches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), cache.FileName)
+	branches := []git.BranchInfo{{Name: "feature-a", SHA: "aaa1"}, {Name: "feature-b", SHA: "bbb1"}}
+
+	var computed []string
+	c := cache.LoadFile(path)
+	results := c.Analyze(branches, "base1", countingAnalysis(&computed))
+	assert.ElementsMatch(t, []string{"feature-a", "feature-b"}, computed, "An empty cache should analyze every branch")
+	assert.Len(t, results, 2)
+	require.NoError(t, c.Save())
+
+	computed = nil
+	results = cache.LoadFile(path).Analyze(branches, "base1", countingAnalysis(&computed))
+	assert.Empty(t, computed, "Unchanged branches should be read from the cache")
+	assert.Equal(t, cache.Result{Divergence: git.AheadBehind{Ahead: 1}}, results["feature-b"])
+
+	c = cache.LoadFile(path)
+	branches[1].SHA = "bbb2"
+	c.Analyze(branches, "base1", countingAnalysis(&computed))
+	assert.Equal(t, []string{"feature-b"}, computed, "Only the branch whose tip moved should be analyzed again")
+	require.NoError(t, c.Save())
+
+	computed = nil
+	cache.LoadFile(path).Analyze(branches, "base2", countingAnalysis(&computed))
+	assert.ElementsMatch(t, []string{"feature-a", "feature-b"}, computed, "Moving the base should analyze every branch again")
+}
+
+// TestCache_EvictsDeletedBranches tests that branches that aren't listed anymore are dropped from the cache,
+// and that branches the analysis leaves out aren't cached.
+func TestCache_EvictsDeletedBranches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), cache.FileName)
+
+	var computed []string
+	c := cache.LoadFile(path)
+	c.Analyze([]git.BranchInfo{{Name: "kept", SHA: "k1"}, {Name: "deleted", SHA: "d1"}}, "base1", countingAnalysis(&computed))
+	require.NoError(t, c.Save())
+	assert.Equal(t, 2, cache.LoadFile(path).Len())
+
+	c = cache.LoadFile(path)
+	c.Analyze([]git.BranchInfo{{Name: "kept", SHA: "k1"}}, "base1", countingAnalysis(&computed))
+	require.NoError(t, c.Save())
+	assert.Equal(t, 1, cache.LoadFile(path).Len(), "The deleted branch should be evicted")
+
+	c = cache.LoadFile(path)
+	results := c.Analyze([]git.BranchInfo{{Name: "kept", SHA: "k1"}, {Name: "broken", SHA: "b1"}}, "base1",
+		func([]string) map[string]cache.Result { return nil })
+	require.NoError(t, c.Save())
+	assert.NotContains(t, results, "broken")
+	assert.Equal(t, 1, cache.LoadFile(path).Len(), "A branch that couldn't be analyzed should not be cached")
+}
+
+// TestCache_CorruptFileIsRebuilt tests that an unreadable cache is ignored and replaced on save.
+func TestCache_CorruptFileIsRebuilt(t *testing.T) {
+	for name, content := range map[string]string{
+		"invalid JSON":    "{not json",
+		"unknown version": `{"version": 999, "entries": {"feature": {"branch_sha": "f1", "base_sha": "base1"}}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), cache.FileName)
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+			var computed []string
+			c := cache.LoadFile(path)
+			assert.Equal(t, 0, c.Len())
+			c.Analyze([]git.BranchInfo{{Name: "feature", SHA: "f1"}}, "base1", countingAnalysis(&computed))
+			assert.Equal(t, []string{"feature"}, computed)
+			require.NoError(t, c.Save())
+
+			computed = nil
+			cache.LoadFile(path).Analyze([]git.BranchInfo{{Name: "feature", SHA: "f1"}}, "base1", countingAnalysis(&computed))
+			assert.Empty(t, computed, "The rebuilt cache should be reused")
+		})
+	}
+}
+
+// TestCache_LivesInGitDirectory tests that the cache of a repository is kept in its git directory.
+func TestCache_LivesInGitDirectory(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	base, err := git.ResolveCommit("HEAD")
+	require.NoError(t, err)
+	_, err = git.ResolveCommit("does-not-exist")
+	assert.Error(t, err)
+
+	var computed []string
+	c := cache.Load()
+	c.Analyze([]git.BranchInfo{{Name: "feature", SHA: base}}, base, countingAnalysis(&computed))
+	require.NoError(t, c.Save())
+	assert.FileExists(t, filepath.Join(repo, ".git", cache.FileName))
+	assert.Equal(t, 1, cache.Load().Len())
+}
@@ -0,0 +1,164 @@
+package unit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/audit"
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupLateWorktree creates the branches in a test repository and checks out the first one in a linked worktree,
+// as if the worktree was added after the branch list was loaded. Returns the worktree path.
+func setupLateWorktree(t *testing.T, branches ...string) string {
+	t.Helper()
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(originalDir) })
+	require.NoError(t, os.Chdir(repo))
+
+	for _, branch := range branches {
+		require.NoError(t, exec.Command("git", "branch", branch).Run())
+	}
+	worktreePath := filepath.Join(t.TempDir(), "late")
+	require.NoError(t, exec.Command("git", "worktree", "add", "-q", worktreePath, branches[0]).Run())
+	return git.CanonicalPath(worktreePath)
+}
+
+// deleteSelected selects every branch of the model and confirms their deletion
+func deleteSelected(t *testing.T, m ui.AppModel) ui.AppModel {
+	t.Helper()
+	for _, branch := range m.Branches {
+		m.Selected[branch] = true
+	}
+	m = sendKey(t, m, "d")
+	return sendKey(t, m, "y")
+}
+
+// TestCheckedOut_RemovesWorktreeAndRetries tests that a branch git refuses to delete because it is checked out
+// in a linked worktree is offered for the worktree's removal, and deleted once it is removed.
+func TestCheckedOut_RemovesWorktreeAndRetries(t *testing.T) {
+	worktreePath := setupLateWorktree(t, "late", "plain")
+
+	m := deleteSelected(t, newTestModel("late", "plain"))
+	require.Equal(t, ui.StateCheckedOutConfirmation, m.State)
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "• late")
+	assert.Contains(t, view, worktreePath)
+	assert.NotContains(t, view, "• plain")
+	assertResult(t, m, "plain", ui.ResultDeleted)
+
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateDone, m.State)
+	assertResult(t, m, "late", ui.ResultDeleted)
+	assert.False(t, m.HasFailures())
+	assert.False(t, git.BranchExists("late"))
+	assert.NoDirExists(t, worktreePath)
+}
+
+// TestCheckedOut_DeclineSkips tests that declining the worktree removal skips the branch without failing the run.
+func TestCheckedOut_DeclineSkips(t *testing.T) {
+	worktreePath := setupLateWorktree(t, "late", "plain")
+
+	m := deleteSelected(t, newTestModel("late", "plain"))
+	require.Equal(t, ui.StateCheckedOutConfirmation, m.State)
+
+	m = sendKey(t, m, "n")
+	require.Equal(t, ui.StateDone, m.State)
+	assertResult(t, m, "late", ui.ResultSkipped)
+	assertResult(t, m, "plain", ui.ResultDeleted)
+	assert.False(t, m.HasFailures(), "A declined removal should not count as a failure")
+	assert.Contains(t, ansi.Strip(m.View()), "checked out in "+worktreePath+", worktree removal declined")
+	assert.True(t, git.BranchExists("late"))
+	assert.DirExists(t, worktreePath)
+}
+
+// TestCheckedOut_CancelledMidPhase tests that a branch found checked out in a worktree while the run is being
+// cancelled is skipped with its worktree, rather than left out of the summary.
+func TestCheckedOut_CancelledMidPhase(t *testing.T) {
+	worktreePath := setupLateWorktree(t, "late", "plain")
+	useAuditLog(t, filepath.Join(t.TempDir(), "audit.jsonl"))
+
+	m := newTestModel("late", "plain")
+	m.AuditLog = true
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = next.(ui.AppModel)
+	require.Equal(t, ui.StateDeleting, m.State)
+
+	// Cancel while the deletion of "late", checked out in the worktree, is in flight
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	m = runCmds(next.(ui.AppModel), cmd)
+	require.Equal(t, ui.StateDone, m.State)
+	assert.Empty(t, m.CheckedOutWorktrees)
+
+	late, ok := m.Result("late")
+	require.True(t, ok, "The checked-out branch should be in the summary")
+	assert.Equal(t, ui.ResultSkipped, late.Status)
+	assert.Equal(t, worktreePath, late.CheckedOutIn)
+	assert.Contains(t, ansi.Strip(m.View()), "checked out in "+worktreePath+", deletion cancelled")
+	assertResult(t, m, "plain", ui.ResultSkipped)
+
+	// Like the other skipped branches, it isn't recorded as a failed deletion in the audit log
+	entries, err := audit.Last(0)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.True(t, git.BranchExists("late"))
+}
+
+// TestCheckedOut_DirtyWorktree tests that a worktree with uncommitted changes is only removed with D,
+// and that its branch is skipped otherwise.
+func TestCheckedOut_DirtyWorktree(t *testing.T) {
+	for _, tt := range []struct {
+		key     string
+		deleted bool
+	}{
+		{key: "y"},
+		{key: "D", deleted: true},
+	} {
+		t.Run(tt.key, func(t *testing.T) {
+			worktreePath := setupLateWorktree(t, "late")
+			require.NoError(t, os.WriteFile(filepath.Join(worktreePath, "notes.txt"), []byte("draft\n"), 0o644))
+
+			m := deleteSelected(t, newTestModel("late"))
+			require.Equal(t, ui.StateCheckedOutConfirmation, m.State)
+			assert.Contains(t, ansi.Strip(m.View()), "1 modified or untracked file(s) would be lost")
+
+			m = sendKey(t, m, tt.key)
+			require.Equal(t, ui.StateDone, m.State)
+			assert.Equal(t, !tt.deleted, git.BranchExists("late"))
+			if tt.deleted {
+				assertResult(t, m, "late", ui.ResultDeleted)
+				return
+			}
+			assertResult(t, m, "late", ui.ResultSkipped)
+			assert.DirExists(t, worktreePath)
+		})
+	}
+}
+
+// TestCheckedOut_UnmergedBranchGoesToForceConfirmation tests that an unmerged branch retried after removing its
+// worktree is refused as unmerged, and offered for force deletion like the others.
+func TestCheckedOut_UnmergedBranchGoesToForceConfirmation(t *testing.T) {
+	setupUnmergedBranches(t, "spike")
+	worktreePath := filepath.Join(t.TempDir(), "spike")
+	require.NoError(t, exec.Command("git", "worktree", "add", "-q", worktreePath, "spike").Run())
+
+	m := deleteSelected(t, newTestModel("spike"))
+	require.Equal(t, ui.StateCheckedOutConfirmation, m.State)
+
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateForceConfirmation, m.State)
+	m = typeConfirmation(t, sendKey(t, m, "y"), "delete")
+	require.Equal(t, ui.StateDone, m.State)
+	assertResult(t, m, "spike", ui.ResultForceDeleted)
+}
@@ -0,0 +1,89 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClipboard records the text copied to it.
+type fakeClipboard struct {
+	copied []string
+	err    error
+}
+
+func (c *fakeClipboard) Copy(text string) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.copied = append(c.copied, text)
+	return nil
+}
+
+// TestOSC52_WritesSequence tests the OSC 52 sequence, and its tmux passthrough wrapping.
+func TestOSC52_WritesSequence(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte("feature-x\nfix/y"))
+
+	var out bytes.Buffer
+	require.NoError(t, ui.OSC52{Out: &out}.Copy("feature-x\nfix/y"))
+	assert.Equal(t, "\x1b]52;c;"+encoded+"\x07", out.String())
+
+	out.Reset()
+	require.NoError(t, ui.OSC52{Out: &out, Tmux: true}.Copy("feature-x\nfix/y"))
+	assert.Equal(t, "\x1bPtmux;\x1b\x1b]52;c;"+encoded+"\x07\x1b\\", out.String())
+}
+
+// TestNewTerminalClipboard tests that OSC 52 is used unless the terminal is known not to support it.
+func TestNewTerminalClipboard(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+	var out bytes.Buffer
+
+	assert.Equal(t, ui.OSC52{Out: &out}, ui.NewTerminalClipboard(&out, env(map[string]string{"TERM": "xterm-256color"})))
+	assert.Equal(t, ui.OSC52{Out: &out, Tmux: true},
+		ui.NewTerminalClipboard(&out, env(map[string]string{"TERM": "tmux-256color", "TMUX": "/tmp/tmux-0/default,1,0"})))
+	assert.Equal(t, ui.SystemClipboard{}, ui.NewTerminalClipboard(&out, env(map[string]string{"TERM": "linux"})))
+	assert.Equal(t, ui.SystemClipboard{}, ui.NewTerminalClipboard(&out, env(nil)))
+}
+
+// TestCopy_CopiesBranchNames tests that y copies the branch under the cursor, or the selected branches,
+// and that the status message clears on the next key press.
+func TestCopy_CopiesBranchNames(t *testing.T) {
+	board := &fakeClipboard{}
+	m := newTestModel("alpha", "beta", "gamma")
+	m.Clipboard = board
+	assert.Contains(t, m.View(), "y: copy name(s)")
+
+	m = sendKey(t, m, "j")
+	m = sendKey(t, m, "y")
+	assert.Equal(t, []string{"beta"}, board.copied)
+	assert.Contains(t, ansi.Strip(m.View()), "copied 1 branch name")
+
+	m = sendKey(t, m, "k")
+	assert.NotContains(t, ansi.Strip(m.View()), "copied", "The status message should clear on the next key press")
+
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "y")
+	assert.Equal(t, "alpha\nbeta\ngamma", board.copied[1])
+	assert.Contains(t, ansi.Strip(m.View()), "copied 3 branch names")
+
+	board.err = errors.New("no clipboard tool found")
+	m = sendKey(t, m, "y")
+	assert.Contains(t, ansi.Strip(m.View()), "Error: failed to copy to the clipboard: no clipboard tool found")
+}
+
+// TestCopy_DisabledWithoutClipboard tests that y does nothing and isn't offered without a clipboard.
+func TestCopy_DisabledWithoutClipboard(t *testing.T) {
+	m := newTestModel("alpha")
+	m = sendKey(t, m, "y")
+	assert.Empty(t, m.SuccessMsg)
+	assert.Empty(t, m.ErrorMsg)
+	assert.NotContains(t, m.View(), "y: copy")
+}
@@ -0,0 +1,94 @@
+package unit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// installSlowGit puts a fake git that never finishes in front of PATH.
+func installSlowGit(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+	script := "#!/bin/sh\nexec sleep 30\n"
+	err := os.WriteFile(filepath.Join(dir, "git"), []byte(script), 0o755)
+	require.NoError(t, err)
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// setGitTimeout sets git.Timeout for the duration of the test.
+func setGitTimeout(t *testing.T, timeout time.Duration) {
+	t.Helper()
+
+	original := git.Timeout
+	git.Timeout = timeout
+	t.Cleanup(func() { git.Timeout = original })
+}
+
+// TestTimeout_SlowGitCommand tests that a hung git command is killed once the timeout passes.
+func TestTimeout_SlowGitCommand(t *testing.T) {
+	installSlowGit(t)
+	setGitTimeout(t, 200*time.Millisecond)
+
+	start := time.Now()
+	err := git.DeleteBranch("feature")
+
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second, "Command should be killed after the timeout")
+	assert.ErrorIs(t, err, git.ErrTimeout)
+	assert.True(t, git.IsInterrupted(err))
+	assert.Equal(t, "failed to delete branch 'feature': git branch timed out after 200ms", err.Error())
+}
+
+// TestTimeout_ListingFunctions tests that functions reading git's output also surface the timeout.
+func TestTimeout_ListingFunctions(t *testing.T) {
+	installSlowGit(t)
+	setGitTimeout(t, 200*time.Millisecond)
+
+	_, err := git.ListBranches()
+	assert.ErrorIs(t, err, git.ErrTimeout)
+
+	err = git.ValidateRepository()
+	assert.ErrorIs(t, err, git.ErrTimeout)
+}
+
+// TestContext_CancelKillsGitCommand tests that cancelling the context stops the running git command.
+func TestContext_CancelKillsGitCommand(t *testing.T) {
+	installSlowGit(t)
+	setGitTimeout(t, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := git.ForceDeleteBranchContext(ctx, "feature")
+
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second, "Command should be killed on cancellation")
+	assert.ErrorIs(t, err, git.ErrCancelled)
+	assert.NotErrorIs(t, err, git.ErrTimeout)
+}
+
+// TestTimeout_FastCommandUnaffected tests that commands finishing within the timeout behave as before.
+func TestTimeout_FastCommandUnaffected(t *testing.T) {
+	repo := setupTestRepo(t)
+	setGitTimeout(t, 10*time.Second)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	err = git.DeleteBranch("nonexistent")
+	require.Error(t, err)
+	assert.False(t, git.IsInterrupted(err))
+	assert.Contains(t, err.Error(), "not found")
+}
@@ -0,0 +1,96 @@
+package unit
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompare_SelectsContainedBranch tests marking an anchor with x, comparing it with a second branch,
+// and selecting the branch the other one contains for deletion.
+func TestCompare_SelectsContainedBranch(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	base, _ := git.GetCurrentBranch()
+	exec.Command("git", "checkout", "-q", "-b", "feature-login").Run()
+	exec.Command("git", "commit", "-q", "--allow-empty", "-m", "Add login form").Run()
+	exec.Command("git", "checkout", "-q", "-b", "feature-login-v2").Run()
+	exec.Command("git", "commit", "-q", "--allow-empty", "-m", "Rework login").Run()
+	exec.Command("git", "checkout", "-q", base).Run()
+
+	m := newTestModel("feature-login", "feature-login-v2")
+	m = sendKey(t, m, "x")
+	assert.Equal(t, "feature-login", m.CompareAnchor)
+	assert.Contains(t, ansi.Strip(m.View()), "comparing feature-login (x on another branch)")
+
+	m = sendKey(t, m, "j")
+	m = sendKey(t, m, "x")
+	require.True(t, m.CompareOpen)
+	assert.Empty(t, m.CompareAnchor, "The anchor should be cleared once compared")
+
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "Compare: feature-login ↔ feature-login-v2")
+	assert.Contains(t, view, "feature-login is 0 ahead and 1 behind feature-login-v2")
+	assert.Contains(t, view, "feature-login-v2 contains feature-login: feature-login is redundant")
+	assert.Contains(t, view, "only on feature-login-v2 (1):")
+	assert.Contains(t, view, "Rework login")
+	assert.Contains(t, view, "s: select feature-login for deletion")
+
+	m = sendKey(t, m, "s")
+	assert.False(t, m.CompareOpen)
+	assert.True(t, m.Selected["feature-login"])
+	assert.False(t, m.Selected["feature-login-v2"])
+	assert.Equal(t, 0, m.CursorIndex, "The cursor should move to the selected branch")
+	assert.Contains(t, m.View(), "Selected feature-login for deletion")
+}
+
+// TestCompare_DivergedBranches tests that branches with commits of their own offer no selection shortcut,
+// and that x on the anchor unmarks it.
+func TestCompare_DivergedBranches(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	base, _ := git.GetCurrentBranch()
+	exec.Command("git", "checkout", "-q", "-b", "left").Run()
+	exec.Command("git", "commit", "-q", "--allow-empty", "-m", "Left change").Run()
+	exec.Command("git", "checkout", "-q", "-b", "right", base).Run()
+	exec.Command("git", "commit", "-q", "--allow-empty", "-m", "Right change").Run()
+	exec.Command("git", "checkout", "-q", base).Run()
+
+	m := newTestModel("left", "right")
+	m = sendKey(t, m, "x")
+	m = sendKey(t, m, "x")
+	assert.Empty(t, m.CompareAnchor, "x on the anchor should unmark it")
+
+	m = sendKey(t, m, "x")
+	m = sendKey(t, m, "j")
+	m = sendKey(t, m, "x")
+	require.True(t, m.CompareOpen)
+
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "neither branch contains the other")
+	assert.Contains(t, view, "Left change")
+	assert.Contains(t, view, "Right change")
+	assert.NotContains(t, view, "s: select")
+
+	m = sendKey(t, m, "s")
+	assert.True(t, m.CompareOpen, "s should do nothing without a contained branch")
+	assert.Empty(t, m.Selected)
+
+	m = sendKey(t, m, "esc")
+	assert.False(t, m.CompareOpen)
+	assert.Equal(t, ui.StateSelection, m.State)
+}
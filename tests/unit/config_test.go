@@ -0,0 +1,145 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfig writes a config file with the given content into dir and returns its path.
+func writeConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+// TestLoadFiles_RepoOverridesGlobal tests that later files override the keys they set.
+func TestLoadFiles_RepoOverridesGlobal(t *testing.T) {
+	dir := t.TempDir()
+	global := writeConfig(t, dir, "global.yaml", "protect: [staging]\nsort: age\ncolor: false\ntheme: light\ndate_format: absolute\nbase_branch: develop\nconfirm_threshold: 20\n")
+	repo := writeConfig(t, dir, "repo.yaml", "sort: -age\nauto_select_gone: true\nconfirm_threshold: 0\n")
+
+	cfg, err := config.LoadFiles(global, repo)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"staging"}, cfg.Protect, "Keys unset in the repo file should keep the global value")
+	assert.Equal(t, "-age", cfg.Sort, "The repo file should override the global file")
+	assert.Equal(t, "develop", cfg.BaseBranch)
+	assert.False(t, cfg.ColorEnabled())
+	assert.Equal(t, "light", cfg.Theme)
+	assert.Equal(t, "absolute", cfg.DateFormat)
+	assert.True(t, cfg.SelectGone())
+	require.NotNil(t, cfg.ConfirmThreshold)
+	assert.Equal(t, 0, *cfg.ConfirmThreshold, "A zero threshold should override the global one")
+	assert.Equal(t, []string{global, repo}, cfg.Sources)
+	assert.Empty(t, cfg.Warnings)
+}
+
+// TestLoadFiles_Keys tests that keys are given as a single key or a list, and merged by action.
+func TestLoadFiles_Keys(t *testing.T) {
+	dir := t.TempDir()
+	global := writeConfig(t, dir, "global.yaml", "keys:\n  delete: x\n  up: [up, ctrl+p]\n")
+	repo := writeConfig(t, dir, "repo.yaml", "keys: { delete: D, confirm: enter }\n")
+
+	cfg, err := config.LoadFiles(global, repo)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Warnings)
+	assert.Equal(t, map[string][]string{
+		"delete":  {"D"},
+		"up":      {"up", "ctrl+p"},
+		"confirm": {"enter"},
+	}, cfg.KeyBindings())
+}
+
+// TestLoadFiles_MissingFiles tests that missing files are skipped and built-in defaults apply.
+func TestLoadFiles_MissingFiles(t *testing.T) {
+	cfg, err := config.LoadFiles(filepath.Join(t.TempDir(), "missing.yaml"), "")
+	require.NoError(t, err)
+
+	assert.Empty(t, cfg.Sources)
+	assert.True(t, cfg.ColorEnabled(), "Color should be enabled by default")
+	assert.False(t, cfg.SelectGone(), "Gone branches should not be auto-selected by default")
+}
+
+// TestLoadFiles_UnknownKeysWarn tests that unknown keys produce warnings instead of errors.
+func TestLoadFiles_UnknownKeysWarn(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "config.yaml", "sort: age\nsortt: name\ncolour: false\n")
+
+	cfg, err := config.LoadFiles(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "age", cfg.Sort, "Known keys should still be loaded")
+	assert.True(t, cfg.ColorEnabled(), "Misspelled keys should have no effect")
+	require.Len(t, cfg.Warnings, 2)
+	assert.Contains(t, cfg.Warnings[0], "unknown key 'colour'")
+	assert.Contains(t, cfg.Warnings[1], "unknown key 'sortt'")
+}
+
+// TestLoadFiles_InvalidYAML tests that malformed files fail with the file path in the error.
+func TestLoadFiles_InvalidYAML(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "config.yaml", "protect: [unterminated\n")
+
+	_, err := config.LoadFiles(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), path)
+}
+
+// TestLoad_GlobalAndRepoFiles tests that Load finds the XDG config file and the repository's .gelete.yaml.
+func TestLoad_GlobalAndRepoFiles(t *testing.T) {
+	repo := setupTestRepo(t)
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	global := writeConfig(t, configHome, "gelete/config.yaml", "sort: age\nprotect: [staging]\n")
+	repoFile := writeConfig(t, repo, config.RepoFileName, "sort: -age\n")
+
+	// Run from a subdirectory to check the repo file is found at the top level
+	subdir := filepath.Join(repo, "nested", "dir")
+	require.NoError(t, os.MkdirAll(subdir, 0o755))
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(subdir)
+	require.NoError(t, err)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "-age", cfg.Sort)
+	assert.Equal(t, []string{"staging"}, cfg.Protect)
+	require.Len(t, cfg.Sources, 2)
+	assert.Equal(t, global, cfg.Sources[0])
+	assert.Equal(t, filepath.Base(repoFile), filepath.Base(cfg.Sources[1]))
+}
+
+// TestParseAge tests friendly ages, Go durations and invalid ages.
+func TestParseAge(t *testing.T) {
+	day := 24 * time.Hour
+	valid := map[string]time.Duration{
+		"90d":   90 * day,
+		"2w":    14 * day,
+		"6m":    180 * day,
+		"1y":    365 * day,
+		"36h":   36 * time.Hour,
+		"1h30m": 90 * time.Minute,
+	}
+	for value, want := range valid {
+		age, err := config.ParseAge(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, age, value)
+	}
+
+	for _, value := range []string{"", "d", "90", "-5d", "0d", "1.5d", "3x", "abc", "99999999999999y"} {
+		_, err := config.ParseAge(value)
+		assert.Error(t, err, value)
+	}
+	_, err := config.ParseAge("10q")
+	assert.ErrorContains(t, err, "invalid age '10q'")
+}
@@ -0,0 +1,158 @@
+package unit
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// confirmationModel builds the confirmation screen of the given number of selected branches,
+// with a typed confirmation above threshold branches.
+func confirmationModel(count, threshold int) ui.AppModel {
+	var branches []string
+	for i := range count {
+		branches = append(branches, fmt.Sprintf("topic-%02d", i))
+	}
+	m := newTestModel(branches...)
+	m.Styles = ui.NewStyles(ui.ThemeAuto, false)
+	m.ConfirmThreshold = threshold
+	m.Analysis = make(map[string]git.BranchAnalysis)
+	for _, branch := range branches {
+		m.Selected[branch] = true
+		m.Analysis[branch] = git.BranchAnalysis{Name: branch, Status: git.StatusMerged}
+	}
+	m.State = ui.StateConfirmation
+	return m
+}
+
+// TestRender_ConfirmationGolden tests the confirmation screens against golden files: a key press confirms
+// up to the threshold, and above it the screen explains the typed confirmation, then shows its input.
+// Run with -update to accept changes.
+func TestRender_ConfirmationGolden(t *testing.T) {
+	assertGolden(t, filepath.Join("testdata", "confirmation", "simple.golden"), confirmationModel(2, 3).View())
+	assertGolden(t, filepath.Join("testdata", "confirmation", "typed-required.golden"), confirmationModel(4, 3).View())
+
+	m := sendKey(t, confirmationModel(4, 3), "y")
+	require.True(t, m.TypingConfirmation)
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "e")
+	assertGolden(t, filepath.Join("testdata", "confirmation", "typing.golden"), m.View())
+
+	m = sendKey(t, m, "enter")
+	assert.True(t, m.TypingConfirmation, "A wrong confirmation should keep the input open")
+	assertGolden(t, filepath.Join("testdata", "confirmation", "typing-error.golden"), m.View())
+}
+
+// TestTypedConfirmation_ThresholdAndForce tests which confirmations take typing: more branches than the
+// threshold, and force deletions whatever their number, but never dry runs.
+func TestTypedConfirmation_ThresholdAndForce(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(m *ui.AppModel)
+		typed bool
+	}{
+		{name: "at the threshold", setup: func(m *ui.AppModel) { m.ConfirmThreshold = 3 }},
+		{name: "above the threshold", setup: func(m *ui.AppModel) { m.ConfirmThreshold = 2 }, typed: true},
+		{name: "threshold disabled", setup: func(m *ui.AppModel) { m.ConfirmThreshold = 0 }},
+		{name: "force mode", setup: func(m *ui.AppModel) { m.ForceMode = true }, typed: true},
+		{name: "force mode dry run", setup: func(m *ui.AppModel) { m.ForceMode, m.DryRun = true, true }},
+		{name: "remote branches", setup: func(m *ui.AppModel) { m.Remote, m.ForceMode = true, true }},
+		{name: "dry run above the threshold", setup: func(m *ui.AppModel) { m.ConfirmThreshold, m.DryRun = 2, true }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := confirmationModel(3, 10)
+			tt.setup(&m)
+			assert.Equal(t, tt.typed, strings.Contains(ansi.Strip(m.View()), "takes typing"), "Requirement shown")
+
+			if tt.typed {
+				next := sendKey(t, m, "y")
+				assert.True(t, next.TypingConfirmation)
+				assert.Equal(t, ui.StateConfirmation, next.State, "y alone should not start the deletion")
+			}
+		})
+	}
+}
+
+// TestTypedConfirmation_DeletesAfterTyping tests that typing "delete" or the number of branches deletes them,
+// and that esc or backspace on an empty input go back to the selection.
+func TestTypedConfirmation_DeletesAfterTyping(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	for _, branch := range []string{"a", "b", "c"} {
+		require.NoError(t, exec.Command("git", "branch", branch).Run())
+	}
+
+	m := newTestModel("a", "b", "c")
+	m.ConfirmThreshold = 2
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	require.True(t, m.TypingConfirmation)
+
+	m = sendKey(t, m, "esc")
+	assert.Equal(t, ui.StateSelection, m.State, "esc should cancel back to the selection")
+	assert.False(t, m.TypingConfirmation)
+
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	m = sendKey(t, m, "x")
+	m = sendKey(t, m, "backspace")
+	require.True(t, m.TypingConfirmation, "backspace should first erase what was typed")
+	m = sendKey(t, m, "backspace")
+	assert.Equal(t, ui.StateSelection, m.State, "backspace on an empty input should cancel back to the selection")
+
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	m = typeConfirmation(t, m, "4")
+	require.Equal(t, ui.StateConfirmation, m.State, "A wrong count should not confirm")
+	assert.Contains(t, ansi.Strip(m.View()), "Error: type delete or 3 to confirm")
+
+	m = sendKey(t, m, "esc")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	m = typeConfirmation(t, m, "3")
+	require.Equal(t, ui.StateDone, m.State)
+	for _, branch := range []string{"a", "b", "c"} {
+		assertResult(t, m, branch, ui.ResultDeleted)
+		assert.False(t, git.BranchExists(branch))
+	}
+}
+
+// TestTypedConfirmation_ForcePromptStaysOpenOnCancel tests that cancelling the typed force confirmation
+// returns to the force prompt, where the unmerged branches can still be skipped.
+func TestTypedConfirmation_ForcePromptStaysOpenOnCancel(t *testing.T) {
+	setupUnmergedBranches(t, "spike")
+
+	m := newTestModel("spike")
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateForceConfirmation, m.State)
+	assert.Contains(t, ansi.Strip(m.View()), `Force deleting takes typing "delete" or the number of branches (1) to confirm.`)
+
+	m = sendKey(t, m, "y")
+	require.True(t, m.TypingConfirmation)
+	m = sendKey(t, m, "esc")
+	require.Equal(t, ui.StateForceConfirmation, m.State)
+	assert.False(t, m.TypingConfirmation)
+
+	m = sendKey(t, m, "n")
+	require.Equal(t, ui.StateDone, m.State)
+	assertResult(t, m, "spike", ui.ResultSkipped)
+	assert.True(t, git.BranchExists("spike"))
+}
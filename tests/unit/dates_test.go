@@ -0,0 +1,90 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHumanizeDuration tests the relative dates around the boundaries of their units
+func TestHumanizeDuration(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{0, "just now"},
+		{-time.Hour, "just now"},
+		{time.Second, "1 second ago"},
+		{59 * time.Second, "59 seconds ago"},
+		{61 * time.Second, "1 minute ago"},
+		{59 * time.Minute, "59 minutes ago"},
+		{23 * time.Hour, "23 hours ago"},
+		{25 * time.Hour, "1 day ago"},
+		{6 * 24 * time.Hour, "6 days ago"},
+		{7 * 24 * time.Hour, "1 week ago"},
+		{29 * 24 * time.Hour, "4 weeks ago"},
+		{30 * 24 * time.Hour, "1 month ago"},
+		{364 * 24 * time.Hour, "12 months ago"},
+		{396 * 24 * time.Hour, "1 year ago"},
+		{3 * 365 * 24 * time.Hour, "3 years ago"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ui.HumanizeDuration(i18n.English, now.Add(-tt.ago), now), tt.ago)
+	}
+
+	assert.Equal(t, "3 か月前", ui.HumanizeDuration(i18n.Japanese, now.AddDate(0, -3, 0), now))
+	assert.Equal(t, "たった今", ui.HumanizeDuration(i18n.Japanese, now, now))
+}
+
+// TestDateFormat_Toggle tests that the t key switches the dates of the list between relative and absolute
+// without reloading the branches, and that a missing date shows as unknown rather than the zero time
+func TestDateFormat_Toggle(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local)
+	m := newTestModel("dated", "undated")
+	m.Clock = func() time.Time { return now }
+	m.BranchDetails = map[string]git.BranchInfo{
+		"dated":   {Name: "dated", LastCommitDate: now.AddDate(0, -3, -1), LastCommitter: "Alice"},
+		"undated": {Name: "undated", LastCommitter: "Bob"},
+	}
+
+	row := func(m ui.AppModel, branch string) string {
+		for _, line := range strings.Split(ansi.Strip(m.View()), "\n") {
+			if strings.Contains(line, "] "+branch) {
+				return line
+			}
+		}
+		return ""
+	}
+	assert.Contains(t, row(m, "dated"), "3 months ago • Alice")
+	assert.Contains(t, row(m, "undated"), "unknown • Bob")
+	assert.NotContains(t, row(m, "undated"), "years ago")
+
+	m = sendKey(t, m, "t")
+	require.Equal(t, ui.DatesAbsolute, m.DateFormat)
+	assert.Contains(t, row(m, "dated"), "2024-01-31 • Alice")
+	assert.Contains(t, row(m, "undated"), "unknown • Bob")
+
+	m = sendKey(t, m, "t")
+	assert.Equal(t, ui.DatesRelative, m.DateFormat)
+}
+
+// TestParseDateFormat tests the values of the date_format config key
+func TestParseDateFormat(t *testing.T) {
+	for _, format := range []ui.DateFormat{ui.DatesRelative, ui.DatesAbsolute} {
+		parsed, err := ui.ParseDateFormat(format.String())
+		require.NoError(t, err)
+		assert.Equal(t, format, parsed)
+	}
+
+	_, err := ui.ParseDateFormat("iso")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid date format 'iso' (expected relative or absolute)")
+}
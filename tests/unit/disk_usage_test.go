@@ -0,0 +1,131 @@
+package unit
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/fsutil"
+	"github.com/Kdaito/gelete/internal/ui"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSizedFile creates a file of size bytes, creating its parent directories.
+func writeSizedFile(t *testing.T, path string, size int) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0o644))
+}
+
+// TestDirSize tests that DirSize adds up nested files without following symlinks.
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	writeSizedFile(t, filepath.Join(dir, "a.bin"), 1000)
+	writeSizedFile(t, filepath.Join(dir, "nested", "b.bin"), 2000)
+	writeSizedFile(t, filepath.Join(dir, "nested", "deeper", "c.bin"), 3000)
+
+	outside := filepath.Join(t.TempDir(), "outside.bin")
+	writeSizedFile(t, outside, 50000)
+	require.NoError(t, os.Symlink(outside, filepath.Join(dir, "link")))
+
+	size, err := fsutil.DirSize(dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6000), size)
+
+	_, err = fsutil.DirSize(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+// TestDirSize_SkipsUnreadableDirectories tests that unreadable subdirectories are skipped, not fatal.
+func TestDirSize_SkipsUnreadableDirectories(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can read directories without permissions")
+	}
+
+	dir := t.TempDir()
+	writeSizedFile(t, filepath.Join(dir, "a.bin"), 1000)
+	writeSizedFile(t, filepath.Join(dir, "locked", "b.bin"), 2000)
+	require.NoError(t, os.Chmod(filepath.Join(dir, "locked"), 0))
+	t.Cleanup(func() { os.Chmod(filepath.Join(dir, "locked"), 0o755) })
+
+	size, err := fsutil.DirSize(dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), size)
+}
+
+// TestDirSizeContext_StopsWhenCancelled tests that a cancelled walk returns the context's error.
+func TestDirSizeContext_StopsWhenCancelled(t *testing.T) {
+	dir := t.TempDir()
+	writeSizedFile(t, filepath.Join(dir, "a.bin"), 1000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := fsutil.DirSizeContext(ctx, dir)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// TestFormatSize tests the human-readable sizes.
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+		{4509715661, "4.2 GB"},
+		{3 << 40, "3.0 TB"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, fsutil.FormatSize(tt.bytes), "FormatSize(%d)", tt.bytes)
+	}
+}
+
+// TestWorktreeConfirmation_ShowsDiskSpace tests that the worktree confirmation shows "calculating…" until
+// the worktrees are measured, then their sizes and the total, and that the done screen shows the space freed.
+func TestWorktreeConfirmation_ShowsDiskSpace(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "wt").Run()
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	require.NoError(t, exec.Command("git", "worktree", "add", worktreePath, "wt").Run())
+	// Ignored build output is what makes worktrees big, and doesn't prevent removing them
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".git", "info", "exclude"), []byte("build/\n"), 0o644))
+	writeSizedFile(t, filepath.Join(worktreePath, "build", "big.bin"), 3*1024*1024)
+
+	m := newTestModel("wt")
+	m.BranchWorktrees["wt"] = worktreePath
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = next.(ui.AppModel)
+	require.Equal(t, ui.StateWorktreeConfirmation, m.State)
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, worktreePath+" (calculating…)")
+	assert.Contains(t, view, "Disk space freed: calculating…")
+
+	m = runCmds(m, cmd)
+	view = ansi.Strip(m.View())
+	assert.Contains(t, view, worktreePath+" (~3.0 MB)")
+	assert.Contains(t, view, "Disk space freed: ~3.0 MB in 1 worktree(s)")
+
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateDone, m.State)
+	assert.Contains(t, ansi.Strip(m.View()), "freed ~3.0 MB")
+}
@@ -0,0 +1,147 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDeleteBranch_ClassifiesErrors tests that unmerged, missing and checked-out branches fail with typed errors
+// that keep git's message.
+func TestDeleteBranch_ClassifiesErrors(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "checkout", "-q", "-b", "unmerged").Run()
+	exec.Command("git", "commit", "-q", "--allow-empty", "-m", "Unmerged work").Run()
+	exec.Command("git", "checkout", "-q", "-").Run()
+
+	err = git.DeleteBranch("unmerged")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, git.ErrNotMerged)
+	assert.NotErrorIs(t, err, git.ErrNotFound)
+	assert.True(t, git.IsUnmergedError(err))
+	assert.Contains(t, err.Error(), "failed to delete branch 'unmerged': error:", "git's message is kept")
+
+	for _, deleteBranch := range []func(string) error{git.DeleteBranch, git.ForceDeleteBranch} {
+		err = deleteBranch("missing")
+		assert.ErrorIs(t, err, git.ErrNotFound)
+		assert.False(t, git.IsUnmergedError(err))
+	}
+
+	worktreePath := filepath.Join(t.TempDir(), "linked")
+	require.NoError(t, exec.Command("git", "worktree", "add", "-q", "-b", "linked", worktreePath).Run())
+	for _, deleteBranch := range []func(string) error{git.DeleteBranch, git.ForceDeleteBranch} {
+		err = deleteBranch("linked")
+		var checkedOut *git.ErrCheckedOutElsewhere
+		require.ErrorAs(t, err, &checkedOut)
+		assert.Equal(t, "linked", checkedOut.Branch)
+		assert.Equal(t, worktreePath, checkedOut.Path)
+		assert.False(t, git.IsUnmergedError(err))
+	}
+}
+
+// TestDeleteBranch_MainWorktreeBranchFromLinkedWorktree tests that the branch of the main worktree can't be deleted
+// from a linked worktree, and that the error names the main worktree.
+func TestDeleteBranch_MainWorktreeBranchFromLinkedWorktree(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "-M", "trunk").Run()
+	worktreePath := filepath.Join(t.TempDir(), "linked")
+	require.NoError(t, exec.Command("git", "-C", repo, "worktree", "add", "-q", "-b", "linked", worktreePath).Run())
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(worktreePath)
+	require.NoError(t, err)
+
+	err = git.DeleteBranch("trunk")
+	var checkedOut *git.ErrCheckedOutElsewhere
+	require.ErrorAs(t, err, &checkedOut)
+	assert.Equal(t, repo, checkedOut.Path)
+	assert.Equal(t, fmt.Sprintf("failed to delete branch 'trunk': branch 'trunk' is checked out in %s", repo), err.Error())
+
+	// The done screen says what to do instead of showing git's message
+	m := newTestModel("trunk")
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateDone, m.State)
+	assert.Contains(t, ansi.Strip(m.View()),
+		fmt.Sprintf("✗ trunk is checked out in %s — remove that worktree or switch its branch first", repo))
+	result, ok := m.Result("trunk")
+	require.True(t, ok)
+	assert.Equal(t, ui.ResultFailed, result.Status)
+	assert.Equal(t, repo, result.CheckedOutIn)
+}
+
+// installGitPrinting puts a fake git in front of PATH that fails `git branch -d` and `git branch -D`
+// with message. Other commands run the real git.
+func installGitPrinting(t *testing.T, message string) {
+	t.Helper()
+
+	realGit, err := exec.LookPath("git")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	messageFile := filepath.Join(dir, "message")
+	require.NoError(t, os.WriteFile(messageFile, []byte(message+"\n"), 0o644))
+
+	script := fmt.Sprintf(`#!/bin/sh
+case "$1 $2" in
+"branch -d"|"branch -D") cat %s >&2; exit 1 ;;
+esac
+exec %s "$@"
+`, messageFile, realGit)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "git"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// TestDeleteBranch_ClassifiesMessagesOfGitVersions tests the classification of the messages of older and newer git.
+func TestDeleteBranch_ClassifiesMessagesOfGitVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    error
+		path    string
+	}{
+		{"checked out, git 2.39", "error: Cannot delete branch 'topic' checked out at '/home/me/repos/app-main'", nil, "/home/me/repos/app-main"},
+		{"used by worktree, git 2.42", "error: cannot delete branch 'topic' used by worktree at '/home/me/my repos/app'", nil, "/home/me/my repos/app"},
+		{"not merged, git 2.39", "error: The branch 'topic' is not fully merged.", git.ErrNotMerged, ""},
+		{"not merged, git 2.45", "error: the branch 'topic' is not fully merged", git.ErrNotMerged, ""},
+		{"not found", "error: branch 'topic' not found.", git.ErrNotFound, ""},
+		{"unclassified", "fatal: cannot lock ref 'refs/heads/topic'", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installGitPrinting(t, tt.message)
+
+			err := git.ForceDeleteBranch("topic")
+			require.Error(t, err)
+
+			var checkedOut *git.ErrCheckedOutElsewhere
+			isCheckedOut := errors.As(err, &checkedOut)
+			assert.Equal(t, tt.path != "", isCheckedOut)
+			if isCheckedOut {
+				assert.Equal(t, tt.path, checkedOut.Path)
+			}
+			for _, kind := range []error{git.ErrNotMerged, git.ErrNotFound} {
+				assert.Equal(t, kind == tt.want, errors.Is(err, kind), "errors.Is(err, %v)", kind)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// forcePromptModel returns a model at the force confirmation for the given unmerged branches
+func forcePromptModel(t *testing.T, branches ...string) ui.AppModel {
+	t.Helper()
+	setupUnmergedBranches(t, branches...)
+
+	m := newTestModel(branches...)
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateForceConfirmation, m.State)
+	return m
+}
+
+// TestForceConfirmation_OffersThreeChoices tests that the force prompt offers forcing all, deciding one by one
+// and skipping all, with the former y and n keys still working.
+func TestForceConfirmation_OffersThreeChoices(t *testing.T) {
+	m := forcePromptModel(t, "spike")
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "f/y: force delete all")
+	assert.Contains(t, view, "i: decide one by one")
+	assert.Contains(t, view, "s/n: skip all")
+}
+
+// TestForceConfirmation_ForceAll tests that f force deletes every unmerged branch once the confirmation is typed.
+func TestForceConfirmation_ForceAll(t *testing.T) {
+	m := forcePromptModel(t, "spike-a", "spike-b")
+
+	m = sendKey(t, m, "f")
+	require.True(t, m.TypingConfirmation, "Force deleting all still takes typing")
+	m = typeText(t, m, "delete")
+	m = sendKey(t, m, "enter")
+
+	require.Equal(t, ui.StateDone, m.State)
+	for _, branch := range []string{"spike-a", "spike-b"} {
+		assertResult(t, m, branch, ui.ResultForceDeleted)
+		assert.False(t, git.BranchExists(branch))
+	}
+}
+
+// TestForceConfirmation_SkipAll tests that s skips every unmerged branch.
+func TestForceConfirmation_SkipAll(t *testing.T) {
+	m := forcePromptModel(t, "spike-a", "spike-b")
+
+	m = sendKey(t, m, "s")
+
+	require.Equal(t, ui.StateDone, m.State)
+	for _, branch := range []string{"spike-a", "spike-b"} {
+		assertResult(t, m, branch, ui.ResultSkipped)
+		assert.True(t, git.BranchExists(branch))
+	}
+}
+
+// TestForceReview_DecidesEachBranch tests deciding on the unmerged branches one by one: each shows its own
+// commits, decisions can be revisited, and nothing is deleted until the last branch is decided.
+func TestForceReview_DecidesEachBranch(t *testing.T) {
+	m := forcePromptModel(t, "spike-a", "spike-b", "spike-c")
+
+	m = sendKey(t, m, "i")
+	require.Equal(t, ui.StateForceReview, m.State)
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "(1/3)")
+	assert.Contains(t, view, "Only on spike-a")
+	assert.NotContains(t, view, "Only on spike-b", "Only the commits of the branch under the cursor are shown")
+
+	m = sendKey(t, m, "f")
+	assert.Equal(t, 1, m.ReviewCursor)
+	assert.Contains(t, ansi.Strip(m.View()), "Only on spike-b")
+	m = sendKey(t, m, "f")
+
+	// Revisit spike-b and skip it instead
+	m = sendKey(t, m, "k")
+	assert.Equal(t, 1, m.ReviewCursor)
+	m = sendKey(t, m, "s")
+	assert.Equal(t, 2, m.ReviewCursor, "Deciding moves on to the first branch without a decision")
+	assert.True(t, git.BranchExists("spike-a"), "Nothing is deleted before every branch is decided")
+	assert.Contains(t, ansi.Strip(m.View()), "spike-b — skip")
+
+	m = sendKey(t, m, "s")
+	require.Equal(t, ui.StateDone, m.State)
+	assertResult(t, m, "spike-a", ui.ResultForceDeleted)
+	assertResult(t, m, "spike-b", ui.ResultSkipped)
+	assertResult(t, m, "spike-c", ui.ResultSkipped)
+	assert.False(t, git.BranchExists("spike-a"))
+	assert.True(t, git.BranchExists("spike-b"))
+	assert.True(t, git.BranchExists("spike-c"))
+}
+
+// TestForceReview_EscReturnsToChoices tests that esc leaves the review for the force prompt, forgetting
+// the decisions made so far.
+func TestForceReview_EscReturnsToChoices(t *testing.T) {
+	m := forcePromptModel(t, "spike-a", "spike-b")
+
+	m = sendKey(t, m, "i")
+	m = sendKey(t, m, "f")
+	m = sendKey(t, m, "esc")
+	require.Equal(t, ui.StateForceConfirmation, m.State)
+	assert.True(t, git.BranchExists("spike-a"))
+
+	m = sendKey(t, m, "i")
+	assert.Equal(t, 0, m.ReviewCursor)
+	assert.Empty(t, m.ForceDecisions)
+}
+
+// TestForceReview_DryRun tests that a dry run plans only the branches decided to be force deleted.
+func TestForceReview_DryRun(t *testing.T) {
+	setupUnmergedBranches(t, "spike-a", "spike-b")
+
+	m := newTestModel("spike-a", "spike-b")
+	m.DryRun = true
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	m = sendKey(t, m, "i")
+	m = sendKey(t, m, "s")
+	m = sendKey(t, m, "f")
+	require.Equal(t, ui.StateDone, m.State)
+
+	require.Len(t, m.DryRunActions, 2)
+	assert.Equal(t, ui.DryRunAction{Branch: "spike-a"}, m.DryRunActions[0])
+	assert.Equal(t, ui.DryRunAction{Branch: "spike-b", Force: true}, m.DryRunActions[1])
+	assert.True(t, git.BranchExists("spike-b"), "Dry runs must not delete branches")
+}
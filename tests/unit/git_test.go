@@ -1,9 +1,13 @@
 package unit
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Kdaito/gelete/internal/git"
 	"github.com/stretchr/testify/assert"
@@ -135,8 +139,8 @@ func TestGetCurrentBranch_DetachedHEAD(t *testing.T) {
 	assert.Equal(t, "HEAD", branch, "Should return 'HEAD' in detached state")
 }
 
-// TestListBranches_MultipleBranches tests listing branches with multiple branches present.
-func TestListBranches_MultipleBranches(t *testing.T) {
+// TestGetHeadBranch_DetachedHEAD tests that detached HEAD is reported explicitly.
+func TestGetHeadBranch_DetachedHEAD(t *testing.T) {
 	repo := setupTestRepo(t)
 
 	originalDir, _ := os.Getwd()
@@ -144,13 +148,52 @@ func TestListBranches_MultipleBranches(t *testing.T) {
 	err := os.Chdir(repo)
 	require.NoError(t, err)
 
+	branch, detached, err := git.GetHeadBranch()
+	assert.NoError(t, err)
+	assert.False(t, detached, "HEAD should be attached after init")
+	assert.NotEmpty(t, branch)
+
+	exec.Command("git", "checkout", "--detach").Run()
+
+	branch, detached, err = git.GetHeadBranch()
+	assert.NoError(t, err)
+	assert.True(t, detached, "HEAD should be detached")
+	assert.Empty(t, branch, "No branch is checked out in detached HEAD state")
+}
+
+// TestListBranches_DetachedHEAD tests that every branch is deletable when HEAD is detached.
+func TestListBranches_DetachedHEAD(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	r := git.Repository{Dir: repo}
+
+	exec.Command("git", "-C", repo, "checkout", "-q", "-b", "work").Run()
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+	exec.Command("git", "-C", repo, "branch", "feature-b").Run()
+	exec.Command("git", "-C", repo, "checkout", "-q", "--detach").Run()
+
+	branches, err := r.ListBranches()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feature-a", "feature-b", "work"}, branches, "The previously checked-out branch should be listed")
+
+	infos, err := r.ListBranchesWithInfo()
+	require.NoError(t, err)
+	assert.Len(t, infos, 3)
+}
+
+// TestListBranches_MultipleBranches tests listing branches with multiple branches present.
+func TestListBranches_MultipleBranches(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	r := git.Repository{Dir: repo}
+
 	// Create test branches
-	exec.Command("git", "branch", "feature-a").Run()
-	exec.Command("git", "branch", "feature-b").Run()
-	exec.Command("git", "branch", "test-1").Run()
+	exec.Command("git", "-C", repo, "branch", "feature-a").Run()
+	exec.Command("git", "-C", repo, "branch", "feature-b").Run()
+	exec.Command("git", "-C", repo, "branch", "test-1").Run()
 
 	// List branches
-	branches, err := git.ListBranches()
+	branches, err := r.ListBranches()
 	assert.NoError(t, err, "ListBranches should succeed")
 	assert.Len(t, branches, 3, "Should return 3 branches (excluding current)")
 	assert.Contains(t, branches, "feature-a")
@@ -162,20 +205,17 @@ func TestListBranches_MultipleBranches(t *testing.T) {
 func TestListBranches_ExcludesCurrentBranch(t *testing.T) {
 	repo := setupTestRepo(t)
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
+	r := git.Repository{Dir: repo}
 
 	// Get current branch
-	currentBranch, err := git.GetCurrentBranch()
+	currentBranch, err := r.GetCurrentBranch()
 	require.NoError(t, err)
 
 	// Create other branches
-	exec.Command("git", "branch", "other").Run()
+	exec.Command("git", "-C", repo, "branch", "other").Run()
 
 	// List branches
-	branches, err := git.ListBranches()
+	branches, err := r.ListBranches()
 	assert.NoError(t, err)
 	assert.NotContains(t, branches, currentBranch, "Current branch should be excluded")
 	assert.Contains(t, branches, "other")
@@ -185,33 +225,73 @@ func TestListBranches_ExcludesCurrentBranch(t *testing.T) {
 func TestListBranches_OnlyCurrentBranch(t *testing.T) {
 	repo := setupTestRepo(t)
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
+	r := git.Repository{Dir: repo}
 
 	// No other branches exist
-	branches, err := git.ListBranches()
+	branches, err := r.ListBranches()
 	assert.NoError(t, err, "ListBranches should succeed")
 	assert.Len(t, branches, 0, "Should return empty list when only current branch exists")
 }
 
+// setupEmptyRepo creates a temporary git repository without commits, as left by git init.
+func setupEmptyRepo(t *testing.T) git.Repository {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", dir).Run(), "Failed to initialize git repository")
+	r, err := git.NewRepository(dir)
+	require.NoError(t, err, "A repository without commits should still be a repository")
+	return r
+}
+
+// TestHasCommits tests that HEAD of a freshly initialized repository has no commit until the first one is made.
+func TestHasCommits(t *testing.T) {
+	r := setupEmptyRepo(t)
+
+	hasCommits, err := r.HasCommits()
+	require.NoError(t, err, "An unborn HEAD should not be an error")
+	assert.False(t, hasCommits)
+
+	exec.Command("git", "-C", r.Dir, "-c", "user.name=Test User", "-c", "user.email=test@example.com",
+		"commit", "--allow-empty", "-m", "Initial commit").Run()
+	hasCommits, err = r.HasCommits()
+	require.NoError(t, err)
+	assert.True(t, hasCommits)
+}
+
+// TestNoCommits_ListsNothing tests that listing branches and worktrees of a repository without commits
+// returns nothing rather than an error.
+func TestNoCommits_ListsNothing(t *testing.T) {
+	r := setupEmptyRepo(t)
+
+	branches, err := r.ListBranches()
+	require.NoError(t, err, "ListBranches should succeed without commits")
+	assert.Empty(t, branches)
+
+	infos, err := r.ListBranchesWithInfo()
+	require.NoError(t, err, "ListBranchesWithInfo should succeed without commits")
+	assert.Empty(t, infos)
+
+	worktrees, err := r.ListWorktrees()
+	require.NoError(t, err, "ListWorktrees should succeed without commits")
+	require.Len(t, worktrees, 1, "The main worktree should be listed")
+	assert.True(t, worktrees[0].Main)
+	assert.True(t, git.SamePath(r.Dir, worktrees[0].Path))
+}
+
 // TestListBranches_Sorted tests that branches are returned in alphabetical order.
 func TestListBranches_Sorted(t *testing.T) {
 	repo := setupTestRepo(t)
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
+	r := git.Repository{Dir: repo}
 
 	// Create branches in non-alphabetical order
-	exec.Command("git", "branch", "zebra").Run()
-	exec.Command("git", "branch", "alpha").Run()
-	exec.Command("git", "branch", "beta").Run()
+	exec.Command("git", "-C", repo, "branch", "zebra").Run()
+	exec.Command("git", "-C", repo, "branch", "alpha").Run()
+	exec.Command("git", "-C", repo, "branch", "beta").Run()
 
 	// List branches
-	branches, err := git.ListBranches()
+	branches, err := r.ListBranches()
 	assert.NoError(t, err)
 
 	// Should be sorted alphabetically
@@ -222,20 +302,17 @@ func TestListBranches_Sorted(t *testing.T) {
 func TestDeleteBranch_Success(t *testing.T) {
 	repo := setupTestRepo(t)
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
+	r := git.Repository{Dir: repo}
 
 	// Create a test branch
-	exec.Command("git", "branch", "test-delete").Run()
+	exec.Command("git", "-C", repo, "branch", "test-delete").Run()
 
 	// Delete the branch
-	err = git.DeleteBranch("test-delete")
+	err := r.DeleteBranch("test-delete")
 	assert.NoError(t, err, "DeleteBranch should succeed for merged branch")
 
 	// Verify branch is deleted (it should not appear in branch list)
-	branches, _ := git.ListBranches()
+	branches, _ := r.ListBranches()
 	assert.NotContains(t, branches, "test-delete", "Branch should be deleted")
 }
 
@@ -243,13 +320,10 @@ func TestDeleteBranch_Success(t *testing.T) {
 func TestDeleteBranch_NonExistent(t *testing.T) {
 	repo := setupTestRepo(t)
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
+	r := git.Repository{Dir: repo}
 
 	// Try to delete non-existent branch
-	err = git.DeleteBranch("does-not-exist")
+	err := r.DeleteBranch("does-not-exist")
 	assert.Error(t, err, "DeleteBranch should fail for non-existent branch")
 }
 
@@ -257,20 +331,17 @@ func TestDeleteBranch_NonExistent(t *testing.T) {
 func TestForceDeleteBranch_Success(t *testing.T) {
 	repo := setupTestRepo(t)
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
+	r := git.Repository{Dir: repo}
 
 	// Create a test branch
-	exec.Command("git", "branch", "test-force").Run()
+	exec.Command("git", "-C", repo, "branch", "test-force").Run()
 
 	// Force delete the branch
-	err = git.ForceDeleteBranch("test-force")
+	err := r.ForceDeleteBranch("test-force")
 	assert.NoError(t, err, "ForceDeleteBranch should succeed")
 
 	// Verify branch is deleted
-	branches, _ := git.ListBranches()
+	branches, _ := r.ListBranches()
 	assert.NotContains(t, branches, "test-force", "Branch should be deleted")
 }
 
@@ -278,30 +349,27 @@ func TestForceDeleteBranch_Success(t *testing.T) {
 func TestForceDeleteBranch_UnmergedBranch(t *testing.T) {
 	repo := setupTestRepo(t)
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
+	r := git.Repository{Dir: repo}
 
 	// Get current branch
-	currentBranch, err := git.GetCurrentBranch()
+	currentBranch, err := r.GetCurrentBranch()
 	require.NoError(t, err)
 
 	// Create a branch with unmerged changes
-	exec.Command("git", "checkout", "-b", "unmerged").Run()
-	exec.Command("git", "commit", "--allow-empty", "-m", "Unmerged commit").Run()
-	exec.Command("git", "checkout", currentBranch).Run()
+	exec.Command("git", "-C", repo, "checkout", "-b", "unmerged").Run()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "-C", repo, "checkout", currentBranch).Run()
 
 	// Safe delete should fail
-	err = git.DeleteBranch("unmerged")
+	err = r.DeleteBranch("unmerged")
 	assert.Error(t, err, "DeleteBranch should fail for unmerged branch")
 
 	// Force delete should succeed
-	err = git.ForceDeleteBranch("unmerged")
+	err = r.ForceDeleteBranch("unmerged")
 	assert.NoError(t, err, "ForceDeleteBranch should succeed for unmerged branch")
 
 	// Verify branch is deleted
-	branches, _ := git.ListBranches()
+	branches, _ := r.ListBranches()
 	assert.NotContains(t, branches, "unmerged", "Branch should be deleted")
 }
 
@@ -309,12 +377,766 @@ func TestForceDeleteBranch_UnmergedBranch(t *testing.T) {
 func TestForceDeleteBranch_NonExistent(t *testing.T) {
 	repo := setupTestRepo(t)
 
+	r := git.Repository{Dir: repo}
+
+	// Try to force delete non-existent branch
+	err := r.ForceDeleteBranch("does-not-exist")
+	assert.Error(t, err, "ForceDeleteBranch should fail for non-existent branch")
+}
+
+// TestIsBranchMerged_Merged tests that a branch pointing at HEAD is reported as merged.
+func TestIsBranchMerged_Merged(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	r := git.Repository{Dir: repo}
+
+	exec.Command("git", "-C", repo, "branch", "merged").Run()
+
+	merged, err := r.IsBranchMerged("merged")
+	assert.NoError(t, err, "IsBranchMerged should succeed")
+	assert.True(t, merged, "Branch at HEAD should be merged")
+}
+
+// TestIsBranchMerged_Unmerged tests that a branch with extra commits is reported as unmerged.
+func TestIsBranchMerged_Unmerged(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	r := git.Repository{Dir: repo}
+
+	exec.Command("git", "-C", repo, "checkout", "-b", "unmerged").Run()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "-C", repo, "checkout", "-").Run()
+
+	merged, err := r.IsBranchMerged("unmerged")
+	assert.NoError(t, err, "IsBranchMerged should succeed")
+	assert.False(t, merged, "Branch with extra commits should be unmerged")
+}
+
+// TestIsBranchMerged_NonExistent tests that checking a non-existent branch fails.
+func TestIsBranchMerged_NonExistent(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	r := git.Repository{Dir: repo}
+
+	_, err := r.IsBranchMerged("does-not-exist")
+	assert.Error(t, err, "IsBranchMerged should fail for non-existent branch")
+}
+
+// TestIsTipReachableElsewhere tests that a branch tip is reachable once another branch or tag contains it.
+func TestIsTipReachableElsewhere(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	r := git.Repository{Dir: repo}
+
+	exec.Command("git", "-C", repo, "checkout", "-b", "only-copy").Run()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Only on this branch").Run()
+	exec.Command("git", "-C", repo, "checkout", "-").Run()
+
+	reachable, err := r.IsTipReachableElsewhere("only-copy")
+	require.NoError(t, err)
+	assert.False(t, reachable, "Commits only on the branch should not be reachable elsewhere")
+
+	exec.Command("git", "-C", repo, "tag", "keep", "only-copy").Run()
+	reachable, err = r.IsTipReachableElsewhere("only-copy")
+	require.NoError(t, err)
+	assert.True(t, reachable, "A tag on the branch tip should make it reachable")
+
+	exec.Command("git", "-C", repo, "tag", "-d", "keep").Run()
+	exec.Command("git", "-C", repo, "branch", "successor", "only-copy").Run()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Unrelated").Run()
+	reachable, err = r.IsTipReachableElsewhere("only-copy")
+	require.NoError(t, err)
+	assert.True(t, reachable, "A branch containing the tip should make it reachable")
+
+	_, err = r.IsTipReachableElsewhere("does-not-exist")
+	assert.Error(t, err)
+}
+
+// TestListBranchesWithInfo_Metadata tests that branch metadata is gathered for each branch.
+func TestListBranchesWithInfo_Metadata(t *testing.T) {
+	repo := setupTestRepo(t)
+
 	originalDir, _ := os.Getwd()
 	defer os.Chdir(originalDir)
 	err := os.Chdir(repo)
 	require.NoError(t, err)
 
-	// Try to force delete non-existent branch
-	err = git.ForceDeleteBranch("does-not-exist")
-	assert.Error(t, err, "ForceDeleteBranch should fail for non-existent branch")
+	exec.Command("git", "checkout", "-b", "feature").Run()
+	cmd := exec.Command("git", "commit", "--allow-empty", "-m", "Feature commit")
+	cmd.Env = append(os.Environ(),
+		"GIT_COMMITTER_NAME=Alice",
+		"GIT_COMMITTER_DATE=2020-01-02T03:04:05Z",
+	)
+	require.NoError(t, cmd.Run())
+	exec.Command("git", "checkout", "-").Run()
+	exec.Command("git", "branch", "another").Run()
+
+	branches, err := git.ListBranchesWithInfo()
+	require.NoError(t, err, "ListBranchesWithInfo should succeed")
+	require.Len(t, branches, 2, "Should return 2 branches (excluding current)")
+
+	assert.Equal(t, "another", branches[0].Name, "Branches should be sorted alphabetically")
+	assert.Equal(t, "Test User", branches[0].LastCommitter)
+
+	feature := branches[1]
+	assert.Equal(t, "feature", feature.Name)
+	assert.Equal(t, "Alice", feature.LastCommitter)
+	assert.Equal(t, int64(1577934245), feature.LastCommitDate.Unix())
+	assert.Contains(t, feature.RelativeAge, "ago")
+}
+
+// createBranches creates count branches at HEAD in a single git invocation.
+func createBranches(t testing.TB, count int) {
+	t.Helper()
+
+	var refs strings.Builder
+	for i := range count {
+		fmt.Fprintf(&refs, "create refs/heads/branch-%04d HEAD\n", i)
+	}
+	cmd := exec.Command("git", "update-ref", "--stdin")
+	cmd.Stdin = strings.NewReader(refs.String())
+	require.NoError(t, cmd.Run())
+}
+
+// TestListBranchesWithInfo_ManyBranches tests that hundreds of branches are listed well under a second.
+func TestListBranchesWithInfo_ManyBranches(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	createBranches(t, 400)
+
+	start := time.Now()
+	branches, err := git.ListBranchesWithInfo()
+	elapsed := time.Since(start)
+	require.NoError(t, err)
+	assert.Len(t, branches, 400)
+	assert.Less(t, elapsed, time.Second, "Listing should not run per-branch commands")
+}
+
+// BenchmarkListBranchesWithInfo measures listing branch metadata in a repository with 400 branches.
+func BenchmarkListBranchesWithInfo(b *testing.B) {
+	repo := b.TempDir()
+	exec.Command("git", "init", repo).Run()
+	exec.Command("git", "-C", repo, "-c", "user.name=Test User", "-c", "user.email=test@example.com",
+		"commit", "--allow-empty", "-m", "Initial commit").Run()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(b, os.Chdir(repo))
+
+	createBranches(b, 400)
+
+	for b.Loop() {
+		if _, err := git.ListBranchesWithInfo(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestListBranchesWithInfo_OnlyCurrentBranch tests that the current branch is excluded.
+func TestListBranchesWithInfo_OnlyCurrentBranch(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	branches, err := git.ListBranchesWithInfo()
+	assert.NoError(t, err)
+	assert.Empty(t, branches, "Should return no branches when only current branch exists")
+}
+
+// TestParseBranchInfo_LineEndings tests that branch metadata parses the same with LF and CRLF line endings,
+// which git on Windows can emit, leaving no carriage return in the last field.
+func TestParseBranchInfo_LineEndings(t *testing.T) {
+	lines := []string{
+		strings.Join([]string{"*", "main", "1111111", "1700000000", "2 weeks ago", "Alice", "origin", "refs/heads/main", "", "<alice@example.com>", "<alice@example.com>"}, "\x00"),
+		strings.Join([]string{" ", "feature/x", "2222222", "1700000000", "3 days ago", "Bob", "origin", "refs/heads/feature/x", "[gone]", "<carol@example.com>", "<bob@example.com>"}, "\x00"),
+	}
+
+	for name, separator := range map[string]string{"LF": "\n", "CRLF": "\r\n"} {
+		t.Run(name, func(t *testing.T) {
+			branches, current := git.ParseBranchInfo(strings.Join(lines, separator) + separator)
+			require.Len(t, branches, 2)
+			assert.Equal(t, "main", current)
+			assert.Equal(t, git.Upstream{Remote: "origin", Branch: "feature/x"}, branches[1].Upstream)
+			assert.True(t, branches[1].Gone, "The [gone] track should be recognized before the line ending")
+			assert.Equal(t, "Bob", branches[1].LastCommitter)
+			assert.Equal(t, "carol@example.com", branches[1].AuthorEmail, "The angle brackets should be stripped")
+			assert.Equal(t, "bob@example.com", branches[1].CommitterEmail, "The committer email should be the last field, before the line ending")
+		})
+	}
+}
+
+// TestGetDefaultBranch_FallsBackToLocalBranch tests default branch detection without an origin remote.
+func TestGetDefaultBranch_FallsBackToLocalBranch(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	currentBranch, err := git.GetCurrentBranch()
+	require.NoError(t, err)
+
+	branch, err := git.GetDefaultBranch()
+	assert.NoError(t, err, "GetDefaultBranch should succeed")
+	assert.Equal(t, currentBranch, branch, "Should fall back to the local main/master branch")
+}
+
+// TestGetDefaultBranch_UsesOriginHEAD tests default branch detection from origin/HEAD.
+func TestGetDefaultBranch_UsesOriginHEAD(t *testing.T) {
+	repo, _ := setupRepoWithRemote(t, "develop")
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/develop").Run()
+
+	branch, err := git.GetDefaultBranch()
+	assert.NoError(t, err)
+	assert.Equal(t, "develop", branch, "Should use the branch origin/HEAD points to")
+}
+
+// TestGetDefaultBranch_Undetermined tests that detection fails when no candidate exists.
+func TestGetDefaultBranch_Undetermined(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "checkout", "-q", "-b", "trunk").Run()
+	exec.Command("git", "branch", "-D", "main").Run()
+	exec.Command("git", "branch", "-D", "master").Run()
+
+	_, err = git.GetDefaultBranch()
+	assert.Error(t, err, "GetDefaultBranch should fail without origin/HEAD, main, or master")
+}
+
+// TestGetMergedBranches tests classifying branches merged into a base branch.
+func TestGetMergedBranches(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	r := git.Repository{Dir: repo}
+
+	base, err := r.GetCurrentBranch()
+	require.NoError(t, err)
+
+	exec.Command("git", "-C", repo, "branch", "merged").Run()
+	exec.Command("git", "-C", repo, "checkout", "-b", "unmerged").Run()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "-C", repo, "checkout", base).Run()
+
+	merged, err := r.GetMergedBranches(base)
+	assert.NoError(t, err, "GetMergedBranches should succeed")
+	assert.True(t, merged["merged"])
+	assert.False(t, merged["unmerged"])
+
+	_, err = r.GetMergedBranches("does-not-exist")
+	assert.Error(t, err, "GetMergedBranches should fail for an unknown base")
+}
+
+// TestGetBranchSHA tests resolving the tip of a branch.
+func TestGetBranchSHA(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	r := git.Repository{Dir: repo}
+
+	exec.Command("git", "-C", repo, "branch", "feature-x").Run()
+	output, err := exec.Command("git", "-C", repo, "rev-parse", "feature-x").Output()
+	require.NoError(t, err)
+
+	sha, err := r.GetBranchSHA("feature-x")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, sha)
+	assert.True(t, strings.HasPrefix(strings.TrimSpace(string(output)), sha), "Should be an abbreviation of the tip")
+
+	_, err = r.GetBranchSHA("missing")
+	assert.Error(t, err, "Should fail for a branch that doesn't exist")
+}
+
+// TestGetAheadBehind tests counting commits a branch is ahead of and behind a base branch.
+func TestGetAheadBehind(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	base, err := git.GetCurrentBranch()
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "same").Run()
+	exec.Command("git", "checkout", "-q", "-b", "diverged").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Branch commit 1").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Branch commit 2").Run()
+	exec.Command("git", "checkout", "-q", base).Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Base commit").Run()
+
+	ahead, behind, err := git.GetAheadBehind("diverged", base)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ahead)
+	assert.Equal(t, 1, behind)
+
+	ahead, behind, err = git.GetAheadBehind("same", base)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, ahead)
+	assert.Equal(t, 1, behind)
+
+	_, _, err = git.GetAheadBehind("missing", base)
+	assert.Error(t, err)
+
+	counts := git.GetAheadBehindCounts([]string{"diverged", "same", "missing"}, base)
+	assert.Equal(t, map[string]git.AheadBehind{
+		"diverged": {Ahead: 2, Behind: 1},
+		"same":     {Ahead: 0, Behind: 1},
+	}, counts, "Branches that can't be compared should be omitted")
+}
+
+// TestGetBranchCommits tests listing the commits a branch has that its base doesn't.
+func TestGetBranchCommits(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	base, err := git.GetCurrentBranch()
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "empty").Run()
+	exec.Command("git", "checkout", "-q", "-b", "feature").Run()
+	for _, subject := range []string{"First", "Second", "Third"} {
+		exec.Command("git", "commit", "--allow-empty", "-m", subject).Run()
+	}
+	exec.Command("git", "checkout", "-q", base).Run()
+
+	commits, err := git.GetBranchCommits("feature", base, 2)
+	require.NoError(t, err)
+	require.Len(t, commits, 2, "Should respect the limit")
+	assert.True(t, strings.HasSuffix(commits[0], " Third"), "Newest commit should come first: %s", commits[0])
+	assert.True(t, strings.HasSuffix(commits[1], " Second"))
+
+	commits, err = git.GetBranchCommits("empty", base, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, commits, "A branch without unique commits has nothing to show")
+
+	stat, err := git.GetBranchDiffStat("empty", base)
+	assert.NoError(t, err)
+	assert.Empty(t, stat)
+
+	_, err = git.GetBranchCommits("missing", base, 10)
+	assert.Error(t, err)
+}
+
+// TestGetBranchOrigin tests finding who started a branch and when its reflog says it was created.
+func TestGetBranchOrigin(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	base, err := git.GetCurrentBranch()
+	require.NoError(t, err)
+
+	before := time.Now().Add(-time.Second)
+	exec.Command("git", "branch", "empty").Run()
+	exec.Command("git", "checkout", "-q", "-b", "feature").Run()
+	for _, author := range []string{"Jane Doe <jane@example.com>", "John Roe <john@example.com>"} {
+		exec.Command("git", "commit", "--allow-empty", "-m", "Work", "--author", author).Run()
+	}
+	exec.Command("git", "checkout", "-q", base).Run()
+
+	creator, err := git.GetBranchCreator("feature", base)
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Doe", creator, "The author of the oldest unique commit started the branch")
+
+	creator, err = git.GetBranchCreator("empty", base)
+	assert.NoError(t, err)
+	assert.Empty(t, creator, "A branch without unique commits has no creator")
+
+	created, err := git.GetBranchCreatedAt("feature")
+	require.NoError(t, err)
+	assert.WithinRange(t, created, before, time.Now().Add(time.Second))
+
+	// A ref written without a reflog, like one fetched with a refspec, has no creation date
+	exec.Command("git", "-c", "core.logAllRefUpdates=false", "update-ref", "refs/heads/fetched", "HEAD").Run()
+	created, err = git.GetBranchCreatedAt("fetched")
+	assert.NoError(t, err)
+	assert.True(t, created.IsZero())
+
+	_, err = git.GetBranchCreator("missing", base)
+	assert.Error(t, err)
+}
+
+// setupDivergingFile commits file.txt on the current branch and a different version on branch "other",
+// then modifies file.txt in the working tree so switching to "other" would overwrite local changes.
+func setupDivergingFile(t *testing.T) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile("file.txt", []byte("base\n"), 0o644))
+	exec.Command("git", "add", "file.txt").Run()
+	exec.Command("git", "commit", "-m", "Add file").Run()
+	exec.Command("git", "checkout", "-b", "other").Run()
+	require.NoError(t, os.WriteFile("file.txt", []byte("other\n"), 0o644))
+	exec.Command("git", "commit", "-am", "Change file").Run()
+	exec.Command("git", "checkout", "-").Run()
+	require.NoError(t, os.WriteFile("file.txt", []byte("local change\n"), 0o644))
+}
+
+// TestCheckoutBranch_Success tests switching to another branch.
+func TestCheckoutBranch_Success(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	r := git.Repository{Dir: repo}
+
+	exec.Command("git", "-C", repo, "branch", "feature").Run()
+
+	err := r.CheckoutBranch("feature")
+	require.NoError(t, err)
+
+	current, err := r.GetCurrentBranch()
+	require.NoError(t, err)
+	assert.Equal(t, "feature", current)
+}
+
+// TestCheckoutBranch_DirtyWorkingTree tests that git's error is returned when local changes would be overwritten.
+func TestCheckoutBranch_DirtyWorkingTree(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	setupDivergingFile(t)
+
+	err = git.CheckoutBranch("other")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to switch to branch 'other'")
+	assert.Contains(t, err.Error(), "local changes")
+}
+
+// TestOpenRepository_FromSubdirectory tests that git commands run from the top level after opening a repository from a nested subdirectory.
+func TestOpenRepository_FromSubdirectory(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "feature").Run()
+	nested := filepath.Join(repo, "a", "b", "c")
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(nested)
+	require.NoError(t, err)
+
+	require.NoError(t, git.OpenRepository(""))
+	defer git.CloseRepository()
+
+	root, _ := filepath.EvalSymlinks(repo)
+	assert.Equal(t, root, git.RepoRoot())
+
+	branches, err := git.ListBranches()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feature"}, branches)
+}
+
+// TestOpenRepository_OtherPath tests that git commands run in the opened repository rather than the working directory.
+func TestOpenRepository_OtherPath(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "elsewhere").Run()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, git.OpenRepository(repo))
+	defer git.CloseRepository()
+
+	branches, err := git.ListBranches()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"elsewhere"}, branches)
+
+	require.NoError(t, git.DeleteBranch("elsewhere"))
+	err = exec.Command("git", "-C", repo, "rev-parse", "--verify", "refs/heads/elsewhere").Run()
+	assert.Error(t, err, "Branch should be deleted from the opened repository")
+}
+
+// TestOpenRepository_Bare tests that a bare repository is opened at its git directory.
+func TestOpenRepository_Bare(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "feature").Run()
+	bare := filepath.Join(t.TempDir(), "bare.git")
+	require.NoError(t, exec.Command("git", "clone", "--bare", repo, bare).Run())
+
+	require.NoError(t, git.OpenRepository(bare))
+	defer git.CloseRepository()
+
+	root, _ := filepath.EvalSymlinks(bare)
+	assert.Equal(t, root, git.RepoRoot())
+
+	branches, err := git.ListBranches()
+	require.NoError(t, err)
+	assert.Contains(t, branches, "feature")
+}
+
+// TestOpenRepository_Invalid tests that opening a missing directory or a non-repository fails and opens nothing.
+func TestOpenRepository_Invalid(t *testing.T) {
+	err := git.OpenRepository(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "is not a directory")
+	assert.Empty(t, git.RepoRoot())
+
+	err = git.OpenRepository(t.TempDir())
+	assert.ErrorContains(t, err, "not a git repository")
+	assert.Empty(t, git.RepoRoot())
+}
+
+// TestNewRepository_ResolvesPaths tests that relative paths and symlinks are resolved to the absolute
+// top-level directory, and that repositories are used side by side without changing directory.
+func TestNewRepository_ResolvesPaths(t *testing.T) {
+	repo := setupTestRepo(t)
+	other := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "in-repo").Run()
+	exec.Command("git", "-C", other, "branch", "in-other").Run()
+	root, _ := filepath.EvalSymlinks(repo)
+
+	link := filepath.Join(t.TempDir(), "link")
+	require.NoError(t, os.Symlink(repo, link))
+	viaLink, err := git.NewRepository(link)
+	require.NoError(t, err)
+	assert.Equal(t, root, viaLink.Dir, "Symlinks should be resolved")
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(filepath.Dir(repo)))
+	relative, err := git.NewRepository(filepath.Base(repo))
+	require.NoError(t, err)
+	assert.Equal(t, root, relative.Dir, "Relative paths should be made absolute")
+
+	otherRepo, err := git.NewRepository(other)
+	require.NoError(t, err)
+	branches, err := relative.ListBranches()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"in-repo"}, branches)
+	branches, err = otherRepo.ListBranches()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"in-other"}, branches)
+
+	_, err = git.NewRepository(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "is not a directory")
+	_, err = git.NewRepository(t.TempDir())
+	assert.ErrorContains(t, err, "not a git repository")
+}
+
+// TestRepository_ZeroValueUsesDefault tests that the zero Repository runs git like the package-level functions.
+func TestRepository_ZeroValueUsesDefault(t *testing.T) {
+	repo := setupTestRepo(t)
+	exec.Command("git", "-C", repo, "branch", "feature").Run()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	branches, err := git.Repository{}.ListBranches()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feature"}, branches)
+}
+
+// TestRestoreBranch tests recreating a deleted branch at its old tip without overwriting existing branches.
+func TestRestoreBranch(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	r := git.Repository{Dir: repo}
+
+	exec.Command("git", "-C", repo, "branch", "feature").Run()
+	sha, err := r.GetBranchSHA("feature")
+	require.NoError(t, err)
+	require.NoError(t, r.DeleteBranch("feature"))
+
+	require.NoError(t, r.RestoreBranch("feature", sha))
+	restored, err := r.GetBranchSHA("feature")
+	require.NoError(t, err)
+	assert.Equal(t, sha, restored)
+
+	err = r.RestoreBranch("feature", sha)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to restore branch 'feature'")
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+// TestAnalyzeBranches tests classifying branches before deletion.
+func TestAnalyzeBranches(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+	defer git.SetProtectedPatterns(git.DefaultProtectedBranches)
+
+	base, _, err := git.GetHeadBranch()
+	require.NoError(t, err)
+	exec.Command("git", "branch", "merged").Run()
+	exec.Command("git", "branch", "release").Run()
+	exec.Command("git", "checkout", "-q", "-b", "unmerged").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	require.NoError(t, os.WriteFile("file.txt", []byte("stashed"), 0o644))
+	exec.Command("git", "add", "file.txt").Run()
+	require.NoError(t, exec.Command("git", "stash").Run())
+	exec.Command("git", "checkout", "-q", base).Run()
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	exec.Command("git", "branch", "wt").Run()
+	require.NoError(t, exec.Command("git", "worktree", "add", worktreePath, "wt").Run())
+
+	git.SetProtectedPatterns([]string{"release"})
+	analyses, err := git.AnalyzeBranches([]string{"merged", "unmerged", "wt", "release", "missing"})
+	require.NoError(t, err)
+	require.Len(t, analyses, 5)
+
+	assert.Equal(t, git.StatusMerged, analyses[0].Status)
+	assert.Empty(t, analyses[0].Worktree)
+
+	assert.Equal(t, git.StatusUnmerged, analyses[1].Status)
+	assert.Equal(t, 1, analyses[1].Stashes)
+
+	assert.Contains(t, analyses[2].Worktree, "wt")
+	assert.True(t, analyses[3].Protected)
+	assert.Equal(t, git.StatusUnknown, analyses[4].Status, "Branches that can't be checked are of unknown status")
+}
+
+// TestBranches_SpecialNames tests listing and deleting branches with slashes, unicode, hashes and dashes,
+// and a branch that shares its name with a tag.
+func TestBranches_SpecialNames(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	names := []string{"feature/ユーザー管理", "fix/#123-weird-chars", "release--2024.01", "v1.0", "émoji-🚀"}
+	for _, name := range names {
+		require.NoError(t, exec.Command("git", "branch", name).Run(), "Failed to create branch %s", name)
+	}
+	require.NoError(t, exec.Command("git", "tag", "v1.0").Run())
+
+	branches, err := git.ListBranches()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, names, branches)
+
+	infos, err := git.ListBranchesWithInfo()
+	require.NoError(t, err)
+	var infoNames []string
+	for _, info := range infos {
+		infoNames = append(infoNames, info.Name)
+	}
+	assert.ElementsMatch(t, names, infoNames)
+
+	base, _, err := git.GetHeadBranch()
+	require.NoError(t, err)
+	merged, err := git.GetMergedBranches(base)
+	require.NoError(t, err)
+	for _, name := range names {
+		assert.True(t, merged[name], "%s should be reported as merged", name)
+	}
+
+	for _, name := range names {
+		assert.NoError(t, git.DeleteBranch(name), "Failed to delete branch %s", name)
+	}
+	branches, err = git.ListBranches()
+	require.NoError(t, err)
+	assert.Empty(t, branches)
+}
+
+// TestIsBranchMerged_TagWithSameName tests that a tag sharing the branch's name doesn't stand in for the branch.
+func TestIsBranchMerged_TagWithSameName(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	base, _, err := git.GetHeadBranch()
+	require.NoError(t, err)
+	exec.Command("git", "tag", "v2").Run()
+	exec.Command("git", "checkout", "-q", "-b", "v2").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "checkout", "-q", base).Run()
+
+	merged, err := git.IsBranchMerged("v2")
+	require.NoError(t, err)
+	assert.False(t, merged, "The merged tag must not hide the unmerged branch")
+
+	ahead, _, err := git.GetAheadBehind("v2", base)
+	require.NoError(t, err)
+	assert.Equal(t, 1, ahead)
+}
+
+// TestIsSquashMerged tests detecting branches whose content was merged with "squash and merge" or cherry-picked,
+// which git branch -d still refuses to delete.
+func TestIsSquashMerged(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	base, _, err := git.GetHeadBranch()
+	require.NoError(t, err)
+
+	commitFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(name, []byte(content), 0o644))
+		exec.Command("git", "add", name).Run()
+		require.NoError(t, exec.Command("git", "commit", "-q", "-m", "Change "+name).Run())
+	}
+
+	// A branch with a single commit, cherry-picked onto base below
+	exec.Command("git", "checkout", "-q", "-b", "picked").Run()
+	commitFile("c.txt", "picked\n")
+	exec.Command("git", "checkout", "-q", base).Run()
+
+	// A branch with two commits merged with `git merge --squash`, followed by another commit on base
+	exec.Command("git", "checkout", "-q", "-b", "squashed").Run()
+	commitFile("a.txt", "one\n")
+	commitFile("a.txt", "one\ntwo\n")
+	exec.Command("git", "checkout", "-q", base).Run()
+	require.NoError(t, exec.Command("git", "merge", "-q", "--squash", "squashed").Run())
+	require.NoError(t, exec.Command("git", "commit", "-q", "-m", "Squash merge").Run())
+	commitFile("b.txt", "later\n")
+
+	// Cherry-picked once base moved on, so the commit differs from the branch's
+	require.NoError(t, exec.Command("git", "cherry-pick", "picked").Run())
+
+	// A branch with changes that are not in base, and a branch that is merged normally
+	exec.Command("git", "checkout", "-q", "-b", "unmerged").Run()
+	commitFile("d.txt", "new\n")
+	exec.Command("git", "checkout", "-q", base).Run()
+	exec.Command("git", "branch", "merged").Run()
+
+	for branch, want := range map[string]bool{"squashed": true, "picked": true, "unmerged": false, "merged": false} {
+		squashed, err := git.IsSquashMerged(branch, base)
+		require.NoError(t, err, branch)
+		assert.Equal(t, want, squashed, branch)
+	}
+
+	_, err = git.IsSquashMerged("missing", base)
+	assert.Error(t, err)
+
+	assert.Equal(t, map[string]bool{"squashed": true, "picked": true},
+		git.ListSquashMerged([]string{"squashed", "picked", "unmerged", "missing"}, base))
 }
@@ -0,0 +1,121 @@
+package unit
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kdaito/gelete/pkg/gitops"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestRepo creates a repository with the given branches and opens it with gitops.
+// The working directory is left alone, so the tests also check that nothing depends on it.
+func openTestRepo(t *testing.T, branches ...string) (gitops.Repo, string) {
+	t.Helper()
+
+	dir := setupTestRepo(t)
+	for _, branch := range branches {
+		require.NoError(t, exec.Command("git", "-C", dir, "branch", branch).Run())
+	}
+
+	repo, err := gitops.Open(context.Background(), dir)
+	require.NoError(t, err)
+	return repo, dir
+}
+
+// TestGitops_Open tests opening repositories by path, including from a subdirectory and outside a repository.
+func TestGitops_Open(t *testing.T) {
+	repo, dir := openTestRepo(t)
+	root, _ := filepath.EvalSymlinks(dir)
+	assert.Equal(t, root, mustEvalSymlinks(t, repo.Root()))
+
+	sub := filepath.Join(dir, "sub")
+	require.NoError(t, os.Mkdir(sub, 0o755))
+	fromSub, err := gitops.Open(context.Background(), sub)
+	require.NoError(t, err)
+	assert.Equal(t, repo.Root(), fromSub.Root(), "Subdirectories should resolve to the top-level directory")
+
+	_, err = gitops.Open(context.Background(), t.TempDir())
+	assert.Error(t, err, "Directories outside a repository should be rejected")
+}
+
+// TestGitops_ListBranches tests listing branches of several repositories independently.
+func TestGitops_ListBranches(t *testing.T) {
+	first, _ := openTestRepo(t, "feature-a", "main")
+	second, _ := openTestRepo(t, "feature-b")
+
+	branches, err := first.ListBranches(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feature-a"}, branches, "The current and protected branches should be excluded")
+
+	infos, err := second.ListBranchesWithInfo(context.Background())
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, "feature-b", infos[0].Name)
+	assert.NotEmpty(t, infos[0].SHA)
+}
+
+// TestGitops_DeleteBranch tests safe and force deletion, and detecting unmerged branches.
+func TestGitops_DeleteBranch(t *testing.T) {
+	repo, dir := openTestRepo(t, "merged")
+	exec.Command("git", "-C", dir, "checkout", "-q", "-b", "unmerged").Run()
+	exec.Command("git", "-C", dir, "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "-C", dir, "checkout", "-q", "-").Run()
+
+	ctx := context.Background()
+	require.NoError(t, repo.DeleteBranch(ctx, "merged"))
+
+	err := repo.DeleteBranch(ctx, "unmerged")
+	require.Error(t, err)
+	assert.True(t, gitops.IsUnmergedError(err))
+
+	require.NoError(t, repo.ForceDeleteBranch(ctx, "unmerged"))
+	branches, err := repo.ListBranches(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, branches)
+
+	assert.Error(t, repo.ForceDeleteBranch(ctx, "missing"), "Deleting a missing branch should fail")
+}
+
+// TestGitops_Worktrees tests listing and removing linked worktrees.
+func TestGitops_Worktrees(t *testing.T) {
+	repo, dir := openTestRepo(t, "wt")
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	require.NoError(t, exec.Command("git", "-C", dir, "worktree", "add", worktreePath, "wt").Run())
+
+	ctx := context.Background()
+	worktrees, err := repo.ListWorktrees(ctx)
+	require.NoError(t, err)
+	require.Len(t, worktrees, 2)
+	assert.True(t, worktrees[0].Main)
+	assert.Equal(t, "wt", worktrees[1].Branch)
+
+	require.NoError(t, repo.RemoveWorktree(ctx, worktrees[1].Path))
+	worktrees, err = repo.ListWorktrees(ctx)
+	require.NoError(t, err)
+	assert.Len(t, worktrees, 1)
+}
+
+// TestGitops_Cancelled tests that operations stop when their context is cancelled.
+func TestGitops_Cancelled(t *testing.T) {
+	repo, _ := openTestRepo(t, "feature")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.ListBranchesWithInfo(ctx)
+	require.Error(t, err)
+	assert.True(t, gitops.IsInterrupted(err))
+}
+
+// mustEvalSymlinks resolves symlinks in path, e.g. /tmp on macOS
+func mustEvalSymlinks(t *testing.T, path string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(path)
+	require.NoError(t, err)
+	return resolved
+}
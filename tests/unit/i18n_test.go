@@ -0,0 +1,170 @@
+package unit
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestI18n_Resolve tests that --lang takes precedence over GELETE_LANG, which takes precedence over the locale.
+func TestI18n_Resolve(t *testing.T) {
+	tests := []struct {
+		name string
+		flag string
+		env  map[string]string
+		want i18n.Lang
+	}{
+		{name: "nothing set", want: i18n.English},
+		{name: "flag", flag: "ja", env: map[string]string{"GELETE_LANG": "en", "LANG": "en_US.UTF-8"}, want: i18n.Japanese},
+		{name: "GELETE_LANG", env: map[string]string{"GELETE_LANG": "ja", "LANG": "en_US.UTF-8"}, want: i18n.Japanese},
+		{name: "LANG", env: map[string]string{"LANG": "ja_JP.UTF-8"}, want: i18n.Japanese},
+		{name: "LC_ALL overrides LANG", env: map[string]string{"LC_ALL": "en_US.UTF-8", "LANG": "ja_JP.UTF-8"}, want: i18n.English},
+		{name: "untranslated locale", env: map[string]string{"LANG": "fr_FR.UTF-8"}, want: i18n.English},
+		{name: "C locale", env: map[string]string{"LC_ALL": "C", "LANG": "ja_JP.UTF-8"}, want: i18n.English},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lang, err := i18n.Resolve(tt.flag, func(key string) string { return tt.env[key] })
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, lang)
+		})
+	}
+}
+
+// TestI18n_ResolveRejectsUnsupported tests that an unsupported --lang or GELETE_LANG is an error naming the languages.
+func TestI18n_ResolveRejectsUnsupported(t *testing.T) {
+	_, err := i18n.Resolve("fr", func(string) string { return "" })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --lang")
+	assert.Contains(t, err.Error(), "en, ja")
+
+	_, err = i18n.Resolve("", func(key string) string { return map[string]string{"GELETE_LANG": "xx"}[key] })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid GELETE_LANG")
+}
+
+// TestI18n_Parse tests that language tags and locales name their language whatever their region and encoding.
+func TestI18n_Parse(t *testing.T) {
+	for _, name := range []string{"ja", "JA", "ja-JP", "ja_JP.UTF-8", "ja_JP.eucJP@euro"} {
+		lang, err := i18n.Parse(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, i18n.Japanese, lang, name)
+	}
+}
+
+// TestI18n_Count tests that English uses the plural for every count but one and Japanese never changes the noun.
+func TestI18n_Count(t *testing.T) {
+	assert.Equal(t, "0 branches", i18n.English.Count(0, i18n.Branch))
+	assert.Equal(t, "1 branch", i18n.English.Count(1, i18n.Branch))
+	assert.Equal(t, "2 remote branches", i18n.English.Count(2, i18n.RemoteBranch))
+	assert.Equal(t, "1 件のブランチ", i18n.Japanese.Count(1, i18n.Branch))
+	assert.Equal(t, "3 件のブランチ", i18n.Japanese.Count(3, i18n.Branch))
+}
+
+// TestI18n_ZeroLangIsEnglish tests that models built without a language render in English.
+func TestI18n_ZeroLangIsEnglish(t *testing.T) {
+	var zero i18n.Lang
+	assert.Equal(t, i18n.English.T(i18n.DoneTitle), zero.T(i18n.DoneTitle))
+	assert.Equal(t, "Switched to branch x", zero.T(i18n.SwitchedToBranch, "x"))
+}
+
+// localizedSelectionModel builds a selection screen with merge badges, divergence and metadata columns,
+// so that the columns after the wider Japanese badges can be checked to line up.
+func localizedSelectionModel(lang i18n.Lang) ui.AppModel {
+	m := newTestModel("feature/login", "fix/typo", "old-experiment")
+	m.Styles = ui.NewStyles(ui.ThemeAuto, false)
+	m.Lang = lang
+	m.BaseBranch = "main"
+	m.MergedBranches = map[string]bool{"fix/typo": true}
+	m.SquashMerged = map[string]bool{"feature/login": true}
+	m.Divergence = map[string]git.AheadBehind{
+		"feature/login": {Ahead: 2, Behind: 1}, "fix/typo": {Ahead: 0, Behind: 4}, "old-experiment": {Ahead: 7, Behind: 30},
+	}
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	m.Clock = func() time.Time { return now }
+	m.BranchDetails = map[string]git.BranchInfo{
+		"feature/login":  {Name: "feature/login", LastCommitDate: now.AddDate(0, 0, -2), LastCommitter: "Alice"},
+		"fix/typo":       {Name: "fix/typo", LastCommitDate: now.AddDate(0, 0, -21), LastCommitter: "Bob"},
+		"old-experiment": {Name: "old-experiment", LastCommitDate: now.AddDate(-1, 0, 0), LastCommitter: "Carol"},
+	}
+	m.Selected["fix/typo"] = true
+	return m
+}
+
+// localizedDoneModel builds the done screen of a run with a deletion, a skip, a failure and a remote deletion
+func localizedDoneModel(lang i18n.Lang) ui.AppModel {
+	m := newTestModel("feature-a", "feature-b", "feature-c")
+	m.Styles = ui.NewStyles(ui.ThemeAuto, false)
+	m.Lang = lang
+	m.State = ui.StateDone
+	m.Results = []ui.DeletionResult{
+		{Branch: "feature-a", Status: ui.ResultDeleted, SHA: "1111111"},
+		{Branch: "feature-b", Status: ui.ResultSkipped, Reason: "force delete declined"},
+		{Branch: "feature-c", Status: ui.ResultFailed, Reason: "error: cannot lock ref"},
+	}
+	m.DeletedCount = 1
+	m.RemoteDeletedCount = 1
+	return m
+}
+
+// localizedBackupDoneModel returns a done screen with the tag deletion, backup and restore lines, in lang
+func localizedBackupDoneModel(lang i18n.Lang) ui.AppModel {
+	m := newTestModel("feature-a", "feature-b", "feature-c")
+	m.Styles = ui.NewStyles(ui.ThemeAuto, false)
+	m.Lang = lang
+	m.State = ui.StateDone
+	m.Results = []ui.DeletionResult{
+		{Branch: "feature-a", Status: ui.ResultDeleted, SHA: "1111111"},
+		{Branch: "feature-b", Status: ui.ResultForceDeleted, SHA: "2222222"},
+		{Branch: "feature-c", Status: ui.ResultForceDeleted, SHA: "3333333"},
+	}
+	m.DeletedCount = 3
+	m.DeletedTagCount = 2
+	m.TagFailures = map[string]string{"v1.0": "tag is protected"}
+	m.BackupTags = map[string]string{"feature-b": "gelete-backup/feature-b-2024-05-01"}
+	m.BackupBundle = "backup.bundle"
+	m.BundledBranches = []string{"feature-c"}
+	m.Restored = map[string]bool{"feature-a": true}
+	m.RestoreFailures = map[string]string{"feature-b": "a branch named 'feature-b' already exists"}
+	return m
+}
+
+// TestI18n_ScreensGolden tests the selection and done screens in every language against golden files.
+// Run with -update to accept changes.
+func TestI18n_ScreensGolden(t *testing.T) {
+	for _, lang := range i18n.Langs {
+		t.Run(string(lang), func(t *testing.T) {
+			dir := filepath.Join("testdata", "i18n", string(lang))
+			assertGolden(t, filepath.Join(dir, "selection.golden"), localizedSelectionModel(lang).View())
+			assertGolden(t, filepath.Join(dir, "done.golden"), localizedDoneModel(lang).View())
+			assertGolden(t, filepath.Join(dir, "done-backup.golden"), localizedBackupDoneModel(lang).View())
+		})
+	}
+}
+
+// TestI18n_BadgeColumnsAlign tests that the columns after the merge badges start at the same display column
+// on every row, although the Japanese badges are made of double-width characters.
+func TestI18n_BadgeColumnsAlign(t *testing.T) {
+	for _, lang := range i18n.Langs {
+		t.Run(string(lang), func(t *testing.T) {
+			var columns []int
+			for _, line := range strings.Split(localizedSelectionModel(lang).View(), "\n") {
+				before, _, found := strings.Cut(line, "↑")
+				if found && strings.Contains(line, "] ") {
+					columns = append(columns, lipgloss.Width(before))
+				}
+			}
+			require.Len(t, columns, 3)
+			assert.Equal(t, columns[0], columns[1])
+			assert.Equal(t, columns[0], columns[2])
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package unit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ignore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIgnore_Parse tests comments, blank lines, negation and escapes in ignore files.
+func TestIgnore_Parse(t *testing.T) {
+	rules, err := ignore.Parse("# long-lived branches\n\nrelease/*\n  !release/tmp  \n\\#hash\n\\!bang\n")
+	require.NoError(t, err)
+	assert.Equal(t, []git.IgnoreRule{
+		{Pattern: "release/*"},
+		{Pattern: "release/tmp", Negate: true},
+		{Pattern: "#hash"},
+		{Pattern: "!bang"},
+	}, rules)
+
+	_, err = ignore.Parse("ok\nrelease/[0-9\n")
+	assert.ErrorContains(t, err, "line 2: invalid pattern 'release/[0-9'")
+}
+
+// TestIgnore_LastMatchingRuleWins tests that "!" includes branches again and that later rules take precedence.
+func TestIgnore_LastMatchingRuleWins(t *testing.T) {
+	defer git.SetIgnoreRules(nil)
+
+	dir := t.TempDir()
+	shared := writeConfig(t, dir, "shared", "release/*\nkeep-*\n")
+	personal := writeConfig(t, dir, "personal", "!release/tmp\n")
+	rules, err := ignore.LoadFiles(shared, filepath.Join(dir, "missing"), personal)
+	require.NoError(t, err)
+	git.SetIgnoreRules(rules)
+
+	assert.True(t, git.IsIgnored("release/1.0"))
+	assert.True(t, git.IsIgnored("keep-me"))
+	assert.False(t, git.IsIgnored("release/tmp"), "A later ! rule should include the branch again")
+	assert.False(t, git.IsIgnored("release"), "* should not match an empty path segment")
+	assert.False(t, git.IsIgnored("feature-a"))
+}
+
+// TestListBranches_ExcludesIgnored tests that the ignore files are found from the repository root, even when
+// gelete runs in a subdirectory, and that ignored branches are not listed.
+func TestListBranches_ExcludesIgnored(t *testing.T) {
+	repo := setupTestRepo(t)
+	for _, branch := range []string{"feature-a", "release/1.0", "release/tmp", "scratch"} {
+		exec.Command("git", "-C", repo, "branch", branch).Run()
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ignore.FileName), []byte("release/*\n!release/tmp\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".git", ignore.FileName), []byte("scratch\n"), 0o644))
+	subdir := filepath.Join(repo, "nested", "dir")
+	require.NoError(t, os.MkdirAll(subdir, 0o755))
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(subdir))
+	defer git.SetIgnoreRules(nil)
+
+	rules, err := ignore.Load()
+	require.NoError(t, err)
+	git.SetIgnoreRules(rules)
+
+	branches, err := git.ListBranches()
+	require.NoError(t, err)
+	assert.NotContains(t, branches, "release/1.0")
+	assert.NotContains(t, branches, "scratch", "The ignore file in the git directory should apply too")
+	assert.Contains(t, branches, "release/tmp")
+	assert.Contains(t, branches, "feature-a")
+
+	infos, err := git.ListBranchesWithInfo()
+	require.NoError(t, err)
+	assert.Len(t, infos, len(branches))
+}
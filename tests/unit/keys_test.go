@@ -0,0 +1,97 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newKeyMap returns the default keys with bindings applied, failing the test if they are refused.
+func newKeyMap(t *testing.T, bindings map[string][]string) *ui.KeyMap {
+	t.Helper()
+
+	keys, err := ui.NewKeyMap(bindings)
+	require.NoError(t, err)
+	return &keys
+}
+
+// TestNewKeyMap_Validation tests that unknown actions, ctrl+c and keys bound twice on the same screen are refused.
+func TestNewKeyMap_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		bindings map[string][]string
+		wantErr  string
+	}{
+		{"unknown action", map[string][]string{"remove": {"x"}}, "unknown key action 'remove' (known actions: up, down,"},
+		{"no keys", map[string][]string{"delete": {}}, "no keys given for key action 'delete'"},
+		{"empty key", map[string][]string{"delete": {"x", "  "}}, "empty key given for key action 'delete'"},
+		{"ctrl+c", map[string][]string{"quit": {"ctrl+c"}}, "ctrl+c can't be bound to 'quit': it always quits"},
+		{"duplicate in the list", map[string][]string{"delete": {"a"}}, "key 'a' is bound to both 'select_all' and 'delete'"},
+		{"duplicate in the prompts", map[string][]string{"confirm": {"n"}}, "key 'n' is bound to both 'confirm' and 'cancel'"},
+		{"cursor keys in the prompts", map[string][]string{"cancel": {"k"}}, "key 'k' is bound to both 'up' and 'cancel'"},
+		{"collapse on group headers", map[string][]string{"collapse": {"d"}}, "key 'd' is bound to both 'collapse' and 'delete'"},
+		{"space", map[string][]string{"toggle": {"space"}, "select_all": {" "}}, "key 'space' is bound to both 'toggle' and 'select_all'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ui.NewKeyMap(tt.bindings)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	// The list and the prompts are separate screens, and collapse shares enter with toggle on purpose
+	_, err := ui.NewKeyMap(map[string][]string{"copy": {"n"}, "confirm": {"enter"}, "collapse": {"enter"}})
+	assert.NoError(t, err)
+}
+
+// TestKeyMap_DefaultHelp tests that the default keys produce the documented help lines.
+func TestKeyMap_DefaultHelp(t *testing.T) {
+	m := newTestModel("alpha")
+	assert.Contains(t, ansi.Strip(m.View()), "↑/k: up • ↓/j: down • pgup/pgdn/home/end: scroll • space/enter: toggle")
+	assert.Contains(t, ansi.Strip(m.View()), "p: preview • c: checkout • e: rename • x: compare • d: delete selected • q: quit")
+	assert.Equal(t, []string{"up", "k"}, ui.DefaultKeyMap().Up.Keys())
+}
+
+// TestKeyMap_CustomKeysRouteActions tests that rebound keys trigger their actions, the keys they replace
+// no longer do, and the help shows the rebound keys.
+func TestKeyMap_CustomKeysRouteActions(t *testing.T) {
+	m := newTestModel("alpha", "beta")
+	m.Keys = newKeyMap(t, map[string][]string{
+		"delete":  {"D"},
+		"confirm": {"enter"},
+		"cancel":  {"esc", "n"},
+		"down":    {"down", "ctrl+n"},
+		"toggle":  {"space"},
+		"quit":    {"Q"},
+	})
+
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "↓/ctrl+n: down")
+	assert.Contains(t, view, "space: toggle")
+	assert.Contains(t, view, "D: delete selected • Q: quit")
+	assert.NotContains(t, view, "d: delete selected")
+
+	m = sendKey(t, m, "j")
+	assert.Equal(t, 0, m.CursorIndex, "j is no longer bound to down")
+	m = sendKey(t, m, "enter")
+	assert.False(t, m.Selected["alpha"], "enter is no longer bound to toggle")
+	m = sendKey(t, m, " ")
+	assert.True(t, m.Selected["alpha"])
+
+	m = sendKey(t, m, "d")
+	assert.Equal(t, ui.StateSelection, m.State, "d is no longer bound to delete")
+	m = sendKey(t, m, "D")
+	require.Equal(t, ui.StateConfirmation, m.State)
+	assert.Contains(t, ansi.Strip(m.View()), "enter: confirm • esc/n: cancel")
+
+	m = sendKey(t, m, "esc")
+	require.Equal(t, ui.StateSelection, m.State, "esc cancels the confirmation")
+	m = sendKey(t, m, "D")
+	m = sendKey(t, m, "y")
+	assert.Equal(t, ui.StateConfirmation, m.State, "y is no longer bound to confirm")
+}
@@ -0,0 +1,101 @@
+package unit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupLeadingDashBranch creates a branch literally named -leading-dash. `git branch -- -leading-dash`
+// refuses the name, but branches like it still appear, e.g. created with update-ref or fetched with a refspec.
+func setupLeadingDashBranch(t *testing.T) (string, git.Repository) {
+	t.Helper()
+
+	repo := setupTestRepo(t)
+	require.NoError(t, exec.Command("git", "-C", repo, "update-ref", "refs/heads/-leading-dash", "HEAD").Run())
+	return repo, git.Repository{Dir: repo}
+}
+
+// TestLeadingDash_ListAndDelete tests that a branch named like an option is listed and deleted
+// instead of being passed to git as a flag.
+func TestLeadingDash_ListAndDelete(t *testing.T) {
+	for name, deleteBranch := range map[string]func(git.Repository, string) error{
+		"delete":       git.Repository.DeleteBranch,
+		"force delete": git.Repository.ForceDeleteBranch,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, r := setupLeadingDashBranch(t)
+
+			branches, err := r.ListBranches()
+			require.NoError(t, err)
+			assert.Contains(t, branches, "-leading-dash")
+
+			require.NoError(t, deleteBranch(r, "-leading-dash"))
+			assert.False(t, r.BranchExists("-leading-dash"))
+		})
+	}
+}
+
+// TestLeadingDash_DeleteMissingBranch tests that deleting a missing branch named like an option
+// fails as not found rather than as an unknown switch.
+func TestLeadingDash_DeleteMissingBranch(t *testing.T) {
+	r := git.Repository{Dir: setupTestRepo(t)}
+
+	err := r.DeleteBranch("-D")
+	assert.ErrorIs(t, err, git.ErrNotFound)
+}
+
+// TestLeadingDash_CheckoutRenameRestore tests the other commands given a branch name.
+func TestLeadingDash_CheckoutRenameRestore(t *testing.T) {
+	_, r := setupLeadingDashBranch(t)
+
+	require.NoError(t, r.CheckoutBranch("-leading-dash"))
+	current, err := r.GetCurrentBranch()
+	require.NoError(t, err)
+	assert.Equal(t, "-leading-dash", current)
+
+	require.NoError(t, r.RenameBranch("-leading-dash", "leading-dash"))
+	assert.True(t, r.BranchExists("leading-dash"))
+
+	// git refuses to create a branch starting with a dash; the error must say so
+	sha, err := r.GetBranchSHA("leading-dash")
+	require.NoError(t, err)
+	err = r.RestoreBranch("-restored", sha)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'-restored' is not a valid branch name")
+}
+
+// TestLeadingDash_VerifyCommit tests that a ref starting with a dash is refused with a precise error,
+// while the full name of such a branch is accepted.
+func TestLeadingDash_VerifyCommit(t *testing.T) {
+	repo, _ := setupLeadingDashBranch(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	err := git.VerifyCommit("-leading-dash")
+	assert.ErrorIs(t, err, git.ErrLooksLikeOption)
+	assert.EqualError(t, err, "'-leading-dash' starts with a dash, so git would take it for an option; "+
+		"name a branch starting with a dash in full, e.g. refs/heads/-leading-dash")
+
+	assert.NoError(t, git.VerifyCommit("refs/heads/-leading-dash"))
+}
+
+// TestLeadingDash_BundlePath tests that a bundle path starting with a dash is written as a file
+// rather than passed as an option.
+func TestLeadingDash_BundlePath(t *testing.T) {
+	repo, _ := setupLeadingDashBranch(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	require.NoError(t, git.CreateBundle("-backup.bundle", []string{"-leading-dash"}))
+	assert.FileExists(t, filepath.Join(repo, "-backup.bundle"))
+}
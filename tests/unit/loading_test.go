@@ -0,0 +1,75 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/ui"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLoadingModel returns a model loading its branches with loader.
+func newLoadingModel(loader ui.BranchLoader) ui.AppModel {
+	return ui.NewLoadingModel(ui.AppModel{
+		Loader:           loader,
+		UnmergedBranches: make(map[string]string),
+		Styles:           ui.NewStyles(ui.ThemeAuto, true),
+	})
+}
+
+// TestLoading_OpensSelectionWhenLoaded tests that the spinner shows until the branches are loaded,
+// and that the loaded list is sorted and filtered like one passed to the model directly.
+func TestLoading_OpensSelectionWhenLoaded(t *testing.T) {
+	m := newLoadingModel(func() (ui.BranchData, error) {
+		return ui.BranchData{
+			CurrentBranch:  "main",
+			Branches:       []string{"zeta", "alpha", "merged"},
+			Selected:       map[string]bool{"alpha": true, "merged": true},
+			BaseBranch:     "main",
+			MergedBranches: map[string]bool{"merged": true},
+		}, nil
+	})
+	m.MergeFilter = ui.MergeFilterUnmerged
+
+	require.Equal(t, ui.StateLoading, m.State)
+	assert.Contains(t, ansi.Strip(m.View()), "Loading branches…")
+
+	m = runCmds(m, m.Init())
+	require.Equal(t, ui.StateSelection, m.State)
+	assert.Equal(t, "main", m.CurrentBranch)
+	assert.Equal(t, []string{"alpha", "merged", "zeta"}, m.Branches)
+	assert.Equal(t, []string{"alpha", "zeta"}, m.VisibleBranches())
+	assert.Equal(t, map[string]bool{"alpha": true}, m.Selected, "Selected branches hidden by the merge filter are dropped")
+	assert.NotContains(t, ansi.Strip(m.View()), "Loading branches…")
+}
+
+// TestLoading_ShowsErrorAndQuits tests that a failed load ends the program with the error as its last screen.
+func TestLoading_ShowsErrorAndQuits(t *testing.T) {
+	m := newLoadingModel(func() (ui.BranchData, error) {
+		return ui.BranchData{}, errors.New("failed to list worktrees")
+	})
+
+	m = runCmds(m, m.Init())
+	assert.EqualError(t, m.LoadErr, "failed to list worktrees")
+	assert.Contains(t, ansi.Strip(m.View()), "Error: failed to list worktrees")
+	assert.False(t, m.WasCancelled())
+}
+
+// TestLoading_QuitsRightAway tests that ctrl+c quits while the branches are still loading.
+func TestLoading_QuitsRightAway(t *testing.T) {
+	m := newLoadingModel(func() (ui.BranchData, error) {
+		return ui.BranchData{Branches: []string{"late"}}, nil
+	})
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	m = next.(ui.AppModel)
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.QuitMsg{}, cmd())
+	assert.False(t, m.WasCancelled(), "Nothing was selected yet, so quitting isn't a cancellation")
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	assert.Equal(t, ui.StateLoading, next.(ui.AppModel).State, "Keys other than quitting are ignored while loading")
+}
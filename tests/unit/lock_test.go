@@ -0,0 +1,161 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockedStderr is what git prints when the lock of the feature branch ref exists
+const lockedStderr = "error: cannot lock ref 'refs/heads/feature': Unable to create '/repo/.git/refs/heads/feature.lock': File exists.\n"
+
+// shortLockRetries makes the retries of deletions that found a lock file wait the given delays for the test
+func shortLockRetries(t *testing.T, delays ...time.Duration) {
+	t.Helper()
+	original := git.LockRetryDelays
+	git.LockRetryDelays = delays
+	t.Cleanup(func() { git.LockRetryDelays = original })
+}
+
+// TestRetryOnLock tests that deletions failing because of a lock file are retried until they succeed or the
+// retries are used up, while other failures are returned right away.
+func TestRetryOnLock(t *testing.T) {
+	shortLockRetries(t, time.Millisecond, time.Millisecond, time.Millisecond)
+	locked := fakeResult{stderr: lockedStderr, code: 1}
+
+	tests := []struct {
+		name      string
+		results   []fakeResult
+		wantCalls int
+		wantLock  bool
+		wantErr   bool
+	}{
+		{"unlocked", []fakeResult{{}}, 1, false, false},
+		{"released after one retry", []fakeResult{locked, {}}, 2, false, false},
+		{"released on the last retry", []fakeResult{locked, locked, locked, {}}, 4, false, false},
+		{"never released", []fakeResult{locked, locked, locked, locked, {}}, 4, true, true},
+		{"not a lock", []fakeResult{{stderr: "error: branch 'feature' not found.\n", code: 1}, {}}, 1, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := newFakeRunner(t, nil)
+			runner.queue("branch -d", tt.results...)
+
+			var waits []string
+			err := git.RetryOnLock(git.WithRunner(context.Background(), runner), "feature", git.DeleteBranchContext,
+				func(lock *git.ErrRefLocked) { waits = append(waits, lock.LockFile) })
+
+			assert.Len(t, runner.calls, tt.wantCalls)
+			assert.Len(t, waits, tt.wantCalls-1, "Every retry should be announced")
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var lock *git.ErrRefLocked
+			require.Error(t, err)
+			assert.Equal(t, tt.wantLock, errors.As(err, &lock))
+		})
+	}
+}
+
+// TestRetryOnLock_Cancelled tests that waiting for a lock stops when the context is done.
+func TestRetryOnLock_Cancelled(t *testing.T) {
+	shortLockRetries(t, time.Hour)
+	runner := newFakeRunner(t, map[string]fakeResult{"branch -D": {stderr: lockedStderr, code: 1}})
+	ctx, cancel := context.WithCancel(git.WithRunner(context.Background(), runner))
+
+	err := git.RetryOnLock(ctx, "feature", git.ForceDeleteBranchContext, func(*git.ErrRefLocked) { cancel() })
+	var lock *git.ErrRefLocked
+	require.ErrorAs(t, err, &lock, "The lock error should be returned")
+	assert.Len(t, runner.calls, 1)
+}
+
+// runCmdsUntil is runCmds stopping as soon as stop holds for the model. Returns the commands not run yet.
+func runCmdsUntil(m ui.AppModel, cmd tea.Cmd, stop func(ui.AppModel) bool) (ui.AppModel, tea.Cmd) {
+	pending := []tea.Cmd{cmd}
+	for len(pending) > 0 && !stop(m) {
+		cmd, pending = pending[0], pending[1:]
+		if cmd == nil {
+			continue
+		}
+
+		switch result := cmd().(type) {
+		case nil, tea.QuitMsg, spinner.TickMsg:
+		case tea.BatchMsg:
+			pending = append(pending, result...)
+		default:
+			next, nextCmd := m.Update(result)
+			m = next.(ui.AppModel)
+			pending = append(pending, nextCmd)
+		}
+	}
+	return m, tea.Batch(pending...)
+}
+
+// lockedDeletionModel returns a model about to delete the feature branch, whose ref is locked, and the lock file
+func lockedDeletionModel(t *testing.T) (ui.AppModel, tea.Cmd, string) {
+	t.Helper()
+	repo := setupTestRepo(t)
+	originalDir, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(originalDir) })
+	require.NoError(t, os.Chdir(repo))
+	require.NoError(t, exec.Command("git", "branch", "feature").Run())
+
+	lock := filepath.Join(repo, ".git", "refs", "heads", "feature.lock")
+	require.NoError(t, os.WriteFile(lock, nil, 0o644))
+
+	m := newTestModel("feature")
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	return next.(ui.AppModel), cmd, lock
+}
+
+// TestDeletion_WaitsForLock tests that the TUI retries deleting a branch whose ref is locked, showing that it
+// waits for the lock, and deletes it once the lock is released.
+func TestDeletion_WaitsForLock(t *testing.T) {
+	shortLockRetries(t, time.Millisecond, time.Millisecond, time.Millisecond)
+	m, cmd, lock := lockedDeletionModel(t)
+
+	m, cmd = runCmdsUntil(m, cmd, func(m ui.AppModel) bool { return m.WaitingForLock != "" })
+	require.Equal(t, ui.StateDeleting, m.State)
+	assert.Equal(t, "feature.lock", filepath.Base(m.WaitingForLock))
+	assert.Contains(t, ansi.Strip(m.View()), "Deleting 1/1: feature… waiting for lock feature.lock…")
+	assert.Empty(t, m.Results, "The branch should not fail while the lock may still be released")
+
+	require.NoError(t, os.Remove(lock))
+	m = runCmds(m, cmd)
+	require.Equal(t, ui.StateDone, m.State)
+	assertResult(t, m, "feature", ui.ResultDeleted)
+	assert.Empty(t, m.WaitingForLock)
+	assert.False(t, git.BranchExists("feature"))
+}
+
+// TestDeletion_LockNotReleased tests that a branch whose ref stays locked fails after the retries, with a hint
+// naming the lock file.
+func TestDeletion_LockNotReleased(t *testing.T) {
+	shortLockRetries(t, time.Millisecond, time.Millisecond, time.Millisecond)
+	m, cmd, _ := lockedDeletionModel(t)
+
+	m = runCmds(m, cmd)
+	require.Equal(t, ui.StateDone, m.State)
+	assertResult(t, m, "feature", ui.ResultFailed)
+	result, _ := m.Result("feature")
+	assert.Equal(t, "feature.lock", filepath.Base(result.LockFile))
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "feature.lock exists — another git process or your IDE may be holding a lock")
+	assert.True(t, git.BranchExists("feature"))
+}
@@ -0,0 +1,46 @@
+package unit
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLog_GitCommands tests that git commands are logged with their arguments, and failures with what git printed.
+func TestLog_GitCommands(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(nil)
+	require.True(t, log.Enabled())
+
+	require.NoError(t, git.ValidateRepository())
+	assert.Contains(t, buf.String(), "git rev-parse --git-dir (")
+
+	buf.Reset()
+	require.Error(t, git.DeleteBranch("missing branch"))
+	output := buf.String()
+	assert.Contains(t, output, `git branch -d -- "missing branch"`, "Arguments with spaces should be quoted")
+	assert.Contains(t, output, "exit status")
+	assert.Contains(t, output, "    error: branch 'missing branch' not found", "What git printed should be logged")
+}
+
+// TestLog_Disabled tests that nothing is logged without an output.
+func TestLog_Disabled(t *testing.T) {
+	log.SetOutput(nil)
+	assert.False(t, log.Enabled())
+
+	// Must not panic without an output
+	log.Command("", []string{"status"}, 0, nil, nil)
+}
@@ -0,0 +1,153 @@
+package unit
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commitRandomFile commits a file of incompressible data on a new branch, then switches back
+func commitRandomFile(t *testing.T, branch string, size int) {
+	t.Helper()
+
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	require.NoError(t, exec.Command("git", "checkout", "-q", "-b", branch).Run())
+	require.NoError(t, os.WriteFile(branch+".bin", data, 0o644))
+	require.NoError(t, exec.Command("git", "add", branch+".bin").Run())
+	require.NoError(t, exec.Command("git", "commit", "-q", "-m", "Add "+branch).Run())
+	require.NoError(t, exec.Command("git", "checkout", "-q", "-").Run())
+}
+
+// TestCompactRepository_PrunesDeletedCommits tests that the commits of a force-deleted branch are gone
+// after compacting, and that the reclaimed space is reported.
+func TestCompactRepository_PrunesDeletedCommits(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	commitRandomFile(t, "bulky", 256*1024)
+	sha, err := git.GetBranchSHA("bulky")
+	require.NoError(t, err)
+	require.NoError(t, git.ForceDeleteBranch("bulky"))
+	require.NoError(t, exec.Command("git", "cat-file", "-e", sha).Run(), "The commit stays reachable from the reflog")
+
+	report, err := git.CompactRepository()
+	require.NoError(t, err)
+	assert.Error(t, exec.Command("git", "cat-file", "-e", sha).Run(), "The commit should be pruned")
+	assert.True(t, report.SizeKnown)
+	assert.Greater(t, report.Reclaimed(), int64(200*1024))
+	assert.Positive(t, report.Duration)
+}
+
+// TestCompaction_OfferedAfterManyForceDeletions tests that g compacts the repository from the done screen
+// once more than CompactionPromptThreshold branches were force deleted, after which undo isn't offered.
+func TestCompaction_OfferedAfterManyForceDeletions(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	var branches []string
+	for i := range ui.CompactionPromptThreshold + 1 {
+		branch := fmt.Sprintf("topic-%02d", i)
+		commitRandomFile(t, branch, 1024)
+		branches = append(branches, branch)
+	}
+
+	m := newTestModel(branches...)
+	m.ForceMode = true
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	m = typeConfirmation(t, m, "delete")
+	require.Equal(t, ui.StateDone, m.State)
+
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "11 branches were force deleted: press g to expire the reflog and run git gc")
+	assert.Contains(t, view, "deleted commits become unrecoverable")
+	assert.Contains(t, view, "g: expire reflog and run git gc")
+
+	m = sendKey(t, m, "g")
+	require.NotNil(t, m.Compaction)
+	assert.False(t, m.Compacting)
+	view = ansi.Strip(m.View())
+	assert.Contains(t, view, "git gc took")
+	assert.Contains(t, view, "reclaimed ~")
+	assert.NotContains(t, view, "press g")
+	assert.NotContains(t, view, "u: undo", "Pruned branches can't be restored")
+	assert.NotContains(t, view, "restore with")
+}
+
+// TestCompaction_NotOfferedForFewForceDeletions tests that g just exits after a small cleanup.
+func TestCompaction_NotOfferedForFewForceDeletions(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	commitRandomFile(t, "topic", 1024)
+
+	m := newTestModel("topic")
+	m.ForceMode = true
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	m = typeConfirmation(t, m, "delete")
+	require.Equal(t, ui.StateDone, m.State)
+	assert.NotContains(t, ansi.Strip(m.View()), "git gc")
+
+	m = sendKey(t, m, "g")
+	assert.Nil(t, m.Compaction)
+	assert.False(t, m.Compacting)
+}
+
+// TestCompaction_ExpireReflogRunsAfterDeletion tests that --expire-reflog is announced on the confirmation
+// screen and compacts the repository once the deletions complete, but not in a dry run.
+func TestCompaction_ExpireReflogRunsAfterDeletion(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "merged").Run()
+
+	dryRun := newTestModel("merged")
+	dryRun.ExpireReflog = true
+	dryRun.DryRun = true
+	dryRun = sendKey(t, dryRun, "a")
+	dryRun = sendKey(t, dryRun, "d")
+	assert.NotContains(t, ansi.Strip(dryRun.View()), "--expire-reflog")
+	dryRun = sendKey(t, dryRun, "y")
+	assert.Nil(t, dryRun.Compaction)
+
+	m := newTestModel("merged")
+	m.ExpireReflog = true
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	assert.Contains(t, ansi.Strip(m.View()), "git gc run afterwards (--expire-reflog): deleted commits become unrecoverable")
+
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateDone, m.State)
+	require.NotNil(t, m.Compaction)
+	assert.Contains(t, ansi.Strip(m.View()), "git gc took")
+}
@@ -0,0 +1,102 @@
+package unit
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNotifier records the summaries it is asked to notify
+type recordingNotifier struct {
+	summaries *[]string
+}
+
+// Notify records the summary
+func (n recordingNotifier) Notify(summary string) error {
+	*n.summaries = append(*n.summaries, summary)
+	return nil
+}
+
+// TestTerminalNotifier tests the bell and the notification sequences written to the terminal.
+func TestTerminalNotifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		notifier ui.TerminalNotifier
+		want     string
+	}{
+		{"OSC 9", ui.TerminalNotifier{}, "\a\x1b]9;gelete: deleted 2 branches\a"},
+		{"OSC 777", ui.TerminalNotifier{OSC777: true}, "\a\x1b]777;notify;gelete;gelete: deleted 2 branches\a"},
+		{"tmux", ui.TerminalNotifier{Tmux: true}, "\a\x1bPtmux;\x1b\x1b]9;gelete: deleted 2 branches\a\x1b\\"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tt.notifier.Out = &out
+			require.NoError(t, tt.notifier.Notify("gelete: deleted 2 branches"))
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, ui.TerminalNotifier{Out: &out}.Notify("a\ab\x1bc"))
+	assert.Equal(t, "\a\x1b]9;a b c\a", out.String(), "Control characters must not end the sequence early")
+}
+
+// TestNewTerminalNotifier tests that the sequence is picked from the environment.
+func TestNewTerminalNotifier(t *testing.T) {
+	env := map[string]string{"TERM": "rxvt-unicode-256color", "TMUX": "/tmp/tmux-1000/default,1,0"}
+	notifier := ui.NewTerminalNotifier(os.Stdout, func(key string) string { return env[key] })
+	assert.Equal(t, ui.TerminalNotifier{Out: os.Stdout, OSC777: true, Tmux: true}, notifier)
+
+	notifier = ui.NewTerminalNotifier(os.Stdout, func(string) string { return "" })
+	assert.Equal(t, ui.TerminalNotifier{Out: os.Stdout}, notifier)
+}
+
+// TestDeletion_NotifiesLongRuns tests that only deletion runs taking longer than 5 seconds are notified.
+func TestDeletion_NotifiesLongRuns(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	tests := []struct {
+		name string
+		step time.Duration
+		want []string
+	}{
+		{"long run", 6 * time.Second, []string{"gelete: deleted 2 branches"}},
+		{"short run", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec.Command("git", "branch", "feature-a").Run()
+			exec.Command("git", "branch", "feature-b").Run()
+
+			var summaries []string
+			now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			m := newTestModel("feature-a", "feature-b")
+			m.Notifier = recordingNotifier{summaries: &summaries}
+			// Each reading of the clock is step later than the one before
+			m.Clock = func() time.Time {
+				now = now.Add(tt.step)
+				return now
+			}
+
+			m = sendKey(t, m, "a")
+			m = sendKey(t, m, "d")
+			m = sendKey(t, m, "y")
+			require.Equal(t, ui.StateDone, m.State)
+			assert.Equal(t, tt.want, summaries)
+			assert.True(t, m.DeletionStarted.IsZero(), "The start time should be reset for the next run")
+		})
+	}
+}
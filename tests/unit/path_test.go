@@ -0,0 +1,59 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEqualPaths tests comparing paths as git reports them with paths from the file system,
+// with and without the case folding of Windows.
+func TestEqualPaths(t *testing.T) {
+	dir := t.TempDir()
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Symlink(dir, link); err != nil {
+		// Creating symlinks takes a privilege on Windows; the link then just names the directory itself
+		link = dir
+	}
+
+	tests := []struct {
+		name            string
+		a, b            string
+		caseInsensitive bool
+		want            bool
+	}{
+		{name: "identical", a: dir, b: dir, want: true},
+		{name: "forward slashes from git", a: filepath.ToSlash(dir), b: dir, want: true},
+		{name: "trailing separator", a: dir + string(filepath.Separator), b: dir, want: true},
+		{name: "symlink", a: link, b: dir, want: true},
+		{name: "missing paths are cleaned", a: "/no/such/../such/wt", b: filepath.FromSlash("/no/such/wt"), want: true},
+		{name: "case differs", a: "/Repo/WT", b: "/repo/wt", want: false},
+		{name: "case differs on Windows", a: "C:/Repo/WT", b: "c:/repo/wt", caseInsensitive: true, want: true},
+		{name: "different paths on Windows", a: "C:/repo/a", b: "C:/repo/b", caseInsensitive: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, git.EqualPaths(tt.a, tt.b, tt.caseInsensitive))
+		})
+	}
+}
+
+// TestGetTopLevel_IsCanonical tests that the repository root git reports compares equal
+// to the directory the repository was created in.
+func TestGetTopLevel_IsCanonical(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	root, err := git.GetTopLevel()
+	require.NoError(t, err)
+	assert.True(t, git.SamePath(root, repo), "%s should be the same path as %s", root, repo)
+	assert.Equal(t, git.CanonicalPath(root), root)
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSamePath_IgnoresCaseOnWindows tests that worktree paths match whatever their case and slashes on Windows.
+func TestSamePath_IgnoresCaseOnWindows(t *testing.T) {
+	assert.True(t, git.SamePath(`C:\Users\dev\repo\wt`, "c:/users/DEV/repo/wt"))
+	assert.False(t, git.SamePath(`C:\Users\dev\repo\wt`, `C:\Users\dev\repo\other`))
+}
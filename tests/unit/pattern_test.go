@@ -0,0 +1,100 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFilterBranches tests selecting branches by glob pattern.
+func TestFilterBranches(t *testing.T) {
+	branches := []string{"spike/a", "spike/deep/b", "tmp/1", "tmp/22", "tmp", "feature-x", "develop"}
+
+	tests := []struct {
+		name          string
+		patterns      []string
+		wantMatched   []string
+		wantUnmatched []string
+	}{
+		{"star stays within one level", []string{"tmp/*"}, []string{"tmp/1", "tmp/22"}, nil},
+		{"question mark matches one character", []string{"tmp/?"}, []string{"tmp/1"}, nil},
+		{"nested slashes need their own star", []string{"spike/*/*"}, []string{"spike/deep/b"}, nil},
+		{"multiple patterns keep list order", []string{"tmp/*", "spike/*"}, []string{"spike/a", "tmp/1", "tmp/22"}, nil},
+		{"overlapping patterns match once", []string{"tmp/*", "tmp/1"}, []string{"tmp/1", "tmp/22"}, nil},
+		{"exact name", []string{"feature-x"}, []string{"feature-x"}, nil},
+		{"unmatched patterns are reported", []string{"tmp/*", "wip/*"}, []string{"tmp/1", "tmp/22"}, []string{"wip/*"}},
+		{"protected branches never match", []string{"dev*"}, nil, []string{"dev*"}},
+		{"match everything except protected", []string{"*"}, []string{"tmp", "feature-x"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, unmatched := git.FilterBranches(branches, tt.patterns)
+			assert.Equal(t, tt.wantMatched, matched)
+			assert.Equal(t, tt.wantUnmatched, unmatched)
+		})
+	}
+}
+
+// TestValidatePattern tests that empty and malformed patterns are refused.
+func TestValidatePattern(t *testing.T) {
+	assert.NoError(t, git.ValidatePattern("tmp/*"))
+	assert.NoError(t, git.ValidatePattern("release/[0-9]*"))
+	assert.Error(t, git.ValidatePattern(""))
+	assert.Error(t, git.ValidatePattern("  "))
+	assert.ErrorContains(t, git.ValidatePattern("release/[0-9"), "invalid pattern 'release/[0-9'")
+}
+
+// TestPatternSelection tests selecting and deselecting the visible branches matching a pattern typed after *.
+func TestPatternSelection(t *testing.T) {
+	m := newTestModel("release/1.0", "tmp/a", "tmp/b", "tmp/nested/c", "topic")
+	assert.Contains(t, m.View(), "*: select by pattern")
+
+	m = sendKey(t, m, "*")
+	require.True(t, m.SelectingPattern)
+	m = typeText(t, m, "tmp/*")
+	m = sendKey(t, m, "enter")
+	assert.False(t, m.SelectingPattern)
+	assert.Equal(t, map[string]bool{"tmp/a": true, "tmp/b": true}, m.Selected, "* doesn't cross /, like --pattern")
+	assert.Contains(t, ansi.Strip(m.View()), "selected 2 branches matching tmp/*")
+
+	m = sendKey(t, m, "*")
+	m = typeText(t, m, "!tmp/a")
+	m = sendKey(t, m, "enter")
+	assert.False(t, m.Selected["tmp/a"])
+	assert.True(t, m.Selected["tmp/b"])
+	assert.Contains(t, ansi.Strip(m.View()), "deselected 1 branch matching tmp/a")
+
+	// Only visible branches are affected
+	m = sendKey(t, m, "/")
+	m = typeText(t, m, "release")
+	m = sendKey(t, m, "enter")
+	m = sendKey(t, m, "*")
+	m = typeText(t, m, "*")
+	m = sendKey(t, m, "enter")
+	assert.Contains(t, ansi.Strip(m.View()), "selected 0 branches matching *")
+	assert.False(t, m.Selected["topic"])
+}
+
+// TestPatternSelection_InvalidAndCancelled tests that an invalid pattern keeps the input open with an error,
+// and that esc closes it without changing the selection.
+func TestPatternSelection_InvalidAndCancelled(t *testing.T) {
+	m := newTestModel("tmp/a", "topic")
+
+	m = sendKey(t, m, "*")
+	m = typeText(t, m, "tmp/[")
+	m = sendKey(t, m, "enter")
+	assert.True(t, m.SelectingPattern, "The input should stay open to correct the pattern")
+	assert.Contains(t, ansi.Strip(m.View()), "Error: invalid pattern 'tmp/['")
+	assert.Empty(t, m.Selected)
+
+	m = sendKey(t, m, "backspace")
+	assert.Empty(t, m.ErrorMsg, "Editing the pattern clears the error")
+	m = sendKey(t, m, "esc")
+	assert.False(t, m.SelectingPattern)
+	assert.Empty(t, m.Selected)
+	assert.NotContains(t, ansi.Strip(m.View()), "Select matching")
+}
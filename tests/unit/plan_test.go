@@ -0,0 +1,87 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/Kdaito/gelete/internal/plan"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlan_WriteRead tests that a written plan reads back unchanged
+func TestPlan_WriteRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cleanup.yaml")
+	want := plan.Plan{
+		Version:    plan.Version,
+		Repository: "/work/repo",
+		Base:       "main",
+		Created:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Entries: []plan.Entry{
+			{Branch: "feature/done", SHA: "1111111111111111111111111111111111111111", Merged: true, Actions: []plan.Action{plan.Delete}},
+			{Branch: "spike", SHA: "2222222222222222222222222222222222222222", Worktree: "/work/spike",
+				Actions: []plan.Action{plan.RemoveWorktree, plan.ForceDelete}},
+		},
+	}
+
+	require.NoError(t, plan.Write(path, want))
+	got, err := plan.Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.True(t, got.Entries[1].Has(plan.RemoveWorktree))
+	assert.False(t, got.Entries[0].Has(plan.RemoveWorktree))
+}
+
+// TestPlan_ReadRejectsInvalid tests that plans that don't say plainly what to delete, or how, are refused
+func TestPlan_ReadRejectsInvalid(t *testing.T) {
+	const entry = "  - branch: spike\n    sha: 2222222\n"
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "empty", content: "", want: "the file is empty"},
+		{name: "other version", content: "version: 2\nbranches: []\n", want: "unsupported version 2"},
+		{name: "unknown key", content: "version: 1\nbranchs: []\n", want: "branchs"},
+		{name: "no deletion", content: "version: 1\nbranches:\n" + entry + "    actions: [remove-worktree]\n", want: "exactly one of the actions"},
+		{name: "two deletions", content: "version: 1\nbranches:\n" + entry + "    actions: [delete, force-delete]\n", want: "exactly one of the actions"},
+		{name: "unknown action", content: "version: 1\nbranches:\n" + entry + "    actions: [delete, push]\n", want: "unknown action 'push'"},
+		{name: "no sha", content: "version: 1\nbranches:\n  - branch: spike\n    actions: [delete]\n", want: "has no sha"},
+		{name: "listed twice", content: "version: 1\nbranches:\n" + entry + "    actions: [delete]\n" + entry + "    actions: [delete]\n",
+			want: "listed twice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cleanup.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o644))
+
+			_, err := plan.Read(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+// TestPlanMode_ConfirmingQuitsWithoutDeleting tests that confirming in plan mode quits with the selection
+// planned, without typing the confirmation of a force deletion and without counting as a cancellation
+func TestPlanMode_ConfirmingQuitsWithoutDeleting(t *testing.T) {
+	m := newTestModel("feature-a", "feature-b", "feature-c")
+	m.PlanPath = "cleanup.yaml"
+	m.ForceMode = true
+	m.Selected["feature-c"] = true
+	m.Selected["feature-a"] = true
+	m.State = ui.StateConfirmation
+	assert.Contains(t, m.View(), i18n.English.T(i18n.PlanNote, "cleanup.yaml"))
+
+	m = sendKey(t, m, "y")
+	assert.True(t, m.Planned)
+	assert.False(t, m.TypingConfirmation, "A plan deletes nothing, so it takes no typed confirmation")
+	assert.Empty(t, m.Results)
+	assert.Equal(t, []string{"feature-a", "feature-c"}, m.PlannedBranches())
+	assert.False(t, m.WasCancelled())
+	assert.Empty(t, m.View())
+}
@@ -0,0 +1,78 @@
+package unit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/pr"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGH puts a gh script printing output on PATH instead of the real gh CLI.
+func fakeGH(t *testing.T, output string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	script := "#!/bin/sh\ncat <<'JSON'\n" + output + "\nJSON\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gh"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// TestGitHub_OpenPRs tests that open pull requests are keyed by their head branch, keeping the most recent one
+func TestGitHub_OpenPRs(t *testing.T) {
+	fakeGH(t, `[
+		{"headRefName": "feature/login", "number": 42, "state": "OPEN"},
+		{"headRefName": "fix/typo", "number": 7, "state": "OPEN"},
+		{"headRefName": "fix/typo", "number": 3, "state": "OPEN"}
+	]`)
+
+	prs, err := pr.GitHub{Dir: t.TempDir()}.OpenPRs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]pr.Info{
+		"feature/login": {Number: 42, State: "OPEN"},
+		"fix/typo":      {Number: 7, State: "OPEN"},
+	}, prs)
+	assert.Equal(t, "PR #42 (open)", prs["feature/login"].String())
+}
+
+// TestGitHub_Unavailable tests that the lookup reports ErrUnavailable when gh isn't installed
+func TestGitHub_Unavailable(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	_, err := pr.GitHub{}.OpenPRs(context.Background())
+	assert.ErrorIs(t, err, pr.ErrUnavailable)
+}
+
+// stubProvider is a pr.Provider returning fixed results
+type stubProvider struct {
+	prs map[string]pr.Info
+	err error
+}
+
+func (p stubProvider) OpenPRs(context.Context) (map[string]pr.Info, error) {
+	return p.prs, p.err
+}
+
+// TestPullRequests_AnnotateBranches tests that pull requests looked up in the background annotate the list
+// and the confirmation, and that a failed lookup leaves the list unannotated
+func TestPullRequests_AnnotateBranches(t *testing.T) {
+	m := newTestModel("feature/login", "spike")
+	m.PRProvider = stubProvider{err: pr.ErrUnavailable}
+	m = runCmds(m, m.Init())
+	assert.NotContains(t, ansi.Strip(m.View()), "PR #")
+
+	m.PRProvider = stubProvider{prs: map[string]pr.Info{"feature/login": {Number: 42, State: "OPEN"}}}
+	m = runCmds(m, m.Init())
+	assert.Regexp(t, `feature/login.*PR #42 \(open\)`, ansi.Strip(m.View()))
+	assert.NotRegexp(t, `spike.*PR #`, ansi.Strip(m.View()))
+
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	require.Equal(t, ui.StateConfirmation, m.State)
+	assert.Contains(t, ansi.Strip(m.View()), "⚠ PR #42 (open): it may still be under review")
+}
@@ -0,0 +1,96 @@
+package unit
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMatchesAnyPattern tests protected branch pattern matching, including glob edge cases.
+func TestMatchesAnyPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		branch   string
+		patterns []string
+		want     bool
+	}{
+		{"exact name", "develop", []string{"main", "develop"}, true},
+		{"no match", "feature-x", []string{"main", "develop"}, false},
+		{"no patterns", "main", nil, false},
+		{"prefix is not a match", "main-backup", []string{"main"}, false},
+		{"glob matches one level", "release/1.0", []string{"release/*"}, true},
+		{"glob does not match the bare prefix", "release", []string{"release/*"}, false},
+		{"glob does not cross slashes", "release/1.0/hotfix", []string{"release/*"}, false},
+		{"nested glob", "release/1.0/hotfix", []string{"release/*/*"}, true},
+		{"suffix glob", "wip-login", []string{"wip-*"}, true},
+		{"single character glob", "v1", []string{"v?"}, true},
+		{"character class", "env/prod", []string{"env/[ps]*"}, true},
+		{"surrounding whitespace is ignored", "staging", []string{"  staging "}, true},
+		{"empty pattern matches nothing", "", []string{""}, false},
+		{"malformed pattern matches literally", "[weird", []string{"[weird"}, true},
+		{"malformed pattern does not glob", "weird", []string{"[weird"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, git.MatchesAnyPattern(tt.branch, tt.patterns))
+		})
+	}
+}
+
+// TestListBranches_ExcludesProtected tests that protected branches are not listed as deletable.
+func TestListBranches_ExcludesProtected(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "checkout", "-b", "work").Run()
+	for _, branch := range []string{"main", "master", "develop", "release/1.0", "feature-a"} {
+		exec.Command("git", "branch", branch).Run()
+	}
+
+	defer git.SetProtectedPatterns(git.DefaultProtectedBranches)
+
+	branches, err := git.ListBranches()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feature-a", "release/1.0"}, branches, "Default protected branches should be excluded")
+
+	git.SetProtectedPatterns(append(git.DefaultProtectedBranches, "release/*"))
+	infos, err := git.ListBranchesWithInfo()
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, "feature-a", infos[0].Name)
+
+	git.SetProtectedPatterns(nil)
+	branches, err = git.ListBranches()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"develop", "feature-a", "main", "master", "release/1.0"}, branches, "Disabling protection should list every branch")
+}
+
+// TestConfiguredProtectedPatterns tests reading the multi-valued gelete.protect config key.
+func TestConfiguredProtectedPatterns(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	patterns, err := git.ConfiguredProtectedPatterns()
+	require.NoError(t, err)
+	assert.Empty(t, patterns, "Unset config key should yield no patterns")
+
+	exec.Command("git", "config", "--add", "gelete.protect", "release/*").Run()
+	exec.Command("git", "config", "--add", "gelete.protect", "staging").Run()
+
+	patterns, err = git.ConfiguredProtectedPatterns()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"release/*", "staging"}, patterns)
+}
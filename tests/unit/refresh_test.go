@@ -0,0 +1,46 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRefresh_ReconcilesSelection tests that r lists the branches again, dropping the branches deleted
+// meanwhile from the list and the selection, and keeping the selection and the cursor of the others.
+func TestRefresh_ReconcilesSelection(t *testing.T) {
+	m := newTestModel("alpha", "beta", "gamma")
+	m.Loader = func() (ui.BranchData, error) {
+		return ui.BranchData{Branches: []string{"beta", "delta", "gamma"}}, nil
+	}
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "j")
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "j")
+	require.Equal(t, map[string]bool{"alpha": true, "beta": true}, m.Selected)
+
+	m = sendKey(t, m, "r")
+	assert.False(t, m.Refreshing)
+	assert.Equal(t, []string{"beta", "delta", "gamma"}, m.Branches)
+	assert.Equal(t, map[string]bool{"beta": true}, m.Selected, "Branches deleted meanwhile should be deselected")
+	assert.Equal(t, []string{"beta"}, m.SelectionOrder)
+	assert.Equal(t, 2, m.CursorIndex, "The cursor should stay on gamma")
+	assert.Equal(t, "Refreshed: 1 branch(es) gone, 1 new", m.SuccessMsg)
+	assert.Contains(t, m.View(), "1/3 selected")
+}
+
+// TestRefresh_Errors tests that a failed refresh keeps the list, and that lists without a loader can't be refreshed.
+func TestRefresh_Errors(t *testing.T) {
+	m := newTestModel("alpha")
+	m = sendKey(t, m, "r")
+	assert.Equal(t, "refreshing is not available for this list", m.ErrorMsg)
+
+	m.Loader = func() (ui.BranchData, error) { return ui.BranchData{}, errors.New("git exploded") }
+	m = sendKey(t, m, "r")
+	assert.Equal(t, []string{"alpha"}, m.Branches)
+	assert.Equal(t, "failed to refresh branches: git exploded", m.ErrorMsg)
+	assert.Contains(t, m.View(), "r: refresh")
+}
@@ -0,0 +1,172 @@
+package unit
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/Kdaito/gelete/internal/ui"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseRemotePrune tests parsing the output of git remote prune --dry-run for one and several remotes.
+func TestParseRemotePrune(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []git.StaleTrackingRef
+	}{
+		{
+			name:   "one remote",
+			output: "Pruning origin\nURL: git@github.com:owner/repo.git\n * [would prune] origin/feature-x\n * [would prune] origin/fix/y\n",
+			want: []git.StaleTrackingRef{
+				{Remote: "origin", Ref: "origin/feature-x"},
+				{Remote: "origin", Ref: "origin/fix/y"},
+			},
+		},
+		{
+			name: "several remotes",
+			output: "Pruning origin\nURL: /srv/origin.git\n * [would prune] origin/a\n" +
+				"Pruning upstream\nURL: https://example.com/upstream.git\n * [would prune] upstream/b\n * [would prune] upstream/c\n",
+			want: []git.StaleTrackingRef{
+				{Remote: "origin", Ref: "origin/a"},
+				{Remote: "upstream", Ref: "upstream/b"},
+				{Remote: "upstream", Ref: "upstream/c"},
+			},
+		},
+		{
+			name:   "remote with nothing to prune",
+			output: "Pruning origin\nURL: /srv/origin.git\nPruning upstream\nURL: /srv/upstream.git\n * [would prune] upstream/b\n",
+			want:   []git.StaleTrackingRef{{Remote: "upstream", Ref: "upstream/b"}},
+		},
+		{
+			name:   "CRLF line endings",
+			output: "Pruning origin\r\nURL: /srv/origin.git\r\n * [would prune] origin/a\r\n",
+			want:   []git.StaleTrackingRef{{Remote: "origin", Ref: "origin/a"}},
+		},
+		{
+			name:   "empty",
+			output: "",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, git.ParseRemotePrune(tt.output))
+		})
+	}
+}
+
+// TestListStaleTrackingRefs tests finding the refs of branches deleted on the remote, and deleting one of them.
+func TestListStaleTrackingRefs(t *testing.T) {
+	repo, remote := setupRepoWithRemote(t, "gone-a", "gone-b", "kept")
+	for _, branch := range []string{"gone-a", "gone-b"} {
+		require.NoError(t, exec.Command("git", "-C", remote, "branch", "-D", branch).Run())
+	}
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	remotes, err := git.ListRemotes()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"origin"}, remotes)
+
+	refs, err := git.ListStaleTrackingRefs("origin")
+	require.NoError(t, err)
+	assert.Equal(t, []git.StaleTrackingRef{
+		{Remote: "origin", Ref: "origin/gone-a"},
+		{Remote: "origin", Ref: "origin/gone-b"},
+	}, refs)
+
+	require.NoError(t, git.DeleteRemoteTrackingRef("origin/gone-a"))
+	refs, err = git.ListStaleTrackingRefs("origin")
+	require.NoError(t, err)
+	assert.Equal(t, []git.StaleTrackingRef{{Remote: "origin", Ref: "origin/gone-b"}}, refs)
+
+	assert.Error(t, git.DeleteRemoteTrackingRef("origin/gone-a"), "Deleting a ref twice should fail")
+	_, err = git.ListStaleTrackingRefs("nosuch")
+	assert.Error(t, err, "An unknown remote should fail")
+}
+
+// TestTrackingRefModel_PrunesSelectedRefs tests that only the refs still selected are pruned.
+func TestTrackingRefModel_PrunesSelectedRefs(t *testing.T) {
+	repo, remote := setupRepoWithRemote(t, "gone-a", "gone-b")
+	for _, branch := range []string{"gone-a", "gone-b"} {
+		require.NoError(t, exec.Command("git", "-C", remote, "branch", "-D", branch).Run())
+	}
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	refs, err := git.ListStaleTrackingRefs("origin")
+	require.NoError(t, err)
+	m := ui.TrackingRefModel{
+		Refs:     refs,
+		Selected: map[string]bool{"origin/gone-a": true, "origin/gone-b": true},
+		Styles:   ui.NewStyles(ui.ThemeAuto, false),
+	}
+	assert.Contains(t, m.View(), "2/2 selected")
+
+	m = sendTrackingRefKey(m, "j")
+	m = sendTrackingRefKey(m, " ")
+	m = sendTrackingRefKey(m, "d")
+	require.Equal(t, ui.TrackingRefStateConfirmation, m.State)
+	assert.NotContains(t, m.View(), "origin/gone-b")
+
+	m = sendTrackingRefKey(m, "y")
+	require.Equal(t, ui.TrackingRefStateDone, m.State)
+	assert.False(t, m.HasFailures(), "Unexpected failures: %v", m.Failures)
+	assert.Equal(t, []string{"origin/gone-a"}, m.Pruned)
+	assert.Contains(t, m.View(), "1 ref pruned • 0 failed")
+
+	remaining, err := git.ListStaleTrackingRefs("origin")
+	require.NoError(t, err)
+	assert.Equal(t, []git.StaleTrackingRef{{Remote: "origin", Ref: "origin/gone-b"}}, remaining)
+}
+
+// TestTrackingRefModel_Japanese tests that the ref pruning screens are rendered in the language of the model.
+func TestTrackingRefModel_Japanese(t *testing.T) {
+	repo, remote := setupRepoWithRemote(t, "gone-a")
+	require.NoError(t, exec.Command("git", "-C", remote, "branch", "-D", "gone-a").Run())
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	refs, err := git.ListStaleTrackingRefs("origin")
+	require.NoError(t, err)
+	m := ui.TrackingRefModel{
+		Refs:     refs,
+		Selected: map[string]bool{"origin/gone-a": true},
+		Styles:   ui.NewStyles(ui.ThemeAuto, false),
+		Lang:     i18n.Japanese,
+	}
+	assert.Contains(t, m.View(), "origin: リモートで削除されたブランチ")
+
+	m = sendTrackingRefKey(m, "d")
+	assert.Contains(t, m.View(), "これらのリモート追跡参照を削除してもよろしいですか?")
+	assert.Contains(t, m.View(), "合計: 1 件の参照。")
+
+	m = sendTrackingRefKey(m, "y")
+	require.Equal(t, ui.TrackingRefStateDone, m.State)
+	assert.Contains(t, m.View(), "✓ origin/gone-a: 削除しました")
+	assert.Contains(t, m.View(), "1 件の参照を削除 • 失敗 0 件")
+}
+
+// sendTrackingRefKey feeds a key press to the ref pruning model and runs any resulting command to completion.
+func sendTrackingRefKey(m ui.TrackingRefModel, key string) ui.TrackingRefModel {
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	if key == " " {
+		msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(key)}
+	}
+
+	next, cmd := m.Update(msg)
+	return runCmds(next.(ui.TrackingRefModel), cmd)
+}
@@ -0,0 +1,337 @@
+package unit
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupRepoWithRemote creates a test repository with a bare "origin" remote
+// and pushes the given branches to it. Returns the repository and remote paths.
+func setupRepoWithRemote(t *testing.T, branches ...string) (string, string) {
+	t.Helper()
+
+	repo := setupTestRepo(t)
+	remote := t.TempDir()
+
+	require.NoError(t, exec.Command("git", "init", "--bare", remote).Run())
+	require.NoError(t, exec.Command("git", "-C", repo, "remote", "add", "origin", remote).Run())
+	require.NoError(t, exec.Command("git", "-C", repo, "push", "-q", "origin", "HEAD").Run())
+
+	for _, branch := range branches {
+		exec.Command("git", "-C", repo, "branch", branch).Run()
+		require.NoError(t, exec.Command("git", "-C", repo, "push", "-q", "origin", branch).Run())
+	}
+
+	// Create origin/HEAD, which must never be offered for deletion
+	exec.Command("git", "-C", repo, "remote", "set-head", "origin", "--auto").Run()
+
+	return repo, remote
+}
+
+// TestListRemoteBranches_ExcludesHEAD tests listing remote-tracking branches without origin/HEAD.
+func TestListRemoteBranches_ExcludesHEAD(t *testing.T) {
+	repo, _ := setupRepoWithRemote(t, "feature-a", "feature/b")
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	currentBranch, err := git.GetCurrentBranch()
+	require.NoError(t, err)
+
+	branches, err := git.ListRemoteBranches()
+	assert.NoError(t, err, "ListRemoteBranches should succeed")
+	assert.Equal(t, []string{"origin/feature-a", "origin/feature/b", "origin/" + currentBranch}, branches)
+	for _, branch := range branches {
+		assert.False(t, strings.HasSuffix(branch, "HEAD"), "origin/HEAD should be excluded")
+	}
+}
+
+// TestListRemoteBranches_NoRemotes tests listing remote-tracking branches when there are no remotes.
+func TestListRemoteBranches_NoRemotes(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	branches, err := git.ListRemoteBranches()
+	assert.NoError(t, err)
+	assert.Empty(t, branches)
+}
+
+// TestSplitRemoteBranch tests splitting remote-tracking branch names.
+func TestSplitRemoteBranch(t *testing.T) {
+	remote, branch := git.SplitRemoteBranch("origin/feature/login", []string{"origin"})
+	assert.Equal(t, "origin", remote)
+	assert.Equal(t, "feature/login", branch)
+
+	// A remote with a slash in its name wins over a shorter remote it starts with
+	remote, branch = git.SplitRemoteBranch("team/origin/feature/login", []string{"team", "team/origin"})
+	assert.Equal(t, "team/origin", remote)
+	assert.Equal(t, "feature/login", branch)
+
+	// Without a known remote, the name is split at its first slash
+	remote, branch = git.SplitRemoteBranch("upstream/fix", nil)
+	assert.Equal(t, "upstream", remote)
+	assert.Equal(t, "fix", branch)
+}
+
+// TestDeleteRemoteBranch_RemoteWithSlash tests deleting the branch of a remote whose name contains a slash.
+func TestDeleteRemoteBranch_RemoteWithSlash(t *testing.T) {
+	repo := setupTestRepo(t)
+	remote := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", "--bare", remote).Run())
+	require.NoError(t, exec.Command("git", "-C", repo, "remote", "add", "team/origin", remote).Run())
+	require.NoError(t, exec.Command("git", "-C", repo, "branch", "feature-a").Run())
+	require.NoError(t, exec.Command("git", "-C", repo, "push", "-q", "team/origin", "feature-a").Run())
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	remoteName, branch, err := git.ResolveRemoteBranch("team/origin/feature-a")
+	require.NoError(t, err)
+	assert.Equal(t, "team/origin", remoteName)
+	assert.Equal(t, "feature-a", branch)
+
+	require.NoError(t, git.DeleteRemoteBranch(remoteName, branch))
+	err = exec.Command("git", "-C", remote, "rev-parse", "--verify", "refs/heads/feature-a").Run()
+	assert.Error(t, err, "Branch should be deleted on the remote")
+}
+
+// TestDeleteRemoteBranch_Success tests deleting a branch on the remote and its tracking ref.
+func TestDeleteRemoteBranch_Success(t *testing.T) {
+	repo, remote := setupRepoWithRemote(t, "feature-a")
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	err = git.DeleteRemoteBranch("origin", "feature-a")
+	assert.NoError(t, err, "DeleteRemoteBranch should succeed")
+
+	// Remote branch should be gone
+	err = exec.Command("git", "-C", remote, "rev-parse", "--verify", "refs/heads/feature-a").Run()
+	assert.Error(t, err, "Branch should be deleted on the remote")
+
+	// Remote-tracking ref should be gone
+	branches, _ := git.ListRemoteBranches()
+	assert.NotContains(t, branches, "origin/feature-a")
+
+	// Local branch should be untouched
+	localBranches, _ := git.ListBranches()
+	assert.Contains(t, localBranches, "feature-a")
+}
+
+// TestDeleteRemoteBranch_NonExistent tests deleting a branch that doesn't exist on the remote.
+func TestDeleteRemoteBranch_NonExistent(t *testing.T) {
+	repo, _ := setupRepoWithRemote(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	err = git.DeleteRemoteBranch("origin", "does-not-exist")
+	assert.Error(t, err, "DeleteRemoteBranch should fail for non-existent remote branch")
+}
+
+// TestListGoneBranches tests detecting branches whose upstream was deleted on the remote.
+func TestListGoneBranches(t *testing.T) {
+	repo, remote := setupRepoWithRemote(t, "merged-pr", "open-pr")
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "--set-upstream-to=origin/merged-pr", "merged-pr").Run()
+	exec.Command("git", "branch", "--set-upstream-to=origin/open-pr", "open-pr").Run()
+	exec.Command("git", "branch", "local-only").Run()
+
+	branches, err := git.ListGoneBranches()
+	require.NoError(t, err)
+	assert.Empty(t, branches, "No upstream is gone yet")
+
+	// Delete the branch on the remote and prune the tracking ref, as after merging a PR
+	require.NoError(t, exec.Command("git", "-C", remote, "branch", "-D", "merged-pr").Run())
+	require.NoError(t, exec.Command("git", "fetch", "-q", "--prune").Run())
+
+	branches, err = git.ListGoneBranches()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"merged-pr"}, branches, "Branches without an upstream must not be reported as gone")
+}
+
+// TestListUpstreams tests detecting the remote branch each local branch tracks.
+func TestListUpstreams(t *testing.T) {
+	repo, _ := setupRepoWithRemote(t, "feature-a")
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "--set-upstream-to=origin/feature-a", "feature-a").Run()
+	exec.Command("git", "branch", "local-only").Run()
+	exec.Command("git", "branch", "--track", "tracks-local", "local-only").Run()
+
+	upstreams, err := git.ListUpstreams()
+	require.NoError(t, err)
+	assert.Equal(t, git.Upstream{Remote: "origin", Branch: "feature-a"}, upstreams["feature-a"])
+	assert.Equal(t, "origin/feature-a", upstreams["feature-a"].String())
+	assert.NotContains(t, upstreams, "local-only", "Branches without an upstream should be omitted")
+	assert.NotContains(t, upstreams, "tracks-local", "Branches tracking a local branch should be omitted")
+}
+
+// TestListBranchesWithInfo_Upstreams tests that branch info carries the tip, upstream and gone status.
+func TestListBranchesWithInfo_Upstreams(t *testing.T) {
+	repo, remote := setupRepoWithRemote(t, "merged-pr", "open-pr")
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "--set-upstream-to=origin/merged-pr", "merged-pr").Run()
+	exec.Command("git", "branch", "--set-upstream-to=origin/open-pr", "open-pr").Run()
+	exec.Command("git", "branch", "local-only").Run()
+	require.NoError(t, exec.Command("git", "-C", remote, "branch", "-D", "merged-pr").Run())
+	require.NoError(t, exec.Command("git", "fetch", "-q", "--prune").Run())
+	exec.Command("git", "checkout", "-q", "open-pr").Run()
+	exec.Command("git", "commit", "-q", "--allow-empty", "-m", "Unpushed").Run()
+	exec.Command("git", "checkout", "-q", "-").Run()
+
+	infos, err := git.ListBranchesWithInfo()
+	require.NoError(t, err)
+	require.Len(t, infos, 3)
+
+	byName := make(map[string]git.BranchInfo)
+	for _, info := range infos {
+		byName[info.Name] = info
+	}
+	assert.True(t, byName["merged-pr"].Gone)
+	assert.Equal(t, git.Upstream{Remote: "origin", Branch: "merged-pr"}, byName["merged-pr"].Upstream)
+	assert.False(t, byName["open-pr"].Gone)
+	assert.Equal(t, git.Upstream{Remote: "origin", Branch: "open-pr"}, byName["open-pr"].Upstream)
+	assert.Equal(t, git.UpstreamTrack{Ahead: 1}, byName["open-pr"].Track)
+	assert.Equal(t, git.UpstreamTrack{Gone: true}, byName["merged-pr"].Track)
+	assert.Empty(t, byName["local-only"].Upstream.Remote)
+	assert.Equal(t, git.UpstreamTrack{}, byName["local-only"].Track)
+
+	sha, err := exec.Command("git", "rev-parse", "open-pr").Output()
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(string(sha)), byName["open-pr"].SHA)
+}
+
+// TestParseUpstreamTrack tests parsing the sync state reported by %(upstream:track).
+func TestParseUpstreamTrack(t *testing.T) {
+	tests := []struct {
+		track string
+		want  git.UpstreamTrack
+	}{
+		{"", git.UpstreamTrack{}},
+		{"[gone]", git.UpstreamTrack{Gone: true}},
+		{"[ahead 2]", git.UpstreamTrack{Ahead: 2}},
+		{"[behind 1]", git.UpstreamTrack{Behind: 1}},
+		{"[ahead 2, behind 1]", git.UpstreamTrack{Ahead: 2, Behind: 1}},
+		{"[ahead 120, behind 3400]", git.UpstreamTrack{Ahead: 120, Behind: 3400}},
+		{" [ahead 2] ", git.UpstreamTrack{Ahead: 2}},
+		{"[diverged]", git.UpstreamTrack{}},
+		{"ahead 2", git.UpstreamTrack{}},
+		{"[ahead x]", git.UpstreamTrack{}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, git.ParseUpstreamTrack(tt.track), "ParseUpstreamTrack(%q)", tt.track)
+	}
+}
+
+// TestUpstreamBadges tests the upstream badge of each branch in the selection list,
+// and the confirmation warning for branches with unpushed commits.
+func TestUpstreamBadges(t *testing.T) {
+	origin := git.Upstream{Remote: "origin", Branch: "x"}
+	m := newTestModel("ahead", "behind", "gone", "local", "synced")
+	m.BranchDetails = map[string]git.BranchInfo{
+		"ahead":  {Name: "ahead", Upstream: origin, Track: git.UpstreamTrack{Ahead: 2}},
+		"behind": {Name: "behind", Upstream: origin, Track: git.UpstreamTrack{Behind: 1}},
+		"gone":   {Name: "gone", Upstream: origin, Gone: true, Track: git.UpstreamTrack{Gone: true}},
+		"local":  {Name: "local"},
+		"synced": {Name: "synced", Upstream: origin},
+	}
+
+	lines := strings.Split(ansi.Strip(m.View()), "\n")
+	rowOf := func(branch string) string {
+		for _, line := range lines {
+			if strings.Contains(line, "] "+branch) {
+				return line
+			}
+		}
+		t.Fatalf("No row for %s", branch)
+		return ""
+	}
+	assert.Contains(t, rowOf("ahead"), "origin ↑2")
+	assert.Contains(t, rowOf("behind"), "origin ↓1")
+	assert.Contains(t, rowOf("gone"), "gone")
+	assert.NotContains(t, rowOf("local"), "origin")
+	assert.Contains(t, rowOf("synced"), "origin ✓")
+
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "⚠ 2 unpushed commit(s) not on origin/x: deleting the branch loses them")
+	assert.Equal(t, 1, strings.Count(view, "unpushed commit(s)"), "Only branches with unpushed commits get the warning")
+}
+
+// TestDeleteWithUpstream tests deleting a local branch together with its remote branch.
+func TestDeleteWithUpstream(t *testing.T) {
+	repo, remote := setupRepoWithRemote(t, "feature-a", "feature-b", "feature-c")
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	for _, branch := range []string{"feature-a", "feature-b", "feature-c"} {
+		exec.Command("git", "branch", "--set-upstream-to=origin/"+branch, branch).Run()
+	}
+	// feature-b is already gone on the remote, so deleting it there fails
+	require.NoError(t, exec.Command("git", "-C", remote, "branch", "-D", "feature-b").Run())
+
+	upstreams, err := git.ListUpstreams()
+	require.NoError(t, err)
+
+	m := newTestModel("feature-a", "feature-b", "feature-c")
+	m.Upstreams = upstreams
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "r")
+	m = sendKey(t, m, "j")
+	m = sendKey(t, m, "r")
+	assert.Contains(t, m.View(), "[✓] also delete origin/feature-a")
+	assert.Contains(t, m.View(), "[ ] also delete origin/feature-c", "Remote deletion should default to off")
+
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateDone, m.State)
+
+	assert.Equal(t, 3, m.DeletedCount, "Remote failures must not fail the local deletion")
+	assert.Equal(t, 1, m.RemoteDeletedCount)
+	assert.Contains(t, m.RemoteFailures, "feature-b")
+	assert.False(t, m.HasFailures())
+	assert.Contains(t, m.View(), "Failed to delete 1 remote branch ")
+
+	output, _ := exec.Command("git", "-C", remote, "branch", "--format=%(refname:short)").Output()
+	assert.NotContains(t, string(output), "feature-a", "Upstream should be deleted")
+	assert.Contains(t, string(output), "feature-c", "Upstream without the toggle should be kept")
+}
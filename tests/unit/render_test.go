@@ -0,0 +1,74 @@
+package unit
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// renderFrames is how many times a screen is rendered to catch an order that changes between frames
+const renderFrames = 20
+
+// mixedResultsModel builds the done screen of a run with every kind of outcome, and failed upstream and tag
+// deletions and backup tags kept in maps, for many branches so a random map order would show.
+func mixedResultsModel() ui.AppModel {
+	var branches []string
+	for i := range 8 {
+		branches = append(branches, fmt.Sprintf("feature-%d", i))
+	}
+	m := newTestModel(branches...)
+	m.Styles = ui.NewStyles(ui.ThemeAuto, false)
+	m.State = ui.StateDone
+	m.Results = []ui.DeletionResult{
+		{Branch: "feature-0", Status: ui.ResultDeleted, SHA: "1111111"},
+		{Branch: "feature-1", Status: ui.ResultForceDeleted, SHA: "2222222"},
+		{Branch: "feature-2", Status: ui.ResultSkipped, Reason: "force delete declined"},
+		{Branch: "feature-3", Status: ui.ResultFailed, Reason: "error: cannot lock ref"},
+		{Branch: "feature-4", Status: ui.ResultAlreadyGone},
+		{Branch: "feature-5", Status: ui.ResultDeleted, SHA: "5555555"},
+		{Branch: "feature-6", Status: ui.ResultForceDeleted, SHA: "6666666"},
+		{Branch: "feature-7", Status: ui.ResultFailed, Reason: "error: unable to delete"},
+	}
+	m.DeletedCount = 4
+	m.RemoteDeletedCount = 1
+	m.RemoteFailures = map[string]string{
+		"feature-6": "remote rejected", "feature-0": "permission denied", "feature-5": "could not read from remote",
+	}
+	m.TagFailures = map[string]string{"v2.0": "tag is protected", "v1.0": "not found", "v1.5": "locked"}
+	m.BackupTags = map[string]string{"feature-6": "gelete-backup/feature-6-2024-05-01", "feature-1": "gelete-backup/feature-1-2024-05-01"}
+	return m
+}
+
+// TestRender_StableAcrossFrames tests that the screens listing branches kept in maps render the same text
+// on every frame, so nothing jumps around as the view is redrawn.
+func TestRender_StableAcrossFrames(t *testing.T) {
+	done := mixedResultsModel()
+
+	force := newTestModel("feature-a", "feature-b", "feature-c", "feature-d", "feature-e", "feature-f")
+	force.State = ui.StateForceConfirmation
+	for _, branch := range force.Branches {
+		force.UnmergedBranches[branch] = fmt.Sprintf("branch '%s' is not fully merged", branch)
+	}
+	force.SquashMerged = map[string]bool{"feature-b": true, "feature-e": true}
+
+	for name, m := range map[string]ui.AppModel{"done": done, "force confirmation": force} {
+		t.Run(name, func(t *testing.T) {
+			first := m.View()
+			for range renderFrames {
+				require.Equal(t, first, m.View(), "Every frame should render the same text")
+			}
+		})
+	}
+}
+
+// TestRender_DoneScreenGolden tests the done screen of a run with successes and failures against a golden file:
+// branches follow the list order and tags the name order. Run with -update to accept changes.
+func TestRender_DoneScreenGolden(t *testing.T) {
+	view := mixedResultsModel().View()
+	assert.NotContains(t, view, "\x1b", "The golden file is rendered without colors")
+	assertGolden(t, filepath.Join("testdata", "done", "mixed.golden"), view)
+}
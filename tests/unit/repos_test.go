@@ -0,0 +1,123 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPicker returns a repository picker over the given repositories, already loaded
+func newTestPicker(repos ...git.RepositorySummary) ui.RepoPickerModel {
+	m := ui.NewRepoPickerModel("~/repos", func() []git.RepositorySummary { return repos }, 0, ui.NewStyles(ui.ThemeAuto, true))
+	for _, msg := range collectMsgs(m.Init()) {
+		next, _ := m.Update(msg)
+		m = next.(ui.RepoPickerModel)
+	}
+	return m
+}
+
+// collectMsgs runs cmd, expanding batches, and returns the messages that aren't spinner ticks
+func collectMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		var msgs []tea.Msg
+		for _, c := range msg {
+			msgs = append(msgs, collectMsgs(c)...)
+		}
+		return msgs
+	case spinner.TickMsg, nil:
+		return nil
+	default:
+		return []tea.Msg{msg}
+	}
+}
+
+// pickerKey sends a key press to the picker and returns the updated picker with the returned command
+func pickerKey(m ui.RepoPickerModel, key string) (ui.RepoPickerModel, tea.Cmd) {
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	if key == "enter" {
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	}
+	next, cmd := m.Update(msg)
+	return next.(ui.RepoPickerModel), cmd
+}
+
+// TestRepoPicker_ListsAndChoosesRepositories tests the repository list with branch counts and errors,
+// that repositories that can't be opened aren't chosen, and that enter chooses the one under the cursor.
+func TestRepoPicker_ListsAndChoosesRepositories(t *testing.T) {
+	m := newTestPicker(
+		git.RepositorySummary{Dir: "/home/me/repos/api", Branches: 3, Gone: 1},
+		git.RepositorySummary{Dir: "/home/me/repos/broken", Err: errors.New("not a git repository")},
+		git.RepositorySummary{Dir: "/home/me/repos/web"},
+	)
+	require.False(t, m.Loading)
+
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "gelete - Repositories in ~/repos")
+	assert.Contains(t, view, "> api     3 branch(es) • 1 gone")
+	assert.Contains(t, view, "  broken  ✗ not a git repository")
+	assert.Contains(t, view, "  web     no branches to delete")
+
+	m, _ = pickerKey(m, "j")
+	m, cmd := pickerKey(m, "enter")
+	assert.Nil(t, cmd, "A broken repository should not be chosen")
+	assert.Empty(t, m.Chosen)
+	assert.Contains(t, ansi.Strip(m.View()), "broken can't be opened: not a git repository")
+
+	m, _ = pickerKey(m, "j")
+	m, _ = pickerKey(m, "enter")
+	assert.Empty(t, m.Chosen, "A repository without branches should not be chosen")
+	assert.Contains(t, ansi.Strip(m.View()), "No branches to delete in web.")
+
+	m, _ = pickerKey(m, "k")
+	m, _ = pickerKey(m, "k")
+	assert.NotContains(t, ansi.Strip(m.View()), "No branches to delete", "The message should clear on the next key")
+	m, cmd = pickerKey(m, "enter")
+	assert.Equal(t, "/home/me/repos/api", m.Chosen)
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+}
+
+// TestRepoPicker_KeepsCursorWhenReopened tests that the picker reopens on the repository it was left on,
+// clamped to the list, and that only quitting is possible while the branches are counted.
+func TestRepoPicker_KeepsCursorWhenReopened(t *testing.T) {
+	repos := []git.RepositorySummary{{Dir: "/repos/api", Branches: 1}, {Dir: "/repos/web", Branches: 1}}
+
+	m := ui.NewRepoPickerModel("/repos", func() []git.RepositorySummary { return repos }, 5, ui.NewStyles(ui.ThemeAuto, true))
+	assert.Contains(t, ansi.Strip(m.View()), "Counting branches")
+	m, cmd := pickerKey(m, "enter")
+	assert.Nil(t, cmd)
+	assert.Empty(t, m.Chosen, "Nothing should be chosen while loading")
+
+	next, _ := m.Update(collectMsgs(m.Init())[0])
+	m = next.(ui.RepoPickerModel)
+	assert.Equal(t, 1, m.CursorIndex)
+
+	m, _ = pickerKey(m, "q")
+	assert.Empty(t, m.Chosen)
+}
+
+// TestBackKey tests that b returns to the repository picker only when the branch list was opened from it
+func TestBackKey(t *testing.T) {
+	m := newTestModel("feature-a", "feature-b")
+	m = sendKey(t, m, "b")
+	assert.False(t, m.WentBack, "Without a picker to return to, b should do nothing")
+	assert.NotContains(t, ansi.Strip(m.View()), "back to repositories")
+
+	m.CanGoBack = true
+	assert.Contains(t, ansi.Strip(m.View()), "b: back to repositories")
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	assert.True(t, next.(ui.AppModel).WentBack)
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+}
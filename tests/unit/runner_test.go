@@ -0,0 +1,308 @@
+package unit
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResult is the canned outcome of a git command run by fakeRunner
+type fakeResult struct {
+	stdout string
+	stderr string
+	code   int
+}
+
+// fakeRunner answers git commands with canned results instead of running git. A command gets the result
+// registered for its longest matching prefix of arguments, e.g. "for-each-ref" answers any for-each-ref;
+// commands without a result fail the test. Results queued for a prefix answer one command each, in order,
+// before the registered one.
+type fakeRunner struct {
+	t       *testing.T
+	results map[string]fakeResult
+	queued  map[string][]fakeResult
+	calls   []git.Command
+}
+
+// newFakeRunner returns a fakeRunner with results keyed by the arguments joined with spaces
+func newFakeRunner(t *testing.T, results map[string]fakeResult) *fakeRunner {
+	return &fakeRunner{t: t, results: results, queued: make(map[string][]fakeResult)}
+}
+
+// queue adds results answering the next commands with the given prefix of arguments, one command each
+func (f *fakeRunner) queue(args string, results ...fakeResult) {
+	f.queued[args] = append(f.queued[args], results...)
+}
+
+// Run returns the result registered for the command, like git would with Combined set
+func (f *fakeRunner) Run(_ context.Context, cmd git.Command) ([]byte, []byte, error) {
+	f.calls = append(f.calls, cmd)
+
+	for n := len(cmd.Args); n > 0; n-- {
+		prefix := strings.Join(cmd.Args[:n], " ")
+		result, ok := f.results[prefix]
+		if queued := f.queued[prefix]; len(queued) > 0 {
+			result, ok, f.queued[prefix] = queued[0], true, queued[1:]
+		}
+		if !ok {
+			continue
+		}
+		stdout, stderr := result.stdout, result.stderr
+		if cmd.Combined {
+			stdout, stderr = stdout+stderr, ""
+		}
+		if result.code != 0 {
+			return []byte(stdout), []byte(stderr), &git.ExitError{Code: result.code, Stderr: []byte(stderr)}
+		}
+		return []byte(stdout), []byte(stderr), nil
+	}
+
+	f.t.Errorf("unexpected command: git %s", strings.Join(cmd.Args, " "))
+	return nil, nil, &git.ExitError{Code: 128}
+}
+
+// fakeRepository returns a repository whose git commands are answered by a fakeRunner
+func fakeRepository(t *testing.T, results map[string]fakeResult) (git.Repository, *fakeRunner) {
+	runner := newFakeRunner(t, results)
+	return git.Repository{Dir: "/repo", Runner: runner}, runner
+}
+
+// TestFakeRunner_ListWorktrees tests parsing the worktree list from canned porcelain output.
+func TestFakeRunner_ListWorktrees(t *testing.T) {
+	main := t.TempDir()
+	linked := t.TempDir()
+	gone := filepath.Join(t.TempDir(), "gone")
+
+	r, runner := fakeRepository(t, map[string]fakeResult{
+		"worktree list --porcelain": {stdout: "worktree " + main + "\r\nHEAD 1111111\r\nbranch refs/heads/trunk\r\n\r\n" +
+			"worktree " + linked + "\nHEAD 2222222\ndetached\nlocked on a USB drive\n\n" +
+			"worktree " + gone + "\nHEAD 3333333\nbranch refs/heads/feature/gone\nprunable gitdir file points to non-existent location\n"},
+	})
+
+	worktrees, err := r.ListWorktrees()
+	require.NoError(t, err)
+	require.Len(t, worktrees, 3)
+
+	assert.Equal(t, git.Worktree{Path: git.CanonicalPath(main), HEAD: "1111111", Branch: "trunk", Main: true}, worktrees[0])
+	assert.Equal(t, git.Worktree{Path: git.CanonicalPath(linked), HEAD: "2222222", Detached: true, Locked: true,
+		LockReason: "on a USB drive"}, worktrees[1])
+	assert.Equal(t, "feature/gone", worktrees[2].Branch)
+	assert.True(t, worktrees[2].Prunable)
+	assert.True(t, worktrees[2].Missing)
+
+	require.Len(t, runner.calls, 1)
+	assert.Equal(t, "/repo", runner.calls[0].Dir, "git should run in the repository")
+}
+
+// TestFakeRunner_ListWorktreesMalformed tests that unexpected porcelain output is parsed as far as it makes sense.
+func TestFakeRunner_ListWorktreesMalformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{"empty", "", nil},
+		{"attributes before the first worktree", "HEAD 1111111\nbranch refs/heads/orphan\n\nworktree /a\n", []string{"/a"}},
+		{"unknown attributes", "worktree /a\nbare\nfuture-attribute value\n", []string{"/a"}},
+		{"repeated blank lines", "\n\nworktree /a\n\n\n\nworktree /b\n\n", []string{"/a", "/b"}},
+		{"no trailing newline", "worktree /a\nbranch refs/heads/x", []string{"/a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := fakeRepository(t, map[string]fakeResult{"worktree list --porcelain": {stdout: tt.output}})
+
+			worktrees, err := r.ListWorktrees()
+			require.NoError(t, err)
+			var paths []string
+			for _, wt := range worktrees {
+				paths = append(paths, filepath.ToSlash(wt.Path))
+				assert.NotEqual(t, "orphan", wt.Branch, "Attributes before the first worktree belong to none")
+			}
+			assert.Equal(t, tt.want, paths)
+		})
+	}
+}
+
+// TestFakeRunner_ListWorktreesFailure tests that git's message is reported when the worktree list fails.
+func TestFakeRunner_ListWorktreesFailure(t *testing.T) {
+	r, _ := fakeRepository(t, map[string]fakeResult{
+		"worktree list": {stderr: "fatal: not a git repository (or any of the parent directories): .git\n", code: 128},
+	})
+
+	_, err := r.ListWorktrees()
+	assert.EqualError(t, err, "failed to list worktrees: fatal: not a git repository (or any of the parent directories): .git")
+}
+
+// TestFakeRunner_DeleteBranchErrors tests the classification of deletion errors, including ones that are hard
+// to produce in a real repository like a locked ref or a permission failure.
+func TestFakeRunner_DeleteBranchErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		stderr     string
+		wantKind   error
+		unmerged   bool
+		checkedOut string
+		lockFile   string
+	}{
+		{
+			name:     "not fully merged",
+			stderr:   "error: The branch 'feature' is not fully merged.\nIf you are sure you want to delete it, run 'git branch -D feature'.\n",
+			wantKind: git.ErrNotMerged,
+			unmerged: true,
+		},
+		{
+			name:     "not fully merged, git 2.46",
+			stderr:   "error: the branch 'feature' is not fully merged\nhint: If you are sure you want to delete it, run 'git branch -D feature'\n",
+			wantKind: git.ErrNotMerged,
+			unmerged: true,
+		},
+		{
+			name:     "not found",
+			stderr:   "error: branch 'feature' not found.\n",
+			wantKind: git.ErrNotFound,
+		},
+		{
+			name:       "checked out elsewhere",
+			stderr:     "error: Cannot delete branch 'feature' checked out at '/work/feature'\n",
+			checkedOut: "/work/feature",
+		},
+		{
+			name:     "locked ref",
+			stderr:   "error: cannot lock ref 'refs/heads/feature': Unable to create '/repo/.git/refs/heads/feature.lock': File exists.\n",
+			lockFile: "/repo/.git/refs/heads/feature.lock",
+		},
+		{
+			name:     "locked packed refs",
+			stderr:   "error: unable to create '/repo/.git/packed-refs.lock': File exists.\n",
+			lockFile: "/repo/.git/packed-refs.lock",
+		},
+		{
+			name:   "permission denied",
+			stderr: "error: unable to unlink '/repo/.git/refs/heads/feature': Permission denied\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, runner := fakeRepository(t, map[string]fakeResult{"branch -d -- feature": {stderr: tt.stderr, code: 1}})
+
+			err := r.DeleteBranch("feature")
+			require.Error(t, err)
+			if tt.checkedOut == "" {
+				assert.Contains(t, err.Error(), "failed to delete branch 'feature': "+strings.TrimSpace(tt.stderr),
+					"git's message is kept")
+			}
+			assert.Equal(t, tt.unmerged, git.IsUnmergedError(err))
+			if tt.wantKind != nil {
+				assert.ErrorIs(t, err, tt.wantKind)
+			}
+
+			var checkedOut *git.ErrCheckedOutElsewhere
+			if tt.checkedOut != "" {
+				require.ErrorAs(t, err, &checkedOut)
+				assert.Equal(t, tt.checkedOut, checkedOut.Path)
+			} else {
+				assert.NotErrorAs(t, err, &checkedOut)
+			}
+
+			var locked *git.ErrRefLocked
+			if tt.lockFile != "" {
+				require.ErrorAs(t, err, &locked)
+				assert.Equal(t, tt.lockFile, locked.LockFile)
+			} else {
+				assert.NotErrorAs(t, err, &locked)
+			}
+
+			require.Len(t, runner.calls, 1)
+			assert.True(t, runner.calls[0].Combined, "The message git prints on stderr should be returned")
+		})
+	}
+}
+
+// TestFakeRunner_ListDeletableBranches tests filtering the branch list: the current branch, protected branches
+// and the branch of the main worktree are excluded, and branches of linked worktrees are annotated.
+func TestFakeRunner_ListDeletableBranches(t *testing.T) {
+	git.SetProtectedPatterns(git.DefaultProtectedBranches)
+	defer git.SetProtectedPatterns(git.DefaultProtectedBranches)
+
+	linked := t.TempDir()
+	r, _ := fakeRepository(t, map[string]fakeResult{
+		"branch --show-current": {stdout: "current\n"},
+		"for-each-ref":          {stdout: "zeta\nmain\n\ncurrent\ntrunk\n  alpha  \nlinked\n"},
+		"worktree list --porcelain": {stdout: "worktree /main\nHEAD 1111111\nbranch refs/heads/trunk\n\n" +
+			"worktree " + linked + "\nHEAD 2222222\nbranch refs/heads/linked\n"},
+	})
+
+	branches, err := r.ListDeletableBranches()
+	require.NoError(t, err)
+
+	var names []string
+	for _, branch := range branches {
+		names = append(names, branch.Name)
+		if branch.Name == "linked" {
+			require.NotNil(t, branch.Worktree)
+			assert.Equal(t, git.CanonicalPath(linked), branch.Worktree.Path)
+		} else {
+			assert.Nil(t, branch.Worktree)
+		}
+	}
+	assert.Equal(t, []string{"alpha", "linked", "zeta"}, names)
+}
+
+// TestFakeRunner_ListBranchesDetachedWithoutShowCurrent tests that git without `git branch --show-current`
+// falls back to symbolic-ref, which tells a detached HEAD by exit code 1 without output.
+func TestFakeRunner_ListBranchesDetachedWithoutShowCurrent(t *testing.T) {
+	r, _ := fakeRepository(t, map[string]fakeResult{
+		"branch --show-current": {stderr: "error: unknown option `show-current'\n", code: 129},
+		"symbolic-ref":          {code: 1},
+		"for-each-ref":          {stdout: "feature-a\nfeature-b\n"},
+	})
+
+	branches, err := r.ListBranches()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feature-a", "feature-b"}, branches, "No branch is current when HEAD is detached")
+}
+
+// TestFakeRunner_ListBranchesWithInfoMalformed tests that lines without every field are skipped.
+func TestFakeRunner_ListBranchesWithInfoMalformed(t *testing.T) {
+	fields := func(values ...string) string { return strings.Join(values, "\x00") }
+	r, _ := fakeRepository(t, map[string]fakeResult{
+		"for-each-ref": {stdout: strings.Join([]string{
+			fields(" ", "feature-a", "1111111", "1714564800", "2 days ago", "Alice", "", "", "", "<a@example.com>", "<a@example.com>"),
+			fields(" ", "truncated", "2222222"),
+			fields(" ", "", "3333333", "1714564800", "2 days ago", "Bob", "", "", "", "<b@example.com>", "<b@example.com>"),
+			fields("*", "current", "4444444", "1714564800", "2 days ago", "Carol", "", "", "", "<c@example.com>", "<c@example.com>"),
+			fields(" ", "feature-b", "5555555", "not a date", "", "Dave", "origin", "refs/heads/feature-b", "[gone]", "<d@example.com>", "<d@example.com>"),
+			"garbage",
+		}, "\n")},
+	})
+
+	branches, err := r.ListBranchesWithInfo()
+	require.NoError(t, err)
+	require.Len(t, branches, 2)
+
+	assert.Equal(t, "feature-a", branches[0].Name)
+	assert.Equal(t, "a@example.com", branches[0].AuthorEmail)
+	assert.Equal(t, "feature-b", branches[1].Name)
+	assert.True(t, branches[1].Gone)
+	assert.True(t, branches[1].LastCommitDate.IsZero(), "An unparsable date should be left unset")
+	assert.Equal(t, git.Upstream{Remote: "origin", Branch: "feature-b"}, branches[1].Upstream)
+}
+
+// TestFakeRunner_Cancelled tests that a command stopped because its context was cancelled reports so,
+// rather than with the message of the killed git.
+func TestFakeRunner_Cancelled(t *testing.T) {
+	runner := newFakeRunner(t, map[string]fakeResult{"branch -D": {code: -1}})
+	ctx, cancel := context.WithCancel(git.WithRunner(context.Background(), runner))
+	cancel()
+
+	err := git.ForceDeleteBranchContext(ctx, "feature")
+	assert.ErrorIs(t, err, git.ErrCancelled)
+	assert.True(t, git.IsInterrupted(err))
+}
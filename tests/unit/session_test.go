@@ -0,0 +1,91 @@
+package unit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/session"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSession_SaveAndLoad tests persisting a selection and dropping deleted branches on load.
+func TestSession_SaveAndLoad(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	selected, err := session.Load()
+	require.NoError(t, err)
+	assert.Empty(t, selected, "No session should be loaded before saving")
+
+	exec.Command("git", "branch", "feature-a").Run()
+	exec.Command("git", "branch", "feature-b").Run()
+	exec.Command("git", "branch", "feature-c").Run()
+
+	err = session.Save(map[string]bool{"feature-a": true, "feature-b": true, "feature-c": false})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(repo, ".git", session.FileName))
+
+	exec.Command("git", "branch", "-D", "feature-b").Run()
+
+	selected, err = session.Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"feature-a": true}, selected, "Deleted branches should be dropped")
+
+	require.NoError(t, session.Clear())
+	assert.NoFileExists(t, filepath.Join(repo, ".git", session.FileName))
+	assert.NoError(t, session.Clear(), "Clearing twice should not fail")
+}
+
+// TestSession_PerRepository tests that sessions of different repositories don't collide.
+func TestSession_PerRepository(t *testing.T) {
+	repoA := setupTestRepo(t)
+	repoB := setupTestRepo(t)
+	exec.Command("git", "-C", repoA, "branch", "shared").Run()
+	exec.Command("git", "-C", repoB, "branch", "shared").Run()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+
+	require.NoError(t, os.Chdir(repoA))
+	require.NoError(t, session.Save(map[string]bool{"shared": true}))
+
+	require.NoError(t, os.Chdir(repoB))
+	selected, err := session.Load()
+	require.NoError(t, err)
+	assert.Empty(t, selected, "Another repository's session should not be loaded")
+}
+
+// TestSession_SavedOnSelectionAndClearedAfterDeletion tests the session lifecycle in the TUI.
+func TestSession_SavedOnSelectionAndClearedAfterDeletion(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "feature-a").Run()
+	exec.Command("git", "branch", "feature-b").Run()
+
+	m := newTestModel("feature-a", "feature-b")
+	m.PersistSession = true
+	m = sendKey(t, m, " ")
+
+	selected, err := session.Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"feature-a": true}, selected, "Selection should be saved as it changes")
+
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateDone, m.State)
+
+	assert.NoFileExists(t, filepath.Join(repo, ".git", session.FileName), "Session should be cleared after deleting")
+}
@@ -0,0 +1,111 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/ui"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resize feeds a terminal size to the model
+func resize(m ui.AppModel, width, height int) ui.AppModel {
+	next, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	return next.(ui.AppModel)
+}
+
+// paneLines returns the lines of the selection pane: the text right of its border
+func paneLines(view string) []string {
+	var lines []string
+	for _, line := range strings.Split(ansi.Strip(view), "\n") {
+		if _, pane, ok := strings.Cut(line, "│ "); ok {
+			lines = append(lines, strings.TrimSpace(pane))
+		}
+	}
+	return lines
+}
+
+// assertSplitFits checks that the lines of the split layout fit the terminal width
+func assertSplitFits(t *testing.T, view string, width int) {
+	t.Helper()
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, "│") {
+			assert.LessOrEqual(t, lipgloss.Width(line), width)
+		}
+	}
+}
+
+// TestSelectionPane_ListsSelectionInOrder tests that a wide terminal shows the selected branches right of the list
+// in the order they were selected, with their badges and a running count.
+func TestSelectionPane_ListsSelectionInOrder(t *testing.T) {
+	m := newTestModel("alpha", "beta", "gamma")
+	m.MergedBranches = map[string]bool{"alpha": true}
+	m.BranchWorktrees["gamma"] = "/tmp/gamma"
+	m = resize(m, 150, 30)
+	assert.Equal(t, []string{"Selected (0)", "nothing yet"}, paneLines(m.View())[:2])
+
+	m = sendKey(t, m, "end")
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "home")
+	m = sendKey(t, m, " ")
+
+	lines := paneLines(m.View())
+	require.GreaterOrEqual(t, len(lines), 3)
+	assert.Equal(t, []string{"Selected (2)", "gamma unmerged [⎇ worktree]", "alpha merged"}, lines[:3])
+
+	m = sendKey(t, m, "end")
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, " ")
+	assert.Equal(t, []string{"Selected (2)", "alpha merged", "gamma unmerged [⎇ worktree]"}, paneLines(m.View())[:3],
+		"Reselecting a branch moves it to the end")
+
+	assertSplitFits(t, m.View(), 150)
+}
+
+// TestSelectionPane_Toggle tests that tab shows and hides the pane, and that narrow terminals keep a single column.
+func TestSelectionPane_Toggle(t *testing.T) {
+	m := newTestModel("alpha", "beta")
+	m = resize(m, 120, 30)
+	assert.Empty(t, paneLines(m.View()), "Not shown on its own below 140 columns")
+	assert.Contains(t, ansi.Strip(m.View()), "tab: selection pane")
+
+	m = sendKey(t, m, "tab")
+	assert.NotEmpty(t, paneLines(m.View()))
+
+	m = resize(m, 90, 30)
+	assert.Empty(t, paneLines(m.View()), "Collapses to a single column below 100 columns")
+	assert.NotContains(t, ansi.Strip(m.View()), "tab: selection pane")
+	m = resize(m, 120, 30)
+	assert.NotEmpty(t, paneLines(m.View()))
+
+	m = sendKey(t, m, "tab")
+	assert.Empty(t, paneLines(m.View()))
+	m = resize(m, 160, 30)
+	assert.Empty(t, paneLines(m.View()), "Hiding the pane outlasts resizing")
+
+	m = resize(m, 80, 30)
+	m = sendKey(t, m, "tab")
+	assert.Contains(t, ansi.Strip(m.View()), "too narrow for the selection pane")
+}
+
+// TestSelectionPane_TruncatesToHeight tests that branches not fitting in the pane are counted on its last line,
+// and that long names are truncated to the pane width.
+func TestSelectionPane_TruncatesToHeight(t *testing.T) {
+	var branches []string
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		branches = append(branches, "feature/"+strings.Repeat(name, 60))
+	}
+	m := newTestModel(branches...)
+	m = resize(m, 150, 14)
+	m = sendKey(t, m, "a")
+
+	lines := paneLines(m.View())
+	assert.Equal(t, "Selected (8)", lines[0])
+	assert.Regexp(t, `^… and \d more$`, lines[len(lines)-1])
+	assert.True(t, strings.HasSuffix(lines[1], "…"))
+	assertSplitFits(t, m.View(), 150)
+}
@@ -0,0 +1,112 @@
+package unit
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// colorTestModel builds a model that exercises most styles: selection, worktrees, merge badges and metadata.
+func colorTestModel(colorEnabled bool) ui.AppModel {
+	m := newTestModel("feature-a", "feature-b")
+	m.Styles = ui.NewStyles(ui.ThemeAuto, colorEnabled)
+	m.Selected["feature-a"] = true
+	m.BranchWorktrees["feature-b"] = "/tmp/wt"
+	m.BaseBranch = "main"
+	m.MergedBranches = map[string]bool{"feature-a": true}
+	m.BranchDetails = map[string]git.BranchInfo{"feature-a": {Name: "feature-a", LastCommitDate: time.Now().Add(-2 * 24 * time.Hour)}}
+	m.Results = []ui.DeletionResult{
+		{Branch: "feature-a", Status: ui.ResultDeleted, SHA: "abc1234"},
+		{Branch: "feature-b", Status: ui.ResultFailed, Reason: "boom"},
+	}
+	m.DeletedCount = 1
+	return m
+}
+
+// TestStyles_NoEscapeSequencesWithoutColor tests that disabling color removes every escape sequence.
+func TestStyles_NoEscapeSequencesWithoutColor(t *testing.T) {
+	// Force a color profile so the colored styles would emit escape sequences
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	defer lipgloss.SetColorProfile(profile)
+
+	states := []ui.AppState{ui.StateSelection, ui.StateConfirmation, ui.StateWorktreeConfirmation, ui.StateForceConfirmation, ui.StateDone}
+	for _, state := range states {
+		colored := colorTestModel(true)
+		colored.State = state
+		assert.Contains(t, colored.View(), "\x1b[", "Colored output should be styled in state %v", state)
+
+		plain := colorTestModel(false)
+		plain.State = state
+		assert.NotContains(t, plain.View(), "\x1b", "Output without color must not contain escape sequences in state %v", state)
+	}
+}
+
+// updateGolden rewrites the golden files in testdata instead of comparing them
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// TestStyles_ThemeSamples tests the colors of every style in the dark and light themes against golden files,
+// so changes to a palette show up in review. Run with -update to accept them.
+func TestStyles_ThemeSamples(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	defer lipgloss.SetColorProfile(profile)
+
+	for _, theme := range []ui.Theme{ui.ThemeDark, ui.ThemeLight} {
+		t.Run(theme.String(), func(t *testing.T) {
+			sample := ui.RenderStyleSample(ui.NewStyles(theme, true))
+			assertGolden(t, filepath.Join("testdata", "themes", theme.String()+".golden"), sample)
+		})
+	}
+}
+
+// assertGolden compares got with the golden file at path, or rewrites the file with -update
+func assertGolden(t *testing.T, path, got string) {
+	t.Helper()
+
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+	}
+
+	golden, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), got)
+}
+
+// TestStyles_AutoThemeFollowsBackground tests that the auto theme renders the colors of the dark or the light
+// theme depending on the terminal background.
+func TestStyles_AutoThemeFollowsBackground(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	defer lipgloss.SetColorProfile(profile)
+	dark := lipgloss.HasDarkBackground()
+	defer lipgloss.SetHasDarkBackground(dark)
+
+	auto := ui.NewStyles(ui.ThemeAuto, true)
+	for _, theme := range []ui.Theme{ui.ThemeDark, ui.ThemeLight} {
+		lipgloss.SetHasDarkBackground(theme == ui.ThemeDark)
+		assert.Equal(t, ui.RenderStyleSample(ui.NewStyles(theme, true)), ui.RenderStyleSample(auto), "auto should match %s", theme)
+	}
+}
+
+// TestParseTheme tests the values of --theme.
+func TestParseTheme(t *testing.T) {
+	for _, theme := range ui.Themes {
+		parsed, err := ui.ParseTheme(theme.String())
+		require.NoError(t, err)
+		assert.Equal(t, theme, parsed)
+	}
+
+	_, err := ui.ParseTheme("solarized")
+	assert.EqualError(t, err, "invalid theme 'solarized' (expected auto, dark or light)")
+}
@@ -0,0 +1,148 @@
+package unit
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTaggedBranches creates "tagged" with a lightweight and an annotated tag at its tip,
+// and "twin-a"/"twin-b" sharing a tip that "shared-tag" points at.
+func setupTaggedBranches(t *testing.T) {
+	t.Helper()
+
+	exec.Command("git", "checkout", "-b", "tagged").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Tagged commit").Run()
+	exec.Command("git", "tag", "tmp-light").Run()
+	exec.Command("git", "tag", "-a", "tmp-annotated", "-m", "Annotated tag").Run()
+	exec.Command("git", "checkout", "-").Run()
+
+	exec.Command("git", "checkout", "-b", "twin-a").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Twin commit").Run()
+	exec.Command("git", "branch", "twin-b").Run()
+	exec.Command("git", "tag", "shared-tag").Run()
+	exec.Command("git", "checkout", "-").Run()
+}
+
+// TestGetTagsAtBranch tests that lightweight and annotated tags at the branch tip are found.
+func TestGetTagsAtBranch(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	setupTaggedBranches(t)
+
+	tags, err := git.GetTagsAtBranch("tagged")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tmp-annotated", "tmp-light"}, tags)
+
+	tags, err = git.GetTagsAtBranch("twin-a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shared-tag"}, tags)
+
+	_, err = git.GetTagsAtBranch("does-not-exist")
+	assert.Error(t, err)
+}
+
+// TestGetBranchesAtTag tests that every branch whose tip a tag points at is found, peeling annotated tags.
+func TestGetBranchesAtTag(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	setupTaggedBranches(t)
+
+	branches, err := git.GetBranchesAtTag("shared-tag")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"twin-a", "twin-b"}, branches)
+
+	branches, err = git.GetBranchesAtTag("tmp-annotated")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tagged"}, branches)
+}
+
+// TestDeleteTag tests deleting an existing and a missing tag.
+func TestDeleteTag(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "tag", "doomed").Run()
+	require.NoError(t, git.DeleteTag("doomed"))
+
+	err = exec.Command("git", "rev-parse", "--verify", "refs/tags/doomed").Run()
+	assert.Error(t, err, "Tag should be deleted")
+
+	err = git.DeleteTag("doomed")
+	assert.ErrorContains(t, err, "failed to delete tag 'doomed'")
+}
+
+// TestDeletion_DeletesTagsAlongWithBranch tests that toggling t deletes the tags only the branch points at,
+// while tags shared with another branch are flagged and kept.
+func TestDeletion_DeletesTagsAlongWithBranch(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	setupTaggedBranches(t)
+	exec.Command("git", "merge", "--ff-only", "tagged").Run()
+	exec.Command("git", "merge", "--no-edit", "twin-a").Run()
+
+	m := newTestModel("tagged", "twin-a")
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	require.Equal(t, ui.StateConfirmation, m.State)
+	require.Len(t, m.BranchTags["twin-a"], 1)
+	assert.Equal(t, []string{"twin-b"}, m.BranchTags["twin-a"][0].SharedWith)
+
+	view := m.View()
+	assert.Contains(t, view, "[ ] also delete tags tmp-annotated, tmp-light")
+	assert.Contains(t, view, "tag shared-tag is kept: also at the tip of twin-b")
+	assert.Contains(t, view, "t: also delete tags")
+
+	m = sendKey(t, m, "t")
+	m = sendKey(t, m, "j")
+	m = sendKey(t, m, "t")
+	assert.False(t, m.DeleteTags["twin-a"], "Branches with only shared tags can't be toggled")
+
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateDone, m.State)
+	assert.Equal(t, 2, m.DeletedTagCount)
+	assert.Empty(t, m.TagFailures)
+	assert.Contains(t, m.View(), "Also deleted 2 tags")
+
+	tags, err := exec.Command("git", "tag", "--list").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "shared-tag\n", string(tags), "Only the shared tag should remain")
+}
+
+// TestDoneView_ReportsTagFailures tests that tags that couldn't be deleted are listed apart from the branch results.
+func TestDoneView_ReportsTagFailures(t *testing.T) {
+	m := newTestModel("feature")
+	m.State = ui.StateDone
+	m.Results = []ui.DeletionResult{{Branch: "feature", Status: ui.ResultDeleted}}
+	m.TagFailures = map[string]string{"tmp-1": "failed to delete tag 'tmp-1': error: tag 'tmp-1' not found."}
+
+	view := m.View()
+	assert.Contains(t, view, "✓ feature: deleted")
+	assert.Contains(t, view, "Failed to delete 1 tag (their branches were deleted)")
+	assert.Contains(t, view, "tmp-1: failed to delete tag 'tmp-1'")
+	assert.False(t, m.HasFailures(), "Tag failures should not fail the branch deletion")
+}
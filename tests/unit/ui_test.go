@@ -0,0 +1,1698 @@
+package unit
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestModel builds an AppModel in the selection state for the given branches.
+func newTestModel(branches ...string) ui.AppModel {
+	return ui.AppModel{
+		Branches:         branches,
+		Selected:         make(map[string]bool),
+		State:            ui.StateSelection,
+		UnmergedBranches: make(map[string]string),
+		BranchWorktrees:  make(map[string]string),
+		Styles:           ui.NewStyles(ui.ThemeAuto, true),
+	}
+}
+
+// specialKeys maps the names sendKey accepts for keys that aren't runes to their key types.
+var specialKeys = map[string]tea.KeyType{
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEsc,
+	"tab":       tea.KeyTab,
+	"backspace": tea.KeyBackspace,
+	"pgup":      tea.KeyPgUp,
+	"pgdown":    tea.KeyPgDown,
+	"home":      tea.KeyHome,
+	"end":       tea.KeyEnd,
+	"ctrl+c":    tea.KeyCtrlC,
+}
+
+// sendKey feeds a key press to the model and runs any resulting command to completion.
+func sendKey(t *testing.T, m ui.AppModel, key string) ui.AppModel {
+	t.Helper()
+
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	if keyType, ok := specialKeys[key]; ok {
+		msg = tea.KeyMsg{Type: keyType}
+	} else if key == " " {
+		msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	}
+
+	next, cmd := m.Update(msg)
+	return runCmds(next.(ui.AppModel), cmd)
+}
+
+// typeConfirmation types text into the typed confirmation of a deletion prompt and presses enter.
+func typeConfirmation(t *testing.T, m ui.AppModel, text string) ui.AppModel {
+	t.Helper()
+
+	for _, r := range text {
+		m = sendKey(t, m, string(r))
+	}
+	return sendKey(t, m, "enter")
+}
+
+// runCmds runs cmd and every command it produces until none are left,
+// feeding each resulting message back into the model. Spinner ticks and quit
+// messages are dropped so the loop terminates.
+func runCmds[M tea.Model](model M, cmd tea.Cmd) M {
+	pending := []tea.Cmd{cmd}
+	for len(pending) > 0 {
+		cmd, pending = pending[0], pending[1:]
+		if cmd == nil {
+			continue
+		}
+
+		switch result := cmd().(type) {
+		case nil, tea.QuitMsg, spinner.TickMsg:
+		case tea.BatchMsg:
+			pending = append(pending, result...)
+		default:
+			next, nextCmd := model.Update(result)
+			model = next.(M)
+			pending = append(pending, nextCmd)
+		}
+	}
+	return model
+}
+
+// assertResult asserts that the deletion run recorded the given outcome for a branch.
+func assertResult(t *testing.T, m ui.AppModel, branch string, status ui.ResultStatus) {
+	t.Helper()
+
+	result, ok := m.Result(branch)
+	require.True(t, ok, "No result recorded for %s", branch)
+	assert.Equal(t, status, result.Status, "Unexpected outcome for %s: %s", branch, result.Reason)
+}
+
+// TestDryRun_DoesNotDeleteBranches tests that a dry run reports actions without deleting anything.
+func TestDryRun_DoesNotDeleteBranches(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "merged").Run()
+	exec.Command("git", "checkout", "-b", "unmerged").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "checkout", "-").Run()
+
+	m := newTestModel("merged", "unmerged")
+	m.DryRun = true
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "j")
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	require.Equal(t, ui.StateConfirmation, m.State)
+
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateForceConfirmation, m.State, "Unmerged branch should prompt for force deletion")
+
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateDone, m.State)
+
+	require.Len(t, m.DryRunActions, 2)
+	assert.Equal(t, ui.DryRunAction{Branch: "merged", Merged: true}, m.DryRunActions[0])
+	assert.Equal(t, ui.DryRunAction{Branch: "unmerged", Force: true}, m.DryRunActions[1])
+	assert.Zero(t, m.DeletedCount, "Dry run should not count deletions")
+	assert.Contains(t, m.View(), "would force delete")
+
+	branches, err := git.ListBranches()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"merged", "unmerged"}, branches, "Dry run must not delete branches")
+}
+
+// TestDryRun_SkipUnmerged tests that declining force deletion in a dry run reports the branch as skipped.
+func TestDryRun_SkipUnmerged(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "checkout", "-b", "unmerged").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "checkout", "-").Run()
+
+	m := newTestModel("unmerged")
+	m.DryRun = true
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	m = sendKey(t, m, "n")
+	require.Equal(t, ui.StateDone, m.State)
+
+	require.Len(t, m.DryRunActions, 1)
+	assert.False(t, m.DryRunActions[0].Force)
+	assert.Contains(t, m.View(), "would skip")
+}
+
+// TestSelectionView_MetadataAligned tests that metadata lines up regardless of branch name length.
+func TestSelectionView_MetadataAligned(t *testing.T) {
+	long := strings.Repeat("x", 80)
+	m := newTestModel("a", long)
+	now := time.Now()
+	m.BranchDetails = map[string]git.BranchInfo{
+		"a":  {Name: "a", LastCommitDate: now.Add(-2 * 24 * time.Hour), LastCommitter: "Alice"},
+		long: {Name: long, LastCommitDate: now.Add(-3 * 7 * 24 * time.Hour), LastCommitter: "Bob"},
+	}
+
+	view := ansi.Strip(m.View())
+	column := func(meta string) int {
+		for _, line := range strings.Split(view, "\n") {
+			if idx := strings.Index(line, meta); idx >= 0 {
+				return lipgloss.Width(line[:idx])
+			}
+		}
+		return -1
+	}
+
+	assert.Equal(t, column("2 days ago"), column("3 weeks ago"), "Metadata column should be aligned")
+	assert.Positive(t, column("2 days ago"))
+	assert.NotContains(t, view, long, "Long branch names should be truncated")
+	assert.Contains(t, view, "Alice")
+}
+
+// TestSelectAll_TogglesAllBranches tests that 'a' selects all branches and pressing it again deselects them.
+func TestSelectAll_TogglesAllBranches(t *testing.T) {
+	m := newTestModel("feature-a", "feature-b", "feature-c")
+
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "a")
+	assert.Equal(t, map[string]bool{"feature-a": true, "feature-b": true, "feature-c": true}, m.Selected)
+	assert.Contains(t, m.View(), "3/3 selected")
+
+	m = sendKey(t, m, "a")
+	assert.Equal(t, map[string]bool{"feature-a": false, "feature-b": false, "feature-c": false}, m.Selected)
+	assert.Contains(t, m.View(), "0/3 selected")
+}
+
+// TestInvertSelection tests that 'A' flips the selection of every branch.
+func TestInvertSelection(t *testing.T) {
+	m := newTestModel("feature-a", "feature-b", "feature-c")
+
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "A")
+	assert.Equal(t, map[string]bool{"feature-a": false, "feature-b": true, "feature-c": true}, m.Selected)
+	assert.Contains(t, m.View(), "2/3 selected")
+}
+
+// typeText sends each rune of text as a separate key press.
+func typeText(t *testing.T, m ui.AppModel, text string) ui.AppModel {
+	t.Helper()
+
+	for _, r := range text {
+		m = sendKey(t, m, string(r))
+	}
+	return m
+}
+
+// TestFilter_NarrowsVisibleBranches tests that typing a query filters the branch list case-insensitively.
+func TestFilter_NarrowsVisibleBranches(t *testing.T) {
+	m := newTestModel("bugfix-1", "feature-a", "Feature-B")
+
+	m = sendKey(t, m, "/")
+	require.True(t, m.Filtering)
+	m = typeText(t, m, "feat")
+	assert.Equal(t, []string{"feature-a", "Feature-B"}, m.VisibleBranches())
+	assert.Contains(t, m.View(), "/feat")
+
+	// Keys like 'q' and 'j' are part of the query while filtering
+	m = typeText(t, m, "q")
+	assert.Empty(t, m.VisibleBranches())
+	assert.Contains(t, m.View(), "No branches match the filter.")
+
+	m = sendKey(t, m, "backspace")
+	assert.Len(t, m.VisibleBranches(), 2)
+
+	m = sendKey(t, m, "enter")
+	assert.False(t, m.Filtering, "enter should return to navigation")
+	assert.Equal(t, "feat", m.FilterQuery, "Filter should remain applied")
+
+	m = sendKey(t, m, "esc")
+	assert.Empty(t, m.FilterQuery, "esc should clear the filter")
+	assert.Len(t, m.VisibleBranches(), 3)
+}
+
+// TestFilter_PreservesSelectionsAndClampsCursor tests selections and cursor handling across filter changes.
+func TestFilter_PreservesSelectionsAndClampsCursor(t *testing.T) {
+	m := newTestModel("bugfix-1", "bugfix-2", "feature-a")
+
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "j")
+	m = sendKey(t, m, "j")
+	require.Equal(t, 2, m.CursorIndex)
+
+	m = sendKey(t, m, "/")
+	m = typeText(t, m, "feature")
+	assert.Equal(t, 0, m.CursorIndex, "Cursor should be clamped to the filtered list")
+
+	m = sendKey(t, m, "enter")
+	m = sendKey(t, m, " ")
+	assert.True(t, m.Selected["feature-a"], "Toggle should apply to the visible branch under the cursor")
+	assert.True(t, m.Selected["bugfix-1"], "Selections made before filtering must be preserved")
+}
+
+// TestFilter_UnicodeCaseInsensitive tests that the filter folds the case of non-ASCII letters.
+func TestFilter_UnicodeCaseInsensitive(t *testing.T) {
+	m := newTestModel("feature/ÄRGER", "fix/σίγμα", "feature/ユーザー管理")
+
+	m = sendKey(t, m, "/")
+	m = typeText(t, m, "ärger")
+	assert.Equal(t, []string{"feature/ÄRGER"}, m.VisibleBranches())
+
+	m = sendKey(t, m, "esc")
+	m = sendKey(t, m, "/")
+	m = typeText(t, m, "ΣΊΓΜΑ")
+	assert.Equal(t, []string{"fix/σίγμα"}, m.VisibleBranches(), "Σ should match both σ and ς")
+
+	m = sendKey(t, m, "esc")
+	m = sendKey(t, m, "/")
+	m = typeText(t, m, "ユーザー")
+	assert.Equal(t, []string{"feature/ユーザー管理"}, m.VisibleBranches())
+}
+
+// TestSelectionView_AlignsWideCharacters tests that the metadata column stays aligned
+// when branch names contain double-width characters.
+func TestSelectionView_AlignsWideCharacters(t *testing.T) {
+	m := newTestModel("feature/ユーザー管理", "fix/#123-weird")
+	m.BaseBranch = "main"
+	m.MergedBranches = map[string]bool{"feature/ユーザー管理": true}
+
+	var columns []int
+	for _, line := range strings.Split(ansi.Strip(m.View()), "\n") {
+		if i := strings.Index(line, "merged"); i >= 0 && strings.Contains(line, "[ ]") {
+			prefix := strings.TrimSuffix(line[:i], "un")
+			columns = append(columns, lipgloss.Width(prefix))
+		}
+	}
+	require.Len(t, columns, 2)
+	assert.Equal(t, columns[0], columns[1], "Merge status should start in the same column")
+}
+
+// TestFilter_SelectAllOnlyVisible tests that select-all only affects branches matching the filter.
+func TestFilter_SelectAllOnlyVisible(t *testing.T) {
+	m := newTestModel("bugfix-1", "feature-a", "feature-b")
+
+	m = sendKey(t, m, "/")
+	m = typeText(t, m, "feature")
+	m = sendKey(t, m, "enter")
+	m = sendKey(t, m, "a")
+
+	assert.False(t, m.Selected["bugfix-1"])
+	assert.True(t, m.Selected["feature-a"])
+	assert.True(t, m.Selected["feature-b"])
+}
+
+// TestSelectionView_MergeAnnotations tests that merge status is shown only when known.
+func TestSelectionView_MergeAnnotations(t *testing.T) {
+	m := newTestModel("feature-a", "feature-b")
+	assert.NotContains(t, m.View(), "merged", "No annotations without merge status")
+
+	m.BaseBranch = "main"
+	m.MergedBranches = map[string]bool{"feature-a": true}
+	view := m.View()
+	assert.Contains(t, view, "feature-a  merged")
+	assert.Contains(t, view, "feature-b  unmerged")
+	assert.Contains(t, view, "merge status against main")
+}
+
+// TestMergeFilter_CyclesAndKeepsVisibleSelections tests the m key cycling through the merge filters.
+func TestMergeFilter_CyclesAndKeepsVisibleSelections(t *testing.T) {
+	m := newTestModel("feature-a", "feature-b", "feature-c")
+	m.BaseBranch = "main"
+	m.MergedBranches = map[string]bool{"feature-a": true, "feature-c": true}
+	m.Selected["feature-a"] = true
+	m.Selected["feature-b"] = true
+
+	m = sendKey(t, m, "m")
+	assert.Equal(t, ui.MergeFilterMerged, m.MergeFilter)
+	assert.Equal(t, []string{"feature-a", "feature-c"}, m.VisibleBranches())
+	assert.Contains(t, m.View(), "showing merged only")
+	assert.True(t, m.Selected["feature-a"], "Selections of branches that remain visible must be preserved")
+	assert.False(t, m.Selected["feature-b"], "Hidden branches must not be deleted")
+
+	m = sendKey(t, m, "m")
+	assert.Equal(t, ui.MergeFilterUnmerged, m.MergeFilter)
+	assert.Equal(t, []string{"feature-b"}, m.VisibleBranches())
+	assert.Contains(t, m.View(), "showing unmerged only")
+	assert.False(t, m.Selected["feature-a"], "Merged branches are hidden and deselected")
+
+	m = sendKey(t, m, "m")
+	assert.Equal(t, ui.MergeFilterAll, m.MergeFilter)
+	assert.Len(t, m.VisibleBranches(), 3)
+	assert.NotContains(t, m.View(), "showing")
+}
+
+// TestMergeFilter_EmptyMessages tests that an empty filtered list is told apart from having no branches.
+func TestMergeFilter_EmptyMessages(t *testing.T) {
+	m := newTestModel()
+	assert.Contains(t, m.View(), "No branches to delete.")
+
+	m = newTestModel("feature-a")
+	m.BaseBranch = "main"
+	m.MergedBranches = map[string]bool{}
+	m.SetMergeFilter(ui.MergeFilterMerged)
+	view := m.View()
+	assert.Contains(t, view, "No branches merged into main.")
+	assert.NotContains(t, view, "No branches to delete.")
+}
+
+// TestMergeFilter_RequiresMergeStatus tests that the m key does nothing without merge status.
+func TestMergeFilter_RequiresMergeStatus(t *testing.T) {
+	m := newTestModel("feature-a")
+
+	m = sendKey(t, m, "m")
+	assert.Equal(t, ui.MergeFilterAll, m.MergeFilter)
+	assert.NotContains(t, m.View(), "m: merged/unmerged")
+}
+
+// newMineTestModel returns a model of three branches: one authored by the user, one authored by someone else
+// and rebased by the user, and one of someone else's, with the user's email in another case than git config's.
+func newMineTestModel() ui.AppModel {
+	m := newTestModel("feature-a", "feature-b", "feature-c")
+	m.RepoPath = "/src/app"
+	m.UserEmail = "Me@Example.com"
+	m.BranchDetails = map[string]git.BranchInfo{
+		"feature-a": {Name: "feature-a", AuthorEmail: "me@example.com", CommitterEmail: "me@example.com"},
+		"feature-b": {Name: "feature-b", AuthorEmail: "alice@example.com", CommitterEmail: "me@example.com"},
+		"feature-c": {Name: "feature-c", AuthorEmail: "bob@example.com", CommitterEmail: "bob@example.com"},
+	}
+	return m
+}
+
+// TestMineFilter_TogglesAndKeepsVisibleSelections tests the o key switching between all branches and the user's.
+func TestMineFilter_TogglesAndKeepsVisibleSelections(t *testing.T) {
+	m := newMineTestModel()
+	m.Selected["feature-a"] = true
+	m.Selected["feature-c"] = true
+	assert.Contains(t, m.View(), "o: all/mine")
+
+	m = sendKey(t, m, "o")
+	assert.True(t, m.OnlyMine)
+	assert.Equal(t, []string{"feature-a"}, m.VisibleBranches(), "The emails should be compared ignoring case")
+	header := strings.Split(ansi.Strip(m.View()), "\n")[0]
+	assert.Equal(t, "/src/app • detached HEAD • 3 branches • only branches authored by Me@Example.com", header)
+	assert.True(t, m.Selected["feature-a"], "Selections of branches that remain visible must be preserved")
+	assert.False(t, m.Selected["feature-c"], "Hidden branches must not be deleted")
+
+	m = sendKey(t, m, "o")
+	assert.False(t, m.OnlyMine)
+	assert.Len(t, m.VisibleBranches(), 3)
+	assert.NotContains(t, m.View(), "only branches")
+}
+
+// TestMineFilter_Committer tests that --mine=committer also counts the branches the user rebased.
+func TestMineFilter_Committer(t *testing.T) {
+	m := newMineTestModel()
+	m.MineField = ui.MineCommitter
+	m.SetMineFilter(true)
+
+	assert.Equal(t, []string{"feature-a", "feature-b"}, m.VisibleBranches())
+	assert.Contains(t, ansi.Strip(m.View()), "only branches committed by Me@Example.com")
+}
+
+// TestMineFilter_EmptyMessages tests that the message of an empty list names the mine filter along with the merge filter.
+func TestMineFilter_EmptyMessages(t *testing.T) {
+	m := newMineTestModel()
+	m.UserEmail = "nobody@example.com"
+	m.SetMineFilter(true)
+	assert.Contains(t, m.View(), "No branches match the filter (authored by nobody@example.com).")
+
+	m = newMineTestModel()
+	m.BaseBranch = "main"
+	m.MergedBranches = map[string]bool{"feature-c": true}
+	m.SetMergeFilter(ui.MergeFilterMerged)
+	m.SetMineFilter(true)
+	assert.Contains(t, m.View(), "No branches match the filter (merged only, authored by Me@Example.com).")
+}
+
+// TestMineFilter_RequiresUserEmail tests that the o key does nothing when user.email isn't set.
+func TestMineFilter_RequiresUserEmail(t *testing.T) {
+	m := newMineTestModel()
+	m.UserEmail = ""
+
+	m = sendKey(t, m, "o")
+	assert.False(t, m.OnlyMine)
+	assert.NotContains(t, m.View(), "o: all/mine")
+}
+
+// TestParseMineField tests the values of --mine.
+func TestParseMineField(t *testing.T) {
+	for _, field := range []ui.MineField{ui.MineAuthor, ui.MineCommitter} {
+		parsed, err := ui.ParseMineField(field.String())
+		require.NoError(t, err)
+		assert.Equal(t, field, parsed)
+	}
+
+	_, err := ui.ParseMineField("reviewer")
+	assert.EqualError(t, err, "invalid --mine 'reviewer' (expected author or committer)")
+}
+
+// setupWorktreeModel creates branches "plain" and "wt" in the current repository,
+// checks "wt" out in a new worktree, and returns a model with both branches selected.
+func setupWorktreeModel(t *testing.T) ui.AppModel {
+	t.Helper()
+
+	exec.Command("git", "branch", "plain").Run()
+	exec.Command("git", "branch", "wt").Run()
+	require.NoError(t, exec.Command("git", "worktree", "add", t.TempDir(), "wt").Run())
+
+	worktrees, err := git.ListWorktrees()
+	require.NoError(t, err)
+
+	m := newTestModel("plain", "wt")
+	for _, wt := range worktrees {
+		if wt.Branch != "" {
+			m.BranchWorktrees[wt.Branch] = wt.Path
+		}
+	}
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	return sendKey(t, m, "y")
+}
+
+// TestWorktreeFlow_RemovesWorktreeBeforeDeleting tests the worktree confirmation flow.
+func TestWorktreeFlow_RemovesWorktreeBeforeDeleting(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	m := setupWorktreeModel(t)
+	require.Equal(t, ui.StateWorktreeConfirmation, m.State, "Branches with worktrees need confirmation")
+	assert.Contains(t, m.View(), m.BranchWorktrees["wt"])
+
+	m = sendKey(t, m, "y")
+	assert.Equal(t, ui.StateDone, m.State)
+	assert.Equal(t, 2, m.DeletedCount)
+	assert.False(t, m.HasFailures())
+
+	branches, _ := git.ListBranches()
+	assert.Empty(t, branches, "Both branches should be deleted")
+}
+
+// TestWorktreeFlow_DeclineSkipsBranch tests that declining worktree removal skips only those branches.
+func TestWorktreeFlow_DeclineSkipsBranch(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	m := setupWorktreeModel(t)
+	require.Equal(t, ui.StateWorktreeConfirmation, m.State)
+
+	m = sendKey(t, m, "n")
+	assert.Equal(t, ui.StateDone, m.State)
+	assert.Equal(t, 1, m.DeletedCount)
+	assertResult(t, m, "wt", ui.ResultSkipped)
+	assert.Contains(t, m.View(), "⊘ wt: skipped (worktree removal declined)")
+
+	branches, _ := git.ListBranches()
+	assert.Equal(t, []string{"wt"}, branches, "Only the branch without a worktree should be deleted")
+}
+
+// TestWorktreeFlow_LockedWorktreeNeedsSecondPrompt tests escalation to force removal for locked worktrees.
+func TestWorktreeFlow_LockedWorktreeNeedsSecondPrompt(t *testing.T) {
+	for _, answer := range []string{"y", "n"} {
+		t.Run(answer, func(t *testing.T) {
+			repo := setupTestRepo(t)
+
+			originalDir, _ := os.Getwd()
+			defer os.Chdir(originalDir)
+			err := os.Chdir(repo)
+			require.NoError(t, err)
+
+			m := setupWorktreeModel(t)
+			exec.Command("git", "worktree", "lock", m.BranchWorktrees["wt"]).Run()
+
+			m = sendKey(t, m, "y")
+			require.Equal(t, ui.StateLockedWorktreeConfirmation, m.State, "Locked worktrees need a second prompt")
+			assert.Contains(t, m.View(), "Locked Worktrees")
+
+			m = sendKey(t, m, answer)
+			assert.Equal(t, ui.StateDone, m.State)
+
+			branches, _ := git.ListBranches()
+			if answer == "y" {
+				assert.Empty(t, branches, "Locked worktree should be force removed and branch deleted")
+			} else {
+				assert.Equal(t, []string{"wt"}, branches, "Branch with locked worktree should be skipped")
+				assertResult(t, m, "wt", ui.ResultSkipped)
+			}
+		})
+	}
+}
+
+// TestWorktreeFlow_DirtyWorktreeNeedsDiscardKey tests that a worktree with uncommitted changes is highlighted
+// with its number of changed files, skipped when confirming with y, and only removed with D.
+func TestWorktreeFlow_DirtyWorktreeNeedsDiscardKey(t *testing.T) {
+	for _, answer := range []string{"y", "D"} {
+		t.Run(answer, func(t *testing.T) {
+			repo := setupTestRepo(t)
+
+			originalDir, _ := os.Getwd()
+			defer os.Chdir(originalDir)
+			require.NoError(t, os.Chdir(repo))
+
+			m := setupWorktreeModel(t)
+			require.Equal(t, ui.StateWorktreeConfirmation, m.State)
+			assert.NotContains(t, m.View(), "uncommitted changes", "A clean worktree should not be flagged")
+
+			// The changes are made after the first prompt, so going back and confirming again must find them
+			path := m.BranchWorktrees["wt"]
+			require.NoError(t, os.WriteFile(filepath.Join(path, "notes.txt"), []byte("todo\n"), 0o644))
+			require.NoError(t, os.WriteFile(filepath.Join(path, "draft.txt"), []byte("draft\n"), 0o644))
+			m = sendKey(t, m, "q")
+			m = sendKey(t, m, "d")
+			m = sendKey(t, m, "y")
+			require.Equal(t, ui.StateWorktreeConfirmation, m.State)
+
+			view := ansi.Strip(m.View())
+			assert.Contains(t, view, "2 modified or untracked file(s) would be lost")
+			assert.Contains(t, view, "Uncommitted changes in 1 worktree")
+			assert.Contains(t, view, "y: remove clean worktrees • D: discard changes and remove all")
+
+			m = sendKey(t, m, answer)
+			assert.Equal(t, ui.StateDone, m.State)
+			assert.False(t, m.HasFailures())
+
+			branches, _ := git.ListBranches()
+			if answer == "D" {
+				assert.Empty(t, branches, "The dirty worktree should be removed and its branch deleted")
+				assert.NoDirExists(t, path)
+			} else {
+				assert.Equal(t, []string{"wt"}, branches, "The branch of the dirty worktree should be skipped")
+				assertResult(t, m, "wt", ui.ResultSkipped)
+				assert.Contains(t, m.View(), "⊘ wt: skipped (worktree has uncommitted changes)")
+				assert.FileExists(t, filepath.Join(path, "notes.txt"))
+			}
+		})
+	}
+}
+
+// manyBranches returns n branch names "branch-000", "branch-001", ...
+func manyBranches(n int) []string {
+	branches := make([]string, n)
+	for i := range branches {
+		branches[i] = fmt.Sprintf("branch-%03d", i)
+	}
+	return branches
+}
+
+// TestScrolling_KeepsCursorVisible tests that the viewport follows the cursor.
+func TestScrolling_KeepsCursorVisible(t *testing.T) {
+	m := newTestModel(manyBranches(137)...)
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+	m = next.(ui.AppModel)
+
+	view := m.View()
+	assert.Contains(t, view, "branch-000")
+	assert.NotContains(t, view, "branch-050")
+	assert.Contains(t, view, "showing 1–11 of 137")
+	assert.LessOrEqual(t, len(strings.Split(view, "\n")), 20, "View should fit in the terminal")
+
+	for i := 0; i < 15; i++ {
+		m = sendKey(t, m, "j")
+	}
+	view = m.View()
+	assert.Contains(t, view, "> [ ] branch-015", "Cursor should stay visible")
+	assert.NotContains(t, view, "branch-000")
+	assert.Contains(t, view, "showing 6–16 of 137")
+}
+
+// TestScrolling_PageAndJumpKeys tests pgup/pgdown/home/end navigation.
+func TestScrolling_PageAndJumpKeys(t *testing.T) {
+	m := newTestModel(manyBranches(137)...)
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+	m = next.(ui.AppModel)
+
+	m = sendKey(t, m, "pgdown")
+	assert.Equal(t, 11, m.CursorIndex)
+
+	m = sendKey(t, m, "end")
+	assert.Equal(t, 136, m.CursorIndex)
+	assert.Contains(t, m.View(), "showing 127–137 of 137")
+
+	m = sendKey(t, m, "pgup")
+	assert.Equal(t, 125, m.CursorIndex)
+
+	m = sendKey(t, m, "home")
+	assert.Equal(t, 0, m.CursorIndex)
+	assert.Equal(t, 0, m.ScrollOffset)
+}
+
+// TestScrolling_Resize tests that resizing the terminal updates the viewport.
+func TestScrolling_Resize(t *testing.T) {
+	m := newTestModel(manyBranches(50)...)
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+	m = next.(ui.AppModel)
+	m = sendKey(t, m, "end")
+
+	next, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 100})
+	m = next.(ui.AppModel)
+	view := m.View()
+	assert.Contains(t, view, "branch-000", "All branches fit after growing the terminal")
+	assert.NotContains(t, view, "showing")
+
+	next, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 15})
+	m = next.(ui.AppModel)
+	assert.Contains(t, m.View(), "> [ ] branch-049", "Cursor should remain visible after shrinking")
+}
+
+// TestDeletion_ReportsProgress tests that branches are deleted one command at a time with live progress.
+func TestDeletion_ReportsProgress(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "first").Run()
+	exec.Command("git", "branch", "second").Run()
+
+	m := newTestModel("first", "second")
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = next.(ui.AppModel)
+	require.Equal(t, ui.StateDeleting, m.State)
+	assert.Contains(t, m.View(), "Deleting 1/2: first")
+
+	// Deliver only the first deletion result
+	var result tea.Msg
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg := c(); msg != nil {
+			if _, tick := msg.(spinner.TickMsg); !tick {
+				result = msg
+			}
+		}
+	}
+	next, cmd = m.Update(result)
+	m = next.(ui.AppModel)
+	assert.Equal(t, ui.StateDeleting, m.State)
+	assert.Equal(t, 1, m.DeletedCount)
+	assert.Contains(t, m.View(), "Deleting 2/2: second")
+
+	m = runCmds(m, cmd)
+	assert.Equal(t, ui.StateDone, m.State)
+	assert.Equal(t, 2, m.DeletedCount)
+}
+
+// TestSort_CyclesAndCursorFollowsBranch tests sorting by age with the s key.
+func TestSort_CyclesAndCursorFollowsBranch(t *testing.T) {
+	now := time.Now()
+	m := newTestModel("alpha", "beta", "gamma")
+	m.BranchDetails = map[string]git.BranchInfo{
+		"alpha": {Name: "alpha", LastCommitDate: now.Add(-1 * time.Hour)},
+		"beta":  {Name: "beta", LastCommitDate: now.Add(-72 * time.Hour)},
+		"gamma": {Name: "gamma", LastCommitDate: now.Add(-24 * time.Hour)},
+	}
+	m = sendKey(t, m, "j") // cursor on beta
+	m = sendKey(t, m, " ")
+
+	m = sendKey(t, m, "s")
+	assert.Equal(t, ui.SortOldestFirst, m.SortMode)
+	assert.Equal(t, []string{"beta", "gamma", "alpha"}, m.Branches)
+	assert.Equal(t, 0, m.CursorIndex, "Cursor should follow beta")
+	assert.True(t, m.Selected["beta"], "Selection should be preserved")
+	assert.Contains(t, m.View(), "sorted by oldest first")
+
+	m = sendKey(t, m, "s")
+	assert.Equal(t, ui.SortNewestFirst, m.SortMode)
+	assert.Equal(t, []string{"alpha", "gamma", "beta"}, m.Branches)
+	assert.Equal(t, 2, m.CursorIndex)
+
+	m = sendKey(t, m, "s")
+	assert.Equal(t, ui.SortByName, m.SortMode)
+	assert.Equal(t, []string{"alpha", "beta", "gamma"}, m.Branches)
+	assert.Equal(t, 1, m.CursorIndex)
+}
+
+// TestParseSortMode tests parsing the --sort flag values.
+func TestParseSortMode(t *testing.T) {
+	for value, want := range map[string]ui.SortMode{
+		"name": ui.SortByName,
+		"age":  ui.SortOldestFirst,
+		"-age": ui.SortNewestFirst,
+	} {
+		mode, err := ui.ParseSortMode(value)
+		assert.NoError(t, err)
+		assert.Equal(t, want, mode)
+		assert.Equal(t, value, mode.String())
+	}
+
+	_, err := ui.ParseSortMode("size")
+	assert.Error(t, err)
+}
+
+// TestDeletion_ShowsRestoreHints tests that deleted and force-deleted branches get a restore hint.
+func TestDeletion_ShowsRestoreHints(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "merged").Run()
+	exec.Command("git", "checkout", "-b", "unmerged").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "checkout", "-").Run()
+	mergedSHA, _ := git.GetBranchSHA("merged")
+	unmergedSHA, _ := git.GetBranchSHA("unmerged")
+
+	m := newTestModel("merged", "unmerged")
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateForceConfirmation, m.State)
+	m = sendKey(t, m, "y")
+	m = typeConfirmation(t, m, "delete")
+	require.Equal(t, ui.StateDone, m.State)
+
+	assert.Equal(t, []ui.DeletionResult{
+		{Branch: "merged", Status: ui.ResultDeleted, SHA: mergedSHA},
+		{Branch: "unmerged", Status: ui.ResultForceDeleted, SHA: unmergedSHA},
+	}, m.Results)
+	view := m.View()
+	assert.Contains(t, view, "✓ merged: deleted (was "+mergedSHA+" — restore with: git branch merged "+mergedSHA+")")
+	assert.Contains(t, view, "✓ unmerged: force-deleted (was "+unmergedSHA+" — restore with: git branch unmerged "+unmergedSHA+")")
+}
+
+// TestSelectionView_DetachedHead tests that the header explains detached HEAD state.
+func TestSelectionView_DetachedHead(t *testing.T) {
+	m := newTestModel("feature-a")
+	assert.NotContains(t, m.View(), "detached HEAD")
+
+	m.DetachedHead = true
+	assert.Contains(t, m.View(), "detached HEAD")
+}
+
+// TestSelectionView_Divergence tests that ahead/behind counts are shown next to each branch.
+func TestSelectionView_Divergence(t *testing.T) {
+	m := newTestModel("diverged", "same")
+	assert.NotRegexp(t, `↑\d+ ↓\d+`, m.View(), "Counts should be hidden when not computed")
+
+	m.Divergence = map[string]git.AheadBehind{
+		"diverged": {Ahead: 3, Behind: 12},
+		"same":     {Ahead: 0, Behind: 4},
+	}
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "↑3 ↓12")
+	assert.Contains(t, view, "↑0 ↓4")
+}
+
+// TestPreview_ShowsBranchCommits tests previewing the commits of the branch under the cursor.
+func TestPreview_ShowsBranchCommits(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	base, _ := git.GetCurrentBranch()
+	longSubject := "Subject " + strings.Repeat("very long ", 20)
+	exec.Command("git", "branch", "empty").Run()
+	exec.Command("git", "checkout", "-q", "-b", "feature").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", longSubject).Run()
+	exec.Command("git", "checkout", "-q", base).Run()
+
+	m := newTestModel("empty", "feature")
+	m.BaseBranch = base
+	m.Width = 60
+	m = sendKey(t, m, "j")
+	m = sendKey(t, m, "p")
+	require.True(t, m.PreviewOpen)
+
+	view := m.View()
+	assert.Contains(t, view, "Preview: feature")
+	assert.Contains(t, view, "Subject very long")
+	assert.Contains(t, view, "started by Test User • created "+time.Now().Format("2006-01-02"))
+	for _, line := range strings.Split(view, "\n") {
+		assert.LessOrEqual(t, lipgloss.Width(line), 60, "Long subjects must not overflow the terminal: %q", line)
+	}
+
+	m = sendKey(t, m, "esc")
+	assert.False(t, m.PreviewOpen)
+	assert.Equal(t, ui.StateSelection, m.State)
+
+	m = sendKey(t, m, "k")
+	m = sendKey(t, m, "p")
+	assert.Contains(t, m.View(), "Nothing on empty that is not in "+base)
+}
+
+// TestShowAuthors_LoadsVisibleBranches tests that the author column is filled in the background,
+// only for the branches scrolled into view.
+func TestShowAuthors_LoadsVisibleBranches(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	base, _ := git.GetCurrentBranch()
+	exec.Command("git", "branch", "empty").Run()
+	exec.Command("git", "branch", "hidden").Run()
+	exec.Command("git", "checkout", "-q", "-b", "feature").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Work", "--author", "Jane Doe <jane@example.com>").Run()
+	exec.Command("git", "checkout", "-q", base).Run()
+
+	m := newTestModel("empty", "feature", "hidden")
+	m.BaseBranch = base
+	m.BranchDetails = map[string]git.BranchInfo{"empty": {Name: "empty"}, "feature": {Name: "feature"}, "hidden": {Name: "hidden"}}
+	m.ShowAuthors = true
+	assert.Contains(t, ansi.Strip(m.View()), "…", "Authors are pending until looked up")
+
+	// Only two branches fit in the viewport
+	m.Height = 11
+	m = sendKey(t, m, "j")
+	assert.Equal(t, map[string]bool{"empty": true, "feature": true}, m.OriginsLoaded)
+	assert.Equal(t, "Jane Doe", m.BranchDetails["feature"].Creator)
+	assert.False(t, m.BranchDetails["feature"].CreatedAt.IsZero())
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "Jane Doe")
+	assert.Contains(t, view, "–", "A branch without commits of its own has no author")
+
+	m = sendKey(t, m, "j")
+	assert.True(t, m.OriginsLoaded["hidden"], "Branches scrolled into view are looked up")
+}
+
+// TestDeletion_CtrlCCancelsRun tests that ctrl+c while deleting lets the in-flight deletion finish,
+// skips the branches still queued and ends with a partial summary.
+func TestDeletion_CtrlCCancelsRun(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "first").Run()
+	exec.Command("git", "branch", "second").Run()
+
+	m := newTestModel("first", "second")
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = next.(ui.AppModel)
+	require.Equal(t, ui.StateDeleting, m.State)
+
+	// Cancel while the deletion of "first" is in flight
+	next, quit := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	m = next.(ui.AppModel)
+	assert.Nil(t, quit, "First ctrl+c should cancel rather than quit")
+	assert.True(t, m.Cancelled)
+	assert.Contains(t, m.View(), "Cancelling after first")
+
+	m = runCmds(m, cmd)
+	assert.Equal(t, ui.StateDone, m.State)
+	assert.Equal(t, 1, m.DeletedCount)
+	assertResult(t, m, "first", ui.ResultDeleted)
+	second, _ := m.Result("second")
+	assert.Equal(t, ui.DeletionResult{Branch: "second", Status: ui.ResultSkipped, Reason: "deletion cancelled"}, second)
+	view := m.View()
+	assert.Contains(t, view, "Deletion Cancelled")
+	assert.Contains(t, view, "1 deleted • 1 skipped • 0 failed")
+
+	// Only the branch in flight was deleted
+	output, _ := exec.Command("git", "branch", "--list", "first", "second").Output()
+	assert.NotContains(t, string(output), "first")
+	assert.Contains(t, string(output), "second")
+}
+
+// TestDeletion_SecondCtrlCQuits tests that ctrl+c while a cancellation is pending quits immediately.
+func TestDeletion_SecondCtrlCQuits(t *testing.T) {
+	m := newTestModel("first")
+	m.State = ui.StateDeleting
+	m.Cancelled = true
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.Quit(), cmd())
+}
+
+// TestCtrlC_QuitsFromConfirmation tests that ctrl+c on the confirmation screen quits without deleting,
+// rather than going back to the list.
+func TestCtrlC_QuitsFromConfirmation(t *testing.T) {
+	m := newTestModel("feature-a")
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	require.Equal(t, ui.StateConfirmation, m.State)
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	m = next.(ui.AppModel)
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.Quit(), cmd())
+	assert.True(t, m.WasCancelled())
+	assert.Zero(t, m.DeletedCount)
+}
+
+// TestCtrlC_CancelsAtForcePrompt tests that ctrl+c when asked to force delete cancels the run with a partial summary.
+func TestCtrlC_CancelsAtForcePrompt(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "merged").Run()
+	exec.Command("git", "checkout", "-b", "unmerged").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "checkout", "-").Run()
+
+	m := newTestModel("merged", "unmerged")
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateForceConfirmation, m.State)
+
+	m = sendKey(t, m, "ctrl+c")
+	assert.Equal(t, ui.StateDone, m.State)
+	assertResult(t, m, "merged", ui.ResultDeleted)
+	unmerged, _ := m.Result("unmerged")
+	assert.Equal(t, ui.ResultSkipped, unmerged.Status)
+	assert.Equal(t, "deletion cancelled", unmerged.Reason)
+	assert.Contains(t, m.View(), "Deletion Cancelled")
+	assert.True(t, git.BranchExists("unmerged"), "Unmerged branch should be kept")
+}
+
+// TestCtrlC_QuitsFromEveryScreen tests that ctrl+c quits from the screens opened from the list,
+// e.g. the rename prompt and the preview.
+func TestCtrlC_QuitsFromEveryScreen(t *testing.T) {
+	for _, open := range []string{"e", "p", "*", "/"} {
+		t.Run(open, func(t *testing.T) {
+			m := newTestModel("feature-a")
+			next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(open)})
+			m = next.(ui.AppModel)
+
+			_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+			require.NotNil(t, cmd)
+			assert.Equal(t, tea.Quit(), cmd())
+		})
+	}
+}
+
+// TestInterrupt_QuitsDuringDeletion tests that a signal while deleting skips the queued branches and quits.
+func TestInterrupt_QuitsDuringDeletion(t *testing.T) {
+	m := newTestModel("first", "second")
+	m.State = ui.StateDeleting
+	m.Queue = []string{"second"}
+
+	next, cmd := m.Update(ui.InterruptMsg{Signal: os.Interrupt})
+	m = next.(ui.AppModel)
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.Quit(), cmd())
+	assert.Equal(t, os.Interrupt, m.Interrupted)
+	assert.True(t, m.Cancelled)
+	assert.Zero(t, m.DeletedCount)
+}
+
+// TestWorktreeFlow_StaleWorktreePrunedAndRetried tests that a branch whose worktree directory
+// was deleted by hand is marked stale and deleted after pruning the worktree.
+func TestWorktreeFlow_StaleWorktreePrunedAndRetried(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "stale").Run()
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	require.NoError(t, exec.Command("git", "worktree", "add", worktreePath, "stale").Run())
+	require.NoError(t, os.RemoveAll(worktreePath))
+
+	m := newTestModel("stale")
+	m.StaleWorktrees = map[string]bool{"stale": true}
+	assert.Contains(t, m.View(), "[⎇ stale worktree]")
+
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	assert.Equal(t, ui.StateDone, m.State, "Stale worktrees should not need the removal prompt")
+	assert.Equal(t, 1, m.DeletedCount)
+	assert.False(t, m.HasFailures())
+
+	assert.False(t, git.BranchExists("stale"), "Branch should be deleted")
+}
+
+// TestCheckout_SwitchesAndQuits tests that c checks out the branch under the cursor and quits.
+func TestCheckout_SwitchesAndQuits(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "first").Run()
+	exec.Command("git", "branch", "second").Run()
+
+	m := newTestModel("first", "second")
+	assert.Contains(t, m.View(), "c: checkout")
+	m = sendKey(t, m, "j")
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m = next.(ui.AppModel)
+	require.NotNil(t, cmd)
+	next, cmd = m.Update(cmd())
+	m = next.(ui.AppModel)
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.Quit(), cmd(), "Program should quit after switching")
+	assert.Equal(t, "second", m.CheckedOut)
+	assert.Contains(t, m.View(), "Switched to branch second")
+
+	current, _ := git.GetCurrentBranch()
+	assert.Equal(t, "second", current)
+}
+
+// TestCheckout_FailureStaysInSelection tests that a failed checkout shows git's error without quitting.
+func TestCheckout_FailureStaysInSelection(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	setupDivergingFile(t)
+
+	m := newTestModel("other")
+	m = sendKey(t, m, "c")
+	assert.Equal(t, ui.StateSelection, m.State)
+	assert.Empty(t, m.CheckedOut)
+	assert.Contains(t, m.ErrorMsg, "local changes")
+	assert.Contains(t, m.View(), "Error: failed to switch to branch 'other'")
+
+	// The error is dismissed by the next key press
+	m = sendKey(t, m, "j")
+	assert.Empty(t, m.ErrorMsg)
+}
+
+// TestCheckout_RefusesWorktreeBranch tests that branches checked out in another worktree are refused.
+func TestCheckout_RefusesWorktreeBranch(t *testing.T) {
+	m := newTestModel("wt")
+	m.BranchWorktrees["wt"] = "/tmp/elsewhere"
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m = next.(ui.AppModel)
+	assert.Nil(t, cmd, "No checkout should be attempted")
+	assert.Contains(t, m.ErrorMsg, "checked out in the worktree at /tmp/elsewhere")
+}
+
+// TestRename_RenamesBranchInPlace tests that e renames the branch under the cursor, keeping its selection
+// and worktree annotation under the new name.
+func TestRename_RenamesBranchInPlace(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "plain").Run()
+	exec.Command("git", "branch", "wt").Run()
+	worktreePath := t.TempDir()
+	require.NoError(t, exec.Command("git", "worktree", "add", worktreePath, "wt").Run())
+
+	m := newTestModel("plain", "wt")
+	m.BranchWorktrees["wt"] = worktreePath
+	assert.Contains(t, m.View(), "e: rename")
+	m = sendKey(t, m, "j")
+	m = sendKey(t, m, " ")
+
+	m = sendKey(t, m, "e")
+	assert.Equal(t, "wt", m.Renaming)
+	assert.Equal(t, "wt", m.RenameInput.Value(), "The input should be prefilled with the branch name")
+	assert.Contains(t, m.View(), "Rename wt to:")
+
+	m = typeText(t, m, "-renamed")
+	m = sendKey(t, m, "enter")
+
+	assert.Empty(t, m.Renaming)
+	assert.Empty(t, m.ErrorMsg)
+	assert.Equal(t, []string{"plain", "wt-renamed"}, m.Branches)
+	assert.True(t, m.Selected["wt-renamed"], "The selection should follow the new name")
+	assert.False(t, m.Selected["wt"])
+	assert.Equal(t, worktreePath, m.BranchWorktrees["wt-renamed"], "The worktree annotation should follow the new name")
+	assert.Equal(t, 1, m.CursorIndex)
+
+	assert.True(t, git.BranchExists("wt-renamed"))
+	assert.False(t, git.BranchExists("wt"))
+	worktree, err := git.GetWorktreeForBranch("wt-renamed")
+	require.NoError(t, err)
+	require.NotNil(t, worktree)
+}
+
+// TestRename_RejectsInvalidNames tests that empty, taken and invalid names are refused with the input kept open,
+// and that esc cancels the rename.
+func TestRename_RejectsInvalidNames(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "first").Run()
+	exec.Command("git", "branch", "second").Run()
+
+	m := newTestModel("first", "second")
+	m = sendKey(t, m, "e")
+
+	for range "first" {
+		m = sendKey(t, m, "backspace")
+	}
+	m = sendKey(t, m, "enter")
+	assert.Contains(t, m.ErrorMsg, "must not be empty")
+	assert.Equal(t, "first", m.Renaming, "The input should stay open after an error")
+
+	m = typeText(t, m, "second")
+	m = sendKey(t, m, "enter")
+	assert.Contains(t, m.ErrorMsg, "a branch named 'second' already exists")
+
+	m = typeText(t, m, "..x")
+	m = sendKey(t, m, "enter")
+	assert.Contains(t, m.ErrorMsg, "'second..x' is not a valid branch name")
+	assert.Contains(t, m.View(), "Error: 'second..x' is not a valid branch name")
+
+	m = sendKey(t, m, "esc")
+	assert.Empty(t, m.Renaming)
+	assert.Empty(t, m.ErrorMsg)
+	assert.Equal(t, []string{"first", "second"}, m.Branches)
+	assert.True(t, git.BranchExists("first"), "Nothing should be renamed")
+}
+
+// TestDeletion_SummaryInSelectionOrder tests that the completion report lists every processed branch
+// in list order with its outcome, and that only failures count as failed.
+func TestDeletion_SummaryInSelectionOrder(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "checkout", "-b", "a-unmerged").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "checkout", "-").Run()
+	exec.Command("git", "branch", "b-merged").Run()
+
+	// "c-missing" doesn't exist, as if it was deleted from another terminal
+	m := newTestModel("a-unmerged", "b-merged", "c-missing")
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateForceConfirmation, m.State)
+	m = sendKey(t, m, "n")
+	require.Equal(t, ui.StateDone, m.State)
+
+	require.Len(t, m.Results, 3)
+	assert.Equal(t, []string{"a-unmerged", "b-merged", "c-missing"},
+		[]string{m.Results[0].Branch, m.Results[1].Branch, m.Results[2].Branch}, "Results should follow list order")
+	assertResult(t, m, "a-unmerged", ui.ResultSkipped)
+	assertResult(t, m, "b-merged", ui.ResultDeleted)
+	assertResult(t, m, "c-missing", ui.ResultAlreadyGone)
+	assert.False(t, m.HasFailures(), "A branch already deleted is not a failure")
+
+	view := m.View()
+	assert.Regexp(t, `(?s)⊘ a-unmerged: skipped.*✓ b-merged: deleted.*○ c-missing: already deleted outside gelete`, view)
+	assert.Contains(t, view, "1 deleted • 1 skipped • 0 failed")
+	assert.Contains(t, view, "1 branch was already deleted")
+	assert.NotContains(t, m.Branches, "c-missing", "Branches already deleted should leave the list")
+}
+
+// TestDeletion_SummaryShowsFirstErrorLine tests that failures with multi-line git errors take one line in the summary.
+func TestDeletion_SummaryShowsFirstErrorLine(t *testing.T) {
+	m := newTestModel("x")
+	m.State = ui.StateDone
+	m.Results = []ui.DeletionResult{{Branch: "x", Status: ui.ResultFailed, Reason: "first\nsecond"}}
+
+	view := m.View()
+	assert.Contains(t, view, "✗ x: failed: first")
+	assert.NotContains(t, view, "second")
+}
+
+// TestConfirmation_GroupsBranchesByRisk tests that the confirmation list is grouped by the pre-flight
+// analysis, riskiest first, and that the toggles follow the grouped order.
+func TestConfirmation_GroupsBranchesByRisk(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	base, _, err := git.GetHeadBranch()
+	require.NoError(t, err)
+	exec.Command("git", "branch", "a-merged").Run()
+	exec.Command("git", "checkout", "-q", "-b", "b-unmerged").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "checkout", "-q", base).Run()
+
+	m := newTestModel("a-merged", "b-unmerged")
+	m.Upstreams = map[string]git.Upstream{"b-unmerged": {Remote: "origin", Branch: "b-unmerged"}}
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	require.Equal(t, ui.StateConfirmation, m.State)
+	require.NotNil(t, m.Analysis, "The analysis should have completed")
+
+	view := m.View()
+	assert.Regexp(t, `(?s)Unmerged \(can only be force deleted\):.*b-unmerged.*Merged:.*a-merged`, view)
+	assert.NotContains(t, view, "Analyzing")
+
+	// The cursor stays on a-merged when the list is regrouped, so moving up reaches b-unmerged
+	m = sendKey(t, m, "k")
+	m = sendKey(t, m, "r")
+	assert.True(t, m.DeleteRemote["b-unmerged"], "Toggles should apply to the highlighted branch of the grouped list")
+}
+
+// TestConfirmation_AnalysisFailureIsUnknownStatus tests that branches stay deletable when the analysis fails.
+func TestConfirmation_AnalysisFailureIsUnknownStatus(t *testing.T) {
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(t.TempDir())
+	require.NoError(t, err)
+
+	m := newTestModel("feature")
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	require.Equal(t, ui.StateConfirmation, m.State)
+
+	view := m.View()
+	assert.Contains(t, view, "Could not analyze the branches")
+	assert.Regexp(t, `(?s)Unknown status:.*feature`, view)
+	assert.Contains(t, view, "y: confirm")
+}
+
+// TestConfirmation_WarnsAboutTagsOnBranchCommits tests that tags and notes on the commits only an unmerged branch
+// has are named on the confirmation screen, and that the branch then needs the force confirmation.
+func TestConfirmation_WarnsAboutTagsOnBranchCommits(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	base, _, err := git.GetHeadBranch()
+	require.NoError(t, err)
+	exec.Command("git", "checkout", "-q", "-b", "release-candidate").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "tag", "v0.9-rc1").Run()
+	exec.Command("git", "notes", "add", "-m", "Reviewed").Run()
+	exec.Command("git", "checkout", "-q", base).Run()
+
+	skipped := newTestModel("release-candidate")
+	skipped.SkipRefCheck = true
+	skipped = sendKey(t, skipped, " ")
+	skipped = sendKey(t, skipped, "d")
+	assert.NotContains(t, skipped.View(), "contains", "--skip-ref-check should skip the check")
+
+	m := newTestModel("release-candidate")
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	require.Equal(t, ui.StateConfirmation, m.State)
+	assert.Contains(t, m.View(), "contains 1 tag(s): v0.9-rc1 and 1 note(s) on commits only this branch has; needs force delete")
+
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateForceConfirmation, m.State)
+	assert.Contains(t, m.View(), "contains 1 tag(s): v0.9-rc1 and 1 note(s)")
+
+	m = sendKey(t, m, "n")
+	assertResult(t, m, "release-candidate", ui.ResultSkipped)
+	assert.True(t, git.BranchExists("release-candidate"), "Declining the force confirmation should keep the branch")
+}
+
+// TestRestore_UndoDeletedBranches tests restoring branches from the done screen,
+// and that a branch recreated in the meantime is reported instead of being overwritten.
+func TestRestore_UndoDeletedBranches(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "a").Run()
+	exec.Command("git", "branch", "b").Run()
+
+	m := newTestModel("a", "b")
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateDone, m.State)
+	require.False(t, git.BranchExists("a"))
+	assert.Contains(t, m.View(), "u: undo")
+
+	// "b" is recreated at another commit before restoring
+	exec.Command("git", "commit", "--allow-empty", "-m", "Newer commit").Run()
+	exec.Command("git", "branch", "b").Run()
+	recreated, _ := exec.Command("git", "rev-parse", "b").Output()
+
+	m = sendKey(t, m, "u")
+	require.Equal(t, ui.StateRestore, m.State)
+	view := m.View()
+	assert.Contains(t, view, "Restore Deleted Branches")
+	assert.Contains(t, view, m.Results[0].SHA)
+
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "j")
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "enter")
+	require.Equal(t, ui.StateDone, m.State)
+
+	assert.True(t, git.BranchExists("a"), "a should be restored")
+	assert.True(t, m.Restored["a"])
+	assert.Contains(t, m.RestoreFailures["b"], "already exists")
+	current, _ := exec.Command("git", "rev-parse", "b").Output()
+	assert.Equal(t, string(recreated), string(current), "The recreated branch must not be overwritten")
+
+	view = m.View()
+	assert.Contains(t, view, "↺ a: restored at")
+	assert.Contains(t, view, "✓ b: deleted")
+	assert.Contains(t, view, "Failed to restore 1 branch")
+	assert.Contains(t, view, "u: undo", "b can still be restored once the new branch is gone")
+}
+
+// TestDeletion_DeclinedForceIsNotAFailure tests that intentionally skipped branches don't fail the run.
+func TestDeletion_DeclinedForceIsNotAFailure(t *testing.T) {
+	m := newTestModel("feature")
+	m.Results = []ui.DeletionResult{{Branch: "feature", Status: ui.ResultSkipped, Reason: "not fully merged, force delete declined"}}
+	assert.False(t, m.HasFailures())
+}
+
+// TestForceConfirmation_WarnsAboutDanglingCommits tests that force confirmation warns about unmerged
+// branches whose commits no other ref contains, and not about branches another ref still contains.
+func TestForceConfirmation_WarnsAboutDanglingCommits(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	base, err := git.GetCurrentBranch()
+	require.NoError(t, err)
+	exec.Command("git", "checkout", "-b", "lonely").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Only on lonely").Run()
+	exec.Command("git", "checkout", "-b", "shared").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "On shared").Run()
+	exec.Command("git", "branch", "shared-copy").Run()
+	exec.Command("git", "checkout", base).Run()
+
+	m := newTestModel("lonely", "shared")
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateForceConfirmation, m.State)
+
+	assert.Equal(t, ui.TipReachable, m.TipReachability["lonely"], "lonely is contained in shared")
+	assert.Equal(t, ui.TipReachable, m.TipReachability["shared"], "shared is contained in shared-copy")
+
+	exec.Command("git", "branch", "-D", "shared-copy").Run()
+	m = newTestModel("shared")
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	m = sendKey(t, m, "y")
+	require.Equal(t, ui.StateForceConfirmation, m.State)
+	assert.Equal(t, ui.TipUnreachable, m.TipReachability["shared"])
+	assert.Contains(t, m.View(), "not reachable from any other ref and will become dangling")
+}
+
+// TestSelectionView_FlagsMissingCommitDate tests that branches without a commit date are flagged.
+func TestSelectionView_FlagsMissingCommitDate(t *testing.T) {
+	m := newTestModel("dated", "undated")
+	m.BranchDetails = map[string]git.BranchInfo{
+		"dated":   {Name: "dated", LastCommitDate: time.Now()},
+		"undated": {Name: "undated"},
+	}
+
+	for _, line := range strings.Split(ansi.Strip(m.View()), "\n") {
+		switch {
+		case strings.Contains(line, "undated"):
+			assert.Contains(t, line, "no commit date")
+		case strings.Contains(line, "dated"):
+			assert.NotContains(t, line, "no commit date")
+		}
+	}
+}
+
+// TestGroupedView_SelectAndCollapseGroups tests grouping branches by prefix, selecting a whole group from its header
+// and collapsing it, with the selection kept when switching back to the flat view.
+func TestGroupedView_SelectAndCollapseGroups(t *testing.T) {
+	m := newTestModel("bugfix/b1", "feature/f1", "feature/f2", "hotfix", "release/1.0")
+
+	m = sendKey(t, m, "g")
+	assert.True(t, m.Grouped)
+	assert.Equal(t, 1, m.CursorIndex, "The cursor should stay on bugfix/b1, below its group header")
+
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "▾ bugfix")
+	assert.Contains(t, view, "▾ feature (0/2 selected)")
+	assert.Less(t, strings.Index(view, "release/1.0"), strings.Index(view, "(other)"), "Branches without a slash should be grouped last")
+	assert.Less(t, strings.Index(view, "(other)"), strings.Index(view, "hotfix"))
+
+	// Space on a group header selects the whole group, a second press deselects it
+	m = sendKey(t, m, "j")
+	m = sendKey(t, m, " ")
+	assert.Equal(t, map[string]bool{"feature/f1": true, "feature/f2": true}, m.Selected)
+	assert.Contains(t, ansi.Strip(m.View()), "▾ feature (2/2 selected)")
+
+	// Enter on a group header collapses it without changing the selection
+	m = sendKey(t, m, "enter")
+	view = ansi.Strip(m.View())
+	assert.Contains(t, view, "▸ feature (2/2 selected)")
+	assert.NotContains(t, view, "feature/f1")
+	assert.Equal(t, map[string]bool{"feature/f1": true, "feature/f2": true}, m.Selected, "Collapsing should not change the selection")
+
+	// Switching back to the flat view keeps the selection and puts the cursor on the group's first branch
+	m = sendKey(t, m, "g")
+	assert.False(t, m.Grouped)
+	assert.Contains(t, ansi.Strip(m.View()), "2/5 selected")
+	m = sendKey(t, m, " ")
+	assert.Equal(t, map[string]bool{"feature/f1": false, "feature/f2": true}, m.Selected)
+
+	// Returning to the grouped view puts the cursor on the header of the collapsed group
+	m = sendKey(t, m, "g")
+	m = sendKey(t, m, "enter")
+	assert.Contains(t, ansi.Strip(m.View()), "▾ feature (1/2 selected)")
+}
+
+// TestForceConfirmation_SquashMergedBranches tests that squash-merged branches get their own annotation,
+// and a softer force deletion prompt when they are the only unmerged branches.
+func TestForceConfirmation_SquashMergedBranches(t *testing.T) {
+	m := newTestModel("feature/squashed", "feature/unmerged")
+	m.BaseBranch = "main"
+	m.MergedBranches = map[string]bool{}
+	m.SquashMerged = map[string]bool{"feature/squashed": true}
+
+	view := ansi.Strip(m.View())
+	assert.Regexp(t, `feature/squashed\s+squashed`, view)
+	assert.Regexp(t, `feature/unmerged\s+unmerged`, view)
+
+	m.State = ui.StateForceConfirmation
+	m.UnmergedBranches = map[string]string{"feature/squashed": "not fully merged"}
+	view = ansi.Strip(m.View())
+	assert.Contains(t, view, "Branches Merged via Squash")
+	assert.Contains(t, view, "content appears merged via squash into main")
+	assert.NotContains(t, view, "cannot be undone")
+
+	m.UnmergedBranches["feature/unmerged"] = "not fully merged"
+	view = ansi.Strip(m.View())
+	assert.Contains(t, view, "Unmerged Branches Detected")
+	assert.Contains(t, view, "content appears merged via squash into main")
+	assert.Contains(t, view, "permanently remove 1 unmerged branch")
+}
+
+// TestHeader_ShowsRepositoryContext tests the header with the repository, current branch, operation in progress,
+// base branch and branch count on every screen, and that long repository paths are truncated from the left.
+func TestHeader_ShowsRepositoryContext(t *testing.T) {
+	m := newTestModel("feature-a", "feature-b")
+	m.RepoPath = "/home/user/src/very/deeply/nested/projects/gelete"
+	m.CurrentBranch = "develop"
+	m.BaseBranch = "main"
+
+	header := strings.Split(ansi.Strip(m.View()), "\n")[0]
+	assert.Equal(t, "/home/user/src/very/deeply/nested/projects/gelete • on develop • base main • 2 branches", header)
+
+	m.State = ui.StateDone
+	assert.True(t, strings.HasPrefix(ansi.Strip(m.View()), "/home/user"), "The header should be shown in every state")
+
+	m.Width = 60
+	m.CurrentBranch = ""
+	header = strings.Split(ansi.Strip(m.View()), "\n")[0]
+	assert.Equal(t, 60, lipgloss.Width(header))
+	assert.True(t, strings.HasPrefix(header, "…"), "Long paths should be truncated from the left: %s", header)
+	assert.Contains(t, header, "projects/gelete • detached HEAD • base main")
+
+	m.Width = 0
+	m.RepoPath = "~/src/app/vendor/lib"
+	m.SuperprojectPath = "~/src/app"
+	header = strings.Split(ansi.Strip(m.View()), "\n")[0]
+	assert.True(t, strings.HasPrefix(header, "~/src/app/vendor/lib (submodule of ~/src/app) • detached HEAD"), header)
+
+	// Started with --force-start during a rebase
+	m.RepositoryState = git.StateRebase
+	header = strings.Split(ansi.Strip(m.View()), "\n")[0]
+	assert.Contains(t, header, "• detached HEAD • ⚠ rebase in progress • base main", header)
+}
+
+// TestWasCancelled tests that quitting with selected branches or cancelling a deletion run counts as cancelled,
+// unlike quitting without a selection or after the run finished.
+func TestWasCancelled(t *testing.T) {
+	m := newTestModel("feature-a", "feature-b")
+	assert.False(t, sendKey(t, m, "q").WasCancelled(), "Quitting without a selection is not a cancellation")
+
+	m = sendKey(t, m, " ")
+	assert.True(t, sendKey(t, m, "q").WasCancelled())
+
+	m.State = ui.StateDone
+	assert.False(t, m.WasCancelled())
+
+	m.Cancelled = true
+	assert.True(t, m.WasCancelled())
+}
+
+// TestForceMode_SkipsForceConfirmation tests that the f key turns the confirmation into a force deletion
+// confirmation, and that force mode deletes unmerged branches without a second prompt.
+func TestForceMode_SkipsForceConfirmation(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "merged").Run()
+	exec.Command("git", "checkout", "-b", "unmerged").Run()
+	exec.Command("git", "commit", "--allow-empty", "-m", "Unmerged commit").Run()
+	exec.Command("git", "checkout", "-").Run()
+
+	m := newTestModel("merged", "unmerged")
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+	require.Equal(t, ui.StateConfirmation, m.State)
+	assert.NotContains(t, ansi.Strip(m.View()), "FORCE")
+
+	m = sendKey(t, m, "f")
+	require.True(t, m.ForceMode)
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "Are you sure you want to FORCE delete these branches?")
+	assert.Contains(t, view, "f: delete safely instead")
+
+	m = sendKey(t, m, "y")
+	m = typeConfirmation(t, m, "delete")
+	require.Equal(t, ui.StateDone, m.State, "Force mode should not prompt for unmerged branches")
+	assertResult(t, m, "merged", ui.ResultForceDeleted)
+	assertResult(t, m, "unmerged", ui.ResultForceDeleted)
+
+	branches, err := git.ListBranches()
+	require.NoError(t, err)
+	assert.Empty(t, branches)
+}
+
+// TestDeletion_LiveResultLog tests that the deleting view lists results as they come in,
+// keeping the most recent ones visible when they don't fit the terminal.
+func TestDeletion_LiveResultLog(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	branches := []string{"b1", "b2", "b3", "b4", "b5"}
+	for _, branch := range branches {
+		exec.Command("git", "branch", branch).Run()
+	}
+
+	m := newTestModel(branches...)
+	m.Height = 10
+	m = sendKey(t, m, "a")
+	m = sendKey(t, m, "d")
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = next.(ui.AppModel)
+
+	// Process the deletions one message at a time, as the program would
+	var views []string
+	pending := []tea.Cmd{cmd}
+	for len(pending) > 0 && m.State == ui.StateDeleting {
+		cmd, pending = pending[0], pending[1:]
+		if cmd == nil {
+			continue
+		}
+		switch msg := cmd().(type) {
+		case nil, spinner.TickMsg:
+		case tea.BatchMsg:
+			pending = append(pending, msg...)
+		default:
+			next, nextCmd := m.Update(msg)
+			m = next.(ui.AppModel)
+			pending = append(pending, nextCmd)
+			views = append(views, ansi.Strip(m.View()))
+		}
+	}
+
+	require.Equal(t, ui.StateDone, m.State, "The run should finish by itself once the last result arrives")
+	require.GreaterOrEqual(t, len(views), 4)
+	assert.Contains(t, views[0], "✓ b1: deleted")
+	assert.Contains(t, views[0], "Deleting 2/5: b2")
+
+	last := views[3]
+	assert.Contains(t, last, "✓ b4: deleted")
+	assert.Contains(t, last, "… 1 earlier result\n")
+	assert.NotContains(t, last, "✓ b1: deleted", "Older results should scroll out of the viewport")
+	assert.Len(t, m.ResultLog, 5)
+}
+
+// TestConfirmation_WarnsAboutDefaultBranch tests that the confirmation names the default branch
+// when it is selected, which is only listed when its protection was lifted.
+func TestConfirmation_WarnsAboutDefaultBranch(t *testing.T) {
+	m := newTestModel("feature-a", "trunk")
+	m.DefaultBranch = "trunk"
+
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	assert.NotContains(t, ansi.Strip(m.View()), "DEFAULT branch")
+
+	m = sendKey(t, m, "n")
+	m = sendKey(t, m, "j")
+	m = sendKey(t, m, " ")
+	m = sendKey(t, m, "d")
+	require.Equal(t, ui.StateConfirmation, m.State)
+	assert.Contains(t, ansi.Strip(m.View()), "⚠ 'trunk' is the DEFAULT branch of this repository")
+}
@@ -0,0 +1,128 @@
+package unit
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseVersion tests parsing the output of git --version from various platforms and builds.
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   git.Version
+	}{
+		{"git version 2.43.0\n", git.Version{Major: 2, Minor: 43}},
+		{"git version 2.39.3 (Apple Git-146)", git.Version{Major: 2, Minor: 39, Patch: 3}},
+		{"git version 2.45.1.windows.1", git.Version{Major: 2, Minor: 45, Patch: 1}},
+		{"git version 2.40.0.vfs.0.0", git.Version{Major: 2, Minor: 40}},
+		{"git version 1.8.3.1", git.Version{Major: 1, Minor: 8, Patch: 3}},
+		{"git version 2.17.1", git.Version{Major: 2, Minor: 17, Patch: 1}},
+		{"git version 2.44.0-rc2", git.Version{Major: 2, Minor: 44}},
+		{"git version 2.45.GIT", git.Version{Major: 2, Minor: 45}},
+		{"git version 2.46.0.rc0.106.gd4ca5be93b", git.Version{Major: 2, Minor: 46}},
+		{"2.22", git.Version{Major: 2, Minor: 22}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			version, err := git.ParseVersion(tt.output)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, version)
+		})
+	}
+
+	for _, invalid := range []string{"", "git version", "git version unknown", "hub version 2.14.2"} {
+		_, err := git.ParseVersion(invalid)
+		assert.Error(t, err, "%q should not parse", invalid)
+	}
+}
+
+// TestVersion_Less tests the ordering of versions.
+func TestVersion_Less(t *testing.T) {
+	v := func(major, minor, patch int) git.Version {
+		return git.Version{Major: major, Minor: minor, Patch: patch}
+	}
+
+	assert.True(t, v(1, 8, 3).Less(v(2, 21, 0)))
+	assert.True(t, v(2, 20, 9).Less(v(2, 21, 0)))
+	assert.True(t, v(2, 21, 0).Less(v(2, 21, 1)))
+	assert.False(t, v(2, 21, 0).Less(v(2, 21, 0)))
+	assert.False(t, v(3, 0, 0).Less(v(2, 45, 1)))
+	assert.Equal(t, "2.39.3", v(2, 39, 3).String())
+}
+
+// installOldGit puts a fake git in front of PATH that reports version and, like git before 2.22 and 2.23,
+// knows neither `git branch --show-current` nor `git switch`. Other commands run the real git.
+func installOldGit(t *testing.T, version string) {
+	t.Helper()
+
+	realGit, err := exec.LookPath("git")
+	require.NoError(t, err)
+
+	script := fmt.Sprintf(`#!/bin/sh
+case "$*" in
+--version) echo "git version %s"; exit 0 ;;
+"branch --show-current") echo "error: unknown option 'show-current'" >&2; exit 129 ;;
+switch*) echo "git: 'switch' is not a git command. See 'git --help'." >&2; exit 1 ;;
+esac
+exec %s "$@"
+`, version, realGit)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "git"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// TestCheckVersion tests that a git older than the minimum is refused with a single clear error.
+func TestCheckVersion(t *testing.T) {
+	installOldGit(t, "2.17.1")
+
+	err := git.CheckVersion("2.22")
+	require.Error(t, err)
+	assert.Equal(t, "gelete requires git >= 2.22, found 2.17.1", err.Error())
+	assert.EqualError(t, git.ValidateEnvironment(), "gelete requires git >= 2.21, found 2.17.1")
+	assert.NoError(t, git.CheckVersion("2.17"))
+
+	installOldGit(t, "2.39.3 (Apple Git-146)")
+	assert.NoError(t, git.ValidateEnvironment())
+}
+
+// TestOldGit_Fallbacks tests that the current branch and checking out work without --show-current and switch.
+func TestOldGit_Fallbacks(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	exec.Command("git", "branch", "-M", "main").Run()
+	exec.Command("git", "branch", "feature").Run()
+	installOldGit(t, "2.21.0")
+
+	branch, detached, err := git.GetHeadBranch()
+	require.NoError(t, err)
+	assert.Equal(t, "main", branch)
+	assert.False(t, detached)
+
+	require.NoError(t, git.CheckoutBranch("feature"))
+	current, err := git.GetCurrentBranch()
+	require.NoError(t, err)
+	assert.Equal(t, "feature", current)
+
+	require.NoError(t, exec.Command("git", "checkout", "-q", "--detach").Run())
+	branch, detached, err = git.GetHeadBranch()
+	require.NoError(t, err)
+	assert.Empty(t, branch)
+	assert.True(t, detached)
+
+	err = git.CheckoutBranch("missing")
+	assert.Error(t, err)
+}
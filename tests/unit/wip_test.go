@@ -0,0 +1,108 @@
+package unit
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commitOnNewBranch creates a branch off HEAD with an empty commit per subject, then switches back
+func commitOnNewBranch(t *testing.T, repo, branch string, subjects ...string) {
+	t.Helper()
+	require.NoError(t, exec.Command("git", "-C", repo, "switch", "-q", "-c", branch).Run())
+	for _, subject := range subjects {
+		require.NoError(t, exec.Command("git", "-C", repo, "commit", "-q", "--allow-empty", "-m", subject).Run())
+	}
+	require.NoError(t, exec.Command("git", "-C", repo, "switch", "-q", "-").Run())
+}
+
+// setupWIPRepo creates a repository with WIP branches and branches with real work, and changes into it
+func setupWIPRepo(t *testing.T) {
+	t.Helper()
+	repo := setupTestRepo(t)
+	commitOnNewBranch(t, repo, "wip-login", "wip", "WIP: more", "fixup! wip")
+	commitOnNewBranch(t, repo, "stashed", "WIP on main: 1a2b3c4 Initial commit")
+	commitOnNewBranch(t, repo, "feature", "wip", "Add the login form")
+	commitOnNewBranch(t, repo, "empty")
+
+	originalDir, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(originalDir) })
+	require.NoError(t, os.Chdir(repo))
+}
+
+// TestIsWIPBranch tests that only branches whose own commits all match the WIP patterns are WIP.
+func TestIsWIPBranch(t *testing.T) {
+	setupWIPRepo(t)
+	patterns, err := git.CompileWIPPatterns(git.DefaultWIPPatterns)
+	require.NoError(t, err)
+
+	tests := []struct {
+		branch string
+		want   bool
+	}{
+		{"wip-login", true},
+		{"stashed", true},
+		{"feature", false},
+		{"empty", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			isWIP, err := git.IsWIPBranch(git.BranchRef(tt.branch), "HEAD", patterns)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, isWIP)
+		})
+	}
+
+	custom, err := git.CompileWIPPatterns([]string{"^add "})
+	require.NoError(t, err)
+	isWIP, err := git.IsWIPBranch(git.BranchRef("wip-login"), "HEAD", custom)
+	require.NoError(t, err)
+	assert.False(t, isWIP, "Custom patterns replace the default ones")
+
+	_, err = git.CompileWIPPatterns([]string{"^(wip"})
+	assert.ErrorContains(t, err, "invalid WIP pattern '^(wip'")
+}
+
+// TestSelectWIP tests that the w key looks for WIP branches once, badges them and selects the visible ones.
+func TestSelectWIP(t *testing.T) {
+	setupWIPRepo(t)
+
+	m := newTestModel("empty", "feature", "stashed", "wip-login")
+	assert.Contains(t, m.View(), "w: select WIP")
+	assert.Nil(t, m.WIPBranches, "WIP branches are only looked for on request")
+
+	m.FilterQuery = "i"
+	m = sendKey(t, m, "w")
+	assert.False(t, m.DetectingWIP)
+	assert.Equal(t, map[string]bool{"stashed": true, "wip-login": true}, m.WIPBranches)
+	assert.Equal(t, map[string]bool{"wip-login": true}, m.Selected, "Only visible branches are selected")
+	assert.Equal(t, "selected 1 WIP branch", m.SuccessMsg)
+	assert.Regexp(t, `wip-login.* wip`, m.View())
+
+	m.FilterQuery = ""
+	m.WIPBranches["feature"] = true
+	m = sendKey(t, m, "w")
+	assert.True(t, m.Selected["feature"], "Later presses reuse the WIP branches found the first time")
+	assert.Equal(t, "selected 3 WIP branches", m.SuccessMsg)
+}
+
+// TestSelectWIP_OnLoad tests that SelectWIP selects the WIP branches once the branches are loaded.
+func TestSelectWIP_OnLoad(t *testing.T) {
+	setupWIPRepo(t)
+
+	m := newTestModel()
+	m.SelectWIP = true
+	m.Loader = func() (ui.BranchData, error) {
+		return ui.BranchData{Branches: []string{"feature", "wip-login"}}, nil
+	}
+	m = ui.NewLoadingModel(m)
+	m = runCmds(m, m.Init())
+
+	require.Equal(t, ui.StateSelection, m.State)
+	assert.Equal(t, map[string]bool{"wip-login": true}, m.Selected)
+}
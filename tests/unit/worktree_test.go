@@ -4,9 +4,13 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/Kdaito/gelete/internal/git"
+	"github.com/Kdaito/gelete/internal/i18n"
+	"github.com/Kdaito/gelete/internal/ui"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -15,13 +19,10 @@ import (
 func TestListWorktrees_NoWorktrees(t *testing.T) {
 	repo := setupTestRepo(t)
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
+	r := git.Repository{Dir: repo}
 
 	// List worktrees (should include main worktree only)
-	worktrees, err := git.ListWorktrees()
+	worktrees, err := r.ListWorktrees()
 	assert.NoError(t, err, "ListWorktrees should succeed")
 	// Main repository is also a worktree
 	assert.GreaterOrEqual(t, len(worktrees), 1, "Should have at least main worktree")
@@ -31,21 +32,18 @@ func TestListWorktrees_NoWorktrees(t *testing.T) {
 func TestListWorktrees_WithWorktrees(t *testing.T) {
 	repo := setupTestRepo(t)
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
+	r := git.Repository{Dir: repo}
 
 	// Create a worktree
-	exec.Command("git", "branch", "test-wt").Run()
+	exec.Command("git", "-C", repo, "branch", "test-wt").Run()
 	worktreePath := t.TempDir()
-	exec.Command("git", "worktree", "add", worktreePath, "test-wt").Run()
+	exec.Command("git", "-C", repo, "worktree", "add", worktreePath, "test-wt").Run()
 
 	// Resolve symlinks in expected path for comparison
 	expectedPath, _ := filepath.EvalSymlinks(worktreePath)
 
 	// List worktrees
-	worktrees, err := git.ListWorktrees()
+	worktrees, err := r.ListWorktrees()
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, len(worktrees), 2, "Should have main + test worktree")
 
@@ -56,35 +54,35 @@ func TestListWorktrees_WithWorktrees(t *testing.T) {
 			found = true
 			assert.Equal(t, expectedPath, wt.Path)
 			assert.False(t, wt.Locked, "New worktree should not be locked")
+			assert.False(t, wt.Missing, "Existing worktree should not be flagged as missing")
+			assert.False(t, wt.Main, "Linked worktrees should not be flagged as main")
 			break
 		}
 	}
 	assert.True(t, found, "test-wt should be in worktree list")
+	assert.True(t, worktrees[0].Main, "The main worktree should be listed first")
 
 	// Cleanup
-	exec.Command("git", "worktree", "remove", worktreePath).Run()
+	exec.Command("git", "-C", repo, "worktree", "remove", worktreePath).Run()
 }
 
 // TestRemoveWorktree_Success tests successful worktree removal.
 func TestRemoveWorktree_Success(t *testing.T) {
 	repo := setupTestRepo(t)
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
+	r := git.Repository{Dir: repo}
 
 	// Create a worktree
-	exec.Command("git", "branch", "test-rm").Run()
+	exec.Command("git", "-C", repo, "branch", "test-rm").Run()
 	worktreePath := t.TempDir()
-	exec.Command("git", "worktree", "add", worktreePath, "test-rm").Run()
+	exec.Command("git", "-C", repo, "worktree", "add", worktreePath, "test-rm").Run()
 
 	// Remove worktree
-	err = git.RemoveWorktree(worktreePath)
+	err := r.RemoveWorktree(worktreePath)
 	assert.NoError(t, err, "RemoveWorktree should succeed")
 
 	// Verify removal
-	worktrees, _ := git.ListWorktrees()
+	worktrees, _ := r.ListWorktrees()
 	for _, wt := range worktrees {
 		assert.NotEqual(t, "test-rm", wt.Branch, "Removed worktree should not be listed")
 	}
@@ -94,45 +92,39 @@ func TestRemoveWorktree_Success(t *testing.T) {
 func TestRemoveWorktree_LockedWorktree(t *testing.T) {
 	repo := setupTestRepo(t)
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
+	r := git.Repository{Dir: repo}
 
 	// Create and lock a worktree
-	exec.Command("git", "branch", "test-locked").Run()
+	exec.Command("git", "-C", repo, "branch", "test-locked").Run()
 	worktreePath := t.TempDir()
-	exec.Command("git", "worktree", "add", worktreePath, "test-locked").Run()
-	exec.Command("git", "worktree", "lock", worktreePath).Run()
+	exec.Command("git", "-C", repo, "worktree", "add", worktreePath, "test-locked").Run()
+	exec.Command("git", "-C", repo, "worktree", "lock", worktreePath).Run()
 
 	// Attempt to remove locked worktree
-	err = git.RemoveWorktree(worktreePath)
+	err := r.RemoveWorktree(worktreePath)
 	assert.Error(t, err, "RemoveWorktree should fail for locked worktree")
 
 	// Cleanup with force
-	exec.Command("git", "worktree", "remove", "--force", worktreePath).Run()
+	exec.Command("git", "-C", repo, "worktree", "remove", "--force", worktreePath).Run()
 }
 
 // TestForceRemoveWorktree_Success tests force removal of worktree.
 func TestForceRemoveWorktree_Success(t *testing.T) {
 	repo := setupTestRepo(t)
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
+	r := git.Repository{Dir: repo}
 
 	// Create a worktree
-	exec.Command("git", "branch", "test-force").Run()
+	exec.Command("git", "-C", repo, "branch", "test-force").Run()
 	worktreePath := t.TempDir()
-	exec.Command("git", "worktree", "add", worktreePath, "test-force").Run()
+	exec.Command("git", "-C", repo, "worktree", "add", worktreePath, "test-force").Run()
 
 	// Force remove worktree
-	err = git.ForceRemoveWorktree(worktreePath)
+	err := r.ForceRemoveWorktree(worktreePath)
 	assert.NoError(t, err, "ForceRemoveWorktree should succeed")
 
 	// Verify removal
-	worktrees, _ := git.ListWorktrees()
+	worktrees, _ := r.ListWorktrees()
 	for _, wt := range worktrees {
 		assert.NotEqual(t, "test-force", wt.Branch, "Removed worktree should not be listed")
 	}
@@ -142,23 +134,20 @@ func TestForceRemoveWorktree_Success(t *testing.T) {
 func TestForceRemoveWorktree_LockedWorktree(t *testing.T) {
 	repo := setupTestRepo(t)
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	err := os.Chdir(repo)
-	require.NoError(t, err)
+	r := git.Repository{Dir: repo}
 
 	// Create and lock a worktree
-	exec.Command("git", "branch", "test-force-locked").Run()
+	exec.Command("git", "-C", repo, "branch", "test-force-locked").Run()
 	worktreePath := t.TempDir()
-	exec.Command("git", "worktree", "add", worktreePath, "test-force-locked").Run()
-	exec.Command("git", "worktree", "lock", worktreePath).Run()
+	exec.Command("git", "-C", repo, "worktree", "add", worktreePath, "test-force-locked").Run()
+	exec.Command("git", "-C", repo, "worktree", "lock", worktreePath).Run()
 
 	// Force remove should succeed even if locked
-	err = git.ForceRemoveWorktree(worktreePath)
+	err := r.ForceRemoveWorktree(worktreePath)
 	assert.NoError(t, err, "ForceRemoveWorktree should succeed for locked worktree")
 
 	// Verify removal
-	worktrees, _ := git.ListWorktrees()
+	worktrees, _ := r.ListWorktrees()
 	for _, wt := range worktrees {
 		assert.NotEqual(t, "test-force-locked", wt.Branch, "Removed worktree should not be listed")
 	}
@@ -168,12 +157,345 @@ func TestForceRemoveWorktree_LockedWorktree(t *testing.T) {
 func TestRemoveWorktree_NonExistent(t *testing.T) {
 	repo := setupTestRepo(t)
 
+	r := git.Repository{Dir: repo}
+
+	// Attempt to remove non-existent worktree
+	err := r.RemoveWorktree("/path/does/not/exist")
+	assert.Error(t, err, "RemoveWorktree should fail for non-existent worktree")
+}
+
+// TestListWorktrees_MissingDirectory tests that worktrees whose directory was deleted are flagged
+// and that PruneWorktrees cleans them up.
+func TestListWorktrees_MissingDirectory(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	r := git.Repository{Dir: repo}
+
+	exec.Command("git", "-C", repo, "branch", "stale-wt").Run()
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	require.NoError(t, exec.Command("git", "-C", repo, "worktree", "add", worktreePath, "stale-wt").Run())
+	require.NoError(t, os.RemoveAll(worktreePath))
+
+	wt, err := r.GetWorktreeForBranch("stale-wt")
+	require.NoError(t, err)
+	require.NotNil(t, wt, "Stale worktree should still be listed")
+	assert.True(t, wt.Missing, "Worktree with a deleted directory should be flagged as missing")
+
+	err = r.PruneWorktrees()
+	require.NoError(t, err)
+
+	wt, err = r.GetWorktreeForBranch("stale-wt")
+	require.NoError(t, err)
+	assert.Nil(t, wt, "Pruning should remove the stale worktree")
+	assert.NoError(t, r.DeleteBranch("stale-wt"), "Branch should be deletable after pruning")
+}
+
+// newWorktreeModel lists the linked worktrees of the current repository in a worktree cleanup model.
+func newWorktreeModel(t *testing.T) ui.WorktreeModel {
+	t.Helper()
+
+	worktrees, err := git.ListWorktrees()
+	require.NoError(t, err)
+
+	m := ui.WorktreeModel{Selected: make(map[string]bool), Styles: ui.NewStyles(ui.ThemeAuto, false)}
+	for _, wt := range worktrees {
+		if !wt.Main {
+			m.Worktrees = append(m.Worktrees, ui.WorktreeItem{Worktree: wt})
+		}
+	}
+	return m
+}
+
+// sendWorktreeKey feeds a key press to the worktree model and runs any resulting command to completion.
+func sendWorktreeKey(m ui.WorktreeModel, key string) ui.WorktreeModel {
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	if key == " " {
+		msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(key)}
+	}
+
+	next, cmd := m.Update(msg)
+	return runCmds(next.(ui.WorktreeModel), cmd)
+}
+
+// TestWorktreeModel_RemovesWorktreesAndFreedBranches tests removing selected worktrees, force removing
+// a locked one after confirmation, and deleting the branches they had checked out.
+func TestWorktreeModel_RemovesWorktreesAndFreedBranches(t *testing.T) {
+	repo := setupTestRepo(t)
+
 	originalDir, _ := os.Getwd()
 	defer os.Chdir(originalDir)
 	err := os.Chdir(repo)
 	require.NoError(t, err)
 
-	// Attempt to remove non-existent worktree
-	err = git.RemoveWorktree("/path/does/not/exist")
-	assert.Error(t, err, "RemoveWorktree should fail for non-existent worktree")
+	plainPath := filepath.Join(t.TempDir(), "plain")
+	lockedPath := filepath.Join(t.TempDir(), "locked")
+	require.NoError(t, exec.Command("git", "worktree", "add", "-b", "plain-wt", plainPath).Run())
+	require.NoError(t, exec.Command("git", "worktree", "add", "-b", "locked-wt", lockedPath).Run())
+	require.NoError(t, exec.Command("git", "worktree", "lock", lockedPath).Run())
+
+	m := newWorktreeModel(t)
+	require.Len(t, m.Worktrees, 2, "The main worktree should not be offered")
+
+	m = sendWorktreeKey(m, "a")
+	m = sendWorktreeKey(m, "d")
+	require.Equal(t, ui.WorktreeStateConfirmation, m.State)
+
+	m = sendWorktreeKey(m, "y")
+	require.Equal(t, ui.WorktreeStateLockedConfirmation, m.State, "Locked worktrees need confirmation")
+	require.Len(t, m.LockedWorktrees, 1)
+	assert.Len(t, m.Removed, 1)
+
+	m = sendWorktreeKey(m, "y")
+	require.Equal(t, ui.WorktreeStateBranchConfirmation, m.State)
+	assert.ElementsMatch(t, []string{"locked-wt", "plain-wt"}, m.FreedBranches)
+	assert.Contains(t, m.View(), "Also delete branch "+m.FreedBranches[0]+"?")
+
+	m = sendWorktreeKey(m, "y")
+	require.Equal(t, ui.WorktreeStateBranchConfirmation, m.State, "Each freed branch is asked about")
+	assert.Contains(t, m.View(), "Also delete branch "+m.FreedBranches[1]+"?")
+	assert.True(t, git.BranchExists(m.FreedBranches[0]), "Branches are deleted once all are answered")
+
+	m = sendWorktreeKey(m, "y")
+	require.Equal(t, ui.WorktreeStateDone, m.State)
+	assert.False(t, m.HasFailures(), "Unexpected failures: %v", m.Failures)
+	assert.ElementsMatch(t, []string{"locked-wt", "plain-wt"}, m.DeletedBranches)
+	assert.Contains(t, m.View(), "2 worktrees removed • 2 branches deleted • 0 failed")
+
+	worktrees, err := git.ListWorktrees()
+	require.NoError(t, err)
+	assert.Len(t, worktrees, 1, "Only the main worktree should remain")
+}
+
+// TestWorktreeModel_KeepBranches tests that declining the follow-up keeps the branches of removed worktrees.
+func TestWorktreeModel_KeepBranches(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	err := os.Chdir(repo)
+	require.NoError(t, err)
+
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	require.NoError(t, exec.Command("git", "worktree", "add", "-b", "keep-me", worktreePath).Run())
+
+	m := newWorktreeModel(t)
+	m = sendWorktreeKey(m, " ")
+	m = sendWorktreeKey(m, "d")
+	m = sendWorktreeKey(m, "y")
+	require.Equal(t, ui.WorktreeStateBranchConfirmation, m.State)
+
+	m = sendWorktreeKey(m, "n")
+	require.Equal(t, ui.WorktreeStateDone, m.State)
+	assert.Empty(t, m.DeletedBranches)
+
+	assert.True(t, git.BranchExists("keep-me"), "Branch should be kept")
+}
+
+// TestWorktreeModel_Japanese tests that the worktrees screens are rendered in the language of the model.
+func TestWorktreeModel_Japanese(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	require.NoError(t, exec.Command("git", "worktree", "add", "-b", "ja-wt", worktreePath).Run())
+
+	m := newWorktreeModel(t)
+	m.Lang = i18n.Japanese
+	m = sendWorktreeKey(m, " ")
+	m = sendWorktreeKey(m, "d")
+	assert.Contains(t, m.View(), "合計: 1 件のワークツリー")
+
+	m = sendWorktreeKey(m, "y")
+	m = sendWorktreeKey(m, "n")
+	require.Equal(t, ui.WorktreeStateDone, m.State)
+	assert.Contains(t, m.View(), "• ブランチ ja-wt: 残しました")
+	assert.Contains(t, m.View(), ": 削除しました")
+}
+
+// TestWorktreeModel_AnswersPerBranch tests that each freed branch is deleted or kept as answered, and that
+// a branch still checked out in another worktree isn't offered.
+func TestWorktreeModel_AnswersPerBranch(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	require.NoError(t, os.Chdir(repo))
+
+	for _, branch := range []string{"delete-me", "keep-me", "shared"} {
+		path := filepath.Join(t.TempDir(), branch)
+		require.NoError(t, exec.Command("git", "worktree", "add", "-b", branch, path).Run())
+	}
+	sharedCopy := filepath.Join(t.TempDir(), "shared-copy")
+	require.NoError(t, exec.Command("git", "worktree", "add", "--force", sharedCopy, "shared").Run())
+
+	m := newWorktreeModel(t)
+	for _, wt := range m.Worktrees {
+		m.Selected[wt.Path] = wt.Path != git.CanonicalPath(sharedCopy)
+	}
+	m = sendWorktreeKey(m, "d")
+	m = sendWorktreeKey(m, "y")
+	require.Equal(t, ui.WorktreeStateBranchConfirmation, m.State)
+	assert.Equal(t, []string{"delete-me", "keep-me"}, m.FreedBranches, "A branch still checked out elsewhere can't be deleted")
+
+	m = sendWorktreeKey(m, "y")
+	m = sendWorktreeKey(m, "n")
+	require.Equal(t, ui.WorktreeStateDone, m.State)
+	assert.Equal(t, []string{"delete-me"}, m.DeletedBranches)
+	assert.Equal(t, []string{"keep-me"}, m.KeptBranches)
+	assert.Contains(t, m.View(), "branch keep-me: kept")
+
+	assert.False(t, git.BranchExists("delete-me"))
+	assert.True(t, git.BranchExists("keep-me"))
+	assert.True(t, git.BranchExists("shared"))
+}
+
+// TestWorktreeModel_ForceDeletesUnmergedBranches tests that a freed branch git refuses as unmerged is force deleted
+// only after confirmation, and kept otherwise.
+func TestWorktreeModel_ForceDeletesUnmergedBranches(t *testing.T) {
+	for _, tt := range []struct {
+		answer  string
+		deleted bool
+	}{
+		{"y", true},
+		{"n", false},
+	} {
+		t.Run(tt.answer, func(t *testing.T) {
+			repo := setupTestRepo(t)
+
+			originalDir, _ := os.Getwd()
+			defer os.Chdir(originalDir)
+			require.NoError(t, os.Chdir(repo))
+
+			path := filepath.Join(t.TempDir(), "wt")
+			require.NoError(t, exec.Command("git", "worktree", "add", "-b", "unmerged", path).Run())
+			require.NoError(t, exec.Command("git", "-C", path, "commit", "-q", "--allow-empty", "-m", "Unmerged work").Run())
+
+			m := newWorktreeModel(t)
+			m.BaseBranch = "HEAD"
+			m.MergedBranches = map[string]bool{}
+			m = sendWorktreeKey(m, " ")
+			m = sendWorktreeKey(m, "d")
+			m = sendWorktreeKey(m, "y")
+			require.Equal(t, ui.WorktreeStateBranchConfirmation, m.State)
+			assert.Contains(t, m.View(), "Not merged into HEAD")
+
+			m = sendWorktreeKey(m, "y")
+			require.Equal(t, ui.WorktreeStateForceConfirmation, m.State, "Unmerged branches need confirmation")
+			assert.Equal(t, []string{"unmerged"}, m.UnmergedBranches)
+			assert.False(t, m.HasFailures(), "An unmerged branch isn't a failure before the user decides")
+
+			m = sendWorktreeKey(m, tt.answer)
+			require.Equal(t, ui.WorktreeStateDone, m.State)
+			assert.False(t, m.HasFailures(), "Unexpected failures: %v", m.Failures)
+			assert.Equal(t, !tt.deleted, git.BranchExists("unmerged"))
+		})
+	}
+}
+
+// TestParseWorktrees tests parsing porcelain output with every worktree attribute,
+// including lock and prune reasons and a missing blank line after the last worktree.
+func TestParseWorktrees(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []git.Worktree
+	}{
+		{
+			name:   "branch",
+			output: "worktree /repo\nHEAD 1111111\nbranch refs/heads/main\n\n",
+			want:   []git.Worktree{{Path: "/repo", HEAD: "1111111", Branch: "main"}},
+		},
+		{
+			name:   "detached HEAD",
+			output: "worktree /wt/detached\nHEAD 2222222\ndetached\n\n",
+			want:   []git.Worktree{{Path: "/wt/detached", HEAD: "2222222", Detached: true}},
+		},
+		{
+			name:   "locked without reason",
+			output: "worktree /wt/locked\nHEAD 3333333\nbranch refs/heads/feature/x\nlocked\n\n",
+			want:   []git.Worktree{{Path: "/wt/locked", HEAD: "3333333", Branch: "feature/x", Locked: true}},
+		},
+		{
+			name:   "locked with reason",
+			output: "worktree /wt/usb\nHEAD 4444444\nbranch refs/heads/usb\nlocked on a removable drive\n\n",
+			want:   []git.Worktree{{Path: "/wt/usb", HEAD: "4444444", Branch: "usb", Locked: true, LockReason: "on a removable drive"}},
+		},
+		{
+			name:   "prunable",
+			output: "worktree /wt/gone\nHEAD 5555555\nbranch refs/heads/gone\nprunable gitdir file points to non-existent location\n\n",
+			want:   []git.Worktree{{Path: "/wt/gone", HEAD: "5555555", Branch: "gone", Prunable: true}},
+		},
+		{
+			name:   "detached, locked and prunable",
+			output: "worktree /wt/all\nHEAD 6666666\ndetached\nlocked\nprunable\n\n",
+			want:   []git.Worktree{{Path: "/wt/all", HEAD: "6666666", Detached: true, Locked: true, Prunable: true}},
+		},
+		{
+			name:   "no trailing blank line",
+			output: "worktree /repo\nHEAD 1111111\nbranch refs/heads/main\n\nworktree /wt/last\nHEAD 7777777\nbranch refs/heads/last",
+			want: []git.Worktree{
+				{Path: "/repo", HEAD: "1111111", Branch: "main"},
+				{Path: "/wt/last", HEAD: "7777777", Branch: "last"},
+			},
+		},
+		{
+			name:   "CRLF line endings",
+			output: "worktree C:/wt/crlf\r\nHEAD 8888888\r\nbranch refs/heads/crlf\r\nlocked on a share\r\n\r\n",
+			want: []git.Worktree{
+				{Path: filepath.FromSlash("C:/wt/crlf"), HEAD: "8888888", Branch: "crlf", Locked: true, LockReason: "on a share"},
+			},
+		},
+		{
+			name:   "empty",
+			output: "",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, git.ParseWorktrees(tt.output))
+		})
+	}
+}
+
+// TestListWorktrees_LockReasonAndPrunable tests that listed worktrees carry their commit, lock reason
+// and prunable state, and that a missing locked worktree isn't prunable
+func TestListWorktrees_LockReasonAndPrunable(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	r := git.Repository{Dir: repo}
+
+	exec.Command("git", "-C", repo, "branch", "locked-wt").Run()
+	exec.Command("git", "-C", repo, "branch", "gone-wt").Run()
+	lockedPath := filepath.Join(t.TempDir(), "locked")
+	gonePath := filepath.Join(t.TempDir(), "gone")
+	require.NoError(t, exec.Command("git", "-C", repo, "worktree", "add", lockedPath, "locked-wt").Run())
+	require.NoError(t, exec.Command("git", "-C", repo, "worktree", "add", gonePath, "gone-wt").Run())
+	require.NoError(t, exec.Command("git", "-C", repo, "worktree", "lock", "--reason", "in use by CI", lockedPath).Run())
+	require.NoError(t, os.RemoveAll(lockedPath))
+	require.NoError(t, os.RemoveAll(gonePath))
+
+	head, err := r.GetBranchSHA("gone-wt")
+	require.NoError(t, err)
+
+	worktrees, err := r.ListWorktrees()
+	require.NoError(t, err)
+	byBranch := make(map[string]git.Worktree)
+	for _, wt := range worktrees {
+		byBranch[wt.Branch] = wt
+	}
+
+	locked := byBranch["locked-wt"]
+	assert.True(t, locked.Locked)
+	assert.Equal(t, "in use by CI", locked.LockReason)
+	assert.True(t, locked.Missing)
+	assert.False(t, locked.Prunable, "Locked worktrees are never pruned")
+
+	gone := byBranch["gone-wt"]
+	assert.True(t, strings.HasPrefix(gone.HEAD, head), "HEAD should be the full commit of the worktree")
+	assert.True(t, gone.Prunable)
 }